@@ -2,10 +2,13 @@ package api
 
 import (
 	"log"
+	"strings"
 
 	"app/api/handlers"
+	"app/api/middleware"
 	"app/config"
-	"app/infrastructure/postgres"
+	"app/infrastructure/dependencies"
+	"app/infrastructure/ratelimit"
 	custom_logger "app/pkg/logger"
 
 	"github.com/gin-contrib/cors"
@@ -19,56 +22,76 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func setupDatabase() *gorm.DB {
-	conn := postgres.Connect()
-	return conn
-}
-
-func setupRouter(conn *gorm.DB) *gin.Engine {
+func setupRouter(conn *gorm.DB, deps *dependencies.Dependencies) *gin.Engine {
 	gin.SetMode(config.EnvironmentVariables.GinMode)
 
 	r := gin.New()
 
+	// Sem proxies confiáveis o IP do cliente é o da conexão: X-Forwarded-For forjado não troca a cota por IP
+	if err := r.SetTrustedProxies(splitList(config.EnvironmentVariables.TRUSTED_PROXIES)); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+
 	corsConfig := cors.DefaultConfig()
-	corsConfig.AllowAllOrigins = true
-	corsConfig.AllowCredentials = true
+	// Credenciais só com origens explícitas; com "*" o browser as recusa de qualquer forma
+	if origins := splitList(config.EnvironmentVariables.CORS_ALLOWED_ORIGINS); len(origins) > 0 {
+		corsConfig.AllowOrigins = origins
+		corsConfig.AllowCredentials = true
+	} else {
+		corsConfig.AllowAllOrigins = true
+	}
 	corsConfig.AddAllowHeaders("authorization")
+	corsConfig.AddExposeHeaders("RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After")
 
 	r.Use(apmgin.Middleware(r))
 	r.Use(cors.New(corsConfig))
 
 	// Configurar middleware de logging baseado no nível de log
 	if config.EnvironmentVariables.GinMode == "debug" || custom_logger.ShouldLogLevel(config.EnvironmentVariables.LogLevel, "INFO") {
-		r.Use(gin.Logger())
+		r.Use(gin.LoggerWithWriter(deps.Redactor.Writer(gin.DefaultWriter)))
 	}
 
 	r.Use(gin.Recovery())
 
-	// Configurar logger
-	logger := custom_logger.NewLogrusLogger(config.EnvironmentVariables.LogLevel)
+	// Rate limiting por IP; os contadores ficam na memória ou no Postgres
+	r.Use(middleware.RateLimitByIPMiddleware(deps.Limiter, ratelimit.GroupIP))
 
 	handlers.MountSamplesHandlers(r)
-	handlers.MountUsersHandlers(r, conn)
-	handlers.MountDocumentHandlers(r, conn, logger)
-	handlers.MountEnvelopeHandlers(r, conn, logger)
-	handlers.MountEnvelopeV2Handlers(r, conn, logger)
-	handlers.MountSignatoryHandlers(r, conn, logger)
-	handlers.MountRequirementHandlers(r, conn, logger)
-	handlers.MountWebhookHandlers(r, conn, logger)
-	handlers.MountAutoSignatureTermHandlers(r, conn, logger)
+	handlers.MountUsersHandlers(r, conn, deps)
+	handlers.MountDocumentHandlers(r, conn, deps)
+	handlers.MountDocumentTemplateHandlers(r, conn, deps)
+	handlers.MountEnvelopeHandlers(r, conn, deps)
+	handlers.MountEnvelopeV2Handlers(r, conn, deps)
+	handlers.MountProviderHandlers(r, conn, deps)
+	handlers.MountSignatoryHandlers(r, conn, deps)
+	handlers.MountRequirementHandlers(r, conn, deps)
+	handlers.MountWebhookHandlers(r, conn, deps)
+	handlers.MountAutoSignatureTermHandlers(r, conn, deps)
+	handlers.MountRetentionHandlers(r, conn, deps)
+	handlers.MountAPIClientHandlers(r, conn, deps)
+	handlers.MountTenantHandlers(r, conn, deps)
+
+	// API de simulação do provider fake: apenas para desenvolvimento e testes de integração
+	if config.EnvironmentVariables.FAKE_PROVIDER_ENABLED {
+		handlers.MountFakeProviderHandlers(r, deps.Logger)
+	}
 
 	return r
 }
 
-func SetupRouters() *gin.Engine {
-	conn := setupDatabase()
-	return setupRouter(conn)
+// splitList lê listas separadas por vírgula (CORS_ALLOWED_ORIGINS, TRUSTED_PROXIES)
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
 
-func StartWebServer() {
-	config.ReadEnvironmentVars()
-
-	r := SetupRouters()
+func StartWebServer(conn *gorm.DB, deps *dependencies.Dependencies) {
+	r := setupRouter(conn, deps)
 
 	url := ginSwagger.URL("http://localhost:8080/swagger/doc.json") // The url pointing to API definition
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, url))
@@ -0,0 +1,103 @@
+package dtos
+
+import (
+	"time"
+
+	"app/entity"
+)
+
+// APIClientCreateRequestDTO representa o request de criação de cliente de API
+type APIClientCreateRequestDTO struct {
+	Name        string   `json:"name" binding:"required,min=3,max=120" example:"erp-financeiro"`
+	Description string   `json:"description,omitempty" example:"Integração do ERP para envio de contratos"`
+	Scopes      []string `json:"scopes" binding:"required,min=1" example:"envelopes:read,envelopes:write"`
+}
+
+// APIClientUpdateRequestDTO representa o request de atualização de cliente de API
+type APIClientUpdateRequestDTO struct {
+	Name        *string  `json:"name,omitempty" binding:"omitempty,min=3,max=120"`
+	Description *string  `json:"description,omitempty"`
+	Scopes      []string `json:"scopes,omitempty" binding:"omitempty,min=1"`
+	Active      *bool    `json:"active,omitempty"`
+}
+
+// APIKeyRotateRequestDTO representa o request de rotação de chave
+type APIKeyRotateRequestDTO struct {
+	OverlapHours *int `json:"overlap_hours,omitempty" binding:"omitempty,min=0,max=720" example:"24" doc:"Horas em que as chaves atuais continuam válidas; padrão API_KEY_ROTATION_OVERLAP_HOURS"`
+}
+
+// APIKeyResponseDTO representa uma chave sem o segredo
+type APIKeyResponseDTO struct {
+	ID         int        `json:"id"`
+	Prefix     string     `json:"prefix" example:"3f9a0c1b2d4e"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	LastUsedIP string     `json:"last_used_ip,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// APIKeyIssuedResponseDTO representa uma chave recém-emitida; Key é exibida apenas nesta resposta
+type APIKeyIssuedResponseDTO struct {
+	APIKeyResponseDTO
+	Key string `json:"key" example:"dsk_3f9a0c1b2d4e_9c1e..."`
+}
+
+// APIClientResponseDTO representa o cliente de API na resposta
+type APIClientResponseDTO struct {
+	ID          int                 `json:"id"`
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	Scopes      []string            `json:"scopes"`
+	Active      bool                `json:"active"`
+	TenantID    string              `json:"tenant_id,omitempty"`
+	Keys        []APIKeyResponseDTO `json:"keys"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+}
+
+// APIClientCreateResponseDTO representa o cliente criado junto com a primeira chave
+type APIClientCreateResponseDTO struct {
+	Client APIClientResponseDTO    `json:"client"`
+	APIKey APIKeyIssuedResponseDTO `json:"api_key"`
+}
+
+// APIClientListResponseDTO representa a estrutura de response para lista de clientes
+type APIClientListResponseDTO struct {
+	Clients []APIClientResponseDTO `json:"clients"`
+	Total   int                    `json:"total"`
+	Scopes  []string               `json:"available_scopes"`
+}
+
+// NewAPIKeyResponseDTO converte a entidade para o DTO de resposta
+func NewAPIKeyResponseDTO(key *entity.EntityAPIKey) APIKeyResponseDTO {
+	return APIKeyResponseDTO{
+		ID:         key.ID,
+		Prefix:     key.Prefix,
+		ExpiresAt:  key.ExpiresAt,
+		RevokedAt:  key.RevokedAt,
+		LastUsedAt: key.LastUsedAt,
+		LastUsedIP: key.LastUsedIP,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+// NewAPIClientResponseDTO converte a entidade para o DTO de resposta
+func NewAPIClientResponseDTO(client *entity.EntityAPIClient) APIClientResponseDTO {
+	keys := make([]APIKeyResponseDTO, 0, len(client.Keys))
+	for i := range client.Keys {
+		keys = append(keys, NewAPIKeyResponseDTO(&client.Keys[i]))
+	}
+
+	return APIClientResponseDTO{
+		ID:          client.ID,
+		Name:        client.Name,
+		Description: client.Description,
+		Scopes:      client.Scopes,
+		Active:      client.Active,
+		TenantID:    client.TenantID,
+		Keys:        keys,
+		CreatedAt:   client.CreatedAt,
+		UpdatedAt:   client.UpdatedAt,
+	}
+}
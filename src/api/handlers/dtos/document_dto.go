@@ -64,9 +64,85 @@ type DocumentResponseDTO struct {
 	MimeType     string    `json:"mime_type"`
 	Status       string    `json:"status"`
 	ClicksignKey string    `json:"clicksign_key"`
+	StorageKey   string    `json:"storage_key,omitempty"`
+	SHA256       string    `json:"sha256,omitempty"`
+	SignedSHA256 string    `json:"signed_sha256,omitempty"`
+	Integrity    string    `json:"integrity_status,omitempty"`
 	Description  string    `json:"description"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
+
+	// Versão atual do conteúdo e, após a assinatura, a versão que foi assinada
+	Version       int `json:"version"`
+	SignedVersion int `json:"signed_version,omitempty"`
+
+	// Metadata do documento, incluindo a conversão para PDF em "conversion" e a verificação de malware em "scan" quando houver
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// DocumentVerifyMatchDTO identifica um documento cujo original ou cópia assinada tem o mesmo conteúdo
+type DocumentVerifyMatchDTO struct {
+	DocumentID      int    `json:"document_id"`
+	Name            string `json:"name"`
+	Status          string `json:"status"`
+	MatchedCopy     string `json:"matched_copy" example:"original" enums:"original,signed"`
+	IntegrityStatus string `json:"integrity_status,omitempty"`
+}
+
+// DocumentVerifyResponseDTO é o resultado de POST /api/v2/documents/verify
+type DocumentVerifyResponseDTO struct {
+	SHA256  string                   `json:"sha256"`
+	Size    int64                    `json:"size"`
+	Matched bool                     `json:"matched"`
+	Matches []DocumentVerifyMatchDTO `json:"matches"`
+}
+
+// DocumentReplaceRequestDTO representa o novo conteúdo de um documento de envelope em rascunho
+// Deve ser informado exatamente um entre file_content_base64 e file_url
+type DocumentReplaceRequestDTO struct {
+	FileContentBase64 string                 `json:"file_content_base64,omitempty"`
+	FileURL           string                 `json:"file_url,omitempty" binding:"omitempty,url"`
+	Metadata          map[string]interface{} `json:"metadata,omitempty"` // Substitui o metadata informado pelo cliente quando presente
+}
+
+// Validate garante que apenas uma origem de conteúdo foi informada
+func (dto *DocumentReplaceRequestDTO) Validate() error {
+	dto.FileContentBase64 = strings.TrimSpace(dto.FileContentBase64)
+	dto.FileURL = strings.TrimSpace(dto.FileURL)
+
+	hasBase64 := dto.FileContentBase64 != ""
+	hasURL := dto.FileURL != ""
+
+	if !hasBase64 && !hasURL {
+		return errors.New("é necessário fornecer file_content_base64 ou file_url")
+	}
+
+	if hasBase64 && hasURL {
+		return errors.New("forneça apenas file_content_base64 OU file_url, não ambos")
+	}
+
+	return nil
+}
+
+// DocumentVersionResponseDTO representa uma versão substituída do documento
+type DocumentVersionResponseDTO struct {
+	Version    int        `json:"version"`
+	FileSize   int64      `json:"file_size"`
+	MimeType   string     `json:"mime_type"`
+	StorageKey string     `json:"storage_key,omitempty"`
+	SHA256     string     `json:"sha256,omitempty"`
+	Signed     bool       `json:"signed"`
+	ReplacedAt *time.Time `json:"replaced_at,omitempty"` // Ausente na versão atual
+}
+
+// DocumentVersionsResponseDTO é o histórico de versões de GET /api/v1/documents/{id}/versions
+// A versão atual é a do próprio documento; Versions traz apenas as substituídas
+type DocumentVersionsResponseDTO struct {
+	DocumentID     int                          `json:"document_id"`
+	CurrentVersion int                          `json:"current_version"`
+	SignedVersion  int                          `json:"signed_version,omitempty"`
+	Current        DocumentVersionResponseDTO   `json:"current"`
+	Versions       []DocumentVersionResponseDTO `json:"versions"`
 }
 
 // DocumentListResponseDTO representa a estrutura de response para lista de documentos
@@ -0,0 +1,42 @@
+package dtos
+
+import "time"
+
+// DocumentTemplateCreateRequestDTO representa a estrutura de request para criação de template
+type DocumentTemplateCreateRequestDTO struct {
+	Name        string `json:"name" binding:"required,min=3,max=255" example:"Contrato de Prestação de Serviços"`
+	Description string `json:"description,omitempty" binding:"max=1000" example:"Modelo padrão de prestação de serviços"`
+	Format      string `json:"format" binding:"required,oneof=html markdown" example:"markdown" doc:"Formato do conteúdo: html ou markdown"`
+	Content     string `json:"content" binding:"required" example:"# Contrato\n\nContratante: {{ contratante_nome }}" doc:"Conteúdo com placeholders {{ variavel }}"`
+}
+
+// DocumentTemplateUpdateRequestDTO representa a estrutura de request para atualização de template
+type DocumentTemplateUpdateRequestDTO struct {
+	Name        *string `json:"name,omitempty" binding:"omitempty,min=3,max=255"`
+	Description *string `json:"description,omitempty" binding:"omitempty,max=1000"`
+	Format      *string `json:"format,omitempty" binding:"omitempty,oneof=html markdown"`
+	Content     *string `json:"content,omitempty" binding:"omitempty,min=1"`
+}
+
+// DocumentTemplateRenderRequestDTO representa as variáveis usadas para gerar o PDF de um template
+type DocumentTemplateRenderRequestDTO struct {
+	Variables map[string]string `json:"variables" example:"contratante_nome:Ana Souza"`
+}
+
+// DocumentTemplateResponseDTO representa a estrutura de response para template
+type DocumentTemplateResponseDTO struct {
+	ID           int       `json:"id"`
+	Name         string    `json:"name"`
+	Description  string    `json:"description,omitempty"`
+	Format       string    `json:"format"`
+	Content      string    `json:"content"`
+	Placeholders []string  `json:"placeholders"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// DocumentTemplateListResponseDTO representa a estrutura de response para lista de templates
+type DocumentTemplateListResponseDTO struct {
+	Templates []DocumentTemplateResponseDTO `json:"templates"`
+	Total     int                           `json:"total"`
+}
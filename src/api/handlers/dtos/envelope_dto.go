@@ -28,10 +28,19 @@ type EnvelopeDocumentRequest struct {
 	Name              string                 `json:"name" binding:"required,min=3,max=255"`
 	FileContentBase64 string                 `json:"file_content_base64,omitempty"` // Opcional: usar OU file_url OU file_content_base64
 	FileURL           string                 `json:"file_url,omitempty"`            // Opcional: URL pública do documento
+	TemplateID        int                    `json:"template_id,omitempty"`         // Opcional (v2): template renderizado em PDF com variables
+	Variables         map[string]string      `json:"variables,omitempty"`           // Variáveis do template_id
+	Parts             []EnvelopeDocumentPart `json:"parts,omitempty"`               // Opcional (v2): imagens e PDFs juntados, na ordem, em um único PDF
 	Description       string                 `json:"description,omitempty"`
 	Metadata          map[string]interface{} `json:"metadata,omitempty"` // Metadata customizado do backend
 }
 
+// EnvelopeDocumentPart é uma das partes de um documento montado a partir de vários arquivos
+type EnvelopeDocumentPart struct {
+	FileContentBase64 string `json:"file_content_base64,omitempty"`
+	FileURL           string `json:"file_url,omitempty"`
+}
+
 // Validate valida o documento
 func (edr *EnvelopeDocumentRequest) Validate() error {
 	// Limpar whitespace dos campos
@@ -229,6 +238,7 @@ type EnvelopeResponseDTO struct {
 	Name             string                 `json:"name"`
 	Description      string                 `json:"description"`
 	Status           string                 `json:"status"`
+	Provider         string                 `json:"provider,omitempty"`
 	ClicksignKey     string                 `json:"clicksign_key"`
 	ClicksignRawData *string                `json:"clicksign_raw_data,omitempty"`
 	DocumentsIDs     []int                  `json:"documents_ids"`
@@ -238,10 +248,26 @@ type EnvelopeResponseDTO struct {
 	DeadlineAt       *time.Time             `json:"deadline_at"`
 	RemindInterval   int                    `json:"remind_interval"`
 	AutoClose        bool                   `json:"auto_close"`
+	CreatedByID      int                    `json:"created_by_id,omitempty"`
+	ApprovalTrail    []EnvelopeApprovalDTO  `json:"approval_trail,omitempty"` // Pedido de aprovação e decisões, em ordem
 	CreatedAt        time.Time              `json:"created_at"`
 	UpdatedAt        time.Time              `json:"updated_at"`
 }
 
+// EnvelopeApprovalDTO é um registro da trilha de aprovação do envelope
+type EnvelopeApprovalDTO struct {
+	Action    string    `json:"action" example:"approved"` // requested, approved ou rejected
+	UserID    int       `json:"user_id" example:"12"`
+	UserName  string    `json:"user_name,omitempty" example:"Maria Souza"`
+	Comment   string    `json:"comment,omitempty" example:"Valores conferidos com o financeiro"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// EnvelopeApprovalRequestDTO representa a decisão sobre um envelope pendente de aprovação
+type EnvelopeApprovalRequestDTO struct {
+	Comment string `json:"comment" binding:"max=1000" example:"Valores conferidos com o financeiro"` // Obrigatório na rejeição
+}
+
 // EnvelopeListResponseDTO representa a estrutura de response para lista de envelopes
 type EnvelopeListResponseDTO struct {
 	Envelopes []EnvelopeResponseDTO `json:"envelopes"`
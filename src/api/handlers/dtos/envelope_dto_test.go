@@ -1,8 +1,12 @@
 package dtos
 
 import (
+	"encoding/base64"
 	"testing"
 
+	"app/config"
+	"app/pkg/utils"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -88,3 +92,210 @@ func TestEnvelopeV2CreateRequestDTO_Validate_VertSignAuthRules(t *testing.T) {
 		assert.Contains(t, err.Error(), "não suporta")
 	})
 }
+
+func TestEnvelopeV2CreateRequestDTO_Validate_ProviderCapabilities(t *testing.T) {
+	newRequest := func(provider string) EnvelopeV2CreateRequestDTO {
+		return EnvelopeV2CreateRequestDTO{
+			Provider: provider,
+			Name:     "Envelope Capabilities",
+			Documents: []EnvelopeDocumentRequest{
+				{
+					Name:    "Contrato.pdf",
+					FileURL: "https://example.com/contrato.pdf",
+				},
+			},
+			Signatories: []EnvelopeSignatoryRequest{
+				{
+					Name:  "Assinante",
+					Email: "assinante@empresa.com",
+				},
+			},
+		}
+	}
+
+	t.Run("should reject unknown provider", func(t *testing.T) {
+		request := newRequest("docusign")
+
+		err := request.Validate()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "provider inválido")
+	})
+
+	t.Run("should reject auto_signature for clicksign signatory", func(t *testing.T) {
+		authMethod := "auto_signature"
+		request := newRequest("clicksign")
+		request.Signatories[0].AuthMethod = &authMethod
+
+		err := request.Validate()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "provider clicksign não suporta auth_method='auto_signature'")
+	})
+
+	t.Run("should reject sequential groups for vert-sign", func(t *testing.T) {
+		firstGroup, secondGroup := 1, 2
+		request := newRequest("vert-sign")
+		request.Signatories[0].Group = &firstGroup
+		request.Signatories = append(request.Signatories, EnvelopeSignatoryRequest{
+			Name:  "Segundo Assinante",
+			Email: "segundo@empresa.com",
+			Group: &secondGroup,
+		})
+
+		err := request.Validate()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "não suporta assinatura sequencial")
+	})
+
+	t.Run("should accept sequential groups for clicksign", func(t *testing.T) {
+		firstGroup, secondGroup := 1, 2
+		request := newRequest("clicksign")
+		request.Signatories[0].Group = &firstGroup
+		request.Signatories = append(request.Signatories, EnvelopeSignatoryRequest{
+			Name:  "Segundo Assinante",
+			Email: "segundo@empresa.com",
+			Group: &secondGroup,
+		})
+
+		err := request.Validate()
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should reject unsupported requirement action for vert-sign", func(t *testing.T) {
+		request := newRequest("vert-sign")
+		request.Requirements = []EnvelopeRequirementRequest{
+			{Action: "agree", Role: "sign"},
+		}
+
+		err := request.Validate()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "não suporta action='agree'")
+	})
+
+	t.Run("should reject unsupported requirement auth for clicksign", func(t *testing.T) {
+		auth := "auto_signature"
+		request := newRequest("clicksign")
+		request.Requirements = []EnvelopeRequirementRequest{
+			{Action: "provide_evidence", Auth: &auth},
+		}
+
+		err := request.Validate()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "não suporta auth='auto_signature'")
+	})
+
+	t.Run("should reject unsupported mime type in base64 document", func(t *testing.T) {
+		request := newRequest("clicksign")
+		request.Documents[0] = EnvelopeDocumentRequest{
+			Name:              "Contrato.txt",
+			FileContentBase64: base64.StdEncoding.EncodeToString([]byte("conteúdo em texto puro")),
+		}
+
+		err := request.Validate()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "tipo de arquivo text/plain")
+	})
+
+	t.Run("should reject base64 document larger than provider limit", func(t *testing.T) {
+		content := make([]byte, utils.MaxFileSize+1)
+		copy(content, []byte("%PDF-1.4"))
+		request := newRequest("clicksign")
+		request.Documents[0] = EnvelopeDocumentRequest{
+			Name:              "Contrato.pdf",
+			FileContentBase64: base64.StdEncoding.EncodeToString(content),
+		}
+
+		err := request.Validate()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "excede o máximo")
+	})
+
+	t.Run("should accept pdf base64 document", func(t *testing.T) {
+		request := newRequest("clicksign")
+		request.Documents[0] = EnvelopeDocumentRequest{
+			Name:              "Contrato.pdf",
+			FileContentBase64: base64.StdEncoding.EncodeToString([]byte("%PDF-1.4\n%conteudo")),
+		}
+
+		err := request.Validate()
+
+		require.NoError(t, err)
+	})
+}
+
+func TestEnvelopeV2CreateRequestDTO_ProviderCandidates(t *testing.T) {
+	newRequest := func(provider string, fallbacks ...string) EnvelopeV2CreateRequestDTO {
+		return EnvelopeV2CreateRequestDTO{
+			Provider:          provider,
+			FallbackProviders: fallbacks,
+			Name:              "Envelope Failover",
+			DocumentsIDs:      []int{1},
+			Signatories: []EnvelopeSignatoryRequest{
+				{
+					Name:  "Assinante",
+					Email: "assinante@empresa.com",
+				},
+			},
+		}
+	}
+
+	t.Run("should return provider followed by fallbacks", func(t *testing.T) {
+		request := newRequest("clicksign", "vert-sign")
+
+		candidates, err := request.ProviderCandidates()
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"clicksign", "vert-sign"}, candidates)
+	})
+
+	t.Run("should reject fallback that does not support the request", func(t *testing.T) {
+		authMethod := "icp_brasil"
+		request := newRequest("clicksign", "vert-sign")
+		request.Signatories[0].AuthMethod = &authMethod
+
+		_, err := request.ProviderCandidates()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "provider vert-sign não suporta")
+	})
+
+	t.Run("should reject duplicated providers", func(t *testing.T) {
+		request := newRequest("clicksign", "clicksign")
+
+		_, err := request.ProviderCandidates()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "mais de uma vez")
+	})
+
+	t.Run("should use configured order for auto and skip incompatible providers", func(t *testing.T) {
+		previousOrder := config.EnvironmentVariables.PROVIDER_FAILOVER_ORDER
+		config.EnvironmentVariables.PROVIDER_FAILOVER_ORDER = "clicksign,vert-sign"
+		defer func() { config.EnvironmentVariables.PROVIDER_FAILOVER_ORDER = previousOrder }()
+
+		authMethod := "icp_brasil"
+		request := newRequest(ProviderAuto)
+		request.Signatories[0].AuthMethod = &authMethod
+
+		candidates, err := request.ProviderCandidates()
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"clicksign"}, candidates)
+	})
+
+	t.Run("should reject auto with explicit fallbacks", func(t *testing.T) {
+		request := newRequest(ProviderAuto, "vert-sign")
+
+		err := request.Validate()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fallback_providers")
+	})
+}
@@ -1,40 +1,55 @@
 package dtos
 
 import (
+	"encoding/base64"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
+
+	"app/config"
+	"app/infrastructure/provider"
+	"app/infrastructure/provider_factory"
+	"app/pkg/utils"
 )
 
+// ProviderAuto seleciona o provider automaticamente pela ordem configurada em PROVIDER_FAILOVER_ORDER
+const ProviderAuto = "auto"
+
+// MaxDocumentParts limita as partes juntadas em um único documento
+const MaxDocumentParts = 20
+
 // EnvelopeV2CreateRequestDTO representa a estrutura de request para criação de envelope na v2
 // Esta versão inclui o campo Provider obrigatório para seleção do provider
 type EnvelopeV2CreateRequestDTO struct {
-	Provider        string                       `json:"provider" binding:"required,oneof=clicksign vert-sign"`
-	Name            string                       `json:"name" binding:"required,min=3,max=255"`
-	Description     string                       `json:"description,omitempty" binding:"max=1000"`
-	DocumentsIDs    []int                        `json:"documents_ids,omitempty"`
-	Documents       []EnvelopeDocumentRequest    `json:"documents,omitempty"`
-	SignatoryEmails []string                     `json:"signatory_emails,omitempty"`
-	Signatories     []EnvelopeSignatoryRequest   `json:"signatories,omitempty"`
-	Requirements    []EnvelopeRequirementRequest `json:"requirements,omitempty"`
-	Qualifiers      []EnvelopeRequirementRequest `json:"qualifiers,omitempty"` // Qualificadores para o envelope, como "sign", "agree", etc.
-	Message         string                       `json:"message,omitempty" binding:"max=500"`
-	DeadlineAt      *time.Time                   `json:"deadline_at,omitempty"`
-	RemindInterval  int                          `json:"remind_interval,omitempty" binding:"omitempty,min=1,max=30"`
-	AutoClose       bool                         `json:"auto_close,omitempty"`
-	Approved        bool                         `json:"approved,omitempty"` // Indica se o envelope foi aprovado
+	Provider          string                       `json:"provider" binding:"required"`  // Nome do provider ou "auto"; validado contra o registro de providers em Validate()
+	FallbackProviders []string                     `json:"fallback_providers,omitempty"` // Providers tentados, em ordem, se o principal estiver indisponível
+	Name              string                       `json:"name" binding:"required,min=3,max=255"`
+	Description       string                       `json:"description,omitempty" binding:"max=1000"`
+	DocumentsIDs      []int                        `json:"documents_ids,omitempty"` // Documentos já persistidos (ex.: upload multipart em POST /api/v1/documents) ainda não enviados a um provider
+	Documents         []EnvelopeDocumentRequest    `json:"documents,omitempty"`
+	SignatoryEmails   []string                     `json:"signatory_emails,omitempty"`
+	Signatories       []EnvelopeSignatoryRequest   `json:"signatories,omitempty"`
+	Requirements      []EnvelopeRequirementRequest `json:"requirements,omitempty"`
+	Qualifiers        []EnvelopeRequirementRequest `json:"qualifiers,omitempty"` // Qualificadores para o envelope, como "sign", "agree", etc.
+	Message           string                       `json:"message,omitempty" binding:"max=500"`
+	DeadlineAt        *time.Time                   `json:"deadline_at,omitempty"`
+	RemindInterval    int                          `json:"remind_interval,omitempty" binding:"omitempty,min=1,max=30"`
+	AutoClose         bool                         `json:"auto_close,omitempty"`
+	Approved          bool                         `json:"approved,omitempty"` // Ativa o envelope após a criação; ignorado quando o envelope precisa de aprovação (ENVELOPE_APPROVAL_REQUIRED)
 }
 
 // Validate valida o DTO de criação de envelope v2
 // Reutiliza a mesma lógica de validação do DTO v1, mas com provider obrigatório
+// Combinações não suportadas pelo provider (auth, ações, grupos, arquivos) são rejeitadas com base nas suas capacidades
 func (dto *EnvelopeV2CreateRequestDTO) Validate() error {
 	// Validar provider
 	if dto.Provider == "" {
 		return fmt.Errorf("provider é obrigatório")
 	}
 
-	if dto.Provider != "clicksign" && dto.Provider != "vert-sign" {
-		return fmt.Errorf("provider inválido: %s. Providers suportados: clicksign, vert-sign", dto.Provider)
+	if _, err := dto.ProviderCandidates(); err != nil {
+		return err
 	}
 
 	// Deve ter pelo menos um tipo de documento (IDs ou base64)
@@ -68,15 +83,10 @@ func (dto *EnvelopeV2CreateRequestDTO) Validate() error {
 			}
 			emailsMap[signatory.Email] = i
 
-			authMethod, err := signatory.ResolveAuthMethod()
-			if err != nil {
+			if _, err := signatory.ResolveAuthMethod(); err != nil {
 				return fmt.Errorf("erro na validação do signatário %d (%s): %v", i+1, signatory.Email, err)
 			}
 
-			if dto.Provider == "vert-sign" && authMethod == "icp_brasil" {
-				return fmt.Errorf("provider vert-sign não suporta auth_method='icp_brasil' para o signatário %s", signatory.Email)
-			}
-
 			// Reutilizar validação da estrutura SignatoryCreateRequestDTO
 			tempSignatory := &SignatoryCreateRequestDTO{
 				Name:              signatory.Name,
@@ -109,17 +119,199 @@ func (dto *EnvelopeV2CreateRequestDTO) Validate() error {
 	// Validar documentos se fornecidos
 	if len(dto.Documents) > 0 {
 		for i, doc := range dto.Documents {
-			hasBase64 := strings.TrimSpace(doc.FileContentBase64) != ""
-			hasURL := strings.TrimSpace(doc.FileURL) != ""
+			sources := 0
+			for _, provided := range []bool{
+				strings.TrimSpace(doc.FileContentBase64) != "",
+				strings.TrimSpace(doc.FileURL) != "",
+				doc.TemplateID != 0,
+				len(doc.Parts) > 0,
+			} {
+				if provided {
+					sources++
+				}
+			}
+
+			if sources == 0 {
+				return fmt.Errorf("documento %d ('%s') deve fornecer file_url, file_content_base64, template_id ou parts", i+1, doc.Name)
+			}
+
+			if sources > 1 {
+				return fmt.Errorf("documento %d ('%s') deve fornecer apenas um entre file_url, file_content_base64, template_id e parts", i+1, doc.Name)
+			}
+
+			if doc.TemplateID < 0 {
+				return fmt.Errorf("documento %d ('%s'): template_id inválido", i+1, doc.Name)
+			}
+
+			if len(doc.Parts) > MaxDocumentParts {
+				return fmt.Errorf("documento %d ('%s'): máximo de %d partes", i+1, doc.Name, MaxDocumentParts)
+			}
+
+			for j, part := range doc.Parts {
+				hasBase64 := strings.TrimSpace(part.FileContentBase64) != ""
+				hasURL := strings.TrimSpace(part.FileURL) != ""
+				if hasBase64 == hasURL {
+					return fmt.Errorf("documento %d ('%s'), parte %d: deve fornecer apenas um entre file_url e file_content_base64", i+1, doc.Name, j+1)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// IsAutoProvider indica se o provider deve ser escolhido automaticamente
+func (dto *EnvelopeV2CreateRequestDTO) IsAutoProvider() bool {
+	return dto.Provider == ProviderAuto
+}
+
+// ProviderCandidates retorna, em ordem, os providers a tentar na criação do envelope
+// Com provider "auto", usa PROVIDER_FAILOVER_ORDER e descarta providers incompatíveis com o request;
+// com fallback_providers explícito, todos os providers informados precisam suportar o request
+func (dto *EnvelopeV2CreateRequestDTO) ProviderCandidates() ([]string, error) {
+	if dto.IsAutoProvider() {
+		if len(dto.FallbackProviders) > 0 {
+			return nil, fmt.Errorf("fallback_providers não pode ser usado com provider '%s'", ProviderAuto)
+		}
 
-			if !hasBase64 && !hasURL {
-				return fmt.Errorf("documento %d ('%s') deve fornecer file_url ou file_content_base64", i+1, doc.Name)
+		var candidates []string
+		var firstErr error
+		for _, name := range provider_factory.DefaultProviderOrder(config.EnvironmentVariables) {
+			capabilities, _ := provider_factory.LookupCapabilities(name)
+			if err := dto.validateCapabilities(capabilities); err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
 			}
+			candidates = append(candidates, name)
+		}
 
-			if hasBase64 && hasURL {
-				return fmt.Errorf("documento %d ('%s') não pode fornecer file_url e file_content_base64 ao mesmo tempo", i+1, doc.Name)
+		if len(candidates) == 0 {
+			if firstErr != nil {
+				return nil, fmt.Errorf("nenhum provider configurado suporta o envelope solicitado: %v", firstErr)
 			}
+			return nil, fmt.Errorf("nenhum provider configurado para o roteamento '%s'", ProviderAuto)
 		}
+
+		return candidates, nil
+	}
+
+	names := append([]string{dto.Provider}, dto.FallbackProviders...)
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		capabilities, ok := provider_factory.LookupCapabilities(name)
+		if !ok {
+			return nil, fmt.Errorf("provider inválido: %s. Providers suportados: %s", name, strings.Join(provider_factory.SupportedProviderNames(), ", "))
+		}
+
+		if seen[name] {
+			return nil, fmt.Errorf("provider %s informado mais de uma vez em provider/fallback_providers", name)
+		}
+		seen[name] = true
+
+		if err := dto.validateCapabilities(capabilities); err != nil {
+			return nil, err
+		}
+	}
+
+	return names, nil
+}
+
+// validateCapabilities rejeita combinações do request não suportadas pelo provider
+func (dto *EnvelopeV2CreateRequestDTO) validateCapabilities(capabilities provider.Capabilities) error {
+	groups := make(map[int]bool)
+	for _, signatory := range dto.Signatories {
+		authMethod, err := signatory.ResolveAuthMethod()
+		if err != nil {
+			return err
+		}
+
+		if !capabilities.SupportsAuthMethod(authMethod) {
+			return fmt.Errorf("provider %s não suporta auth_method='%s' para o signatário %s. Métodos suportados: %s",
+				capabilities.Name, authMethod, signatory.Email, strings.Join(capabilities.AuthMethods, ", "))
+		}
+
+		if signatory.Group != nil {
+			groups[*signatory.Group] = true
+		}
+	}
+
+	if len(groups) > 1 && !capabilities.SupportsSequentialSigning {
+		return fmt.Errorf("provider %s não suporta assinatura sequencial: todos os signatários devem pertencer ao mesmo group", capabilities.Name)
+	}
+
+	for i, requirement := range dto.Requirements {
+		if err := validateRequirementCapabilities(capabilities, requirement); err != nil {
+			return fmt.Errorf("erro na validação do requirement %d: %v", i+1, err)
+		}
+	}
+
+	for i, qualifier := range dto.Qualifiers {
+		if !capabilities.SupportsAction(qualifier.Action) {
+			return fmt.Errorf("erro na validação do qualifier %d: provider %s não suporta action='%s'. Ações suportadas: %s",
+				i+1, capabilities.Name, qualifier.Action, strings.Join(capabilities.Actions, ", "))
+		}
+	}
+
+	for i, doc := range dto.Documents {
+		content := strings.TrimSpace(doc.FileContentBase64)
+		if content == "" {
+			continue
+		}
+
+		if err := validateBase64DocumentCapabilities(capabilities, content); err != nil {
+			return fmt.Errorf("documento %d ('%s'): %v", i+1, doc.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// validateRequirementCapabilities verifica se a ação e a autenticação do requirement são suportadas pelo provider
+func validateRequirementCapabilities(capabilities provider.Capabilities, requirement EnvelopeRequirementRequest) error {
+	if !capabilities.SupportsAction(requirement.Action) {
+		return fmt.Errorf("provider %s não suporta action='%s'. Ações suportadas: %s",
+			capabilities.Name, requirement.Action, strings.Join(capabilities.Actions, ", "))
+	}
+
+	if requirement.Auth != nil && *requirement.Auth != "" && !capabilities.SupportsAuthMethod(*requirement.Auth) {
+		return fmt.Errorf("provider %s não suporta auth='%s'. Métodos suportados: %s",
+			capabilities.Name, *requirement.Auth, strings.Join(capabilities.AuthMethods, ", "))
+	}
+
+	return nil
+}
+
+// validateBase64DocumentCapabilities verifica tamanho estimado e tipo MIME de um documento base64
+// sem decodificar o conteúdo completo
+func validateBase64DocumentCapabilities(capabilities provider.Capabilities, content string) error {
+	estimatedSize := int64(base64.StdEncoding.DecodedLen(len(content))) - int64(strings.Count(content[max(0, len(content)-2):], "="))
+	if capabilities.MaxFileSize > 0 && estimatedSize > capabilities.MaxFileSize {
+		return fmt.Errorf("tamanho do arquivo (%d bytes) excede o máximo de %d bytes suportado pelo provider %s",
+			estimatedSize, capabilities.MaxFileSize, capabilities.Name)
+	}
+
+	// 684 caracteres base64 correspondem a 513 bytes, suficiente para a detecção de MIME type
+	prefix := content
+	if len(prefix) > 684 {
+		prefix = prefix[:684]
+	}
+
+	sample, err := base64.StdEncoding.DecodeString(prefix)
+	if err != nil {
+		// Conteúdo inválido é tratado na decodificação completa do documento
+		return nil
+	}
+
+	mimeType := http.DetectContentType(sample)
+	// DOCX, ODT e HTML são convertidos para PDF antes do envio; o tipo final é confirmado na decodificação completa
+	if utils.MayBeConvertible(mimeType) {
+		mimeType = "application/pdf"
+	}
+	if !capabilities.SupportsMimeType(mimeType) {
+		return fmt.Errorf("tipo de arquivo %s não suportado pelo provider %s. Tipos suportados: %s",
+			mimeType, capabilities.Name, strings.Join(capabilities.MimeTypes, ", "))
 	}
 
 	return nil
@@ -0,0 +1,14 @@
+package dtos
+
+// FakeProviderEventRequestDTO representa o request para emitir um webhook arbitrário do provider fake
+type FakeProviderEventRequestDTO struct {
+	Name string                 `json:"name" binding:"required"` // Nome do evento no formato do Clicksign (ex.: "upload", "add_signer")
+	Data map[string]interface{} `json:"data,omitempty"`
+}
+
+// FakeProviderWebhooksResponseDTO lista os webhooks emitidos por uma simulação do provider fake
+type FakeProviderWebhooksResponseDTO struct {
+	EnvelopeKey string   `json:"envelope_key"`
+	Status      string   `json:"status"`
+	Events      []string `json:"events"`
+}
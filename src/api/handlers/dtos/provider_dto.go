@@ -0,0 +1,167 @@
+package dtos
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"app/entity"
+	"app/infrastructure/provider"
+)
+
+// ProviderCapabilitiesResponseDTO representa as funcionalidades suportadas por um provider
+type ProviderCapabilitiesResponseDTO struct {
+	Name                      string   `json:"name"`
+	AuthMethods               []string `json:"auth_methods"`
+	Actions                   []string `json:"actions"`
+	SupportsNotify            bool     `json:"supports_notify"`
+	SupportsCancel            bool     `json:"supports_cancel"`
+	SupportsSequentialSigning bool     `json:"supports_sequential_signing"`
+	NotifiesSignersOnCreate   bool     `json:"notifies_signers_on_create"`
+	SupportsDocumentReplace   bool     `json:"supports_document_replace"`
+	MaxFileSize               int64    `json:"max_file_size"`
+	MimeTypes                 []string `json:"mime_types"`
+}
+
+// ProviderListResponseDTO representa a resposta da listagem de providers
+type ProviderListResponseDTO struct {
+	Providers []ProviderCapabilitiesResponseDTO `json:"providers"`
+	Total     int                               `json:"total"`
+}
+
+// NewProviderCapabilitiesResponseDTO converte as capacidades do provider para o DTO de resposta
+func NewProviderCapabilitiesResponseDTO(capabilities provider.Capabilities) ProviderCapabilitiesResponseDTO {
+	return ProviderCapabilitiesResponseDTO{
+		Name:                      capabilities.Name,
+		AuthMethods:               capabilities.AuthMethods,
+		Actions:                   capabilities.Actions,
+		SupportsNotify:            capabilities.SupportsNotify,
+		SupportsCancel:            capabilities.SupportsCancel,
+		SupportsSequentialSigning: capabilities.SupportsSequentialSigning,
+		NotifiesSignersOnCreate:   capabilities.NotifiesSignersOnCreate,
+		SupportsDocumentReplace:   capabilities.SupportsDocumentReplace,
+		MaxFileSize:               capabilities.MaxFileSize,
+		MimeTypes:                 capabilities.MimeTypes,
+	}
+}
+
+// ProviderCredentialCreateRequestDTO representa o request de cadastro de credenciais de provider por tenant
+type ProviderCredentialCreateRequestDTO struct {
+	TenantID      string `json:"tenant_id" binding:"required,max=100"`
+	Provider      string `json:"provider" binding:"required,max=50"`
+	APIKey        string `json:"api_key,omitempty"`
+	BaseURL       string `json:"base_url,omitempty" binding:"omitempty,url"`
+	Login         string `json:"login,omitempty"`
+	Password      string `json:"password,omitempty"`
+	Active        *bool  `json:"active,omitempty"`
+	AccountKey    string `json:"account_key,omitempty" example:"a1b2c3d4" doc:"account_key enviado pelo provider nos webhooks; roteia os webhooks ao tenant"`
+	WebhookSecret string `json:"webhook_secret,omitempty" doc:"segredo HMAC dos webhooks da conta; obrigatório para receber webhooks da conta"`
+}
+
+// ProviderCredentialUpdateRequestDTO representa o request de atualização de credenciais
+// Campos omitidos mantêm o valor atual
+type ProviderCredentialUpdateRequestDTO struct {
+	APIKey        *string `json:"api_key,omitempty"`
+	BaseURL       *string `json:"base_url,omitempty" binding:"omitempty,url"`
+	Login         *string `json:"login,omitempty"`
+	Password      *string `json:"password,omitempty"`
+	Active        *bool   `json:"active,omitempty"`
+	AccountKey    *string `json:"account_key,omitempty"`
+	WebhookSecret *string `json:"webhook_secret,omitempty"`
+}
+
+// ProviderCredentialResponseDTO representa as credenciais na resposta
+// Segredos nunca são retornados, apenas indicadores de preenchimento
+type ProviderCredentialResponseDTO struct {
+	ID               int       `json:"id"`
+	TenantID         string    `json:"tenant_id"`
+	Provider         string    `json:"provider"`
+	BaseURL          string    `json:"base_url,omitempty"`
+	Login            string    `json:"login,omitempty"`
+	HasAPIKey        bool      `json:"has_api_key"`
+	HasPassword      bool      `json:"has_password"`
+	HasWebhookSecret bool      `json:"has_webhook_secret"`
+	Active           bool      `json:"active"`
+	AccountKey       string    `json:"account_key,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// Validate valida as credenciais exigidas por cada provider
+func (dto *ProviderCredentialCreateRequestDTO) Validate() error {
+	switch dto.Provider {
+	case "clicksign":
+		if strings.TrimSpace(dto.APIKey) == "" {
+			return fmt.Errorf("api_key é obrigatório para o provider clicksign")
+		}
+	case "vert-sign":
+		if strings.TrimSpace(dto.Login) == "" || dto.Password == "" {
+			return fmt.Errorf("login e password são obrigatórios para o provider vert-sign")
+		}
+	}
+
+	return nil
+}
+
+// ToEntity converte o DTO para a entidade de credenciais
+func (dto *ProviderCredentialCreateRequestDTO) ToEntity() entity.EntityProviderCredential {
+	active := true
+	if dto.Active != nil {
+		active = *dto.Active
+	}
+
+	return entity.EntityProviderCredential{
+		TenantID:      dto.TenantID,
+		Provider:      dto.Provider,
+		APIKey:        dto.APIKey,
+		BaseURL:       dto.BaseURL,
+		Login:         dto.Login,
+		Password:      dto.Password,
+		Active:        active,
+		AccountKey:    dto.AccountKey,
+		WebhookSecret: dto.WebhookSecret,
+	}
+}
+
+// ApplyTo aplica os campos informados sobre a entidade existente
+func (dto *ProviderCredentialUpdateRequestDTO) ApplyTo(credential *entity.EntityProviderCredential) {
+	if dto.APIKey != nil {
+		credential.APIKey = *dto.APIKey
+	}
+	if dto.BaseURL != nil {
+		credential.BaseURL = strings.TrimSpace(*dto.BaseURL)
+	}
+	if dto.Login != nil {
+		credential.Login = strings.TrimSpace(*dto.Login)
+	}
+	if dto.Password != nil {
+		credential.Password = *dto.Password
+	}
+	if dto.Active != nil {
+		credential.Active = *dto.Active
+	}
+	if dto.AccountKey != nil {
+		credential.AccountKey = strings.TrimSpace(*dto.AccountKey)
+	}
+	if dto.WebhookSecret != nil {
+		credential.WebhookSecret = *dto.WebhookSecret
+	}
+}
+
+// NewProviderCredentialResponseDTO converte a entidade para o DTO de resposta
+func NewProviderCredentialResponseDTO(credential *entity.EntityProviderCredential) ProviderCredentialResponseDTO {
+	return ProviderCredentialResponseDTO{
+		ID:               credential.ID,
+		TenantID:         credential.TenantID,
+		Provider:         credential.Provider,
+		BaseURL:          credential.BaseURL,
+		Login:            credential.Login,
+		HasAPIKey:        credential.APIKey != "",
+		HasPassword:      credential.Password != "",
+		HasWebhookSecret: credential.WebhookSecret != "",
+		Active:           credential.Active,
+		AccountKey:       credential.AccountKey,
+		CreatedAt:        credential.CreatedAt,
+		UpdatedAt:        credential.UpdatedAt,
+	}
+}
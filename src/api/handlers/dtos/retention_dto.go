@@ -0,0 +1,44 @@
+package dtos
+
+import "time"
+
+// RetentionPolicyResponseDTO representa os prazos de retenção configurados, em dias (0 mantém para sempre)
+type RetentionPolicyResponseDTO struct {
+	EnvelopeRawDataDays int    `json:"envelope_raw_data_days" example:"90"`
+	WebhookPayloadDays  int    `json:"webhook_payload_days" example:"90"`
+	SignerPIIDays       int    `json:"signer_pii_days" example:"1825"`
+	DocumentFileDays    int    `json:"document_file_days" example:"0"`
+	ScheduleEnabled     bool   `json:"schedule_enabled" example:"true"`
+	Schedule            string `json:"schedule" example:"0 3 * * *"`
+	ScheduleDryRun      bool   `json:"schedule_dry_run" example:"true"`
+}
+
+// RetentionRunItemResponseDTO representa o resultado de uma regra em uma execução do expurgo
+type RetentionRunItemResponseDTO struct {
+	Rule   string    `json:"rule" example:"signer_pii"`
+	Cutoff time.Time `json:"cutoff"`
+	Count  int       `json:"count" example:"2"`
+	IDs    []int     `json:"ids" example:"10,11"`
+	Files  int       `json:"files,omitempty" example:"0" doc:"Arquivos apagados do storage (regra document_files)"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// RetentionRunResponseDTO representa uma execução do expurgo e o que foi expurgado
+type RetentionRunResponseDTO struct {
+	ID         int                           `json:"id"`
+	Trigger    string                        `json:"trigger" example:"manual"`
+	DryRun     bool                          `json:"dry_run" example:"true"`
+	Status     string                        `json:"status" example:"completed"`
+	Policy     RetentionPolicyResponseDTO    `json:"policy"`
+	Items      []RetentionRunItemResponseDTO `json:"items"`
+	Total      int                           `json:"total" example:"2"`
+	Error      string                        `json:"error,omitempty"`
+	StartedAt  time.Time                     `json:"started_at"`
+	FinishedAt *time.Time                    `json:"finished_at,omitempty"`
+}
+
+// RetentionRunListResponseDTO representa a estrutura de response para lista de execuções
+type RetentionRunListResponseDTO struct {
+	Runs  []RetentionRunResponseDTO `json:"runs"`
+	Total int                       `json:"total"`
+}
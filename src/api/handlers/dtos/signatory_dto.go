@@ -300,14 +300,17 @@ type SignatoryFiltersDTO struct {
 	EnvelopeID int    `json:"envelope_id,omitempty"`
 	Email      string `json:"email,omitempty"`
 	Name       string `json:"name,omitempty"`
+	// Documentation é comparado pelo documento inteiro, sem busca parcial
+	Documentation string `json:"documentation,omitempty"`
 }
 
 // ToEntityFilters converte os filtros do DTO para a estrutura da entidade
 func (dto *SignatoryFiltersDTO) ToEntityFilters() entity.EntitySignatoryFilters {
 	return entity.EntitySignatoryFilters{
-		IDs:        dto.IDs,
-		EnvelopeID: dto.EnvelopeID,
-		Email:      dto.Email,
-		Name:       dto.Name,
+		IDs:           dto.IDs,
+		EnvelopeID:    dto.EnvelopeID,
+		Email:         dto.Email,
+		Name:          dto.Name,
+		Documentation: dto.Documentation,
 	}
 }
@@ -0,0 +1,45 @@
+package dtos
+
+import (
+	"time"
+
+	"app/entity"
+)
+
+// TenantCreateRequestDTO representa o request de criação de tenant
+type TenantCreateRequestDTO struct {
+	ID   string `json:"id" binding:"required,max=100" example:"financeiro"`
+	Name string `json:"name" binding:"required,min=3,max=255" example:"Financeiro"`
+}
+
+// TenantUpdateRequestDTO representa o request de atualização de tenant; campos omitidos mantêm o valor atual
+type TenantUpdateRequestDTO struct {
+	Name   *string `json:"name,omitempty" binding:"omitempty,min=3,max=255"`
+	Active *bool   `json:"active,omitempty"`
+}
+
+// TenantResponseDTO representa o tenant na resposta
+type TenantResponseDTO struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TenantListResponseDTO representa a estrutura de response para lista de tenants
+type TenantListResponseDTO struct {
+	Tenants []TenantResponseDTO `json:"tenants"`
+	Total   int                 `json:"total"`
+}
+
+// NewTenantResponseDTO converte a entidade para o DTO de resposta
+func NewTenantResponseDTO(tenant *entity.EntityTenant) TenantResponseDTO {
+	return TenantResponseDTO{
+		ID:        tenant.ID,
+		Name:      tenant.Name,
+		Active:    tenant.Active,
+		CreatedAt: tenant.CreatedAt,
+		UpdatedAt: tenant.UpdatedAt,
+	}
+}
@@ -0,0 +1,27 @@
+package dtos
+
+import "app/infrastructure/jwtkeys"
+
+// TokenResponseDTO representa o par de tokens retornado no login e no refresh
+type TokenResponseDTO struct {
+	Token        string `json:"token" example:"eyJhbGciOiJIUzI1NiIsImtpZCI6ImRlZmF1bHQifQ..."`
+	RefreshToken string `json:"refreshToken,omitempty" example:"6f1c0e9a..."`
+	TokenType    string `json:"tokenType" example:"Bearer"`
+	ExpiresIn    int64  `json:"expiresIn" example:"86400"`
+}
+
+// RefreshTokenRequestDTO representa o request de renovação de tokens
+type RefreshTokenRequestDTO struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+// LogoutRequestDTO representa o request de logout
+type LogoutRequestDTO struct {
+	RefreshToken string `json:"refreshToken,omitempty"`
+	AllSessions  bool   `json:"allSessions,omitempty" doc:"Revoga também as demais sessões do usuário"`
+}
+
+// JWKSResponseDTO representa o conjunto de chaves públicas de verificação
+type JWKSResponseDTO struct {
+	Keys []jwtkeys.JWK `json:"keys"`
+}
@@ -0,0 +1,26 @@
+package dtos
+
+// UserInviteRequestDTO representa o request de convite de usuário; a senha é escolhida pelo convidado
+type UserInviteRequestDTO struct {
+	Name        string   `json:"name" binding:"required,min=3,max=255" example:"Maria Silva"`
+	Email       string   `json:"email" binding:"required,email,max=255" example:"maria@empresa.com"`
+	Role        string   `json:"role,omitempty" example:"operator"`
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+// AcceptInvitationRequestDTO representa o request de aceite do convite
+type AcceptInvitationRequestDTO struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// ForgotPasswordRequestDTO representa o pedido do link de redefinição de senha
+type ForgotPasswordRequestDTO struct {
+	Email string `json:"email" binding:"required,email" example:"maria@empresa.com"`
+}
+
+// ResetPasswordRequestDTO representa o request de redefinição de senha com o token recebido por email
+type ResetPasswordRequestDTO struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
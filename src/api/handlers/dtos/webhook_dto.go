@@ -42,11 +42,14 @@ type WebhookDocumentDTO struct {
 	Events            []WebhookEventDTO      `json:"events"`
 	Attachments       []interface{}          `json:"attachments"`
 	Links             WebhookLinksDTO        `json:"links"`
+	// SHA256 do original recebido pelo provider, quando informado; comparado com o hash da ingestão
+	SHA256 string `json:"sha256"`
 }
 
 // WebhookDownloadsDTO representa os downloads do documento
 type WebhookDownloadsDTO struct {
 	OriginalFileURL string `json:"original_file_url"`
+	SignedFileURL   string `json:"signed_file_url"`
 }
 
 // WebhookSignerDTO representa um signatário do webhook
@@ -87,6 +90,7 @@ type WebhookResponseDTO struct {
 	EventName   string     `json:"event_name"`
 	DocumentKey string     `json:"document_key"`
 	AccountKey  string     `json:"account_key"`
+	TenantID    string     `json:"tenant_id,omitempty"`
 	Status      string     `json:"status"`
 	ProcessedAt *time.Time `json:"processed_at,omitempty"`
 	Error       *string    `json:"error,omitempty"`
@@ -0,0 +1,403 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"app/api/handlers/dtos"
+	"app/api/middleware"
+	"app/config"
+	"app/entity"
+	"app/infrastructure/dependencies"
+	"app/infrastructure/repository"
+	usecase_api_client "app/usecase/api_client"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// APIClientHandlers gerencia os clientes de API e suas chaves; acesso restrito a administradores
+type APIClientHandlers struct {
+	UsecaseAPIClient usecase_api_client.IUsecaseAPIClient
+	Logger           *logrus.Logger
+}
+
+func NewAPIClientHandler(usecaseAPIClient usecase_api_client.IUsecaseAPIClient, logger *logrus.Logger) *APIClientHandlers {
+	return &APIClientHandlers{
+		UsecaseAPIClient: usecaseAPIClient,
+		Logger:           logger,
+	}
+}
+
+// @Summary Criar cliente de API
+// @Description Cria um cliente para integração sistema a sistema e emite a primeira API key
+// @Description A chave completa é exibida apenas nesta resposta; guarde-a com segurança. Somente administradores.
+// @Tags API Clients
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param client body dtos.APIClientCreateRequestDTO true "Dados do cliente"
+// @Success 201 {object} dtos.APIClientCreateResponseDTO "Cliente criado com a primeira chave"
+// @Failure 400 {object} dtos.ErrorResponseDTO "Dados inválidos ou escopo desconhecido"
+// @Failure 403 {object} dtos.ErrorResponseDTO "Apenas administradores"
+// @Failure 500 {object} dtos.ErrorResponseDTO "Erro interno"
+// @Router /api/v1/api-clients [post]
+func (h APIClientHandlers) CreateAPIClientHandler(c *gin.Context) {
+	var requestDTO dtos.APIClientCreateRequestDTO
+	if err := c.ShouldBindJSON(&requestDTO); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if !canGrantScopes(c, requestDTO.Scopes) {
+		respondPlatformScopeForbidden(c)
+		return
+	}
+
+	clientParam := entity.EntityAPIClient{
+		Name:        requestDTO.Name,
+		Description: requestDTO.Description,
+		Scopes:      requestDTO.Scopes,
+	}
+	if user, ok := c.Get("user"); ok {
+		if current, ok := user.(entity.EntityUser); ok {
+			clientParam.CreatedByID = current.ID
+		}
+	}
+
+	client, err := entity.NewAPIClient(clientParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	client, rawKey, err := h.UsecaseAPIClient.CreateClient(client)
+	if err != nil {
+		h.Logger.WithError(err).Error("Failed to create API client")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+			Error:   "Internal server error",
+			Message: "Failed to create API client",
+		})
+		return
+	}
+
+	jsonResponse(c, http.StatusCreated, dtos.APIClientCreateResponseDTO{
+		Client: dtos.NewAPIClientResponseDTO(client),
+		APIKey: dtos.APIKeyIssuedResponseDTO{
+			APIKeyResponseDTO: dtos.NewAPIKeyResponseDTO(&client.Keys[0]),
+			Key:               rawKey,
+		},
+	})
+}
+
+// @Summary Listar clientes de API
+// @Description Retorna os clientes de API com suas chaves (sem os segredos) e os escopos disponíveis. Somente administradores.
+// @Tags API Clients
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} dtos.APIClientListResponseDTO "Lista de clientes"
+// @Failure 403 {object} dtos.ErrorResponseDTO "Apenas administradores"
+// @Failure 500 {object} dtos.ErrorResponseDTO "Erro interno"
+// @Router /api/v1/api-clients [get]
+func (h APIClientHandlers) GetAPIClientsHandler(c *gin.Context) {
+	clients, err := h.UsecaseAPIClient.GetClients()
+	if err != nil {
+		h.Logger.WithError(err).Error("Failed to list API clients")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+			Error:   "Internal server error",
+			Message: "Failed to retrieve API clients",
+		})
+		return
+	}
+
+	responseDTOs := make([]dtos.APIClientResponseDTO, 0, len(clients))
+	for i := range clients {
+		responseDTOs = append(responseDTOs, dtos.NewAPIClientResponseDTO(&clients[i]))
+	}
+
+	jsonResponse(c, http.StatusOK, dtos.APIClientListResponseDTO{
+		Clients: responseDTOs,
+		Total:   len(responseDTOs),
+		Scopes:  entity.APIScopes,
+	})
+}
+
+// @Summary Buscar cliente de API
+// @Description Retorna o cliente com suas chaves, incluindo expiração, revogação e último uso. Somente administradores.
+// @Tags API Clients
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID do cliente"
+// @Success 200 {object} dtos.APIClientResponseDTO "Cliente encontrado"
+// @Failure 400 {object} dtos.ErrorResponseDTO "ID inválido"
+// @Failure 404 {object} dtos.ErrorResponseDTO "Cliente não encontrado"
+// @Router /api/v1/api-clients/{id} [get]
+func (h APIClientHandlers) GetAPIClientHandler(c *gin.Context) {
+	client, ok := h.loadClient(c)
+	if !ok {
+		return
+	}
+
+	jsonResponse(c, http.StatusOK, dtos.NewAPIClientResponseDTO(client))
+}
+
+// @Summary Atualizar cliente de API
+// @Description Altera nome, descrição, escopos ou desativa o cliente; um cliente inativo tem todas as chaves recusadas. Somente administradores.
+// @Tags API Clients
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID do cliente"
+// @Param client body dtos.APIClientUpdateRequestDTO true "Dados para atualização"
+// @Success 200 {object} dtos.APIClientResponseDTO "Cliente atualizado"
+// @Failure 400 {object} dtos.ErrorResponseDTO "Dados inválidos ou escopo desconhecido"
+// @Failure 404 {object} dtos.ErrorResponseDTO "Cliente não encontrado"
+// @Failure 500 {object} dtos.ErrorResponseDTO "Erro interno"
+// @Router /api/v1/api-clients/{id} [put]
+func (h APIClientHandlers) UpdateAPIClientHandler(c *gin.Context) {
+	var requestDTO dtos.APIClientUpdateRequestDTO
+	if err := c.ShouldBindJSON(&requestDTO); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	client, ok := h.loadClient(c)
+	if !ok {
+		return
+	}
+
+	if requestDTO.Name != nil {
+		client.Name = *requestDTO.Name
+	}
+	if requestDTO.Description != nil {
+		client.Description = *requestDTO.Description
+	}
+	if requestDTO.Scopes != nil {
+		if !canGrantScopes(c, requestDTO.Scopes) {
+			respondPlatformScopeForbidden(c)
+			return
+		}
+		client.Scopes = requestDTO.Scopes
+	}
+	if requestDTO.Active != nil {
+		client.Active = *requestDTO.Active
+	}
+	if err := client.SetScopes(client.Scopes); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.UsecaseAPIClient.UpdateClient(client); err != nil {
+		h.Logger.WithError(err).Error("Failed to update API client")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+			Error:   "Internal server error",
+			Message: "Failed to update API client",
+		})
+		return
+	}
+
+	jsonResponse(c, http.StatusOK, dtos.NewAPIClientResponseDTO(client))
+}
+
+// @Summary Rotacionar API key
+// @Description Emite uma nova chave; as chaves atuais continuam válidas por overlap_hours para a troca sem indisponibilidade
+// @Description A chave completa é exibida apenas nesta resposta. Somente administradores.
+// @Tags API Clients
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID do cliente"
+// @Param rotation body dtos.APIKeyRotateRequestDTO false "Período de convivência"
+// @Success 201 {object} dtos.APIKeyIssuedResponseDTO "Nova chave"
+// @Failure 400 {object} dtos.ErrorResponseDTO "Dados inválidos"
+// @Failure 404 {object} dtos.ErrorResponseDTO "Cliente não encontrado"
+// @Failure 500 {object} dtos.ErrorResponseDTO "Erro interno"
+// @Router /api/v1/api-clients/{id}/keys [post]
+func (h APIClientHandlers) RotateAPIKeyHandler(c *gin.Context) {
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	var requestDTO dtos.APIKeyRotateRequestDTO
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&requestDTO); err != nil {
+			c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+				Error:   "Validation failed",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	overlapHours := config.EnvironmentVariables.API_KEY_ROTATION_OVERLAP_HOURS
+	if requestDTO.OverlapHours != nil {
+		overlapHours = *requestDTO.OverlapHours
+	}
+
+	key, rawKey, err := h.UsecaseAPIClient.RotateKey(id, time.Duration(overlapHours)*time.Hour)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			h.respondNotFound(c)
+			return
+		}
+
+		h.Logger.WithError(err).Error("Failed to rotate API key")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+			Error:   "Internal server error",
+			Message: "Failed to rotate API key",
+		})
+		return
+	}
+
+	jsonResponse(c, http.StatusCreated, dtos.APIKeyIssuedResponseDTO{
+		APIKeyResponseDTO: dtos.NewAPIKeyResponseDTO(key),
+		Key:               rawKey,
+	})
+}
+
+// @Summary Revogar API key
+// @Description Invalida a chave imediatamente. Somente administradores.
+// @Tags API Clients
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID do cliente"
+// @Param key_id path int true "ID da chave"
+// @Success 204 "Chave revogada"
+// @Failure 400 {object} dtos.ErrorResponseDTO "ID inválido"
+// @Failure 404 {object} dtos.ErrorResponseDTO "Chave não encontrada"
+// @Failure 500 {object} dtos.ErrorResponseDTO "Erro interno"
+// @Router /api/v1/api-clients/{id}/keys/{key_id} [delete]
+func (h APIClientHandlers) RevokeAPIKeyHandler(c *gin.Context) {
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+	keyID, ok := h.parseID(c, "key_id")
+	if !ok {
+		return
+	}
+
+	if err := h.UsecaseAPIClient.RevokeKey(id, keyID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, dtos.ErrorResponseDTO{
+				Error:   "API key not found",
+				Message: "The requested API key does not exist for this client",
+			})
+			return
+		}
+
+		h.Logger.WithError(err).Error("Failed to revoke API key")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+			Error:   "Internal server error",
+			Message: "Failed to revoke API key",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h APIClientHandlers) parseID(c *gin.Context, param string) (int, bool) {
+	id, err := strconv.Atoi(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Invalid ID",
+			Message: "ID must be a valid integer",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// loadClient busca o cliente do parâmetro :id e já responde 400/404/500 quando não o encontra
+func (h APIClientHandlers) loadClient(c *gin.Context) (*entity.EntityAPIClient, bool) {
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return nil, false
+	}
+
+	client, err := h.UsecaseAPIClient.GetClient(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			h.respondNotFound(c)
+			return nil, false
+		}
+
+		h.Logger.WithError(err).Error("Failed to get API client")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+			Error:   "Internal server error",
+			Message: "Failed to get API client",
+		})
+		return nil, false
+	}
+
+	return client, true
+}
+
+// canGrantScopes impede que administradores de tenant emitam chaves da plataforma; platform:admin só vale para
+// clientes sem tenant, criados por um administrador da plataforma sem X-Tenant-ID
+func canGrantScopes(c *gin.Context, scopes []string) bool {
+	for _, scope := range scopes {
+		if strings.ToLower(strings.TrimSpace(scope)) == entity.ScopePlatformAdmin {
+			return middleware.IsPlatformAdmin(c) && middleware.TenantFromContext(c) == ""
+		}
+	}
+	return true
+}
+
+func respondPlatformScopeForbidden(c *gin.Context) {
+	c.JSON(http.StatusForbidden, dtos.ErrorResponseDTO{
+		Error:   "Forbidden",
+		Message: "Only platform administrators can grant the " + entity.ScopePlatformAdmin + " scope to clients without tenant",
+	})
+}
+
+func (h APIClientHandlers) respondNotFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, dtos.ErrorResponseDTO{
+		Error:   "API client not found",
+		Message: "The requested API client does not exist",
+	})
+}
+
+func MountAPIClientHandlers(gin *gin.Engine, conn *gorm.DB, deps *dependencies.Dependencies) {
+	logger := deps.Logger
+	// Clientes são criados no tenant do administrador (ou no escolhido por X-Tenant-ID, na plataforma)
+	apiClientHandlers := newTenantHandlers(conn, func(conn *gorm.DB) *APIClientHandlers {
+		return NewAPIClientHandler(
+			usecase_api_client.NewUsecaseAPIClientService(repository.NewRepositoryAPIClient(conn), logger),
+			logger,
+		)
+	})
+
+	// Apenas usuários administradores; API keys não gerenciam outras API keys
+	group := gin.Group("/api/v1/api-clients")
+	SetAuthMiddleware(conn, deps, group)
+	group.Use(middleware.RequireAdminMiddleware())
+
+	group.POST("/", apiClientHandlers.Handle((*APIClientHandlers).CreateAPIClientHandler))
+	group.GET("/", apiClientHandlers.Handle((*APIClientHandlers).GetAPIClientsHandler))
+	group.GET("/:id", apiClientHandlers.Handle((*APIClientHandlers).GetAPIClientHandler))
+	group.PUT("/:id", apiClientHandlers.Handle((*APIClientHandlers).UpdateAPIClientHandler))
+	group.POST("/:id/keys", apiClientHandlers.Handle((*APIClientHandlers).RotateAPIKeyHandler))
+	group.DELETE("/:id/keys/:key_id", apiClientHandlers.Handle((*APIClientHandlers).RevokeAPIKeyHandler))
+}
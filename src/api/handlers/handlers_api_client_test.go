@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"app/api/handlers/dtos"
+	"app/config"
+	"app/entity"
+	"app/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func performAPIClientRequest(handler *APIClientHandlers, method, path string, body interface{}) *httptest.ResponseRecorder {
+	return performAPIClientRequestAs(handler, entity.EntityUser{ID: 1, IsAdmin: true}, method, path, body)
+}
+
+func performAPIClientRequestAs(handler *APIClientHandlers, user entity.EntityUser, method, path string, body interface{}) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) { c.Set("user", user) })
+	router.POST("/api/v1/api-clients", handler.CreateAPIClientHandler)
+	router.PUT("/api/v1/api-clients/:id", handler.UpdateAPIClientHandler)
+	router.POST("/api/v1/api-clients/:id/keys", handler.RotateAPIKeyHandler)
+	router.DELETE("/api/v1/api-clients/:id/keys/:key_id", handler.RevokeAPIKeyHandler)
+
+	var reader *bytes.Buffer
+	if body != nil {
+		jsonData, _ := json.Marshal(body)
+		reader = bytes.NewBuffer(jsonData)
+	} else {
+		reader = &bytes.Buffer{}
+	}
+	req, _ := http.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAPIClientHandlers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockIUsecaseAPIClient(ctrl)
+	handler := NewAPIClientHandler(mockUsecase, logrus.New())
+
+	t.Run("should create the client and return the key once", func(t *testing.T) {
+		mockUsecase.EXPECT().CreateClient(gomock.Any()).DoAndReturn(func(client *entity.EntityAPIClient) (*entity.EntityAPIClient, string, error) {
+			client.ID = 3
+			client.Keys = []entity.EntityAPIKey{{ID: 10, ClientID: 3, Prefix: "abc"}}
+			return client, "dsk_abc_secret", nil
+		})
+
+		w := performAPIClientRequest(handler, http.MethodPost, "/api/v1/api-clients", dtos.APIClientCreateRequestDTO{
+			Name:   "erp-financeiro",
+			Scopes: []string{entity.ScopeEnvelopesWrite},
+		})
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		var response dtos.APIClientCreateResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "dsk_abc_secret", response.APIKey.Key)
+		assert.Equal(t, []string{entity.ScopeEnvelopesWrite}, response.Client.Scopes)
+		assert.NotContains(t, w.Body.String(), "key_hash")
+	})
+
+	t.Run("should let only platform admins grant the platform scope", func(t *testing.T) {
+		w := performAPIClientRequestAs(handler, entity.EntityUser{ID: 2, IsAdmin: true, TenantID: "acme"}, http.MethodPost, "/api/v1/api-clients", dtos.APIClientCreateRequestDTO{
+			Name:   "erp-financeiro",
+			Scopes: []string{entity.ScopeEnvelopesWrite, entity.ScopePlatformAdmin},
+		})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		mockUsecase.EXPECT().CreateClient(gomock.Any()).DoAndReturn(func(client *entity.EntityAPIClient) (*entity.EntityAPIClient, string, error) {
+			client.ID = 4
+			client.Keys = []entity.EntityAPIKey{{ID: 11, ClientID: 4, Prefix: "def"}}
+			return client, "dsk_def_secret", nil
+		})
+		w = performAPIClientRequest(handler, http.MethodPost, "/api/v1/api-clients", dtos.APIClientCreateRequestDTO{
+			Name:   "backoffice",
+			Scopes: []string{entity.ScopePlatformAdmin},
+		})
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("should reject unknown scopes", func(t *testing.T) {
+		w := performAPIClientRequest(handler, http.MethodPost, "/api/v1/api-clients", dtos.APIClientCreateRequestDTO{
+			Name:   "erp-financeiro",
+			Scopes: []string{"everything"},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should rotate with the configured overlap by default", func(t *testing.T) {
+		previous := config.EnvironmentVariables.API_KEY_ROTATION_OVERLAP_HOURS
+		config.EnvironmentVariables.API_KEY_ROTATION_OVERLAP_HOURS = 24
+		defer func() { config.EnvironmentVariables.API_KEY_ROTATION_OVERLAP_HOURS = previous }()
+
+		mockUsecase.EXPECT().RotateKey(3, 24*time.Hour).Return(&entity.EntityAPIKey{ID: 11, ClientID: 3}, "dsk_def_secret", nil)
+		w := performAPIClientRequest(handler, http.MethodPost, "/api/v1/api-clients/3/keys", nil)
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		overlap := 0
+		mockUsecase.EXPECT().RotateKey(3, time.Duration(0)).Return(&entity.EntityAPIKey{ID: 12, ClientID: 3}, "dsk_ghi_secret", nil)
+		w = performAPIClientRequest(handler, http.MethodPost, "/api/v1/api-clients/3/keys", dtos.APIKeyRotateRequestDTO{OverlapHours: &overlap})
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("should return 404 when revoking an unknown key", func(t *testing.T) {
+		mockUsecase.EXPECT().RevokeKey(3, 99).Return(gorm.ErrRecordNotFound)
+
+		w := performAPIClientRequest(handler, http.MethodDelete, "/api/v1/api-clients/3/keys/99", nil)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"app/config"
 	"app/entity"
 	"app/infrastructure/clicksign"
+	"app/infrastructure/dependencies"
 	"app/infrastructure/repository"
 	"app/infrastructure/vertc_assinaturas"
 	usecase_auto_signature_term "app/usecase/auto_signature_term"
@@ -135,16 +136,23 @@ func (h *AutoSignatureTermHandlers) GetAutoSignatureTermHandler(c *gin.Context)
 }
 
 // @Summary Get all auto signature terms
-// @Description Get all auto signature terms
+// @Description Get all auto signature terms, optionally filtered by the signer documentation. Signer documentation is masked in the list
 // @Tags auto-signature-terms
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
+// @Param documentation query string false "Signer documentation (CPF/CNPJ)"
 // @Success 200 {object} dtos.AutoSignatureTermListResponseDTO "List of auto signature terms"
 // @Failure 500 {object} dtos.ErrorResponseDTO "Internal server error"
 // @Router /api/v1/auto-signature/terms [get]
 func (h *AutoSignatureTermHandlers) GetAllAutoSignatureTermsHandler(c *gin.Context) {
-	terms, err := h.UsecaseAutoSignatureTerm.GetAllAutoSignatureTerms()
+	var terms []entity.EntityAutoSignatureTerm
+	var err error
+	if documentation := c.Query("documentation"); documentation != "" {
+		terms, err = h.UsecaseAutoSignatureTerm.GetAutoSignatureTermsBySignerDocumentation(documentation)
+	} else {
+		terms, err = h.UsecaseAutoSignatureTerm.GetAllAutoSignatureTerms()
+	}
 	if err != nil {
 		h.Logger.WithError(err).Error("Failed to get all auto signature terms")
 		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
@@ -409,7 +417,8 @@ func (h *AutoSignatureTermHandlers) mapEntityToResponse(term *entity.EntityAutoS
 func (h *AutoSignatureTermHandlers) mapEntitiesToResponseList(terms []entity.EntityAutoSignatureTerm) dtos.AutoSignatureTermListResponseDTO {
 	var responseList []dtos.AutoSignatureTermResponseDTO
 	for _, term := range terms {
-		responseList = append(responseList, h.mapEntityToResponse(&term))
+		masked := term.Masked()
+		responseList = append(responseList, h.mapEntityToResponse(&masked))
 	}
 
 	return dtos.AutoSignatureTermListResponseDTO{
@@ -523,26 +532,29 @@ func validateClicksignCreateTermRequest(requestDTO dtos.AutoSignatureTermCreateR
 	return details
 }
 
-func MountAutoSignatureTermHandlers(gin *gin.Engine, conn *gorm.DB, logger *logrus.Logger) {
+func MountAutoSignatureTermHandlers(gin *gin.Engine, conn *gorm.DB, deps *dependencies.Dependencies) {
+	logger := deps.Logger
 	clicksignClient := clicksign.NewClicksignClient(config.EnvironmentVariables, logger).(*clicksign.ClicksignClient)
 	vertcAssinaturasClient := vertc_assinaturas.NewVertcAssinaturasClient(config.EnvironmentVariables, logger)
 
-	autoSignatureTermHandlers := NewAutoSignatureTermHandler(
-		usecase_auto_signature_term.NewUsecaseAutoSignatureTermService(
-			repository.NewRepositoryAutoSignatureTerm(conn),
-			clicksignClient,
+	autoSignatureTermHandlers := newTenantHandlers(conn, func(conn *gorm.DB) *AutoSignatureTermHandlers {
+		return NewAutoSignatureTermHandler(
+			usecase_auto_signature_term.NewUsecaseAutoSignatureTermService(
+				repository.NewRepositoryAutoSignatureTerm(conn),
+				clicksignClient,
+				logger,
+			),
+			vertc_assinaturas.NewAutomaticSignatureService(vertcAssinaturasClient, logger),
 			logger,
-		),
-		vertc_assinaturas.NewAutomaticSignatureService(vertcAssinaturasClient, logger),
-		logger,
-	)
+		)
+	})
 
 	group := gin.Group("/api/v1/auto-signature/terms")
-	SetAuthMiddleware(conn, group)
+	SetScopedAuthMiddleware(conn, deps, group, entity.ScopeTermsRead, entity.ScopeTermsWrite)
 
-	group.POST("/", autoSignatureTermHandlers.CreateAutoSignatureTermHandler)
-	group.GET("/status", autoSignatureTermHandlers.CheckAutoSignatureTermStatusHandler)
-	group.GET("/:id", autoSignatureTermHandlers.GetAutoSignatureTermHandler)
-	group.GET("/", autoSignatureTermHandlers.GetAllAutoSignatureTermsHandler)
-	group.DELETE("/:id", autoSignatureTermHandlers.DeleteAutoSignatureTermHandler)
+	group.POST("/", autoSignatureTermHandlers.Handle((*AutoSignatureTermHandlers).CreateAutoSignatureTermHandler))
+	group.GET("/status", autoSignatureTermHandlers.Handle((*AutoSignatureTermHandlers).CheckAutoSignatureTermStatusHandler))
+	group.GET("/:id", autoSignatureTermHandlers.Handle((*AutoSignatureTermHandlers).GetAutoSignatureTermHandler))
+	group.GET("/", autoSignatureTermHandlers.Handle((*AutoSignatureTermHandlers).GetAllAutoSignatureTermsHandler))
+	group.DELETE("/:id", autoSignatureTermHandlers.Handle((*AutoSignatureTermHandlers).DeleteAutoSignatureTermHandler))
 }
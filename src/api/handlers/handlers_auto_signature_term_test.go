@@ -1,9 +1,18 @@
 package handlers
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"app/api/handlers/dtos"
+	"app/entity"
+	"app/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -47,3 +56,49 @@ func TestAutoSignatureTermHandler_MapCreateRequestToEntity(t *testing.T) {
 		assert.Contains(t, err.Error(), "alphanumeric CNPJ")
 	})
 }
+
+func TestGetAllAutoSignatureTermsHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockIUsecaseAutoSignatureTerm(ctrl)
+	handler := &AutoSignatureTermHandlers{UsecaseAutoSignatureTerm: mockUsecase, Logger: logrus.New()}
+	router := gin.New()
+	router.GET("/api/v1/auto-signature/terms", handler.GetAllAutoSignatureTermsHandler)
+
+	rawData := `{"signer":{"documentation":"123.456.789-09"}}`
+	terms := []entity.EntityAutoSignatureTerm{{ID: 1, SignerDocumentation: "123.456.789-09", SignerName: "John Doe", ClicksignRawData: &rawData}}
+
+	get := func(url string) dtos.AutoSignatureTermListResponseDTO {
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "123.456.789-09")
+
+		var response dtos.AutoSignatureTermListResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	t.Run("should mask the signer documentation", func(t *testing.T) {
+		mockUsecase.EXPECT().GetAllAutoSignatureTerms().Return(terms, nil)
+
+		response := get("/api/v1/auto-signature/terms")
+
+		require.Len(t, response.Terms, 1)
+		assert.Equal(t, "***.***.***-09", response.Terms[0].Signer.Documentation)
+		assert.Nil(t, response.Terms[0].ClicksignRawData)
+		assert.Equal(t, "123.456.789-09", terms[0].SignerDocumentation)
+	})
+
+	t.Run("should filter by the signer documentation", func(t *testing.T) {
+		mockUsecase.EXPECT().GetAutoSignatureTermsBySignerDocumentation("12345678909").Return(terms, nil)
+
+		response := get("/api/v1/auto-signature/terms?documentation=12345678909")
+
+		assert.Equal(t, 1, response.Total)
+	})
+}
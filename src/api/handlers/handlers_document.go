@@ -5,9 +5,14 @@ import (
 	"app/config"
 	"app/entity"
 	"app/infrastructure/clicksign"
+	"app/infrastructure/dependencies"
 	"app/infrastructure/repository"
+	"app/infrastructure/storage"
 	"app/pkg/utils"
 	usecase_document "app/usecase/document"
+	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
 	"time"
@@ -19,33 +24,45 @@ import (
 )
 
 type DocumentHandlers struct {
-	UsecaseDocument usecase_document.IUsecaseDocument
-	Logger          *logrus.Logger
+	UsecaseDocument          usecase_document.IUsecaseDocument
+	UsecaseDocumentIngestion *usecase_document.UsecaseDocumentIngestionService
+	Logger                   *logrus.Logger
 }
 
-func NewDocumentHandler(usecaseDocument usecase_document.IUsecaseDocument, logger *logrus.Logger) *DocumentHandlers {
+func NewDocumentHandler(usecaseDocument usecase_document.IUsecaseDocument, usecaseDocumentIngestion *usecase_document.UsecaseDocumentIngestionService, logger *logrus.Logger) *DocumentHandlers {
 	return &DocumentHandlers{
-		UsecaseDocument: usecaseDocument,
-		Logger:          logger,
+		UsecaseDocument:          usecaseDocument,
+		UsecaseDocumentIngestion: usecaseDocumentIngestion,
+		Logger:                   logger,
 	}
 }
 
 // @Summary Criar documento
-// @Description Cria um novo documento usando file_path ou conteúdo base64
+// @Description Cria um novo documento usando file_path, conteúdo base64 ou upload multipart
 // @Description Aceita documentos através de file_path (caminho absoluto) ou file_content_base64 (conteúdo em base64)
 // @Description Para file_path: file_size e mime_type são obrigatórios
 // @Description Para file_content_base64: file_size e mime_type são opcionais (detectados automaticamente)
-// @Description Tipos suportados: PDF, JPEG, PNG, GIF
+// @Description Com multipart/form-data, o campo file é gravado no storage em streaming (limite DOCUMENT_UPLOAD_MAX_SIZE_MB, padrão 50MB)
+// @Description e o id retornado pode ser usado em documents_ids na criação de envelopes v2
+// @Description Tipos suportados: PDF, JPEG, PNG, GIF; DOCX, ODT e HTML são convertidos para PDF e o arquivo original é mantido no storage
+// @Description PDFs são validados na ingestão: arquivos criptografados ou corrompidos são recusados com 422
+// @Description Com DOCUMENT_SCANNER habilitado, uploads infectados são registrados com status quarantined e respondidos com 422
 // @Description Tamanho máximo: 7.5MB após decodificação
 // @Tags Documents
-// @Accept json
+// @Accept json,mpfd
 // @Produce json
 // @Security ApiKeyAuth
-// @Param document body dtos.DocumentCreateRequestDTO true "Dados do documento"
+// @Param document body dtos.DocumentCreateRequestDTO false "Dados do documento (application/json)"
+// @Param name formData string false "Nome do documento (multipart/form-data)"
+// @Param description formData string false "Descrição do documento (multipart/form-data)"
+// @Param file formData file false "Arquivo do documento (multipart/form-data)"
 // @Success 201 {object} dtos.DocumentResponseDTO "Documento criado com sucesso"
 // @Failure 400 {object} dtos.ErrorResponseDTO "Dados inválidos"
 // @Failure 401 {object} dtos.ErrorResponseDTO "Não autorizado"
+// @Failure 413 {object} dtos.ErrorResponseDTO "Arquivo excede o tamanho máximo"
+// @Failure 422 {object} dtos.ErrorResponseDTO "Falha na conversão para PDF, PDF criptografado/corrompido ou arquivo infectado"
 // @Failure 500 {object} dtos.ErrorResponseDTO "Erro interno"
+// @Failure 503 {object} dtos.ErrorResponseDTO "Verificação de malware ou ingestão de documentos indisponível"
 // @Router /api/v1/documents [post]
 func (h DocumentHandlers) CreateDocumentHandler(c *gin.Context) {
 	correlationID := c.GetHeader("X-Correlation-ID")
@@ -53,6 +70,11 @@ func (h DocumentHandlers) CreateDocumentHandler(c *gin.Context) {
 		correlationID = strconv.FormatInt(time.Now().Unix(), 10)
 	}
 
+	if c.ContentType() == "multipart/form-data" {
+		h.createDocumentFromMultipart(c, correlationID)
+		return
+	}
+
 	var requestDTO dtos.DocumentCreateRequestDTO
 
 	if err := c.ShouldBindJSON(&requestDTO); err != nil {
@@ -158,6 +180,55 @@ func (h DocumentHandlers) GetDocumentHandler(c *gin.Context) {
 	jsonResponse(c, http.StatusOK, responseDTO)
 }
 
+// @Summary Histórico de versões do documento
+// @Description Retorna a versão atual, as versões substituídas (PUT /api/v2/envelopes/{id}/documents/{document_id}) e qual versão foi assinada
+// @Tags Documents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID do documento"
+// @Success 200 {object} dtos.DocumentVersionsResponseDTO "Histórico de versões"
+// @Failure 401 {object} dtos.ErrorResponseDTO "Não autorizado"
+// @Failure 404 {object} dtos.ErrorResponseDTO "Documento não encontrado"
+// @Failure 500 {object} dtos.ErrorResponseDTO "Erro interno"
+// @Router /api/v1/documents/{id}/versions [get]
+func (h DocumentHandlers) GetDocumentVersionsHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Invalid ID",
+			Message: "Document ID must be a valid integer",
+		})
+		return
+	}
+
+	document, err := h.UsecaseDocument.GetDocument(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, dtos.ErrorResponseDTO{
+			Error:   "Document not found",
+			Message: "The requested document does not exist",
+		})
+		return
+	}
+
+	versions, err := h.UsecaseDocument.GetVersions(id)
+	if err != nil {
+		h.Logger.WithFields(logrus.Fields{
+			"document_id": id,
+			"error":       err.Error(),
+		}).Error("Failed to get document versions")
+
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+			Error:   "Internal server error",
+			Message: "Failed to get document versions",
+		})
+		return
+	}
+
+	jsonResponse(c, http.StatusOK, h.mapVersionsToResponse(document, versions))
+}
+
 // @Summary Listar documentos
 // @Description Retorna uma lista de documentos com filtros opcionais
 // @Tags Documents
@@ -338,9 +409,310 @@ func (h DocumentHandlers) DeleteDocumentHandler(c *gin.Context) {
 	jsonResponse(c, http.StatusOK, gin.H{"message": "Documento deletado com sucesso"})
 }
 
+// createDocumentFromMultipart grava o campo file direto no storage, calculando o hash durante a leitura,
+// sem carregar o arquivo inteiro em memória
+func (h DocumentHandlers) createDocumentFromMultipart(c *gin.Context, correlationID string) {
+	maxSize := documentUploadMaxSize()
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+multipartFieldsMaxSize)
+
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Invalid request",
+			Message: "Invalid multipart request: " + err.Error(),
+		})
+		return
+	}
+
+	document := &entity.EntityDocument{Status: "draft"}
+	hasFile := false
+	var infected error
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			h.respondUploadError(c, correlationID, err)
+			return
+		}
+
+		switch part.FormName() {
+		case "name":
+			document.Name, err = readMultipartValue(part)
+		case "description":
+			document.Description, err = readMultipartValue(part)
+		case "file":
+			if hasFile {
+				err = errors.New("only one file is accepted per document")
+				break
+			}
+			err = h.UsecaseDocumentIngestion.StoreUpload(c.Request.Context(), document, part, maxSize)
+			// O upload infectado é registrado em quarentena depois de lidos os demais campos
+			if errors.Is(err, usecase_document.ErrInfected) {
+				infected, err = err, nil
+			}
+			hasFile = err == nil
+		}
+		part.Close()
+
+		if err != nil {
+			h.respondUploadError(c, correlationID, err)
+			return
+		}
+	}
+
+	if !hasFile {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Validation failed",
+			Message: "file is required",
+		})
+		return
+	}
+
+	if err := document.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.UsecaseDocument.Create(document); err != nil {
+		h.Logger.WithFields(logrus.Fields{
+			"correlation_id": correlationID,
+			"storage_key":    document.StorageKey,
+			"error":          err.Error(),
+		}).Error("Failed to create uploaded document")
+
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+			Error:   "Internal server error",
+			Message: "Failed to create document",
+			Details: map[string]interface{}{
+				"correlation_id": correlationID,
+			},
+		})
+		return
+	}
+
+	if infected != nil {
+		h.Logger.WithFields(logrus.Fields{
+			"correlation_id": correlationID,
+			"document_id":    document.ID,
+			"storage_key":    document.StorageKey,
+			"error":          infected.Error(),
+		}).Warn("Uploaded document quarantined")
+
+		c.JSON(http.StatusUnprocessableEntity, dtos.ErrorResponseDTO{
+			Error:   "Document infected",
+			Message: infected.Error(),
+			Details: map[string]interface{}{
+				"document_id": document.ID,
+				"status":      document.Status,
+			},
+		})
+		return
+	}
+
+	jsonResponse(c, http.StatusCreated, h.mapEntityToResponse(document))
+}
+
+// @Summary Verificar integridade de documento
+// @Description Calcula o SHA-256 do arquivo enviado, sem armazená-lo, e informa se ele corresponde ao original
+// @Description ou à cópia assinada de algum documento registrado
+// @Tags Documents
+// @Accept mpfd
+// @Produce json
+// @Security ApiKeyAuth
+// @Param file formData file true "Arquivo a verificar"
+// @Success 200 {object} dtos.DocumentVerifyResponseDTO "Resultado da verificação"
+// @Failure 400 {object} dtos.ErrorResponseDTO "Dados inválidos"
+// @Failure 401 {object} dtos.ErrorResponseDTO "Não autorizado"
+// @Failure 413 {object} dtos.ErrorResponseDTO "Arquivo excede o tamanho máximo"
+// @Failure 500 {object} dtos.ErrorResponseDTO "Erro interno"
+// @Router /api/v2/documents/verify [post]
+func (h DocumentHandlers) VerifyDocumentHandler(c *gin.Context) {
+	correlationID := c.GetHeader("X-Correlation-ID")
+	if correlationID == "" {
+		correlationID = strconv.FormatInt(time.Now().Unix(), 10)
+	}
+
+	maxSize := documentUploadMaxSize()
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+multipartFieldsMaxSize)
+
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Invalid request",
+			Message: "Invalid multipart request: " + err.Error(),
+		})
+		return
+	}
+
+	var object *storage.Object
+	for object == nil {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			h.respondUploadError(c, correlationID, err)
+			return
+		}
+
+		if part.FormName() == "file" {
+			object, err = storage.Hash(part, maxSize)
+		}
+		part.Close()
+
+		if err != nil {
+			h.respondUploadError(c, correlationID, err)
+			return
+		}
+	}
+
+	if object == nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Validation failed",
+			Message: "file is required",
+		})
+		return
+	}
+
+	documents, err := h.UsecaseDocument.FindByContentHash(object.SHA256)
+	if err != nil {
+		h.Logger.WithFields(logrus.Fields{
+			"correlation_id": correlationID,
+			"sha256":         object.SHA256,
+			"error":          err.Error(),
+		}).Error("Failed to verify document")
+
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+			Error:   "Internal server error",
+			Message: "Failed to verify document",
+			Details: map[string]interface{}{
+				"correlation_id": correlationID,
+			},
+		})
+		return
+	}
+
+	response := dtos.DocumentVerifyResponseDTO{
+		SHA256:  object.SHA256,
+		Size:    object.Size,
+		Matches: []dtos.DocumentVerifyMatchDTO{},
+	}
+	for _, document := range documents {
+		matchedCopy := "original"
+		if document.SHA256 != object.SHA256 {
+			matchedCopy = "signed"
+		}
+		response.Matches = append(response.Matches, dtos.DocumentVerifyMatchDTO{
+			DocumentID:      document.ID,
+			Name:            document.Name,
+			Status:          document.Status,
+			MatchedCopy:     matchedCopy,
+			IntegrityStatus: document.IntegrityStatus,
+		})
+	}
+	response.Matched = len(response.Matches) > 0
+
+	jsonResponse(c, http.StatusOK, response)
+}
+
+func (h DocumentHandlers) respondUploadError(c *gin.Context, correlationID string, err error) {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.Is(err, storage.ErrTooLarge) || errors.As(err, &maxBytesErr):
+		c.JSON(http.StatusRequestEntityTooLarge, dtos.ErrorResponseDTO{
+			Error:   "File too large",
+			Message: err.Error(),
+		})
+	case errors.Is(err, usecase_document.ErrUnsupportedUpload):
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+	case errors.Is(err, usecase_document.ErrConversion):
+		h.Logger.WithFields(logrus.Fields{
+			"correlation_id": correlationID,
+			"error":          err.Error(),
+		}).Warn("Failed to convert uploaded document")
+
+		c.JSON(http.StatusUnprocessableEntity, dtos.ErrorResponseDTO{
+			Error:   "Conversion failed",
+			Message: err.Error(),
+		})
+	case errors.Is(err, usecase_document.ErrNormalization):
+		h.Logger.WithFields(logrus.Fields{
+			"correlation_id": correlationID,
+			"error":          err.Error(),
+		}).Warn("Uploaded document rejected by normalization")
+
+		c.JSON(http.StatusUnprocessableEntity, dtos.ErrorResponseDTO{
+			Error:   "Document rejected",
+			Message: err.Error(),
+		})
+	case errors.Is(err, usecase_document.ErrScanFailed):
+		h.Logger.WithFields(logrus.Fields{
+			"correlation_id": correlationID,
+			"error":          err.Error(),
+		}).Error("Failed to scan uploaded document")
+
+		c.JSON(http.StatusServiceUnavailable, dtos.ErrorResponseDTO{
+			Error:   "Malware scan unavailable",
+			Message: "Failed to scan document",
+			Details: map[string]interface{}{
+				"correlation_id": correlationID,
+			},
+		})
+	case errors.Is(err, usecase_document.ErrNotConfigured):
+		h.Logger.WithFields(logrus.Fields{
+			"correlation_id": correlationID,
+			"error":          err.Error(),
+		}).Error("Document ingestion is not configured")
+
+		c.JSON(http.StatusServiceUnavailable, dtos.ErrorResponseDTO{
+			Error:   "Document ingestion unavailable",
+			Message: "Failed to process document",
+			Details: map[string]interface{}{
+				"correlation_id": correlationID,
+			},
+		})
+	case errors.Is(err, usecase_document.ErrUploadStorage):
+		h.Logger.WithFields(logrus.Fields{
+			"correlation_id": correlationID,
+			"error":          err.Error(),
+		}).Error("Failed to store uploaded document")
+
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+			Error:   "Internal server error",
+			Message: "Failed to store document",
+			Details: map[string]interface{}{
+				"correlation_id": correlationID,
+			},
+		})
+	default:
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+	}
+}
+
 // Helper methods
 
 func (h DocumentHandlers) mapEntityToResponse(document *entity.EntityDocument) dtos.DocumentResponseDTO {
+	return mapDocumentToResponse(document)
+}
+
+// mapDocumentToResponse converte o documento para o DTO de resposta; usado também pelas rotas de envelope
+func mapDocumentToResponse(document *entity.EntityDocument) dtos.DocumentResponseDTO {
+	var metadata map[string]interface{}
+	if len(document.Metadata) > 0 {
+		_ = json.Unmarshal(document.Metadata, &metadata)
+	}
+
 	return dtos.DocumentResponseDTO{
 		ID:           document.ID,
 		Name:         document.Name,
@@ -349,10 +721,50 @@ func (h DocumentHandlers) mapEntityToResponse(document *entity.EntityDocument) d
 		MimeType:     document.MimeType,
 		Status:       document.Status,
 		ClicksignKey: document.ClicksignKey,
+		StorageKey:   document.StorageKey,
+		SHA256:       document.SHA256,
+		SignedSHA256: document.SignedSHA256,
+		Integrity:    document.IntegrityStatus,
 		Description:  document.Description,
+		Metadata:     metadata,
 		CreatedAt:    document.CreatedAt,
 		UpdatedAt:    document.UpdatedAt,
+
+		Version:       document.CurrentVersion(),
+		SignedVersion: document.SignedVersion,
+	}
+}
+
+func (h DocumentHandlers) mapVersionsToResponse(document *entity.EntityDocument, versions []entity.EntityDocumentVersion) dtos.DocumentVersionsResponseDTO {
+	response := dtos.DocumentVersionsResponseDTO{
+		DocumentID:     document.ID,
+		CurrentVersion: document.CurrentVersion(),
+		SignedVersion:  document.SignedVersion,
+		Current: dtos.DocumentVersionResponseDTO{
+			Version:    document.CurrentVersion(),
+			FileSize:   document.FileSize,
+			MimeType:   document.MimeType,
+			StorageKey: document.StorageKey,
+			SHA256:     document.SHA256,
+			Signed:     document.SignedVersion == document.CurrentVersion(),
+		},
+		Versions: make([]dtos.DocumentVersionResponseDTO, 0, len(versions)),
+	}
+
+	for _, version := range versions {
+		replacedAt := version.ReplacedAt
+		response.Versions = append(response.Versions, dtos.DocumentVersionResponseDTO{
+			Version:    version.Version,
+			FileSize:   version.FileSize,
+			MimeType:   version.MimeType,
+			StorageKey: version.StorageKey,
+			SHA256:     version.SHA256,
+			Signed:     document.SignedVersion == version.Version,
+			ReplacedAt: &replacedAt,
+		})
 	}
+
+	return response
 }
 
 func (h DocumentHandlers) extractValidationErrors(err error) []dtos.ValidationErrorDetail {
@@ -391,25 +803,39 @@ func (h DocumentHandlers) getValidationErrorMessage(fieldError validator.FieldEr
 	}
 }
 
-func MountDocumentHandlers(gin *gin.Engine, conn *gorm.DB, logger *logrus.Logger) {
+func MountDocumentHandlers(gin *gin.Engine, conn *gorm.DB, deps *dependencies.Dependencies) {
+	logger := deps.Logger
 	// Inicializar cliente Clicksign
 	clicksignClient := clicksign.NewClicksignClient(config.EnvironmentVariables, logger)
-
-	documentHandlers := NewDocumentHandler(
-		usecase_document.NewUsecaseDocumentServiceWithClicksign(
-			repository.NewRepositoryDocument(conn),
-			clicksignClient,
+	documentIngestion := newDocumentIngestion(deps)
+
+	documentHandlers := newTenantHandlers(conn, func(conn *gorm.DB) *DocumentHandlers {
+		return NewDocumentHandler(
+			usecase_document.NewUsecaseDocumentServiceWithClicksign(
+				repository.NewRepositoryDocument(conn),
+				clicksignClient,
+				deps.Storage,
+				deps.Fetcher,
+				logger,
+			),
+			documentIngestion,
 			logger,
-		),
-		logger,
-	)
+		)
+	})
 
 	group := gin.Group("/api/v1/documents")
-	SetAuthMiddleware(conn, group)
+	SetScopedAuthMiddleware(conn, deps, group, entity.ScopeDocumentsRead, entity.ScopeDocumentsWrite)
+
+	group.POST("/", documentHandlers.Handle((*DocumentHandlers).CreateDocumentHandler))
+	group.GET("/:id", documentHandlers.Handle((*DocumentHandlers).GetDocumentHandler))
+	group.GET("/:id/versions", documentHandlers.Handle((*DocumentHandlers).GetDocumentVersionsHandler))
+	group.GET("/", documentHandlers.Handle((*DocumentHandlers).GetDocumentsHandler))
+	group.PUT("/:id", documentHandlers.Handle((*DocumentHandlers).UpdateDocumentHandler))
+	group.DELETE("/:id", documentHandlers.Handle((*DocumentHandlers).DeleteDocumentHandler))
+
+	groupV2 := gin.Group("/api/v2/documents")
+	// A verificação só consulta hashes, então basta o escopo de leitura
+	SetScopedAuthMiddleware(conn, deps, groupV2, entity.ScopeDocumentsRead, entity.ScopeDocumentsRead)
 
-	group.POST("/", documentHandlers.CreateDocumentHandler)
-	group.GET("/:id", documentHandlers.GetDocumentHandler)
-	group.GET("/", documentHandlers.GetDocumentsHandler)
-	group.PUT("/:id", documentHandlers.UpdateDocumentHandler)
-	group.DELETE("/:id", documentHandlers.DeleteDocumentHandler)
+	groupV2.POST("/verify", documentHandlers.Handle((*DocumentHandlers).VerifyDocumentHandler))
 }
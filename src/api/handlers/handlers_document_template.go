@@ -0,0 +1,400 @@
+package handlers
+
+import (
+	"app/api/handlers/dtos"
+	"app/entity"
+	"app/infrastructure/dependencies"
+	"app/infrastructure/repository"
+	usecase_document_template "app/usecase/document_template"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type DocumentTemplateHandlers struct {
+	UsecaseDocumentTemplate usecase_document_template.IUsecaseDocumentTemplate
+	Logger                  *logrus.Logger
+}
+
+func NewDocumentTemplateHandler(usecaseDocumentTemplate usecase_document_template.IUsecaseDocumentTemplate, logger *logrus.Logger) *DocumentTemplateHandlers {
+	return &DocumentTemplateHandlers{
+		UsecaseDocumentTemplate: usecaseDocumentTemplate,
+		Logger:                  logger,
+	}
+}
+
+// @Summary Criar template de documento
+// @Description Cria um template HTML ou Markdown com placeholders {{ variavel }}
+// @Description O template pode ser referenciado em documents[].template_id na criação de envelopes v2
+// @Tags Document Templates
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param template body dtos.DocumentTemplateCreateRequestDTO true "Dados do template"
+// @Success 201 {object} dtos.DocumentTemplateResponseDTO "Template criado com sucesso"
+// @Failure 400 {object} dtos.ValidationErrorResponseDTO "Dados inválidos"
+// @Failure 401 {object} dtos.ErrorResponseDTO "Não autorizado"
+// @Failure 500 {object} dtos.ErrorResponseDTO "Erro interno"
+// @Router /api/v2/document-templates [post]
+func (h DocumentTemplateHandlers) CreateDocumentTemplateHandler(c *gin.Context) {
+	var requestDTO dtos.DocumentTemplateCreateRequestDTO
+	if err := c.ShouldBindJSON(&requestDTO); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ValidationErrorResponseDTO{
+			Error:   "Validation failed",
+			Message: "Invalid request payload",
+			Details: h.extractValidationErrors(err),
+		})
+		return
+	}
+
+	template, err := entity.NewDocumentTemplate(entity.EntityDocumentTemplate{
+		Name:        requestDTO.Name,
+		Description: requestDTO.Description,
+		Format:      requestDTO.Format,
+		Content:     requestDTO.Content,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ValidationErrorResponseDTO{
+			Error:   "Validation failed",
+			Message: "Invalid template",
+			Details: h.extractValidationErrors(err),
+		})
+		return
+	}
+
+	template, err = h.UsecaseDocumentTemplate.CreateTemplate(template)
+	if err != nil {
+		h.Logger.WithError(err).Error("Failed to create document template")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+			Error:   "Internal server error",
+			Message: "Failed to create document template",
+		})
+		return
+	}
+
+	jsonResponse(c, http.StatusCreated, h.mapEntityToResponse(template))
+}
+
+// @Summary Buscar template de documento
+// @Description Retorna um template e a lista de variáveis que ele exige
+// @Tags Document Templates
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID do template"
+// @Success 200 {object} dtos.DocumentTemplateResponseDTO "Template encontrado"
+// @Failure 400 {object} dtos.ErrorResponseDTO "ID inválido"
+// @Failure 404 {object} dtos.ErrorResponseDTO "Template não encontrado"
+// @Router /api/v2/document-templates/{id} [get]
+func (h DocumentTemplateHandlers) GetDocumentTemplateHandler(c *gin.Context) {
+	template, ok := h.loadTemplate(c)
+	if !ok {
+		return
+	}
+
+	jsonResponse(c, http.StatusOK, h.mapEntityToResponse(template))
+}
+
+// @Summary Listar templates de documento
+// @Description Retorna todos os templates ordenados por nome
+// @Tags Document Templates
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} dtos.DocumentTemplateListResponseDTO "Lista de templates"
+// @Failure 500 {object} dtos.ErrorResponseDTO "Erro interno"
+// @Router /api/v2/document-templates [get]
+func (h DocumentTemplateHandlers) GetDocumentTemplatesHandler(c *gin.Context) {
+	templates, err := h.UsecaseDocumentTemplate.GetTemplates()
+	if err != nil {
+		h.Logger.WithError(err).Error("Failed to list document templates")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+			Error:   "Internal server error",
+			Message: "Failed to retrieve document templates",
+		})
+		return
+	}
+
+	responseDTOs := make([]dtos.DocumentTemplateResponseDTO, 0, len(templates))
+	for i := range templates {
+		responseDTOs = append(responseDTOs, h.mapEntityToResponse(&templates[i]))
+	}
+
+	jsonResponse(c, http.StatusOK, dtos.DocumentTemplateListResponseDTO{
+		Templates: responseDTOs,
+		Total:     len(responseDTOs),
+	})
+}
+
+// @Summary Atualizar template de documento
+// @Description Atualiza nome, descrição, formato ou conteúdo de um template
+// @Description Envelopes já criados não são afetados: o PDF gerado fica no storage
+// @Tags Document Templates
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID do template"
+// @Param template body dtos.DocumentTemplateUpdateRequestDTO true "Dados para atualização"
+// @Success 200 {object} dtos.DocumentTemplateResponseDTO "Template atualizado"
+// @Failure 400 {object} dtos.ValidationErrorResponseDTO "Dados inválidos"
+// @Failure 404 {object} dtos.ErrorResponseDTO "Template não encontrado"
+// @Failure 500 {object} dtos.ErrorResponseDTO "Erro interno"
+// @Router /api/v2/document-templates/{id} [put]
+func (h DocumentTemplateHandlers) UpdateDocumentTemplateHandler(c *gin.Context) {
+	var requestDTO dtos.DocumentTemplateUpdateRequestDTO
+	if err := c.ShouldBindJSON(&requestDTO); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ValidationErrorResponseDTO{
+			Error:   "Validation failed",
+			Message: "Invalid request payload",
+			Details: h.extractValidationErrors(err),
+		})
+		return
+	}
+
+	template, ok := h.loadTemplate(c)
+	if !ok {
+		return
+	}
+
+	if requestDTO.Name != nil {
+		template.Name = *requestDTO.Name
+	}
+	if requestDTO.Description != nil {
+		template.Description = *requestDTO.Description
+	}
+	if requestDTO.Format != nil {
+		template.Format = *requestDTO.Format
+	}
+	if requestDTO.Content != nil {
+		template.Content = *requestDTO.Content
+	}
+
+	if err := h.UsecaseDocumentTemplate.UpdateTemplate(template); err != nil {
+		var validationErr validator.ValidationErrors
+		if errors.As(err, &validationErr) {
+			c.JSON(http.StatusBadRequest, dtos.ValidationErrorResponseDTO{
+				Error:   "Validation failed",
+				Message: "Invalid template",
+				Details: h.extractValidationErrors(validationErr),
+			})
+			return
+		}
+
+		h.Logger.WithError(err).Error("Failed to update document template")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+			Error:   "Internal server error",
+			Message: "Failed to update document template",
+		})
+		return
+	}
+
+	jsonResponse(c, http.StatusOK, h.mapEntityToResponse(template))
+}
+
+// @Summary Deletar template de documento
+// @Description Remove um template; documentos já gerados a partir dele são mantidos
+// @Tags Document Templates
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID do template"
+// @Success 204 "Template deletado com sucesso"
+// @Failure 400 {object} dtos.ErrorResponseDTO "ID inválido"
+// @Failure 404 {object} dtos.ErrorResponseDTO "Template não encontrado"
+// @Failure 500 {object} dtos.ErrorResponseDTO "Erro interno"
+// @Router /api/v2/document-templates/{id} [delete]
+func (h DocumentTemplateHandlers) DeleteDocumentTemplateHandler(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.UsecaseDocumentTemplate.DeleteTemplate(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			h.respondNotFound(c)
+			return
+		}
+
+		h.Logger.WithError(err).Error("Failed to delete document template")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+			Error:   "Internal server error",
+			Message: "Failed to delete document template",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Gerar PDF a partir do template
+// @Description Renderiza o template com as variáveis informadas e retorna o PDF, sem criar documento
+// @Description Útil para conferir o resultado antes de usar o template em um envelope
+// @Tags Document Templates
+// @Accept json
+// @Produce application/pdf
+// @Security ApiKeyAuth
+// @Param id path int true "ID do template"
+// @Param variables body dtos.DocumentTemplateRenderRequestDTO true "Variáveis do template"
+// @Success 200 {file} binary "PDF gerado"
+// @Failure 400 {object} dtos.ErrorResponseDTO "Variáveis faltando ou request inválido"
+// @Failure 404 {object} dtos.ErrorResponseDTO "Template não encontrado"
+// @Failure 422 {object} dtos.ErrorResponseDTO "Falha na geração do PDF"
+// @Router /api/v2/document-templates/{id}/render [post]
+func (h DocumentTemplateHandlers) RenderDocumentTemplateHandler(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	var requestDTO dtos.DocumentTemplateRenderRequestDTO
+	if err := c.ShouldBindJSON(&requestDTO); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	data, template, err := h.UsecaseDocumentTemplate.GeneratePDF(c.Request.Context(), id, requestDTO.Variables)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			h.respondNotFound(c)
+		case errors.Is(err, entity.ErrMissingTemplateVariables):
+			c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+				Error:   "Missing variables",
+				Message: err.Error(),
+			})
+		case template == nil:
+			h.Logger.WithError(err).Error("Failed to load document template")
+			c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+				Error:   "Internal server error",
+				Message: "Failed to load document template",
+			})
+		default:
+			h.Logger.WithError(err).WithField("template_id", id).Warn("Failed to generate PDF from document template")
+			c.JSON(http.StatusUnprocessableEntity, dtos.ErrorResponseDTO{
+				Error:   "Template rendering failed",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", template.Name+".pdf"))
+	c.Data(http.StatusOK, "application/pdf", data)
+}
+
+func (h DocumentTemplateHandlers) parseID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Invalid ID",
+			Message: "Template ID must be a valid integer",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// loadTemplate busca o template do parâmetro :id e já responde 400/404/500 quando não o encontra
+func (h DocumentTemplateHandlers) loadTemplate(c *gin.Context) (*entity.EntityDocumentTemplate, bool) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return nil, false
+	}
+
+	template, err := h.UsecaseDocumentTemplate.GetTemplate(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			h.respondNotFound(c)
+			return nil, false
+		}
+
+		h.Logger.WithError(err).Error("Failed to get document template")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+			Error:   "Internal server error",
+			Message: "Failed to get document template",
+		})
+		return nil, false
+	}
+
+	return template, true
+}
+
+func (h DocumentTemplateHandlers) respondNotFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, dtos.ErrorResponseDTO{
+		Error:   "Template not found",
+		Message: "The requested document template does not exist",
+	})
+}
+
+func (h DocumentTemplateHandlers) mapEntityToResponse(template *entity.EntityDocumentTemplate) dtos.DocumentTemplateResponseDTO {
+	placeholders := template.Placeholders()
+	if placeholders == nil {
+		placeholders = []string{}
+	}
+
+	return dtos.DocumentTemplateResponseDTO{
+		ID:           template.ID,
+		Name:         template.Name,
+		Description:  template.Description,
+		Format:       template.Format,
+		Content:      template.Content,
+		Placeholders: placeholders,
+		CreatedAt:    template.CreatedAt,
+		UpdatedAt:    template.UpdatedAt,
+	}
+}
+
+func (h DocumentTemplateHandlers) extractValidationErrors(err error) []dtos.ValidationErrorDetail {
+	var validationErrors []dtos.ValidationErrorDetail
+
+	if validationErr, ok := err.(validator.ValidationErrors); ok {
+		for _, fieldError := range validationErr {
+			validationErrors = append(validationErrors, dtos.ValidationErrorDetail{
+				Field:   fieldError.Field(),
+				Message: "This field is invalid (" + fieldError.Tag() + ")",
+				Value:   fmt.Sprintf("%v", fieldError.Value()),
+			})
+		}
+	} else {
+		validationErrors = append(validationErrors, dtos.ValidationErrorDetail{
+			Field:   "general",
+			Message: err.Error(),
+		})
+	}
+
+	return validationErrors
+}
+
+func MountDocumentTemplateHandlers(gin *gin.Engine, conn *gorm.DB, deps *dependencies.Dependencies) {
+	logger := deps.Logger
+	// Templates pertencem ao tenant: cada tenant vê e usa apenas os seus
+	documentTemplateHandlers := newTenantHandlers(conn, func(conn *gorm.DB) *DocumentTemplateHandlers {
+		return NewDocumentTemplateHandler(
+			usecase_document_template.NewUsecaseDocumentTemplateService(
+				repository.NewRepositoryDocumentTemplate(conn),
+				deps.Converter,
+				logger,
+			),
+			logger,
+		)
+	})
+
+	group := gin.Group("/api/v2/document-templates")
+	SetScopedAuthMiddleware(conn, deps, group, entity.ScopeTemplatesRead, entity.ScopeTemplatesWrite)
+
+	group.POST("/", documentTemplateHandlers.Handle((*DocumentTemplateHandlers).CreateDocumentTemplateHandler))
+	group.GET("/", documentTemplateHandlers.Handle((*DocumentTemplateHandlers).GetDocumentTemplatesHandler))
+	group.GET("/:id", documentTemplateHandlers.Handle((*DocumentTemplateHandlers).GetDocumentTemplateHandler))
+	group.PUT("/:id", documentTemplateHandlers.Handle((*DocumentTemplateHandlers).UpdateDocumentTemplateHandler))
+	group.DELETE("/:id", documentTemplateHandlers.Handle((*DocumentTemplateHandlers).DeleteDocumentTemplateHandler))
+	group.POST("/:id/render", documentTemplateHandlers.Handle((*DocumentTemplateHandlers).RenderDocumentTemplateHandler))
+}
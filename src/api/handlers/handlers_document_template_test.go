@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"app/api/handlers/dtos"
+	"app/entity"
+	"app/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func performDocumentTemplateRequest(handler *DocumentTemplateHandlers, method, path string, body interface{}) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v2/document-templates", handler.CreateDocumentTemplateHandler)
+	router.GET("/api/v2/document-templates/:id", handler.GetDocumentTemplateHandler)
+	router.POST("/api/v2/document-templates/:id/render", handler.RenderDocumentTemplateHandler)
+
+	jsonData, _ := json.Marshal(body)
+	req, _ := http.NewRequest(method, path, bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestDocumentTemplateHandlers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockIUsecaseDocumentTemplate(ctrl)
+	handler := NewDocumentTemplateHandler(mockUsecase, logrus.New())
+
+	t.Run("should create template and list its placeholders", func(t *testing.T) {
+		mockUsecase.EXPECT().CreateTemplate(gomock.Any()).DoAndReturn(func(template *entity.EntityDocumentTemplate) (*entity.EntityDocumentTemplate, error) {
+			template.ID = 7
+			return template, nil
+		})
+
+		w := performDocumentTemplateRequest(handler, http.MethodPost, "/api/v2/document-templates", map[string]string{
+			"name":    "Contrato de prestação",
+			"format":  "markdown",
+			"content": "# Contrato\n\n{{ contratante }} pagará {{ valor }}",
+		})
+
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var response dtos.DocumentTemplateResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 7, response.ID)
+		assert.Equal(t, []string{"contratante", "valor"}, response.Placeholders)
+	})
+
+	t.Run("should reject unsupported formats", func(t *testing.T) {
+		w := performDocumentTemplateRequest(handler, http.MethodPost, "/api/v2/document-templates", map[string]string{
+			"name":    "Contrato",
+			"format":  "docx",
+			"content": "x",
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should return 404 for unknown templates", func(t *testing.T) {
+		mockUsecase.EXPECT().GetTemplate(99).Return(nil, gorm.ErrRecordNotFound)
+
+		w := performDocumentTemplateRequest(handler, http.MethodGet, "/api/v2/document-templates/99", nil)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("should render the PDF", func(t *testing.T) {
+		variables := map[string]string{"contratante": "Ana"}
+		mockUsecase.EXPECT().GeneratePDF(gomock.Any(), 7, variables).
+			Return([]byte("%PDF-1.4\n"), &entity.EntityDocumentTemplate{ID: 7, Name: "Contrato"}, nil)
+
+		w := performDocumentTemplateRequest(handler, http.MethodPost, "/api/v2/document-templates/7/render", map[string]interface{}{"variables": variables})
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+		assert.Equal(t, "%PDF-1.4\n", w.Body.String())
+	})
+
+	t.Run("should report missing variables as bad request", func(t *testing.T) {
+		mockUsecase.EXPECT().GeneratePDF(gomock.Any(), 7, gomock.Any()).
+			Return(nil, &entity.EntityDocumentTemplate{ID: 7}, fmt.Errorf("%w: valor", entity.ErrMissingTemplateVariables))
+
+		w := performDocumentTemplateRequest(handler, http.MethodPost, "/api/v2/document-templates/7/render", map[string]interface{}{"variables": map[string]string{}})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "valor")
+	})
+
+	t.Run("should report generation failures as unprocessable", func(t *testing.T) {
+		mockUsecase.EXPECT().GeneratePDF(gomock.Any(), 8, gomock.Any()).
+			Return(nil, &entity.EntityDocumentTemplate{ID: 8}, errors.New("document conversion is disabled"))
+
+		w := performDocumentTemplateRequest(handler, http.MethodPost, "/api/v2/document-templates/8/render", map[string]interface{}{"variables": map[string]string{}})
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+}
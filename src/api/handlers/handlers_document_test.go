@@ -0,0 +1,519 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"app/api/handlers/dtos"
+	"app/config"
+	"app/entity"
+	"app/infrastructure/converter"
+	"app/infrastructure/normalizer"
+	"app/infrastructure/scanner"
+	"app/infrastructure/storage"
+	"app/mocks"
+	"app/pkg/utils"
+	usecase_document "app/usecase/document"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubConverter devolve um PDF fixo ou o erro configurado
+type stubConverter struct {
+	pdf []byte
+	err error
+}
+
+func (s stubConverter) ConvertToPDF(ctx context.Context, data []byte, mimeType string) ([]byte, error) {
+	return s.pdf, s.err
+}
+
+func (s stubConverter) Name() string {
+	return "stub"
+}
+
+// stubScanner acusa como infectado o conteúdo que contém marker
+type stubScanner struct {
+	marker string
+	err    error
+}
+
+func (s stubScanner) Scan(ctx context.Context, r io.Reader) (*scanner.Result, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	if bytes.Contains(data, []byte(s.marker)) {
+		return &scanner.Result{Infected: true, Threat: "Eicar-Test-Signature"}, nil
+	}
+	return &scanner.Result{}, nil
+}
+
+func (s stubScanner) Name() string {
+	return "stub"
+}
+
+// newTestIngestion cria o serviço de ingestão sobre um storage local temporário, com a validação básica de PDFs
+// Sem conversor a conversão fica desabilitada e, sem scanner, a verificação de malware
+func newTestIngestion(t *testing.T, documentConverter converter.Converter, documentScanner scanner.Scanner) (*usecase_document.UsecaseDocumentIngestionService, storage.Storage) {
+	documentStorage, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	if documentConverter == nil {
+		documentConverter = converter.DisabledConverter{}
+	}
+	if documentScanner == nil {
+		documentScanner = scanner.DisabledScanner{}
+	}
+	return usecase_document.NewUsecaseDocumentIngestionService(
+		documentStorage,
+		documentConverter,
+		normalizer.NewNormalizer(normalizer.BasicTool{}, normalizer.Options{}),
+		documentScanner,
+	), documentStorage
+}
+
+// samplePDF monta um PDF com a estrutura mínima aceita pela validação (cabeçalho, startxref e %%EOF)
+func samplePDF(padding int, trailer string) []byte {
+	return []byte("%PDF-1.4\n%" + strings.Repeat("0", padding) + "\ntrailer\n<< /Size 1" + trailer + " >>\nstartxref\n0\n%%EOF\n")
+}
+
+func buildDocx(t *testing.T) []byte {
+	buffer := &bytes.Buffer{}
+	writer := zip.NewWriter(buffer)
+	for _, name := range []string{"[Content_Types].xml", "word/document.xml"} {
+		file, err := writer.Create(name)
+		require.NoError(t, err)
+		_, err = file.Write([]byte("<xml/>"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return buffer.Bytes()
+}
+
+func performMultipartUpload(t *testing.T, handler *DocumentHandlers, fields map[string]string, fileContent []byte) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v1/documents", handler.CreateDocumentHandler)
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for name, value := range fields {
+		require.NoError(t, writer.WriteField(name, value))
+	}
+	if fileContent != nil {
+		part, err := writer.CreateFormFile("file", "contrato.pdf")
+		require.NoError(t, err)
+		_, err = part.Write(fileContent)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/documents", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCreateDocumentHandler_Multipart(t *testing.T) {
+	pdf := samplePDF(4096, "")
+
+	t.Run("should stream file to storage and create document", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		ingestion, documentStorage := newTestIngestion(t, nil, nil)
+
+		mockUsecaseDocument := mocks.NewMockIUsecaseDocument(ctrl)
+		mockUsecaseDocument.EXPECT().Create(gomock.Any()).DoAndReturn(func(document *entity.EntityDocument) error {
+			document.ID = 15
+			return nil
+		})
+		handler := NewDocumentHandler(mockUsecaseDocument, ingestion, logrus.New())
+
+		w := performMultipartUpload(t, handler, map[string]string{"name": "Contrato grande", "description": "Upload multipart"}, pdf)
+
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var response dtos.DocumentResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 15, response.ID)
+		assert.Equal(t, "application/pdf", response.MimeType)
+		assert.Equal(t, int64(len(pdf)), response.FileSize)
+		assert.True(t, strings.HasPrefix(response.StorageKey, "originals/sha256/"))
+		assert.Equal(t, storage.URI(response.StorageKey), response.FilePath)
+		assert.Equal(t, storage.ContentKey(storage.KindOriginal, response.SHA256), response.StorageKey)
+
+		stored, err := storage.ReadAll(t.Context(), documentStorage, response.StorageKey)
+		require.NoError(t, err)
+		assert.Equal(t, pdf, stored)
+	})
+
+	t.Run("should reject unsupported file type", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		ingestion, _ := newTestIngestion(t, nil, nil)
+
+		handler := NewDocumentHandler(mocks.NewMockIUsecaseDocument(ctrl), ingestion, logrus.New())
+
+		w := performMultipartUpload(t, handler, map[string]string{"name": "Planilha"}, []byte("nome,valor\nteste,1\n"))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "unsupported file type")
+	})
+
+	t.Run("should reject encrypted PDF", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		ingestion, _ := newTestIngestion(t, nil, nil)
+
+		handler := NewDocumentHandler(mocks.NewMockIUsecaseDocument(ctrl), ingestion, logrus.New())
+
+		w := performMultipartUpload(t, handler, map[string]string{"name": "Contrato protegido"}, samplePDF(64, " /Encrypt 5 0 R"))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), "encrypted")
+	})
+
+	t.Run("should reject truncated PDF", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		ingestion, _ := newTestIngestion(t, nil, nil)
+
+		handler := NewDocumentHandler(mocks.NewMockIUsecaseDocument(ctrl), ingestion, logrus.New())
+		truncated := samplePDF(4096, "")[:2048]
+
+		w := performMultipartUpload(t, handler, map[string]string{"name": "Contrato truncado"}, truncated)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), "Document rejected")
+	})
+
+	t.Run("should reject file above the configured limit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		ingestion, _ := newTestIngestion(t, nil, nil)
+
+		previous := config.EnvironmentVariables.DOCUMENT_UPLOAD_MAX_SIZE_MB
+		config.EnvironmentVariables.DOCUMENT_UPLOAD_MAX_SIZE_MB = 1
+		defer func() { config.EnvironmentVariables.DOCUMENT_UPLOAD_MAX_SIZE_MB = previous }()
+
+		handler := NewDocumentHandler(mocks.NewMockIUsecaseDocument(ctrl), ingestion, logrus.New())
+		large := append([]byte("%PDF-1.4\n"), bytes.Repeat([]byte("0"), 1024*1024)...)
+
+		w := performMultipartUpload(t, handler, map[string]string{"name": "Contrato enorme"}, large)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+
+	t.Run("should require file", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		ingestion, _ := newTestIngestion(t, nil, nil)
+
+		handler := NewDocumentHandler(mocks.NewMockIUsecaseDocument(ctrl), ingestion, logrus.New())
+
+		w := performMultipartUpload(t, handler, map[string]string{"name": "Sem arquivo"}, nil)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "file is required")
+	})
+}
+
+func TestCreateDocumentHandler_MultipartScan(t *testing.T) {
+	t.Run("should record a clean scan", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		ingestion, _ := newTestIngestion(t, nil, stubScanner{marker: "EICAR"})
+
+		mockUsecaseDocument := mocks.NewMockIUsecaseDocument(ctrl)
+		mockUsecaseDocument.EXPECT().Create(gomock.Any()).Return(nil)
+		handler := NewDocumentHandler(mockUsecaseDocument, ingestion, logrus.New())
+
+		w := performMultipartUpload(t, handler, map[string]string{"name": "Contrato limpo"}, samplePDF(128, ""))
+
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var response dtos.DocumentResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "draft", response.Status)
+		scan := response.Metadata["scan"].(map[string]interface{})
+		assert.Equal(t, "stub", scan["scanner"])
+		assert.Equal(t, false, scan["infected"])
+	})
+
+	t.Run("should quarantine infected uploads", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		ingestion, _ := newTestIngestion(t, nil, stubScanner{marker: "EICAR"})
+
+		mockUsecaseDocument := mocks.NewMockIUsecaseDocument(ctrl)
+		mockUsecaseDocument.EXPECT().Create(gomock.Any()).DoAndReturn(func(document *entity.EntityDocument) error {
+			assert.Equal(t, entity.DocumentStatusQuarantined, document.Status)
+			assert.NotEmpty(t, document.StorageKey)
+			document.ID = 21
+			return nil
+		})
+		handler := NewDocumentHandler(mockUsecaseDocument, ingestion, logrus.New())
+
+		infected := append(samplePDF(128, ""), []byte("EICAR")...)
+		w := performMultipartUpload(t, handler, map[string]string{"name": "Contrato infectado"}, infected)
+
+		require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+		var response dtos.ErrorResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Document infected", response.Error)
+		assert.Contains(t, response.Message, "Eicar-Test-Signature")
+		assert.Equal(t, float64(21), response.Details["document_id"])
+		assert.Equal(t, entity.DocumentStatusQuarantined, response.Details["status"])
+	})
+
+	t.Run("should answer 503 when the scanner fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		ingestion, _ := newTestIngestion(t, nil, stubScanner{err: errors.New("failed to connect to clamd")})
+
+		handler := NewDocumentHandler(mocks.NewMockIUsecaseDocument(ctrl), ingestion, logrus.New())
+
+		w := performMultipartUpload(t, handler, map[string]string{"name": "Contrato"}, samplePDF(128, ""))
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code, w.Body.String())
+	})
+
+	t.Run("should answer 503 without a configured scanner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		documentStorage, err := storage.NewLocalStorage(t.TempDir())
+		require.NoError(t, err)
+		ingestion := usecase_document.NewUsecaseDocumentIngestionService(
+			documentStorage,
+			converter.DisabledConverter{},
+			normalizer.NewNormalizer(normalizer.BasicTool{}, normalizer.Options{}),
+			nil,
+		)
+
+		handler := NewDocumentHandler(mocks.NewMockIUsecaseDocument(ctrl), ingestion, logrus.New())
+
+		w := performMultipartUpload(t, handler, map[string]string{"name": "Contrato"}, samplePDF(128, ""))
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code, w.Body.String())
+	})
+}
+
+func TestCreateDocumentHandler_MultipartConversion(t *testing.T) {
+	docx := buildDocx(t)
+	pdf := []byte("%PDF-1.4\nconvertido\n")
+
+	t.Run("should convert DOCX to PDF and keep the original", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		ingestion, documentStorage := newTestIngestion(t, stubConverter{pdf: pdf}, nil)
+
+		mockUsecaseDocument := mocks.NewMockIUsecaseDocument(ctrl)
+		mockUsecaseDocument.EXPECT().Create(gomock.Any()).DoAndReturn(func(document *entity.EntityDocument) error {
+			document.ID = 16
+			return nil
+		})
+		handler := NewDocumentHandler(mockUsecaseDocument, ingestion, logrus.New())
+
+		w := performMultipartUpload(t, handler, map[string]string{"name": "Contrato Word"}, docx)
+
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var response dtos.DocumentResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "application/pdf", response.MimeType)
+		assert.Equal(t, int64(len(pdf)), response.FileSize)
+
+		converted, err := storage.ReadAll(t.Context(), documentStorage, response.StorageKey)
+		require.NoError(t, err)
+		assert.Equal(t, pdf, converted)
+
+		var metadata struct {
+			Metadata struct {
+				Conversion entity.DocumentConversion `json:"conversion"`
+			} `json:"metadata"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &metadata))
+		assert.Equal(t, utils.MimeTypeDOCX, metadata.Metadata.Conversion.SourceMimeType)
+		assert.Equal(t, "stub", metadata.Metadata.Conversion.Converter)
+		assert.Equal(t, int64(len(docx)), metadata.Metadata.Conversion.SourceSize)
+
+		original, err := storage.ReadAll(t.Context(), documentStorage, metadata.Metadata.Conversion.SourceStorageKey)
+		require.NoError(t, err)
+		assert.Equal(t, docx, original)
+	})
+
+	t.Run("should answer 422 when conversion fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		ingestion, _ := newTestIngestion(t, converter.DisabledConverter{}, nil)
+
+		handler := NewDocumentHandler(mocks.NewMockIUsecaseDocument(ctrl), ingestion, logrus.New())
+
+		w := performMultipartUpload(t, handler, map[string]string{"name": "Contrato Word"}, docx)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), "document conversion is disabled")
+	})
+
+	t.Run("should reject arbitrary ZIP files", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		ingestion, _ := newTestIngestion(t, stubConverter{pdf: pdf}, nil)
+
+		handler := NewDocumentHandler(mocks.NewMockIUsecaseDocument(ctrl), ingestion, logrus.New())
+		plainZip := &bytes.Buffer{}
+		writer := zip.NewWriter(plainZip)
+		_, err := writer.Create("dados.csv")
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		w := performMultipartUpload(t, handler, map[string]string{"name": "Arquivo zip"}, plainZip.Bytes())
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "unsupported file type")
+	})
+}
+
+func TestEnvelopeV2Handler_LoadUploadedDocuments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := newFailoverTestHandler(ctrl, mocks.NewMockIRepositoryEnvelope(ctrl))
+	mockUsecaseDocument := handler.UsecaseDocuments.(*mocks.MockIUsecaseDocument)
+
+	t.Run("should load draft documents", func(t *testing.T) {
+		mockUsecaseDocument.EXPECT().GetDocument(3).Return(&entity.EntityDocument{ID: 3, Status: "draft", StorageKey: "originals/sha256/abc"}, nil)
+
+		documents, err := handler.loadUploadedDocuments([]int{3})
+
+		require.NoError(t, err)
+		require.Len(t, documents, 1)
+		assert.Equal(t, "originals/sha256/abc", documents[0].StorageKey)
+	})
+
+	t.Run("should reject documents already sent to a provider", func(t *testing.T) {
+		mockUsecaseDocument.EXPECT().GetDocument(4).Return(&entity.EntityDocument{ID: 4, Status: "draft", ClicksignKey: "doc-key"}, nil)
+
+		_, err := handler.loadUploadedDocuments([]int{4})
+
+		assert.ErrorContains(t, err, "already sent")
+	})
+
+	t.Run("should reject unknown documents", func(t *testing.T) {
+		mockUsecaseDocument.EXPECT().GetDocument(5).Return(nil, errors.New("record not found"))
+
+		_, err := handler.loadUploadedDocuments([]int{5})
+
+		assert.ErrorContains(t, err, "document 5 not found")
+	})
+}
+
+func performVerifyUpload(t *testing.T, handler *DocumentHandlers, fileContent []byte) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v2/documents/verify", handler.VerifyDocumentHandler)
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", "contrato-assinado.pdf")
+	require.NoError(t, err)
+	_, err = part.Write(fileContent)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/v2/documents/verify", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestVerifyDocumentHandler(t *testing.T) {
+	// SHA-256 de "test"
+	sum := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+
+	t.Run("should report original and signed matches", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUsecaseDocument := mocks.NewMockIUsecaseDocument(ctrl)
+		mockUsecaseDocument.EXPECT().FindByContentHash(sum).Return([]entity.EntityDocument{
+			{ID: 1, Name: "Contrato", Status: "sent", SHA256: sum, IntegrityStatus: entity.IntegrityVerified},
+			{ID: 2, Name: "Aditivo", Status: "sent", SHA256: "outro", SignedSHA256: sum},
+		}, nil)
+		handler := NewDocumentHandler(mockUsecaseDocument, nil, logrus.New())
+
+		w := performVerifyUpload(t, handler, []byte("test"))
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response dtos.DocumentVerifyResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.True(t, response.Matched)
+		assert.Equal(t, sum, response.SHA256)
+		assert.Equal(t, int64(4), response.Size)
+		require.Len(t, response.Matches, 2)
+		assert.Equal(t, "original", response.Matches[0].MatchedCopy)
+		assert.Equal(t, entity.IntegrityVerified, response.Matches[0].IntegrityStatus)
+		assert.Equal(t, "signed", response.Matches[1].MatchedCopy)
+	})
+
+	t.Run("should report no match for unknown content", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUsecaseDocument := mocks.NewMockIUsecaseDocument(ctrl)
+		mockUsecaseDocument.EXPECT().FindByContentHash(sum).Return(nil, nil)
+		handler := NewDocumentHandler(mockUsecaseDocument, nil, logrus.New())
+
+		w := performVerifyUpload(t, handler, []byte("test"))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"sha256":"`+sum+`","size":4,"matched":false,"matches":[]}`, w.Body.String())
+	})
+}
+
+func TestGetDocumentVersionsHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecaseDocument := mocks.NewMockIUsecaseDocument(ctrl)
+	mockUsecaseDocument.EXPECT().GetDocument(5).Return(&entity.EntityDocument{ID: 5, Version: 2, SignedVersion: 2, SHA256: "v2"}, nil)
+	mockUsecaseDocument.EXPECT().GetVersions(5).Return([]entity.EntityDocumentVersion{
+		{DocumentID: 5, Version: 1, SHA256: "v1", ReplacedAt: time.Now()},
+	}, nil)
+	handler := NewDocumentHandler(mockUsecaseDocument, nil, logrus.New())
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/documents/:id/versions", handler.GetDocumentVersionsHandler)
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/documents/5/versions", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response dtos.DocumentVersionsResponseDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.CurrentVersion)
+	assert.Equal(t, 2, response.SignedVersion)
+	assert.True(t, response.Current.Signed)
+	assert.Nil(t, response.Current.ReplacedAt)
+	require.Len(t, response.Versions, 1)
+	assert.Equal(t, "v1", response.Versions[0].SHA256)
+	assert.False(t, response.Versions[0].Signed)
+	assert.NotNil(t, response.Versions[0].ReplacedAt)
+}
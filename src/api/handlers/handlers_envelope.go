@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,10 +12,13 @@ import (
 	"time"
 
 	"app/api/handlers/dtos"
+	"app/api/middleware"
 	"app/config"
 	"app/entity"
 	"app/infrastructure/clicksign"
+	"app/infrastructure/dependencies"
 	"app/infrastructure/repository"
+	"app/pkg/fetcher"
 	"app/pkg/utils"
 	"app/usecase/document"
 	usecase_envelope "app/usecase/envelope"
@@ -35,7 +39,13 @@ type EnvelopeHandlers struct {
 	UsecaseRequirement requirement.IUsecaseRequirement
 	UsecaseSignatory   signatory.IUsecaseSignatory
 	UsecaseWebhook     webhook.UsecaseWebhookInterface
-	Logger             *logrus.Logger
+	// UsecaseDocumentIngestion grava no storage os documentos enviados por URL ou base64
+	UsecaseDocumentIngestion *document.UsecaseDocumentIngestionService
+	// DocumentFetcher baixa os documentos informados por file_url, bloqueando a rede interna
+	DocumentFetcher *fetcher.Fetcher
+	// ApprovalRequired deixa os envelopes de usuários sem envelopes:activate aguardando aprovação antes da ativação
+	ApprovalRequired bool
+	Logger           *logrus.Logger
 }
 
 func NewEnvelopeHandler(usecaseEnvelope usecase_envelope.IUsecaseEnvelope, usecaseDocuments document.IUsecaseDocument, usecaseRequirement requirement.IUsecaseRequirement, usecaseSignatory signatory.IUsecaseSignatory, logger *logrus.Logger) *EnvelopeHandlers {
@@ -98,7 +108,7 @@ func (h *EnvelopeHandlers) CreateEnvelopeHandler(c *gin.Context) {
 	}
 
 	// Converter DTO para entidade
-	envelope, documents, err := h.mapCreateRequestToEntity(requestDTO)
+	envelope, documents, err := h.mapCreateRequestToEntity(c.Request.Context(), requestDTO)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
 			Error:   "Invalid request",
@@ -107,20 +117,7 @@ func (h *EnvelopeHandlers) CreateEnvelopeHandler(c *gin.Context) {
 		return
 	}
 
-	// Limpar arquivos temporários em caso de erro
-	var tempPaths []string
-	for _, doc := range documents {
-		if doc.IsFromBase64 && doc.FilePath != "" {
-			tempPaths = append(tempPaths, doc.FilePath)
-		}
-	}
-	defer func() {
-		for _, tempPath := range tempPaths {
-			if cleanupErr := utils.CleanupTempFile(tempPath); cleanupErr != nil {
-				h.Logger.Warn("Failed to cleanup temporary file")
-			}
-		}
-	}()
+	envelope.CreatedByID = middleware.UserIDFromContext(c)
 
 	// Criar envelope através do use case
 	var createdEnvelope *entity.EntityEnvelope
@@ -349,7 +346,20 @@ func (h *EnvelopeHandlers) CreateEnvelopeHandler(c *gin.Context) {
 		}
 	}
 
-	if requestDTO.Approved {
+	if requester, needsApproval := approvalRequester(h.ApprovalRequired, c); needsApproval {
+		// O envelope aguarda a decisão de um aprovador (POST /api/v2/envelopes/{id}/approve); Approved é ignorado
+		createdEnvelope.RequestApproval(requester, time.Now())
+		if err := h.UsecaseEnvelope.UpdateEnvelope(createdEnvelope); err != nil {
+			c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+				Error:   "Internal server error",
+				Message: fmt.Sprintf("Failed to request approval for envelope %d: %v", createdEnvelope.ID, err),
+				Details: map[string]interface{}{
+					"correlation_id": correlationID,
+				},
+			})
+			return
+		}
+	} else if requestDTO.Approved {
 		// Ativar envelope se aprovado
 		createdEnvelope, err = h.UsecaseEnvelope.ActivateEnvelope(createdEnvelope.ID)
 		if err != nil {
@@ -435,6 +445,8 @@ func (h *EnvelopeHandlers) GetEnvelopesHandler(c *gin.Context) {
 	filters.Search = c.Query("search")
 	filters.Status = c.Query("status")
 	filters.ClicksignKey = c.Query("clicksign_key")
+	// Usuários sem envelopes:read_all veem apenas os envelopes que criaram
+	filters.CreatedByID, _ = middleware.EnvelopeOwnerFromContext(c)
 
 	envelopes, err := h.UsecaseEnvelope.GetEnvelopes(filters)
 	if err != nil {
@@ -461,6 +473,7 @@ func (h *EnvelopeHandlers) GetEnvelopesHandler(c *gin.Context) {
 // @Failure 400 {object} dtos.ErrorResponseDTO
 // @Failure 404 {object} dtos.ErrorResponseDTO
 // @Failure 500 {object} dtos.ErrorResponseDTO
+// @Failure 403 {object} dtos.ErrorResponseDTO "Usuário sem a permissão envelopes:activate (papéis approver e admin)"
 // @Router /api/v1/envelopes/{id}/activate [post]
 func (h *EnvelopeHandlers) ActivateEnvelopeHandler(c *gin.Context) {
 	correlationID := c.GetHeader("X-Correlation-ID")
@@ -559,7 +572,7 @@ func (h *EnvelopeHandlers) NotifyEnvelopeHandler(c *gin.Context) {
 
 // Helper methods
 
-func (h *EnvelopeHandlers) mapCreateRequestToEntity(dto dtos.EnvelopeCreateRequestDTO) (*entity.EntityEnvelope, []*entity.EntityDocument, error) {
+func (h *EnvelopeHandlers) mapCreateRequestToEntity(ctx context.Context, dto dtos.EnvelopeCreateRequestDTO) (*entity.EntityEnvelope, []*entity.EntityDocument, error) {
 	// Determinar emails dos signatários com base no formato usado
 	var signatoryEmails []string
 	if len(dto.SignatoryEmails) > 0 {
@@ -601,7 +614,7 @@ func (h *EnvelopeHandlers) mapCreateRequestToEntity(dto dtos.EnvelopeCreateReque
 		// Verificar se é URL ou base64
 		if strings.TrimSpace(docRequest.FileURL) != "" {
 			// Processar URL
-			fileInfo, err = utils.DownloadFileFromURL(docRequest.FileURL)
+			fileInfo, err = utils.DownloadFileFromURL(h.DocumentFetcher, docRequest.FileURL)
 			if err != nil {
 				return nil, nil, fmt.Errorf("failed to download file from URL for document '%s': %w", docRequest.Name, err)
 			}
@@ -635,7 +648,7 @@ func (h *EnvelopeHandlers) mapCreateRequestToEntity(dto dtos.EnvelopeCreateReque
 		}
 
 		// Se veio de URL, manter a URL no FilePath para vertc-assinaturas
-		// Se veio de base64, usar o tempPath para Clicksign
+		// Se veio de base64, o FilePath passa a apontar para o original no storage
 		filePath := fileInfo.TempPath
 		if !isFromBase64 {
 			// Para URL, manter a URL original no FilePath (será usada diretamente pelo vertc-assinaturas)
@@ -653,6 +666,11 @@ func (h *EnvelopeHandlers) mapCreateRequestToEntity(dto dtos.EnvelopeCreateReque
 			Metadata:     metadataJSON, // Metadata customizado do backend
 		}
 
+		// Gravar o original no storage para permitir reenvio e auditoria
+		if err := h.UsecaseDocumentIngestion.StoreOriginal(ctx, document, fileInfo); err != nil {
+			return nil, nil, fmt.Errorf("failed to store document '%s': %w", docRequest.Name, err)
+		}
+
 		documents = append(documents, document)
 	}
 
@@ -673,6 +691,8 @@ func (h *EnvelopeHandlers) mapEntityToResponse(envelope *entity.EntityEnvelope,
 		DeadlineAt:       envelope.DeadlineAt,
 		RemindInterval:   envelope.RemindInterval,
 		AutoClose:        envelope.AutoClose,
+		CreatedByID:      envelope.CreatedByID,
+		ApprovalTrail:    newEnvelopeApprovalDTOs(envelope.ApprovalTrail),
 		CreatedAt:        envelope.CreatedAt,
 		UpdatedAt:        envelope.UpdatedAt,
 	}
@@ -848,75 +868,99 @@ func (h *EnvelopeHandlers) CheckSignatureEventsHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
-func MountEnvelopeHandlers(gin *gin.Engine, conn *gorm.DB, logger *logrus.Logger) {
+func MountEnvelopeHandlers(gin *gin.Engine, conn *gorm.DB, deps *dependencies.Dependencies) {
+	logger := deps.Logger
 	clicksignClient := clicksign.NewClicksignClient(config.EnvironmentVariables, logger)
-
-	// Criar usecase de documento para envelopes com documentos base64
-	usecaseDocument := document.NewUsecaseDocumentServiceWithClicksign(
-		repository.NewRepositoryDocument(conn),
-		clicksignClient,
-		logger,
-	)
-
-	// Criar usecase de signatory
-	usecaseSignatory := signatory.NewUsecaseSignatoryService(
-		repository.NewRepositorySignatory(conn),
-		repository.NewRepositoryEnvelope(conn),
-		clicksignClient,
-		logger,
-	)
-
-	// Criar usecase de requirement
-	usecaseRequirement := requirement.NewUsecaseRequirementService(
-		repository.NewRepositoryRequirement(conn),
-		repository.NewRepositoryEnvelope(conn),
-		clicksignClient,
-		logger,
-	)
-
-	envelopeUsecase := usecase_envelope.NewUsecaseEnvelopeService(
-		repository.NewRepositoryEnvelope(conn),
-		clicksignClient,
-		usecaseDocument,
-		usecaseRequirement,
-		logger,
-	)
-
-	// // Criar usecase de webhook
-	usecaseWebhook := webhook.NewUsecaseWebhookService(
-		repository.NewRepositoryWebhook(conn),
-		envelopeUsecase,
-		usecaseDocument,
-		logger,
-	)
-
-	envelopeHandlers := NewEnvelopeHandler(
-		envelopeUsecase,
-		usecaseDocument,
-		usecaseRequirement,
-		usecaseSignatory,
-		logger,
-	)
-
-	// Injetar webhook usecase no handler
-	envelopeHandlers.UsecaseWebhook = usecaseWebhook
+	documentIngestion := newDocumentIngestion(deps)
+
+	envelopeHandlers := newTenantHandlers(conn, func(conn *gorm.DB) *EnvelopeHandlers {
+		// Criar usecase de documento para envelopes com documentos base64
+		usecaseDocument := document.NewUsecaseDocumentServiceWithClicksign(
+			repository.NewRepositoryDocument(conn),
+			clicksignClient,
+			deps.Storage,
+			deps.Fetcher,
+			logger,
+		)
+
+		// Criar usecase de signatory
+		usecaseSignatory := signatory.NewUsecaseSignatoryService(
+			repository.NewRepositorySignatory(conn),
+			repository.NewRepositoryEnvelope(conn),
+			clicksignClient,
+			logger,
+		)
+
+		// Criar usecase de requirement
+		usecaseRequirement := requirement.NewUsecaseRequirementService(
+			repository.NewRepositoryRequirement(conn),
+			repository.NewRepositoryEnvelope(conn),
+			clicksignClient,
+			logger,
+		)
+
+		envelopeUsecase := usecase_envelope.NewUsecaseEnvelopeService(
+			repository.NewRepositoryEnvelope(conn),
+			clicksignClient,
+			usecaseDocument,
+			usecaseRequirement,
+			deps.Storage,
+			deps.Fetcher,
+			logger,
+		)
+
+		// // Criar usecase de webhook
+		usecaseWebhook := webhook.NewUsecaseWebhookService(
+			repository.NewRepositoryWebhook(conn),
+			envelopeUsecase,
+			usecaseDocument,
+			deps.Storage,
+			deps.Fetcher,
+			logger,
+		)
+
+		envelopeHandlers := NewEnvelopeHandler(
+			envelopeUsecase,
+			usecaseDocument,
+			usecaseRequirement,
+			usecaseSignatory,
+			logger,
+		)
+
+		// Injetar webhook usecase no handler
+		envelopeHandlers.UsecaseWebhook = usecaseWebhook
+		envelopeHandlers.UsecaseDocumentIngestion = documentIngestion
+		envelopeHandlers.DocumentFetcher = deps.Fetcher
+		envelopeHandlers.ApprovalRequired = config.EnvironmentVariables.ENVELOPE_APPROVAL_REQUIRED
+
+		return envelopeHandlers
+	})
 
 	// Criar handler de requirements
-	requirementHandlers := NewRequirementHandler(usecaseRequirement, logger)
+	requirementHandlers := newTenantHandlers(conn, func(conn *gorm.DB) *RequirementHandlers {
+		usecaseRequirement := requirement.NewUsecaseRequirementService(
+			repository.NewRepositoryRequirement(conn),
+			repository.NewRepositoryEnvelope(conn),
+			clicksignClient,
+			logger,
+		)
+		return NewRequirementHandler(usecaseRequirement, logger)
+	})
 
 	group := gin.Group("/api/v1/envelopes")
-	SetAuthMiddleware(conn, group)
+	SetScopedAuthMiddleware(conn, deps, group, entity.ScopeEnvelopesRead, entity.ScopeEnvelopesWrite)
+	group.Use(requireEnvelopeOwner(newEnvelopeOwnership(conn), envelopeFromRoute))
 
-	group.POST("/", envelopeHandlers.CreateEnvelopeHandler)
-	group.GET("/:id", envelopeHandlers.GetEnvelopeHandler)
-	group.GET("/", envelopeHandlers.GetEnvelopesHandler)
-	group.POST("/:id/activate", envelopeHandlers.ActivateEnvelopeHandler)
-	group.POST("/:id/notify", envelopeHandlers.NotifyEnvelopeHandler)
+	group.POST("/", envelopeHandlers.Handle((*EnvelopeHandlers).CreateEnvelopeHandler))
+	group.GET("/:id", envelopeHandlers.Handle((*EnvelopeHandlers).GetEnvelopeHandler))
+	group.GET("/", envelopeHandlers.Handle((*EnvelopeHandlers).GetEnvelopesHandler))
+	group.POST("/:id/activate", middleware.RequirePermissionMiddleware(entity.PermissionEnvelopesActivate), envelopeHandlers.Handle((*EnvelopeHandlers).ActivateEnvelopeHandler))
+	group.POST("/:id/notify", envelopeHandlers.Handle((*EnvelopeHandlers).NotifyEnvelopeHandler))
 
 	// Rota de fallback para verificar eventos manualmente quando webhook falha
-	group.POST("/:id/events/check", envelopeHandlers.CheckSignatureEventsHandler)
+	group.POST("/:id/events/check", envelopeHandlers.Handle((*EnvelopeHandlers).CheckSignatureEventsHandler))
 
 	// Rotas de requirements por envelope
-	group.POST("/:id/requirements", requirementHandlers.CreateRequirementHandler)
-	group.GET("/:id/requirements", requirementHandlers.GetRequirementsByEnvelopeHandler)
+	group.POST("/:id/requirements", requirementHandlers.Handle((*RequirementHandlers).CreateRequirementHandler))
+	group.GET("/:id/requirements", requirementHandlers.Handle((*RequirementHandlers).GetRequirementsByEnvelopeHandler))
 }
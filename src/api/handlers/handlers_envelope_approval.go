@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"app/api/handlers/dtos"
+	"app/api/middleware"
+	"app/entity"
+	"app/infrastructure/provider"
+	"app/infrastructure/provider_factory"
+	usecase_envelope "app/usecase/envelope"
+
+	"github.com/gin-gonic/gin"
+)
+
+// approvalRequester retorna o usuário cujo envelope precisa de aprovação antes da ativação no provider
+// Com a aprovação habilitada, são os usuários sem envelopes:activate; API keys e aprovadores seguem o fluxo direto
+func approvalRequester(approvalRequired bool, c *gin.Context) (entity.EntityUser, bool) {
+	if !approvalRequired {
+		return entity.EntityUser{}, false
+	}
+
+	user, ok := middleware.UserFromContext(c)
+	if !ok || user.HasPermission(entity.PermissionEnvelopesActivate) {
+		return entity.EntityUser{}, false
+	}
+	return user, true
+}
+
+// notifiesSignersOnCreate indica providers em que a criação já envia o envelope, sem espaço para a aprovação
+func notifiesSignersOnCreate(providerName string) bool {
+	capabilities, ok := provider_factory.LookupCapabilities(providerName)
+	return ok && capabilities.NotifiesSignersOnCreate
+}
+
+func newEnvelopeApprovalDTOs(trail []entity.EnvelopeApprovalDecision) []dtos.EnvelopeApprovalDTO {
+	if len(trail) == 0 {
+		return nil
+	}
+
+	approvals := make([]dtos.EnvelopeApprovalDTO, len(trail))
+	for i, decision := range trail {
+		approvals[i] = dtos.EnvelopeApprovalDTO{
+			Action:    decision.Action,
+			UserID:    decision.UserID,
+			UserName:  decision.UserName,
+			Comment:   decision.Comment,
+			CreatedAt: decision.CreatedAt,
+		}
+	}
+	return approvals
+}
+
+// @Summary Approve envelope (v2)
+// @Description Aprova um envelope em pending_approval e o ativa no provider. Quem criou o envelope não pode aprová-lo (quatro olhos).
+// @Description Se a ativação falhar, a aprovação fica registrada e o envelope permanece em approved, sem aceitar edições, até nova tentativa em /activate.
+// @Tags envelopes-v2
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Envelope ID"
+// @Param request body dtos.EnvelopeApprovalRequestDTO false "Comentário da aprovação"
+// @Success 200 {object} dtos.EnvelopeResponseDTO
+// @Failure 400 {object} dtos.ErrorResponseDTO
+// @Failure 403 {object} dtos.ErrorResponseDTO "Sem envelopes:approve, API key ou criador do envelope"
+// @Failure 404 {object} dtos.ErrorResponseDTO
+// @Failure 409 {object} dtos.ErrorResponseDTO "Envelope não está pendente de aprovação"
+// @Failure 500 {object} dtos.ErrorResponseDTO
+// @Router /api/v2/envelopes/{id}/approve [post]
+func (h *EnvelopeV2Handlers) ApproveEnvelopeV2Handler(c *gin.Context) {
+	h.decideEnvelopeApproval(c, true)
+}
+
+// @Summary Reject envelope (v2)
+// @Description Rejeita um envelope em pending_approval com o motivo no comentário; o envelope não é enviado aos signatários.
+// @Description Quem criou o envelope não pode rejeitá-lo.
+// @Tags envelopes-v2
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Envelope ID"
+// @Param request body dtos.EnvelopeApprovalRequestDTO true "Motivo da rejeição"
+// @Success 200 {object} dtos.EnvelopeResponseDTO
+// @Failure 400 {object} dtos.ErrorResponseDTO "ID inválido ou comentário ausente"
+// @Failure 403 {object} dtos.ErrorResponseDTO "Sem envelopes:approve, API key ou criador do envelope"
+// @Failure 404 {object} dtos.ErrorResponseDTO
+// @Failure 409 {object} dtos.ErrorResponseDTO "Envelope não está pendente de aprovação"
+// @Failure 500 {object} dtos.ErrorResponseDTO
+// @Router /api/v2/envelopes/{id}/reject [post]
+func (h *EnvelopeV2Handlers) RejectEnvelopeV2Handler(c *gin.Context) {
+	h.decideEnvelopeApproval(c, false)
+}
+
+func (h *EnvelopeV2Handlers) decideEnvelopeApproval(c *gin.Context, approve bool) {
+	correlationID := c.GetHeader("X-Correlation-ID")
+	if correlationID == "" {
+		correlationID = strconv.FormatInt(time.Now().Unix(), 10)
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Invalid ID",
+			Message: "Envelope ID must be a valid integer",
+			Details: map[string]interface{}{
+				"correlation_id": correlationID,
+			},
+		})
+		return
+	}
+
+	// A decisão é sempre de uma pessoa identificada, que fica registrada na trilha
+	approver, ok := middleware.UserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusForbidden, dtos.ErrorResponseDTO{
+			Error:   "Forbidden",
+			Message: "Envelope approval requires a user; API keys cannot approve or reject envelopes",
+		})
+		return
+	}
+
+	var request dtos.EnvelopeApprovalRequestDTO
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+				Error:   "Invalid request body",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	envelope, err := h.RepositoryEnvelope.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, dtos.ErrorResponseDTO{
+			Error:   "Envelope not found",
+			Message: "The requested envelope does not exist",
+			Details: map[string]interface{}{
+				"correlation_id": correlationID,
+			},
+		})
+		return
+	}
+
+	// A rejeição não chega ao provider; só a aprovação precisa dele para ativar o envelope
+	providerName := envelopeProviderName(envelope)
+	var envelopeProvider provider.EnvelopeProvider
+	if approve {
+		envelopeProvider, err = h.ProviderFactory.GetProviderForTenant(envelope.TenantID, providerName)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+				Error:   "Internal server error",
+				Message: fmt.Sprintf("Failed to get provider: %v", err),
+				Details: map[string]interface{}{
+					"correlation_id": correlationID,
+					"provider":       providerName,
+				},
+			})
+			return
+		}
+	}
+
+	envelopeProviderService := usecase_envelope.NewUsecaseEnvelopeProviderService(
+		h.RepositoryEnvelope,
+		envelopeProvider,
+		h.UsecaseDocuments,
+		h.UsecaseRequirement,
+		h.Logger,
+	)
+
+	var decided *entity.EntityEnvelope
+	if approve {
+		decided, err = envelopeProviderService.ApproveEnvelope(id, approver, request.Comment)
+	} else {
+		decided, err = envelopeProviderService.RejectEnvelope(id, approver, request.Comment)
+	}
+
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, entity.ErrEnvelopeNotPendingApproval):
+			status = http.StatusConflict
+		case errors.Is(err, entity.ErrSelfApproval):
+			status = http.StatusForbidden
+		case errors.Is(err, entity.ErrRejectionCommentRequired):
+			status = http.StatusBadRequest
+		}
+
+		action := "reject"
+		if approve {
+			action = "approve"
+		}
+		c.JSON(status, dtos.ErrorResponseDTO{
+			Error:   http.StatusText(status),
+			Message: fmt.Sprintf("Failed to %s envelope: %v", action, err),
+			Details: map[string]interface{}{
+				"correlation_id": correlationID,
+				"provider":       providerName,
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.mapEntityToResponseV2(decided))
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"app/entity"
+	"app/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApprovalRequester(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	contextWith := func(key string, value interface{}) *gin.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Set(key, value)
+		return c
+	}
+
+	operator := entity.EntityUser{ID: 7, Role: entity.RoleOperator}
+
+	requester, ok := approvalRequester(true, contextWith("user", operator))
+	assert.True(t, ok)
+	assert.Equal(t, operator.ID, requester.ID)
+
+	_, ok = approvalRequester(false, contextWith("user", operator))
+	assert.False(t, ok)
+
+	_, ok = approvalRequester(true, contextWith("user", entity.EntityUser{ID: 8, Role: entity.RoleApprover}))
+	assert.False(t, ok)
+
+	_, ok = approvalRequester(true, contextWith("api_client", entity.EntityAPIClient{ID: 3}))
+	assert.False(t, ok)
+}
+
+func TestRejectEnvelopeV2Handler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	envelopes := mocks.NewMockIRepositoryEnvelope(ctrl)
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	h := &EnvelopeV2Handlers{RepositoryEnvelope: envelopes, Logger: logger}
+
+	perform := func(principal func(c *gin.Context), body string) *httptest.ResponseRecorder {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(principal)
+		router.POST("/api/v2/envelopes/:id/reject", h.RejectEnvelopeV2Handler)
+
+		req, _ := http.NewRequest(http.MethodPost, "/api/v2/envelopes/10/reject", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	asUser := func(user entity.EntityUser) func(c *gin.Context) {
+		return func(c *gin.Context) { c.Set("user", user) }
+	}
+
+	approver := entity.EntityUser{ID: 2, Name: "Aprovador", Role: entity.RoleApprover}
+
+	t.Run("should record the rejection", func(t *testing.T) {
+		envelope := &entity.EntityEnvelope{ID: 10, Status: entity.EnvelopeStatusPendingApproval, CreatedByID: 7}
+		envelopes.EXPECT().GetByID(10).Return(envelope, nil).Times(2)
+		envelopes.EXPECT().Update(envelope).Return(nil)
+
+		w := perform(asUser(approver), `{"comment":"Valor divergente"}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"rejected"`)
+		assert.Contains(t, w.Body.String(), `"comment":"Valor divergente"`)
+	})
+
+	t.Run("should require a comment", func(t *testing.T) {
+		envelopes.EXPECT().GetByID(10).Return(&entity.EntityEnvelope{ID: 10, Status: entity.EnvelopeStatusPendingApproval, CreatedByID: 7}, nil).Times(2)
+
+		assert.Equal(t, http.StatusBadRequest, perform(asUser(approver), "").Code)
+	})
+
+	t.Run("should forbid the creator of the envelope", func(t *testing.T) {
+		envelopes.EXPECT().GetByID(10).Return(&entity.EntityEnvelope{ID: 10, Status: entity.EnvelopeStatusPendingApproval, CreatedByID: 2}, nil).Times(2)
+
+		assert.Equal(t, http.StatusForbidden, perform(asUser(approver), `{"comment":"não"}`).Code)
+	})
+
+	t.Run("should conflict when the envelope is not pending approval", func(t *testing.T) {
+		envelopes.EXPECT().GetByID(10).Return(&entity.EntityEnvelope{ID: 10, Status: "sent"}, nil).Times(2)
+
+		assert.Equal(t, http.StatusConflict, perform(asUser(approver), `{"comment":"não"}`).Code)
+	})
+
+	t.Run("should forbid api keys", func(t *testing.T) {
+		w := perform(func(c *gin.Context) { c.Set("api_client", entity.EntityAPIClient{ID: 3}) }, `{"comment":"não"}`)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
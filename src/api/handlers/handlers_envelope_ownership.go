@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"app/api/handlers/dtos"
+	"app/api/middleware"
+	"app/entity"
+	"app/infrastructure/repository"
+	usecase_envelope "app/usecase/envelope"
+	"app/usecase/requirement"
+	"app/usecase/signatory"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// envelopeOwnership localiza o envelope da rota para conferir se o usuário o criou
+type envelopeOwnership struct {
+	envelopes    usecase_envelope.IRepositoryEnvelope
+	signatories  signatory.IRepositorySignatory
+	requirements requirement.IRepositoryRequirement
+}
+
+// envelopeLookup encontra o envelope referenciado pela rota; errEnvelopeNotInRoute indica rotas sem envelope, como a listagem
+type envelopeLookup func(o *envelopeOwnership, c *gin.Context) (*entity.EntityEnvelope, error)
+
+var errEnvelopeNotInRoute = errors.New("route without envelope")
+
+func newEnvelopeOwnership(conn *gorm.DB) *tenantHandlers[envelopeOwnership] {
+	return newTenantHandlers(conn, func(conn *gorm.DB) *envelopeOwnership {
+		return &envelopeOwnership{
+			envelopes:    repository.NewRepositoryEnvelope(conn),
+			signatories:  repository.NewRepositorySignatory(conn),
+			requirements: repository.NewRepositoryRequirement(conn),
+		}
+	})
+}
+
+// requireEnvelopeOwner responde 404 quando o usuário está restrito aos próprios envelopes e a rota é de envelope de outro usuário
+// Envelopes inexistentes e ids inválidos seguem para o handler, que já trata esses casos
+func requireEnvelopeOwner(owners *tenantHandlers[envelopeOwnership], lookup envelopeLookup) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ownerID, restricted := middleware.EnvelopeOwnerFromContext(c)
+		if !restricted {
+			c.Next()
+			return
+		}
+
+		envelope, err := lookup(owners.For(middleware.TenantFromContext(c)), c)
+		if err != nil || envelope.CreatedByID == ownerID {
+			c.Next()
+			return
+		}
+
+		c.JSON(http.StatusNotFound, dtos.ErrorResponseDTO{
+			Error:   "Envelope not found",
+			Message: "The requested envelope does not exist",
+		})
+		c.Abort()
+	}
+}
+
+// envelopeFromRoute lê o envelope do parâmetro :id ou, nas rotas by-key, do :key do provider
+func envelopeFromRoute(o *envelopeOwnership, c *gin.Context) (*entity.EntityEnvelope, error) {
+	if key := c.Param("key"); key != "" {
+		return o.envelopes.GetByClicksignKey(key)
+	}
+	id, err := intParam(c, "id")
+	if err != nil {
+		return nil, err
+	}
+	return o.envelopes.GetByID(id)
+}
+
+// envelopeOfSignatory lê o envelope do signatário do parâmetro :id
+func envelopeOfSignatory(o *envelopeOwnership, c *gin.Context) (*entity.EntityEnvelope, error) {
+	id, err := intParam(c, "id")
+	if err != nil {
+		return nil, err
+	}
+	signatory, err := o.signatories.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return o.envelopes.GetByID(signatory.EnvelopeID)
+}
+
+// envelopeOfRequirement lê o envelope do requirement do parâmetro :requirement_id
+func envelopeOfRequirement(o *envelopeOwnership, c *gin.Context) (*entity.EntityEnvelope, error) {
+	id, err := intParam(c, "requirement_id")
+	if err != nil {
+		return nil, err
+	}
+	requirement, err := o.requirements.GetByID(c.Request.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	return o.envelopes.GetByID(requirement.EnvelopeID)
+}
+
+func intParam(c *gin.Context, name string) (int, error) {
+	value := c.Param(name)
+	if value == "" {
+		return 0, errEnvelopeNotInRoute
+	}
+	return strconv.Atoi(value)
+}
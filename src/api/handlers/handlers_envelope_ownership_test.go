@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"app/entity"
+	"app/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestRequireEnvelopeOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	envelopes := mocks.NewMockIRepositoryEnvelope(ctrl)
+	signatories := mocks.NewMockIRepositorySignatory(ctrl)
+	owners := &tenantHandlers[envelopeOwnership]{byTenant: map[string]*envelopeOwnership{
+		"": {envelopes: envelopes, signatories: signatories},
+	}}
+
+	perform := func(user entity.EntityUser, path string) *httptest.ResponseRecorder {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(func(c *gin.Context) { c.Set("user", user) })
+		router.GET("/api/v2/envelopes/:id", requireEnvelopeOwner(owners, envelopeFromRoute), func(c *gin.Context) { c.Status(http.StatusOK) })
+		router.GET("/api/v2/envelopes/", requireEnvelopeOwner(owners, envelopeFromRoute), func(c *gin.Context) { c.Status(http.StatusOK) })
+		router.GET("/api/v1/signatories/:id", requireEnvelopeOwner(owners, envelopeOfSignatory), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	operator := entity.EntityUser{ID: 7, Role: entity.RoleOperator}
+
+	t.Run("should let operators access their own envelopes", func(t *testing.T) {
+		envelopes.EXPECT().GetByID(10).Return(&entity.EntityEnvelope{ID: 10, CreatedByID: 7}, nil)
+
+		assert.Equal(t, http.StatusOK, perform(operator, "/api/v2/envelopes/10").Code)
+	})
+
+	t.Run("should hide envelopes of other users from operators", func(t *testing.T) {
+		envelopes.EXPECT().GetByID(11).Return(&entity.EntityEnvelope{ID: 11, CreatedByID: 8}, nil)
+		signatories.EXPECT().GetByID(5).Return(&entity.EntitySignatory{ID: 5, EnvelopeID: 11}, nil)
+		envelopes.EXPECT().GetByID(11).Return(&entity.EntityEnvelope{ID: 11, CreatedByID: 8}, nil)
+
+		assert.Equal(t, http.StatusNotFound, perform(operator, "/api/v2/envelopes/11").Code)
+		assert.Equal(t, http.StatusNotFound, perform(operator, "/api/v1/signatories/5").Code)
+	})
+
+	t.Run("should leave missing envelopes and listings to the handler", func(t *testing.T) {
+		envelopes.EXPECT().GetByID(12).Return(nil, gorm.ErrRecordNotFound)
+
+		assert.Equal(t, http.StatusOK, perform(operator, "/api/v2/envelopes/12").Code)
+		assert.Equal(t, http.StatusOK, perform(operator, "/api/v2/envelopes/").Code)
+	})
+
+	t.Run("should not restrict users with envelopes:read_all", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, perform(entity.EntityUser{ID: 7, Role: entity.RoleApprover}, "/api/v2/envelopes/11").Code)
+		assert.Equal(t, http.StatusOK, perform(entity.EntityUser{ID: 7, Role: entity.RoleOperator, Permissions: []string{entity.PermissionEnvelopesReadAll}}, "/api/v2/envelopes/11").Code)
+	})
+}
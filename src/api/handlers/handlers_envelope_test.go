@@ -31,6 +31,7 @@ func TestNewEnvelopeHandler(t *testing.T) {
 	logger := logrus.New()
 
 	handler := NewEnvelopeHandler(mockUsecaseEnvelope, mockUsecaseDocument, mockUsecaseRequirement, mockUsecaseSignatory, logger)
+	handler.UsecaseDocumentIngestion, _ = newTestIngestion(t, nil, nil)
 
 	assert.NotNil(t, handler)
 	assert.Equal(t, mockUsecaseEnvelope, handler.UsecaseEnvelope)
@@ -54,6 +55,7 @@ func TestCreateEnvelopeHandler_WithoutSignatories_Success(t *testing.T) {
 	logger.SetLevel(logrus.FatalLevel) // Suprimir logs durante teste
 
 	handler := NewEnvelopeHandler(mockUsecaseEnvelope, mockUsecaseDocument, mockUsecaseRequirement, mockUsecaseSignatory, logger)
+	handler.UsecaseDocumentIngestion, _ = newTestIngestion(t, nil, nil)
 
 	// Request DTO sem signatários (compatibilidade retroativa)
 	requestDTO := dtos.EnvelopeCreateRequestDTO{
@@ -151,6 +153,7 @@ func TestCreateEnvelopeHandler_WithSignatories_Success(t *testing.T) {
 	logger.SetLevel(logrus.FatalLevel) // Suprimir logs durante teste
 
 	handler := NewEnvelopeHandler(mockUsecaseEnvelope, mockUsecaseDocument, mockUsecaseRequirement, mockUsecaseSignatory, logger)
+	handler.UsecaseDocumentIngestion, _ = newTestIngestion(t, nil, nil)
 
 	// Request DTO com signatários
 	requestDTO := dtos.EnvelopeCreateRequestDTO{
@@ -270,6 +273,7 @@ func TestCreateEnvelopeHandler_EnvelopeCreationFails(t *testing.T) {
 	logger.SetLevel(logrus.FatalLevel) // Suprimir logs durante teste
 
 	handler := NewEnvelopeHandler(mockUsecaseEnvelope, mockUsecaseDocument, mockUsecaseRequirement, mockUsecaseSignatory, logger)
+	handler.UsecaseDocumentIngestion, _ = newTestIngestion(t, nil, nil)
 
 	requestDTO := dtos.EnvelopeCreateRequestDTO{
 		Name:            "Test Envelope",
@@ -332,6 +336,7 @@ func TestCreateEnvelopeHandler_SignatoryCreationFails(t *testing.T) {
 	logger.SetLevel(logrus.FatalLevel) // Suprimir logs durante teste
 
 	handler := NewEnvelopeHandler(mockUsecaseEnvelope, mockUsecaseDocument, mockUsecaseRequirement, mockUsecaseSignatory, logger)
+	handler.UsecaseDocumentIngestion, _ = newTestIngestion(t, nil, nil)
 
 	requestDTO := dtos.EnvelopeCreateRequestDTO{
 		Name:        "Test Envelope",
@@ -434,6 +439,7 @@ func TestCreateEnvelopeHandler_ValidationErrors(t *testing.T) {
 	logger.SetLevel(logrus.FatalLevel) // Suprimir logs durante teste
 
 	handler := NewEnvelopeHandler(mockUsecaseEnvelope, mockUsecaseDocument, mockUsecaseRequirement, mockUsecaseSignatory, logger)
+	handler.UsecaseDocumentIngestion, _ = newTestIngestion(t, nil, nil)
 
 	// Request DTO com dados inválidos
 	requestDTO := dtos.EnvelopeCreateRequestDTO{
@@ -484,6 +490,7 @@ func TestCreateEnvelopeHandler_DuplicateSignatoryEmails(t *testing.T) {
 	logger.SetLevel(logrus.FatalLevel) // Suprimir logs durante teste
 
 	handler := NewEnvelopeHandler(mockUsecaseEnvelope, mockUsecaseDocument, mockUsecaseRequirement, mockUsecaseSignatory, logger)
+	handler.UsecaseDocumentIngestion, _ = newTestIngestion(t, nil, nil)
 
 	// Request DTO com emails duplicados de signatários
 	requestDTO := dtos.EnvelopeCreateRequestDTO{
@@ -611,6 +618,7 @@ func TestCreateEnvelopeHandler_WithClicksignRawData_Success(t *testing.T) {
 	logger.SetLevel(logrus.FatalLevel)
 
 	handler := NewEnvelopeHandler(mockUsecaseEnvelope, mockUsecaseDocument, mockUsecaseRequirement, mockUsecaseSignatory, logger)
+	handler.UsecaseDocumentIngestion, _ = newTestIngestion(t, nil, nil)
 
 	requestDTO := dtos.EnvelopeCreateRequestDTO{
 		Name:            "Test Envelope",
@@ -704,6 +712,7 @@ func TestCreateEnvelopeHandler_WithoutClicksignRawData_Success(t *testing.T) {
 	logger.SetLevel(logrus.FatalLevel)
 
 	handler := NewEnvelopeHandler(mockUsecaseEnvelope, mockUsecaseDocument, mockUsecaseRequirement, mockUsecaseSignatory, logger)
+	handler.UsecaseDocumentIngestion, _ = newTestIngestion(t, nil, nil)
 
 	requestDTO := dtos.EnvelopeCreateRequestDTO{
 		Name:            "Test Envelope",
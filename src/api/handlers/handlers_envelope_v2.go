@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,15 +12,20 @@ import (
 	"time"
 
 	"app/api/handlers/dtos"
+	"app/api/middleware"
 	"app/config"
 	"app/entity"
 	"app/infrastructure/clicksign"
+	"app/infrastructure/dependencies"
+	"app/infrastructure/normalizer"
 	"app/infrastructure/provider"
 	"app/infrastructure/provider_factory"
 	"app/infrastructure/repository"
 	"app/infrastructure/vertc_assinaturas"
+	"app/pkg/fetcher"
 	"app/pkg/utils"
 	"app/usecase/document"
+	usecase_document_template "app/usecase/document_template"
 	usecase_envelope "app/usecase/envelope"
 	"app/usecase/requirement"
 	"app/usecase/signatory"
@@ -41,7 +47,15 @@ type EnvelopeV2Handlers struct {
 	RepositoryEnvelope      usecase_envelope.IRepositoryEnvelope
 	RepositorySignatory     signatory.IRepositorySignatory
 	RepositoryRequirement   requirement.IRepositoryRequirement
-	Logger                  *logrus.Logger
+	// UsecaseDocumentTemplates gera os documentos informados por template_id
+	UsecaseDocumentTemplates usecase_document_template.IUsecaseDocumentTemplate
+	// UsecaseDocumentIngestion verifica, converte, normaliza e grava no storage os documentos recebidos
+	UsecaseDocumentIngestion *document.UsecaseDocumentIngestionService
+	// DocumentFetcher baixa os documentos informados por file_url, bloqueando a rede interna
+	DocumentFetcher *fetcher.Fetcher
+	// ApprovalRequired deixa os envelopes de usuários sem envelopes:activate aguardando aprovação antes da ativação
+	ApprovalRequired bool
+	Logger           *logrus.Logger
 }
 
 // NewEnvelopeV2Handler cria uma nova instância do EnvelopeV2Handlers
@@ -70,15 +84,19 @@ func NewEnvelopeV2Handler(
 }
 
 // @Summary Create envelope (v2)
-// @Description Create a new envelope with provider selection. Supports multiple providers (clicksign, vert-sign). The provider field is required.
+// @Description Create a new envelope with provider selection. Supports multiple providers (clicksign, vert-sign). The provider field is required. Use provider "auto" (order from PROVIDER_FAILOVER_ORDER) or fallback_providers to fail over to the next provider when the current one is unavailable and no signer was notified yet; the response provider field tells which provider holds the envelope. Large files can be uploaded first with multipart POST /api/v1/documents and referenced through documents_ids. A document can also reference a stored template with template_id and variables; the PDF is generated from /api/v2/document-templates.
+// @Description With ENVELOPE_APPROVAL_REQUIRED, envelopes created by users without envelopes:activate are kept as drafts at the provider with status pending_approval (approved is ignored) until POST /api/v2/envelopes/{id}/approve; providers that send on creation (vert-sign) are skipped or rejected with 422.
 // @Tags envelopes-v2
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
+// @Param X-Tenant-ID header string false "Tenant that owns the envelope and whose stored provider credentials are used; only platform admins may choose it. Defaults to the caller's tenant"
 // @Param request body dtos.EnvelopeV2CreateRequestDTO true "Envelope data with provider field"
 // @Success 201 {object} dtos.EnvelopeResponseDTO "Envelope created successfully"
 // @Failure 400 {object} dtos.ValidationErrorResponseDTO "Validation error or invalid provider"
+// @Failure 422 {object} dtos.ErrorResponseDTO "DOCX/ODT/HTML document could not be converted to PDF, template PDF generation failed, the PDF is encrypted or corrupt , a document is infected or the provider cannot wait for a required approval"
 // @Failure 501 {object} dtos.ErrorResponseDTO "Provider not implemented"
+// @Failure 503 {object} dtos.ErrorResponseDTO "Malware scanner or document ingestion unavailable"
 // @Failure 500 {object} dtos.ErrorResponseDTO "Internal server error"
 // @Router /api/v2/envelopes [post]
 func (h *EnvelopeV2Handlers) CreateEnvelopeV2Handler(c *gin.Context) {
@@ -121,40 +139,18 @@ func (h *EnvelopeV2Handlers) CreateEnvelopeV2Handler(c *gin.Context) {
 		return
 	}
 
-	if err := h.validateVertSignAutoSignaturePreconditions(c, &requestDTO, correlationID); err != nil {
-		return
-	}
-
-	// Validar e obter provider
-	envelopeProvider, err := h.ProviderFactory.GetProvider(requestDTO.Provider)
+	// Providers a tentar, em ordem (provider "auto" ou fallback_providers habilitam failover)
+	providerCandidates, err := requestDTO.ProviderCandidates()
 	if err != nil {
-		// Verificar se é provider não implementado ou inválido
-		if !h.ProviderFactory.IsProviderSupported(requestDTO.Provider) {
-			c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
-				Error:   "Invalid provider",
-				Message: err.Error(),
-				Details: map[string]interface{}{
-					"correlation_id": correlationID,
-					"provider":       requestDTO.Provider,
-				},
-			})
-			return
-		}
-
-		// Provider suportado mas não implementado
-		c.JSON(http.StatusNotImplemented, dtos.ErrorResponseDTO{
-			Error:   "Provider not implemented",
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Validation failed",
 			Message: err.Error(),
-			Details: map[string]interface{}{
-				"correlation_id": correlationID,
-				"provider":       requestDTO.Provider,
-			},
 		})
 		return
 	}
 
 	// Converter DTO para entidade (reutilizar lógica do handler v1)
-	envelope, documents, err := h.mapCreateRequestToEntityV2(requestDTO)
+	envelope, documents, err := h.mapCreateRequestToEntityV2(c.Request.Context(), requestDTO)
 	if err != nil {
 		h.Logger.WithFields(logrus.Fields{
 			"correlation_id": correlationID,
@@ -162,6 +158,64 @@ func (h *EnvelopeV2Handlers) CreateEnvelopeV2Handler(c *gin.Context) {
 			"error":          err.Error(),
 		}).Error("Failed to map request DTO to entity")
 
+		if errors.Is(err, document.ErrConversion) {
+			c.JSON(http.StatusUnprocessableEntity, dtos.ErrorResponseDTO{
+				Error:   "Conversion failed",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, document.ErrInfected) {
+			c.JSON(http.StatusUnprocessableEntity, dtos.ErrorResponseDTO{
+				Error:   "Document infected",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, document.ErrScanFailed) {
+			c.JSON(http.StatusServiceUnavailable, dtos.ErrorResponseDTO{
+				Error:   "Malware scan unavailable",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, document.ErrNotConfigured) {
+			c.JSON(http.StatusServiceUnavailable, dtos.ErrorResponseDTO{
+				Error:   "Document ingestion unavailable",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, document.ErrNormalization) {
+			c.JSON(http.StatusUnprocessableEntity, dtos.ErrorResponseDTO{
+				Error:   "Document rejected",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, errTemplateRendering) {
+			c.JSON(http.StatusUnprocessableEntity, dtos.ErrorResponseDTO{
+				Error:   "Template rendering failed",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		// Falhas do download por file_url são de validação e trazem a categoria do bloqueio
+		if details, ok := fetchValidationDetails(err); ok {
+			c.JSON(http.StatusBadRequest, dtos.ValidationErrorResponseDTO{
+				Error:   "Validation failed",
+				Message: err.Error(),
+				Details: details,
+			})
+			return
+		}
+
 		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
 			Error:   "Invalid request",
 			Message: err.Error(),
@@ -169,108 +223,148 @@ func (h *EnvelopeV2Handlers) CreateEnvelopeV2Handler(c *gin.Context) {
 		return
 	}
 
-	// Limpar arquivos temporários em caso de erro
-	var tempPaths []string
-	for _, doc := range documents {
-		if doc.IsFromBase64 && doc.FilePath != "" {
-			tempPaths = append(tempPaths, doc.FilePath)
-		}
-	}
-	defer func() {
-		for _, tempPath := range tempPaths {
-			if cleanupErr := utils.CleanupTempFile(tempPath); cleanupErr != nil {
-				h.Logger.Warn("Failed to cleanup temporary file")
+	// Tenant da requisição (usuário, API key ou X-Tenant-ID de administradores da plataforma), que define também as credenciais
+	tenantID := middleware.TenantFromContext(c)
+	envelope.TenantID = tenantID
+	envelope.CreatedByID = middleware.UserIDFromContext(c)
+
+	// Com a aprovação obrigatória, o envelope fica em rascunho no provider até a decisão de um aprovador
+	requester, needsApproval := approvalRequester(h.ApprovalRequired, c)
+
+	var envelopeProvider provider.EnvelopeProvider
+	var envelopeProviderService *usecase_envelope.UsecaseEnvelopeProviderService
+	var createdEnvelope *entity.EntityEnvelope
+	for attempt, providerName := range providerCandidates {
+		hasFallback := attempt < len(providerCandidates)-1
+
+		// A partir daqui o fluxo segue o provider escolhido
+		requestDTO.Provider = providerName
+
+		// Providers que enviam o envelope já na criação não permitem aprovação prévia
+		if needsApproval && notifiesSignersOnCreate(providerName) {
+			if hasFallback {
+				h.Logger.WithFields(logrus.Fields{
+					"correlation_id": correlationID,
+					"provider":       providerName,
+				}).Warn("Skipping provider that sends on create for envelope pending approval")
+				continue
 			}
+
+			c.JSON(http.StatusUnprocessableEntity, dtos.ErrorResponseDTO{
+				Error:   "Approval required",
+				Message: fmt.Sprintf("Provider %s sends the envelope on creation and cannot wait for approval", providerName),
+				Details: map[string]interface{}{
+					"correlation_id": correlationID,
+					"provider":       providerName,
+				},
+			})
+			return
 		}
-	}()
 
-	// Criar use case com provider
-	envelopeProviderService := usecase_envelope.NewUsecaseEnvelopeProviderService(
-		h.RepositoryEnvelope,
-		envelopeProvider,
-		h.UsecaseDocuments,
-		h.UsecaseRequirement,
-		h.Logger,
-	)
+		if err := h.validateVertSignAutoSignaturePreconditions(c, &requestDTO, correlationID); err != nil {
+			return
+		}
 
-	// Para vert-sign, passamos documentos e signatários via contexto.
-	// O provider decide internamente entre quick-send e fluxo direto.
-	ctx := c.Request.Context()
-	if requestDTO.Provider == "vert-sign" {
-		// Preparar signatários para o contexto
-		var signersData []provider.SignerData
-		if len(requestDTO.Signatories) > 0 {
-			for _, signatoryRequest := range requestDTO.Signatories {
-				signatoryDTO := signatoryRequest.ToSignatoryCreateRequestDTO(0) // ID temporário
-				signatoryEntity := signatoryDTO.ToEntity()
-				authMethod, err := signatoryRequest.ResolveAuthMethod()
-				if err != nil {
-					c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
-						Error:   "Validation failed",
-						Message: err.Error(),
-						Details: map[string]interface{}{
-							"correlation_id": correlationID,
-							"provider":       requestDTO.Provider,
-						},
-					})
-					return
+		// Validar e obter provider
+		envelopeProvider, err = h.ProviderFactory.GetProviderForTenant(tenantID, providerName)
+		if err != nil {
+			if errors.Is(err, provider_factory.ErrCredentialsNotFound) {
+				if hasFallback {
+					h.Logger.WithFields(logrus.Fields{
+						"correlation_id": correlationID,
+						"provider":       providerName,
+						"tenant_id":      tenantID,
+					}).Warn("Skipping provider without tenant credentials")
+					continue
 				}
 
-				defaultGroup := 1
-				defaultHasDoc := false
-				defaultRefusable := true
+				c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+					Error:   "Provider credentials not configured",
+					Message: err.Error(),
+					Details: map[string]interface{}{
+						"correlation_id": correlationID,
+						"provider":       providerName,
+						"tenant_id":      tenantID,
+					},
+				})
+				return
+			}
 
-				signerData := provider.SignerData{
-					Name:             signatoryEntity.Name,
-					Email:            signatoryEntity.Email,
-					Birthday:         "",
-					HasDocumentation: defaultHasDoc,
-					Refusable:        defaultRefusable,
-					Group:            defaultGroup,
-					AuthMethod:       authMethod,
-				}
+			// Verificar se é provider não implementado ou inválido
+			if !h.ProviderFactory.IsProviderSupported(providerName) {
+				c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+					Error:   "Invalid provider",
+					Message: err.Error(),
+					Details: map[string]interface{}{
+						"correlation_id": correlationID,
+						"provider":       providerName,
+					},
+				})
+				return
+			}
 
-				if signatoryEntity.Birthday != nil {
-					signerData.Birthday = *signatoryEntity.Birthday
-				}
-				if signatoryEntity.Documentation != nil {
-					signerData.Documentation = signatoryEntity.Documentation
-				}
-				if signatoryEntity.PhoneNumber != nil {
-					signerData.PhoneNumber = signatoryEntity.PhoneNumber
-				}
-				if signatoryEntity.HasDocumentation != nil {
-					signerData.HasDocumentation = *signatoryEntity.HasDocumentation
-				}
-				if signatoryEntity.Refusable != nil {
-					signerData.Refusable = *signatoryEntity.Refusable
-				}
-				if signatoryEntity.Group != nil && *signatoryEntity.Group > 0 {
-					signerData.Group = *signatoryEntity.Group
-				}
-				signersData = append(signersData, signerData)
+			// Provider suportado mas não implementado
+			c.JSON(http.StatusNotImplemented, dtos.ErrorResponseDTO{
+				Error:   "Provider not implemented",
+				Message: err.Error(),
+				Details: map[string]interface{}{
+					"correlation_id": correlationID,
+					"provider":       providerName,
+				},
+			})
+			return
+		}
+
+		// Registrar o provider que mantém o envelope; o ID é zerado pois uma tentativa anterior pode ter sido revertida
+		envelope.ID = 0
+		envelope.Provider = providerName
+
+		// Criar use case com provider
+		envelopeProviderService = usecase_envelope.NewUsecaseEnvelopeProviderService(
+			h.RepositoryEnvelope,
+			envelopeProvider,
+			h.UsecaseDocuments,
+			h.UsecaseRequirement,
+			h.Logger,
+		)
+
+		// Para vert-sign, passamos documentos e signatários via contexto.
+		// O provider decide internamente entre quick-send e fluxo direto.
+		ctx := c.Request.Context()
+		if providerName == "vert-sign" {
+			quickSendData, err := h.buildVertSignQuickSendData(requestDTO, envelope, documents)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+					Error:   "Validation failed",
+					Message: err.Error(),
+					Details: map[string]interface{}{
+						"correlation_id": correlationID,
+						"provider":       providerName,
+					},
+				})
+				return
 			}
+			ctx = vertc_assinaturas.WithQuickSendData(ctx, quickSendData)
 		}
 
-		// Adicionar dados ao contexto
-		// Log dos documentos e seus metadatas para debug
-		for i, doc := range documents {
-			h.Logger.Debugf("Documento %d preparado para o fluxo do provider vert-sign: Name=%s, Metadata length=%d, Metadata=%s",
-				i+1, doc.Name, len(doc.Metadata), string(doc.Metadata))
+		// Criar envelope através do use case
+		// Para vert-sign, o contexto já contém QuickSendData com documentos e signatários
+		createdEnvelope, err = envelopeProviderService.CreateEnvelope(ctx, envelope)
+		if err == nil {
+			break
 		}
 
-		quickSendData := &vertc_assinaturas.QuickSendData{
-			Envelope:  envelope,
-			Documents: documents,
-			Signers:   signersData,
+		if hasFallback && h.canFailoverEnvelopeCreation(providerName, err) {
+			h.Logger.WithFields(logrus.Fields{
+				"correlation_id":    correlationID,
+				"provider":          providerName,
+				"fallback_provider": providerCandidates[attempt+1],
+				"envelope_name":     requestDTO.Name,
+				"error":             err.Error(),
+			}).Warn("Provider unavailable, failing over envelope creation to next provider")
+			continue
 		}
-		ctx = vertc_assinaturas.WithQuickSendData(ctx, quickSendData)
-	}
 
-	// Criar envelope através do use case
-	// Para vert-sign, o contexto já contém QuickSendData com documentos e signatários
-	createdEnvelope, err := envelopeProviderService.CreateEnvelope(ctx, envelope)
-	if err != nil {
 		status := http.StatusInternalServerError
 		var ce *clicksign.ClicksignError
 		if errors.As(err, &ce) && ce.StatusCode > 0 {
@@ -280,7 +374,7 @@ func (h *EnvelopeV2Handlers) CreateEnvelopeV2Handler(c *gin.Context) {
 		// Log detalhado do erro
 		h.Logger.WithFields(logrus.Fields{
 			"correlation_id": correlationID,
-			"provider":       requestDTO.Provider,
+			"provider":       providerName,
 			"envelope_name":  requestDTO.Name,
 			"status_code":    status,
 			"error":          err.Error(),
@@ -291,7 +385,19 @@ func (h *EnvelopeV2Handlers) CreateEnvelopeV2Handler(c *gin.Context) {
 			Message: "Failed to create envelope: " + err.Error(),
 			Details: map[string]interface{}{
 				"correlation_id": correlationID,
-				"provider":       requestDTO.Provider,
+				"provider":       providerName,
+			},
+		})
+		return
+	}
+
+	if createdEnvelope == nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Provider credentials not configured",
+			Message: "No provider candidate could be used for this tenant",
+			Details: map[string]interface{}{
+				"correlation_id": correlationID,
+				"tenant_id":      tenantID,
 			},
 		})
 		return
@@ -303,7 +409,11 @@ func (h *EnvelopeV2Handlers) CreateEnvelopeV2Handler(c *gin.Context) {
 	if requestDTO.Provider == "vert-sign" {
 		// O provider vert-sign já criou tudo, apenas atualizar documentos localmente
 		for _, doc := range documents {
-			err := h.UsecaseDocuments.Create(doc)
+			var err error
+			// Documentos referenciados por documents_ids já estão persistidos
+			if doc.ID == 0 {
+				err = h.UsecaseDocuments.Create(doc)
+			}
 			if err != nil {
 				h.Logger.WithFields(logrus.Fields{
 					"correlation_id": correlationID,
@@ -401,7 +511,11 @@ func (h *EnvelopeV2Handlers) CreateEnvelopeV2Handler(c *gin.Context) {
 		// Criar documentos base64 se fornecidos
 		if len(documents) > 0 {
 			for _, doc := range documents {
-				err := h.UsecaseDocuments.Create(doc)
+				var err error
+				// Documentos referenciados por documents_ids já estão persistidos
+				if doc.ID == 0 {
+					err = h.UsecaseDocuments.Create(doc)
+				}
 				if err != nil {
 					h.Logger.WithFields(logrus.Fields{
 						"correlation_id": correlationID,
@@ -839,7 +953,21 @@ func (h *EnvelopeV2Handlers) CreateEnvelopeV2Handler(c *gin.Context) {
 		}
 	}
 
-	if requestDTO.Approved && requestDTO.Provider != "vert-sign" {
+	if needsApproval {
+		// O envelope aguarda a decisão de um aprovador; Approved é ignorado
+		createdEnvelope.RequestApproval(requester, time.Now())
+		if err := envelopeProviderService.UpdateEnvelope(createdEnvelope); err != nil {
+			c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+				Error:   "Internal server error",
+				Message: fmt.Sprintf("Failed to request approval for envelope %d: %v", createdEnvelope.ID, err),
+				Details: map[string]interface{}{
+					"correlation_id": correlationID,
+					"provider":       requestDTO.Provider,
+				},
+			})
+			return
+		}
+	} else if requestDTO.Approved && requestDTO.Provider != "vert-sign" {
 		// Ativar envelope se aprovado
 		createdEnvelope, err = envelopeProviderService.ActivateEnvelope(createdEnvelope.ID)
 		if err != nil {
@@ -861,6 +989,83 @@ func (h *EnvelopeV2Handlers) CreateEnvelopeV2Handler(c *gin.Context) {
 	c.JSON(http.StatusCreated, responseDTO)
 }
 
+// canFailoverEnvelopeCreation indica se a falha na criação permite tentar o próximo provider
+// Só é seguro quando o provider com certeza não criou o envelope e a criação não notifica signatários;
+// após um timeout o envelope pode existir no provider e o failover deixaria um envelope órfão
+func (h *EnvelopeV2Handlers) canFailoverEnvelopeCreation(providerName string, err error) bool {
+	capabilities, ok := provider_factory.LookupCapabilities(providerName)
+	if !ok || capabilities.NotifiesSignersOnCreate {
+		return false
+	}
+
+	return provider.IsNotProcessedError(err)
+}
+
+// buildVertSignQuickSendData prepara documentos e signatários repassados via contexto ao provider vert-sign
+func (h *EnvelopeV2Handlers) buildVertSignQuickSendData(
+	requestDTO dtos.EnvelopeV2CreateRequestDTO,
+	envelope *entity.EntityEnvelope,
+	documents []*entity.EntityDocument,
+) (*vertc_assinaturas.QuickSendData, error) {
+	var signersData []provider.SignerData
+	if len(requestDTO.Signatories) > 0 {
+		for _, signatoryRequest := range requestDTO.Signatories {
+			signatoryDTO := signatoryRequest.ToSignatoryCreateRequestDTO(0) // ID temporário
+			signatoryEntity := signatoryDTO.ToEntity()
+			authMethod, err := signatoryRequest.ResolveAuthMethod()
+			if err != nil {
+				return nil, err
+			}
+
+			defaultGroup := 1
+			defaultHasDoc := false
+			defaultRefusable := true
+
+			signerData := provider.SignerData{
+				Name:             signatoryEntity.Name,
+				Email:            signatoryEntity.Email,
+				Birthday:         "",
+				HasDocumentation: defaultHasDoc,
+				Refusable:        defaultRefusable,
+				Group:            defaultGroup,
+				AuthMethod:       authMethod,
+			}
+
+			if signatoryEntity.Birthday != nil {
+				signerData.Birthday = *signatoryEntity.Birthday
+			}
+			if signatoryEntity.Documentation != nil {
+				signerData.Documentation = signatoryEntity.Documentation
+			}
+			if signatoryEntity.PhoneNumber != nil {
+				signerData.PhoneNumber = signatoryEntity.PhoneNumber
+			}
+			if signatoryEntity.HasDocumentation != nil {
+				signerData.HasDocumentation = *signatoryEntity.HasDocumentation
+			}
+			if signatoryEntity.Refusable != nil {
+				signerData.Refusable = *signatoryEntity.Refusable
+			}
+			if signatoryEntity.Group != nil && *signatoryEntity.Group > 0 {
+				signerData.Group = *signatoryEntity.Group
+			}
+			signersData = append(signersData, signerData)
+		}
+	}
+
+	// Log dos documentos e seus metadatas para debug
+	for i, doc := range documents {
+		h.Logger.Debugf("Documento %d preparado para o fluxo do provider vert-sign: Name=%s, Metadata length=%d, Metadata=%s",
+			i+1, doc.Name, len(doc.Metadata), string(doc.Metadata))
+	}
+
+	return &vertc_assinaturas.QuickSendData{
+		Envelope:  envelope,
+		Documents: documents,
+		Signers:   signersData,
+	}, nil
+}
+
 func (h *EnvelopeV2Handlers) validateVertSignAutoSignaturePreconditions(
 	c *gin.Context,
 	requestDTO *dtos.EnvelopeV2CreateRequestDTO,
@@ -1008,6 +1213,8 @@ func (h *EnvelopeV2Handlers) GetEnvelopesV2Handler(c *gin.Context) {
 	filters.Search = c.Query("search")
 	filters.Status = c.Query("status")
 	filters.ClicksignKey = c.Query("clicksign_key")
+	// Usuários sem envelopes:read_all veem apenas os envelopes que criaram
+	filters.CreatedByID, _ = middleware.EnvelopeOwnerFromContext(c)
 
 	// Obter envelopes do repository (não precisa de provider para leitura)
 	envelopes, err := h.RepositoryEnvelope.GetEnvelopes(filters)
@@ -1038,6 +1245,7 @@ func (h *EnvelopeV2Handlers) GetEnvelopesV2Handler(c *gin.Context) {
 // @Failure 400 {object} dtos.ErrorResponseDTO
 // @Failure 404 {object} dtos.ErrorResponseDTO
 // @Failure 500 {object} dtos.ErrorResponseDTO
+// @Failure 403 {object} dtos.ErrorResponseDTO "Usuário sem a permissão envelopes:activate (papéis approver e admin)"
 // @Router /api/v2/envelopes/{id}/activate [post]
 func (h *EnvelopeV2Handlers) ActivateEnvelopeV2Handler(c *gin.Context) {
 	correlationID := c.GetHeader("X-Correlation-ID")
@@ -1076,7 +1284,7 @@ func (h *EnvelopeV2Handlers) ActivateEnvelopeV2Handler(c *gin.Context) {
 	// Determinar provider baseado no envelope
 	// Por enquanto, se tem ClicksignKey, assume Clicksign
 	// No futuro, podemos ter um campo provider na entidade
-	providerName := "clicksign" // Default para envelopes existentes
+	providerName := envelopeProviderName(envelope)
 	if envelope.ClicksignKey == "" {
 		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
 			Error:   "Bad Request",
@@ -1089,7 +1297,7 @@ func (h *EnvelopeV2Handlers) ActivateEnvelopeV2Handler(c *gin.Context) {
 	}
 
 	// Obter provider
-	envelopeProvider, err := h.ProviderFactory.GetProvider(providerName)
+	envelopeProvider, err := h.ProviderFactory.GetProviderForTenant(envelope.TenantID, providerName)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
 			Error:   "Internal server error",
@@ -1191,7 +1399,7 @@ func (h *EnvelopeV2Handlers) NotifyEnvelopeV2Handler(c *gin.Context) {
 
 	// Determinar provider baseado no envelope
 	// Por enquanto, se tem ClicksignKey, assume Clicksign
-	providerName := "clicksign" // Default para envelopes existentes
+	providerName := envelopeProviderName(envelope)
 	if envelope.ClicksignKey == "" {
 		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
 			Error:   "Bad Request",
@@ -1204,7 +1412,7 @@ func (h *EnvelopeV2Handlers) NotifyEnvelopeV2Handler(c *gin.Context) {
 	}
 
 	// Obter provider
-	envelopeProvider, err := h.ProviderFactory.GetProvider(providerName)
+	envelopeProvider, err := h.ProviderFactory.GetProviderForTenant(envelope.TenantID, providerName)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
 			Error:   "Internal server error",
@@ -1252,6 +1460,7 @@ func (h *EnvelopeV2Handlers) NotifyEnvelopeV2Handler(c *gin.Context) {
 
 // ActivateEnvelopeByKeyV2Handler ativa um envelope pelo provider key (clicksign_key).
 // Funciona para Clicksign e VertSign; o identificador é o mesmo armazenado em clicksign_key.
+// @Failure 403 {object} dtos.ErrorResponseDTO "Usuário sem a permissão envelopes:activate (papéis approver e admin)"
 // @Router /api/v2/envelopes/by-key/:key/activate [post]
 func (h *EnvelopeV2Handlers) ActivateEnvelopeByKeyV2Handler(c *gin.Context) {
 	correlationID := c.GetHeader("X-Correlation-ID")
@@ -1287,8 +1496,8 @@ func (h *EnvelopeV2Handlers) ActivateEnvelopeByKeyV2Handler(c *gin.Context) {
 		return
 	}
 
-	providerName := "clicksign"
-	envelopeProvider, err := h.ProviderFactory.GetProvider(providerName)
+	providerName := envelopeProviderName(envelope)
+	envelopeProvider, err := h.ProviderFactory.GetProviderForTenant(envelope.TenantID, providerName)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
 			Error:   "Internal server error",
@@ -1374,8 +1583,8 @@ func (h *EnvelopeV2Handlers) NotifyEnvelopeByKeyV2Handler(c *gin.Context) {
 		return
 	}
 
-	providerName := "clicksign"
-	envelopeProvider, err := h.ProviderFactory.GetProvider(providerName)
+	providerName := envelopeProviderName(envelope)
+	envelopeProvider, err := h.ProviderFactory.GetProviderForTenant(envelope.TenantID, providerName)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
 			Error:   "Internal server error",
@@ -1416,6 +1625,192 @@ func (h *EnvelopeV2Handlers) NotifyEnvelopeByKeyV2Handler(c *gin.Context) {
 	c.JSON(http.StatusOK, responseDTO)
 }
 
+// @Summary Replace envelope document (v2)
+// @Description Replace the content of a document while the envelope is still a draft. The previous content is kept as a document version (GET /api/v1/documents/{id}/versions). At the provider the document is deleted and created again, and the requirements that referenced it are recreated. Only providers with supports_document_replace accept it
+// @Tags envelopes-v2
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Envelope ID"
+// @Param document_id path int true "Document ID"
+// @Param request body dtos.DocumentReplaceRequestDTO true "New document content"
+// @Success 200 {object} dtos.DocumentResponseDTO "Document replaced"
+// @Failure 400 {object} dtos.ErrorResponseDTO "Validation error or provider without document replacement"
+// @Failure 404 {object} dtos.ErrorResponseDTO "Envelope or document not found"
+// @Failure 409 {object} dtos.ErrorResponseDTO "Envelope is not a draft"
+// @Failure 422 {object} dtos.ErrorResponseDTO "Document could not be converted, was rejected or is infected"
+// @Failure 503 {object} dtos.ErrorResponseDTO "Malware scanner or document ingestion unavailable"
+// @Failure 500 {object} dtos.ErrorResponseDTO "Internal server error"
+// @Router /api/v2/envelopes/{id}/documents/{document_id} [put]
+func (h *EnvelopeV2Handlers) ReplaceEnvelopeDocumentV2Handler(c *gin.Context) {
+	correlationID := c.GetHeader("X-Correlation-ID")
+	if correlationID == "" {
+		correlationID = strconv.FormatInt(time.Now().Unix(), 10)
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Invalid ID",
+			Message: "Envelope ID must be a valid integer",
+		})
+		return
+	}
+
+	documentID, err := strconv.Atoi(c.Param("document_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Invalid ID",
+			Message: "Document ID must be a valid integer",
+		})
+		return
+	}
+
+	var requestDTO dtos.DocumentReplaceRequestDTO
+	if err := c.ShouldBindJSON(&requestDTO); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ValidationErrorResponseDTO{
+			Error:   "Validation failed",
+			Message: "Invalid request payload",
+			Details: h.extractValidationErrors(err),
+		})
+		return
+	}
+
+	if err := requestDTO.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	envelope, err := h.RepositoryEnvelope.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, dtos.ErrorResponseDTO{
+			Error:   "Envelope not found",
+			Message: "The requested envelope does not exist",
+		})
+		return
+	}
+
+	providerName := envelopeProviderName(envelope)
+	registration, ok := provider.Lookup(providerName)
+	if !ok || !registration.Capabilities.SupportsDocumentReplace {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Bad Request",
+			Message: fmt.Sprintf("Provider '%s' does not support document replacement", providerName),
+		})
+		return
+	}
+
+	replacement, err := h.mapReplaceRequestToEntity(c.Request.Context(), requestDTO)
+	if err == nil && !registration.Capabilities.SupportsMimeType(replacement.MimeType) {
+		err = fmt.Errorf("provider '%s' does not accept %s documents", providerName, replacement.MimeType)
+	}
+	if err != nil {
+		h.Logger.WithFields(logrus.Fields{
+			"correlation_id": correlationID,
+			"envelope_id":    id,
+			"document_id":    documentID,
+			"error":          err.Error(),
+		}).Error("Failed to process replacement document")
+
+		switch {
+		case errors.Is(err, document.ErrConversion):
+			c.JSON(http.StatusUnprocessableEntity, dtos.ErrorResponseDTO{Error: "Conversion failed", Message: err.Error()})
+		case errors.Is(err, document.ErrInfected):
+			c.JSON(http.StatusUnprocessableEntity, dtos.ErrorResponseDTO{Error: "Document infected", Message: err.Error()})
+		case errors.Is(err, document.ErrScanFailed):
+			c.JSON(http.StatusServiceUnavailable, dtos.ErrorResponseDTO{Error: "Malware scan unavailable", Message: err.Error()})
+		case errors.Is(err, document.ErrNotConfigured):
+			c.JSON(http.StatusServiceUnavailable, dtos.ErrorResponseDTO{Error: "Document ingestion unavailable", Message: err.Error()})
+		case errors.Is(err, document.ErrNormalization):
+			c.JSON(http.StatusUnprocessableEntity, dtos.ErrorResponseDTO{Error: "Document rejected", Message: err.Error()})
+		default:
+			if details, ok := fetchValidationDetails(err); ok {
+				c.JSON(http.StatusBadRequest, dtos.ValidationErrorResponseDTO{
+					Error:   "Validation failed",
+					Message: err.Error(),
+					Details: details,
+				})
+				return
+			}
+			c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{Error: "Invalid request", Message: err.Error()})
+		}
+		return
+	}
+
+	envelopeProvider, err := h.ProviderFactory.GetProviderForTenant(envelope.TenantID, providerName)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+			Error:   "Internal server error",
+			Message: fmt.Sprintf("Failed to get provider: %v", err),
+			Details: map[string]interface{}{
+				"correlation_id": correlationID,
+				"provider":       providerName,
+			},
+		})
+		return
+	}
+
+	envelopeProviderService := usecase_envelope.NewUsecaseEnvelopeProviderService(
+		h.RepositoryEnvelope,
+		envelopeProvider,
+		h.UsecaseDocuments,
+		h.UsecaseRequirement,
+		h.Logger,
+	)
+
+	document, err := envelopeProviderService.ReplaceDocument(c.Request.Context(), id, documentID, replacement)
+	if err != nil {
+		h.Logger.WithFields(logrus.Fields{
+			"correlation_id": correlationID,
+			"provider":       providerName,
+			"envelope_id":    id,
+			"document_id":    documentID,
+			"error":          err.Error(),
+		}).Error("Failed to replace envelope document")
+
+		switch {
+		case errors.Is(err, usecase_envelope.ErrEnvelopeNotDraft):
+			c.JSON(http.StatusConflict, dtos.ErrorResponseDTO{Error: "Conflict", Message: err.Error()})
+		case errors.Is(err, usecase_envelope.ErrDocumentNotInEnvelope):
+			c.JSON(http.StatusNotFound, dtos.ErrorResponseDTO{Error: "Document not found", Message: err.Error()})
+		case errors.Is(err, entity.ErrDocumentQuarantined):
+			c.JSON(http.StatusUnprocessableEntity, dtos.ErrorResponseDTO{Error: "Document infected", Message: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+				Error:   "Internal server error",
+				Message: "Failed to replace document: " + err.Error(),
+				Details: map[string]interface{}{
+					"correlation_id": correlationID,
+					"provider":       providerName,
+				},
+			})
+		}
+		return
+	}
+
+	h.Logger.WithFields(logrus.Fields{
+		"correlation_id": correlationID,
+		"provider":       providerName,
+		"envelope_id":    id,
+		"document_id":    documentID,
+		"version":        document.CurrentVersion(),
+	}).Info("Envelope document replaced")
+
+	c.JSON(http.StatusOK, mapDocumentToResponse(document))
+}
+
+// envelopeProviderName retorna o provider que mantém o envelope
+// Envelopes criados antes do registro do provider são tratados como Clicksign
+func envelopeProviderName(envelope *entity.EntityEnvelope) string {
+	if envelope.Provider != "" {
+		return envelope.Provider
+	}
+	return "clicksign"
+}
+
 // mapEntityToResponseV2 converte EntityEnvelope para DTO de resposta (reutiliza lógica do v1)
 func (h *EnvelopeV2Handlers) mapEntityToResponseV2(envelope *entity.EntityEnvelope, signatories ...[]entity.EntitySignatory) *dtos.EnvelopeResponseDTO {
 	response := &dtos.EnvelopeResponseDTO{
@@ -1423,6 +1818,7 @@ func (h *EnvelopeV2Handlers) mapEntityToResponseV2(envelope *entity.EntityEnvelo
 		Name:             envelope.Name,
 		Description:      envelope.Description,
 		Status:           envelope.Status,
+		Provider:         envelope.Provider,
 		ClicksignKey:     envelope.ClicksignKey,
 		ClicksignRawData: envelope.ClicksignRawData,
 		DocumentsIDs:     envelope.DocumentsIDs,
@@ -1431,6 +1827,8 @@ func (h *EnvelopeV2Handlers) mapEntityToResponseV2(envelope *entity.EntityEnvelo
 		DeadlineAt:       envelope.DeadlineAt,
 		RemindInterval:   envelope.RemindInterval,
 		AutoClose:        envelope.AutoClose,
+		CreatedByID:      envelope.CreatedByID,
+		ApprovalTrail:    newEnvelopeApprovalDTOs(envelope.ApprovalTrail),
 		CreatedAt:        envelope.CreatedAt,
 		UpdatedAt:        envelope.UpdatedAt,
 	}
@@ -1462,7 +1860,7 @@ func (h *EnvelopeV2Handlers) mapEnvelopeListToResponseV2(envelopes []entity.Enti
 
 // mapCreateRequestToEntityV2 converte EnvelopeV2CreateRequestDTO para EntityEnvelope e documentos
 // Reutiliza a mesma lógica do handler v1
-func (h *EnvelopeV2Handlers) mapCreateRequestToEntityV2(dto dtos.EnvelopeV2CreateRequestDTO) (*entity.EntityEnvelope, []*entity.EntityDocument, error) {
+func (h *EnvelopeV2Handlers) mapCreateRequestToEntityV2(ctx context.Context, dto dtos.EnvelopeV2CreateRequestDTO) (*entity.EntityEnvelope, []*entity.EntityDocument, error) {
 	// Determinar emails dos signatários com base no formato usado
 	var signatoryEmails []string
 	if len(dto.SignatoryEmails) > 0 {
@@ -1498,13 +1896,15 @@ func (h *EnvelopeV2Handlers) mapCreateRequestToEntityV2(dto dtos.EnvelopeV2Creat
 	// Processar documentos (URL ou base64) se fornecidos
 	for _, docRequest := range dto.Documents {
 		var fileInfo *utils.Base64FileInfo
+		var templateSource *entity.DocumentTemplateSource
+		var merged *document.MergedParts
 		var err error
 		var isFromBase64 bool
 
 		// Verificar se é URL ou base64
 		if strings.TrimSpace(docRequest.FileURL) != "" {
 			// Processar URL
-			fileInfo, err = utils.DownloadFileFromURL(docRequest.FileURL)
+			fileInfo, err = utils.DownloadFileFromURL(h.DocumentFetcher, docRequest.FileURL)
 			if err != nil {
 				return nil, nil, fmt.Errorf("failed to download file from URL for document '%s': %w", docRequest.Name, err)
 			}
@@ -1516,8 +1916,27 @@ func (h *EnvelopeV2Handlers) mapCreateRequestToEntityV2(dto dtos.EnvelopeV2Creat
 				return nil, nil, fmt.Errorf("failed to process base64 content for document '%s': %w", docRequest.Name, err)
 			}
 			isFromBase64 = true
+		} else if docRequest.TemplateID != 0 {
+			// Gerar o PDF a partir do template; o resultado segue o mesmo fluxo de um base64
+			fileInfo, templateSource, err = h.generateFromTemplate(ctx, docRequest)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to generate document '%s' from template %d: %w", docRequest.Name, docRequest.TemplateID, err)
+			}
+			isFromBase64 = true
+		} else if len(docRequest.Parts) > 0 {
+			// Juntar as partes em um único PDF; o resultado segue o mesmo fluxo de um base64
+			merged, err = h.mergeParts(ctx, docRequest)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to merge parts of document '%s': %w", docRequest.Name, err)
+			}
+			fileInfo = &utils.Base64FileInfo{
+				DecodedData: merged.Result.Data,
+				MimeType:    "application/pdf",
+				Size:        int64(len(merged.Result.Data)),
+			}
+			isFromBase64 = true
 		} else {
-			return nil, nil, fmt.Errorf("document '%s' must provide either file_url or file_content_base64", docRequest.Name)
+			return nil, nil, fmt.Errorf("document '%s' must provide file_url, file_content_base64, template_id or parts", docRequest.Name)
 		}
 
 		// Validar MIME type
@@ -1538,7 +1957,7 @@ func (h *EnvelopeV2Handlers) mapCreateRequestToEntityV2(dto dtos.EnvelopeV2Creat
 		}
 
 		// Se veio de URL, manter a URL no FilePath para vert-sign
-		// Se veio de base64, usar o tempPath para Clicksign
+		// Se veio de base64, o FilePath passa a apontar para o original no storage
 		filePath := fileInfo.TempPath
 		if !isFromBase64 {
 			// Para URL, manter a URL original no FilePath (será usada diretamente pelo vert-sign)
@@ -1556,12 +1975,168 @@ func (h *EnvelopeV2Handlers) mapCreateRequestToEntityV2(dto dtos.EnvelopeV2Creat
 			Metadata:     metadataJSON,
 		}
 
+		// Gravar o original no storage para permitir reenvio e auditoria
+		if merged != nil {
+			err = h.UsecaseDocumentIngestion.StoreMergedParts(ctx, document, merged)
+		} else {
+			err = h.UsecaseDocumentIngestion.StoreOriginal(ctx, document, fileInfo)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to store document '%s': %w", docRequest.Name, err)
+		}
+
+		if templateSource != nil {
+			if err := document.SetTemplateSource(*templateSource); err != nil {
+				return nil, nil, fmt.Errorf("failed to record template for document '%s': %w", docRequest.Name, err)
+			}
+		}
+
 		documents = append(documents, document)
 	}
 
+	// Documentos enviados previamente (ex.: upload multipart em POST /api/v1/documents)
+	// Os IDs são vinculados ao envelope à medida que cada documento é enviado ao provider
+	if len(dto.DocumentsIDs) > 0 {
+		uploadedDocuments, err := h.loadUploadedDocuments(dto.DocumentsIDs)
+		if err != nil {
+			return nil, nil, err
+		}
+		documents = append(documents, uploadedDocuments...)
+		envelope.DocumentsIDs = nil
+	}
+
 	return envelope, documents, nil
 }
 
+// mapReplaceRequestToEntity baixa ou decodifica o novo conteúdo e o grava no storage, com a mesma verificação,
+// normalização e conversão da criação de envelopes
+func (h *EnvelopeV2Handlers) mapReplaceRequestToEntity(ctx context.Context, dto dtos.DocumentReplaceRequestDTO) (*entity.EntityDocument, error) {
+	var fileInfo *utils.Base64FileInfo
+	var err error
+	isFromBase64 := dto.FileURL == ""
+	if isFromBase64 {
+		fileInfo, err = utils.DecodeBase64File(dto.FileContentBase64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to process base64 content: %w", err)
+		}
+	} else {
+		fileInfo, err = utils.DownloadFileFromURL(h.DocumentFetcher, dto.FileURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download file from URL: %w", err)
+		}
+	}
+
+	if err := utils.ValidateMimeType(fileInfo.MimeType); err != nil {
+		utils.CleanupTempFile(fileInfo.TempPath)
+		return nil, fmt.Errorf("unsupported file type: %w", err)
+	}
+
+	var metadataJSON datatypes.JSON
+	if dto.Metadata != nil {
+		metadataBytes, err := json.Marshal(dto.Metadata)
+		if err != nil {
+			utils.CleanupTempFile(fileInfo.TempPath)
+			return nil, fmt.Errorf("failed to marshal metadata: %w", err)
+		}
+		metadataJSON = datatypes.JSON(metadataBytes)
+	}
+
+	filePath := fileInfo.TempPath
+	if !isFromBase64 {
+		filePath = dto.FileURL
+	}
+
+	document := &entity.EntityDocument{
+		FilePath:     filePath,
+		FileSize:     fileInfo.Size,
+		MimeType:     fileInfo.MimeType,
+		IsFromBase64: isFromBase64,
+		Metadata:     metadataJSON,
+	}
+	if err := h.UsecaseDocumentIngestion.StoreOriginal(ctx, document, fileInfo); err != nil {
+		return nil, fmt.Errorf("failed to store document: %w", err)
+	}
+
+	return document, nil
+}
+
+// generateFromTemplate renderiza o template_id com as variáveis do documento
+// Falhas na geração do PDF são marcadas com errTemplateRendering; template inexistente e variáveis faltando são erros do request
+func (h *EnvelopeV2Handlers) generateFromTemplate(ctx context.Context, docRequest dtos.EnvelopeDocumentRequest) (*utils.Base64FileInfo, *entity.DocumentTemplateSource, error) {
+	if h.UsecaseDocumentTemplates == nil {
+		return nil, nil, errors.New("document templates are not available")
+	}
+
+	data, template, err := h.UsecaseDocumentTemplates.GeneratePDF(ctx, docRequest.TemplateID, docRequest.Variables)
+	if err != nil {
+		if template == nil {
+			return nil, nil, fmt.Errorf("template not found: %w", err)
+		}
+		if errors.Is(err, entity.ErrMissingTemplateVariables) {
+			return nil, nil, err
+		}
+		return nil, nil, fmt.Errorf("%w: %v", errTemplateRendering, err)
+	}
+
+	fileInfo := &utils.Base64FileInfo{
+		DecodedData: data,
+		MimeType:    "application/pdf",
+		Size:        int64(len(data)),
+	}
+	source := &entity.DocumentTemplateSource{
+		TemplateID:   template.ID,
+		TemplateName: template.Name,
+		Variables:    docRequest.Variables,
+		RenderedAt:   time.Now(),
+	}
+	return fileInfo, source, nil
+}
+
+// mergeParts baixa ou decodifica cada parte e as junta, na ordem, em um único PDF
+func (h *EnvelopeV2Handlers) mergeParts(ctx context.Context, docRequest dtos.EnvelopeDocumentRequest) (*document.MergedParts, error) {
+	parts := make([]normalizer.Part, 0, len(docRequest.Parts))
+	for i, partRequest := range docRequest.Parts {
+		var fileInfo *utils.Base64FileInfo
+		var err error
+		if strings.TrimSpace(partRequest.FileURL) != "" {
+			fileInfo, err = utils.DownloadFileFromURL(h.DocumentFetcher, partRequest.FileURL)
+		} else {
+			fileInfo, err = utils.DecodeBase64File(partRequest.FileContentBase64)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("part %d: %w", i+1, err)
+		}
+		utils.CleanupTempFile(fileInfo.TempPath)
+
+		parts = append(parts, normalizer.Part{Data: fileInfo.DecodedData, MimeType: fileInfo.MimeType})
+	}
+
+	return h.UsecaseDocumentIngestion.MergeParts(ctx, parts)
+}
+
+// loadUploadedDocuments busca documentos já persistidos que ainda não foram enviados a um provider
+func (h *EnvelopeV2Handlers) loadUploadedDocuments(documentIDs []int) ([]*entity.EntityDocument, error) {
+	documents := make([]*entity.EntityDocument, 0, len(documentIDs))
+	for _, documentID := range documentIDs {
+		uploaded, err := h.UsecaseDocuments.GetDocument(documentID)
+		if err != nil {
+			return nil, fmt.Errorf("document %d not found: %w", documentID, err)
+		}
+
+		if uploaded.IsQuarantined() {
+			return nil, fmt.Errorf("%w: document %d is quarantined", document.ErrInfected, documentID)
+		}
+
+		if uploaded.ClicksignKey != "" || uploaded.Status == "sent" {
+			return nil, fmt.Errorf("document %d was already sent to a provider", documentID)
+		}
+
+		documents = append(documents, uploaded)
+	}
+
+	return documents, nil
+}
+
 // extractValidationErrors extrai erros de validação (reutiliza do handler v1)
 func (h *EnvelopeV2Handlers) extractValidationErrors(err error) []dtos.ValidationErrorDetail {
 	var validationErrors []dtos.ValidationErrorDetail
@@ -1601,63 +2176,90 @@ func (h *EnvelopeV2Handlers) getValidationErrorMessage(fieldError validator.Fiel
 }
 
 // MountEnvelopeV2Handlers monta as rotas v2 de envelopes
-func MountEnvelopeV2Handlers(gin *gin.Engine, conn *gorm.DB, logger *logrus.Logger) {
-	// Criar factory de providers
-	providerFactory := provider_factory.NewProviderFactory(config.EnvironmentVariables, logger)
-
-	// Criar usecase de documento
-	clicksignClient := clicksign.NewClicksignClient(config.EnvironmentVariables, logger)
-	usecaseDocument := document.NewUsecaseDocumentServiceWithClicksign(
-		repository.NewRepositoryDocument(conn),
-		clicksignClient,
-		logger,
-	)
-
-	// Criar usecase de requirement
-	usecaseRequirement := requirement.NewUsecaseRequirementService(
-		repository.NewRepositoryRequirement(conn),
-		repository.NewRepositoryEnvelope(conn),
-		clicksignClient,
-		logger,
-	)
-
-	// Criar usecase de signatory
-	usecaseSignatory := signatory.NewUsecaseSignatoryService(
-		repository.NewRepositorySignatory(conn),
-		repository.NewRepositoryEnvelope(conn),
-		clicksignClient,
-		logger,
-	)
-
-	vertcAssinaturasClient := vertc_assinaturas.NewVertcAssinaturasClient(config.EnvironmentVariables, logger)
-	vertcAutomaticSignature := vertc_assinaturas.NewAutomaticSignatureService(vertcAssinaturasClient, logger)
-
-	// Criar repositories
-	repositoryEnvelope := repository.NewRepositoryEnvelope(conn)
-	repositorySignatory := repository.NewRepositorySignatory(conn)
-	repositoryRequirement := repository.NewRepositoryRequirement(conn)
-
-	envelopeV2Handlers := NewEnvelopeV2Handler(
-		providerFactory,
-		vertcAutomaticSignature,
-		usecaseDocument,
-		usecaseRequirement,
-		usecaseSignatory,
-		repositoryEnvelope,
-		repositorySignatory,
-		repositoryRequirement,
-		logger,
-	)
+func MountEnvelopeV2Handlers(gin *gin.Engine, conn *gorm.DB, deps *dependencies.Dependencies) {
+	logger := deps.Logger
+	documentIngestion := newDocumentIngestion(deps)
+
+	// Handlers por tenant: envelopes, documentos e credenciais de provider ficam restritos ao tenant da requisição
+	envelopeV2Handlers := newTenantHandlers(conn, func(conn *gorm.DB) *EnvelopeV2Handlers {
+		// Criar factory de providers com suporte a credenciais por tenant
+		providerFactory := provider_factory.NewProviderFactoryWithCredentials(
+			config.EnvironmentVariables,
+			repository.NewRepositoryProviderCredential(conn),
+			deps.Storage,
+			deps.Fetcher,
+			logger,
+		)
+
+		// Criar usecase de documento
+		clicksignClient := clicksign.NewClicksignClient(config.EnvironmentVariables, logger)
+		usecaseDocument := document.NewUsecaseDocumentServiceWithClicksign(
+			repository.NewRepositoryDocument(conn),
+			clicksignClient,
+			deps.Storage,
+			deps.Fetcher,
+			logger,
+		)
+
+		// Criar usecase de requirement
+		usecaseRequirement := requirement.NewUsecaseRequirementService(
+			repository.NewRepositoryRequirement(conn),
+			repository.NewRepositoryEnvelope(conn),
+			clicksignClient,
+			logger,
+		)
+
+		// Criar usecase de signatory
+		usecaseSignatory := signatory.NewUsecaseSignatoryService(
+			repository.NewRepositorySignatory(conn),
+			repository.NewRepositoryEnvelope(conn),
+			clicksignClient,
+			logger,
+		)
+
+		vertcAssinaturasClient := vertc_assinaturas.NewVertcAssinaturasClient(config.EnvironmentVariables, logger)
+		vertcAutomaticSignature := vertc_assinaturas.NewAutomaticSignatureService(vertcAssinaturasClient, logger)
+
+		// Criar repositories
+		repositoryEnvelope := repository.NewRepositoryEnvelope(conn)
+		repositorySignatory := repository.NewRepositorySignatory(conn)
+		repositoryRequirement := repository.NewRepositoryRequirement(conn)
+
+		envelopeV2Handlers := NewEnvelopeV2Handler(
+			providerFactory,
+			vertcAutomaticSignature,
+			usecaseDocument,
+			usecaseRequirement,
+			usecaseSignatory,
+			repositoryEnvelope,
+			repositorySignatory,
+			repositoryRequirement,
+			logger,
+		)
+		envelopeV2Handlers.UsecaseDocumentTemplates = usecase_document_template.NewUsecaseDocumentTemplateService(
+			repository.NewRepositoryDocumentTemplate(conn),
+			deps.Converter,
+			logger,
+		)
+		envelopeV2Handlers.UsecaseDocumentIngestion = documentIngestion
+		envelopeV2Handlers.DocumentFetcher = deps.Fetcher
+		envelopeV2Handlers.ApprovalRequired = config.EnvironmentVariables.ENVELOPE_APPROVAL_REQUIRED
+		return envelopeV2Handlers
+	})
 
 	group := gin.Group("/api/v2/envelopes")
-	SetAuthMiddleware(conn, group)
+	SetScopedAuthMiddleware(conn, deps, group, entity.ScopeEnvelopesRead, entity.ScopeEnvelopesWrite)
+	group.Use(requireEnvelopeOwner(newEnvelopeOwnership(conn), envelopeFromRoute))
 
-	group.POST("/", envelopeV2Handlers.CreateEnvelopeV2Handler)
-	group.GET("/:id", envelopeV2Handlers.GetEnvelopeV2Handler)
-	group.GET("/", envelopeV2Handlers.GetEnvelopesV2Handler)
+	group.POST("/", envelopeV2Handlers.Handle((*EnvelopeV2Handlers).CreateEnvelopeV2Handler))
+	group.GET("/:id", envelopeV2Handlers.Handle((*EnvelopeV2Handlers).GetEnvelopeV2Handler))
+	group.GET("/", envelopeV2Handlers.Handle((*EnvelopeV2Handlers).GetEnvelopesV2Handler))
 	// Rotas por provider key (clicksign_key) — antes das rotas por :id para não capturar "by-key" como id
-	group.POST("/by-key/:key/activate", envelopeV2Handlers.ActivateEnvelopeByKeyV2Handler)
-	group.POST("/by-key/:key/notify", envelopeV2Handlers.NotifyEnvelopeByKeyV2Handler)
-	group.POST("/:id/activate", envelopeV2Handlers.ActivateEnvelopeV2Handler)
-	group.POST("/:id/notify", envelopeV2Handlers.NotifyEnvelopeV2Handler)
+	group.POST("/by-key/:key/activate", middleware.RequirePermissionMiddleware(entity.PermissionEnvelopesActivate), envelopeV2Handlers.Handle((*EnvelopeV2Handlers).ActivateEnvelopeByKeyV2Handler))
+	group.POST("/by-key/:key/notify", envelopeV2Handlers.Handle((*EnvelopeV2Handlers).NotifyEnvelopeByKeyV2Handler))
+	group.POST("/:id/activate", middleware.RequirePermissionMiddleware(entity.PermissionEnvelopesActivate), envelopeV2Handlers.Handle((*EnvelopeV2Handlers).ActivateEnvelopeV2Handler))
+	group.POST("/:id/notify", envelopeV2Handlers.Handle((*EnvelopeV2Handlers).NotifyEnvelopeV2Handler))
+	group.POST("/:id/approve", middleware.RequirePermissionMiddleware(entity.PermissionEnvelopesApprove), envelopeV2Handlers.Handle((*EnvelopeV2Handlers).ApproveEnvelopeV2Handler))
+	group.POST("/:id/reject", middleware.RequirePermissionMiddleware(entity.PermissionEnvelopesApprove), envelopeV2Handlers.Handle((*EnvelopeV2Handlers).RejectEnvelopeV2Handler))
+	group.PUT("/:id/documents/:document_id", middleware.RequirePermissionMiddleware(entity.ScopeDocumentsWrite), envelopeV2Handlers.Handle((*EnvelopeV2Handlers).ReplaceEnvelopeDocumentV2Handler))
 }
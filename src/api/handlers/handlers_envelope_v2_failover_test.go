@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"app/api/handlers/dtos"
+	"app/config"
+	"app/entity"
+	"app/infrastructure/clicksign"
+	"app/infrastructure/provider"
+	"app/infrastructure/provider_factory"
+	"app/infrastructure/storage"
+	"app/mocks"
+	"app/pkg/fetcher"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failoverTestProviders contém as instâncias devolvidas pelos providers de teste registrados abaixo
+var failoverTestProviders = map[string]provider.EnvelopeProvider{}
+
+func init() {
+	capabilities := provider.Capabilities{
+		AuthMethods: []string{"email"},
+		Actions:     []string{"agree", "sign"},
+		MimeTypes:   []string{"application/pdf"},
+	}
+	for _, name := range []string{"failover-primary", "failover-secondary"} {
+		providerName := name
+		provider.Register(providerName, func(envVars config.EnvironmentVars, credentials *provider.Credentials, documentStorage storage.Storage, documentFetcher *fetcher.Fetcher, logger *logrus.Logger) (provider.EnvelopeProvider, error) {
+			return failoverTestProviders[providerName], nil
+		}, capabilities)
+	}
+}
+
+func newFailoverTestHandler(ctrl *gomock.Controller, repositoryEnvelope *mocks.MockIRepositoryEnvelope) *EnvelopeV2Handlers {
+	return NewEnvelopeV2Handler(
+		provider_factory.NewProviderFactory(config.EnvironmentVars{}, nil, nil, logrus.New()),
+		nil,
+		mocks.NewMockIUsecaseDocument(ctrl),
+		mocks.NewMockIUsecaseRequirement(ctrl),
+		mocks.NewMockIUsecaseSignatory(ctrl),
+		repositoryEnvelope,
+		mocks.NewMockIRepositorySignatory(ctrl),
+		mocks.NewMockIRepositoryRequirement(ctrl),
+		logrus.New(),
+	)
+}
+
+func performFailoverRequest(t *testing.T, handler *EnvelopeV2Handlers, body map[string]interface{}) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v2/envelopes", handler.CreateEnvelopeV2Handler)
+
+	jsonData, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/v2/envelopes", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCreateEnvelopeV2Handler_Failover(t *testing.T) {
+	requestBody := map[string]interface{}{
+		"provider":           "failover-primary",
+		"fallback_providers": []string{"failover-secondary"},
+		"name":               "Envelope com failover",
+		"documents_ids":      []int{1},
+		"signatory_emails":   []string{"assinante@empresa.com"},
+	}
+
+	t.Run("should create envelope on fallback provider when primary is unavailable", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		primary := mocks.NewMockEnvelopeProvider(ctrl)
+		secondary := mocks.NewMockEnvelopeProvider(ctrl)
+		failoverTestProviders["failover-primary"] = primary
+		failoverTestProviders["failover-secondary"] = secondary
+
+		repositoryEnvelope := mocks.NewMockIRepositoryEnvelope(ctrl)
+		nextID := 0
+		repositoryEnvelope.EXPECT().Create(gomock.Any()).DoAndReturn(func(envelope *entity.EntityEnvelope) error {
+			nextID++
+			envelope.ID = nextID
+			return nil
+		}).Times(2)
+		repositoryEnvelope.EXPECT().Delete(gomock.Any()).Return(nil).Times(1)
+		repositoryEnvelope.EXPECT().Update(gomock.Any()).Return(nil).Times(2)
+		repositoryEnvelope.EXPECT().GetByID(2).Return(&entity.EntityEnvelope{ID: 2, Status: "draft"}, nil)
+
+		primary.EXPECT().CreateEnvelope(gomock.Any(), gomock.Any()).
+			Return("", "", &clicksign.ClicksignError{Type: clicksign.ErrorTypeServer, Message: "unavailable", StatusCode: http.StatusServiceUnavailable})
+		secondary.EXPECT().CreateEnvelope(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ interface{}, envelope *entity.EntityEnvelope) (string, string, error) {
+				assert.Equal(t, "failover-secondary", envelope.Provider)
+				assert.Equal(t, 2, envelope.ID, "retry must create a fresh local envelope")
+				return "secondary-key", "{}", nil
+			})
+
+		// documents_ids referencia um documento já enviado via POST /api/v1/documents
+		handler := newFailoverTestHandler(ctrl, repositoryEnvelope)
+		usecaseDocument := handler.UsecaseDocuments.(*mocks.MockIUsecaseDocument)
+		usecaseDocument.EXPECT().GetDocument(1).Return(&entity.EntityDocument{ID: 1, Name: "contrato.pdf", Status: "draft"}, nil)
+		secondary.EXPECT().CreateDocument(gomock.Any(), "secondary-key", gomock.Any(), 2).Return("secondary-doc-key", nil)
+		usecaseDocument.EXPECT().Update(gomock.Any()).DoAndReturn(func(document *entity.EntityDocument) error {
+			assert.Equal(t, "secondary-doc-key", document.ClicksignKey)
+			return nil
+		})
+
+		w := performFailoverRequest(t, handler, requestBody)
+
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var response dtos.EnvelopeResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "failover-secondary", response.Provider)
+		assert.Equal(t, "secondary-key", response.ClicksignKey)
+		assert.Equal(t, []int{1}, response.DocumentsIDs)
+	})
+
+	t.Run("should not fail over when the primary may have created the envelope", func(t *testing.T) {
+		for name, providerErr := range map[string]error{
+			"timeout":     &clicksign.ClicksignError{Type: clicksign.ErrorTypeTimeout, Message: "HTTP request failed", Original: context.DeadlineExceeded},
+			"bad gateway": &clicksign.ClicksignError{Type: clicksign.ErrorTypeServer, Message: "server error", StatusCode: http.StatusBadGateway},
+		} {
+			t.Run(name, func(t *testing.T) {
+				ctrl := gomock.NewController(t)
+				defer ctrl.Finish()
+
+				primary := mocks.NewMockEnvelopeProvider(ctrl)
+				secondary := mocks.NewMockEnvelopeProvider(ctrl)
+				failoverTestProviders["failover-primary"] = primary
+				failoverTestProviders["failover-secondary"] = secondary
+
+				repositoryEnvelope := mocks.NewMockIRepositoryEnvelope(ctrl)
+				repositoryEnvelope.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+				repositoryEnvelope.EXPECT().Delete(gomock.Any()).Return(nil).Times(1)
+
+				primary.EXPECT().CreateEnvelope(gomock.Any(), gomock.Any()).Return("", "", providerErr)
+
+				handler := newFailoverTestHandler(ctrl, repositoryEnvelope)
+				handler.UsecaseDocuments.(*mocks.MockIUsecaseDocument).EXPECT().GetDocument(1).Return(&entity.EntityDocument{ID: 1, Name: "contrato.pdf", Status: "draft"}, nil)
+
+				w := performFailoverRequest(t, handler, requestBody)
+
+				assert.NotEqual(t, http.StatusCreated, w.Code)
+			})
+		}
+	})
+
+	t.Run("should not fail over on client errors", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		primary := mocks.NewMockEnvelopeProvider(ctrl)
+		secondary := mocks.NewMockEnvelopeProvider(ctrl)
+		failoverTestProviders["failover-primary"] = primary
+		failoverTestProviders["failover-secondary"] = secondary
+
+		repositoryEnvelope := mocks.NewMockIRepositoryEnvelope(ctrl)
+		repositoryEnvelope.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+		repositoryEnvelope.EXPECT().Delete(gomock.Any()).Return(nil).Times(1)
+
+		primary.EXPECT().CreateEnvelope(gomock.Any(), gomock.Any()).
+			Return("", "", &clicksign.ClicksignError{Type: clicksign.ErrorTypeClient, Message: "invalid payload", StatusCode: http.StatusUnprocessableEntity})
+
+		handler := newFailoverTestHandler(ctrl, repositoryEnvelope)
+		handler.UsecaseDocuments.(*mocks.MockIUsecaseDocument).EXPECT().GetDocument(1).Return(&entity.EntityDocument{ID: 1, Name: "contrato.pdf", Status: "draft"}, nil)
+
+		w := performFailoverRequest(t, handler, requestBody)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+}
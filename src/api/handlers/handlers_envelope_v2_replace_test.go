@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"app/api/handlers/dtos"
+	"app/config"
+	"app/entity"
+	"app/infrastructure/provider"
+	"app/infrastructure/storage"
+	"app/mocks"
+	"app/pkg/fetcher"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// replaceTestProvider é a instância devolvida pelo provider de teste com suporte a substituição de documentos
+var replaceTestProvider provider.EnvelopeProvider
+
+func init() {
+	provider.Register("replace-test", func(envVars config.EnvironmentVars, credentials *provider.Credentials, documentStorage storage.Storage, documentFetcher *fetcher.Fetcher, logger *logrus.Logger) (provider.EnvelopeProvider, error) {
+		return replaceTestProvider, nil
+	}, provider.Capabilities{
+		Actions:                 []string{"sign"},
+		SupportsDocumentReplace: true,
+		MimeTypes:               []string{"application/pdf"},
+	})
+}
+
+func performReplaceRequest(t *testing.T, handler *EnvelopeV2Handlers, path string, body map[string]interface{}) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.PUT("/api/v2/envelopes/:id/documents/:document_id", handler.ReplaceEnvelopeDocumentV2Handler)
+
+	jsonData, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	req, _ := http.NewRequest(http.MethodPut, path, bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestReplaceEnvelopeDocumentV2Handler(t *testing.T) {
+	pdf := base64.StdEncoding.EncodeToString(samplePDF(16, ""))
+
+	t.Run("should replace the document at the provider and keep the previous version", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		envelopeProvider := mocks.NewMockEnvelopeProvider(ctrl)
+		replaceTestProvider = envelopeProvider
+
+		repositoryEnvelope := mocks.NewMockIRepositoryEnvelope(ctrl)
+		envelope := &entity.EntityEnvelope{ID: 1, Status: "draft", Provider: "replace-test", ClicksignKey: "envelope-key", DocumentsIDs: []int{5}}
+		repositoryEnvelope.EXPECT().GetByID(1).Return(envelope, nil).Times(2)
+
+		handler := newFailoverTestHandler(ctrl, repositoryEnvelope)
+		handler.UsecaseDocumentIngestion, _ = newTestIngestion(t, nil, nil)
+		document := &entity.EntityDocument{ID: 5, Name: "Contrato", Status: "draft", ClicksignKey: "doc-old", SHA256: "old"}
+		usecaseDocument := handler.UsecaseDocuments.(*mocks.MockIUsecaseDocument)
+		usecaseDocument.EXPECT().GetDocument(5).Return(document, nil)
+		usecaseDocument.EXPECT().ReplaceContent(document, gomock.Any()).
+			DoAndReturn(func(document, replacement *entity.EntityDocument) (*entity.EntityDocumentVersion, error) {
+				return document.ReplaceContent(replacement)
+			})
+		handler.UsecaseRequirement.(*mocks.MockIUsecaseRequirement).EXPECT().
+			GetRequirementsByEnvelopeID(gomock.Any(), 1).Return(nil, nil)
+
+		envelopeProvider.EXPECT().DeleteDocument(gomock.Any(), "envelope-key", "doc-old").Return(nil)
+		envelopeProvider.EXPECT().CreateDocument(gomock.Any(), "envelope-key", gomock.Any(), 1).Return("doc-new", nil)
+
+		w := performReplaceRequest(t, handler, "/api/v2/envelopes/1/documents/5", map[string]interface{}{"file_content_base64": pdf})
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response dtos.DocumentResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 2, response.Version)
+		assert.Equal(t, "doc-new", response.ClicksignKey)
+		assert.NotEqual(t, "old", response.SHA256)
+	})
+
+	t.Run("should reject envelopes that are no longer drafts", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		replaceTestProvider = mocks.NewMockEnvelopeProvider(ctrl)
+
+		repositoryEnvelope := mocks.NewMockIRepositoryEnvelope(ctrl)
+		envelope := &entity.EntityEnvelope{ID: 1, Status: "sent", Provider: "replace-test", ClicksignKey: "envelope-key", DocumentsIDs: []int{5}}
+		repositoryEnvelope.EXPECT().GetByID(1).Return(envelope, nil).Times(2)
+		handler := newFailoverTestHandler(ctrl, repositoryEnvelope)
+		handler.UsecaseDocumentIngestion, _ = newTestIngestion(t, nil, nil)
+
+		w := performReplaceRequest(t, handler, "/api/v2/envelopes/1/documents/5", map[string]interface{}{"file_content_base64": pdf})
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("should reject providers without document replacement", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repositoryEnvelope := mocks.NewMockIRepositoryEnvelope(ctrl)
+		repositoryEnvelope.EXPECT().GetByID(1).Return(&entity.EntityEnvelope{ID: 1, Status: "draft", Provider: "failover-primary"}, nil)
+
+		w := performReplaceRequest(t, newFailoverTestHandler(ctrl, repositoryEnvelope), "/api/v2/envelopes/1/documents/5", map[string]interface{}{"file_content_base64": pdf})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "does not support document replacement")
+	})
+
+	t.Run("should require exactly one content source", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		body := map[string]interface{}{"file_content_base64": pdf, "file_url": "https://exemplo.com/contrato.pdf"}
+		w := performReplaceRequest(t, newFailoverTestHandler(ctrl, mocks.NewMockIRepositoryEnvelope(ctrl)), "/api/v2/envelopes/1/documents/5", body)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -2,15 +2,27 @@ package handlers
 
 import (
 	"bytes"
+	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"image"
+	"image/png"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"app/api/handlers/dtos"
+	"app/entity"
+	"app/infrastructure/normalizer"
+	"app/infrastructure/storage"
+	"app/mocks"
+	"app/pkg/fetcher"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestEnvelopeV2Handler_ProviderValidation testa a validação de provider na rota v2
@@ -133,3 +145,261 @@ func TestEnvelopeV2Handler_HTTPRequest(t *testing.T) {
 	})
 }
 
+func TestCreateEnvelopeV2Handler_BlockedFileURL(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := newFailoverTestHandler(ctrl, mocks.NewMockIRepositoryEnvelope(ctrl))
+	handler.DocumentFetcher = fetcher.NewFetcher(fetcher.Config{})
+
+	w := performFailoverRequest(t, handler, map[string]interface{}{
+		"provider": "failover-primary",
+		"name":     "Envelope com URL interna",
+		"documents": []map[string]interface{}{
+			{"name": "contrato.pdf", "file_url": "http://169.254.169.254/latest/meta-data/"},
+		},
+		"signatory_emails": []string{"assinante@empresa.com"},
+	})
+
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	var response dtos.ValidationErrorResponseDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Validation failed", response.Error)
+	require.Len(t, response.Details, 1)
+	assert.Equal(t, "documents.file_url", response.Details[0].Field)
+	assert.Contains(t, response.Details[0].Message, "blocked_address")
+}
+
+func TestEnvelopeV2Handler_TemplateDocuments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := newFailoverTestHandler(ctrl, mocks.NewMockIRepositoryEnvelope(ctrl))
+	mockTemplates := mocks.NewMockIUsecaseDocumentTemplate(ctrl)
+	handler.UsecaseDocumentTemplates = mockTemplates
+
+	t.Run("should generate the PDF and record the template in metadata", func(t *testing.T) {
+		handler.UsecaseDocumentIngestion, _ = newTestIngestion(t, nil, nil)
+		variables := map[string]string{"contratante": "Ana"}
+		mockTemplates.EXPECT().GeneratePDF(gomock.Any(), 3, variables).
+			Return(samplePDF(16, ""), &entity.EntityDocumentTemplate{ID: 3, Name: "Contrato padrão"}, nil)
+
+		_, documents, err := handler.mapCreateRequestToEntityV2(context.Background(), dtos.EnvelopeV2CreateRequestDTO{
+			Name: "Envelope com template",
+			Documents: []dtos.EnvelopeDocumentRequest{
+				{Name: "contrato.pdf", TemplateID: 3, Variables: variables, Metadata: map[string]interface{}{"contract_id": "42"}},
+			},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, documents, 1)
+		assert.Equal(t, "application/pdf", documents[0].MimeType)
+		assert.True(t, documents[0].IsFromBase64)
+		assert.NotEmpty(t, documents[0].StorageKey)
+		assert.NotEmpty(t, documents[0].SHA256)
+
+		var metadata map[string]interface{}
+		require.NoError(t, json.Unmarshal(documents[0].Metadata, &metadata))
+		assert.Equal(t, "42", metadata["contract_id"])
+		template := metadata["template"].(map[string]interface{})
+		assert.Equal(t, float64(3), template["template_id"])
+		assert.Equal(t, "Contrato padrão", template["template_name"])
+	})
+
+	t.Run("should answer 422 when the PDF cannot be generated", func(t *testing.T) {
+		mockTemplates.EXPECT().GeneratePDF(gomock.Any(), 4, gomock.Any()).
+			Return(nil, &entity.EntityDocumentTemplate{ID: 4}, errors.New("document conversion is disabled"))
+
+		w := performFailoverRequest(t, handler, map[string]interface{}{
+			"provider": "failover-primary",
+			"name":     "Envelope com template",
+			"documents": []map[string]interface{}{
+				{"name": "contrato.pdf", "template_id": 4},
+			},
+			"signatory_emails": []string{"assinante@empresa.com"},
+		})
+
+		require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), "Template rendering failed")
+	})
+
+	t.Run("should reject documents with more than one source", func(t *testing.T) {
+		w := performFailoverRequest(t, handler, map[string]interface{}{
+			"provider": "failover-primary",
+			"name":     "Envelope com template",
+			"documents": []map[string]interface{}{
+				{"name": "contrato.pdf", "template_id": 4, "file_url": "https://exemplo.com/contrato.pdf"},
+			},
+			"signatory_emails": []string{"assinante@empresa.com"},
+		})
+
+		require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), "template_id")
+	})
+}
+
+func TestEnvelopeV2Handler_DocumentParts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := newFailoverTestHandler(ctrl, mocks.NewMockIRepositoryEnvelope(ctrl))
+	encodePNG := func(width, height int) string {
+		var buffer bytes.Buffer
+		require.NoError(t, png.Encode(&buffer, image.NewRGBA(image.Rect(0, 0, width, height))))
+		return base64.StdEncoding.EncodeToString(buffer.Bytes())
+	}
+
+	t.Run("should merge images into a single PDF and keep the sources", func(t *testing.T) {
+		ingestion, documentStorage := newTestIngestion(t, nil, nil)
+		handler.UsecaseDocumentIngestion = ingestion
+
+		_, documents, err := handler.mapCreateRequestToEntityV2(context.Background(), dtos.EnvelopeV2CreateRequestDTO{
+			Name: "Envelope com fotos",
+			Documents: []dtos.EnvelopeDocumentRequest{{
+				Name: "rg.pdf",
+				Parts: []dtos.EnvelopeDocumentPart{
+					{FileContentBase64: encodePNG(40, 60)},
+					{FileContentBase64: encodePNG(60, 40)},
+				},
+			}},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, documents, 1)
+		assert.Equal(t, "application/pdf", documents[0].MimeType)
+		assert.True(t, documents[0].IsFromBase64)
+
+		merged, err := storage.ReadAll(t.Context(), documentStorage, documents[0].StorageKey)
+		require.NoError(t, err)
+		assert.Equal(t, 2, bytes.Count(merged, []byte("/Type /Page ")))
+
+		var metadata struct {
+			Normalization entity.DocumentNormalization `json:"normalization"`
+		}
+		require.NoError(t, json.Unmarshal(documents[0].Metadata, &metadata))
+		assert.Equal(t, normalizer.BackendBasic, metadata.Normalization.Normalizer)
+		assert.Equal(t, []string{normalizer.StepImagesConverted}, metadata.Normalization.Steps)
+		require.Len(t, metadata.Normalization.Sources, 2)
+		assert.Equal(t, "image/png", metadata.Normalization.Sources[0].MimeType)
+		assert.NotEmpty(t, metadata.Normalization.Sources[1].StorageKey)
+	})
+
+	t.Run("should answer 422 for a corrupt PDF part", func(t *testing.T) {
+		handler.UsecaseDocumentIngestion, _ = newTestIngestion(t, nil, nil)
+
+		corrupt := base64.StdEncoding.EncodeToString([]byte("%PDF-1.4\nsem fim\n"))
+		w := performFailoverRequest(t, handler, map[string]interface{}{
+			"provider": "failover-primary",
+			"name":     "Envelope com partes",
+			"documents": []map[string]interface{}{
+				{"name": "rg.pdf", "parts": []map[string]interface{}{
+					{"file_content_base64": encodePNG(10, 10)},
+					{"file_content_base64": corrupt},
+				}},
+			},
+			"signatory_emails": []string{"assinante@empresa.com"},
+		})
+
+		require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), "Document rejected")
+		assert.Contains(t, w.Body.String(), "part 2")
+	})
+
+	t.Run("should reject parts with more than one source", func(t *testing.T) {
+		w := performFailoverRequest(t, handler, map[string]interface{}{
+			"provider": "failover-primary",
+			"name":     "Envelope com partes",
+			"documents": []map[string]interface{}{
+				{"name": "rg.pdf", "parts": []map[string]interface{}{
+					{"file_content_base64": encodePNG(10, 10), "file_url": "https://exemplo.com/rg.png"},
+				}},
+			},
+			"signatory_emails": []string{"assinante@empresa.com"},
+		})
+
+		require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), "parte 1")
+	})
+}
+
+func TestEnvelopeV2Handler_MalwareScan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := newFailoverTestHandler(ctrl, mocks.NewMockIRepositoryEnvelope(ctrl))
+	request := func(document map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"provider":         "failover-primary",
+			"name":             "Envelope verificado",
+			"documents":        []map[string]interface{}{document},
+			"signatory_emails": []string{"assinante@empresa.com"},
+		}
+	}
+	infected := base64.StdEncoding.EncodeToString(append(samplePDF(16, ""), []byte("EICAR")...))
+
+	t.Run("should record the scan of clean documents", func(t *testing.T) {
+		handler.UsecaseDocumentIngestion, _ = newTestIngestion(t, nil, stubScanner{marker: "EICAR"})
+
+		_, documents, err := handler.mapCreateRequestToEntityV2(context.Background(), dtos.EnvelopeV2CreateRequestDTO{
+			Name: "Envelope verificado",
+			Documents: []dtos.EnvelopeDocumentRequest{
+				{Name: "contrato.pdf", FileContentBase64: base64.StdEncoding.EncodeToString(samplePDF(16, ""))},
+			},
+		})
+
+		require.NoError(t, err)
+		var metadata struct {
+			Scan entity.DocumentScan `json:"scan"`
+		}
+		require.NoError(t, json.Unmarshal(documents[0].Metadata, &metadata))
+		assert.Equal(t, "stub", metadata.Scan.Scanner)
+		assert.False(t, metadata.Scan.Infected)
+		assert.Equal(t, "draft", documents[0].Status)
+	})
+
+	t.Run("should reject infected documents", func(t *testing.T) {
+		handler.UsecaseDocumentIngestion, _ = newTestIngestion(t, nil, stubScanner{marker: "EICAR"})
+
+		w := performFailoverRequest(t, handler, request(map[string]interface{}{"name": "contrato.pdf", "file_content_base64": infected}))
+
+		require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), "Document infected")
+		assert.Contains(t, w.Body.String(), "Eicar-Test-Signature")
+	})
+
+	t.Run("should reject infected parts", func(t *testing.T) {
+		handler.UsecaseDocumentIngestion, _ = newTestIngestion(t, nil, stubScanner{marker: "EICAR"})
+
+		w := performFailoverRequest(t, handler, request(map[string]interface{}{"name": "contrato.pdf", "parts": []map[string]interface{}{
+			{"file_content_base64": base64.StdEncoding.EncodeToString(samplePDF(16, ""))},
+			{"file_content_base64": infected},
+		}}))
+
+		require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), "part 2")
+	})
+
+	t.Run("should reject quarantined documents_ids", func(t *testing.T) {
+		handler.UsecaseDocuments.(*mocks.MockIUsecaseDocument).EXPECT().GetDocument(9).
+			Return(&entity.EntityDocument{ID: 9, Status: entity.DocumentStatusQuarantined}, nil)
+
+		w := performFailoverRequest(t, handler, map[string]interface{}{
+			"provider":         "failover-primary",
+			"name":             "Envelope verificado",
+			"documents_ids":    []int{9},
+			"signatory_emails": []string{"assinante@empresa.com"},
+		})
+
+		require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), "document 9 is quarantined")
+	})
+
+	t.Run("should answer 503 when the scanner is unavailable", func(t *testing.T) {
+		handler.UsecaseDocumentIngestion, _ = newTestIngestion(t, nil, stubScanner{err: errors.New("failed to connect to clamd")})
+
+		w := performFailoverRequest(t, handler, request(map[string]interface{}{"name": "contrato.pdf", "file_content_base64": infected}))
+
+		require.Equal(t, http.StatusServiceUnavailable, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), "Malware scan unavailable")
+	})
+}
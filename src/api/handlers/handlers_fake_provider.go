@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"net/http"
+
+	"app/api/handlers/dtos"
+	"app/config"
+	"app/infrastructure/fake_provider"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// FakeProviderHandlers expõe a API de simulação do provider fake
+// Permite inspecionar os envelopes em memória e simular ações dos signatários
+type FakeProviderHandlers struct {
+	Simulator *fake_provider.Simulator
+	Logger    *logrus.Logger
+}
+
+// NewFakeProviderHandler cria uma nova instância do FakeProviderHandlers
+func NewFakeProviderHandler(simulator *fake_provider.Simulator, logger *logrus.Logger) *FakeProviderHandlers {
+	return &FakeProviderHandlers{
+		Simulator: simulator,
+		Logger:    logger,
+	}
+}
+
+// @Summary List fake provider envelopes
+// @Description List envelopes stored in memory by the fake provider. Only available when FAKE_PROVIDER_ENABLED=true.
+// @Tags fake-provider
+// @Produce json
+// @Success 200 {array} fake_provider.Envelope
+// @Router /api/v2/fake-provider/envelopes [get]
+func (h *FakeProviderHandlers) GetEnvelopesHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, h.Simulator.Store().List())
+}
+
+// @Summary Get fake provider envelope
+// @Description Get an envelope stored in memory by the fake provider, including signer keys used by the simulation endpoints
+// @Tags fake-provider
+// @Produce json
+// @Param key path string true "Fake envelope key"
+// @Success 200 {object} fake_provider.Envelope
+// @Failure 404 {object} dtos.ErrorResponseDTO
+// @Router /api/v2/fake-provider/envelopes/{key} [get]
+func (h *FakeProviderHandlers) GetEnvelopeHandler(c *gin.Context) {
+	envelope, ok := h.Simulator.Store().Get(c.Param("key"))
+	if !ok {
+		c.JSON(http.StatusNotFound, dtos.ErrorResponseDTO{
+			Error:   "Not found",
+			Message: "Fake envelope not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, envelope)
+}
+
+// @Summary Simulate signature
+// @Description Mark the signer as signed and emit a Clicksign-shaped "sign" webhook to /api/v1/webhooks. When the last signer signs an auto-close envelope, "auto_close" is emitted too.
+// @Tags fake-provider
+// @Produce json
+// @Param key path string true "Fake envelope key"
+// @Param signer_key path string true "Fake signer key"
+// @Success 200 {object} dtos.FakeProviderWebhooksResponseDTO
+// @Failure 400 {object} dtos.ErrorResponseDTO
+// @Router /api/v2/fake-provider/envelopes/{key}/signers/{signer_key}/sign [post]
+func (h *FakeProviderHandlers) SignHandler(c *gin.Context) {
+	envelopeKey := c.Param("key")
+
+	payloads, err := h.Simulator.Sign(c.Request.Context(), envelopeKey, c.Param("signer_key"))
+	if err != nil {
+		h.Logger.WithError(err).WithField("envelope_key", envelopeKey).Error("Fake signature simulation failed")
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Simulation failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, newFakeProviderWebhooksResponse(envelopeKey, payloads))
+}
+
+// @Summary Emit fake webhook
+// @Description Emit an arbitrary Clicksign-shaped webhook (e.g. "upload", "add_signer", "signature_started") for a fake envelope without changing its state
+// @Tags fake-provider
+// @Accept json
+// @Produce json
+// @Param key path string true "Fake envelope key"
+// @Param request body dtos.FakeProviderEventRequestDTO true "Event data"
+// @Success 200 {object} dtos.FakeProviderWebhooksResponseDTO
+// @Failure 400 {object} dtos.ErrorResponseDTO
+// @Router /api/v2/fake-provider/envelopes/{key}/events [post]
+func (h *FakeProviderHandlers) EmitEventHandler(c *gin.Context) {
+	envelopeKey := c.Param("key")
+
+	var requestDTO dtos.FakeProviderEventRequestDTO
+	if err := c.ShouldBindJSON(&requestDTO); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Invalid payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	payload, err := h.Simulator.EmitEvent(c.Request.Context(), envelopeKey, requestDTO.Name, requestDTO.Data)
+	if err != nil {
+		h.Logger.WithError(err).WithField("envelope_key", envelopeKey).Error("Fake webhook emission failed")
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Simulation failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, newFakeProviderWebhooksResponse(envelopeKey, []fake_provider.WebhookPayload{payload}))
+}
+
+// @Summary Reset fake provider
+// @Description Remove all envelopes stored in memory by the fake provider
+// @Tags fake-provider
+// @Success 204
+// @Router /api/v2/fake-provider/envelopes [delete]
+func (h *FakeProviderHandlers) ResetHandler(c *gin.Context) {
+	h.Simulator.Store().Reset()
+	c.Status(http.StatusNoContent)
+}
+
+func newFakeProviderWebhooksResponse(envelopeKey string, payloads []fake_provider.WebhookPayload) dtos.FakeProviderWebhooksResponseDTO {
+	response := dtos.FakeProviderWebhooksResponseDTO{
+		EnvelopeKey: envelopeKey,
+		Events:      make([]string, 0, len(payloads)),
+	}
+	for _, payload := range payloads {
+		response.Events = append(response.Events, payload.Event.Name)
+		response.Status = payload.Document.Status
+	}
+	return response
+}
+
+// MountFakeProviderHandlers monta a API de simulação do provider fake
+// Os webhooks são enviados para FAKE_PROVIDER_WEBHOOK_URL (por padrão, o próprio /api/v1/webhooks)
+func MountFakeProviderHandlers(gin *gin.Engine, logger *logrus.Logger) {
+	simulator := fake_provider.NewSimulator(
+		fake_provider.DefaultStore(),
+		fake_provider.NewHTTPWebhookEmitter(config.EnvironmentVariables.FAKE_PROVIDER_WEBHOOK_URL, config.EnvironmentVariables.CLICKSIGN_WEBHOOK_SECRET),
+		logger,
+	)
+	fakeProviderHandlers := NewFakeProviderHandler(simulator, logger)
+
+	group := gin.Group("/api/v2/fake-provider")
+
+	group.GET("/envelopes", fakeProviderHandlers.GetEnvelopesHandler)
+	group.DELETE("/envelopes", fakeProviderHandlers.ResetHandler)
+	group.GET("/envelopes/:key", fakeProviderHandlers.GetEnvelopeHandler)
+	group.POST("/envelopes/:key/signers/:signer_key/sign", fakeProviderHandlers.SignHandler)
+	group.POST("/envelopes/:key/events", fakeProviderHandlers.EmitEventHandler)
+}
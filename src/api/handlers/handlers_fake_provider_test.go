@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"app/api/handlers/dtos"
+	"app/entity"
+	"app/infrastructure/clicksign"
+	"app/infrastructure/fake_provider"
+	"app/infrastructure/provider"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeProviderHandlers_SignEmitsClicksignWebhooks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+
+	// Receptor com o mesmo DTO usado por POST /api/v1/webhooks
+	var received []dtos.WebhookRequestDTO
+	webhookServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.NoError(t, clicksign.VerifyWebhookSignature("webhook-secret", body, r.Header.Get(clicksign.WebhookSignatureHeader)))
+		var webhookDTO dtos.WebhookRequestDTO
+		require.NoError(t, json.Unmarshal(body, &webhookDTO))
+		received = append(received, webhookDTO)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer webhookServer.Close()
+
+	store := fake_provider.NewStore()
+	envelopeProvider := fake_provider.NewFakeProvider(store, logger)
+	ctx := context.Background()
+
+	envelopeKey, _, err := envelopeProvider.CreateEnvelope(ctx, &entity.EntityEnvelope{ID: 10, Name: "Contrato", AutoClose: true})
+	require.NoError(t, err)
+	_, err = envelopeProvider.CreateDocument(ctx, envelopeKey, &entity.EntityDocument{ID: 1, Name: "contrato.pdf"}, 10)
+	require.NoError(t, err)
+	signerKey, err := envelopeProvider.CreateSigner(ctx, envelopeKey, provider.SignerData{Name: "Assinante", Email: "assinante@empresa.com"})
+	require.NoError(t, err)
+	require.NoError(t, envelopeProvider.ActivateEnvelope(ctx, envelopeKey))
+
+	handler := NewFakeProviderHandler(fake_provider.NewSimulator(store, fake_provider.NewHTTPWebhookEmitter(webhookServer.URL, "webhook-secret"), logger), logger)
+	router := gin.New()
+	router.POST("/api/v2/fake-provider/envelopes/:key/signers/:signer_key/sign", handler.SignHandler)
+
+	t.Run("should emit sign and auto_close", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v2/fake-provider/envelopes/"+envelopeKey+"/signers/"+signerKey+"/sign", nil)
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response dtos.FakeProviderWebhooksResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, []string{"sign", "auto_close"}, response.Events)
+		assert.Equal(t, fake_provider.StatusClosed, response.Status)
+
+		require.Len(t, received, 2)
+		assert.Equal(t, "auto_close", received[1].Event.Name)
+		assert.Equal(t, envelopeKey, received[1].Document.Key)
+		assert.Equal(t, fake_provider.AccountKey, received[1].Document.AccountKey)
+		assert.Equal(t, float64(10), received[1].Document.Metadata["envelope_id"])
+		require.Len(t, received[1].Document.Signers, 1)
+		assert.Equal(t, signerKey, received[1].Document.Signers[0].Key)
+	})
+
+	t.Run("should reject signing twice", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v2/fake-provider/envelopes/"+envelopeKey+"/signers/"+signerKey+"/sign", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
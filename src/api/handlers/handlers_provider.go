@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"app/api/handlers/dtos"
+	"app/api/middleware"
+	"app/config"
+	"app/entity"
+	"app/infrastructure/dependencies"
+	"app/infrastructure/provider_factory"
+	"app/infrastructure/repository"
+	usecase_provider_credential "app/usecase/provider_credential"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// ProviderHandlers gerencia handlers de descoberta de providers e de credenciais por tenant
+type ProviderHandlers struct {
+	ProviderFactory           *provider_factory.ProviderFactory
+	UsecaseProviderCredential usecase_provider_credential.IUsecaseProviderCredential
+	Logger                    *logrus.Logger
+}
+
+// NewProviderHandler cria uma nova instância do ProviderHandlers
+func NewProviderHandler(
+	providerFactory *provider_factory.ProviderFactory,
+	usecaseProviderCredential usecase_provider_credential.IUsecaseProviderCredential,
+	logger *logrus.Logger,
+) *ProviderHandlers {
+	return &ProviderHandlers{
+		ProviderFactory:           providerFactory,
+		UsecaseProviderCredential: usecaseProviderCredential,
+		Logger:                    logger,
+	}
+}
+
+// @Summary List providers (v2)
+// @Description List supported envelope providers and their capabilities (auth methods, actions, notify, cancel, sequential signing, max file size and mime types)
+// @Tags providers-v2
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} dtos.ProviderListResponseDTO
+// @Router /api/v2/providers [get]
+func (h *ProviderHandlers) GetProvidersHandler(c *gin.Context) {
+	capabilities := h.ProviderFactory.ListCapabilities()
+
+	response := dtos.ProviderListResponseDTO{
+		Providers: make([]dtos.ProviderCapabilitiesResponseDTO, 0, len(capabilities)),
+		Total:     len(capabilities),
+	}
+	for _, providerCapabilities := range capabilities {
+		response.Providers = append(response.Providers, dtos.NewProviderCapabilitiesResponseDTO(providerCapabilities))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Create provider credential
+// @Description Store provider credentials (API key, base URL, login) for a tenant. Secrets are never returned. Admin only.
+// @Tags providers-v2
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body dtos.ProviderCredentialCreateRequestDTO true "Provider credential data"
+// @Success 201 {object} dtos.ProviderCredentialResponseDTO
+// @Failure 400 {object} dtos.ValidationErrorResponseDTO
+// @Failure 409 {object} dtos.ErrorResponseDTO
+// @Failure 500 {object} dtos.ErrorResponseDTO
+// @Router /api/v2/providers/credentials [post]
+func (h *ProviderHandlers) CreateProviderCredentialHandler(c *gin.Context) {
+	var requestDTO dtos.ProviderCredentialCreateRequestDTO
+	if err := c.ShouldBindJSON(&requestDTO); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ValidationErrorResponseDTO{
+			Error:   "Validation failed",
+			Message: "Invalid request payload",
+			Details: []dtos.ValidationErrorDetail{{Message: err.Error()}},
+		})
+		return
+	}
+
+	if err := requestDTO.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	credential, err := entity.NewProviderCredential(requestDTO.ToEntity())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	created, err := h.UsecaseProviderCredential.CreateProviderCredential(credential)
+	if err != nil {
+		if errors.Is(err, usecase_provider_credential.ErrCredentialAlreadyExists) || errors.Is(err, usecase_provider_credential.ErrAccountKeyInUse) {
+			c.JSON(http.StatusConflict, dtos.ErrorResponseDTO{
+				Error:   "Conflict",
+				Message: err.Error(),
+			})
+			return
+		}
+		if !h.ProviderFactory.IsProviderSupported(credential.Provider) {
+			c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+				Error:   "Invalid provider",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		h.Logger.WithError(err).Error("Failed to create provider credential")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+			Error:   "Internal server error",
+			Message: "Failed to create provider credential",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dtos.NewProviderCredentialResponseDTO(created))
+}
+
+// @Summary List provider credentials
+// @Description List stored provider credentials for all tenants. Secrets are never returned. Admin only.
+// @Tags providers-v2
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} dtos.ProviderCredentialResponseDTO
+// @Failure 500 {object} dtos.ErrorResponseDTO
+// @Router /api/v2/providers/credentials [get]
+func (h *ProviderHandlers) GetProviderCredentialsHandler(c *gin.Context) {
+	credentials, err := h.UsecaseProviderCredential.GetAllProviderCredentials()
+	if err != nil {
+		h.Logger.WithError(err).Error("Failed to list provider credentials")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+			Error:   "Internal server error",
+			Message: "Failed to list provider credentials",
+		})
+		return
+	}
+
+	response := make([]dtos.ProviderCredentialResponseDTO, 0, len(credentials))
+	for i := range credentials {
+		response = append(response, dtos.NewProviderCredentialResponseDTO(&credentials[i]))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Update provider credential
+// @Description Update stored provider credentials. Omitted fields keep their current value. Admin only.
+// @Tags providers-v2
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Provider credential ID"
+// @Param request body dtos.ProviderCredentialUpdateRequestDTO true "Fields to update"
+// @Success 200 {object} dtos.ProviderCredentialResponseDTO
+// @Failure 400 {object} dtos.ErrorResponseDTO
+// @Failure 404 {object} dtos.ErrorResponseDTO
+// @Failure 500 {object} dtos.ErrorResponseDTO
+// @Router /api/v2/providers/credentials/{id} [put]
+func (h *ProviderHandlers) UpdateProviderCredentialHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Invalid ID",
+			Message: "ID must be a valid integer",
+		})
+		return
+	}
+
+	var requestDTO dtos.ProviderCredentialUpdateRequestDTO
+	if err := c.ShouldBindJSON(&requestDTO); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ValidationErrorResponseDTO{
+			Error:   "Validation failed",
+			Message: "Invalid request payload",
+			Details: []dtos.ValidationErrorDetail{{Message: err.Error()}},
+		})
+		return
+	}
+
+	credential, err := h.UsecaseProviderCredential.GetProviderCredential(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, dtos.ErrorResponseDTO{
+				Error:   "Not found",
+				Message: "Provider credential not found",
+			})
+			return
+		}
+		h.Logger.WithError(err).Error("Failed to get provider credential")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+			Error:   "Internal server error",
+			Message: "Failed to get provider credential",
+		})
+		return
+	}
+
+	requestDTO.ApplyTo(credential)
+
+	if err := h.UsecaseProviderCredential.UpdateProviderCredential(credential); err != nil {
+		if errors.Is(err, usecase_provider_credential.ErrAccountKeyInUse) {
+			c.JSON(http.StatusConflict, dtos.ErrorResponseDTO{
+				Error:   "Conflict",
+				Message: err.Error(),
+			})
+			return
+		}
+		h.Logger.WithError(err).WithField("credential_id", id).Error("Failed to update provider credential")
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Update failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.NewProviderCredentialResponseDTO(credential))
+}
+
+// @Summary Delete provider credential
+// @Description Delete stored provider credentials of a tenant. Admin only.
+// @Tags providers-v2
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Provider credential ID"
+// @Success 204
+// @Failure 404 {object} dtos.ErrorResponseDTO
+// @Failure 500 {object} dtos.ErrorResponseDTO
+// @Router /api/v2/providers/credentials/{id} [delete]
+func (h *ProviderHandlers) DeleteProviderCredentialHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Invalid ID",
+			Message: "ID must be a valid integer",
+		})
+		return
+	}
+
+	if err := h.UsecaseProviderCredential.DeleteProviderCredential(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, dtos.ErrorResponseDTO{
+				Error:   "Not found",
+				Message: "Provider credential not found",
+			})
+			return
+		}
+		h.Logger.WithError(err).WithField("credential_id", id).Error("Failed to delete provider credential")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+			Error:   "Internal server error",
+			Message: "Failed to delete provider credential",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func MountProviderHandlers(gin *gin.Engine, conn *gorm.DB, deps *dependencies.Dependencies) {
+	logger := deps.Logger
+	repositoryProviderCredential := repository.NewRepositoryProviderCredential(conn)
+
+	providerHandlers := NewProviderHandler(
+		provider_factory.NewProviderFactoryWithCredentials(config.EnvironmentVariables, repositoryProviderCredential, deps.Storage, deps.Fetcher, logger),
+		usecase_provider_credential.NewUsecaseProviderCredentialService(repositoryProviderCredential, logger),
+		logger,
+	)
+
+	group := gin.Group("/api/v2/providers")
+	SetScopedAuthMiddleware(conn, deps, group, entity.ScopeProvidersRead, "")
+
+	group.GET("/", providerHandlers.GetProvidersHandler)
+
+	// Credenciais são configuradas pelos administradores da plataforma para todos os tenants
+	credentialsGroup := group.Group("/credentials")
+	credentialsGroup.Use(middleware.RequirePlatformAdminMiddleware())
+
+	credentialsGroup.POST("/", providerHandlers.CreateProviderCredentialHandler)
+	credentialsGroup.GET("/", providerHandlers.GetProviderCredentialsHandler)
+	credentialsGroup.PUT("/:id", providerHandlers.UpdateProviderCredentialHandler)
+	credentialsGroup.DELETE("/:id", providerHandlers.DeleteProviderCredentialHandler)
+}
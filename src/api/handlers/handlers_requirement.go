@@ -2,12 +2,14 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
 	"app/api/handlers/dtos"
+	"app/entity"
 	"app/usecase/requirement"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -38,6 +40,7 @@ func NewRequirementHandler(usecaseRequirement requirement.IUsecaseRequirement, l
 // @Success 201 {object} dtos.RequirementResponseDTO "Requirement created successfully"
 // @Failure 400 {object} dtos.ValidationErrorResponseDTO "Validation error - invalid request data or business rule violation"
 // @Failure 404 {object} dtos.ErrorResponseDTO "Envelope not found"
+// @Failure 409 {object} dtos.ErrorResponseDTO "Envelope is not in draft status"
 // @Failure 500 {object} dtos.ErrorResponseDTO "Internal server error - requirement creation failed"
 // @Router /api/v1/envelopes/{id}/requirements [post]
 func (h *RequirementHandlers) CreateRequirementHandler(c *gin.Context) {
@@ -97,6 +100,11 @@ func (h *RequirementHandlers) CreateRequirementHandler(c *gin.Context) {
 	// Create requirement
 	createdRequirement, err := h.UsecaseRequirement.CreateRequirement(ctx, requirementEntity)
 	if err != nil {
+		if errors.Is(err, entity.ErrEnvelopeNotDraft) {
+			h.respondEnvelopeNotDraft(c)
+			return
+		}
+
 		// Check if it's a validation error (envelope not found, etc.)
 		if contains(err.Error(), "envelope not found") {
 			errorResponse := &dtos.ErrorResponseDTO{
@@ -256,6 +264,7 @@ func (h *RequirementHandlers) GetRequirementHandler(c *gin.Context) {
 // @Success 200 {object} dtos.RequirementResponseDTO "Requirement updated successfully"
 // @Failure 400 {object} dtos.ValidationErrorResponseDTO "Validation error - invalid request data"
 // @Failure 404 {object} dtos.ErrorResponseDTO "Requirement not found"
+// @Failure 409 {object} dtos.ErrorResponseDTO "Envelope is not in draft status"
 // @Failure 500 {object} dtos.ErrorResponseDTO "Internal server error"
 // @Router /api/v1/requirements/{requirement_id} [put]
 func (h *RequirementHandlers) UpdateRequirementHandler(c *gin.Context) {
@@ -318,6 +327,11 @@ func (h *RequirementHandlers) UpdateRequirementHandler(c *gin.Context) {
 	// Update requirement
 	updatedRequirement, err := h.UsecaseRequirement.UpdateRequirement(ctx, requirement)
 	if err != nil {
+		if errors.Is(err, entity.ErrEnvelopeNotDraft) {
+			h.respondEnvelopeNotDraft(c)
+			return
+		}
+
 		errorResponse := &dtos.ErrorResponseDTO{
 			Message: "Falha ao atualizar requisito",
 			Error:   "update_failed",
@@ -342,6 +356,7 @@ func (h *RequirementHandlers) UpdateRequirementHandler(c *gin.Context) {
 // @Param requirement_id path int true "Requirement ID"
 // @Success 204 "Requirement deleted successfully"
 // @Failure 404 {object} dtos.ErrorResponseDTO "Requirement not found"
+// @Failure 409 {object} dtos.ErrorResponseDTO "Envelope is not in draft status"
 // @Failure 500 {object} dtos.ErrorResponseDTO "Internal server error"
 // @Router /api/v1/requirements/{requirement_id} [delete]
 func (h *RequirementHandlers) DeleteRequirementHandler(c *gin.Context) {
@@ -366,6 +381,11 @@ func (h *RequirementHandlers) DeleteRequirementHandler(c *gin.Context) {
 	// Delete requirement
 	err = h.UsecaseRequirement.DeleteRequirement(ctx, requirementID)
 	if err != nil {
+		if errors.Is(err, entity.ErrEnvelopeNotDraft) {
+			h.respondEnvelopeNotDraft(c)
+			return
+		}
+
 		if contains(err.Error(), "failed to fetch requirement for deletion") {
 			errorResponse := &dtos.ErrorResponseDTO{
 				Message: "Requisito não encontrado",
@@ -386,6 +406,14 @@ func (h *RequirementHandlers) DeleteRequirementHandler(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// respondEnvelopeNotDraft responde 409: envelopes pendentes de aprovação, aprovados ou enviados não aceitam edições
+func (h *RequirementHandlers) respondEnvelopeNotDraft(c *gin.Context) {
+	c.JSON(http.StatusConflict, &dtos.ErrorResponseDTO{
+		Message: "Requisitos só podem ser alterados em envelopes em rascunho",
+		Error:   "envelope_not_draft",
+	})
+}
+
 // Helper function to check if string contains substring
 func contains(s, substr string) bool {
 	return strings.Contains(s, substr)
@@ -2,34 +2,37 @@ package handlers
 
 import (
 	"app/config"
+	"app/entity"
 	"app/infrastructure/clicksign"
+	"app/infrastructure/dependencies"
 	"app/infrastructure/repository"
 	"app/usecase/requirement"
 
 	"github.com/gin-gonic/gin"
-	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
-func MountRequirementHandlers(gin *gin.Engine, conn *gorm.DB, logger *logrus.Logger) {
+func MountRequirementHandlers(gin *gin.Engine, conn *gorm.DB, deps *dependencies.Dependencies) {
+	logger := deps.Logger
 	clicksignClient := clicksign.NewClicksignClient(config.EnvironmentVariables, logger)
 
-	// Criar usecase de requirement
-	usecaseRequirement := requirement.NewUsecaseRequirementService(
-		repository.NewRepositoryRequirement(conn),
-		repository.NewRepositoryEnvelope(conn),
-		clicksignClient,
-		logger,
-	)
-
 	// Criar handler de requirements
-	requirementHandlers := NewRequirementHandler(usecaseRequirement, logger)
+	requirementHandlers := newTenantHandlers(conn, func(conn *gorm.DB) *RequirementHandlers {
+		usecaseRequirement := requirement.NewUsecaseRequirementService(
+			repository.NewRepositoryRequirement(conn),
+			repository.NewRepositoryEnvelope(conn),
+			clicksignClient,
+			logger,
+		)
+		return NewRequirementHandler(usecaseRequirement, logger)
+	})
 
 	// Grupo de rotas individuais de requirements
 	requirementGroup := gin.Group("/api/v1/requirements")
-	SetAuthMiddleware(conn, requirementGroup)
+	SetScopedAuthMiddleware(conn, deps, requirementGroup, entity.ScopeEnvelopesRead, entity.ScopeEnvelopesWrite)
+	requirementGroup.Use(requireEnvelopeOwner(newEnvelopeOwnership(conn), envelopeOfRequirement))
 
-	requirementGroup.GET("/:requirement_id", requirementHandlers.GetRequirementHandler)
-	requirementGroup.PUT("/:requirement_id", requirementHandlers.UpdateRequirementHandler)
-	requirementGroup.DELETE("/:requirement_id", requirementHandlers.DeleteRequirementHandler)
+	requirementGroup.GET("/:requirement_id", requirementHandlers.Handle((*RequirementHandlers).GetRequirementHandler))
+	requirementGroup.PUT("/:requirement_id", requirementHandlers.Handle((*RequirementHandlers).UpdateRequirementHandler))
+	requirementGroup.DELETE("/:requirement_id", requirementHandlers.Handle((*RequirementHandlers).DeleteRequirementHandler))
 }
\ No newline at end of file
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"app/api/handlers/dtos"
+	"app/api/middleware"
+	"app/config"
+	"app/entity"
+	"app/infrastructure/dependencies"
+	"app/infrastructure/repository"
+	usecase_retention "app/usecase/retention"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// defaultRetentionRunsLimit é a quantidade de execuções retornadas quando limit não é informado
+const defaultRetentionRunsLimit = 20
+
+type RetentionHandlers struct {
+	UsecaseRetention usecase_retention.IUsecaseRetention
+	Logger           *logrus.Logger
+}
+
+func NewRetentionHandler(usecaseRetention usecase_retention.IUsecaseRetention, logger *logrus.Logger) *RetentionHandlers {
+	return &RetentionHandlers{
+		UsecaseRetention: usecaseRetention,
+		Logger:           logger,
+	}
+}
+
+// @Summary Consultar política de retenção
+// @Description Retorna os prazos de retenção por tipo de dado e o agendamento do expurgo automático
+// @Tags Retention
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} dtos.RetentionPolicyResponseDTO "Política de retenção"
+// @Failure 401 {object} dtos.ErrorResponseDTO "Não autorizado"
+// @Router /api/v1/retention/policy [get]
+func (h RetentionHandlers) GetRetentionPolicyHandler(c *gin.Context) {
+	jsonResponse(c, http.StatusOK, mapRetentionPolicyToResponse(h.UsecaseRetention.Policy()))
+}
+
+// @Summary Executar expurgo
+// @Description Aplica a política de retenção imediatamente e retorna o relatório da execução
+// @Description Por padrão roda em dry-run, apenas listando o que seria expurgado; use dry_run=false para expurgar
+// @Tags Retention
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param dry_run query bool false "Apenas simula o expurgo (padrão true)"
+// @Success 200 {object} dtos.RetentionRunResponseDTO "Relatório da execução"
+// @Failure 400 {object} dtos.ErrorResponseDTO "Parâmetro inválido"
+// @Failure 409 {object} dtos.ErrorResponseDTO "Já existe um expurgo em andamento"
+// @Failure 500 {object} dtos.ErrorResponseDTO "Erro interno"
+// @Router /api/v1/retention/runs [post]
+func (h RetentionHandlers) CreateRetentionRunHandler(c *gin.Context) {
+	dryRun := true
+	if value := c.Query("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+				Error:   "Invalid parameter",
+				Message: "dry_run must be a boolean",
+			})
+			return
+		}
+		dryRun = parsed
+	}
+
+	run, err := h.UsecaseRetention.Run(c.Request.Context(), entity.RetentionTriggerManual, dryRun)
+	if err != nil {
+		if errors.Is(err, usecase_retention.ErrRetentionRunning) {
+			c.JSON(http.StatusConflict, dtos.ErrorResponseDTO{
+				Error:   "Retention run in progress",
+				Message: "Wait for the current retention run to finish",
+			})
+			return
+		}
+
+		h.Logger.WithError(err).Error("Failed to run retention")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+			Error:   "Internal server error",
+			Message: "Failed to run retention",
+		})
+		return
+	}
+
+	jsonResponse(c, http.StatusOK, mapRetentionRunToResponse(run))
+}
+
+// @Summary Listar execuções do expurgo
+// @Description Retorna as execuções mais recentes, manuais e agendadas, com o que foi expurgado em cada uma
+// @Tags Retention
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param limit query int false "Quantidade de execuções (padrão 20)"
+// @Success 200 {object} dtos.RetentionRunListResponseDTO "Lista de execuções"
+// @Failure 500 {object} dtos.ErrorResponseDTO "Erro interno"
+// @Router /api/v1/retention/runs [get]
+func (h RetentionHandlers) GetRetentionRunsHandler(c *gin.Context) {
+	limit := defaultRetentionRunsLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+
+	runs, err := h.UsecaseRetention.GetRuns(limit)
+	if err != nil {
+		h.Logger.WithError(err).Error("Failed to list retention runs")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+			Error:   "Internal server error",
+			Message: "Failed to retrieve retention runs",
+		})
+		return
+	}
+
+	responseDTOs := make([]dtos.RetentionRunResponseDTO, 0, len(runs))
+	for i := range runs {
+		responseDTOs = append(responseDTOs, mapRetentionRunToResponse(&runs[i]))
+	}
+
+	jsonResponse(c, http.StatusOK, dtos.RetentionRunListResponseDTO{
+		Runs:  responseDTOs,
+		Total: len(responseDTOs),
+	})
+}
+
+// @Summary Buscar execução do expurgo
+// @Description Retorna o relatório de uma execução do expurgo
+// @Tags Retention
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID da execução"
+// @Success 200 {object} dtos.RetentionRunResponseDTO "Relatório da execução"
+// @Failure 400 {object} dtos.ErrorResponseDTO "ID inválido"
+// @Failure 404 {object} dtos.ErrorResponseDTO "Execução não encontrada"
+// @Failure 500 {object} dtos.ErrorResponseDTO "Erro interno"
+// @Router /api/v1/retention/runs/{id} [get]
+func (h RetentionHandlers) GetRetentionRunHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Invalid ID",
+			Message: "Retention run ID must be a valid integer",
+		})
+		return
+	}
+
+	run, err := h.UsecaseRetention.GetRun(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, dtos.ErrorResponseDTO{
+				Error:   "Retention run not found",
+				Message: "The requested retention run does not exist",
+			})
+			return
+		}
+
+		h.Logger.WithError(err).Error("Failed to get retention run")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+			Error:   "Internal server error",
+			Message: "Failed to get retention run",
+		})
+		return
+	}
+
+	jsonResponse(c, http.StatusOK, mapRetentionRunToResponse(run))
+}
+
+func mapRetentionPolicyToResponse(policy entity.RetentionPolicy) dtos.RetentionPolicyResponseDTO {
+	return dtos.RetentionPolicyResponseDTO{
+		EnvelopeRawDataDays: policy.EnvelopeRawDataDays,
+		WebhookPayloadDays:  policy.WebhookPayloadDays,
+		SignerPIIDays:       policy.SignerPIIDays,
+		DocumentFileDays:    policy.DocumentFileDays,
+		ScheduleEnabled:     config.EnvironmentVariables.RETENTION_ENABLED,
+		Schedule:            config.EnvironmentVariables.RETENTION_SCHEDULE,
+		ScheduleDryRun:      config.EnvironmentVariables.RETENTION_DRY_RUN,
+	}
+}
+
+func mapRetentionRunToResponse(run *entity.EntityRetentionRun) dtos.RetentionRunResponseDTO {
+	items := make([]dtos.RetentionRunItemResponseDTO, 0, len(run.Items))
+	for _, item := range run.Items {
+		ids := item.IDs
+		if ids == nil {
+			ids = []int{}
+		}
+		items = append(items, dtos.RetentionRunItemResponseDTO{
+			Rule:   item.Rule,
+			Cutoff: item.Cutoff,
+			Count:  item.Count,
+			IDs:    ids,
+			Files:  item.Files,
+			Error:  item.Error,
+		})
+	}
+
+	return dtos.RetentionRunResponseDTO{
+		ID:         run.ID,
+		Trigger:    run.Trigger,
+		DryRun:     run.DryRun,
+		Status:     run.Status,
+		Policy:     mapRetentionPolicyToResponse(run.Policy),
+		Items:      items,
+		Total:      run.Total(),
+		Error:      run.Error,
+		StartedAt:  run.StartedAt,
+		FinishedAt: run.FinishedAt,
+	}
+}
+
+func MountRetentionHandlers(gin *gin.Engine, conn *gorm.DB, deps *dependencies.Dependencies) {
+	logger := deps.Logger
+	retentionHandlers := NewRetentionHandler(
+		usecase_retention.NewUsecaseRetentionService(
+			repository.NewRepositoryRetention(conn),
+			deps.Storage,
+			usecase_retention.PolicyFromConfig(config.EnvironmentVariables),
+			logger,
+		),
+		logger,
+	)
+
+	group := gin.Group("/api/v1/retention")
+	SetScopedAuthMiddleware(conn, deps, group, entity.ScopeRetentionAdmin, entity.ScopeRetentionAdmin)
+	// A política vale para todos os tenants; as execuções listam registros de qualquer tenant
+	group.Use(middleware.RequirePlatformAdminMiddleware())
+
+	group.GET("/policy", retentionHandlers.GetRetentionPolicyHandler)
+	group.POST("/runs", retentionHandlers.CreateRetentionRunHandler)
+	group.GET("/runs", retentionHandlers.GetRetentionRunsHandler)
+	group.GET("/runs/:id", retentionHandlers.GetRetentionRunHandler)
+}
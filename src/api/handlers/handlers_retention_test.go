@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"app/api/handlers/dtos"
+	"app/entity"
+	"app/infrastructure/dependencies"
+	"app/infrastructure/jwtkeys"
+	"app/mocks"
+	usecase_retention "app/usecase/retention"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+)
+
+func performRetentionRequest(handler *RetentionHandlers, method, path string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/retention/policy", handler.GetRetentionPolicyHandler)
+	router.POST("/api/v1/retention/runs", handler.CreateRetentionRunHandler)
+	router.GET("/api/v1/retention/runs", handler.GetRetentionRunsHandler)
+	router.GET("/api/v1/retention/runs/:id", handler.GetRetentionRunHandler)
+
+	req, _ := http.NewRequest(method, path, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRetentionHandlers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockIUsecaseRetention(ctrl)
+	handler := NewRetentionHandler(mockUsecase, logrus.New())
+
+	finishedRun := func(dryRun bool) *entity.EntityRetentionRun {
+		run := entity.NewRetentionRun(entity.RetentionTriggerManual, dryRun, entity.RetentionPolicy{SignerPIIDays: 365})
+		run.ID = 3
+		run.AddItem(entity.RetentionRunItem{Rule: entity.RetentionRuleSignerPII, IDs: []int{10, 11}})
+		run.Finish()
+		return run
+	}
+
+	t.Run("should return the policy", func(t *testing.T) {
+		mockUsecase.EXPECT().Policy().Return(entity.RetentionPolicy{EnvelopeRawDataDays: 90, SignerPIIDays: 1825})
+
+		w := performRetentionRequest(handler, http.MethodGet, "/api/v1/retention/policy")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response dtos.RetentionPolicyResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 90, response.EnvelopeRawDataDays)
+		assert.Equal(t, 1825, response.SignerPIIDays)
+	})
+
+	t.Run("should run in dry run by default", func(t *testing.T) {
+		mockUsecase.EXPECT().Run(gomock.Any(), entity.RetentionTriggerManual, true).Return(finishedRun(true), nil)
+
+		w := performRetentionRequest(handler, http.MethodPost, "/api/v1/retention/runs")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response dtos.RetentionRunResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.True(t, response.DryRun)
+		assert.Equal(t, 2, response.Total)
+		require.Len(t, response.Items, 1)
+		assert.Equal(t, []int{10, 11}, response.Items[0].IDs)
+	})
+
+	t.Run("should purge when dry_run is false", func(t *testing.T) {
+		mockUsecase.EXPECT().Run(gomock.Any(), entity.RetentionTriggerManual, false).Return(finishedRun(false), nil)
+
+		w := performRetentionRequest(handler, http.MethodPost, "/api/v1/retention/runs?dry_run=false")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("should reject an invalid dry_run", func(t *testing.T) {
+		w := performRetentionRequest(handler, http.MethodPost, "/api/v1/retention/runs?dry_run=talvez")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should return conflict while another run is in progress", func(t *testing.T) {
+		mockUsecase.EXPECT().Run(gomock.Any(), entity.RetentionTriggerManual, true).Return(nil, usecase_retention.ErrRetentionRunning)
+
+		w := performRetentionRequest(handler, http.MethodPost, "/api/v1/retention/runs")
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("should list runs", func(t *testing.T) {
+		mockUsecase.EXPECT().GetRuns(5).Return([]entity.EntityRetentionRun{*finishedRun(true)}, nil)
+
+		w := performRetentionRequest(handler, http.MethodGet, "/api/v1/retention/runs?limit=5")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response dtos.RetentionRunListResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 1, response.Total)
+		assert.Equal(t, 3, response.Runs[0].ID)
+	})
+
+	t.Run("should return 404 for unknown run", func(t *testing.T) {
+		mockUsecase.EXPECT().GetRun(9).Return(nil, gorm.ErrRecordNotFound)
+
+		w := performRetentionRequest(handler, http.MethodGet, "/api/v1/retention/runs/9")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("should return 500 when listing fails", func(t *testing.T) {
+		mockUsecase.EXPECT().GetRuns(defaultRetentionRunsLimit).Return(nil, errors.New("db down"))
+
+		w := performRetentionRequest(handler, http.MethodGet, "/api/v1/retention/runs")
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestMountRetentionHandlers(t *testing.T) {
+	deps := &dependencies.Dependencies{
+		Logger:    logrus.New(),
+		TokenKeys: jwtkeys.NewKeySet(jwtkeys.NewHMACKey("test", "secret")),
+	}
+	gin.SetMode(gin.TestMode)
+	conn, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	require.NoError(t, err)
+	router := gin.New()
+	MountRetentionHandlers(router, conn, deps)
+
+	t.Run("should require authentication to purge", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/retention/runs?dry_run=false", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
@@ -11,6 +11,7 @@ import (
 	"app/config"
 	"app/entity"
 	"app/infrastructure/clicksign"
+	"app/infrastructure/dependencies"
 	"app/infrastructure/repository"
 	"app/usecase/document"
 	usecase_envelope "app/usecase/envelope"
@@ -127,7 +128,7 @@ func (h *SignatoryHandlers) CreateSignatoryHandler(c *gin.Context) {
 }
 
 // @Summary Get signatories by envelope
-// @Description Get list of signatories for a specific envelope
+// @Description Get list of signatories for a specific envelope. Birthday, documentation and phone number are masked in the list
 // @Tags signatories
 // @Accept json
 // @Produce json
@@ -499,7 +500,8 @@ func (h *SignatoryHandlers) mapEntityToResponse(signatory *entity.EntitySignator
 func (h *SignatoryHandlers) mapSignatoryListToResponse(signatories []entity.EntitySignatory) *dtos.SignatoryListResponseDTO {
 	signatoryList := make([]dtos.SignatoryResponseDTO, len(signatories))
 	for i, signatory := range signatories {
-		signatoryList[i] = *h.mapEntityToResponse(&signatory)
+		masked := signatory.Masked()
+		signatoryList[i] = *h.mapEntityToResponse(&masked)
 	}
 
 	return &dtos.SignatoryListResponseDTO{
@@ -544,54 +546,64 @@ func (h *SignatoryHandlers) getValidationErrorMessage(fieldError validator.Field
 	}
 }
 
-func MountSignatoryHandlers(gin *gin.Engine, conn *gorm.DB, logger *logrus.Logger) {
+func MountSignatoryHandlers(gin *gin.Engine, conn *gorm.DB, deps *dependencies.Dependencies) {
+	logger := deps.Logger
 	// Criar clientes e repositórios
 	clicksignClient := clicksign.NewClicksignClient(config.EnvironmentVariables, logger)
 
-	// Criar usecase de documento para envelopes
-	usecaseDocument := document.NewUsecaseDocumentServiceWithClicksign(
-		repository.NewRepositoryDocument(conn),
-		clicksignClient,
-		logger,
-	)
-
-	// Criar usecase de requirement
-	usecaseRequirement := requirement.NewUsecaseRequirementService(
-		repository.NewRepositoryRequirement(conn),
-		repository.NewRepositoryEnvelope(conn),
-		clicksignClient,
-		logger,
-	)
-
-	// Importar as dependências necessárias
-	signatoryHandlers := NewSignatoryHandler(
-		signatory.NewUsecaseSignatoryService(
-			repository.NewRepositorySignatory(conn),
-			repository.NewRepositoryEnvelope(conn),
+	signatoryHandlers := newTenantHandlers(conn, func(conn *gorm.DB) *SignatoryHandlers {
+		// Criar usecase de documento para envelopes
+		usecaseDocument := document.NewUsecaseDocumentServiceWithClicksign(
+			repository.NewRepositoryDocument(conn),
 			clicksignClient,
+			deps.Storage,
+			deps.Fetcher,
 			logger,
-		),
-		usecase_envelope.NewUsecaseEnvelopeService(
+		)
+
+		// Criar usecase de requirement
+		usecaseRequirement := requirement.NewUsecaseRequirementService(
+			repository.NewRepositoryRequirement(conn),
 			repository.NewRepositoryEnvelope(conn),
 			clicksignClient,
-			usecaseDocument,
-			usecaseRequirement,
 			logger,
-		),
-		logger,
-	)
+		)
+
+		// Importar as dependências necessárias
+		return NewSignatoryHandler(
+			signatory.NewUsecaseSignatoryService(
+				repository.NewRepositorySignatory(conn),
+				repository.NewRepositoryEnvelope(conn),
+				clicksignClient,
+				logger,
+			),
+			usecase_envelope.NewUsecaseEnvelopeService(
+				repository.NewRepositoryEnvelope(conn),
+				clicksignClient,
+				usecaseDocument,
+				usecaseRequirement,
+				deps.Storage,
+				deps.Fetcher,
+				logger,
+			),
+			logger,
+		)
+	})
 
 	// Rotas para signatários por envelope (usando :id para consistência com envelope handlers)
 	envelopeGroup := gin.Group("/api/v1/envelopes")
-	SetAuthMiddleware(conn, envelopeGroup)
-	envelopeGroup.POST("/:id/signatories", signatoryHandlers.CreateSignatoryHandler)
-	envelopeGroup.GET("/:id/signatories", signatoryHandlers.GetSignatoriesHandler)
-	envelopeGroup.POST("/:id/send", signatoryHandlers.SendSignatoriesToClicksignHandler)
+	SetScopedAuthMiddleware(conn, deps, envelopeGroup, entity.ScopeEnvelopesRead, entity.ScopeEnvelopesWrite)
+	envelopeOwners := newEnvelopeOwnership(conn)
+	envelopeGroup.Use(requireEnvelopeOwner(envelopeOwners, envelopeFromRoute))
+	envelopeGroup.POST("/:id/signatories", signatoryHandlers.Handle((*SignatoryHandlers).CreateSignatoryHandler))
+	envelopeGroup.GET("/:id/signatories", signatoryHandlers.Handle((*SignatoryHandlers).GetSignatoriesHandler))
+	envelopeGroup.POST("/:id/send", signatoryHandlers.Handle((*SignatoryHandlers).SendSignatoriesToClicksignHandler))
 
 	// Rotas para signatários individuais
 	signatoryGroup := gin.Group("/api/v1/signatories")
-	SetAuthMiddleware(conn, signatoryGroup)
-	signatoryGroup.GET("/:id", signatoryHandlers.GetSignatoryHandler)
-	signatoryGroup.PUT("/:id", signatoryHandlers.UpdateSignatoryHandler)
-	signatoryGroup.DELETE("/:id", signatoryHandlers.DeleteSignatoryHandler)
+	SetScopedAuthMiddleware(conn, deps, signatoryGroup, entity.ScopeEnvelopesRead, entity.ScopeEnvelopesWrite)
+	signatoryGroup.Use(requireEnvelopeOwner(envelopeOwners, envelopeOfSignatory))
+	signatoryGroup.GET("/:id", signatoryHandlers.Handle((*SignatoryHandlers).GetSignatoryHandler))
+	signatoryGroup.PUT("/:id", signatoryHandlers.Handle((*SignatoryHandlers).UpdateSignatoryHandler))
+	signatoryGroup.DELETE("/:id", signatoryHandlers.Handle((*SignatoryHandlers).DeleteSignatoryHandler))
 }
@@ -263,6 +263,34 @@ func TestGetSignatoriesHandler_Success(t *testing.T) {
 	assert.Equal(t, expectedSignatories[1].Name, response.Signatories[1].Name)
 }
 
+func TestGetSignatoriesHandler_MasksPII(t *testing.T) {
+	router, mockUsecaseSignatory, mockUsecaseEnvelope, ctrl := setupSignatoryHandlerTest(t)
+	defer ctrl.Finish()
+
+	documentation := "123.456.789-09"
+	birthday := "1990-01-15"
+	phoneNumber := "11999998888"
+
+	mockUsecaseEnvelope.EXPECT().GetEnvelope(1).Return(&entity.EntityEnvelope{ID: 1}, nil)
+	mockUsecaseSignatory.EXPECT().
+		GetSignatoriesByEnvelope(1).
+		Return([]entity.EntitySignatory{{ID: 1, Name: "João Silva", EnvelopeID: 1, Documentation: &documentation, Birthday: &birthday, PhoneNumber: &phoneNumber}}, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/envelopes/1/signatories", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), documentation)
+
+	var response dtos.SignatoryListResponseDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Signatories, 1)
+	assert.Equal(t, "***.***.***-09", *response.Signatories[0].Documentation)
+	assert.Equal(t, "****-**-**", *response.Signatories[0].Birthday)
+	assert.Equal(t, "*******8888", *response.Signatories[0].PhoneNumber)
+}
+
 func TestGetSignatoryHandler_Success(t *testing.T) {
 	router, mockUsecaseSignatory, _, ctrl := setupSignatoryHandlerTest(t)
 	defer ctrl.Finish()
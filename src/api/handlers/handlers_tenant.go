@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+
+	"app/api/handlers/dtos"
+	"app/api/middleware"
+	"app/entity"
+	"app/infrastructure/dependencies"
+	"app/infrastructure/repository"
+	"app/infrastructure/tenancy"
+	usecase_tenant "app/usecase/tenant"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// tenantHandlers mantém uma instância dos handlers por tenant, com repositórios criados a partir de tenancy.Scope
+// Assim todas as consultas do request ficam restritas ao tenant resolvido por TenantMiddleware
+type tenantHandlers[H any] struct {
+	conn  *gorm.DB
+	build func(conn *gorm.DB) *H
+
+	mu       sync.Mutex
+	byTenant map[string]*H
+}
+
+func newTenantHandlers[H any](conn *gorm.DB, build func(conn *gorm.DB) *H) *tenantHandlers[H] {
+	return &tenantHandlers[H]{
+		conn:     conn,
+		build:    build,
+		byTenant: map[string]*H{},
+	}
+}
+
+// For retorna os handlers do tenant, criando-os no primeiro uso
+func (t *tenantHandlers[H]) For(tenantID string) *H {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	handlers, ok := t.byTenant[tenantID]
+	if !ok {
+		handlers = t.build(tenancy.Scope(t.conn, tenantID))
+		t.byTenant[tenantID] = handlers
+	}
+	return handlers
+}
+
+// Handle adapta um método dos handlers, como (*EnvelopeHandlers).GetEnvelopeHandler, a uma rota do gin
+func (t *tenantHandlers[H]) Handle(handler func(*H, *gin.Context)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		handler(t.For(middleware.TenantFromContext(c)), c)
+	}
+}
+
+// TenantHandlers gerencia os tenants; acesso restrito aos administradores da plataforma
+type TenantHandlers struct {
+	UsecaseTenant usecase_tenant.IUsecaseTenant
+	Logger        *logrus.Logger
+}
+
+func NewTenantHandler(usecaseTenant usecase_tenant.IUsecaseTenant, logger *logrus.Logger) *TenantHandlers {
+	return &TenantHandlers{
+		UsecaseTenant: usecaseTenant,
+		Logger:        logger,
+	}
+}
+
+// @Summary Criar tenant
+// @Description Cria uma organização; usuários, API keys e credenciais de provider são vinculados a ela pelo id
+// @Description Somente administradores da plataforma (sem tenant).
+// @Tags Tenants
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param tenant body dtos.TenantCreateRequestDTO true "Dados do tenant"
+// @Success 201 {object} dtos.TenantResponseDTO "Tenant criado"
+// @Failure 400 {object} dtos.ErrorResponseDTO "Dados inválidos"
+// @Failure 403 {object} dtos.ErrorResponseDTO "Apenas administradores da plataforma"
+// @Failure 409 {object} dtos.ErrorResponseDTO "Tenant já existe"
+// @Failure 500 {object} dtos.ErrorResponseDTO "Erro interno"
+// @Router /api/v1/tenants [post]
+func (h TenantHandlers) CreateTenantHandler(c *gin.Context) {
+	var requestDTO dtos.TenantCreateRequestDTO
+	if err := c.ShouldBindJSON(&requestDTO); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	tenant, err := entity.NewTenant(entity.EntityTenant{ID: requestDTO.ID, Name: requestDTO.Name})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.UsecaseTenant.CreateTenant(tenant); err != nil {
+		if errors.Is(err, entity.ErrTenantExists) {
+			c.JSON(http.StatusConflict, dtos.ErrorResponseDTO{
+				Error:   "Conflict",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		h.Logger.WithError(err).Error("Failed to create tenant")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+			Error:   "Internal server error",
+			Message: "Failed to create tenant",
+		})
+		return
+	}
+
+	jsonResponse(c, http.StatusCreated, dtos.NewTenantResponseDTO(tenant))
+}
+
+// @Summary Listar tenants
+// @Description Retorna todos os tenants. Somente administradores da plataforma.
+// @Tags Tenants
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} dtos.TenantListResponseDTO "Lista de tenants"
+// @Failure 403 {object} dtos.ErrorResponseDTO "Apenas administradores da plataforma"
+// @Failure 500 {object} dtos.ErrorResponseDTO "Erro interno"
+// @Router /api/v1/tenants [get]
+func (h TenantHandlers) GetTenantsHandler(c *gin.Context) {
+	tenants, err := h.UsecaseTenant.GetTenants()
+	if err != nil {
+		h.Logger.WithError(err).Error("Failed to list tenants")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+			Error:   "Internal server error",
+			Message: "Failed to retrieve tenants",
+		})
+		return
+	}
+
+	responseDTOs := make([]dtos.TenantResponseDTO, 0, len(tenants))
+	for i := range tenants {
+		responseDTOs = append(responseDTOs, dtos.NewTenantResponseDTO(&tenants[i]))
+	}
+
+	jsonResponse(c, http.StatusOK, dtos.TenantListResponseDTO{
+		Tenants: responseDTOs,
+		Total:   len(responseDTOs),
+	})
+}
+
+// @Summary Buscar tenant
+// @Description Retorna o tenant pelo id. Somente administradores da plataforma.
+// @Tags Tenants
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "ID do tenant"
+// @Success 200 {object} dtos.TenantResponseDTO "Tenant encontrado"
+// @Failure 404 {object} dtos.ErrorResponseDTO "Tenant não encontrado"
+// @Router /api/v1/tenants/{id} [get]
+func (h TenantHandlers) GetTenantHandler(c *gin.Context) {
+	tenant, ok := h.loadTenant(c)
+	if !ok {
+		return
+	}
+
+	jsonResponse(c, http.StatusOK, dtos.NewTenantResponseDTO(tenant))
+}
+
+// @Summary Atualizar tenant
+// @Description Altera o nome ou desativa o tenant; usuários e API keys de um tenant inativo têm o acesso bloqueado
+// @Description Somente administradores da plataforma.
+// @Tags Tenants
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "ID do tenant"
+// @Param tenant body dtos.TenantUpdateRequestDTO true "Dados para atualização"
+// @Success 200 {object} dtos.TenantResponseDTO "Tenant atualizado"
+// @Failure 400 {object} dtos.ErrorResponseDTO "Dados inválidos"
+// @Failure 404 {object} dtos.ErrorResponseDTO "Tenant não encontrado"
+// @Failure 500 {object} dtos.ErrorResponseDTO "Erro interno"
+// @Router /api/v1/tenants/{id} [put]
+func (h TenantHandlers) UpdateTenantHandler(c *gin.Context) {
+	var requestDTO dtos.TenantUpdateRequestDTO
+	if err := c.ShouldBindJSON(&requestDTO); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Validation failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	tenant, ok := h.loadTenant(c)
+	if !ok {
+		return
+	}
+
+	if requestDTO.Name != nil {
+		tenant.Name = *requestDTO.Name
+	}
+	if requestDTO.Active != nil {
+		tenant.Active = *requestDTO.Active
+	}
+
+	if err := h.UsecaseTenant.UpdateTenant(tenant); err != nil {
+		h.Logger.WithError(err).Error("Failed to update tenant")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+			Error:   "Internal server error",
+			Message: "Failed to update tenant",
+		})
+		return
+	}
+
+	jsonResponse(c, http.StatusOK, dtos.NewTenantResponseDTO(tenant))
+}
+
+// loadTenant busca o tenant do parâmetro :id e já responde 404/500 quando não o encontra
+func (h TenantHandlers) loadTenant(c *gin.Context) (*entity.EntityTenant, bool) {
+	tenant, err := h.UsecaseTenant.GetTenant(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, entity.ErrTenantNotFound) {
+			c.JSON(http.StatusNotFound, dtos.ErrorResponseDTO{
+				Error:   "Tenant not found",
+				Message: "The requested tenant does not exist",
+			})
+			return nil, false
+		}
+
+		h.Logger.WithError(err).Error("Failed to get tenant")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+			Error:   "Internal server error",
+			Message: "Failed to get tenant",
+		})
+		return nil, false
+	}
+
+	return tenant, true
+}
+
+func MountTenantHandlers(gin *gin.Engine, conn *gorm.DB, deps *dependencies.Dependencies) {
+	logger := deps.Logger
+	tenantHandlers := NewTenantHandler(
+		usecase_tenant.NewUsecaseTenantService(repository.NewRepositoryTenant(conn), logger),
+		logger,
+	)
+
+	group := gin.Group("/api/v1/tenants")
+	SetAuthMiddleware(conn, deps, group)
+	group.Use(middleware.RequirePlatformAdminMiddleware())
+
+	group.POST("/", tenantHandlers.CreateTenantHandler)
+	group.GET("/", tenantHandlers.GetTenantsHandler)
+	group.GET("/:id", tenantHandlers.GetTenantHandler)
+	group.PUT("/:id", tenantHandlers.UpdateTenantHandler)
+}
@@ -10,7 +10,7 @@ import (
 func TestNewDocumentHandler(t *testing.T) {
 	// Test that NewDocumentHandler creates a handler instance
 	// This is a simple unit test to verify the constructor works
-	handler := NewDocumentHandler(nil, nil)
+	handler := NewDocumentHandler(nil, nil, nil)
 	assert.NotNil(t, handler)
 	assert.Nil(t, handler.UsecaseDocument) // Since we passed nil
 	assert.Nil(t, handler.Logger)          // Since we passed nil
@@ -31,7 +31,7 @@ func TestDocumentDTOsStructure(t *testing.T) {
 
 func TestDocumentHandlerHelperMethods(t *testing.T) {
 	// Test the helper methods work correctly
-	handler := NewDocumentHandler(nil, nil)
+	handler := NewDocumentHandler(nil, nil, nil)
 	assert.NotNil(t, handler)
 	
 	// Test that helper methods exist (compilation test)
@@ -1,13 +1,22 @@
 package handlers
 
 import (
+	"app/api/handlers/dtos"
+	"app/api/middleware"
+	"app/config"
 	"app/entity"
+	"app/infrastructure/dependencies"
+	"app/infrastructure/jwtkeys"
+	"app/infrastructure/ratelimit"
 	"app/infrastructure/repository"
 	usecase_user "app/usecase/user"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
@@ -25,10 +34,11 @@ type UpdateUserPasswordData struct {
 
 type UserHandlers struct {
 	UsecaseUser usecase_user.IUsecaseUser
+	Keys        *jwtkeys.KeySet
 }
 
-func NewUserHandler(usecaseUser usecase_user.IUsecaseUser) *UserHandlers {
-	return &UserHandlers{UsecaseUser: usecaseUser}
+func NewUserHandler(usecaseUser usecase_user.IUsecaseUser, keys *jwtkeys.KeySet) *UserHandlers {
+	return &UserHandlers{UsecaseUser: usecaseUser, Keys: keys}
 }
 
 // @Summary Login
@@ -38,7 +48,9 @@ func NewUserHandler(usecaseUser usecase_user.IUsecaseUser) *UserHandlers {
 // @Produce  json
 // @Param email body string true "Email"
 // @Param password body string true "Password"
-// @Success 200 {object} entity.EntityUser "success"
+// @Success 200 {object} dtos.TokenResponseDTO "success"
+// @Failure 403 {object} dtos.ErrorResponseDTO "login local desativado (OIDC_ALLOW_LOCAL_LOGIN=false) ou usuário desativado"
+// @Failure 429 {object} dtos.ErrorResponseDTO "cota de login do IP esgotada ou conta bloqueada após falhas seguidas (LOGIN_LOCKOUT_*)"
 // @Router /api/login [post]
 func (h UserHandlers) LoginHandler(c *gin.Context) {
 
@@ -51,17 +63,139 @@ func (h UserHandlers) LoginHandler(c *gin.Context) {
 
 	user, err := h.UsecaseUser.LoginUser(loginData.Email, loginData.Password)
 
+	if errors.Is(err, entity.ErrAccountLocked) {
+		c.JSON(http.StatusTooManyRequests, dtos.ErrorResponseDTO{
+			Error:   "Account locked",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if errors.Is(err, entity.ErrUserInactive) {
+		c.JSON(http.StatusForbidden, dtos.ErrorResponseDTO{
+			Error:   "User inactive",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if errors.Is(err, entity.ErrLocalLoginDisabled) {
+		c.JSON(http.StatusForbidden, dtos.ErrorResponseDTO{
+			Error:   "Local login disabled",
+			Message: err.Error(),
+		})
+		return
+	}
+
 	if exception := handleError(c, err); exception {
 		return
 	}
 
-	token, refreshToken, err := usecase_user.JWTTokenGenerator(*user)
+	tokens, err := h.UsecaseUser.IssueTokens(user)
+
+	if exception := handleError(c, err); exception {
+		return
+	}
+
+	jsonResponse(c, http.StatusOK, newTokenResponseDTO(tokens))
+}
+
+// @Summary Refresh token
+// @Description Troca o refresh token por um novo par de tokens. O refresh token apresentado deixa de valer; reapresentá-lo revoga todas as sessões derivadas dele
+// @Tags User
+// @Accept  json
+// @Produce  json
+// @Param request body dtos.RefreshTokenRequestDTO true "Refresh token"
+// @Success 200 {object} dtos.TokenResponseDTO "success"
+// @Failure 400 {object} dtos.ErrorResponseDTO
+// @Failure 401 {object} dtos.ErrorResponseDTO
+// @Router /api/token/refresh [post]
+func (h UserHandlers) RefreshTokenHandler(c *gin.Context) {
+	var request dtos.RefreshTokenRequestDTO
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	tokens, err := h.UsecaseUser.RefreshTokens(request.RefreshToken)
+	if err != nil {
+		if isRefreshTokenError(err) {
+			c.JSON(http.StatusUnauthorized, dtos.ErrorResponseDTO{
+				Error:   "Invalid refresh token",
+				Message: err.Error(),
+			})
+			return
+		}
+		handleError(c, err)
+		return
+	}
+
+	jsonResponse(c, http.StatusOK, newTokenResponseDTO(tokens))
+}
+
+// @Summary Logout
+// @Description Revoga o access token atual e a família do refresh token informado. Com allSessions, encerra todas as sessões do usuário
+// @Tags User
+// @Accept  json
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param request body dtos.LogoutRequestDTO false "Logout"
+// @Success 204
+// @Failure 400 {object} dtos.ErrorResponseDTO
+// @Router /api/logout [post]
+func (h UserHandlers) LogoutHandler(c *gin.Context) {
+	var request dtos.LogoutRequestDTO
+
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+				Error:   "Invalid request body",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	accessToken := strings.TrimSpace(strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer"))
+	err := h.UsecaseUser.Logout(accessToken, request.RefreshToken, request.AllSessions)
+
+	if errors.Is(err, entity.ErrExternalIdentity) {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "External token",
+			Message: err.Error(),
+		})
+		return
+	}
 
 	if exception := handleError(c, err); exception {
 		return
 	}
 
-	jsonResponse(c, http.StatusOK, gin.H{"token": token, "refreshToken": refreshToken})
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary JWKS
+// @Description Chaves públicas para validar os tokens de usuário. Disponível apenas com JWT_ALGORITHM=RS256
+// @Tags User
+// @Produce  json
+// @Success 200 {object} dtos.JWKSResponseDTO "success"
+// @Failure 404 {object} dtos.ErrorResponseDTO
+// @Router /.well-known/jwks.json [get]
+func (h UserHandlers) JWKSHandler(c *gin.Context) {
+	if h.Keys.Algorithm() != jwtkeys.AlgorithmRS256 {
+		c.JSON(http.StatusNotFound, dtos.ErrorResponseDTO{
+			Error:   "Not found",
+			Message: "tokens are signed with a shared secret; there are no public keys to publish",
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	jsonResponse(c, http.StatusOK, dtos.JWKSResponseDTO{Keys: h.Keys.JWKS()})
 }
 
 // @Summary Get me
@@ -73,9 +207,10 @@ func (h UserHandlers) LoginHandler(c *gin.Context) {
 // @Success 200 {object} entity.EntityUser "success"
 // @Router /api/user/me [get]
 func (h UserHandlers) GetMeHandler(c *gin.Context) {
-	user, err := h.UsecaseUser.GetUserByToken(c.GetHeader("Authorization"))
+	user, exists := c.Get("user")
 
-	if exception := handleError(c, err); exception {
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
 		return
 	}
 
@@ -90,6 +225,8 @@ func (h UserHandlers) GetMeHandler(c *gin.Context) {
 // @Security ApiKeyAuth
 // @Param entity.EntityUser body entity.EntityUser true "User"
 // @Success 200 {object} entity.EntityUser "success"
+// @Failure 400 {object} dtos.ErrorResponseDTO "Senha fora da política (PASSWORD_*)"
+// @Failure 403 {object} dtos.ErrorResponseDTO "Sem users:admin ou papel/permissões acima dos próprios"
 // @Router /api/user/create [post]
 func (h UserHandlers) CreateUserHandler(c *gin.Context) {
 
@@ -100,8 +237,16 @@ func (h UserHandlers) CreateUserHandler(c *gin.Context) {
 		return
 	}
 
+	if !canGrantRole(c, &entityUser) {
+		return
+	}
+
 	err := h.UsecaseUser.Create(&entityUser)
 
+	if weakPassword(c, err) {
+		return
+	}
+
 	if exception := handleError(c, err); exception {
 		return
 	}
@@ -118,6 +263,7 @@ func (h UserHandlers) CreateUserHandler(c *gin.Context) {
 // @Param id path int true "User ID"
 // @Param entity.EntityUser body entity.EntityUser true "User"
 // @Success 200 {object} entity.EntityUser "success"
+// @Failure 403 {object} dtos.ErrorResponseDTO "Sem users:admin ou papel/permissões acima dos próprios"
 // @Router /api/user/{id} [put]
 func (h UserHandlers) UpdateUserHandler(c *gin.Context) {
 
@@ -134,6 +280,10 @@ func (h UserHandlers) UpdateUserHandler(c *gin.Context) {
 		return
 	}
 
+	if !canGrantRole(c, &entityUser) {
+		return
+	}
+
 	err := h.UsecaseUser.Update(&entityUser)
 
 	if exception := handleError(c, err); exception {
@@ -179,6 +329,7 @@ func (h UserHandlers) DeleteUserHandler(c *gin.Context) {
 // @Param id path int true "User ID"
 // @Param entity.EntityUser body entity.EntityUser true "User"
 // @Success 200 {object} entity.EntityUser "success"
+// @Failure 400 {object} dtos.ErrorResponseDTO "Senha fora da política (PASSWORD_*)"
 // @Router /api/user/password/{id} [put]
 func (h UserHandlers) UpdatePasswordHandler(c *gin.Context) {
 
@@ -193,6 +344,10 @@ func (h UserHandlers) UpdatePasswordHandler(c *gin.Context) {
 
 	err := h.UsecaseUser.UpdatePassword(id, updatePasswordData.OldPassword, updatePasswordData.NewPassword, updatePasswordData.ConfirmPassword)
 
+	if weakPassword(c, err) {
+		return
+	}
+
 	if exception := handleError(c, err); exception {
 		return
 	}
@@ -248,28 +403,112 @@ func (h UserHandlers) GetUserHandler(c *gin.Context) {
 	jsonResponse(c, http.StatusOK, user)
 }
 
-func MountUsersHandlers(gin *gin.Engine, conn *gorm.DB) {
+// canGrantRole impede que o usuário autenticado atribua papel ou permissões que ele próprio não tem
+func canGrantRole(c *gin.Context, target *entity.EntityUser) bool {
+	value, _ := c.Get("user")
+	caller, _ := value.(entity.EntityUser)
+	if caller.CanGrant(target) {
+		return true
+	}
+
+	c.JSON(http.StatusForbidden, dtos.ErrorResponseDTO{
+		Error:   "Forbidden",
+		Message: entity.ErrPermissionDenied.Error() + ": role or permissions above your own",
+	})
+	return false
+}
+
+func newTokenResponseDTO(tokens *usecase_user.TokenPair) dtos.TokenResponseDTO {
+	return dtos.TokenResponseDTO{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    tokens.ExpiresIn,
+	}
+}
+
+func isRefreshTokenError(err error) bool {
+	return errors.Is(err, entity.ErrInvalidRefreshToken) ||
+		errors.Is(err, entity.ErrRefreshTokenExpired) ||
+		errors.Is(err, entity.ErrRefreshTokenRevoked) ||
+		errors.Is(err, entity.ErrRefreshTokenReused) ||
+		errors.Is(err, gorm.ErrRecordNotFound)
+}
 
-	userHandlers := NewUserHandler(
-		usecase_user.NewService(
-			repository.NewUserPostgres(conn),
-		),
+func MountUsersHandlers(gin *gin.Engine, conn *gorm.DB, deps *dependencies.Dependencies) {
+
+	keys := deps.TokenKeys
+	usecaseUser := usecase_user.NewServiceWithTokens(
+		repository.NewUserPostgres(conn),
+		repository.NewRepositoryToken(conn),
+		keys,
+		identityVerifier(deps),
 	)
+	usecaseUser.SetLoginAttempts(loginAttempts(deps))
+	userHandlers := NewUserHandler(usecaseUser, keys)
 
-	gin.GET("/", HomeHandler)
-	gin.POST("/api/login", userHandlers.LoginHandler)
+	// Login e refresh têm cota própria por IP contra força bruta, além do bloqueio da conta após falhas seguidas
+	loginRateLimit := middleware.RateLimitByIPMiddleware(deps.Limiter, ratelimit.GroupLogin)
 
-	gin.POST("/login", userHandlers.LoginHandler)
+	gin.GET("/", HomeHandler)
+	gin.POST("/api/login", loginRateLimit, userHandlers.LoginHandler)
+
+	gin.POST("/login", loginRateLimit, userHandlers.LoginHandler)
+
+	gin.POST("/api/token/refresh", loginRateLimit, userHandlers.RefreshTokenHandler)
+	gin.GET("/.well-known/jwks.json", userHandlers.JWKSHandler)
+
+	// Convite e redefinição de senha: o token recebido por email autentica a requisição
+	accountHandlers := NewUserAccountHandler(newUserAccountService(conn, deps.Logger))
+	gin.POST("/api/invitations/accept", loginRateLimit, accountHandlers.AcceptInvitationHandler)
+	gin.POST("/api/password/forgot", loginRateLimit, accountHandlers.ForgotPasswordHandler)
+	gin.POST("/api/password/reset", loginRateLimit, accountHandlers.ResetPasswordHandler)
+
+	gin.POST("/api/logout", middleware.AuthenticatedMiddleware(userHandlers.UsecaseUser), userHandlers.LogoutHandler)
+
+	// user: cada tenant administra apenas os próprios usuários
+	tenantUserHandlers := newTenantHandlers(conn, func(conn *gorm.DB) *UserHandlers {
+		return NewUserHandler(
+			usecase_user.NewServiceWithTokens(
+				repository.NewUserPostgres(conn),
+				repository.NewRepositoryToken(conn),
+				keys,
+				identityVerifier(deps),
+			),
+			keys,
+		)
+	})
 
-	// user
 	group := gin.Group("/api/user")
-	SetAuthMiddleware(conn, group)
-
-	group.GET("/me", userHandlers.GetMeHandler)
-	group.POST("/create", userHandlers.CreateUserHandler)
-	group.PUT("/:id", userHandlers.UpdateUserHandler)
-	group.DELETE("/:id", userHandlers.DeleteUserHandler)
-	group.PUT("/password/:id", userHandlers.UpdatePasswordHandler)
-	group.GET("/list", userHandlers.GetUsersHandler)
-	group.GET("/:id", userHandlers.GetUserHandler)
+	SetAuthMiddleware(conn, deps, group)
+
+	group.GET("/me", tenantUserHandlers.Handle((*UserHandlers).GetMeHandler))
+	group.PUT("/password/:id", tenantUserHandlers.Handle((*UserHandlers).UpdatePasswordHandler))
+
+	// Gestão de usuários: apenas quem tem users:admin (papel admin)
+	usersAdmin := middleware.RequirePermissionMiddleware(entity.PermissionUsersAdmin)
+	group.POST("/create", usersAdmin, tenantUserHandlers.Handle((*UserHandlers).CreateUserHandler))
+	group.PUT("/:id", usersAdmin, tenantUserHandlers.Handle((*UserHandlers).UpdateUserHandler))
+	group.DELETE("/:id", usersAdmin, tenantUserHandlers.Handle((*UserHandlers).DeleteUserHandler))
+	group.GET("/list", usersAdmin, tenantUserHandlers.Handle((*UserHandlers).GetUsersHandler))
+	group.GET("/:id", usersAdmin, tenantUserHandlers.Handle((*UserHandlers).GetUserHandler))
+
+	tenantAccountHandlers := newTenantHandlers(conn, func(conn *gorm.DB) *UserAccountHandlers {
+		return NewUserAccountHandler(newUserAccountService(conn, deps.Logger))
+	})
+	group.POST("/invite", usersAdmin, tenantAccountHandlers.Handle((*UserAccountHandlers).InviteUserHandler))
+	group.POST("/:id/invite", usersAdmin, tenantAccountHandlers.Handle((*UserAccountHandlers).ResendInvitationHandler))
+	group.POST("/:id/deactivate", usersAdmin, tenantAccountHandlers.Handle((*UserAccountHandlers).DeactivateUserHandler))
+	group.POST("/:id/activate", usersAdmin, tenantAccountHandlers.Handle((*UserAccountHandlers).ActivateUserHandler))
+}
+
+// newUserAccountService cria o serviço de convite, redefinição de senha e desativação sobre conn
+func newUserAccountService(conn *gorm.DB, logger *logrus.Logger) *usecase_user.UseCaseUserAccount {
+	return usecase_user.NewUserAccountService(
+		repository.NewUserPostgres(conn),
+		repository.NewRepositoryToken(conn),
+		accountMailer(logger),
+		usecase_user.AccountSettingsFromConfig(config.EnvironmentVariables),
+		logger,
+	)
 }
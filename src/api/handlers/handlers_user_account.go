@@ -0,0 +1,299 @@
+package handlers
+
+import (
+	"app/api/handlers/dtos"
+	"app/api/middleware"
+	"app/entity"
+	usecase_user "app/usecase/user"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type UserAccountHandlers struct {
+	UsecaseAccount usecase_user.IUsecaseUserAccount
+}
+
+func NewUserAccountHandler(usecaseAccount usecase_user.IUsecaseUserAccount) *UserAccountHandlers {
+	return &UserAccountHandlers{UsecaseAccount: usecaseAccount}
+}
+
+// @Summary Invite user
+// @Description Cadastra o usuário e envia por email o link para que ele escolha a própria senha. O link vale USER_INVITATION_TTL_HOURS
+// @Tags User
+// @Accept  json
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param request body dtos.UserInviteRequestDTO true "Convite"
+// @Success 201 {object} map[string]string "success"
+// @Failure 400 {object} dtos.ErrorResponseDTO
+// @Failure 403 {object} dtos.ErrorResponseDTO "Sem users:admin ou papel/permissões acima dos próprios"
+// @Failure 409 {object} dtos.ErrorResponseDTO "Email já cadastrado"
+// @Failure 503 {object} dtos.ErrorResponseDTO "Envio de emails não configurado (EMAIL_HOST)"
+// @Router /api/user/invite [post]
+func (h UserAccountHandlers) InviteUserHandler(c *gin.Context) {
+	var request dtos.UserInviteRequestDTO
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	user := entity.EntityUser{
+		Name:        request.Name,
+		Email:       request.Email,
+		Role:        request.Role,
+		Permissions: request.Permissions,
+	}
+	if !canGrantRole(c, &user) {
+		return
+	}
+
+	err := h.UsecaseAccount.Invite(c.Request.Context(), &user)
+
+	if handleAccountError(c, err) {
+		return
+	}
+
+	jsonResponse(c, http.StatusCreated, gin.H{"message": "Invitation sent successfully"})
+}
+
+// @Summary Resend invitation
+// @Description Envia um novo convite ao usuário; os links enviados antes deixam de valer
+// @Tags User
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]string "success"
+// @Failure 404 {object} dtos.ErrorResponseDTO
+// @Failure 409 {object} dtos.ErrorResponseDTO "Usuário desativado"
+// @Failure 503 {object} dtos.ErrorResponseDTO "Envio de emails não configurado (EMAIL_HOST)"
+// @Router /api/user/{id}/invite [post]
+func (h UserAccountHandlers) ResendInvitationHandler(c *gin.Context) {
+	id, ok := accountUserID(c)
+	if !ok {
+		return
+	}
+
+	err := h.UsecaseAccount.ResendInvitation(c.Request.Context(), id)
+
+	if handleAccountError(c, err) {
+		return
+	}
+
+	jsonResponse(c, http.StatusOK, gin.H{"message": "Invitation sent successfully"})
+}
+
+// @Summary Accept invitation
+// @Description Define a senha do convidado com o token recebido por email. O token só pode ser usado uma vez
+// @Tags User
+// @Accept  json
+// @Produce  json
+// @Param request body dtos.AcceptInvitationRequestDTO true "Aceite do convite"
+// @Success 200 {object} map[string]string "success"
+// @Failure 400 {object} dtos.ErrorResponseDTO "Token inválido, expirado ou já usado, ou senha fora da política (PASSWORD_*)"
+// @Router /api/invitations/accept [post]
+func (h UserAccountHandlers) AcceptInvitationHandler(c *gin.Context) {
+	var request dtos.AcceptInvitationRequestDTO
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err := h.UsecaseAccount.AcceptInvitation(request.Token, request.Password)
+
+	if handleAccountError(c, err) {
+		return
+	}
+
+	jsonResponse(c, http.StatusOK, gin.H{"message": "Invitation accepted successfully"})
+}
+
+// @Summary Forgot password
+// @Description Envia por email o link de redefinição de senha. A resposta é a mesma para emails não cadastrados. O link vale PASSWORD_RESET_TTL_MINUTES
+// @Tags User
+// @Accept  json
+// @Produce  json
+// @Param request body dtos.ForgotPasswordRequestDTO true "Email"
+// @Success 202 {object} map[string]string "success"
+// @Failure 400 {object} dtos.ErrorResponseDTO
+// @Failure 503 {object} dtos.ErrorResponseDTO "Envio de emails não configurado (EMAIL_HOST)"
+// @Router /api/password/forgot [post]
+func (h UserAccountHandlers) ForgotPasswordHandler(c *gin.Context) {
+	var request dtos.ForgotPasswordRequestDTO
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err := h.UsecaseAccount.RequestPasswordReset(c.Request.Context(), request.Email)
+
+	if handleAccountError(c, err) {
+		return
+	}
+
+	jsonResponse(c, http.StatusAccepted, gin.H{"message": "If the email is registered, a reset link has been sent"})
+}
+
+// @Summary Reset password
+// @Description Define a nova senha com o token recebido por email e encerra todas as sessões do usuário
+// @Tags User
+// @Accept  json
+// @Produce  json
+// @Param request body dtos.ResetPasswordRequestDTO true "Redefinição de senha"
+// @Success 200 {object} map[string]string "success"
+// @Failure 400 {object} dtos.ErrorResponseDTO "Token inválido, expirado ou já usado, ou senha fora da política (PASSWORD_*)"
+// @Router /api/password/reset [post]
+func (h UserAccountHandlers) ResetPasswordHandler(c *gin.Context) {
+	var request dtos.ResetPasswordRequestDTO
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err := h.UsecaseAccount.ResetPassword(request.Token, request.Password)
+
+	if handleAccountError(c, err) {
+		return
+	}
+
+	jsonResponse(c, http.StatusOK, gin.H{"message": "Password reset successfully"})
+}
+
+// @Summary Deactivate user
+// @Description Bloqueia o acesso do usuário e encerra todas as sessões dele
+// @Tags User
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]string "success"
+// @Failure 403 {object} dtos.ErrorResponseDTO "O usuário não pode desativar a si mesmo"
+// @Failure 404 {object} dtos.ErrorResponseDTO
+// @Router /api/user/{id}/deactivate [post]
+func (h UserAccountHandlers) DeactivateUserHandler(c *gin.Context) {
+	id, ok := accountUserID(c)
+	if !ok {
+		return
+	}
+
+	if id == middleware.UserIDFromContext(c) {
+		c.JSON(http.StatusForbidden, dtos.ErrorResponseDTO{
+			Error:   "Forbidden",
+			Message: "users cannot deactivate themselves",
+		})
+		return
+	}
+
+	err := h.UsecaseAccount.Deactivate(id)
+
+	if handleAccountError(c, err) {
+		return
+	}
+
+	jsonResponse(c, http.StatusOK, gin.H{"message": "User deactivated successfully"})
+}
+
+// @Summary Activate user
+// @Description Devolve o acesso ao usuário desativado
+// @Tags User
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]string "success"
+// @Failure 404 {object} dtos.ErrorResponseDTO
+// @Router /api/user/{id}/activate [post]
+func (h UserAccountHandlers) ActivateUserHandler(c *gin.Context) {
+	id, ok := accountUserID(c)
+	if !ok {
+		return
+	}
+
+	err := h.UsecaseAccount.Activate(id)
+
+	if handleAccountError(c, err) {
+		return
+	}
+
+	jsonResponse(c, http.StatusOK, gin.H{"message": "User activated successfully"})
+}
+
+func accountUserID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Invalid ID",
+			Message: "ID must be a positive integer",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// handleAccountError traduz os erros do ciclo de vida da conta; os demais seguem para handleError
+func handleAccountError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, usecase_user.ErrMailerNotConfigured):
+		c.JSON(http.StatusServiceUnavailable, dtos.ErrorResponseDTO{
+			Error:   "Email disabled",
+			Message: err.Error(),
+		})
+	case errors.Is(err, entity.ErrInvalidUserToken), errors.Is(err, entity.ErrUserTokenUsed):
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Invalid token",
+			Message: err.Error(),
+		})
+	case errors.Is(err, entity.ErrWeakPassword):
+		weakPassword(c, err)
+	case errors.Is(err, entity.ErrUnknownRole), errors.Is(err, entity.ErrUnknownPermission):
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+			Error:   "Invalid role",
+			Message: err.Error(),
+		})
+	case errors.Is(err, usecase_user.ErrUserAlreadyExists), errors.Is(err, entity.ErrUserInactive):
+		c.JSON(http.StatusConflict, dtos.ErrorResponseDTO{
+			Error:   "Conflict",
+			Message: err.Error(),
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, dtos.ErrorResponseDTO{
+			Error:   "Not found",
+			Message: "user not found",
+		})
+	default:
+		handleError(c, err)
+	}
+	return true
+}
+
+// weakPassword responde 400 para senhas fora da política
+func weakPassword(c *gin.Context, err error) bool {
+	if !errors.Is(err, entity.ErrWeakPassword) {
+		return false
+	}
+
+	c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+		Error:   "Weak password",
+		Message: err.Error(),
+	})
+	return true
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"app/api/handlers/dtos"
+	"app/entity"
+	"app/mocks"
+	usecase_user "app/usecase/user"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func performAccountRequest(handler *UserAccountHandlers, caller *entity.EntityUser, method, path string, body interface{}) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if caller != nil {
+		router.Use(func(c *gin.Context) { c.Set("user", *caller) })
+	}
+	router.POST("/api/user/invite", handler.InviteUserHandler)
+	router.POST("/api/user/:id/deactivate", handler.DeactivateUserHandler)
+	router.POST("/api/invitations/accept", handler.AcceptInvitationHandler)
+	router.POST("/api/password/forgot", handler.ForgotPasswordHandler)
+	router.POST("/api/password/reset", handler.ResetPasswordHandler)
+
+	reader := &bytes.Buffer{}
+	if body != nil {
+		jsonData, _ := json.Marshal(body)
+		reader = bytes.NewBuffer(jsonData)
+	}
+	req, _ := http.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestUserAccountHandlers_Invite(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockIUsecaseUserAccount(ctrl)
+	handler := NewUserAccountHandler(mockUsecase)
+	admin := &entity.EntityUser{ID: 1, Role: entity.RoleAdmin, IsAdmin: true}
+	request := dtos.UserInviteRequestDTO{Name: "Maria", Email: "maria@empresa.com", Role: entity.RoleViewer}
+
+	t.Run("should invite the user", func(t *testing.T) {
+		mockUsecase.EXPECT().Invite(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, user *entity.EntityUser) error {
+			assert.Equal(t, "maria@empresa.com", user.Email)
+			assert.Equal(t, entity.RoleViewer, user.Role)
+			return nil
+		})
+
+		w := performAccountRequest(handler, admin, http.MethodPost, "/api/user/invite", request)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("should not grant roles above the caller", func(t *testing.T) {
+		operator := &entity.EntityUser{ID: 2, Role: entity.RoleOperator}
+
+		w := performAccountRequest(handler, operator, http.MethodPost, "/api/user/invite", dtos.UserInviteRequestDTO{Name: "Maria", Email: "maria@empresa.com", Role: entity.RoleAdmin})
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("should answer 409 for registered emails and 503 without email delivery", func(t *testing.T) {
+		mockUsecase.EXPECT().Invite(gomock.Any(), gomock.Any()).Return(usecase_user.ErrUserAlreadyExists)
+		w := performAccountRequest(handler, admin, http.MethodPost, "/api/user/invite", request)
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		mockUsecase.EXPECT().Invite(gomock.Any(), gomock.Any()).Return(usecase_user.ErrMailerNotConfigured)
+		w = performAccountRequest(handler, admin, http.MethodPost, "/api/user/invite", request)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+}
+
+func TestUserAccountHandlers_Password(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockIUsecaseUserAccount(ctrl)
+	handler := NewUserAccountHandler(mockUsecase)
+
+	t.Run("should accept the reset request", func(t *testing.T) {
+		mockUsecase.EXPECT().RequestPasswordReset(gomock.Any(), "maria@empresa.com").Return(nil)
+
+		w := performAccountRequest(handler, nil, http.MethodPost, "/api/password/forgot", dtos.ForgotPasswordRequestDTO{Email: "maria@empresa.com"})
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+	})
+
+	t.Run("should answer 400 for invalid tokens and weak passwords", func(t *testing.T) {
+		mockUsecase.EXPECT().ResetPassword("token", "Segura2026!").Return(entity.ErrUserTokenUsed)
+		w := performAccountRequest(handler, nil, http.MethodPost, "/api/password/reset", dtos.ResetPasswordRequestDTO{Token: "token", Password: "Segura2026!"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		mockUsecase.EXPECT().AcceptInvitation("token", "fraca").Return(entity.ErrWeakPassword)
+		w = performAccountRequest(handler, nil, http.MethodPost, "/api/invitations/accept", dtos.AcceptInvitationRequestDTO{Token: "token", Password: "fraca"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestUserAccountHandlers_Deactivate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockIUsecaseUserAccount(ctrl)
+	handler := NewUserAccountHandler(mockUsecase)
+	admin := &entity.EntityUser{ID: 1, Role: entity.RoleAdmin, IsAdmin: true}
+
+	mockUsecase.EXPECT().Deactivate(7).Return(nil)
+	w := performAccountRequest(handler, admin, http.MethodPost, "/api/user/7/deactivate", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	mockUsecase.EXPECT().Deactivate(9).Return(gorm.ErrRecordNotFound)
+	w = performAccountRequest(handler, admin, http.MethodPost, "/api/user/9/deactivate", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = performAccountRequest(handler, admin, http.MethodPost, "/api/user/1/deactivate", nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
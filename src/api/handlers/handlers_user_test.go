@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"app/api/handlers/dtos"
+	"app/entity"
+	"app/infrastructure/jwtkeys"
+	"app/mocks"
+	usecase_user "app/usecase/user"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func performUserRequest(handler *UserHandlers, method, path string, body interface{}) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/token/refresh", handler.RefreshTokenHandler)
+	router.POST("/api/logout", handler.LogoutHandler)
+	router.GET("/.well-known/jwks.json", handler.JWKSHandler)
+
+	reader := &bytes.Buffer{}
+	if body != nil {
+		jsonData, _ := json.Marshal(body)
+		reader = bytes.NewBuffer(jsonData)
+	}
+	req, _ := http.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer access-token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestUserHandlers_RefreshToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockIUsecaseUser(ctrl)
+	handler := NewUserHandler(mockUsecase, jwtkeys.NewKeySet(jwtkeys.NewHMACKey("test", "secret")))
+
+	t.Run("should return the new token pair", func(t *testing.T) {
+		mockUsecase.EXPECT().RefreshTokens("refresh").Return(&usecase_user.TokenPair{AccessToken: "access", RefreshToken: "next", ExpiresIn: 3600}, nil)
+
+		w := performUserRequest(handler, http.MethodPost, "/api/token/refresh", dtos.RefreshTokenRequestDTO{RefreshToken: "refresh"})
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response dtos.TokenResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, dtos.TokenResponseDTO{Token: "access", RefreshToken: "next", TokenType: "Bearer", ExpiresIn: 3600}, response)
+	})
+
+	t.Run("should answer 401 when the token was reused", func(t *testing.T) {
+		mockUsecase.EXPECT().RefreshTokens("refresh").Return(nil, entity.ErrRefreshTokenReused)
+
+		w := performUserRequest(handler, http.MethodPost, "/api/token/refresh", dtos.RefreshTokenRequestDTO{RefreshToken: "refresh"})
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("should require the refresh token", func(t *testing.T) {
+		w := performUserRequest(handler, http.MethodPost, "/api/token/refresh", dtos.RefreshTokenRequestDTO{})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestUserHandlers_Logout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUsecase := mocks.NewMockIUsecaseUser(ctrl)
+	handler := NewUserHandler(mockUsecase, jwtkeys.NewKeySet(jwtkeys.NewHMACKey("test", "secret")))
+
+	mockUsecase.EXPECT().Logout("access-token", "refresh", true).Return(nil)
+	w := performUserRequest(handler, http.MethodPost, "/api/logout", dtos.LogoutRequestDTO{RefreshToken: "refresh", AllSessions: true})
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	mockUsecase.EXPECT().Logout("access-token", "", false).Return(nil)
+	w = performUserRequest(handler, http.MethodPost, "/api/logout", nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestUserHandlers_JWKS(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUsecase := mocks.NewMockIUsecaseUser(ctrl)
+
+	w := performUserRequest(NewUserHandler(mockUsecase, jwtkeys.NewKeySet(jwtkeys.NewHMACKey("test", "secret"))), http.MethodGet, "/.well-known/jwks.json", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	w = performUserRequest(NewUserHandler(mockUsecase, jwtkeys.NewKeySet(jwtkeys.NewRSAKey("2026-06", private))), http.MethodGet, "/.well-known/jwks.json", nil)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response dtos.JWKSResponseDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Keys, 1)
+	assert.Equal(t, "2026-06", response.Keys[0].KeyID)
+}
@@ -33,17 +33,18 @@ func NewWebhookHandler(webhookUsecase webhook.UsecaseWebhookInterface, logger *l
 // @Accept json
 // @Produce json
 // @Param webhook body dtos.WebhookRequestDTO true "Dados do webhook"
+// @Param Content-Hmac header string true "HMAC-SHA256 do corpo (sha256=<hex>)"
 // @Success 200 {object} dtos.WebhookResponseDTO
 // @Failure 400 {object} dtos.ErrorResponseDTO
+// @Failure 401 {object} dtos.ErrorResponseDTO
+// @Failure 413 {object} dtos.ErrorResponseDTO
 // @Failure 500 {object} dtos.ErrorResponseDTO
 // @Router /api/v1/webhooks [post]
 func (h *WebhookHandler) ReceiveWebhook(c *gin.Context) {
 	// Ler o body da requisição
 	body, err := io.ReadAll(c.Request.Body)
 	if err != nil {
-		h.logger.Error("Failed to read request body", map[string]interface{}{
-			"error": err.Error(),
-		})
+		h.logger.WithError(err).Error("Failed to read request body")
 		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
 			Error:   "BAD_REQUEST",
 			Message: "Falha ao ler o corpo da requisição",
@@ -54,10 +55,7 @@ func (h *WebhookHandler) ReceiveWebhook(c *gin.Context) {
 	// Parse do JSON
 	var webhookDTO dtos.WebhookRequestDTO
 	if err := json.Unmarshal(body, &webhookDTO); err != nil {
-		h.logger.Error("Failed to parse webhook JSON", map[string]interface{}{
-			"error": err.Error(),
-			"body":  string(body),
-		})
+		h.logger.WithError(err).Error("Failed to parse webhook JSON")
 		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
 			Error:   "INVALID_JSON",
 			Message: "JSON inválido",
@@ -85,11 +83,10 @@ func (h *WebhookHandler) ReceiveWebhook(c *gin.Context) {
 	// Processar webhook
 	webhook, err := h.webhookUsecase.ProcessWebhook(&webhookDTO, string(body))
 	if err != nil {
-		h.logger.Error("Failed to process webhook", map[string]interface{}{
-			"error":        err.Error(),
+		h.logger.WithError(err).WithFields(logrus.Fields{
 			"event_name":   webhookDTO.Event.Name,
 			"document_key": webhookDTO.Document.Key,
-		})
+		}).Error("Failed to process webhook")
 		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
 			Error:   "PROCESSING_ERROR",
 			Message: "Erro ao processar webhook",
@@ -103,6 +100,7 @@ func (h *WebhookHandler) ReceiveWebhook(c *gin.Context) {
 		EventName:   webhook.EventName,
 		DocumentKey: webhook.DocumentKey,
 		AccountKey:  webhook.AccountKey,
+		TenantID:    webhook.TenantID,
 		Status:      webhook.Status,
 		ProcessedAt: webhook.ProcessedAt,
 		Error:       webhook.Error,
@@ -137,10 +135,7 @@ func (h *WebhookHandler) GetWebhookByID(c *gin.Context) {
 
 	webhook, err := h.webhookUsecase.GetWebhookByID(id)
 	if err != nil {
-		h.logger.Error("Failed to get webhook by ID", map[string]interface{}{
-			"error": err.Error(),
-			"id":    id,
-		})
+		h.logger.WithError(err).WithField("id", id).Error("Failed to get webhook by ID")
 		c.JSON(http.StatusNotFound, dtos.ErrorResponseDTO{
 			Error:   "WEBHOOK_NOT_FOUND",
 			Message: "Webhook não encontrado",
@@ -153,6 +148,7 @@ func (h *WebhookHandler) GetWebhookByID(c *gin.Context) {
 		EventName:   webhook.EventName,
 		DocumentKey: webhook.DocumentKey,
 		AccountKey:  webhook.AccountKey,
+		TenantID:    webhook.TenantID,
 		Status:      webhook.Status,
 		ProcessedAt: webhook.ProcessedAt,
 		Error:       webhook.Error,
@@ -209,9 +205,7 @@ func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
 
 	webhooks, total, err := h.webhookUsecase.GetWebhooksByFilters(filters)
 	if err != nil {
-		h.logger.Error("Failed to get webhooks", map[string]interface{}{
-			"error": err.Error(),
-		})
+		h.logger.WithError(err).Error("Failed to get webhooks")
 		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
 			Error:   "DATABASE_ERROR",
 			Message: "Erro ao buscar webhooks",
@@ -227,6 +221,7 @@ func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
 			EventName:   webhook.EventName,
 			DocumentKey: webhook.DocumentKey,
 			AccountKey:  webhook.AccountKey,
+			TenantID:    webhook.TenantID,
 			Status:      webhook.Status,
 			ProcessedAt: webhook.ProcessedAt,
 			Error:       webhook.Error,
@@ -265,10 +260,7 @@ func (h *WebhookHandler) GetWebhooksByDocumentKey(c *gin.Context) {
 
 	webhooks, err := h.webhookUsecase.GetWebhooksByDocumentKey(documentKey)
 	if err != nil {
-		h.logger.Error("Failed to get webhooks by document key", map[string]interface{}{
-			"error":        err.Error(),
-			"document_key": documentKey,
-		})
+		h.logger.WithError(err).WithField("document_key", documentKey).Error("Failed to get webhooks by document key")
 		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
 			Error:   "DATABASE_ERROR",
 			Message: "Erro ao buscar webhooks",
@@ -284,6 +276,7 @@ func (h *WebhookHandler) GetWebhooksByDocumentKey(c *gin.Context) {
 			EventName:   webhook.EventName,
 			DocumentKey: webhook.DocumentKey,
 			AccountKey:  webhook.AccountKey,
+			TenantID:    webhook.TenantID,
 			Status:      webhook.Status,
 			ProcessedAt: webhook.ProcessedAt,
 			Error:       webhook.Error,
@@ -325,10 +318,7 @@ func (h *WebhookHandler) RetryWebhook(c *gin.Context) {
 
 	err = h.webhookUsecase.RetryWebhook(id)
 	if err != nil {
-		h.logger.Error("Failed to retry webhook", map[string]interface{}{
-			"error": err.Error(),
-			"id":    id,
-		})
+		h.logger.WithError(err).WithField("id", id).Error("Failed to retry webhook")
 		c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
 			Error:   "RETRY_ERROR",
 			Message: err.Error(),
@@ -367,10 +357,7 @@ func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
 
 	err = h.webhookUsecase.DeleteWebhook(id)
 	if err != nil {
-		h.logger.Error("Failed to delete webhook", map[string]interface{}{
-			"error": err.Error(),
-			"id":    id,
-		})
+		h.logger.WithError(err).WithField("id", id).Error("Failed to delete webhook")
 		c.JSON(http.StatusNotFound, dtos.ErrorResponseDTO{
 			Error:   "DELETE_ERROR",
 			Message: "Erro ao deletar webhook",
@@ -396,9 +383,7 @@ func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
 func (h *WebhookHandler) GetPendingWebhooks(c *gin.Context) {
 	webhooks, err := h.webhookUsecase.GetPendingWebhooks()
 	if err != nil {
-		h.logger.Error("Failed to get pending webhooks", map[string]interface{}{
-			"error": err.Error(),
-		})
+		h.logger.WithError(err).Error("Failed to get pending webhooks")
 		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
 			Error:   "DATABASE_ERROR",
 			Message: "Erro ao buscar webhooks pendentes",
@@ -414,6 +399,7 @@ func (h *WebhookHandler) GetPendingWebhooks(c *gin.Context) {
 			EventName:   webhook.EventName,
 			DocumentKey: webhook.DocumentKey,
 			AccountKey:  webhook.AccountKey,
+			TenantID:    webhook.TenantID,
 			Status:      webhook.Status,
 			ProcessedAt: webhook.ProcessedAt,
 			Error:       webhook.Error,
@@ -442,9 +428,7 @@ func (h *WebhookHandler) GetPendingWebhooks(c *gin.Context) {
 func (h *WebhookHandler) GetFailedWebhooks(c *gin.Context) {
 	webhooks, err := h.webhookUsecase.GetFailedWebhooks()
 	if err != nil {
-		h.logger.Error("Failed to get failed webhooks", map[string]interface{}{
-			"error": err.Error(),
-		})
+		h.logger.WithError(err).Error("Failed to get failed webhooks")
 		c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
 			Error:   "DATABASE_ERROR",
 			Message: "Erro ao buscar webhooks que falharam",
@@ -460,6 +444,7 @@ func (h *WebhookHandler) GetFailedWebhooks(c *gin.Context) {
 			EventName:   webhook.EventName,
 			DocumentKey: webhook.DocumentKey,
 			AccountKey:  webhook.AccountKey,
+			TenantID:    webhook.TenantID,
 			Status:      webhook.Status,
 			ProcessedAt: webhook.ProcessedAt,
 			Error:       webhook.Error,
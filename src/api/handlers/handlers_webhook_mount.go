@@ -1,18 +1,80 @@
 package handlers
 
 import (
+	"app/api/handlers/dtos"
+	"app/config"
+	"app/entity"
+	"app/infrastructure/clicksign"
+	"app/infrastructure/dependencies"
 	"app/infrastructure/repository"
 	"app/usecase/document"
 	usecase_envelope "app/usecase/envelope"
 	"app/usecase/webhook"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
+// webhookBodyMaxSize limita o corpo lido antes da verificação da assinatura; os eventos do provider têm poucos KB
+const webhookBodyMaxSize = 1024 * 1024
+
+// webhookAccounts encontra a credencial (e portanto o tenant) dona da conta do provider
+type webhookAccounts interface {
+	GetByAccountKey(accountKey string) (*entity.EntityProviderCredential, error)
+}
+
 // MountWebhookHandlers monta as rotas de webhook
-func MountWebhookHandlers(r *gin.Engine, db *gorm.DB, logger *logrus.Logger) {
+func MountWebhookHandlers(r *gin.Engine, db *gorm.DB, deps *dependencies.Dependencies) {
+	logger := deps.Logger
+	// Webhooks de contas cadastradas são processados no tenant da conta; os da conta global, sem restrição
+	sharedWebhookHandler := newWebhookHandler(db, deps)
+	webhookHandlers := newTenantHandlers(db, func(conn *gorm.DB) *WebhookHandler {
+		return newWebhookHandler(conn, deps)
+	})
+
+	// Grupo de rotas para webhooks
+	webhookGroup := r.Group("/api/v1/webhooks")
+	{
+		// POST /api/v1/webhooks - Receber webhook do Clicksign
+		webhookGroup.POST("/", routeWebhookToTenant(repository.NewRepositoryProviderCredential(db), webhookHandlers, sharedWebhookHandler, config.EnvironmentVariables.CLICKSIGN_WEBHOOK_SECRET, logger))
+	}
+
+	// Consulta e reprocessamento exigem usuário ou API key com webhooks:admin; cada tenant vê apenas os seus webhooks
+	adminGroup := r.Group("/api/v1/webhooks")
+	SetScopedAuthMiddleware(db, deps, adminGroup, entity.ScopeWebhooksAdmin, entity.ScopeWebhooksAdmin)
+	{
+		// GET /api/v1/webhooks - Listar webhooks com filtros
+		adminGroup.GET("/", webhookHandlers.Handle((*WebhookHandler).GetWebhooks))
+
+		// GET /api/v1/webhooks/pending - Listar webhooks pendentes
+		adminGroup.GET("/pending", webhookHandlers.Handle((*WebhookHandler).GetPendingWebhooks))
+
+		// GET /api/v1/webhooks/failed - Listar webhooks que falharam
+		adminGroup.GET("/failed", webhookHandlers.Handle((*WebhookHandler).GetFailedWebhooks))
+
+		// GET /api/v1/webhooks/document/:document_key - Buscar webhooks por document key
+		adminGroup.GET("/document/:document_key", webhookHandlers.Handle((*WebhookHandler).GetWebhooksByDocumentKey))
+
+		// GET /api/v1/webhooks/:id - Buscar webhook por ID
+		adminGroup.GET("/:id", webhookHandlers.Handle((*WebhookHandler).GetWebhookByID))
+
+		// POST /api/v1/webhooks/:id/retry - Reprocessar webhook
+		adminGroup.POST("/:id/retry", webhookHandlers.Handle((*WebhookHandler).RetryWebhook))
+
+		// DELETE /api/v1/webhooks/:id - Deletar webhook
+		adminGroup.DELETE("/:id", webhookHandlers.Handle((*WebhookHandler).DeleteWebhook))
+	}
+}
+
+func newWebhookHandler(db *gorm.DB, deps *dependencies.Dependencies) *WebhookHandler {
+	logger := deps.Logger
+
 	// Criar repositórios
 	webhookRepository := repository.NewRepositoryWebhook(db)
 	envelopeRepository := repository.NewRepositoryEnvelope(db)
@@ -25,38 +87,81 @@ func MountWebhookHandlers(r *gin.Engine, db *gorm.DB, logger *logrus.Logger) {
 		nil,             // clicksignClient - será configurado se necessário
 		documentUsecase, // usecaseDocument
 		nil,             // usecaseRequirement - será configurado se necessário
+		deps.Storage,
+		deps.Fetcher,
 		logger,
 	)
-	webhookUsecase := webhook.NewUsecaseWebhookService(webhookRepository, envelopeUsecase, documentUsecase, logger)
+	webhookUsecase := webhook.NewUsecaseWebhookService(webhookRepository, envelopeUsecase, documentUsecase, deps.Storage, deps.Fetcher, logger)
 
 	// Criar handler
-	webhookHandler := NewWebhookHandler(webhookUsecase, logger)
-
-	// Grupo de rotas para webhooks
-	webhookGroup := r.Group("/api/v1/webhooks")
-	{
-		// POST /api/v1/webhooks - Receber webhook do Clicksign
-		webhookGroup.POST("/", webhookHandler.ReceiveWebhook)
-
-		// GET /api/v1/webhooks - Listar webhooks com filtros
-		webhookGroup.GET("/", webhookHandler.GetWebhooks)
-
-		// GET /api/v1/webhooks/pending - Listar webhooks pendentes
-		webhookGroup.GET("/pending", webhookHandler.GetPendingWebhooks)
+	return NewWebhookHandler(webhookUsecase, logger)
+}
 
-		// GET /api/v1/webhooks/failed - Listar webhooks que falharam
-		webhookGroup.GET("/failed", webhookHandler.GetFailedWebhooks)
+// routeWebhookToTenant autentica o webhook com o segredo da conta (account_key) e só então o entrega aos
+// handlers do tenant dono da conta. Contas sem credencial cadastrada (credenciais globais, compartilhadas
+// pelos tenants) são autenticadas com o segredo global e seguem para shared
+func routeWebhookToTenant(accounts webhookAccounts, tenants *tenantHandlers[WebhookHandler], shared *WebhookHandler, secret string, logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, webhookBodyMaxSize))
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			logger.WithField("limit", maxBytesErr.Limit).Warn("Rejected webhook with oversized body")
+			c.JSON(http.StatusRequestEntityTooLarge, dtos.ErrorResponseDTO{
+				Error:   "PAYLOAD_TOO_LARGE",
+				Message: "Corpo do webhook excede o limite permitido",
+			})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dtos.ErrorResponseDTO{
+				Error:   "BAD_REQUEST",
+				Message: "Falha ao ler o corpo da requisição",
+			})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
 
-		// GET /api/v1/webhooks/document/:document_key - Buscar webhooks por document key
-		webhookGroup.GET("/document/:document_key", webhookHandler.GetWebhooksByDocumentKey)
+		// O account_key ainda não é confiável: serve apenas para escolher o segredo que autentica o webhook.
+		// JSON inválido segue com o segredo global e é rejeitado por ReceiveWebhook
+		var payload struct {
+			Document struct {
+				AccountKey string `json:"account_key"`
+			} `json:"document"`
+		}
+		var credential *entity.EntityProviderCredential
+		if json.Unmarshal(body, &payload) == nil && payload.Document.AccountKey != "" {
+			credential, err = accounts.GetByAccountKey(payload.Document.AccountKey)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				credential = nil
+			} else if err != nil {
+				logger.WithError(err).WithField("account_key", payload.Document.AccountKey).Error("Failed to resolve webhook tenant")
+				c.JSON(http.StatusInternalServerError, dtos.ErrorResponseDTO{
+					Error:   "PROCESSING_ERROR",
+					Message: "Erro ao processar webhook",
+				})
+				return
+			}
+		}
 
-		// GET /api/v1/webhooks/:id - Buscar webhook por ID
-		webhookGroup.GET("/:id", webhookHandler.GetWebhookByID)
+		webhookSecret := secret
+		if credential != nil {
+			webhookSecret = credential.WebhookSecret
+		}
 
-		// POST /api/v1/webhooks/:id/retry - Reprocessar webhook
-		webhookGroup.POST("/:id/retry", webhookHandler.RetryWebhook)
+		// O webhook aciona a finalização do envelope e o download da cópia assinada: só vale se veio do provider
+		if err := clicksign.VerifyWebhookSignature(webhookSecret, body, c.GetHeader(clicksign.WebhookSignatureHeader)); err != nil {
+			logger.WithError(err).WithField("account_key", payload.Document.AccountKey).Warn("Rejected webhook with invalid signature")
+			c.JSON(http.StatusUnauthorized, dtos.ErrorResponseDTO{
+				Error:   "INVALID_SIGNATURE",
+				Message: "Assinatura do webhook inválida",
+			})
+			return
+		}
 
-		// DELETE /api/v1/webhooks/:id - Deletar webhook
-		webhookGroup.DELETE("/:id", webhookHandler.DeleteWebhook)
+		if credential == nil {
+			shared.ReceiveWebhook(c)
+			return
+		}
+		tenants.For(credential.TenantID).ReceiveWebhook(c)
 	}
 }
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"app/api/handlers/dtos"
+	"app/entity"
+	"app/infrastructure/clicksign"
+	"app/usecase/webhook"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// recordingWebhookUsecase registra em qual tenant o webhook foi processado
+type recordingWebhookUsecase struct {
+	webhook.UsecaseWebhookInterface
+	tenantID  string
+	processed int
+}
+
+func (u *recordingWebhookUsecase) ProcessWebhook(webhookDTO *dtos.WebhookRequestDTO, rawPayload string) (*entity.EntityWebhook, error) {
+	u.processed++
+	return &entity.EntityWebhook{ID: 1, EventName: webhookDTO.Event.Name, DocumentKey: webhookDTO.Document.Key, TenantID: u.tenantID}, nil
+}
+
+type fakeWebhookAccounts map[string]*entity.EntityProviderCredential
+
+func (f fakeWebhookAccounts) GetByAccountKey(accountKey string) (*entity.EntityProviderCredential, error) {
+	if accountKey == "broken" {
+		return nil, errors.New("connection refused")
+	}
+	credential, ok := f[accountKey]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return credential, nil
+}
+
+func TestRouteWebhookToTenant(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+
+	acmeUsecase := &recordingWebhookUsecase{tenantID: "acme"}
+	sharedUsecase := &recordingWebhookUsecase{}
+	tenants := &tenantHandlers[WebhookHandler]{byTenant: map[string]*WebhookHandler{
+		"acme": NewWebhookHandler(acmeUsecase, logger),
+	}}
+	accounts := fakeWebhookAccounts{
+		"acc-acme":   {ID: 1, TenantID: "acme", AccountKey: "acc-acme", WebhookSecret: "acme-secret"},
+		"acc-legacy": {ID: 2, TenantID: "acme", AccountKey: "acc-legacy"},
+	}
+
+	router := gin.New()
+	router.POST("/api/v1/webhooks/", routeWebhookToTenant(accounts, tenants, NewWebhookHandler(sharedUsecase, logger), "webhook-secret", logger))
+
+	sendSigned := func(accountKey, secret string) *httptest.ResponseRecorder {
+		body := `{"event":{"name":"sign"},"document":{"key":"doc-1","account_key":"` + accountKey + `"}}`
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/webhooks/", strings.NewReader(body))
+		if secret != "" {
+			req.Header.Set(clicksign.WebhookSignatureHeader, clicksign.SignWebhook(secret, []byte(body)))
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	send := func(accountKey string) *httptest.ResponseRecorder {
+		return sendSigned(accountKey, "webhook-secret")
+	}
+
+	t.Run("should reject unsigned and forged webhooks", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, sendSigned("acc-acme", "").Code)
+		assert.Equal(t, http.StatusUnauthorized, sendSigned("acc-acme", "attacker-secret").Code)
+		assert.Equal(t, 0, acmeUsecase.processed)
+		assert.Equal(t, 0, sharedUsecase.processed)
+	})
+
+	t.Run("should not route webhooks signed with another account secret", func(t *testing.T) {
+		// Quem conhece o segredo global não consegue injetar webhooks no tenant trocando o account_key
+		assert.Equal(t, http.StatusUnauthorized, send("acc-acme").Code)
+		assert.Equal(t, http.StatusUnauthorized, sendSigned("acc-legacy", "webhook-secret").Code)
+		assert.Equal(t, 0, acmeUsecase.processed)
+		assert.Equal(t, 0, sharedUsecase.processed)
+	})
+
+	t.Run("should process webhooks of registered accounts in their tenant", func(t *testing.T) {
+		w := sendSigned("acc-acme", "acme-secret")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response dtos.WebhookResponseDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "acme", response.TenantID)
+		assert.Equal(t, "doc-1", response.DocumentKey)
+		assert.Equal(t, 1, acmeUsecase.processed)
+		assert.Equal(t, 0, sharedUsecase.processed)
+	})
+
+	t.Run("should process unknown accounts with the shared handler", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, send("acc-global").Code)
+		assert.Equal(t, http.StatusOK, send("").Code)
+		assert.Equal(t, 2, sharedUsecase.processed)
+	})
+
+	t.Run("should reject oversized bodies before verifying the signature", func(t *testing.T) {
+		processed := acmeUsecase.processed
+		body := `{"document":{"key":"doc-1","account_key":"acc-acme"},"padding":"` + strings.Repeat("a", webhookBodyMaxSize) + `"}`
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/webhooks/", strings.NewReader(body))
+		req.Header.Set(clicksign.WebhookSignatureHeader, clicksign.SignWebhook("acme-secret", []byte(body)))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Equal(t, processed, acmeUsecase.processed)
+	})
+
+	t.Run("should fail when the account cannot be resolved", func(t *testing.T) {
+		assert.Equal(t, http.StatusInternalServerError, send("broken").Code)
+	})
+}
@@ -1,14 +1,28 @@
 package handlers
 
 import (
+	"app/api/handlers/dtos"
 	"app/api/middleware"
+	"app/config"
+	"app/infrastructure/dependencies"
+	"app/infrastructure/mailer"
+	"app/infrastructure/ratelimit"
 	"app/infrastructure/repository"
+	"app/pkg/fetcher"
+	usecase_api_client "app/usecase/api_client"
+	usecase_document "app/usecase/document"
+	usecase_tenant "app/usecase/tenant"
 	usecase_user "app/usecase/user"
+	"errors"
+	"fmt"
+	"io"
 	"math"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
@@ -52,20 +66,89 @@ func RoutersHandler(c *gin.Context, r *gin.Engine) {
 	}
 }
 
-func SetAuthMiddleware(conn *gorm.DB, group *gin.RouterGroup) {
-	usecaseUser := usecase_user.NewService(
+// newAuthUsecaseUser cria o serviço usado na autenticação: consulta os tokens revogados e aceita tokens do provedor OIDC
+func newAuthUsecaseUser(conn *gorm.DB, deps *dependencies.Dependencies) *usecase_user.UseCaseUser {
+	return usecase_user.NewServiceWithTokens(
 		repository.NewUserPostgres(conn),
+		repository.NewRepositoryToken(conn),
+		deps.TokenKeys,
+		identityVerifier(deps),
 	)
+}
 
-	group.Use(middleware.AuthenticatedMiddleware(usecaseUser))
+// identityVerifier retorna o verificador OIDC ou nil quando o SSO está desativado
+func identityVerifier(deps *dependencies.Dependencies) usecase_user.IIdentityVerifier {
+	if deps.Identity != nil {
+		return deps.Identity
+	}
+	return nil
 }
 
-func SetAdminMiddleware(conn *gorm.DB, group *gin.RouterGroup) {
-	usecaseUser := usecase_user.NewService(
-		repository.NewUserPostgres(conn),
+// newTenantMiddleware resolve o tenant da requisição depois da autenticação; conn não deve estar restrita a um tenant
+func newTenantMiddleware(conn *gorm.DB, logger *logrus.Logger) gin.HandlerFunc {
+	return middleware.TenantMiddleware(usecase_tenant.NewUsecaseTenantService(
+		repository.NewRepositoryTenant(conn),
+		logger,
+	))
+}
+
+// newClientRateLimitMiddleware conta as requisições de cada usuário ou API key no grupo de rotas
+// O grupo da cota é o último segmento do caminho base, ex.: /api/v2/envelopes usa a cota "envelopes"
+func newClientRateLimitMiddleware(limiter *ratelimit.Limiter, group *gin.RouterGroup) gin.HandlerFunc {
+	return middleware.RateLimitByClientMiddleware(limiter, rateLimitGroup(group.BasePath()))
+}
+
+func rateLimitGroup(basePath string) string {
+	segments := strings.Split(strings.Trim(basePath, "/"), "/")
+	return segments[len(segments)-1]
+}
+
+// loginAttempts retorna o bloqueio de login ou nil quando está desativado
+func loginAttempts(deps *dependencies.Dependencies) usecase_user.ILoginAttempts {
+	if deps.LoginAttempts != nil {
+		return deps.LoginAttempts
+	}
+	return nil
+}
+
+// newDocumentIngestion cria o serviço que verifica, converte, normaliza e grava os documentos recebidos
+func newDocumentIngestion(deps *dependencies.Dependencies) *usecase_document.UsecaseDocumentIngestionService {
+	return usecase_document.NewUsecaseDocumentIngestionService(deps.Storage, deps.Converter, deps.Normalizer, deps.Scanner)
+}
+
+// accountMailer retorna nil quando o envio de emails não está configurado, desativando convites e redefinição de senha
+func accountMailer(logger *logrus.Logger) usecase_user.IMailer {
+	smtpMailer, err := mailer.NewMailerFromConfig(config.EnvironmentVariables)
+	if err != nil {
+		if !errors.Is(err, mailer.ErrMailerNotConfigured) {
+			logger.WithError(err).Warn("Email delivery disabled")
+		}
+		return nil
+	}
+	return smtpMailer
+}
+
+func SetAuthMiddleware(conn *gorm.DB, deps *dependencies.Dependencies, group *gin.RouterGroup) {
+	usecaseUser := newAuthUsecaseUser(conn, deps)
+
+	group.Use(middleware.AuthenticatedMiddleware(usecaseUser), newClientRateLimitMiddleware(deps.Limiter, group), newTenantMiddleware(conn, deps.Logger))
+}
+
+// SetScopedAuthMiddleware aceita também API keys, exigindo readScope nas rotas GET e writeScope nas demais
+func SetScopedAuthMiddleware(conn *gorm.DB, deps *dependencies.Dependencies, group *gin.RouterGroup, readScope, writeScope string) {
+	usecaseUser := newAuthUsecaseUser(conn, deps)
+	usecaseAPIClient := usecase_api_client.NewUsecaseAPIClientService(
+		repository.NewRepositoryAPIClient(conn),
+		deps.Logger,
 	)
 
-	group.Use(middleware.AdminMiddleware(usecaseUser))
+	group.Use(middleware.AuthenticatedOrAPIKeyMiddleware(usecaseUser, usecaseAPIClient, readScope, writeScope), newClientRateLimitMiddleware(deps.Limiter, group), newTenantMiddleware(conn, deps.Logger))
+}
+
+func SetAdminMiddleware(conn *gorm.DB, deps *dependencies.Dependencies, group *gin.RouterGroup) {
+	usecaseUser := newAuthUsecaseUser(conn, deps)
+
+	group.Use(middleware.AdminMiddleware(usecaseUser), newClientRateLimitMiddleware(deps.Limiter, group), newTenantMiddleware(conn, deps.Logger))
 }
 
 func getPaginationParams(c *gin.Context) (int, int) {
@@ -110,3 +193,44 @@ func getOrderByParams(c *gin.Context, defaultValue string) (string, string) {
 func getTotalPaginas(totalRegistros int64, tamanhoPagina int) int {
 	return int(math.Ceil(float64(totalRegistros) / float64(tamanhoPagina)))
 }
+
+const (
+	// defaultDocumentUploadMaxSize é usado quando DOCUMENT_UPLOAD_MAX_SIZE_MB não está configurado
+	defaultDocumentUploadMaxSize = 50 * 1024 * 1024
+	// multipartFieldsMaxSize é a folga do corpo multipart para os campos de texto e boundaries
+	multipartFieldsMaxSize = 64 * 1024
+)
+
+var errTemplateRendering = errors.New("failed to generate document from template")
+
+// documentUploadMaxSize retorna o limite de DOCUMENT_UPLOAD_MAX_SIZE_MB em bytes
+func documentUploadMaxSize() int64 {
+	maxSize := int64(config.EnvironmentVariables.DOCUMENT_UPLOAD_MAX_SIZE_MB) * 1024 * 1024
+	if maxSize <= 0 {
+		return defaultDocumentUploadMaxSize
+	}
+	return maxSize
+}
+
+// readMultipartValue lê um campo de texto do formulário multipart
+func readMultipartValue(part io.Reader) (string, error) {
+	value, err := io.ReadAll(io.LimitReader(part, multipartFieldsMaxSize))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(value)), nil
+}
+
+// fetchValidationDetails converte uma falha do fetcher em detalhe de validação do campo file_url
+func fetchValidationDetails(err error) ([]dtos.ValidationErrorDetail, bool) {
+	var fetchErr *fetcher.Error
+	if !errors.As(err, &fetchErr) {
+		return nil, false
+	}
+
+	return []dtos.ValidationErrorDetail{{
+		Field:   "documents.file_url",
+		Message: fmt.Sprintf("%s: %s", fetchErr.Category, fetchErr.Message),
+		Value:   fetchErr.URL,
+	}}, true
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"app/entity"
+	"app/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func performScopedRequest(handler gin.HandlerFunc, method string, headers map[string]string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(handler)
+	router.Handle(method, "/api/v2/envelopes", func(c *gin.Context) {
+		if _, ok := c.Get("api_client"); ok {
+			c.String(http.StatusOK, "api_client")
+			return
+		}
+		c.String(http.StatusOK, "user")
+	})
+
+	req, _ := http.NewRequest(method, "/api/v2/envelopes", nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuthenticatedOrAPIKeyMiddleware(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	usecaseUser := mocks.NewMockIUsecaseUser(ctrl)
+	usecaseAPIClient := mocks.NewMockIUsecaseAPIClient(ctrl)
+	handler := AuthenticatedOrAPIKeyMiddleware(usecaseUser, usecaseAPIClient, entity.ScopeEnvelopesRead, entity.ScopeEnvelopesWrite)
+	readOnly := &entity.EntityAPIClient{ID: 3, Active: true, Scopes: []string{entity.ScopeEnvelopesRead}}
+
+	t.Run("should accept user tokens", func(t *testing.T) {
+		usecaseUser.EXPECT().GetUserByToken("jwt-token").Return(&entity.EntityUser{ID: 1}, nil)
+
+		w := performScopedRequest(handler, http.MethodPost, map[string]string{"Authorization": "Bearer jwt-token"})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "user", w.Body.String())
+	})
+
+	t.Run("should require the permission of the scope from users", func(t *testing.T) {
+		viewer := &entity.EntityUser{ID: 2, Role: entity.RoleViewer}
+		usecaseUser.EXPECT().GetUserByToken("viewer-token").Return(viewer, nil).Times(2)
+
+		w := performScopedRequest(handler, http.MethodGet, map[string]string{"Authorization": "Bearer viewer-token"})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = performScopedRequest(handler, http.MethodPost, map[string]string{"Authorization": "Bearer viewer-token"})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), entity.ScopeEnvelopesWrite)
+	})
+
+	t.Run("should accept api keys with the read scope on GET", func(t *testing.T) {
+		usecaseAPIClient.EXPECT().Authenticate("dsk_abc_secret", gomock.Any()).Return(readOnly, nil)
+
+		w := performScopedRequest(handler, http.MethodGet, map[string]string{"Authorization": "Bearer dsk_abc_secret"})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "api_client", w.Body.String())
+	})
+
+	t.Run("should accept the X-API-Key header", func(t *testing.T) {
+		usecaseAPIClient.EXPECT().Authenticate("dsk_abc_secret", gomock.Any()).Return(readOnly, nil)
+
+		w := performScopedRequest(handler, http.MethodGet, map[string]string{"X-API-Key": "dsk_abc_secret"})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("should forbid api keys without the write scope", func(t *testing.T) {
+		usecaseAPIClient.EXPECT().Authenticate("dsk_abc_secret", gomock.Any()).Return(readOnly, nil)
+
+		w := performScopedRequest(handler, http.MethodPost, map[string]string{"Authorization": "Bearer dsk_abc_secret"})
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), entity.ScopeEnvelopesWrite)
+	})
+
+	t.Run("should reject invalid credentials", func(t *testing.T) {
+		usecaseAPIClient.EXPECT().Authenticate("dsk_abc_wrong", gomock.Any()).Return(nil, entity.ErrInvalidAPIKey)
+		usecaseUser.EXPECT().GetUserByToken("expired").Return(nil, errors.New("token is expired"))
+
+		assert.Equal(t, http.StatusUnauthorized, performScopedRequest(handler, http.MethodGet, map[string]string{"Authorization": "Bearer dsk_abc_wrong"}).Code)
+		assert.Equal(t, http.StatusUnauthorized, performScopedRequest(handler, http.MethodGet, map[string]string{"Authorization": "Bearer expired"}).Code)
+		assert.Equal(t, http.StatusUnauthorized, performScopedRequest(handler, http.MethodGet, nil).Code)
+	})
+
+	t.Run("should forbid api keys on user-only routes", func(t *testing.T) {
+		userOnly := AuthenticatedOrAPIKeyMiddleware(usecaseUser, usecaseAPIClient, entity.ScopeProvidersRead, "")
+		usecaseAPIClient.EXPECT().Authenticate("dsk_abc_secret", gomock.Any()).Return(&entity.EntityAPIClient{Active: true, Scopes: entity.APIScopes}, nil)
+
+		w := performScopedRequest(userOnly, http.MethodPost, map[string]string{"Authorization": "Bearer dsk_abc_secret"})
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"app/entity"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermissionMiddleware exige que o papel ou as permissões do usuário concedam permission
+// API keys não têm papel: precisam do escopo correspondente à permissão (entity.APIKeyScope)
+func RequirePermissionMiddleware(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if value, ok := c.Get("api_client"); ok {
+			client, _ := value.(entity.EntityAPIClient)
+			scope, allowed := entity.APIKeyScope(permission)
+			if !allowed || !client.HasScope(scope) {
+				abortScopeDenied(c, scope)
+				return
+			}
+
+			c.Next()
+			return
+		}
+
+		value, exists := c.Get("user")
+		user, ok := value.(entity.EntityUser)
+		if !exists || !ok || !user.HasPermission(permission) {
+			abortPermissionDenied(c, permission)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// EnvelopeOwnerFromContext retorna o usuário ao qual os envelopes da requisição ficam restritos
+// ok é false para API keys e para usuários com envelopes:read_all, que acessam todos os envelopes do tenant
+func EnvelopeOwnerFromContext(c *gin.Context) (ownerID int, ok bool) {
+	if _, isClient := c.Get("api_client"); isClient {
+		return 0, false
+	}
+
+	value, exists := c.Get("user")
+	user, isUser := value.(entity.EntityUser)
+	if !exists || !isUser || user.HasPermission(entity.PermissionEnvelopesReadAll) {
+		return 0, false
+	}
+	return user.ID, true
+}
+
+// UserFromContext retorna o usuário autenticado; ok é false quando a requisição usa API key
+func UserFromContext(c *gin.Context) (user entity.EntityUser, ok bool) {
+	value, exists := c.Get("user")
+	user, ok = value.(entity.EntityUser)
+	return user, exists && ok
+}
+
+// UserIDFromContext retorna o id do usuário autenticado, ou 0 quando a requisição usa API key
+func UserIDFromContext(c *gin.Context) int {
+	user, _ := UserFromContext(c)
+	return user.ID
+}
+
+func abortScopeDenied(c *gin.Context, scope string) {
+	c.JSON(http.StatusForbidden, gin.H{
+		"message":        "Forbidden",
+		"required_scope": scope,
+	})
+	c.Abort()
+}
+
+func abortPermissionDenied(c *gin.Context, permission string) {
+	c.JSON(http.StatusForbidden, gin.H{
+		"message":             "Forbidden",
+		"required_permission": permission,
+	})
+	c.Abort()
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"app/entity"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequirePermissionMiddleware(t *testing.T) {
+	handler := RequirePermissionMiddleware(entity.PermissionEnvelopesActivate)
+
+	assert.Equal(t, http.StatusOK, performTenantRequest(withUser(entity.EntityUser{ID: 1, Role: entity.RoleApprover}), handler, nil).Code)
+	assert.Equal(t, http.StatusOK, performTenantRequest(withUser(entity.EntityUser{ID: 1, IsAdmin: true}), handler, nil).Code)
+	assert.Equal(t, http.StatusOK, performTenantRequest(withUser(entity.EntityUser{ID: 1, Role: entity.RoleOperator, Permissions: []string{entity.PermissionEnvelopesActivate}}), handler, nil).Code)
+	assert.Equal(t, http.StatusOK, performTenantRequest(withAPIClient(entity.EntityAPIClient{ID: 3, Scopes: []string{entity.ScopeEnvelopesWrite, entity.ScopeEnvelopesActivate}}), handler, nil).Code)
+
+	w := performTenantRequest(withUser(entity.EntityUser{ID: 1, Role: entity.RoleOperator}), handler, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), entity.PermissionEnvelopesActivate)
+
+	assert.Equal(t, http.StatusForbidden, performTenantRequest(func(c *gin.Context) {}, handler, nil).Code)
+
+	t.Run("should require the matching scope from API keys", func(t *testing.T) {
+		w := performTenantRequest(withAPIClient(entity.EntityAPIClient{ID: 3, Scopes: []string{entity.ScopeEnvelopesWrite}}), handler, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), `"required_scope":"envelopes:activate"`)
+
+		documentsWrite := RequirePermissionMiddleware(entity.ScopeDocumentsWrite)
+		assert.Equal(t, http.StatusOK, performTenantRequest(withAPIClient(entity.EntityAPIClient{ID: 3, Scopes: []string{entity.ScopeDocumentsWrite}}), documentsWrite, nil).Code)
+		assert.Equal(t, http.StatusForbidden, performTenantRequest(withAPIClient(entity.EntityAPIClient{ID: 3, Scopes: []string{entity.ScopeDocumentsRead}}), documentsWrite, nil).Code)
+	})
+
+	t.Run("should deny API keys permissions without scope", func(t *testing.T) {
+		client := entity.EntityAPIClient{ID: 3, Scopes: entity.APIScopes}
+		assert.Equal(t, http.StatusForbidden, performTenantRequest(withAPIClient(client), RequirePermissionMiddleware(entity.PermissionUsersAdmin), nil).Code)
+		assert.Equal(t, http.StatusForbidden, performTenantRequest(withAPIClient(client), RequirePermissionMiddleware(entity.PermissionEnvelopesApprove), nil).Code)
+	})
+}
+
+func TestEnvelopeOwnerFromContext(t *testing.T) {
+	owner := func(principal func(c *gin.Context)) string {
+		w := performTenantRequest(principal, func(c *gin.Context) {
+			ownerID, ok := EnvelopeOwnerFromContext(c)
+			c.String(http.StatusOK, fmt.Sprintf("%d %t", ownerID, ok))
+			c.Abort()
+		}, nil)
+		return w.Body.String()
+	}
+
+	assert.Equal(t, "7 true", owner(withUser(entity.EntityUser{ID: 7, Role: entity.RoleOperator})))
+	assert.Equal(t, "0 false", owner(withUser(entity.EntityUser{ID: 7, Role: entity.RoleOperator, Permissions: []string{entity.PermissionEnvelopesReadAll}})))
+	assert.Equal(t, "0 false", owner(withUser(entity.EntityUser{ID: 7, Role: entity.RoleApprover})))
+	assert.Equal(t, "0 false", owner(withUser(entity.EntityUser{ID: 7})))
+	assert.Equal(t, "0 false", owner(withAPIClient(entity.EntityAPIClient{ID: 3})))
+}
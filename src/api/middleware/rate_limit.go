@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"app/entity"
+	"app/infrastructure/ratelimit"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitByIPMiddleware aplica a cota do grupo a cada IP; limiter nil não limita
+func RateLimitByIPMiddleware(limiter *ratelimit.Limiter, group string) gin.HandlerFunc {
+	return rateLimitMiddleware(limiter, group, func(c *gin.Context) (string, bool) {
+		return c.ClientIP(), true
+	})
+}
+
+// RateLimitByClientMiddleware aplica a cota do grupo a cada usuário ou API key; vai depois da autenticação
+func RateLimitByClientMiddleware(limiter *ratelimit.Limiter, group string) gin.HandlerFunc {
+	return rateLimitMiddleware(limiter, group, clientKey)
+}
+
+func rateLimitMiddleware(limiter *ratelimit.Limiter, group string, key func(c *gin.Context) (string, bool)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limiter == nil {
+			c.Next()
+			return
+		}
+
+		clientKey, ok := key(c)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		result, err := limiter.Allow(c.Request.Context(), group, clientKey)
+		if err != nil {
+			// Sem o store de contadores a API continua atendendo; o rate limit não derruba as rotas
+			_ = c.Error(err)
+			c.Next()
+			return
+		}
+
+		reset := secondsUntil(result.ResetAt)
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(reset))
+
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(reset))
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"message": "Too many requests",
+				"group":   group,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func clientKey(c *gin.Context) (string, bool) {
+	if value, ok := c.Get("api_client"); ok {
+		if client, ok := value.(entity.EntityAPIClient); ok {
+			return "client:" + strconv.Itoa(client.ID), true
+		}
+	}
+	if user, ok := UserFromContext(c); ok {
+		return "user:" + strconv.Itoa(user.ID), true
+	}
+	return "", false
+}
+
+func secondsUntil(t time.Time) int {
+	return max(int(math.Ceil(time.Until(t).Seconds())), 0)
+}
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"app/entity"
+	"app/infrastructure/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func performRateLimitedRequest(principal func(c *gin.Context), handler gin.HandlerFunc) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(principal, handler)
+	router.GET("/api/v2/envelopes", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v2/envelopes", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimitByClientMiddleware(t *testing.T) {
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Quota{Limit: 10, Window: time.Minute}, ratelimit.Quota{Limit: 1, Window: time.Minute}, nil)
+	handler := RateLimitByClientMiddleware(limiter, "envelopes")
+
+	t.Run("should send the quota headers", func(t *testing.T) {
+		w := performRateLimitedRequest(withUser(entity.EntityUser{ID: 1}), handler)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "1", w.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+		assert.NotEmpty(t, w.Header().Get("RateLimit-Reset"))
+	})
+
+	t.Run("should refuse requests over the quota", func(t *testing.T) {
+		w := performRateLimitedRequest(withUser(entity.EntityUser{ID: 1}), handler)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	})
+
+	t.Run("should count api keys and users separately", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, performRateLimitedRequest(withAPIClient(entity.EntityAPIClient{ID: 1}), handler).Code)
+		assert.Equal(t, http.StatusOK, performRateLimitedRequest(withUser(entity.EntityUser{ID: 2}), handler).Code)
+	})
+}
+
+func TestRateLimitByIPMiddleware(t *testing.T) {
+	anonymous := func(c *gin.Context) {}
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Quota{Limit: 1, Window: time.Minute}, ratelimit.Quota{Limit: 10, Window: time.Minute}, nil)
+
+	assert.Equal(t, http.StatusOK, performRateLimitedRequest(anonymous, RateLimitByIPMiddleware(limiter, ratelimit.GroupIP)).Code)
+	assert.Equal(t, http.StatusTooManyRequests, performRateLimitedRequest(anonymous, RateLimitByIPMiddleware(limiter, ratelimit.GroupIP)).Code)
+
+	w := performRateLimitedRequest(anonymous, RateLimitByIPMiddleware(nil, ratelimit.GroupIP))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+}
+
+func TestRateLimitByIPMiddlewareBehindProxies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	anonymous := func(c *gin.Context) {}
+
+	perform := func(trustedProxies []string, limiter *ratelimit.Limiter, forwardedFor string) int {
+		router := gin.New()
+		if err := router.SetTrustedProxies(trustedProxies); err != nil {
+			t.Fatal(err)
+		}
+		router.Use(anonymous, RateLimitByIPMiddleware(limiter, ratelimit.GroupIP))
+		router.GET("/api/v2/envelopes", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/v2/envelopes", nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	t.Run("should ignore a spoofed X-Forwarded-For without trusted proxies", func(t *testing.T) {
+		limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Quota{Limit: 1, Window: time.Minute}, ratelimit.Quota{Limit: 10, Window: time.Minute}, nil)
+
+		assert.Equal(t, http.StatusOK, perform(nil, limiter, "198.51.100.1"))
+		assert.Equal(t, http.StatusTooManyRequests, perform(nil, limiter, "198.51.100.2"))
+	})
+
+	t.Run("should use X-Forwarded-For from trusted proxies", func(t *testing.T) {
+		limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Quota{Limit: 1, Window: time.Minute}, ratelimit.Quota{Limit: 10, Window: time.Minute}, nil)
+		trusted := []string{"203.0.113.0/24"}
+
+		assert.Equal(t, http.StatusOK, perform(trusted, limiter, "198.51.100.1"))
+		assert.Equal(t, http.StatusOK, perform(trusted, limiter, "198.51.100.2"))
+		assert.Equal(t, http.StatusTooManyRequests, perform(trusted, limiter, "198.51.100.1"))
+	})
+}
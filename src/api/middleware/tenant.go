@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"app/entity"
+	usecase_tenant "app/usecase/tenant"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TenantHeader permite que administradores da plataforma atuem em um tenant específico
+const TenantHeader = "X-Tenant-ID"
+
+const tenantKey = "tenant_id"
+
+// ResolveTenant retorna o tenant do usuário ou da API key autenticados
+// Usuários e clientes vinculados a um tenant só podem informar o próprio tenant no header, assim como usuários e
+// clientes sem tenant que não são da plataforma (acessam só o tenant padrão); administradores da plataforma e
+// API keys com platform:admin escolhem o tenant pelo header, ou usam o tenant padrão ("")
+func ResolveTenant(c *gin.Context) (string, error) {
+	requested := strings.TrimSpace(c.GetHeader(TenantHeader))
+
+	var tenantID string
+	if value, ok := c.Get("api_client"); ok {
+		client, _ := value.(entity.EntityAPIClient)
+		tenantID = client.TenantID
+	} else if value, ok := c.Get("user"); ok {
+		user, _ := value.(entity.EntityUser)
+		tenantID = user.TenantID
+	}
+
+	if requested == "" || requested == tenantID {
+		return tenantID, nil
+	}
+	if IsPlatformAdmin(c) {
+		return requested, nil
+	}
+	return "", entity.ErrTenantForbidden
+}
+
+// IsPlatformAdmin informa se a requisição é de um administrador sem tenant ou de uma API key com platform:admin
+func IsPlatformAdmin(c *gin.Context) bool {
+	if value, ok := c.Get("api_client"); ok {
+		client, _ := value.(entity.EntityAPIClient)
+		return client.IsPlatformAdmin()
+	}
+	if value, ok := c.Get("user"); ok {
+		user, _ := value.(entity.EntityUser)
+		return user.IsAdmin && user.TenantID == ""
+	}
+	return false
+}
+
+// TenantMiddleware define o tenant da requisição; deve ser usado depois da autenticação
+// Tenants desativados têm o acesso bloqueado
+func TenantMiddleware(usecaseTenant usecase_tenant.IUsecaseTenant) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID, err := ResolveTenant(c)
+		if err == nil && tenantID != "" {
+			err = usecaseTenant.RequireActive(tenantID)
+		}
+
+		if err != nil {
+			status := http.StatusForbidden
+			if !errors.Is(err, entity.ErrTenantForbidden) && !errors.Is(err, entity.ErrTenantNotFound) && !errors.Is(err, entity.ErrTenantInactive) {
+				status = http.StatusInternalServerError
+			}
+			c.JSON(status, gin.H{
+				"message": err.Error(),
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set(tenantKey, tenantID)
+		c.Next()
+	}
+}
+
+// TenantFromContext retorna o tenant definido por TenantMiddleware
+func TenantFromContext(c *gin.Context) string {
+	return c.GetString(tenantKey)
+}
+
+// RequirePlatformAdminMiddleware restringe a rota a administradores sem tenant e a API keys com platform:admin,
+// que gerenciam recursos compartilhados por todos os tenants
+func RequirePlatformAdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !IsPlatformAdmin(c) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"message": "Forbidden",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
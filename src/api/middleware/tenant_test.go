@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"app/entity"
+	"app/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func performTenantRequest(principal func(c *gin.Context), handler gin.HandlerFunc, headers map[string]string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(principal, handler)
+	router.GET("/api/v2/envelopes", func(c *gin.Context) {
+		c.String(http.StatusOK, TenantFromContext(c))
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v2/envelopes", nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func withUser(user entity.EntityUser) func(c *gin.Context) {
+	return func(c *gin.Context) { c.Set("user", user) }
+}
+
+func withAPIClient(client entity.EntityAPIClient) func(c *gin.Context) {
+	return func(c *gin.Context) { c.Set("api_client", client) }
+}
+
+func TestTenantMiddleware(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	usecaseTenant := mocks.NewMockIUsecaseTenant(ctrl)
+	handler := TenantMiddleware(usecaseTenant)
+
+	t.Run("should use the tenant of the user", func(t *testing.T) {
+		usecaseTenant.EXPECT().RequireActive("acme").Return(nil)
+
+		w := performTenantRequest(withUser(entity.EntityUser{ID: 1, TenantID: "acme"}), handler, nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "acme", w.Body.String())
+	})
+
+	t.Run("should use the tenant of the api key", func(t *testing.T) {
+		usecaseTenant.EXPECT().RequireActive("acme").Return(nil)
+
+		w := performTenantRequest(withAPIClient(entity.EntityAPIClient{ID: 3, TenantID: "acme"}), handler, map[string]string{TenantHeader: "acme"})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "acme", w.Body.String())
+	})
+
+	t.Run("should forbid tenant users from choosing another tenant", func(t *testing.T) {
+		w := performTenantRequest(withUser(entity.EntityUser{ID: 1, TenantID: "acme", IsAdmin: true}), handler, map[string]string{TenantHeader: "globex"})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		w = performTenantRequest(withUser(entity.EntityUser{ID: 2}), handler, map[string]string{TenantHeader: "globex"})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("should let platform admins choose the tenant", func(t *testing.T) {
+		usecaseTenant.EXPECT().RequireActive("globex").Return(nil)
+
+		w := performTenantRequest(withUser(entity.EntityUser{ID: 1, IsAdmin: true}), handler, map[string]string{TenantHeader: "globex"})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "globex", w.Body.String())
+
+		w = performTenantRequest(withUser(entity.EntityUser{ID: 1, IsAdmin: true}), handler, nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "", w.Body.String())
+	})
+
+	t.Run("should keep tenantless api keys in the default tenant", func(t *testing.T) {
+		w := performTenantRequest(withAPIClient(entity.EntityAPIClient{ID: 3, Scopes: []string{entity.ScopeEnvelopesRead}}), handler, map[string]string{TenantHeader: "globex"})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		w = performTenantRequest(withAPIClient(entity.EntityAPIClient{ID: 3, Scopes: []string{entity.ScopeEnvelopesRead}}), handler, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "", w.Body.String())
+	})
+
+	t.Run("should let platform api keys choose the tenant", func(t *testing.T) {
+		usecaseTenant.EXPECT().RequireActive("globex").Return(nil)
+
+		w := performTenantRequest(withAPIClient(entity.EntityAPIClient{ID: 3, Scopes: []string{entity.ScopePlatformAdmin}}), handler, map[string]string{TenantHeader: "globex"})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "globex", w.Body.String())
+	})
+
+	t.Run("should block inactive tenants", func(t *testing.T) {
+		usecaseTenant.EXPECT().RequireActive("acme").Return(entity.ErrTenantInactive)
+
+		w := performTenantRequest(withUser(entity.EntityUser{ID: 1, TenantID: "acme"}), handler, nil)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestRequirePlatformAdminMiddleware(t *testing.T) {
+	handler := RequirePlatformAdminMiddleware()
+
+	assert.Equal(t, http.StatusOK, performTenantRequest(withUser(entity.EntityUser{IsAdmin: true}), handler, nil).Code)
+	assert.Equal(t, http.StatusOK, performTenantRequest(withAPIClient(entity.EntityAPIClient{ID: 3, Scopes: []string{entity.ScopePlatformAdmin}}), handler, nil).Code)
+	assert.Equal(t, http.StatusForbidden, performTenantRequest(withAPIClient(entity.EntityAPIClient{ID: 3, Scopes: []string{entity.ScopeRetentionAdmin}}), handler, nil).Code)
+	assert.Equal(t, http.StatusForbidden, performTenantRequest(withAPIClient(entity.EntityAPIClient{ID: 3, TenantID: "acme", Scopes: []string{entity.ScopePlatformAdmin}}), handler, nil).Code)
+	assert.Equal(t, http.StatusForbidden, performTenantRequest(withUser(entity.EntityUser{IsAdmin: true, TenantID: "acme"}), handler, nil).Code)
+	assert.Equal(t, http.StatusForbidden, performTenantRequest(withUser(entity.EntityUser{}), handler, nil).Code)
+	assert.Equal(t, http.StatusForbidden, performTenantRequest(withAPIClient(entity.EntityAPIClient{ID: 3, TenantID: "acme"}), handler, nil).Code)
+}
@@ -36,6 +36,7 @@ func ReadEnvironmentVars() {
 	EnvironmentVariables.EMAIL_HOST_USER = os.Getenv("EMAIL_HOST_USER")
 	EnvironmentVariables.EMAIL_HOST_PASSWORD = os.Getenv("EMAIL_HOST_PASSWORD")
 	EnvironmentVariables.EMAIL_PORT, _ = strconv.Atoi(os.Getenv("EMAIL_PORT"))
+	EnvironmentVariables.EMAIL_USE_TLS = strings.EqualFold(os.Getenv("EMAIL_USE_TLS"), "true")
 
 	EnvironmentVariables.EMAIL_FROM = os.Getenv("EMAIL_FROM")
 
@@ -47,12 +48,142 @@ func ReadEnvironmentVars() {
 	EnvironmentVariables.CLICKSIGN_BASE_URL = getEnvOrDefault("CLICKSIGN_BASE_URL", "https://api.clicksign.com")
 	EnvironmentVariables.CLICKSIGN_TIMEOUT, _ = strconv.Atoi(getEnvOrDefault("CLICKSIGN_TIMEOUT", "30"))
 	EnvironmentVariables.CLICKSIGN_RETRY_ATTEMPTS, _ = strconv.Atoi(getEnvOrDefault("CLICKSIGN_RETRY_ATTEMPTS", "3"))
+	EnvironmentVariables.CLICKSIGN_WEBHOOK_SECRET = os.Getenv("CLICKSIGN_WEBHOOK_SECRET")
+	EnvironmentVariables.CLICKSIGN_SIGNED_FILE_HOSTS = getEnvOrDefault("CLICKSIGN_SIGNED_FILE_HOSTS", "*.clicksign.com")
 
 	// Vertc-Assinaturas configuration
 	EnvironmentVariables.VERTC_ASSINATURAS_BASE_URL = getEnvOrDefault("VERTC_ASSINATURAS_BASE_URL", "https://api-assinaturas-stg.vert-tech.dev")
 	EnvironmentVariables.VERTC_ASSINATURAS_EMAIL = os.Getenv("VERTC_ASSINATURAS_EMAIL")
 	EnvironmentVariables.VERTC_ASSINATURAS_PASSWORD = os.Getenv("VERTC_ASSINATURAS_PASSWORD")
 	EnvironmentVariables.VERTC_ASSINATURAS_TIMEOUT, _ = strconv.Atoi(getEnvOrDefault("VERTC_ASSINATURAS_TIMEOUT", "30"))
+
+	// Ordem de preferência dos providers quando o envelope é criado com provider "auto"
+	EnvironmentVariables.PROVIDER_FAILOVER_ORDER = getEnvOrDefault("PROVIDER_FAILOVER_ORDER", "clicksign,vert-sign")
+
+	// Provider fake em memória para desenvolvimento e testes de integração (nunca habilitar em produção)
+	EnvironmentVariables.FAKE_PROVIDER_ENABLED = os.Getenv("FAKE_PROVIDER_ENABLED") == "true"
+	EnvironmentVariables.FAKE_PROVIDER_WEBHOOK_URL = getEnvOrDefault("FAKE_PROVIDER_WEBHOOK_URL", "http://localhost:8080/api/v1/webhooks/")
+
+	// Storage dos documentos originais e assinados: "local" (disco) ou "s3" (AWS S3, MinIO)
+	EnvironmentVariables.STORAGE_BACKEND = getEnvOrDefault("STORAGE_BACKEND", "local")
+	EnvironmentVariables.STORAGE_LOCAL_PATH = getEnvOrDefault("STORAGE_LOCAL_PATH", "./data/documents")
+	EnvironmentVariables.STORAGE_S3_ENDPOINT = os.Getenv("STORAGE_S3_ENDPOINT")
+	EnvironmentVariables.STORAGE_S3_REGION = getEnvOrDefault("STORAGE_S3_REGION", "us-east-1")
+	EnvironmentVariables.STORAGE_S3_BUCKET = os.Getenv("STORAGE_S3_BUCKET")
+	EnvironmentVariables.STORAGE_S3_ACCESS_KEY_ID = os.Getenv("STORAGE_S3_ACCESS_KEY_ID")
+	EnvironmentVariables.STORAGE_S3_SECRET_ACCESS_KEY = os.Getenv("STORAGE_S3_SECRET_ACCESS_KEY")
+	EnvironmentVariables.STORAGE_S3_USE_PATH_STYLE = os.Getenv("STORAGE_S3_USE_PATH_STYLE") != "false"
+
+	// Tamanho máximo dos uploads multipart de documentos
+	EnvironmentVariables.DOCUMENT_UPLOAD_MAX_SIZE_MB, _ = strconv.Atoi(getEnvOrDefault("DOCUMENT_UPLOAD_MAX_SIZE_MB", "50"))
+
+	// Download de documentos por URL (file_url): endereços internos são sempre bloqueados,
+	// exceto as redes listadas em FETCH_ALLOWED_NETWORKS
+	EnvironmentVariables.FETCH_ALLOWED_SCHEMES = getEnvOrDefault("FETCH_ALLOWED_SCHEMES", "https,http")
+	EnvironmentVariables.FETCH_ALLOWED_HOSTS = os.Getenv("FETCH_ALLOWED_HOSTS")
+	EnvironmentVariables.FETCH_ALLOWED_NETWORKS = os.Getenv("FETCH_ALLOWED_NETWORKS")
+	EnvironmentVariables.FETCH_MAX_SIZE_MB, _ = strconv.Atoi(getEnvOrDefault("FETCH_MAX_SIZE_MB", "50"))
+	EnvironmentVariables.FETCH_TIMEOUT, _ = strconv.Atoi(getEnvOrDefault("FETCH_TIMEOUT", "30"))
+	EnvironmentVariables.FETCH_MAX_REDIRECTS, _ = strconv.Atoi(getEnvOrDefault("FETCH_MAX_REDIRECTS", "5"))
+
+	// Conversão de DOCX, ODT e HTML para PDF: "libreoffice" (soffice headless) ou "disabled"
+	EnvironmentVariables.DOCUMENT_CONVERTER = getEnvOrDefault("DOCUMENT_CONVERTER", "libreoffice")
+	EnvironmentVariables.LIBREOFFICE_PATH = getEnvOrDefault("LIBREOFFICE_PATH", "soffice")
+	// O soffice roda sem rede e sem acesso ao sistema de arquivos do host: "bwrap" (bubblewrap) ou "none"
+	EnvironmentVariables.LIBREOFFICE_SANDBOX = getEnvOrDefault("LIBREOFFICE_SANDBOX", "bwrap")
+	EnvironmentVariables.DOCUMENT_CONVERSION_TIMEOUT, _ = strconv.Atoi(getEnvOrDefault("DOCUMENT_CONVERSION_TIMEOUT", "120"))
+
+	// Normalização na ingestão: "basic" (valida PDFs sem ferramentas externas) ou "qpdf" (também junta e reescreve PDFs)
+	EnvironmentVariables.DOCUMENT_NORMALIZER = getEnvOrDefault("DOCUMENT_NORMALIZER", "basic")
+	EnvironmentVariables.QPDF_PATH = getEnvOrDefault("QPDF_PATH", "qpdf")
+	EnvironmentVariables.DOCUMENT_NORMALIZATION_TIMEOUT, _ = strconv.Atoi(getEnvOrDefault("DOCUMENT_NORMALIZATION_TIMEOUT", "60"))
+	EnvironmentVariables.DOCUMENT_STRIP_METADATA = os.Getenv("DOCUMENT_STRIP_METADATA") == "true"
+	EnvironmentVariables.DOCUMENT_FLATTEN_FORMS = os.Getenv("DOCUMENT_FLATTEN_FORMS") == "true"
+	EnvironmentVariables.DOCUMENT_IMAGES_TO_PDF = os.Getenv("DOCUMENT_IMAGES_TO_PDF") == "true"
+	// Imagens maiores são recusadas antes de decodificar (proteção contra decompression bombs)
+	EnvironmentVariables.DOCUMENT_IMAGE_MAX_WIDTH, _ = strconv.Atoi(getEnvOrDefault("DOCUMENT_IMAGE_MAX_WIDTH", "10000"))
+	EnvironmentVariables.DOCUMENT_IMAGE_MAX_HEIGHT, _ = strconv.Atoi(getEnvOrDefault("DOCUMENT_IMAGE_MAX_HEIGHT", "10000"))
+	EnvironmentVariables.DOCUMENT_IMAGE_MAX_PIXELS, _ = strconv.Atoi(getEnvOrDefault("DOCUMENT_IMAGE_MAX_PIXELS", "25000000"))
+
+	// Verificação de malware na ingestão: "disabled" ou "clamav" (clamd via TCP)
+	EnvironmentVariables.DOCUMENT_SCANNER = getEnvOrDefault("DOCUMENT_SCANNER", "disabled")
+	EnvironmentVariables.CLAMAV_ADDRESS = getEnvOrDefault("CLAMAV_ADDRESS", "localhost:3310")
+	EnvironmentVariables.DOCUMENT_SCAN_TIMEOUT, _ = strconv.Atoi(getEnvOrDefault("DOCUMENT_SCAN_TIMEOUT", "60"))
+
+	// Expurgo agendado conforme a política de retenção; prazos em dias, 0 mantém para sempre
+	EnvironmentVariables.RETENTION_ENABLED = os.Getenv("RETENTION_ENABLED") == "true"
+	EnvironmentVariables.RETENTION_SCHEDULE = getEnvOrDefault("RETENTION_SCHEDULE", "0 3 * * *")
+	EnvironmentVariables.RETENTION_DRY_RUN = getEnvOrDefault("RETENTION_DRY_RUN", "true") == "true"
+	EnvironmentVariables.RETENTION_ENVELOPE_RAW_DATA_DAYS, _ = strconv.Atoi(getEnvOrDefault("RETENTION_ENVELOPE_RAW_DATA_DAYS", "90"))
+	EnvironmentVariables.RETENTION_WEBHOOK_PAYLOAD_DAYS, _ = strconv.Atoi(getEnvOrDefault("RETENTION_WEBHOOK_PAYLOAD_DAYS", "90"))
+	EnvironmentVariables.RETENTION_SIGNER_PII_DAYS, _ = strconv.Atoi(getEnvOrDefault("RETENTION_SIGNER_PII_DAYS", "1825"))
+	EnvironmentVariables.RETENTION_DOCUMENT_FILE_DAYS, _ = strconv.Atoi(getEnvOrDefault("RETENTION_DOCUMENT_FILE_DAYS", "0"))
+
+	// Por quanto tempo as chaves anteriores continuam válidas após uma rotação
+	EnvironmentVariables.API_KEY_ROTATION_OVERLAP_HOURS, _ = strconv.Atoi(getEnvOrDefault("API_KEY_ROTATION_OVERLAP_HOURS", "24"))
+
+	// Tokens de usuário: HS256 com JWT_SECRET ou RS256 com JWT_PRIVATE_KEY_PATH; JWT_VERIFICATION_KEYS mantém chaves anteriores na rotação
+	EnvironmentVariables.JWT_ALGORITHM = getEnvOrDefault("JWT_ALGORITHM", "HS256")
+	EnvironmentVariables.JWT_KEY_ID = getEnvOrDefault("JWT_KEY_ID", "default")
+	EnvironmentVariables.JWT_SECRET = os.Getenv("JWT_SECRET")
+	EnvironmentVariables.JWT_PRIVATE_KEY_PATH = os.Getenv("JWT_PRIVATE_KEY_PATH")
+	EnvironmentVariables.JWT_VERIFICATION_KEYS = os.Getenv("JWT_VERIFICATION_KEYS")
+	EnvironmentVariables.JWT_ISSUER = os.Getenv("JWT_ISSUER")
+	EnvironmentVariables.JWT_ACCESS_TOKEN_TTL_MINUTES, _ = strconv.Atoi(getEnvOrDefault("JWT_ACCESS_TOKEN_TTL_MINUTES", "1440"))
+	EnvironmentVariables.JWT_REFRESH_TOKEN_TTL_HOURS, _ = strconv.Atoi(getEnvOrDefault("JWT_REFRESH_TOKEN_TTL_HOURS", "720"))
+
+	// SSO: tokens do provedor OIDC aceitos diretamente como bearer, com provisionamento dos usuários no primeiro acesso
+	EnvironmentVariables.OIDC_ENABLED = os.Getenv("OIDC_ENABLED") == "true"
+	EnvironmentVariables.OIDC_ISSUER = os.Getenv("OIDC_ISSUER")
+	EnvironmentVariables.OIDC_AUDIENCE = os.Getenv("OIDC_AUDIENCE")
+	EnvironmentVariables.OIDC_JWKS_URL = os.Getenv("OIDC_JWKS_URL")
+	EnvironmentVariables.OIDC_JWKS_CACHE_MINUTES, _ = strconv.Atoi(getEnvOrDefault("OIDC_JWKS_CACHE_MINUTES", "60"))
+	EnvironmentVariables.OIDC_EMAIL_CLAIM = getEnvOrDefault("OIDC_EMAIL_CLAIM", "email")
+	EnvironmentVariables.OIDC_NAME_CLAIM = getEnvOrDefault("OIDC_NAME_CLAIM", "name")
+	EnvironmentVariables.OIDC_GROUPS_CLAIM = getEnvOrDefault("OIDC_GROUPS_CLAIM", "groups")
+	EnvironmentVariables.OIDC_ADMIN_GROUPS = os.Getenv("OIDC_ADMIN_GROUPS")
+	EnvironmentVariables.OIDC_ALLOWED_GROUPS = os.Getenv("OIDC_ALLOWED_GROUPS")
+	EnvironmentVariables.OIDC_AUTO_PROVISION = getEnvOrDefault("OIDC_AUTO_PROVISION", "true") == "true"
+	EnvironmentVariables.OIDC_ALLOW_LOCAL_LOGIN = getEnvOrDefault("OIDC_ALLOW_LOCAL_LOGIN", "true") == "true"
+	EnvironmentVariables.OIDC_LINK_BY_EMAIL = os.Getenv("OIDC_LINK_BY_EMAIL") == "true"
+
+	EnvironmentVariables.ENVELOPE_APPROVAL_REQUIRED = os.Getenv("ENVELOPE_APPROVAL_REQUIRED") == "true"
+
+	// Origens aceitas pelo CORS; vazio aceita qualquer origem, sem credenciais
+	EnvironmentVariables.CORS_ALLOWED_ORIGINS = os.Getenv("CORS_ALLOWED_ORIGINS")
+
+	// Proxies reversos cujo X-Forwarded-For é aceito como IP do cliente; vazio usa o IP da conexão
+	EnvironmentVariables.TRUSTED_PROXIES = os.Getenv("TRUSTED_PROXIES")
+
+	// Rate limiting por IP e por cliente (usuário ou API key), com cotas por grupo de rotas, e bloqueio do login
+	EnvironmentVariables.RATE_LIMIT_ENABLED = getEnvOrDefault("RATE_LIMIT_ENABLED", "true") == "true"
+	EnvironmentVariables.RATE_LIMIT_STORE = getEnvOrDefault("RATE_LIMIT_STORE", "memory")
+	EnvironmentVariables.RATE_LIMIT_IP = getEnvOrDefault("RATE_LIMIT_IP", "600/m")
+	EnvironmentVariables.RATE_LIMIT_CLIENT = getEnvOrDefault("RATE_LIMIT_CLIENT", "300/m")
+	EnvironmentVariables.RATE_LIMIT_QUOTAS = getEnvOrDefault("RATE_LIMIT_QUOTAS", "login=10/m")
+	EnvironmentVariables.LOGIN_LOCKOUT_MAX_ATTEMPTS, _ = strconv.Atoi(getEnvOrDefault("LOGIN_LOCKOUT_MAX_ATTEMPTS", "5"))
+	EnvironmentVariables.LOGIN_LOCKOUT_MINUTES, _ = strconv.Atoi(getEnvOrDefault("LOGIN_LOCKOUT_MINUTES", "15"))
+
+	// Convites e redefinição de senha por email (EMAIL_*) e política de senhas
+	EnvironmentVariables.APP_URL = strings.TrimRight(os.Getenv("APP_URL"), "/")
+	EnvironmentVariables.USER_INVITATION_TTL_HOURS, _ = strconv.Atoi(getEnvOrDefault("USER_INVITATION_TTL_HOURS", "72"))
+	EnvironmentVariables.PASSWORD_RESET_TTL_MINUTES, _ = strconv.Atoi(getEnvOrDefault("PASSWORD_RESET_TTL_MINUTES", "60"))
+	EnvironmentVariables.PASSWORD_MIN_LENGTH, _ = strconv.Atoi(getEnvOrDefault("PASSWORD_MIN_LENGTH", "10"))
+	EnvironmentVariables.PASSWORD_REQUIRE_MIXED = getEnvOrDefault("PASSWORD_REQUIRE_MIXED", "true") == "true"
+
+	// Cifragem dos dados pessoais dos signatários (CPF/CNPJ, nascimento e telefone)
+	EnvironmentVariables.PII_ENCRYPTION_KEYS = os.Getenv("PII_ENCRYPTION_KEYS")
+	// Sem chaves a aplicação não inicia; PII_ENCRYPTION_DISABLED=true aceita gravar os dados pessoais em texto puro
+	EnvironmentVariables.PII_ENCRYPTION_DISABLED = os.Getenv("PII_ENCRYPTION_DISABLED") == "true"
+	EnvironmentVariables.PII_ENCRYPTION_ACTIVE_KEY = os.Getenv("PII_ENCRYPTION_ACTIVE_KEY")
+	EnvironmentVariables.PII_BLIND_INDEX_KEY = os.Getenv("PII_BLIND_INDEX_KEY")
+	EnvironmentVariables.PII_REENCRYPT_SCHEDULE = getEnvOrDefault("PII_REENCRYPT_SCHEDULE", "0 3 * * *")
+
+	// Redação de dados pessoais nos logs; campos e padrões vazios usam a lista padrão de pkg/logger
+	EnvironmentVariables.LOG_PII_REDACTION = getEnvOrDefault("LOG_PII_REDACTION", "mask")
+	EnvironmentVariables.LOG_PII_FIELDS = os.Getenv("LOG_PII_FIELDS")
+	EnvironmentVariables.LOG_PII_PATTERNS = os.Getenv("LOG_PII_PATTERNS")
+	EnvironmentVariables.LOG_PII_HASH_KEY = os.Getenv("LOG_PII_HASH_KEY")
 }
 
 func getEnvOrDefault(key, defaultValue string) string {
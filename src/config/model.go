@@ -19,21 +19,129 @@ type EnvironmentVars struct {
 	EMAIL_HOST_USER     string
 	EMAIL_HOST_PASSWORD string
 	EMAIL_PORT          int
+	EMAIL_USE_TLS       bool
 
 	EMAIL_FROM string
 
 	DEFAULT_ADMIN_MAIL     string
 	DEFAULT_ADMIN_PASSWORD string
 
-	CLICKSIGN_API_KEY        string
-	CLICKSIGN_BASE_URL       string
-	CLICKSIGN_TIMEOUT        int
-	CLICKSIGN_RETRY_ATTEMPTS int
+	CLICKSIGN_API_KEY           string
+	CLICKSIGN_BASE_URL          string
+	CLICKSIGN_TIMEOUT           int
+	CLICKSIGN_RETRY_ATTEMPTS    int
+	CLICKSIGN_WEBHOOK_SECRET    string
+	CLICKSIGN_SIGNED_FILE_HOSTS string
 
 	VERTC_ASSINATURAS_BASE_URL string
 	VERTC_ASSINATURAS_EMAIL    string
 	VERTC_ASSINATURAS_PASSWORD string
 	VERTC_ASSINATURAS_TIMEOUT  int
 
+	PROVIDER_FAILOVER_ORDER string
+
+	FAKE_PROVIDER_ENABLED     bool
+	FAKE_PROVIDER_WEBHOOK_URL string
+
+	STORAGE_BACKEND              string
+	STORAGE_LOCAL_PATH           string
+	STORAGE_S3_ENDPOINT          string
+	STORAGE_S3_REGION            string
+	STORAGE_S3_BUCKET            string
+	STORAGE_S3_ACCESS_KEY_ID     string
+	STORAGE_S3_SECRET_ACCESS_KEY string
+	STORAGE_S3_USE_PATH_STYLE    bool
+
+	DOCUMENT_UPLOAD_MAX_SIZE_MB int
+
+	FETCH_ALLOWED_SCHEMES  string
+	FETCH_ALLOWED_HOSTS    string
+	FETCH_ALLOWED_NETWORKS string
+	FETCH_MAX_SIZE_MB      int
+	FETCH_TIMEOUT          int
+	FETCH_MAX_REDIRECTS    int
+
+	DOCUMENT_CONVERTER          string
+	LIBREOFFICE_PATH            string
+	LIBREOFFICE_SANDBOX         string
+	DOCUMENT_CONVERSION_TIMEOUT int
+
+	DOCUMENT_NORMALIZER            string
+	QPDF_PATH                      string
+	DOCUMENT_NORMALIZATION_TIMEOUT int
+	DOCUMENT_STRIP_METADATA        bool
+	DOCUMENT_FLATTEN_FORMS         bool
+	DOCUMENT_IMAGES_TO_PDF         bool
+	DOCUMENT_IMAGE_MAX_WIDTH       int
+	DOCUMENT_IMAGE_MAX_HEIGHT      int
+	DOCUMENT_IMAGE_MAX_PIXELS      int
+
+	DOCUMENT_SCANNER      string
+	CLAMAV_ADDRESS        string
+	DOCUMENT_SCAN_TIMEOUT int
+
+	RETENTION_ENABLED                bool
+	RETENTION_SCHEDULE               string
+	RETENTION_DRY_RUN                bool
+	RETENTION_ENVELOPE_RAW_DATA_DAYS int
+	RETENTION_WEBHOOK_PAYLOAD_DAYS   int
+	RETENTION_SIGNER_PII_DAYS        int
+	RETENTION_DOCUMENT_FILE_DAYS     int
+
+	API_KEY_ROTATION_OVERLAP_HOURS int
+
+	JWT_ALGORITHM                string
+	JWT_KEY_ID                   string
+	JWT_SECRET                   string
+	JWT_PRIVATE_KEY_PATH         string
+	JWT_VERIFICATION_KEYS        string
+	JWT_ISSUER                   string
+	JWT_ACCESS_TOKEN_TTL_MINUTES int
+	JWT_REFRESH_TOKEN_TTL_HOURS  int
+
+	OIDC_ENABLED            bool
+	OIDC_ISSUER             string
+	OIDC_AUDIENCE           string
+	OIDC_JWKS_URL           string
+	OIDC_JWKS_CACHE_MINUTES int
+	OIDC_EMAIL_CLAIM        string
+	OIDC_NAME_CLAIM         string
+	OIDC_GROUPS_CLAIM       string
+	OIDC_ADMIN_GROUPS       string
+	OIDC_ALLOWED_GROUPS     string
+	OIDC_AUTO_PROVISION     bool
+	OIDC_ALLOW_LOCAL_LOGIN  bool
+	OIDC_LINK_BY_EMAIL      bool
+
+	ENVELOPE_APPROVAL_REQUIRED bool
+
+	CORS_ALLOWED_ORIGINS string
+	TRUSTED_PROXIES      string
+
+	RATE_LIMIT_ENABLED         bool
+	RATE_LIMIT_STORE           string
+	RATE_LIMIT_IP              string
+	RATE_LIMIT_CLIENT          string
+	RATE_LIMIT_QUOTAS          string
+	LOGIN_LOCKOUT_MAX_ATTEMPTS int
+	LOGIN_LOCKOUT_MINUTES      int
+
+	APP_URL                    string
+	USER_INVITATION_TTL_HOURS  int
+	PASSWORD_RESET_TTL_MINUTES int
+	PASSWORD_MIN_LENGTH        int
+	PASSWORD_REQUIRE_MIXED     bool
+
+	PII_ENCRYPTION_KEYS       string
+	PII_ENCRYPTION_DISABLED   bool
+	PII_ENCRYPTION_ACTIVE_KEY string
+	PII_BLIND_INDEX_KEY       string
+	PII_REENCRYPT_SCHEDULE    string
+
+	LOG_PII_REDACTION string
+	LOG_PII_FIELDS    string
+	LOG_PII_PATTERNS  string
+	LOG_PII_HASH_KEY  string
+
 	ISRELEASE bool
 }
@@ -1,13 +1,135 @@
 package cron
 
 import (
+	"app/config"
+	"app/entity"
+	"app/infrastructure/dependencies"
+	"app/infrastructure/postgres"
+	"app/infrastructure/ratelimit"
+	"app/infrastructure/repository"
+	usecase_retention "app/usecase/retention"
+	usecase_user "app/usecase/user"
+	"context"
+	"errors"
 	"time"
 
 	"github.com/go-co-op/gocron"
+	"github.com/sirupsen/logrus"
 )
 
-func StartCronJobs() {
+// StartCronJobs agenda as rotinas com a infraestrutura criada na inicialização
+func StartCronJobs(deps *dependencies.Dependencies) {
 	s := gocron.NewScheduler(time.UTC)
 
+	if config.EnvironmentVariables.RETENTION_ENABLED {
+		scheduleRetention(s, deps)
+	}
+	scheduleTokenCleanup(s, deps)
+	if config.EnvironmentVariables.RATE_LIMIT_STORE == ratelimit.StorePostgres {
+		scheduleRateLimitCleanup(s, deps)
+	}
+	schedulePIIReencryption(s, deps)
+
 	s.StartAsync()
 }
+
+// scheduleRetention agenda o expurgo; SingletonMode evita sobrepor execuções longas
+func scheduleRetention(s *gocron.Scheduler, deps *dependencies.Dependencies) {
+	logger := deps.Logger
+
+	_, err := s.Cron(config.EnvironmentVariables.RETENTION_SCHEDULE).SingletonMode().Do(func() {
+		usecase := usecase_retention.NewUsecaseRetentionService(
+			repository.NewRepositoryRetention(postgres.Connect()),
+			deps.Storage,
+			usecase_retention.PolicyFromConfig(config.EnvironmentVariables),
+			logger,
+		)
+
+		_, err := usecase.Run(context.Background(), entity.RetentionTriggerCron, config.EnvironmentVariables.RETENTION_DRY_RUN)
+		if err != nil && !errors.Is(err, usecase_retention.ErrRetentionRunning) {
+			logger.WithError(err).Error("Retention run failed")
+		}
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to schedule retention")
+	}
+}
+
+// scheduleTokenCleanup remove diariamente refresh tokens e revogações de access tokens já expirados
+func scheduleTokenCleanup(s *gocron.Scheduler, deps *dependencies.Dependencies) {
+	logger := deps.Logger
+
+	_, err := s.Cron("30 4 * * *").SingletonMode().Do(func() {
+		conn := postgres.Connect()
+		usecase := usecase_user.NewServiceWithTokens(
+			repository.NewUserPostgres(conn),
+			repository.NewRepositoryToken(conn),
+			deps.TokenKeys,
+			nil,
+		)
+
+		deleted, err := usecase.DeleteExpiredTokens()
+		if err != nil {
+			logger.WithError(err).Error("Failed to delete expired tokens")
+			return
+		}
+		logger.WithField("deleted", deleted).Info("Expired tokens deleted")
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to schedule token cleanup")
+	}
+}
+
+// scheduleRateLimitCleanup remove de hora em hora as janelas de rate limit e de bloqueio de login já encerradas
+func scheduleRateLimitCleanup(s *gocron.Scheduler, deps *dependencies.Dependencies) {
+	logger := deps.Logger
+
+	_, err := s.Cron("15 * * * *").SingletonMode().Do(func() {
+		deleted, err := ratelimit.NewPostgresStore(postgres.Connect()).DeleteExpired(time.Now())
+		if err != nil {
+			logger.WithError(err).Error("Failed to delete expired rate limit counters")
+			return
+		}
+		logger.WithField("deleted", deleted).Debug("Expired rate limit counters deleted")
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to schedule rate limit cleanup")
+	}
+}
+
+// schedulePIIReencryption recifra com a chave ativa os dados pessoais gravados por outra chave ou em texto puro
+func schedulePIIReencryption(s *gocron.Scheduler, deps *dependencies.Dependencies) {
+	logger := deps.Logger
+
+	_, err := s.Cron(config.EnvironmentVariables.PII_REENCRYPT_SCHEDULE).SingletonMode().Do(func() {
+		currentPrefix := ""
+		if cipher := entity.CurrentPIICipher(); cipher != nil {
+			currentPrefix = cipher.CurrentPrefix()
+		}
+		repo := repository.NewRepositoryPII(postgres.Connect())
+
+		signatories, err := repo.ReencryptSignatories(currentPrefix, 500)
+		if err != nil {
+			logger.WithError(err).Error("Failed to re-encrypt signatories")
+			return
+		}
+		terms, err := repo.ReencryptAutoSignatureTerms(currentPrefix, 500)
+		if err != nil {
+			logger.WithError(err).Error("Failed to re-encrypt auto signature terms")
+			return
+		}
+		credentials, err := repo.ReencryptProviderCredentials(currentPrefix, 500)
+		if err != nil {
+			logger.WithError(err).Error("Failed to re-encrypt provider credentials")
+			return
+		}
+		logger.WithFields(logrus.Fields{
+			"signatories":          signatories,
+			"auto_signature_terms": terms,
+			"provider_credentials": credentials,
+		}).Info("PII re-encryption finished")
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to schedule PII re-encryption")
+	}
+}
@@ -0,0 +1,254 @@
+package entity
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Escopos concedidos às API keys; cada grupo de rotas exige o escopo de leitura (GET) ou de escrita
+const (
+	ScopeDocumentsRead  = "documents:read"
+	ScopeDocumentsWrite = "documents:write"
+	ScopeEnvelopesRead  = "envelopes:read"
+	ScopeEnvelopesWrite = "envelopes:write"
+	// ScopeEnvelopesActivate é exigido além de envelopes:write para ativar envelopes
+	ScopeEnvelopesActivate = "envelopes:activate"
+	ScopeTemplatesRead     = "templates:read"
+	ScopeTemplatesWrite    = "templates:write"
+	ScopeTermsRead         = "terms:read"
+	ScopeTermsWrite        = "terms:write"
+	ScopeProvidersRead     = "providers:read"
+	ScopeWebhooksAdmin     = "webhooks:admin"
+	ScopeRetentionAdmin    = "retention:admin"
+	// ScopePlatformAdmin permite a um cliente sem tenant escolher o tenant (X-Tenant-ID) e gerenciar recursos da plataforma
+	ScopePlatformAdmin = "platform:admin"
+)
+
+// APIScopes lista os escopos aceitos na criação de clientes
+var APIScopes = []string{
+	ScopeDocumentsRead,
+	ScopeDocumentsWrite,
+	ScopeEnvelopesRead,
+	ScopeEnvelopesWrite,
+	ScopeEnvelopesActivate,
+	ScopeTemplatesRead,
+	ScopeTemplatesWrite,
+	ScopeTermsRead,
+	ScopeTermsWrite,
+	ScopeProvidersRead,
+	ScopeWebhooksAdmin,
+	ScopeRetentionAdmin,
+	ScopePlatformAdmin,
+}
+
+// APIKeyPrefix identifica as API keys no header Authorization, diferenciando-as dos JWTs de usuário
+const APIKeyPrefix = "dsk_"
+
+var (
+	ErrInvalidAPIKey     = errors.New("invalid api key")
+	ErrUnknownScope      = errors.New("unknown scope")
+	ErrAPIKeyRevoked     = errors.New("api key revoked")
+	ErrAPIKeyExpired     = errors.New("api key expired")
+	ErrAPIClientInactive = errors.New("api client inactive")
+)
+
+// EntityAPIClient é um sistema que acessa a API com API keys em vez de usuário e senha
+type EntityAPIClient struct {
+	ID          int            `json:"id" gorm:"primaryKey"`
+	Name        string         `json:"name" gorm:"not null;uniqueIndex" validate:"required,min=3,max=120"`
+	Description string         `json:"description"`
+	Scopes      []string       `json:"scopes" gorm:"serializer:json" validate:"required,min=1"`
+	Active      bool           `json:"active" gorm:"default:true"`
+	CreatedByID int            `json:"created_by_id"`
+	TenantID    string         `json:"tenant_id,omitempty" gorm:"not null;default:'';index"` // Tenant dos envelopes e documentos acessados pelo cliente
+	Keys        []EntityAPIKey `json:"keys,omitempty" gorm:"foreignKey:ClientID"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// TableName sets the table name for GORM
+func (EntityAPIClient) TableName() string {
+	return "api_clients"
+}
+
+// EntityAPIKey guarda apenas o hash da chave; o valor completo é exibido uma única vez, na emissão
+type EntityAPIKey struct {
+	ID         int        `json:"id" gorm:"primaryKey"`
+	ClientID   int        `json:"client_id" gorm:"not null;index"`
+	Prefix     string     `json:"prefix" gorm:"not null;uniqueIndex"` // Parte pública da chave, usada na busca
+	KeyHash    string     `json:"-" gorm:"not null"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"` // Definido na rotação para encerrar o período de convivência
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	LastUsedIP string     `json:"last_used_ip,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// TableName sets the table name for GORM
+func (EntityAPIKey) TableName() string {
+	return "api_keys"
+}
+
+func NewAPIClient(clientParam EntityAPIClient) (*EntityAPIClient, error) {
+	now := time.Now()
+
+	client := &EntityAPIClient{
+		Name:        strings.TrimSpace(clientParam.Name),
+		Description: strings.TrimSpace(clientParam.Description),
+		Scopes:      normalizeScopes(clientParam.Scopes),
+		Active:      true,
+		CreatedByID: clientParam.CreatedByID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := client.Validate(); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+func (c *EntityAPIClient) Validate() error {
+	if err := validate.Struct(c); err != nil {
+		return err
+	}
+	for _, scope := range c.Scopes {
+		if !isKnownScope(scope) {
+			return fmt.Errorf("%w: %s", ErrUnknownScope, scope)
+		}
+	}
+	return nil
+}
+
+// SetScopes substitui os escopos do cliente
+func (c *EntityAPIClient) SetScopes(scopes []string) error {
+	c.Scopes = normalizeScopes(scopes)
+	return c.Validate()
+}
+
+// HasScope informa se o cliente recebeu o escopo
+func (c *EntityAPIClient) HasScope(scope string) bool {
+	for _, granted := range c.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPlatformAdmin informa se o cliente atua na plataforma: sem tenant e com o escopo platform:admin
+// Clientes sem tenant e sem o escopo acessam apenas o tenant padrão
+func (c *EntityAPIClient) IsPlatformAdmin() bool {
+	return c.TenantID == "" && c.HasScope(ScopePlatformAdmin)
+}
+
+// NewAPIKey emite uma chave para o cliente e retorna também o valor completo, que não é persistido
+// Formato: dsk_<prefixo>_<segredo>, com prefixo e segredo aleatórios em hexadecimal
+func NewAPIKey(clientID int) (*EntityAPIKey, string, error) {
+	prefix, err := randomHex(6)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rawKey := APIKeyPrefix + prefix + "_" + secret
+	return &EntityAPIKey{
+		ClientID:  clientID,
+		Prefix:    prefix,
+		KeyHash:   hashSecret(rawKey),
+		CreatedAt: time.Now(),
+	}, rawKey, nil
+}
+
+// IsAPIKey informa se o token tem o formato de API key
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+// ParseAPIKeyPrefix extrai o prefixo público da chave
+func ParseAPIKeyPrefix(rawKey string) (string, error) {
+	if !IsAPIKey(rawKey) {
+		return "", ErrInvalidAPIKey
+	}
+	prefix, secret, found := strings.Cut(strings.TrimPrefix(rawKey, APIKeyPrefix), "_")
+	if !found || prefix == "" || secret == "" {
+		return "", ErrInvalidAPIKey
+	}
+	return prefix, nil
+}
+
+// Matches compara a chave informada com o hash em tempo constante
+func (k *EntityAPIKey) Matches(rawKey string) bool {
+	return subtle.ConstantTimeCompare([]byte(hashSecret(rawKey)), []byte(k.KeyHash)) == 1
+}
+
+// CheckUsable retorna o motivo pelo qual a chave não pode mais ser usada
+func (k *EntityAPIKey) CheckUsable(now time.Time) error {
+	if k.RevokedAt != nil {
+		return ErrAPIKeyRevoked
+	}
+	if k.ExpiresAt != nil && !now.Before(*k.ExpiresAt) {
+		return ErrAPIKeyExpired
+	}
+	return nil
+}
+
+// ExpireAt antecipa a expiração da chave; nunca a prorroga
+func (k *EntityAPIKey) ExpireAt(at time.Time) {
+	if k.ExpiresAt == nil || at.Before(*k.ExpiresAt) {
+		k.ExpiresAt = &at
+	}
+}
+
+// Revoke invalida a chave imediatamente
+func (k *EntityAPIKey) Revoke() {
+	if k.RevokedAt == nil {
+		now := time.Now()
+		k.RevokedAt = &now
+	}
+}
+
+// hashSecret usa SHA-256: API keys e refresh tokens têm 256 bits aleatórios, então não precisam de um hash lento como bcrypt
+func hashSecret(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate random secret: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func normalizeScopes(scopes []string) []string {
+	seen := make(map[string]bool, len(scopes))
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if scope != "" && !seen[scope] {
+			seen[scope] = true
+			normalized = append(normalized, scope)
+		}
+	}
+	return normalized
+}
+
+func isKnownScope(scope string) bool {
+	for _, known := range APIScopes {
+		if known == scope {
+			return true
+		}
+	}
+	return false
+}
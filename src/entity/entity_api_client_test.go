@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAPIClient(t *testing.T) {
+	client, err := NewAPIClient(EntityAPIClient{
+		Name:   " erp-financeiro ",
+		Scopes: []string{"Envelopes:Write", "envelopes:read", "envelopes:write"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "erp-financeiro", client.Name)
+	assert.Equal(t, []string{ScopeEnvelopesWrite, ScopeEnvelopesRead}, client.Scopes)
+	assert.True(t, client.Active)
+	assert.True(t, client.HasScope(ScopeEnvelopesRead))
+	assert.False(t, client.HasScope(ScopeWebhooksAdmin))
+
+	_, err = NewAPIClient(EntityAPIClient{Name: "erp-financeiro", Scopes: []string{"envelopes:delete"}})
+	assert.ErrorIs(t, err, ErrUnknownScope)
+
+	_, err = NewAPIClient(EntityAPIClient{Name: "erp-financeiro"})
+	assert.Error(t, err)
+}
+
+func TestNewAPIKey(t *testing.T) {
+	key, rawKey, err := NewAPIKey(3)
+	require.NoError(t, err)
+
+	assert.True(t, IsAPIKey(rawKey))
+	assert.NotContains(t, key.KeyHash, rawKey)
+	assert.True(t, key.Matches(rawKey))
+	assert.False(t, key.Matches(rawKey+"0"))
+
+	prefix, err := ParseAPIKeyPrefix(rawKey)
+	require.NoError(t, err)
+	assert.Equal(t, key.Prefix, prefix)
+
+	for _, invalid := range []string{"eyJhbGciOi", "dsk_", "dsk_abc", "dsk__secret"} {
+		_, err := ParseAPIKeyPrefix(invalid)
+		assert.ErrorIs(t, err, ErrInvalidAPIKey, invalid)
+	}
+}
+
+func TestEntityAPIKey_CheckUsable(t *testing.T) {
+	now := time.Now()
+	key := &EntityAPIKey{}
+	assert.NoError(t, key.CheckUsable(now))
+
+	key.ExpireAt(now.Add(time.Hour))
+	assert.NoError(t, key.CheckUsable(now))
+	assert.ErrorIs(t, key.CheckUsable(now.Add(time.Hour)), ErrAPIKeyExpired)
+
+	// Uma nova rotação nunca prorroga a expiração já definida
+	key.ExpireAt(now.Add(48 * time.Hour))
+	assert.Equal(t, now.Add(time.Hour), *key.ExpiresAt)
+
+	key.Revoke()
+	assert.ErrorIs(t, key.CheckUsable(now), ErrAPIKeyRevoked)
+}
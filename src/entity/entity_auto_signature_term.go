@@ -5,21 +5,26 @@ import (
 	"fmt"
 	"net/mail"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // EntityAutoSignatureTerm representa um termo de assinatura automática
 type EntityAutoSignatureTerm struct {
-	ID                  int       `json:"id" gorm:"primaryKey"`
-	SignerDocumentation string    `json:"signer_documentation" gorm:"column:signer_documentation;not null" validate:"required"`
-	SignerBirthday      string    `json:"signer_birthday" gorm:"column:signer_birthday;not null" validate:"required"`
-	SignerEmail         string    `json:"signer_email" gorm:"column:signer_email;not null" validate:"required,email"`
-	SignerName          string    `json:"signer_name" gorm:"column:signer_name;not null" validate:"required,min=2,max=255"`
-	AdminEmail          string    `json:"admin_email" gorm:"not null" validate:"required,email"`
-	APIEmail            string    `json:"api_email" gorm:"not null" validate:"required,email"`
-	ClicksignKey        string    `json:"clicksign_key" gorm:"index"`
-	ClicksignRawData    *string   `json:"clicksign_raw_data" gorm:"type:text"`
-	CreatedAt           time.Time `json:"created_at"`
-	UpdatedAt           time.Time `json:"updated_at"`
+	ID                       int        `json:"id" gorm:"primaryKey"`
+	SignerDocumentation      string     `json:"signer_documentation" gorm:"column:signer_documentation;not null;serializer:encrypted" validate:"required"`
+	SignerDocumentationIndex string     `json:"-" gorm:"size:64;index"` // Blind index do documento cifrado, usado nas buscas por CPF/CNPJ
+	SignerBirthday           string     `json:"signer_birthday" gorm:"column:signer_birthday;not null" validate:"required"`
+	SignerEmail              string     `json:"signer_email" gorm:"column:signer_email;not null" validate:"required,email"`
+	SignerName               string     `json:"signer_name" gorm:"column:signer_name;not null" validate:"required,min=2,max=255"`
+	AdminEmail               string     `json:"admin_email" gorm:"not null" validate:"required,email"`
+	APIEmail                 string     `json:"api_email" gorm:"not null" validate:"required,email"`
+	ClicksignKey             string     `json:"clicksign_key" gorm:"index"`
+	ClicksignRawData         *string    `json:"clicksign_raw_data" gorm:"type:text"`
+	AnonymizedAt             *time.Time `json:"anonymized_at,omitempty"` // CPF e nascimento removidos pela política de retenção
+	TenantID                 string     `json:"tenant_id,omitempty" gorm:"not null;default:'';index"`
+	CreatedAt                time.Time  `json:"created_at"`
+	UpdatedAt                time.Time  `json:"updated_at"`
 }
 
 // SignerInfo representa as informações do signatário (para DTOs)
@@ -35,6 +40,30 @@ func (EntityAutoSignatureTerm) TableName() string {
 	return "auto_signature_terms"
 }
 
+// BeforeSave atualiza o blind index do documento antes de cifrá-lo
+func (t *EntityAutoSignatureTerm) BeforeSave(tx *gorm.DB) error {
+	t.IndexPII()
+	return nil
+}
+
+// AfterCreate amarra o documento cifrado ao id gerado na inserção
+func (t *EntityAutoSignatureTerm) AfterCreate(tx *gorm.DB) error {
+	return bindEncryptedFields(tx, t)
+}
+
+// IndexPII recalcula o blind index a partir do documento em claro
+func (t *EntityAutoSignatureTerm) IndexPII() {
+	t.SignerDocumentationIndex = DocumentationIndex(t.SignerDocumentation)
+}
+
+// Masked retorna uma cópia com o documento do signatário mascarado, para listagens e logs
+// O payload bruto do provedor repete o documento e por isso é omitido
+func (t EntityAutoSignatureTerm) Masked() EntityAutoSignatureTerm {
+	t.SignerDocumentation = MaskDocumentation(t.SignerDocumentation)
+	t.ClicksignRawData = nil
+	return t
+}
+
 func NewAutoSignatureTerm(termParam EntityAutoSignatureTerm) (*EntityAutoSignatureTerm, error) {
 	now := time.Now()
 
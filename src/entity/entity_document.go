@@ -1,6 +1,8 @@
 package entity
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -16,17 +18,89 @@ type EntityDocumentFilters struct {
 	ClicksignKey string `json:"clicksign_key"`
 }
 
+const (
+	// IntegrityVerified indica que o hash informado pelo provider confere com o original
+	IntegrityVerified = "verified"
+	// IntegrityMismatch indica que o provider recebeu um conteúdo diferente do original armazenado
+	IntegrityMismatch = "mismatch"
+)
+
+// DocumentStatusQuarantined marca documentos em que a verificação de malware encontrou uma ameaça
+// Documentos em quarentena não mudam de status nem podem ser enviados a um provider
+const DocumentStatusQuarantined = "quarantined"
+
+// ErrDocumentQuarantined é retornado ao tentar enviar a um provider um documento em quarentena
+var ErrDocumentQuarantined = errors.New("document is quarantined")
+
+// DocumentScan registra a verificação de malware do conteúdo enviado
+// Documentos montados a partir de partes guardam também o resultado de cada parte
+type DocumentScan struct {
+	Scanner   string             `json:"scanner"`
+	Infected  bool               `json:"infected"`
+	Threat    string             `json:"threat,omitempty"`
+	ScannedAt time.Time          `json:"scanned_at"`
+	Parts     []DocumentScanPart `json:"parts,omitempty"`
+}
+
+// DocumentScanPart é o resultado da verificação de uma parte, numerada a partir de 1 na ordem enviada
+type DocumentScanPart struct {
+	Index    int    `json:"index"`
+	Infected bool   `json:"infected"`
+	Threat   string `json:"threat,omitempty"`
+}
+
+// DocumentConversion registra a conversão para PDF de um documento enviado em outro formato
+type DocumentConversion struct {
+	SourceMimeType   string    `json:"source_mime_type"`
+	SourceStorageKey string    `json:"source_storage_key"`
+	SourceSHA256     string    `json:"source_sha256"`
+	SourceSize       int64     `json:"source_size"`
+	Converter        string    `json:"converter"`
+	ConvertedAt      time.Time `json:"converted_at"`
+}
+
+// DocumentNormalization registra a normalização do documento: arquivos de origem e etapas aplicadas
+type DocumentNormalization struct {
+	Sources      []DocumentSource `json:"sources"`
+	Steps        []string         `json:"steps"`
+	Normalizer   string           `json:"normalizer"`
+	NormalizedAt time.Time        `json:"normalized_at"`
+}
+
+// DocumentSource é um arquivo de origem guardado no storage
+type DocumentSource struct {
+	MimeType   string `json:"mime_type"`
+	StorageKey string `json:"storage_key"`
+	SHA256     string `json:"sha256"`
+	Size       int64  `json:"size"`
+}
+
 type EntityDocument struct {
 	ID           int                    `json:"id" gorm:"primaryKey"`
 	Name         string                 `json:"name" gorm:"not null" validate:"required,min=3,max=255"`
 	FilePath     string                 `json:"file_path" gorm:"not null" validate:"required"`
 	FileSize     int64                  `json:"file_size" gorm:"not null" validate:"required,gt=0"`
 	MimeType     string                 `json:"mime_type" gorm:"not null" validate:"required"`
-	Status       string                 `json:"status" gorm:"not null;default:'draft'" validate:"required,oneof=draft ready processing sent"`
+	Status       string                 `json:"status" gorm:"not null;default:'draft'" validate:"required,oneof=draft ready processing sent quarantined"`
 	ClicksignKey string                 `json:"clicksign_key" gorm:"index"`
 	Description  string        `json:"description" validate:"max=1000"`
 	IsFromBase64 bool          `json:"is_from_base64" gorm:"default:false"`
 	Metadata     datatypes.JSON `json:"metadata" gorm:"type:jsonb"` // Metadata customizado do backend
+	// Chaves endereçadas por conteúdo no storage de documentos (originals/sha256/<hash>, signed/sha256/<hash>)
+	StorageKey       string `json:"storage_key,omitempty" gorm:"index"`
+	SignedStorageKey string `json:"signed_storage_key,omitempty"`
+	// Impressão digital do original (calculada na ingestão) e da cópia assinada (calculada no download)
+	SHA256          string `json:"sha256,omitempty" gorm:"index"`
+	SignedSHA256    string `json:"signed_sha256,omitempty" gorm:"index"`
+	SignedFileSize  int64  `json:"signed_file_size,omitempty"`
+	ProviderSHA256  string `json:"provider_sha256,omitempty"`
+	IntegrityStatus string `json:"integrity_status,omitempty"`
+	// Versão atual do conteúdo (as anteriores ficam em document_versions) e a versão da cópia assinada
+	Version       int `json:"version" gorm:"not null;default:1"`
+	SignedVersion int `json:"signed_version,omitempty"`
+	// Quando os arquivos foram apagados do storage pela política de retenção; os hashes continuam valendo para verificação
+	FilesPurgedAt *time.Time `json:"files_purged_at,omitempty"`
+	TenantID      string     `json:"tenant_id,omitempty" gorm:"not null;default:'';index"`
 	CreatedAt    time.Time     `json:"created_at"`
 	UpdatedAt    time.Time     `json:"updated_at"`
 }
@@ -53,6 +127,8 @@ func NewDocument(docParam EntityDocument) (*EntityDocument, error) {
 		Description:  docParam.Description,
 		IsFromBase64: docParam.IsFromBase64,
 		Metadata:     docParam.Metadata,
+		StorageKey:   docParam.StorageKey,
+		SHA256:       docParam.SHA256,
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}
@@ -92,6 +168,11 @@ func (d *EntityDocument) validateFileExists() error {
 	if strings.HasPrefix(d.FilePath, "http://") || strings.HasPrefix(d.FilePath, "https://") {
 		return nil
 	}
+
+	// Arquivo já gravado no storage de documentos (storage://<chave>)
+	if d.StorageKey != "" && d.FilePath == "storage://"+d.StorageKey {
+		return nil
+	}
 	
 	if _, err := os.Stat(d.FilePath); os.IsNotExist(err) {
 		return fmt.Errorf("file does not exist: %s", d.FilePath)
@@ -129,6 +210,10 @@ func (d *EntityDocument) PrepareForSigning() error {
 }
 
 func (d *EntityDocument) SetStatus(status string) error {
+	if d.IsQuarantined() {
+		return fmt.Errorf("%w: status cannot change", ErrDocumentQuarantined)
+	}
+
 	validStatuses := []string{"draft", "ready", "processing", "sent"}
 
 	for _, validStatus := range validStatuses {
@@ -142,6 +227,238 @@ func (d *EntityDocument) SetStatus(status string) error {
 	return fmt.Errorf("invalid status: %s. Valid statuses: %s", status, strings.Join(validStatuses, ", "))
 }
 
+// SetStorageKey referencia o original gravado no storage em vez de um arquivo temporário
+func (d *EntityDocument) SetStorageKey(key string) {
+	d.StorageKey = key
+	d.FilePath = "storage://" + key
+	d.UpdatedAt = time.Now()
+}
+
+// SetContentHash registra o SHA-256 (hexadecimal) e o tamanho do original
+func (d *EntityDocument) SetContentHash(sum string, size int64) {
+	d.SHA256 = strings.ToLower(sum)
+	d.FileSize = size
+	d.UpdatedAt = time.Now()
+}
+
+// MarkSignedVersion registra que a versão atual é a que foi assinada
+func (d *EntityDocument) MarkSignedVersion() {
+	d.SignedVersion = d.CurrentVersion()
+	d.UpdatedAt = time.Now()
+}
+
+// SetSignedContent registra a cópia assinada gravada no storage e a versão que foi assinada
+func (d *EntityDocument) SetSignedContent(key, sum string, size int64) {
+	d.MarkSignedVersion()
+	d.SignedStorageKey = key
+	d.SignedSHA256 = strings.ToLower(sum)
+	d.SignedFileSize = size
+	d.UpdatedAt = time.Now()
+}
+
+// VerifyProviderHash compara o hash informado pelo provider com o do original e registra o resultado
+// Retorna erro quando os hashes divergem; sem hash do original não há o que comparar
+func (d *EntityDocument) VerifyProviderHash(sum string) error {
+	sum = strings.ToLower(strings.TrimSpace(sum))
+	if sum == "" || d.SHA256 == "" {
+		return nil
+	}
+
+	d.ProviderSHA256 = sum
+	d.UpdatedAt = time.Now()
+
+	if sum != d.SHA256 {
+		d.IntegrityStatus = IntegrityMismatch
+		return fmt.Errorf("document hash mismatch: stored %s, provider reported %s", d.SHA256, sum)
+	}
+
+	d.IntegrityStatus = IntegrityVerified
+	return nil
+}
+
+// CurrentVersion retorna a versão atual; documentos anteriores ao versionamento estão na versão 1
+func (d *EntityDocument) CurrentVersion() int {
+	if d.Version < 1 {
+		return 1
+	}
+	return d.Version
+}
+
+// ReplaceContent troca o conteúdo pelo de next e retorna a versão substituída, a ser guardada no histórico
+// Nome, descrição e status são mantidos; no metadata, as chaves que descrevem o conteúdo anterior são descartadas
+// e as de next prevalecem sobre as do cliente
+// A chave no provider passa a ser a de next, já que a versão anterior sai do envelope
+func (d *EntityDocument) ReplaceContent(next *EntityDocument) (*EntityDocumentVersion, error) {
+	if d.IsQuarantined() {
+		return nil, ErrDocumentQuarantined
+	}
+	if next.IsQuarantined() {
+		return nil, fmt.Errorf("replacement: %w", ErrDocumentQuarantined)
+	}
+	if d.Status != "draft" && d.Status != "ready" {
+		return nil, fmt.Errorf("document in '%s' status cannot be replaced", d.Status)
+	}
+
+	metadata, err := mergeReplacementMetadata(d.Metadata, next.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	previous := &EntityDocumentVersion{
+		DocumentID:   d.ID,
+		Version:      d.CurrentVersion(),
+		FilePath:     d.FilePath,
+		FileSize:     d.FileSize,
+		MimeType:     d.MimeType,
+		IsFromBase64: d.IsFromBase64,
+		StorageKey:   d.StorageKey,
+		SHA256:       d.SHA256,
+		ClicksignKey: d.ClicksignKey,
+		Metadata:     d.Metadata,
+		ReplacedAt:   now,
+	}
+
+	d.Version = previous.Version + 1
+	d.FilePath = next.FilePath
+	d.FileSize = next.FileSize
+	d.MimeType = next.MimeType
+	d.IsFromBase64 = next.IsFromBase64
+	d.StorageKey = next.StorageKey
+	d.SHA256 = next.SHA256
+	d.ClicksignKey = next.ClicksignKey
+	d.Metadata = metadata
+	d.ProviderSHA256 = ""
+	d.IntegrityStatus = ""
+	d.UpdatedAt = now
+
+	return previous, nil
+}
+
+// SetConversion registra a conversão em Metadata["conversion"], preservando o metadata informado pelo cliente
+func (d *EntityDocument) SetConversion(conversion DocumentConversion) error {
+	return d.setMetadataKey("conversion", conversion)
+}
+
+// SetNormalization registra em Metadata["normalization"] as origens e as etapas da normalização
+func (d *EntityDocument) SetNormalization(normalization DocumentNormalization) error {
+	return d.setMetadataKey("normalization", normalization)
+}
+
+// SetScan registra a verificação em Metadata["scan"]; conteúdo infectado coloca o documento em quarentena
+func (d *EntityDocument) SetScan(scan DocumentScan) error {
+	if scan.Infected {
+		d.Status = DocumentStatusQuarantined
+		d.UpdatedAt = time.Now()
+	}
+	return d.setMetadataKey("scan", scan)
+}
+
+func (d *EntityDocument) IsQuarantined() bool {
+	return d.Status == DocumentStatusQuarantined
+}
+
+// SetTemplateSource registra em Metadata["template"] o template e as variáveis que geraram o documento
+func (d *EntityDocument) SetTemplateSource(source DocumentTemplateSource) error {
+	return d.setMetadataKey("template", source)
+}
+
+// StorageKeys retorna as chaves no storage do original, da cópia assinada e dos arquivos de origem da conversão ou normalização
+func (d *EntityDocument) StorageKeys() []string {
+	return collectStorageKeys(d.Metadata, d.StorageKey, d.SignedStorageKey)
+}
+
+// StorageKeys retorna as chaves no storage do conteúdo da versão e dos seus arquivos de origem
+func (v *EntityDocumentVersion) StorageKeys() []string {
+	return collectStorageKeys(v.Metadata, v.StorageKey)
+}
+
+func collectStorageKeys(metadata datatypes.JSON, keys ...string) []string {
+	var source struct {
+		Conversion    *DocumentConversion    `json:"conversion"`
+		Normalization *DocumentNormalization `json:"normalization"`
+	}
+	if len(metadata) > 0 && json.Unmarshal(metadata, &source) == nil {
+		if source.Conversion != nil {
+			keys = append(keys, source.Conversion.SourceStorageKey)
+		}
+		if source.Normalization != nil {
+			for _, normalized := range source.Normalization.Sources {
+				keys = append(keys, normalized.StorageKey)
+			}
+		}
+	}
+
+	seen := make(map[string]bool, len(keys))
+	unique := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key != "" && !seen[key] {
+			seen[key] = true
+			unique = append(unique, key)
+		}
+	}
+	return unique
+}
+
+// contentMetadataKeys são as chaves reservadas que descrevem o conteúdo (origem, conversão e verificação)
+var contentMetadataKeys = []string{"conversion", "template", "normalization", "scan"}
+
+// mergeReplacementMetadata mantém o metadata do cliente sem as chaves de conteúdo e aplica por cima o de next
+func mergeReplacementMetadata(current, next datatypes.JSON) (datatypes.JSON, error) {
+	metadata, err := decodeMetadata(current)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range contentMetadataKeys {
+		delete(metadata, key)
+	}
+
+	values, err := decodeMetadata(next)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range values {
+		metadata[key] = value
+	}
+
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+	raw, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode document metadata: %w", err)
+	}
+	return datatypes.JSON(raw), nil
+}
+
+func decodeMetadata(raw datatypes.JSON) (map[string]interface{}, error) {
+	metadata := map[string]interface{}{}
+	if len(raw) > 0 && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &metadata); err != nil {
+			return nil, fmt.Errorf("invalid document metadata: %w", err)
+		}
+	}
+	return metadata, nil
+}
+
+// setMetadataKey grava uma chave reservada em Metadata sem descartar as demais
+func (d *EntityDocument) setMetadataKey(key string, value interface{}) error {
+	metadata, err := decodeMetadata(d.Metadata)
+	if err != nil {
+		return err
+	}
+
+	metadata[key] = value
+	raw, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to encode document metadata: %w", err)
+	}
+
+	d.Metadata = datatypes.JSON(raw)
+	d.UpdatedAt = time.Now()
+	return nil
+}
+
 func (d *EntityDocument) SetClicksignKey(key string) {
 	d.ClicksignKey = key
 	d.UpdatedAt = time.Now()
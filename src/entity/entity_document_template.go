@@ -0,0 +1,106 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	TemplateFormatHTML     = "html"
+	TemplateFormatMarkdown = "markdown"
+)
+
+// ErrMissingTemplateVariables é retornado quando a renderização não recebe todas as variáveis do template
+var ErrMissingTemplateVariables = errors.New("missing template variables")
+
+// placeholderPattern reconhece {{ nome }}; nomes aceitam letras, números, "_" e "."
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_.]*)\s*\}\}`)
+
+// EntityDocumentTemplate é um modelo de contrato em HTML ou Markdown com placeholders {{ variavel }}
+type EntityDocumentTemplate struct {
+	ID          int       `json:"id" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"not null" validate:"required,min=3,max=255"`
+	Description string    `json:"description"`
+	Format      string    `json:"format" gorm:"not null" validate:"required,oneof=html markdown"`
+	Content     string    `json:"content" gorm:"type:text;not null" validate:"required"`
+	TenantID    string    `json:"tenant_id,omitempty" gorm:"not null;default:'';index"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// DocumentTemplateSource registra no metadata do documento o template e as variáveis usados na geração
+type DocumentTemplateSource struct {
+	TemplateID   int               `json:"template_id"`
+	TemplateName string            `json:"template_name"`
+	Variables    map[string]string `json:"variables"`
+	RenderedAt   time.Time         `json:"rendered_at"`
+}
+
+// TableName sets the table name for GORM
+func (EntityDocumentTemplate) TableName() string {
+	return "document_templates"
+}
+
+func NewDocumentTemplate(templateParam EntityDocumentTemplate) (*EntityDocumentTemplate, error) {
+	now := time.Now()
+
+	template := &EntityDocumentTemplate{
+		Name:        templateParam.Name,
+		Description: templateParam.Description,
+		Format:      strings.ToLower(strings.TrimSpace(templateParam.Format)),
+		Content:     templateParam.Content,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := template.Validate(); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (t *EntityDocumentTemplate) Validate() error {
+	return validate.Struct(t)
+}
+
+// Placeholders retorna os nomes das variáveis do template, sem repetição, em ordem alfabética
+func (t *EntityDocumentTemplate) Placeholders() []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, match := range placeholderPattern.FindAllStringSubmatch(t.Content, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			names = append(names, match[1])
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Render substitui os placeholders pelas variáveis; todas as variáveis do template são obrigatórias
+// Em templates HTML os valores são escapados para não injetar marcação no documento
+func (t *EntityDocumentTemplate) Render(variables map[string]string) (string, error) {
+	var missing []string
+	for _, name := range t.Placeholders() {
+		if _, ok := variables[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("%w: %s", ErrMissingTemplateVariables, strings.Join(missing, ", "))
+	}
+
+	return placeholderPattern.ReplaceAllStringFunc(t.Content, func(placeholder string) string {
+		value := variables[placeholderPattern.FindStringSubmatch(placeholder)[1]]
+		if t.Format == TemplateFormatHTML {
+			return html.EscapeString(value)
+		}
+		return value
+	}), nil
+}
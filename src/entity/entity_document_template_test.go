@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDocumentTemplate(t *testing.T) {
+	template, err := NewDocumentTemplate(EntityDocumentTemplate{
+		Name:    "Contrato de prestação",
+		Format:  " Markdown ",
+		Content: "# Contrato\n\nContratante: {{ contratante }}",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, TemplateFormatMarkdown, template.Format)
+	assert.False(t, template.CreatedAt.IsZero())
+
+	_, err = NewDocumentTemplate(EntityDocumentTemplate{Name: "Contrato", Format: "docx", Content: "x"})
+	assert.Error(t, err)
+
+	_, err = NewDocumentTemplate(EntityDocumentTemplate{Name: "Contrato", Format: TemplateFormatHTML})
+	assert.Error(t, err)
+}
+
+func TestEntityDocumentTemplate_Placeholders(t *testing.T) {
+	template := &EntityDocumentTemplate{Content: "{{valor}} {{ contratante.nome }} {{ valor }} {{ 1invalido }}"}
+
+	assert.Equal(t, []string{"contratante.nome", "valor"}, template.Placeholders())
+}
+
+func TestEntityDocumentTemplate_Render(t *testing.T) {
+	t.Run("should substitute markdown variables as is", func(t *testing.T) {
+		template := &EntityDocumentTemplate{Format: TemplateFormatMarkdown, Content: "Contratante: {{ nome }} <{{email}}>"}
+
+		rendered, err := template.Render(map[string]string{"nome": "Ana & Cia", "email": "ana@exemplo.com"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Contratante: Ana & Cia <ana@exemplo.com>", rendered)
+	})
+
+	t.Run("should escape html variables", func(t *testing.T) {
+		template := &EntityDocumentTemplate{Format: TemplateFormatHTML, Content: "<p>{{ nome }}</p>"}
+
+		rendered, err := template.Render(map[string]string{"nome": "<script>Ana & Cia</script>"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "<p>&lt;script&gt;Ana &amp; Cia&lt;/script&gt;</p>", rendered)
+	})
+
+	t.Run("should list missing variables", func(t *testing.T) {
+		template := &EntityDocumentTemplate{Format: TemplateFormatMarkdown, Content: "{{ nome }} {{ cpf }} {{ valor }}"}
+
+		_, err := template.Render(map[string]string{"nome": "Ana"})
+
+		assert.ErrorIs(t, err, ErrMissingTemplateVariables)
+		assert.ErrorContains(t, err, "cpf, valor")
+	})
+}
+
+func TestEntityDocument_SetTemplateSource(t *testing.T) {
+	doc := &EntityDocument{Metadata: []byte(`{"contract_id":"42"}`)}
+
+	err := doc.SetTemplateSource(DocumentTemplateSource{TemplateID: 3, TemplateName: "Contrato", Variables: map[string]string{"nome": "Ana"}})
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"contract_id": "42",
+		"template": {
+			"template_id": 3,
+			"template_name": "Contrato",
+			"variables": {"nome": "Ana"},
+			"rendered_at": "0001-01-01T00:00:00Z"
+		}
+	}`, string(doc.Metadata))
+}
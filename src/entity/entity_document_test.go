@@ -2,6 +2,7 @@ package entity
 
 import (
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -321,3 +322,192 @@ func TestEntityDocument_SetClicksignKey(t *testing.T) {
 	assert.Equal(t, testKey, doc.ClicksignKey)
 	assert.True(t, doc.UpdatedAt.After(oldUpdatedAt))
 }
+
+func TestEntityDocument_SetStorageKey(t *testing.T) {
+	doc := &EntityDocument{
+		Name:      "Stored Document",
+		FilePath:  "/tmp/docsigner_base64_removed",
+		FileSize:  1024,
+		MimeType:  "application/pdf",
+		Status:    "draft",
+		UpdatedAt: time.Now().Add(-1 * time.Hour),
+	}
+
+	oldUpdatedAt := doc.UpdatedAt
+
+	doc.SetStorageKey("originals/sha256/abc123")
+
+	assert.Equal(t, "originals/sha256/abc123", doc.StorageKey)
+	assert.Equal(t, "storage://originals/sha256/abc123", doc.FilePath)
+	assert.True(t, doc.UpdatedAt.After(oldUpdatedAt))
+	assert.NoError(t, doc.Validate())
+}
+
+func TestEntityDocument_VerifyProviderHash(t *testing.T) {
+	sum := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+
+	t.Run("should mark document as verified when hashes match", func(t *testing.T) {
+		doc := &EntityDocument{SHA256: sum}
+
+		err := doc.VerifyProviderHash(strings.ToUpper(sum))
+
+		assert.NoError(t, err)
+		assert.Equal(t, IntegrityVerified, doc.IntegrityStatus)
+		assert.Equal(t, sum, doc.ProviderSHA256)
+	})
+
+	t.Run("should flag mismatch when provider reports another hash", func(t *testing.T) {
+		doc := &EntityDocument{SHA256: sum}
+
+		err := doc.VerifyProviderHash("e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")
+
+		assert.ErrorContains(t, err, "hash mismatch")
+		assert.Equal(t, IntegrityMismatch, doc.IntegrityStatus)
+	})
+
+	t.Run("should ignore when there is nothing to compare", func(t *testing.T) {
+		doc := &EntityDocument{}
+
+		assert.NoError(t, doc.VerifyProviderHash(sum))
+		assert.Empty(t, doc.IntegrityStatus)
+	})
+}
+
+func TestEntityDocument_SetConversion(t *testing.T) {
+	doc := &EntityDocument{Metadata: []byte(`{"contract_id":"42"}`)}
+
+	err := doc.SetConversion(DocumentConversion{
+		SourceMimeType:   "application/vnd.oasis.opendocument.text",
+		SourceStorageKey: "originals/sha256/abc",
+		Converter:        "libreoffice",
+	})
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"contract_id": "42",
+		"conversion": {
+			"source_mime_type": "application/vnd.oasis.opendocument.text",
+			"source_storage_key": "originals/sha256/abc",
+			"source_sha256": "",
+			"source_size": 0,
+			"converter": "libreoffice",
+			"converted_at": "0001-01-01T00:00:00Z"
+		}
+	}`, string(doc.Metadata))
+
+	assert.Error(t, (&EntityDocument{Metadata: []byte(`[1,2]`)}).SetConversion(DocumentConversion{}))
+}
+
+func TestEntityDocument_SetNormalization(t *testing.T) {
+	doc := &EntityDocument{}
+
+	err := doc.SetNormalization(DocumentNormalization{
+		Sources:    []DocumentSource{{MimeType: "image/jpeg", StorageKey: "originals/sha256/abc", SHA256: "abc", Size: 10}},
+		Steps:      []string{"images_converted"},
+		Normalizer: "basic",
+	})
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"normalization": {
+			"sources": [{"mime_type": "image/jpeg", "storage_key": "originals/sha256/abc", "sha256": "abc", "size": 10}],
+			"steps": ["images_converted"],
+			"normalizer": "basic",
+			"normalized_at": "0001-01-01T00:00:00Z"
+		}
+	}`, string(doc.Metadata))
+}
+
+func TestEntityDocument_SetScan(t *testing.T) {
+	t.Run("should record a clean scan without changing the status", func(t *testing.T) {
+		doc := &EntityDocument{Status: "draft", Metadata: []byte(`{"contract_id":"42"}`)}
+
+		require.NoError(t, doc.SetScan(DocumentScan{Scanner: "clamav"}))
+
+		assert.Equal(t, "draft", doc.Status)
+		assert.JSONEq(t, `{"contract_id":"42","scan":{"scanner":"clamav","infected":false,"scanned_at":"0001-01-01T00:00:00Z"}}`, string(doc.Metadata))
+	})
+
+	t.Run("should quarantine infected documents", func(t *testing.T) {
+		doc := &EntityDocument{Status: "draft"}
+
+		require.NoError(t, doc.SetScan(DocumentScan{Scanner: "clamav", Infected: true, Threat: "Eicar-Test-Signature"}))
+
+		assert.True(t, doc.IsQuarantined())
+		assert.Error(t, doc.SetStatus("ready"))
+		assert.Error(t, doc.PrepareForSigning())
+		assert.Equal(t, DocumentStatusQuarantined, doc.Status)
+	})
+}
+
+func TestEntityDocument_ReplaceContent(t *testing.T) {
+	t.Run("should keep the previous content as a version", func(t *testing.T) {
+		doc := &EntityDocument{
+			ID:           7,
+			Name:         "Contrato",
+			Status:       "draft",
+			FilePath:     "storage://originals/sha256/old",
+			FileSize:     10,
+			MimeType:     "application/pdf",
+			IsFromBase64: true,
+			StorageKey:   "originals/sha256/old",
+			SHA256:       "old",
+			ClicksignKey: "doc-old",
+			Metadata:     []byte(`{"contract_id":"42","scan":{"scanner":"clamav","infected":false,"scanned_at":"0001-01-01T00:00:00Z"}}`),
+		}
+		next := &EntityDocument{
+			FilePath:     "storage://originals/sha256/new",
+			FileSize:     20,
+			MimeType:     "application/pdf",
+			IsFromBase64: true,
+			StorageKey:   "originals/sha256/new",
+			SHA256:       "new",
+			ClicksignKey: "doc-new",
+			Metadata:     []byte(`{"revision":"2"}`),
+		}
+
+		previous, err := doc.ReplaceContent(next)
+
+		require.NoError(t, err)
+		assert.Equal(t, 7, previous.DocumentID)
+		assert.Equal(t, 1, previous.Version)
+		assert.Equal(t, "old", previous.SHA256)
+		assert.Equal(t, "doc-old", previous.ClicksignKey)
+		assert.False(t, previous.ReplacedAt.IsZero())
+
+		assert.Equal(t, 2, doc.CurrentVersion())
+		assert.Equal(t, "Contrato", doc.Name)
+		assert.Equal(t, "new", doc.SHA256)
+		assert.Equal(t, int64(20), doc.FileSize)
+		assert.Equal(t, "doc-new", doc.ClicksignKey)
+		assert.JSONEq(t, `{"contract_id":"42","revision":"2"}`, string(doc.Metadata))
+	})
+
+	t.Run("should reject documents that left the draft", func(t *testing.T) {
+		doc := &EntityDocument{Status: "sent"}
+
+		_, err := doc.ReplaceContent(&EntityDocument{})
+
+		assert.ErrorContains(t, err, "cannot be replaced")
+		assert.Equal(t, 1, doc.CurrentVersion())
+	})
+
+	t.Run("should reject quarantined content", func(t *testing.T) {
+		doc := &EntityDocument{Status: "draft"}
+
+		_, err := doc.ReplaceContent(&EntityDocument{Status: DocumentStatusQuarantined})
+
+		assert.ErrorIs(t, err, ErrDocumentQuarantined)
+	})
+}
+
+func TestEntityDocument_SignedVersion(t *testing.T) {
+	doc := &EntityDocument{Status: "draft"}
+	_, err := doc.ReplaceContent(&EntityDocument{SHA256: "v2"})
+	require.NoError(t, err)
+
+	doc.SetSignedContent("signed/sha256/abc", "ABC", 30)
+
+	assert.Equal(t, 2, doc.SignedVersion)
+	assert.Equal(t, "abc", doc.SignedSHA256)
+}
@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// EntityDocumentVersion guarda o conteúdo de uma versão substituída do documento
+// A versão atual fica sempre em EntityDocument; aqui ficam apenas as anteriores
+type EntityDocumentVersion struct {
+	ID           int            `json:"id" gorm:"primaryKey"`
+	DocumentID   int            `json:"document_id" gorm:"not null;uniqueIndex:idx_document_version"`
+	Version      int            `json:"version" gorm:"not null;uniqueIndex:idx_document_version"`
+	FilePath     string         `json:"file_path"`
+	FileSize     int64          `json:"file_size"`
+	MimeType     string         `json:"mime_type"`
+	IsFromBase64 bool           `json:"is_from_base64"`
+	StorageKey   string         `json:"storage_key,omitempty"`
+	SHA256       string         `json:"sha256,omitempty" gorm:"index"`
+	ClicksignKey string         `json:"clicksign_key,omitempty"` // Chave no provider enquanto esta versão esteve no envelope
+	Metadata     datatypes.JSON `json:"metadata" gorm:"type:jsonb"`
+	ReplacedAt   time.Time      `json:"replaced_at"` // Quando foi substituída pela versão seguinte
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
+// TableName sets the table name for GORM
+func (EntityDocumentVersion) TableName() string {
+	return "document_versions"
+}
@@ -5,6 +5,8 @@ import (
 	"net/mail"
 	"strings"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type EntityEnvelopeFilters struct {
@@ -12,23 +14,29 @@ type EntityEnvelopeFilters struct {
 	Search       string `json:"search"`
 	Status       string `json:"status"`
 	ClicksignKey string `json:"clicksign_key"`
+	CreatedByID  int    `json:"created_by_id"` // Restringe aos envelopes criados pelo usuário; 0 não filtra
 }
 
 type EntityEnvelope struct {
-	ID               int        `json:"id" gorm:"primaryKey"`
-	Name             string     `json:"name" gorm:"not null" validate:"required,min=3,max=255"`
-	Description      string     `json:"description" validate:"max=1000"`
-	Status           string     `json:"status" gorm:"not null;default:'draft'" validate:"required,oneof=draft sent pending completed cancelled"`
-	ClicksignKey     string     `json:"clicksign_key" gorm:"index"`
-	ClicksignRawData *string    `json:"clicksign_raw_data" gorm:"type:text"`
-	DocumentsIDs     []int      `json:"documents_ids" gorm:"serializer:json" validate:"-"`
-	SignatoryEmails  []string   `json:"signatory_emails" gorm:"serializer:json"`
-	Message          string     `json:"message" validate:"max=500"`
-	DeadlineAt       *time.Time `json:"deadline_at"`
-	RemindInterval   int        `json:"remind_interval" validate:"min=1,max=30"`
-	AutoClose        bool       `json:"auto_close" gorm:"default:true"`
-	CreatedAt        time.Time  `json:"created_at"`
-	UpdatedAt        time.Time  `json:"updated_at"`
+	ID               int                        `json:"id" gorm:"primaryKey"`
+	Name             string                     `json:"name" gorm:"not null" validate:"required,min=3,max=255"`
+	Description      string                     `json:"description" validate:"max=1000"`
+	Status           string                     `json:"status" gorm:"not null;default:'draft'" validate:"required,oneof=draft pending_approval approved rejected sent pending completed cancelled"`
+	ClicksignKey     string                     `json:"clicksign_key" gorm:"index"`
+	ClicksignRawData *string                    `json:"clicksign_raw_data" gorm:"type:text"`
+	DocumentsIDs     []int                      `json:"documents_ids" gorm:"serializer:json" validate:"-"`
+	SignatoryEmails  []string                   `json:"signatory_emails" gorm:"serializer:json"`
+	Message          string                     `json:"message" validate:"max=500"`
+	DeadlineAt       *time.Time                 `json:"deadline_at"`
+	RemindInterval   int                        `json:"remind_interval" validate:"min=1,max=30"`
+	AutoClose        bool                       `json:"auto_close" gorm:"default:true"`
+	Provider         string                     `json:"provider,omitempty" gorm:"index"`                 // Provider que efetivamente mantém o envelope
+	TenantID         string                     `json:"tenant_id,omitempty" gorm:"index"`                // Tenant dono do envelope; define também as credenciais de provider usadas
+	CreatedByID      int                        `json:"created_by_id,omitempty" gorm:"index"`            // Usuário que criou o envelope; 0 para envelopes criados por API key
+	ApprovalTrail    []EnvelopeApprovalDecision `json:"approval_trail,omitempty" gorm:"serializer:json"` // Pedidos e decisões de aprovação, em ordem
+	FinishedAt       *time.Time                 `json:"finished_at,omitempty" gorm:"index"`              // Quando o envelope foi concluído ou cancelado; base dos prazos de retenção
+	CreatedAt        time.Time                  `json:"created_at"`
+	UpdatedAt        time.Time                  `json:"updated_at"`
 }
 
 // TableName sets the table name for GORM
@@ -59,6 +67,9 @@ func NewEnvelope(envelopeParam EntityEnvelope) (*EntityEnvelope, error) {
 		DeadlineAt:       envelopeParam.DeadlineAt,
 		RemindInterval:   envelopeParam.RemindInterval,
 		AutoClose:        envelopeParam.AutoClose,
+		Provider:         envelopeParam.Provider,
+		TenantID:         envelopeParam.TenantID,
+		CreatedByID:      envelopeParam.CreatedByID,
 		CreatedAt:        now,
 		UpdatedAt:        now,
 	}
@@ -114,12 +125,13 @@ func (e *EntityEnvelope) validateDeadline() error {
 }
 
 func (e *EntityEnvelope) SetStatus(status string) error {
-	validStatuses := []string{"draft", "sent", "pending", "completed", "cancelled"}
+	validStatuses := []string{"draft", EnvelopeStatusPendingApproval, EnvelopeStatusApproved, EnvelopeStatusRejected, "sent", "pending", "completed", "cancelled"}
 
 	for _, validStatus := range validStatuses {
 		if status == validStatus {
 			e.Status = status
 			e.UpdatedAt = time.Now()
+			e.markFinished(e.UpdatedAt)
 			return nil
 		}
 	}
@@ -127,13 +139,35 @@ func (e *EntityEnvelope) SetStatus(status string) error {
 	return fmt.Errorf("invalid status: %s. Valid statuses: %s", status, strings.Join(validStatuses, ", "))
 }
 
+// IsFinished indica se o envelope foi concluído ou cancelado e não muda mais
+func (e *EntityEnvelope) IsFinished() bool {
+	return e.Status == "completed" || e.Status == "cancelled"
+}
+
+// markFinished registra o momento da finalização uma única vez; updated_at muda a cada edição posterior
+func (e *EntityEnvelope) markFinished(at time.Time) {
+	if e.IsFinished() && e.FinishedAt == nil {
+		e.FinishedAt = &at
+	}
+}
+
+// BeforeSave registra finished_at também quando o status é alterado sem SetStatus
+func (e *EntityEnvelope) BeforeSave(tx *gorm.DB) error {
+	e.markFinished(time.Now())
+	return nil
+}
+
 func (e *EntityEnvelope) SetClicksignKey(key string) {
 	e.ClicksignKey = key
 	e.UpdatedAt = time.Now()
 }
 
 func (e *EntityEnvelope) ActivateEnvelope() error {
-	if e.Status != "draft" {
+	// Envelopes que entraram no fluxo de aprovação só são ativados depois de aprovados
+	if e.requiresApproval() && (e.Status != EnvelopeStatusApproved || !e.IsApproved()) {
+		return fmt.Errorf("%w, current status: %s", ErrEnvelopeNotApproved, e.Status)
+	}
+	if e.Status != "draft" && e.Status != EnvelopeStatusApproved {
 		return fmt.Errorf("envelope must be in 'draft' status to activate, current status: %s", e.Status)
 	}
 
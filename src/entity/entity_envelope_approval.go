@@ -0,0 +1,113 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Status do envelope durante a aprovação; a ativação no provider só acontece depois de approved
+// Envelopes aprovados não aceitam mais edições: o que foi aprovado é exatamente o que será enviado
+const (
+	EnvelopeStatusPendingApproval = "pending_approval"
+	EnvelopeStatusApproved        = "approved"
+	EnvelopeStatusRejected        = "rejected"
+)
+
+// Registros da trilha de aprovação
+const (
+	ApprovalActionRequested = "requested"
+	ApprovalActionApproved  = "approved"
+	ApprovalActionRejected  = "rejected"
+)
+
+var (
+	ErrEnvelopeNotPendingApproval = errors.New("envelope is not pending approval")
+	ErrSelfApproval               = errors.New("the creator of the envelope cannot approve or reject it")
+	ErrRejectionCommentRequired   = errors.New("a comment is required to reject an envelope")
+	ErrEnvelopeNotApproved        = errors.New("envelope requires approval before activation")
+	// ErrEnvelopeNotDraft é retornado ao alterar documentos ou requisitos de um envelope que já saiu do rascunho
+	ErrEnvelopeNotDraft = errors.New("envelope is not in draft status")
+)
+
+// EnvelopeApprovalDecision é um registro da trilha de aprovação guardada no envelope
+type EnvelopeApprovalDecision struct {
+	Action    string    `json:"action"`
+	UserID    int       `json:"user_id"`
+	UserName  string    `json:"user_name,omitempty"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CheckDraft garante que o envelope ainda aceita edições; pendentes de aprovação e aprovados ficam travados
+func (e *EntityEnvelope) CheckDraft() error {
+	if e.Status != "draft" {
+		return fmt.Errorf("%w: envelope is '%s'", ErrEnvelopeNotDraft, e.Status)
+	}
+	return nil
+}
+
+// RequestApproval coloca o envelope em rascunho na fila de aprovação em nome de requester
+func (e *EntityEnvelope) RequestApproval(requester EntityUser, now time.Time) {
+	e.Status = EnvelopeStatusPendingApproval
+	e.appendApproval(ApprovalActionRequested, requester, "", now)
+}
+
+// Approve registra a aprovação e coloca o envelope em approved, pronto para a ativação no provider
+func (e *EntityEnvelope) Approve(approver EntityUser, comment string, now time.Time) error {
+	if err := e.checkApprover(approver); err != nil {
+		return err
+	}
+
+	e.Status = EnvelopeStatusApproved
+	e.appendApproval(ApprovalActionApproved, approver, comment, now)
+	return nil
+}
+
+// Reject registra a rejeição com o motivo; o envelope não pode mais ser ativado
+func (e *EntityEnvelope) Reject(approver EntityUser, comment string, now time.Time) error {
+	if err := e.checkApprover(approver); err != nil {
+		return err
+	}
+	if strings.TrimSpace(comment) == "" {
+		return ErrRejectionCommentRequired
+	}
+
+	e.Status = EnvelopeStatusRejected
+	e.appendApproval(ApprovalActionRejected, approver, comment, now)
+	return nil
+}
+
+// IsApproved informa se a última decisão da trilha de aprovação foi a aprovação
+func (e *EntityEnvelope) IsApproved() bool {
+	last := len(e.ApprovalTrail) - 1
+	return last >= 0 && e.ApprovalTrail[last].Action == ApprovalActionApproved
+}
+
+// requiresApproval indica se o envelope entrou no fluxo de aprovação
+func (e *EntityEnvelope) requiresApproval() bool {
+	return len(e.ApprovalTrail) > 0
+}
+
+// checkApprover aplica os quatro olhos: quem criou o envelope não decide sobre ele
+func (e *EntityEnvelope) checkApprover(approver EntityUser) error {
+	if e.Status != EnvelopeStatusPendingApproval {
+		return ErrEnvelopeNotPendingApproval
+	}
+	if e.CreatedByID != 0 && e.CreatedByID == approver.ID {
+		return ErrSelfApproval
+	}
+	return nil
+}
+
+func (e *EntityEnvelope) appendApproval(action string, user EntityUser, comment string, now time.Time) {
+	e.ApprovalTrail = append(e.ApprovalTrail, EnvelopeApprovalDecision{
+		Action:    action,
+		UserID:    user.ID,
+		UserName:  user.Name,
+		Comment:   strings.TrimSpace(comment),
+		CreatedAt: now,
+	})
+	e.UpdatedAt = now
+}
@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntityEnvelope_ApprovalWorkflow(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	operator := EntityUser{ID: 1, Name: "Operador", Role: RoleOperator}
+	approver := EntityUser{ID: 2, Name: "Aprovador", Role: RoleApprover}
+
+	t.Run("should approve envelope pending approval", func(t *testing.T) {
+		envelope := &EntityEnvelope{Status: "draft", CreatedByID: operator.ID}
+
+		envelope.RequestApproval(operator, now)
+		assert.Equal(t, EnvelopeStatusPendingApproval, envelope.Status)
+		assert.False(t, envelope.IsApproved())
+		assert.Error(t, envelope.ActivateEnvelope())
+
+		require.NoError(t, envelope.Approve(approver, " ok ", now))
+
+		assert.Equal(t, EnvelopeStatusApproved, envelope.Status)
+		assert.True(t, envelope.IsApproved())
+		require.Len(t, envelope.ApprovalTrail, 2)
+		assert.Equal(t, EnvelopeApprovalDecision{Action: ApprovalActionApproved, UserID: 2, UserName: "Aprovador", Comment: "ok", CreatedAt: now}, envelope.ApprovalTrail[1])
+	})
+
+	t.Run("should only activate approved envelopes after the approval flow", func(t *testing.T) {
+		envelope := &EntityEnvelope{Status: "draft", CreatedByID: operator.ID}
+		envelope.RequestApproval(operator, now)
+		require.NoError(t, envelope.Approve(approver, "", now))
+
+		// Um envelope da fila devolvido a draft (ex.: por SetStatus) não pode ser ativado sem nova aprovação
+		envelope.Status = "draft"
+		assert.ErrorIs(t, envelope.ActivateEnvelope(), ErrEnvelopeNotApproved)
+
+		envelope.Status = EnvelopeStatusApproved
+		require.NoError(t, envelope.ActivateEnvelope())
+		assert.Equal(t, "sent", envelope.Status)
+
+		withoutApproval := &EntityEnvelope{Status: "draft"}
+		require.NoError(t, withoutApproval.ActivateEnvelope())
+	})
+
+	t.Run("should refuse approval by the creator", func(t *testing.T) {
+		envelope := &EntityEnvelope{Status: "draft", CreatedByID: approver.ID}
+		envelope.RequestApproval(approver, now)
+
+		assert.ErrorIs(t, envelope.Approve(approver, "", now), ErrSelfApproval)
+		assert.ErrorIs(t, envelope.Reject(approver, "não", now), ErrSelfApproval)
+		assert.Equal(t, EnvelopeStatusPendingApproval, envelope.Status)
+	})
+
+	t.Run("should require a comment to reject", func(t *testing.T) {
+		envelope := &EntityEnvelope{Status: "draft", CreatedByID: operator.ID}
+		envelope.RequestApproval(operator, now)
+
+		assert.ErrorIs(t, envelope.Reject(approver, "  ", now), ErrRejectionCommentRequired)
+		require.NoError(t, envelope.Reject(approver, "Valor divergente", now))
+
+		assert.Equal(t, EnvelopeStatusRejected, envelope.Status)
+		assert.Error(t, envelope.ActivateEnvelope())
+		assert.ErrorIs(t, envelope.Approve(approver, "", now), ErrEnvelopeNotPendingApproval)
+	})
+
+	t.Run("should only decide envelopes pending approval", func(t *testing.T) {
+		envelope := &EntityEnvelope{Status: "draft"}
+
+		assert.ErrorIs(t, envelope.Approve(approver, "", now), ErrEnvelopeNotPendingApproval)
+		assert.Empty(t, envelope.ApprovalTrail)
+	})
+}
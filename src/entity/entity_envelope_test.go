@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewEnvelope(t *testing.T) {
@@ -170,6 +171,38 @@ func TestEnvelopeSetStatus(t *testing.T) {
 		assert.Equal(t, "sent", envelope.Status)
 	})
 
+	t.Run("should record when the envelope finished only once", func(t *testing.T) {
+		// Arrange
+		envelope := &EntityEnvelope{
+			Status: "sent",
+		}
+
+		// Act
+		err := envelope.SetStatus("completed")
+		finishedAt := envelope.FinishedAt
+		_ = envelope.BeforeSave(nil)
+
+		// Assert
+		assert.NoError(t, err)
+		require.NotNil(t, finishedAt)
+		assert.Same(t, finishedAt, envelope.FinishedAt)
+	})
+
+	t.Run("should not record finished_at for open envelopes", func(t *testing.T) {
+		// Arrange
+		envelope := &EntityEnvelope{
+			Status: "draft",
+		}
+
+		// Act
+		err := envelope.SetStatus("sent")
+		_ = envelope.BeforeSave(nil)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Nil(t, envelope.FinishedAt)
+	})
+
 	t.Run("should fail with invalid status", func(t *testing.T) {
 		// Arrange
 		envelope := &EntityEnvelope{
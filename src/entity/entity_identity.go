@@ -0,0 +1,84 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrIdentityNotAllowed = errors.New("identity is not allowed to access the api")
+	ErrUserNotProvisioned = errors.New("user is not provisioned")
+	ErrUserInactive       = errors.New("user is inactive")
+	ErrUnverifiedEmail    = errors.New("identity email is not verified")
+	ErrIdentityNotLinked  = errors.New("a local user with the identity email exists and is not linked to the identity provider")
+	ErrLocalLoginDisabled = errors.New("local login is disabled; sign in with the identity provider")
+	ErrExternalIdentity   = errors.New("tokens issued by the identity provider must be revoked at the provider")
+)
+
+// ExternalIdentity é o usuário autenticado pelo provedor OIDC, já extraído das claims do token
+type ExternalIdentity struct {
+	Issuer  string
+	Subject string
+	Email   string
+	// EmailVerified é falso apenas quando o provedor informa email_verified=false
+	EmailVerified bool
+	Name          string
+	Groups        []string
+}
+
+// ExternalID identifica o usuário de forma estável no provedor; o email pode mudar
+func (i *ExternalIdentity) ExternalID() string {
+	return i.Issuer + "|" + i.Subject
+}
+
+// InAnyGroup informa se a identidade pertence a algum dos grupos
+func (i *ExternalIdentity) InAnyGroup(groups []string) bool {
+	for _, group := range groups {
+		for _, granted := range i.Groups {
+			if strings.EqualFold(granted, group) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NewUserFromIdentity cria o usuário local de uma identidade externa
+// A senha é aleatória e não é revelada: o usuário só entra pelo provedor
+func NewUserFromIdentity(identity *ExternalIdentity, isAdmin bool) (*EntityUser, error) {
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	password, err := GeneratePassword(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(identity.Name)
+	if len([]rune(name)) < 3 {
+		name = identity.Email
+	}
+	if runes := []rune(name); len(runes) > 120 {
+		name = string(runes[:120])
+	}
+
+	now := time.Now()
+	user := &EntityUser{
+		Name:       name,
+		Email:      identity.Email,
+		Password:   password,
+		Active:     true,
+		ExternalID: identity.ExternalID(),
+		Role:       DefaultRole,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	user.SetAdmin(isAdmin)
+
+	if err := user.Validate(); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
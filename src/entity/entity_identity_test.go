@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewUserFromIdentity(t *testing.T) {
+	identity := &ExternalIdentity{
+		Issuer:  "https://sso.empresa.com",
+		Subject: "f7c1d2",
+		Email:   "maria@empresa.com",
+		Groups:  []string{"Assinaturas-Admin"},
+	}
+
+	user, err := NewUserFromIdentity(identity, identity.InAnyGroup([]string{"assinaturas-admin"}))
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://sso.empresa.com|f7c1d2", user.ExternalID)
+	assert.Equal(t, "maria@empresa.com", user.Name)
+	assert.True(t, user.IsAdmin)
+	assert.True(t, user.Active)
+	assert.NotEmpty(t, user.Password)
+
+	_, err = NewUserFromIdentity(&ExternalIdentity{Subject: "f7c1d2", Email: "not-an-email"}, false)
+	assert.Error(t, err)
+}
@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// MinPasswordLength é o mínimo aceito mesmo com uma política configurada mais branda
+const MinPasswordLength = 8
+
+var ErrWeakPassword = errors.New("password does not meet the password policy")
+
+// PasswordPolicy define as regras das senhas escolhidas pelos usuários
+type PasswordPolicy struct {
+	MinLength int
+	// RequireMixed exige letras maiúsculas, minúsculas e dígitos
+	RequireMixed bool
+}
+
+// Check valida a senha em claro; email impede senhas iguais ao próprio login
+func (p PasswordPolicy) Check(password, email string) error {
+	minLength := max(p.MinLength, MinPasswordLength)
+	if len([]rune(password)) < minLength {
+		return fmt.Errorf("%w: must have at least %d characters", ErrWeakPassword, minLength)
+	}
+	if len(password) > 72 {
+		// bcrypt ignora o que passa de 72 bytes
+		return fmt.Errorf("%w: must have at most 72 bytes", ErrWeakPassword)
+	}
+	if email != "" && strings.EqualFold(password, email) {
+		return fmt.Errorf("%w: must differ from the email", ErrWeakPassword)
+	}
+
+	if p.RequireMixed {
+		var upper, lower, digit bool
+		for _, r := range password {
+			switch {
+			case unicode.IsUpper(r):
+				upper = true
+			case unicode.IsLower(r):
+				lower = true
+			case unicode.IsDigit(r):
+				digit = true
+			}
+		}
+		if !upper || !lower || !digit {
+			return fmt.Errorf("%w: must mix uppercase letters, lowercase letters and digits", ErrWeakPassword)
+		}
+	}
+
+	return nil
+}
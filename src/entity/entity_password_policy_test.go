@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordPolicy_Check(t *testing.T) {
+	policy := PasswordPolicy{MinLength: 10, RequireMixed: true}
+
+	assert.NoError(t, policy.Check("Segura2026!", "maria@empresa.com"))
+	assert.ErrorIs(t, policy.Check("Curta1", ""), ErrWeakPassword)
+	assert.ErrorIs(t, policy.Check("semmaiusculas1", ""), ErrWeakPassword)
+	assert.ErrorIs(t, policy.Check("SEMMINUSCULAS1", ""), ErrWeakPassword)
+	assert.ErrorIs(t, policy.Check("SemDigitosAqui", ""), ErrWeakPassword)
+	assert.ErrorIs(t, policy.Check("Maria1@Empresa.com", "maria1@empresa.com"), ErrWeakPassword)
+	assert.ErrorIs(t, policy.Check("Aa1"+strings.Repeat("x", 70), ""), ErrWeakPassword)
+
+	t.Run("should never accept less than the minimum length", func(t *testing.T) {
+		lenient := PasswordPolicy{MinLength: 4}
+
+		assert.ErrorIs(t, lenient.Check("abc123", ""), ErrWeakPassword)
+		assert.NoError(t, lenient.Check("abcd1234", ""))
+	})
+}
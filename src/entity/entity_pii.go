@@ -0,0 +1,268 @@
+package entity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// EncryptedPIIPrefix identifica os valores cifrados; valores sem ele são legados em texto puro
+const EncryptedPIIPrefix = "enc:"
+
+var ErrPIICipherNotConfigured = errors.New("encrypted personal data found but PII encryption is not configured")
+
+// ErrSecretCipherNotConfigured impede gravar ou ler segredos (tag gorm:"serializer:secret") sem a cifragem configurada
+var ErrSecretCipherNotConfigured = errors.New("secrets require PII_ENCRYPTION_KEYS to be configured")
+
+// PIICipher cifra os dados pessoais dos signatários antes de gravá-los (tag gorm:"serializer:encrypted")
+// binding identifica a tabela, a coluna e a linha do valor (ver PIIBinding): um valor cifrado copiado
+// para outra linha ou coluna não é decifrado
+type PIICipher interface {
+	Encrypt(plaintext, binding string) (string, error)
+	// Decrypt devolve sem alteração os valores legados em texto puro
+	Decrypt(value, binding string) (string, error)
+	// BlindIndex calcula o índice usado nas buscas por igualdade sem revelar o valor
+	BlindIndex(value string) string
+	// CurrentPrefix é o prefixo dos valores cifrados com a chave ativa; os demais precisam ser recifrados
+	CurrentPrefix() string
+}
+
+var (
+	piiCipherMu sync.RWMutex
+	piiCipher   PIICipher
+)
+
+func init() {
+	schema.RegisterSerializer("encrypted", EncryptedSerializer{})
+	schema.RegisterSerializer("secret", SecretSerializer{})
+}
+
+// SetPIICipher define a cifragem usada pelo serializer; sem ela os dados são gravados em texto puro
+func SetPIICipher(cipher PIICipher) {
+	piiCipherMu.Lock()
+	defer piiCipherMu.Unlock()
+	piiCipher = cipher
+}
+
+// CurrentPIICipher retorna a cifragem configurada, ou nil
+func CurrentPIICipher() PIICipher {
+	piiCipherMu.RLock()
+	defer piiCipherMu.RUnlock()
+	return piiCipher
+}
+
+// EncryptedSerializer cifra campos string e *string na gravação e decifra na leitura
+type EncryptedSerializer struct{}
+
+func (EncryptedSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	return scanEncrypted(ctx, field, dst, dbValue, decryptPII)
+}
+
+func (EncryptedSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	return valueEncrypted(ctx, field, dst, fieldValue, encryptPII)
+}
+
+// SecretSerializer cifra credenciais como o EncryptedSerializer, mas falha quando a cifragem não está configurada
+// em vez de gravar o valor em texto puro
+type SecretSerializer struct{}
+
+func (SecretSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	return scanEncrypted(ctx, field, dst, dbValue, decryptSecret)
+}
+
+func (SecretSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	return valueEncrypted(ctx, field, dst, fieldValue, encryptSecret)
+}
+
+// PIIBinding identifica a coluna e a linha de um valor cifrado, no formato tabela.coluna#id
+func PIIBinding(table, column string, id interface{}) string {
+	return fmt.Sprintf("%s.%s#%v", table, column, id)
+}
+
+// fieldBinding calcula o binding do campo na linha dst; linhas ainda não inseridas usam o id zero
+// e são amarradas ao id definitivo por bindEncryptedFields
+func fieldBinding(ctx context.Context, field *schema.Field, dst reflect.Value) string {
+	var id interface{} = 0
+	if primaryKey := field.Schema.PrioritizedPrimaryField; primaryKey != nil && dst.IsValid() {
+		id, _ = primaryKey.ValueOf(ctx, dst)
+	}
+	return PIIBinding(field.Schema.Table, field.DBName, id)
+}
+
+// bindEncryptedFields regrava os campos cifrados de uma linha recém-criada com o binding do id gerado na inserção
+// Usado no AfterCreate dos modelos com campos serializer:encrypted ou serializer:secret
+func bindEncryptedFields(tx *gorm.DB, model interface{}) error {
+	if CurrentPIICipher() == nil || tx.Statement.Schema == nil {
+		return nil
+	}
+	primaryKey := tx.Statement.Schema.PrioritizedPrimaryField
+	if primaryKey == nil {
+		return nil
+	}
+	if _, isZero := primaryKey.ValueOf(tx.Statement.Context, reflect.ValueOf(model)); isZero {
+		return nil
+	}
+
+	var columns []string
+	for _, field := range tx.Statement.Schema.Fields {
+		switch field.TagSettings["SERIALIZER"] {
+		case "encrypted", "secret":
+			columns = append(columns, field.DBName)
+		}
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+
+	// UpdateColumns não altera updated_at e, sem hooks, não volta a chamar o AfterCreate
+	return tx.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Model(model).Select(columns).UpdateColumns(model).Error
+}
+
+func scanEncrypted(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}, decrypt func(value, binding string) (string, error)) error {
+	fieldValue := reflect.New(field.FieldType)
+
+	if dbValue != nil {
+		var stored string
+		switch v := dbValue.(type) {
+		case []byte:
+			stored = string(v)
+		case string:
+			stored = v
+		default:
+			return fmt.Errorf("unsupported encrypted value type %T for %s", dbValue, field.Name)
+		}
+
+		plaintext, err := decrypt(stored, fieldBinding(ctx, field, dst))
+		if err != nil {
+			return fmt.Errorf("failed to decrypt %s: %w", field.Name, err)
+		}
+
+		if field.FieldType.Kind() == reflect.Ptr {
+			fieldValue.Elem().Set(reflect.ValueOf(&plaintext))
+		} else {
+			fieldValue.Elem().SetString(plaintext)
+		}
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(fieldValue.Elem())
+	return nil
+}
+
+func valueEncrypted(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}, encrypt func(plaintext, binding string) (string, error)) (interface{}, error) {
+	switch v := fieldValue.(type) {
+	case string:
+		return encrypt(v, fieldBinding(ctx, field, dst))
+	case *string:
+		if v == nil {
+			return nil, nil
+		}
+		return encrypt(*v, fieldBinding(ctx, field, dst))
+	}
+	return nil, fmt.Errorf("encrypted serializer supports only string fields, got %T for %s", fieldValue, field.Name)
+}
+
+func encryptPII(plaintext, binding string) (string, error) {
+	cipher := CurrentPIICipher()
+	if cipher == nil || plaintext == "" {
+		return plaintext, nil
+	}
+	return cipher.Encrypt(plaintext, binding)
+}
+
+func decryptPII(value, binding string) (string, error) {
+	cipher := CurrentPIICipher()
+	if cipher == nil {
+		if strings.HasPrefix(value, EncryptedPIIPrefix) {
+			return "", ErrPIICipherNotConfigured
+		}
+		return value, nil
+	}
+	return cipher.Decrypt(value, binding)
+}
+
+func encryptSecret(plaintext, binding string) (string, error) {
+	if plaintext == "" {
+		return plaintext, nil
+	}
+	cipher := CurrentPIICipher()
+	if cipher == nil {
+		return "", ErrSecretCipherNotConfigured
+	}
+	return cipher.Encrypt(plaintext, binding)
+}
+
+// decryptSecret aceita os segredos legados em texto puro apenas com a cifragem ativa, para que a recifragem os alcance
+func decryptSecret(value, binding string) (string, error) {
+	if value == "" {
+		return value, nil
+	}
+	cipher := CurrentPIICipher()
+	if cipher == nil {
+		return "", ErrSecretCipherNotConfigured
+	}
+	return cipher.Decrypt(value, binding)
+}
+
+// DocumentationIndex calcula o blind index do CPF/CNPJ, ignorando pontuação e caixa
+// Sem cifragem configurada o índice é um hash simples, pois o próprio valor está em texto puro
+func DocumentationIndex(documentation string) string {
+	normalized := strings.ToUpper(strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, documentation))
+	if normalized == "" {
+		return ""
+	}
+
+	if cipher := CurrentPIICipher(); cipher != nil {
+		return cipher.BlindIndex(normalized)
+	}
+	return hashSecret(normalized)
+}
+
+// MaskDocumentation mantém visíveis apenas os dois últimos caracteres do CPF/CNPJ
+func MaskDocumentation(value string) string {
+	return maskAlphanumerics(value, 2)
+}
+
+// MaskPhoneNumber mantém visíveis apenas os quatro últimos dígitos
+func MaskPhoneNumber(value string) string {
+	return maskAlphanumerics(value, 4)
+}
+
+// MaskBirthday oculta a data inteira, mantendo apenas o formato
+func MaskBirthday(value string) string {
+	return maskAlphanumerics(value, 0)
+}
+
+func maskAlphanumerics(value string, visible int) string {
+	runes := []rune(value)
+	for i := len(runes) - 1; i >= 0; i-- {
+		if !unicode.IsLetter(runes[i]) && !unicode.IsDigit(runes[i]) {
+			continue
+		}
+		if visible > 0 {
+			visible--
+			continue
+		}
+		runes[i] = '*'
+	}
+	return string(runes)
+}
+
+func maskOptional(value *string, mask func(string) string) *string {
+	if value == nil {
+		return nil
+	}
+	masked := mask(*value)
+	return &masked
+}
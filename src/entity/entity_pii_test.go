@@ -0,0 +1,219 @@
+package entity
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+	"gorm.io/gorm/utils/tests"
+)
+
+// fakePIICipher marca os valores com o binding em vez de cifrá-los, o suficiente para verificar o serializer
+type fakePIICipher struct{}
+
+func (fakePIICipher) Encrypt(plaintext, binding string) (string, error) {
+	return "enc:fake:" + binding + ":" + plaintext, nil
+}
+
+func (fakePIICipher) Decrypt(value, binding string) (string, error) {
+	if !strings.HasPrefix(value, "enc:fake:"+binding+":") {
+		return "", errors.New("value bound to another row")
+	}
+	return strings.TrimPrefix(value, "enc:fake:"+binding+":"), nil
+}
+
+func (fakePIICipher) BlindIndex(value string) string {
+	return "idx:" + value
+}
+
+func (fakePIICipher) CurrentPrefix() string {
+	return "enc:fake:"
+}
+
+func withPIICipher(t *testing.T, cipher PIICipher) {
+	previous := CurrentPIICipher()
+	SetPIICipher(cipher)
+	t.Cleanup(func() { SetPIICipher(previous) })
+}
+
+func TestDocumentationIndex(t *testing.T) {
+	t.Run("should ignore punctuation and case", func(t *testing.T) {
+		assert.Equal(t, DocumentationIndex("123.456.789-09"), DocumentationIndex("12345678909"))
+		assert.Equal(t, DocumentationIndex("12.abc.345/01de-35"), DocumentationIndex("12ABC34501DE35"))
+		assert.NotEqual(t, DocumentationIndex("12345678909"), DocumentationIndex("12345678900"))
+		assert.Empty(t, DocumentationIndex(" .-/ "))
+	})
+
+	t.Run("should use the blind index of the configured cipher", func(t *testing.T) {
+		withPIICipher(t, fakePIICipher{})
+
+		assert.Equal(t, "idx:12345678909", DocumentationIndex("123.456.789-09"))
+	})
+}
+
+func TestMaskPII(t *testing.T) {
+	assert.Equal(t, "***.***.***-09", MaskDocumentation("123.456.789-09"))
+	assert.Equal(t, "**.***.***/****-35", MaskDocumentation("12.ABC.345/01DE-35"))
+	assert.Equal(t, "+** ** *****-8888", MaskPhoneNumber("+55 11 99999-8888"))
+	assert.Equal(t, "****-**-**", MaskBirthday("1990-01-15"))
+	assert.Equal(t, "", MaskDocumentation(""))
+}
+
+func TestEntitySignatory_Masked(t *testing.T) {
+	documentation := "123.456.789-09"
+	birthday := "1990-01-15"
+	signatory := EntitySignatory{Name: "Maria", Email: "maria@example.com", Documentation: &documentation, Birthday: &birthday}
+
+	masked := signatory.Masked()
+
+	assert.Equal(t, "***.***.***-09", *masked.Documentation)
+	assert.Equal(t, "****-**-**", *masked.Birthday)
+	assert.Nil(t, masked.PhoneNumber)
+	assert.Equal(t, "maria@example.com", masked.Email)
+	assert.Equal(t, "123.456.789-09", *signatory.Documentation)
+	assert.Equal(t, "1990-01-15", *signatory.Birthday)
+}
+
+// statementValues resolve os valores que o serializer gravaria em cada coluna
+func statementValues(t *testing.T, stmt *gorm.Statement) []interface{} {
+	values := make([]interface{}, len(stmt.Vars))
+	for i, v := range stmt.Vars {
+		values[i] = v
+		if valuer, ok := v.(driver.Valuer); ok {
+			value, err := valuer.Value()
+			require.NoError(t, err)
+			values[i] = value
+		}
+	}
+	return values
+}
+
+func TestEncryptedSerializer(t *testing.T) {
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	require.NoError(t, err)
+
+	documentation := "123.456.789-09"
+	phone := "11999998888"
+	newSignatory := func() *EntitySignatory {
+		doc, ph := documentation, phone
+		return &EntitySignatory{Name: "Maria", Documentation: &doc, PhoneNumber: &ph}
+	}
+
+	t.Run("should store plaintext without a cipher", func(t *testing.T) {
+		withPIICipher(t, nil)
+		signatory := newSignatory()
+
+		values := statementValues(t, db.Create(signatory).Statement)
+
+		assert.Contains(t, values, documentation)
+		require.NotNil(t, signatory.DocumentationIndex)
+		assert.Equal(t, DocumentationIndex(documentation), *signatory.DocumentationIndex)
+	})
+
+	t.Run("should encrypt values and index the plaintext", func(t *testing.T) {
+		withPIICipher(t, fakePIICipher{})
+		signatory := newSignatory()
+
+		values := statementValues(t, db.Create(signatory).Statement)
+
+		assert.Contains(t, values, "enc:fake:signatories.documentation#0:"+documentation)
+		assert.Contains(t, values, "enc:fake:signatories.phone_number#0:"+phone)
+		assert.NotContains(t, values, documentation)
+		assert.Equal(t, "idx:12345678909", *signatory.DocumentationIndex)
+		assert.Equal(t, documentation, *signatory.Documentation)
+	})
+
+	t.Run("should bind new rows to the generated id", func(t *testing.T) {
+		withPIICipher(t, fakePIICipher{})
+		var rebound *gorm.Statement
+		capturing := db.Session(&gorm.Session{})
+		require.NoError(t, capturing.Callback().Update().After("gorm:update").Register("test:capture", func(tx *gorm.DB) {
+			rebound = tx.Statement
+		}))
+		t.Cleanup(func() { _ = capturing.Callback().Update().Remove("test:capture") })
+
+		signatory := newSignatory()
+		signatory.ID = 7
+		require.NoError(t, capturing.Create(signatory).Error)
+
+		require.NotNil(t, rebound)
+		assert.Contains(t, rebound.SQL.String(), "UPDATE `signatories` SET `birthday`=?,`documentation`=?,`phone_number`=?")
+		values := statementValues(t, rebound)
+		assert.Contains(t, values, "enc:fake:signatories.documentation#7:"+documentation)
+		assert.Contains(t, values, "enc:fake:signatories.phone_number#7:"+phone)
+	})
+
+	t.Run("should not decrypt values moved to another row or column", func(t *testing.T) {
+		withPIICipher(t, fakePIICipher{})
+		signatorySchema, err := schema.Parse(&EntitySignatory{}, &sync.Map{}, schema.NamingStrategy{})
+		require.NoError(t, err)
+		field := signatorySchema.LookUpField("documentation")
+
+		scan := func(id int, stored string) error {
+			return EncryptedSerializer{}.Scan(context.Background(), field, reflect.ValueOf(&EntitySignatory{ID: id}).Elem(), stored)
+		}
+
+		assert.NoError(t, scan(7, "enc:fake:signatories.documentation#7:"+documentation))
+		assert.Error(t, scan(8, "enc:fake:signatories.documentation#7:"+documentation))
+		assert.Error(t, scan(7, "enc:fake:signatories.phone_number#7:"+phone))
+	})
+
+	t.Run("should refuse encrypted values without a cipher", func(t *testing.T) {
+		withPIICipher(t, nil)
+
+		_, err := decryptPII("enc:v1:k1:a:b", "signatories.documentation#1")
+
+		assert.ErrorIs(t, err, ErrPIICipherNotConfigured)
+	})
+}
+
+func TestSecretSerializer(t *testing.T) {
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	require.NoError(t, err)
+
+	newCredential := func() *EntityProviderCredential {
+		return &EntityProviderCredential{TenantID: "acme", Provider: "clicksign", APIKey: "secret-api-key", Password: "secret-password"}
+	}
+
+	t.Run("should refuse to store secrets without a cipher", func(t *testing.T) {
+		withPIICipher(t, nil)
+
+		var err error
+		for _, v := range db.Create(newCredential()).Statement.Vars {
+			if valuer, ok := v.(driver.Valuer); ok {
+				if _, valueErr := valuer.Value(); valueErr != nil {
+					err = valueErr
+				}
+			}
+		}
+
+		assert.ErrorIs(t, err, ErrSecretCipherNotConfigured)
+	})
+
+	t.Run("should encrypt secrets", func(t *testing.T) {
+		withPIICipher(t, fakePIICipher{})
+
+		values := statementValues(t, db.Create(newCredential()).Statement)
+
+		assert.Contains(t, values, "enc:fake:provider_credentials.api_key#0:secret-api-key")
+		assert.Contains(t, values, "enc:fake:provider_credentials.password#0:secret-password")
+		assert.NotContains(t, values, "secret-api-key")
+		assert.NotContains(t, values, "secret-password")
+	})
+
+	t.Run("should refuse to read secrets without a cipher", func(t *testing.T) {
+		withPIICipher(t, nil)
+
+		_, err := decryptSecret("legacy-plaintext", "provider_credentials.api_key#1")
+
+		assert.ErrorIs(t, err, ErrSecretCipherNotConfigured)
+	})
+}
@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EntityProviderCredential representa as credenciais de um provider para um tenant
+// Permite que uma mesma instalação atenda várias unidades de negócio com contas distintas no provider
+type EntityProviderCredential struct {
+	ID         int    `json:"id" gorm:"primaryKey"`
+	TenantID   string `json:"tenant_id" gorm:"not null;uniqueIndex:idx_provider_credentials_tenant_provider" validate:"required,max=100"`
+	Provider   string `json:"provider" gorm:"not null;uniqueIndex:idx_provider_credentials_tenant_provider" validate:"required,max=50"`
+	APIKey     string `json:"-" gorm:"column:api_key;serializer:secret"`
+	BaseURL    string `json:"base_url" validate:"omitempty,url"`
+	Login      string `json:"login"`
+	Password   string `json:"-" gorm:"serializer:secret"`
+	Active     bool   `json:"active"`
+	AccountKey string `json:"account_key,omitempty" gorm:"index"` // Conta no provider (account_key dos webhooks), usada para rotear os webhooks ao tenant
+	// Segredo HMAC dos webhooks da conta; sem ele os webhooks da conta são rejeitados
+	WebhookSecret string    `json:"-" gorm:"serializer:secret"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// TableName sets the table name for GORM
+func (EntityProviderCredential) TableName() string {
+	return "provider_credentials"
+}
+
+// AfterCreate amarra os segredos cifrados ao id gerado na inserção
+func (c *EntityProviderCredential) AfterCreate(tx *gorm.DB) error {
+	return bindEncryptedFields(tx, c)
+}
+
+func NewProviderCredential(credentialParam EntityProviderCredential) (*EntityProviderCredential, error) {
+	now := time.Now()
+
+	credential := &EntityProviderCredential{
+		TenantID:      strings.TrimSpace(credentialParam.TenantID),
+		Provider:      strings.TrimSpace(credentialParam.Provider),
+		APIKey:        credentialParam.APIKey,
+		BaseURL:       strings.TrimSpace(credentialParam.BaseURL),
+		Login:         strings.TrimSpace(credentialParam.Login),
+		Password:      credentialParam.Password,
+		Active:        credentialParam.Active,
+		AccountKey:    strings.TrimSpace(credentialParam.AccountKey),
+		WebhookSecret: credentialParam.WebhookSecret,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	err := credential.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return credential, nil
+}
+
+func (c *EntityProviderCredential) Validate() error {
+	return validate.Struct(c)
+}
@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrAccountLocked indica o login bloqueado temporariamente depois de falhas seguidas
+var ErrAccountLocked = errors.New("too many failed login attempts; try again later")
+
+// EntityRateLimitCounter é a janela de contagem de uma chave de rate limit quando o estado fica no Postgres
+// A chave identifica o grupo de rotas e o cliente (IP, usuário ou API key), ou a conta no bloqueio de login
+type EntityRateLimitCounter struct {
+	Key       string    `json:"key" gorm:"primaryKey;size:255"`
+	Count     int       `json:"count" gorm:"not null"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index"`
+}
+
+// TableName sets the table name for GORM
+func (EntityRateLimitCounter) TableName() string {
+	return "rate_limit_counters"
+}
@@ -16,6 +16,7 @@ type EntityRequirement struct {
 	DocumentID   *string   `json:"document_id" gorm:"index"`
 	SignerID     *string   `json:"signer_id" gorm:"index"`
 	Status       string    `json:"status" gorm:"not null;default:'pending'" validate:"required,oneof=pending completed"`
+	TenantID     string    `json:"tenant_id,omitempty" gorm:"not null;default:'';index"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
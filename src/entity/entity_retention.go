@@ -0,0 +1,152 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// Regras de retenção aplicadas pelo expurgo
+const (
+	// RetentionRuleEnvelopeRawData remove o ClicksignRawData dos envelopes
+	RetentionRuleEnvelopeRawData = "envelope_raw_data"
+	// RetentionRuleWebhookPayload remove RawPayload e EventData dos webhooks já processados
+	RetentionRuleWebhookPayload = "webhook_payload"
+	// RetentionRuleSignerPII anonimiza CPF, data de nascimento e telefone dos signatários de envelopes finalizados
+	RetentionRuleSignerPII = "signer_pii"
+	// RetentionRuleAutoSignatureTermPII anonimiza CPF e data de nascimento dos termos de assinatura automática sem uso
+	RetentionRuleAutoSignatureTermPII = "auto_signature_term_pii"
+	// RetentionRuleDocumentFiles apaga do storage os arquivos dos documentos de envelopes finalizados
+	RetentionRuleDocumentFiles = "document_files"
+)
+
+// Status de uma execução do expurgo
+const (
+	RetentionRunRunning   = "running"
+	RetentionRunCompleted = "completed"
+	RetentionRunFailed    = "failed"
+)
+
+// Origem de uma execução do expurgo
+const (
+	RetentionTriggerCron   = "cron"
+	RetentionTriggerManual = "manual"
+)
+
+// RetentionPolicy define, por tipo de dado, após quantos dias ele é expurgado; 0 mantém para sempre
+// Os prazos de signer_pii e document_files contam a partir da finalização do envelope
+// auto_signature_term_pii usa o prazo de signer_pii contado a partir da última alteração do termo
+type RetentionPolicy struct {
+	EnvelopeRawDataDays int `json:"envelope_raw_data_days"`
+	WebhookPayloadDays  int `json:"webhook_payload_days"`
+	SignerPIIDays       int `json:"signer_pii_days"`
+	DocumentFileDays    int `json:"document_file_days"`
+}
+
+// Days retorna o prazo configurado para a regra
+func (p RetentionPolicy) Days(rule string) int {
+	switch rule {
+	case RetentionRuleEnvelopeRawData:
+		return p.EnvelopeRawDataDays
+	case RetentionRuleWebhookPayload:
+		return p.WebhookPayloadDays
+	case RetentionRuleSignerPII, RetentionRuleAutoSignatureTermPII:
+		return p.SignerPIIDays
+	case RetentionRuleDocumentFiles:
+		return p.DocumentFileDays
+	default:
+		return 0
+	}
+}
+
+// Cutoff retorna a data limite da regra: registros anteriores a ela são expurgados
+// Retorna false quando a regra está desabilitada
+func (p RetentionPolicy) Cutoff(rule string, now time.Time) (time.Time, bool) {
+	days := p.Days(rule)
+	if days <= 0 {
+		return time.Time{}, false
+	}
+	return now.AddDate(0, 0, -days), true
+}
+
+// RetentionRunItem é o resultado de uma regra em uma execução
+// IDs são os registros expurgados (ou que seriam, em dry-run); Files conta os arquivos apagados do storage
+type RetentionRunItem struct {
+	Rule   string    `json:"rule"`
+	Cutoff time.Time `json:"cutoff"`
+	Count  int       `json:"count"`
+	IDs    []int     `json:"ids"`
+	Files  int       `json:"files,omitempty"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// EntityRetentionRun registra uma execução do expurgo e o que foi expurgado
+type EntityRetentionRun struct {
+	ID         int                `json:"id" gorm:"primaryKey"`
+	Trigger    string             `json:"trigger" gorm:"not null"`
+	DryRun     bool               `json:"dry_run"`
+	Status     string             `json:"status" gorm:"not null;index"`
+	Policy     RetentionPolicy    `json:"policy" gorm:"serializer:json"`
+	Items      []RetentionRunItem `json:"items" gorm:"serializer:json"`
+	Error      string             `json:"error,omitempty" gorm:"type:text"`
+	StartedAt  time.Time          `json:"started_at"`
+	FinishedAt *time.Time         `json:"finished_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+}
+
+// TableName sets the table name for GORM
+func (EntityRetentionRun) TableName() string {
+	return "retention_runs"
+}
+
+// NewRetentionRun inicia o registro de uma execução
+func NewRetentionRun(trigger string, dryRun bool, policy RetentionPolicy) *EntityRetentionRun {
+	now := time.Now()
+	return &EntityRetentionRun{
+		Trigger:   trigger,
+		DryRun:    dryRun,
+		Status:    RetentionRunRunning,
+		Policy:    policy,
+		Items:     []RetentionRunItem{},
+		StartedAt: now,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// AddItem registra o resultado de uma regra
+func (r *EntityRetentionRun) AddItem(item RetentionRunItem) {
+	if item.IDs == nil {
+		item.IDs = []int{}
+	}
+	item.Count = len(item.IDs)
+	r.Items = append(r.Items, item)
+}
+
+// Finish encerra a execução; falha se alguma regra falhou
+func (r *EntityRetentionRun) Finish() {
+	now := time.Now()
+	r.FinishedAt = &now
+	r.UpdatedAt = now
+	r.Status = RetentionRunCompleted
+
+	var failures []string
+	for _, item := range r.Items {
+		if item.Error != "" {
+			failures = append(failures, item.Rule+": "+item.Error)
+		}
+	}
+	if len(failures) > 0 {
+		r.Status = RetentionRunFailed
+		r.Error = strings.Join(failures, "; ")
+	}
+}
+
+// Total retorna quantos registros foram expurgados somando todas as regras
+func (r *EntityRetentionRun) Total() int {
+	total := 0
+	for _, item := range r.Items {
+		total += item.Count
+	}
+	return total
+}
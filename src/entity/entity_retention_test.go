@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/datatypes"
+)
+
+func TestRetentionPolicy_Cutoff(t *testing.T) {
+	policy := RetentionPolicy{EnvelopeRawDataDays: 90, SignerPIIDays: 0}
+	now := time.Date(2026, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	cutoff, enabled := policy.Cutoff(RetentionRuleEnvelopeRawData, now)
+	assert.True(t, enabled)
+	assert.Equal(t, time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC), cutoff)
+
+	_, enabled = policy.Cutoff(RetentionRuleSignerPII, now)
+	assert.False(t, enabled)
+
+	_, enabled = policy.Cutoff("unknown", now)
+	assert.False(t, enabled)
+}
+
+func TestEntityRetentionRun_Finish(t *testing.T) {
+	t.Run("should complete and count the purged records", func(t *testing.T) {
+		run := NewRetentionRun(RetentionTriggerCron, false, RetentionPolicy{})
+		run.AddItem(RetentionRunItem{Rule: RetentionRuleEnvelopeRawData, IDs: []int{1, 2}})
+		run.AddItem(RetentionRunItem{Rule: RetentionRuleWebhookPayload})
+
+		run.Finish()
+
+		assert.Equal(t, RetentionRunCompleted, run.Status)
+		assert.NotNil(t, run.FinishedAt)
+		assert.Equal(t, 2, run.Total())
+		assert.Equal(t, []int{}, run.Items[1].IDs)
+	})
+
+	t.Run("should fail when a rule failed", func(t *testing.T) {
+		run := NewRetentionRun(RetentionTriggerManual, true, RetentionPolicy{})
+		run.AddItem(RetentionRunItem{Rule: RetentionRuleSignerPII, Error: "connection refused"})
+
+		run.Finish()
+
+		assert.Equal(t, RetentionRunFailed, run.Status)
+		assert.Equal(t, "signer_pii: connection refused", run.Error)
+	})
+}
+
+func TestEntityDocument_StorageKeys(t *testing.T) {
+	document := &EntityDocument{
+		StorageKey:       "originals/sha256/aa",
+		SignedStorageKey: "signed/sha256/bb",
+		Metadata: datatypes.JSON(`{
+			"conversion": {"source_storage_key": "originals/sha256/cc"},
+			"normalization": {"sources": [{"storage_key": "originals/sha256/dd"}, {"storage_key": "originals/sha256/aa"}]}
+		}`),
+	}
+
+	assert.Equal(t, []string{
+		"originals/sha256/aa",
+		"signed/sha256/bb",
+		"originals/sha256/cc",
+		"originals/sha256/dd",
+	}, document.StorageKeys())
+
+	assert.Empty(t, (&EntityDocumentVersion{}).StorageKeys())
+}
@@ -0,0 +1,136 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Papéis atribuídos aos usuários; cada papel concede um conjunto fixo de permissões
+const (
+	RoleViewer   = "viewer"
+	RoleOperator = "operator"
+	RoleApprover = "approver"
+	RoleAdmin    = "admin"
+)
+
+// Roles lista os papéis aceitos no cadastro de usuários
+var Roles = []string{RoleViewer, RoleOperator, RoleApprover, RoleAdmin}
+
+// DefaultRole é o papel dos usuários criados pela API ou pelo provedor OIDC sem papel informado
+const DefaultRole = RoleOperator
+
+// LegacyRole é o papel efetivo dos usuários cadastrados antes dos papéis, que mantêm o acesso que já tinham
+const LegacyRole = RoleApprover
+
+// Permissões de usuário; os escopos de API key também valem como permissões
+const (
+	PermissionEnvelopesActivate = "envelopes:activate"
+	PermissionEnvelopesApprove  = "envelopes:approve"  // Decide os envelopes pendentes de aprovação criados por outros usuários
+	PermissionEnvelopesReadAll  = "envelopes:read_all" // Sem ela, o usuário vê apenas os envelopes que criou
+	PermissionUsersAdmin        = "users:admin"
+)
+
+// permissionScopes é o escopo que a API key precisa ter para exercer cada permissão que não é, ela mesma, um escopo
+// envelopes:approve, envelopes:read_all e users:admin não têm escopo: aprovações são sempre de um usuário,
+// API keys já veem todo o tenant e não gerenciam usuários
+var permissionScopes = map[string]string{
+	PermissionEnvelopesActivate: ScopeEnvelopesActivate,
+}
+
+var (
+	ErrUnknownRole       = errors.New("unknown role")
+	ErrUnknownPermission = errors.New("unknown permission")
+	ErrPermissionDenied  = errors.New("permission denied")
+)
+
+var viewerPermissions = []string{
+	ScopeDocumentsRead,
+	ScopeEnvelopesRead,
+	ScopeTemplatesRead,
+	ScopeTermsRead,
+	ScopeProvidersRead,
+	PermissionEnvelopesReadAll,
+}
+
+var operatorPermissions = []string{
+	ScopeDocumentsRead,
+	ScopeDocumentsWrite,
+	ScopeEnvelopesRead,
+	ScopeEnvelopesWrite,
+	ScopeTemplatesRead,
+	ScopeTemplatesWrite,
+	ScopeTermsRead,
+	ScopeTermsWrite,
+	ScopeProvidersRead,
+}
+
+var approverPermissions = append(append([]string{}, operatorPermissions...),
+	PermissionEnvelopesActivate,
+	PermissionEnvelopesApprove,
+	PermissionEnvelopesReadAll,
+)
+
+// Permissions lista as permissões que podem ser concedidas individualmente aos usuários
+// envelopes:activate já faz parte de APIScopes
+var Permissions = append(append([]string{}, APIScopes...),
+	PermissionEnvelopesApprove,
+	PermissionEnvelopesReadAll,
+	PermissionUsersAdmin,
+)
+
+// APIKeyScope retorna o escopo exigido das API keys para permission
+// ok é false quando a permissão não pode ser exercida por API keys
+func APIKeyScope(permission string) (scope string, ok bool) {
+	if scope, ok := permissionScopes[permission]; ok {
+		return scope, true
+	}
+	if isKnownScope(permission) {
+		return permission, true
+	}
+	return "", false
+}
+
+// RolePermissions retorna as permissões concedidas pelo papel; admin recebe todas
+func RolePermissions(role string) []string {
+	switch role {
+	case RoleViewer:
+		return viewerPermissions
+	case RoleOperator:
+		return operatorPermissions
+	case RoleApprover:
+		return approverPermissions
+	case RoleAdmin:
+		return Permissions
+	}
+	return nil
+}
+
+func isKnownRole(role string) bool {
+	for _, known := range Roles {
+		if known == role {
+			return true
+		}
+	}
+	return false
+}
+
+func isKnownPermission(permission string) bool {
+	for _, known := range Permissions {
+		if known == permission {
+			return true
+		}
+	}
+	return false
+}
+
+func validateRoleAndPermissions(role string, permissions []string) error {
+	if role != "" && !isKnownRole(role) {
+		return fmt.Errorf("%w: %s", ErrUnknownRole, role)
+	}
+	for _, permission := range permissions {
+		if !isKnownPermission(permission) {
+			return fmt.Errorf("%w: %s", ErrUnknownPermission, permission)
+		}
+	}
+	return nil
+}
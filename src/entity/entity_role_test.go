@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntityUser_HasPermission(t *testing.T) {
+	viewer := EntityUser{Role: RoleViewer}
+	operator := EntityUser{Role: RoleOperator}
+	approver := EntityUser{Role: RoleApprover}
+	admin := EntityUser{IsAdmin: true}
+	legacy := EntityUser{}
+
+	assert.True(t, viewer.HasPermission(ScopeEnvelopesRead))
+	assert.True(t, viewer.HasPermission(PermissionEnvelopesReadAll))
+	assert.False(t, viewer.HasPermission(ScopeEnvelopesWrite))
+
+	assert.True(t, operator.HasPermission(ScopeEnvelopesWrite))
+	assert.False(t, operator.HasPermission(PermissionEnvelopesActivate))
+	assert.False(t, operator.HasPermission(PermissionEnvelopesReadAll))
+
+	assert.True(t, approver.HasPermission(PermissionEnvelopesActivate))
+	assert.False(t, approver.HasPermission(ScopeWebhooksAdmin))
+	assert.False(t, approver.HasPermission(PermissionUsersAdmin))
+
+	for _, permission := range Permissions {
+		assert.True(t, admin.HasPermission(permission), permission)
+	}
+
+	assert.Equal(t, LegacyRole, legacy.EffectiveRole())
+	assert.True(t, legacy.HasPermission(PermissionEnvelopesActivate))
+
+	operator.Permissions = []string{PermissionEnvelopesReadAll}
+	assert.True(t, operator.HasPermission(PermissionEnvelopesReadAll))
+}
+
+func TestEntityUser_NormalizeRole(t *testing.T) {
+	user := EntityUser{IsAdmin: true}
+	user.NormalizeRole()
+	assert.Equal(t, RoleAdmin, user.Role)
+
+	user = EntityUser{IsAdmin: true, Role: RoleOperator}
+	user.NormalizeRole()
+	assert.False(t, user.IsAdmin)
+
+	user = EntityUser{Role: RoleAdmin}
+	user.NormalizeRole()
+	assert.True(t, user.IsAdmin)
+
+	user.SetAdmin(false)
+	assert.Equal(t, DefaultRole, user.Role)
+	assert.False(t, user.IsAdmin)
+}
+
+func TestEntityUser_CanGrant(t *testing.T) {
+	admin := EntityUser{Role: RoleAdmin, IsAdmin: true}
+	delegated := EntityUser{Role: RoleOperator, Permissions: []string{PermissionUsersAdmin}}
+
+	assert.True(t, admin.CanGrant(&EntityUser{Role: RoleAdmin}))
+	assert.True(t, delegated.CanGrant(&EntityUser{Role: RoleOperator}))
+	assert.True(t, delegated.CanGrant(&EntityUser{}))
+	assert.False(t, delegated.CanGrant(&EntityUser{Role: RoleApprover}))
+	assert.False(t, delegated.CanGrant(&EntityUser{IsAdmin: true}))
+	assert.False(t, delegated.CanGrant(&EntityUser{Role: RoleOperator, Permissions: []string{ScopeWebhooksAdmin}}))
+}
@@ -7,6 +7,8 @@ import (
 	"net/mail"
 	"regexp"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type EntitySignatoryFilters struct {
@@ -14,6 +16,8 @@ type EntitySignatoryFilters struct {
 	EnvelopeID int    `json:"envelope_id"`
 	Email      string `json:"email"`
 	Name       string `json:"name"`
+	// Documentation busca pelo índice cego, pois o documento é gravado cifrado
+	Documentation string `json:"documentation"`
 }
 
 type CommunicateEvents struct {
@@ -23,20 +27,23 @@ type CommunicateEvents struct {
 }
 
 type EntitySignatory struct {
-	ID                int                `json:"id" gorm:"primaryKey"`
-	Name              string             `json:"name" gorm:"not null" validate:"required,min=2,max=255"`
-	Email             string             `json:"email" gorm:"not null" validate:"required,email"`
-	EnvelopeID        int                `json:"envelope_id" gorm:"not null" validate:"required"`
-	Birthday          *string            `json:"birthday,omitempty"`
-	Documentation     *string            `json:"documentation,omitempty"`
-	PhoneNumber       *string            `json:"phone_number,omitempty"`
-	HasDocumentation  *bool              `json:"has_documentation,omitempty"`
-	Refusable         *bool              `json:"refusable,omitempty"`
-	Group             *int               `json:"group,omitempty"`
-	CommunicateEvents *CommunicateEvents `json:"communicate_events,omitempty" gorm:"serializer:json"`
-	ClicksignKey      string             `json:"clicksign_key,omitempty" gorm:"column:clicksign_key"`
-	CreatedAt         time.Time          `json:"created_at"`
-	UpdatedAt         time.Time          `json:"updated_at"`
+	ID                 int                `json:"id" gorm:"primaryKey"`
+	Name               string             `json:"name" gorm:"not null" validate:"required,min=2,max=255"`
+	Email              string             `json:"email" gorm:"not null" validate:"required,email"`
+	EnvelopeID         int                `json:"envelope_id" gorm:"not null" validate:"required"`
+	Birthday           *string            `json:"birthday,omitempty" gorm:"serializer:encrypted"`
+	Documentation      *string            `json:"documentation,omitempty" gorm:"serializer:encrypted"`
+	PhoneNumber        *string            `json:"phone_number,omitempty" gorm:"serializer:encrypted"`
+	DocumentationIndex *string            `json:"-" gorm:"size:64;index"` // Blind index do documento cifrado, usado nas buscas por CPF/CNPJ
+	HasDocumentation   *bool              `json:"has_documentation,omitempty"`
+	Refusable          *bool              `json:"refusable,omitempty"`
+	Group              *int               `json:"group,omitempty"`
+	CommunicateEvents  *CommunicateEvents `json:"communicate_events,omitempty" gorm:"serializer:json"`
+	ClicksignKey       string             `json:"clicksign_key,omitempty" gorm:"column:clicksign_key"`
+	AnonymizedAt       *time.Time         `json:"anonymized_at,omitempty"` // CPF, nascimento e telefone removidos pela política de retenção
+	TenantID           string             `json:"tenant_id,omitempty" gorm:"not null;default:'';index"`
+	CreatedAt          time.Time          `json:"created_at"`
+	UpdatedAt          time.Time          `json:"updated_at"`
 }
 
 // TableName sets the table name for GORM
@@ -44,6 +51,35 @@ func (EntitySignatory) TableName() string {
 	return "signatories"
 }
 
+// BeforeSave atualiza o blind index do documento antes de cifrá-lo
+func (s *EntitySignatory) BeforeSave(tx *gorm.DB) error {
+	s.IndexPII()
+	return nil
+}
+
+// AfterCreate amarra os dados cifrados ao id gerado na inserção
+func (s *EntitySignatory) AfterCreate(tx *gorm.DB) error {
+	return bindEncryptedFields(tx, s)
+}
+
+// IndexPII recalcula o blind index a partir do documento em claro
+func (s *EntitySignatory) IndexPII() {
+	s.DocumentationIndex = nil
+	if s.Documentation != nil {
+		if index := DocumentationIndex(*s.Documentation); index != "" {
+			s.DocumentationIndex = &index
+		}
+	}
+}
+
+// Masked retorna uma cópia com CPF/CNPJ, nascimento e telefone mascarados, para listagens e logs
+func (s EntitySignatory) Masked() EntitySignatory {
+	s.Documentation = maskOptional(s.Documentation, MaskDocumentation)
+	s.Birthday = maskOptional(s.Birthday, MaskBirthday)
+	s.PhoneNumber = maskOptional(s.PhoneNumber, MaskPhoneNumber)
+	return s
+}
+
 func NewSignatory(signatoryParam EntitySignatory) (*EntitySignatory, error) {
 	now := time.Now()
 
@@ -140,13 +176,13 @@ func (s *EntitySignatory) validateBirthday() error {
 		// Validate YYYY-MM-DD format
 		birthdayRegex := regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
 		if !birthdayRegex.MatchString(*s.Birthday) {
-			return fmt.Errorf("birthday must be in YYYY-MM-DD format, got: %s", *s.Birthday)
+			return fmt.Errorf("birthday must be in YYYY-MM-DD format, got: %s", MaskBirthday(*s.Birthday))
 		}
 
 		// Validate that it's a valid date
 		_, err := time.Parse("2006-01-02", *s.Birthday)
 		if err != nil {
-			return fmt.Errorf("invalid birthday date: %s", *s.Birthday)
+			return fmt.Errorf("invalid birthday date: %s", MaskBirthday(*s.Birthday))
 		}
 	}
 	return nil
@@ -157,7 +193,7 @@ func (s *EntitySignatory) validatePhoneNumber() error {
 		// Basic international phone number validation (starts with + and contains digits)
 		phoneRegex := regexp.MustCompile(`^\+\d{8,15}$`)
 		if !phoneRegex.MatchString(*s.PhoneNumber) {
-			return fmt.Errorf("phone number must be in international format (+xxxxxxxx), got: %s", *s.PhoneNumber)
+			return fmt.Errorf("phone number must be in international format (+xxxxxxxx), got: %s", MaskPhoneNumber(*s.PhoneNumber))
 		}
 	}
 	return nil
@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidTenantID = errors.New("tenant id must contain only lowercase letters, digits and hyphens")
+	ErrTenantNotFound  = errors.New("tenant not found")
+	ErrTenantExists    = errors.New("tenant already exists")
+	ErrTenantInactive  = errors.New("tenant inactive")
+	ErrTenantForbidden = errors.New("access to tenant not allowed")
+)
+
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// EntityTenant é a organização dona dos envelopes, documentos e webhooks
+// O ID é um slug legível, o mesmo usado em provider_credentials e no header X-Tenant-ID
+type EntityTenant struct {
+	ID        string    `json:"id" gorm:"primaryKey;size:100" validate:"required,max=100"`
+	Name      string    `json:"name" gorm:"not null" validate:"required,min=3,max=255"`
+	Active    bool      `json:"active" gorm:"default:true"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName sets the table name for GORM
+func (EntityTenant) TableName() string {
+	return "tenants"
+}
+
+func NewTenant(tenantParam EntityTenant) (*EntityTenant, error) {
+	now := time.Now()
+
+	tenant := &EntityTenant{
+		ID:        strings.ToLower(strings.TrimSpace(tenantParam.ID)),
+		Name:      strings.TrimSpace(tenantParam.Name),
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := tenant.Validate(); err != nil {
+		return nil, err
+	}
+
+	return tenant, nil
+}
+
+func (t *EntityTenant) Validate() error {
+	if err := validate.Struct(t); err != nil {
+		return err
+	}
+	if !tenantIDPattern.MatchString(t.ID) {
+		return ErrInvalidTenantID
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTenant(t *testing.T) {
+	t.Run("should normalize the id and activate the tenant", func(t *testing.T) {
+		tenant, err := NewTenant(EntityTenant{ID: "  Acme-Brasil ", Name: " Acme Brasil "})
+
+		require.NoError(t, err)
+		assert.Equal(t, "acme-brasil", tenant.ID)
+		assert.Equal(t, "Acme Brasil", tenant.Name)
+		assert.True(t, tenant.Active)
+		assert.False(t, tenant.CreatedAt.IsZero())
+	})
+
+	t.Run("should reject ids that are not slugs", func(t *testing.T) {
+		for _, id := range []string{"acme brasil", "-acme", "acme_brasil", "acmé"} {
+			_, err := NewTenant(EntityTenant{ID: id, Name: "Acme"})
+			assert.ErrorIs(t, err, ErrInvalidTenantID, id)
+		}
+	})
+
+	t.Run("should require id and name", func(t *testing.T) {
+		_, err := NewTenant(EntityTenant{Name: "Acme"})
+		assert.Error(t, err)
+
+		_, err = NewTenant(EntityTenant{ID: "acme", Name: "A"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,85 @@
+package entity
+
+import (
+	"crypto/subtle"
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenExpired = errors.New("refresh token expired")
+	ErrRefreshTokenRevoked = errors.New("refresh token revoked")
+	// ErrRefreshTokenReused indica que um refresh token já rotacionado foi apresentado de novo; a família inteira é revogada
+	ErrRefreshTokenReused = errors.New("refresh token reused")
+	ErrTokenRevoked       = errors.New("token revoked")
+)
+
+// EntityRefreshToken é um refresh token opaco; apenas o hash é persistido
+// Cada rotação cria um token novo na mesma família, o que permite detectar reuso de um token antigo
+type EntityRefreshToken struct {
+	ID        int        `json:"id" gorm:"primaryKey"`
+	UserID    int        `json:"user_id" gorm:"not null;index"`
+	FamilyID  string     `json:"family_id" gorm:"not null;index"`
+	TokenHash string     `json:"-" gorm:"not null;uniqueIndex"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null;index"`
+	RotatedAt *time.Time `json:"rotated_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (EntityRefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// EntityRevokedToken registra o jti de um access token revogado no logout até a sua expiração natural
+type EntityRevokedToken struct {
+	JTI       string    `json:"jti" gorm:"primaryKey"`
+	UserID    int       `json:"user_id" gorm:"index"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+func (EntityRevokedToken) TableName() string {
+	return "revoked_tokens"
+}
+
+// NewRefreshToken emite um refresh token da família e retorna também o valor em claro, que não é persistido
+func NewRefreshToken(userID int, familyID string, ttl time.Duration, now time.Time) (*EntityRefreshToken, string, error) {
+	rawToken, err := randomHex(32)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &EntityRefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: HashRefreshToken(rawToken),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}, rawToken, nil
+}
+
+// HashRefreshToken calcula o hash usado para localizar o token
+func HashRefreshToken(rawToken string) string {
+	return hashSecret(rawToken)
+}
+
+// Matches compara o token informado com o hash em tempo constante
+func (t *EntityRefreshToken) Matches(rawToken string) bool {
+	return subtle.ConstantTimeCompare([]byte(hashSecret(rawToken)), []byte(t.TokenHash)) == 1
+}
+
+// CheckUsable retorna o motivo pelo qual o token não pode ser trocado
+func (t *EntityRefreshToken) CheckUsable(now time.Time) error {
+	if t.RevokedAt != nil {
+		return ErrRefreshTokenRevoked
+	}
+	if t.RotatedAt != nil {
+		return ErrRefreshTokenReused
+	}
+	if !now.Before(t.ExpiresAt) {
+		return ErrRefreshTokenExpired
+	}
+	return nil
+}
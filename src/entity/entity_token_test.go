@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRefreshToken(t *testing.T) {
+	now := time.Date(2026, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	token, rawToken, err := NewRefreshToken(1, "family", time.Hour, now)
+	require.NoError(t, err)
+
+	assert.Len(t, rawToken, 64)
+	assert.Equal(t, HashRefreshToken(rawToken), token.TokenHash)
+	assert.NotContains(t, token.TokenHash, rawToken)
+	assert.True(t, token.Matches(rawToken))
+	assert.False(t, token.Matches(rawToken+"0"))
+	assert.Equal(t, now.Add(time.Hour), token.ExpiresAt)
+
+	assert.NoError(t, token.CheckUsable(now))
+	assert.ErrorIs(t, token.CheckUsable(now.Add(time.Hour)), ErrRefreshTokenExpired)
+
+	rotatedAt := now.Add(time.Minute)
+	token.RotatedAt = &rotatedAt
+	assert.ErrorIs(t, token.CheckUsable(now), ErrRefreshTokenReused)
+
+	token.RevokedAt = &rotatedAt
+	assert.ErrorIs(t, token.CheckUsable(now), ErrRefreshTokenRevoked)
+}
@@ -3,19 +3,9 @@ package entity
 import (
 	"time"
 
-	"github.com/golang-jwt/jwt"
 	"golang.org/x/crypto/bcrypt"
 )
 
-const SECRET_KEY = "9an0afx$thw)k9#y*_d9-ch^r&a6ndi#x#dwu^52zbqw=hso(9"
-
-type SignedDetails struct {
-	ID    int
-	Name  string
-	Email string
-	jwt.StandardClaims
-}
-
 type EntityUserFilters struct {
 	IDs    []uint `json:"ids"`
 	Search string `json:"search"`
@@ -31,6 +21,16 @@ type EntityUser struct {
 	Active    bool      `json:"active" gorm:"default:true"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// ExternalID vincula o usuário a uma identidade do provedor OIDC (issuer|subject)
+	ExternalID string `json:"external_id,omitempty" gorm:"index"`
+	// TenantID vazio identifica os administradores da plataforma, que enxergam todos os tenants
+	TenantID string `json:"tenant_id,omitempty" gorm:"not null;default:'';index"`
+	// Role define as permissões do usuário; vazio mantém o acesso anterior aos papéis (LegacyRole)
+	Role string `json:"role,omitempty" gorm:"size:20"`
+	// Permissions concede permissões além das do papel, como envelopes:read_all para um operador
+	Permissions []string `json:"permissions,omitempty" gorm:"serializer:json"`
+	// TokensRevokedAt invalida os access tokens emitidos até esse instante (logout de todas as sessões)
+	TokensRevokedAt *time.Time `json:"-"`
 }
 
 func NewUser(userParam EntityUser) (*EntityUser, error) {
@@ -51,13 +51,16 @@ func NewUser(userParam EntityUser) (*EntityUser, error) {
 	}
 
 	u := &EntityUser{
-		Name:      userParam.Name,
-		Email:     userParam.Email,
-		Password:  password,
-		IsAdmin:   userParam.IsAdmin,
-		Active:    userParam.Active,
-		CreatedAt: now,
-		UpdatedAt: now,
+		Name:        userParam.Name,
+		Email:       userParam.Email,
+		Password:    password,
+		IsAdmin:     userParam.IsAdmin,
+		Active:      userParam.Active,
+		TenantID:    userParam.TenantID,
+		Role:        userParam.Role,
+		Permissions: userParam.Permissions,
+		CreatedAt:   now,
+		UpdatedAt:   now,
 	}
 
 	return u, nil
@@ -74,93 +77,110 @@ func (u *EntityUser) ValidatePassword(p string) error {
 }
 
 func (u *EntityUser) Validate() error {
-	return validate.Struct(u)
-}
-
-func (u *EntityUser) UpdatePassword(newPassword string) error {
-	hash, err := GeneratePassword(newPassword)
-	if err != nil {
+	if err := validate.Struct(u); err != nil {
 		return err
 	}
+	return u.ValidateRole()
+}
 
-	u.Password = hash
-
-	return nil
+// ValidateRole verifica apenas o papel e as permissões, para atualizações que não revalidam o restante do cadastro
+func (u *EntityUser) ValidateRole() error {
+	return validateRoleAndPermissions(u.Role, u.Permissions)
 }
 
-func (u *EntityUser) GetValidated() error {
-	err := u.Validate()
-	if err != nil {
-		return err
+// EffectiveRole retorna o papel usado na autorização; IsAdmin prevalece para manter os administradores existentes
+func (u *EntityUser) EffectiveRole() string {
+	if u.IsAdmin {
+		return RoleAdmin
 	}
-
-	pwd, err := GeneratePassword(u.Password)
-	if err != nil {
-		return err
+	if u.Role == "" {
+		return LegacyRole
 	}
-	u.Password = pwd
-
-	return nil
+	return u.Role
 }
 
-func (u *EntityUser) JWTTokenGenerator() (signedToken string, signedRefreshToken string, err error) {
-
-	claims := SignedDetails{
-		ID:    u.ID,
-		Name:  u.Name,
-		Email: u.Email,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(time.Hour * 24).Unix(),
-		},
+// NormalizeRole mantém IsAdmin e Role coerentes: o papel informado prevalece e, sem ele, IsAdmin define admin
+func (u *EntityUser) NormalizeRole() {
+	if u.Role == "" && u.IsAdmin {
+		u.Role = RoleAdmin
 	}
-
-	refreshClaims := SignedDetails{
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(time.Hour * 24 * 7 * 365).Unix(),
-		},
+	if u.Role != "" {
+		u.IsAdmin = u.Role == RoleAdmin
 	}
+}
 
-	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(SECRET_KEY))
-
-	if err != nil {
-		return "", "", err
+// SetAdmin concede ou retira o papel de administrador
+func (u *EntityUser) SetAdmin(isAdmin bool) {
+	u.IsAdmin = isAdmin
+	switch {
+	case isAdmin:
+		u.Role = RoleAdmin
+	case u.Role == RoleAdmin:
+		u.Role = DefaultRole
 	}
+}
 
-	refreshToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims).SignedString([]byte(SECRET_KEY))
-
-	if err != nil {
-		return "", "", err
+// HasPermission informa se o papel ou as permissões individuais do usuário concedem permission
+func (u *EntityUser) HasPermission(permission string) bool {
+	for _, granted := range RolePermissions(u.EffectiveRole()) {
+		if granted == permission {
+			return true
+		}
+	}
+	for _, granted := range u.Permissions {
+		if granted == permission {
+			return true
+		}
 	}
+	return false
+}
 
-	return token, refreshToken, nil
+// CanGrant informa se o usuário pode atribuir o papel e as permissões de target: ninguém concede o que não tem
+// Sem papel, target recebe DefaultRole na criação ou mantém o papel atual na atualização
+func (u *EntityUser) CanGrant(target *EntityUser) bool {
+	role := target.Role
+	if role == "" {
+		role = DefaultRole
+	}
+	if target.IsAdmin {
+		role = RoleAdmin
+	}
+	return u.hasAllPermissions(RolePermissions(role)) && u.hasAllPermissions(target.Permissions)
 }
 
-func (u *EntityUser) ValidateToken(signedToken string) (claims *SignedDetails, err error) {
-	token, err := jwt.ParseWithClaims(
-		signedToken,
-		&SignedDetails{},
-		func(token *jwt.Token) (interface{}, error) {
-			return []byte(SECRET_KEY), nil
-		},
-	)
+func (u *EntityUser) hasAllPermissions(permissions []string) bool {
+	for _, permission := range permissions {
+		if !u.HasPermission(permission) {
+			return false
+		}
+	}
+	return true
+}
 
+func (u *EntityUser) UpdatePassword(newPassword string) error {
+	hash, err := GeneratePassword(newPassword)
 	if err != nil {
-
-		return nil, err
+		return err
 	}
 
-	claims, ok := token.Claims.(*SignedDetails)
-	if !ok {
+	u.Password = hash
 
-		return nil, err
-	}
+	return nil
+}
 
-	if claims.ExpiresAt < time.Now().Local().Unix() {
+func (u *EntityUser) GetValidated() error {
+	err := u.Validate()
+	if err != nil {
+		return err
+	}
 
-		return nil, err
+	pwd, err := GeneratePassword(u.Password)
+	if err != nil {
+		return err
 	}
+	u.Password = pwd
 
-	return claims, nil
+	return nil
 }
 
 func GeneratePassword(raw string) (string, error) {
@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+// Finalidades dos tokens de uso único enviados por email
+const (
+	UserTokenInvitation    = "invitation"
+	UserTokenPasswordReset = "password_reset"
+)
+
+var (
+	ErrInvalidUserToken = errors.New("invalid or expired token")
+	ErrUserTokenUsed    = errors.New("token already used")
+)
+
+// EntityUserToken é um token de uso único do convite ou da redefinição de senha; apenas o hash é persistido
+type EntityUserToken struct {
+	ID        int        `json:"id" gorm:"primaryKey"`
+	UserID    int        `json:"user_id" gorm:"not null;index"`
+	Purpose   string     `json:"purpose" gorm:"size:30;not null"`
+	TokenHash string     `json:"-" gorm:"not null;uniqueIndex"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null;index"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (EntityUserToken) TableName() string {
+	return "user_tokens"
+}
+
+// NewUserToken emite o token e retorna também o valor em claro, enviado ao usuário e não persistido
+func NewUserToken(userID int, purpose string, ttl time.Duration, now time.Time) (*EntityUserToken, string, error) {
+	rawToken, err := randomHex(32)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &EntityUserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: HashUserToken(rawToken),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}, rawToken, nil
+}
+
+// HashUserToken calcula o hash usado para localizar o token
+func HashUserToken(rawToken string) string {
+	return hashSecret(rawToken)
+}
+
+// CheckUsable retorna o motivo pelo qual o token não pode ser usado para purpose
+func (t *EntityUserToken) CheckUsable(purpose string, now time.Time) error {
+	if t.Purpose != purpose || !now.Before(t.ExpiresAt) {
+		return ErrInvalidUserToken
+	}
+	if t.UsedAt != nil {
+		return ErrUserTokenUsed
+	}
+	return nil
+}
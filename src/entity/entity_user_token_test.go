@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewUserToken(t *testing.T) {
+	now := time.Date(2026, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	token, rawToken, err := NewUserToken(1, UserTokenInvitation, time.Hour, now)
+	require.NoError(t, err)
+
+	assert.Len(t, rawToken, 64)
+	assert.Equal(t, HashUserToken(rawToken), token.TokenHash)
+	assert.NotContains(t, token.TokenHash, rawToken)
+	assert.Equal(t, now.Add(time.Hour), token.ExpiresAt)
+
+	assert.NoError(t, token.CheckUsable(UserTokenInvitation, now))
+	assert.ErrorIs(t, token.CheckUsable(UserTokenPasswordReset, now), ErrInvalidUserToken)
+	assert.ErrorIs(t, token.CheckUsable(UserTokenInvitation, now.Add(time.Hour)), ErrInvalidUserToken)
+
+	usedAt := now.Add(time.Minute)
+	token.UsedAt = &usedAt
+	assert.ErrorIs(t, token.CheckUsable(UserTokenInvitation, now), ErrUserTokenUsed)
+}
@@ -17,6 +17,7 @@ type EntityWebhook struct {
 	ProcessedAt *time.Time `json:"processed_at"`
 	Error       *string    `json:"error" gorm:"type:text"`
 	RawPayload  string     `json:"raw_payload" gorm:"type:text;not null"`
+	TenantID    string     `json:"tenant_id,omitempty" gorm:"not null;default:'';index"` // Resolvido pela conta do provider (AccountKey)
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
 }
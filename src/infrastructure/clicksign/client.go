@@ -4,12 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"time"
 
 	"app/config"
+	"app/infrastructure/provider"
 
 	"github.com/sirupsen/logrus"
 )
@@ -20,6 +22,9 @@ type ClicksignError struct {
 	Message    string
 	StatusCode int
 	Original   error
+
+	// priorAttemptMayHaveBeenProcessed indica que uma tentativa anterior teve resultado desconhecido
+	priorAttemptMayHaveBeenProcessed bool
 }
 
 func (e *ClicksignError) Error() string {
@@ -29,6 +34,27 @@ func (e *ClicksignError) Error() string {
 	return fmt.Sprintf("clicksign %s error: %s", e.Type, e.Message)
 }
 
+// Temporary indica se o erro representa indisponibilidade temporária da API
+func (e *ClicksignError) Temporary() bool {
+	switch e.Type {
+	case ErrorTypeNetwork, ErrorTypeTimeout, ErrorTypeServer:
+		return true
+	}
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+// NotProcessed indica se a API com certeza não processou a requisição: a conexão nem foi estabelecida
+// ou a API a recusou com 503/429. Timeouts e demais 5xx têm resultado desconhecido
+func (e *ClicksignError) NotProcessed() bool {
+	if e.priorAttemptMayHaveBeenProcessed {
+		return false
+	}
+	if e.StatusCode == http.StatusServiceUnavailable || e.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return e.StatusCode == 0 && e.Original != nil && provider.IsConnectionError(e.Original)
+}
+
 // Error types constants
 const (
 	ErrorTypeNetwork        = "network"
@@ -98,7 +124,9 @@ func (c *ClicksignClient) doRequest(ctx context.Context, method, endpoint string
 	}
 
 	// Implementar retry com backoff exponencial
+	// uncertain registra se alguma tentativa anterior pode ter sido processada, o que impede o failover
 	var lastErr error
+	uncertain := false
 	for attempt := 0; attempt <= c.retryAttempts; attempt++ {
 		if attempt > 0 {
 			// Backoff exponencial: 100ms, 200ms, 400ms, 800ms...
@@ -107,9 +135,10 @@ func (c *ClicksignClient) doRequest(ctx context.Context, method, endpoint string
 			select {
 			case <-ctx.Done():
 				return nil, &ClicksignError{
-					Type:     ErrorTypeTimeout,
-					Message:  "context cancelled during retry backoff",
-					Original: ctx.Err(),
+					Type:                             ErrorTypeTimeout,
+					Message:                          "context cancelled during retry backoff",
+					Original:                         ctx.Err(),
+					priorAttemptMayHaveBeenProcessed: uncertain,
 				}
 			case <-time.After(backoffDuration):
 				// Continue with retry
@@ -118,10 +147,11 @@ func (c *ClicksignClient) doRequest(ctx context.Context, method, endpoint string
 
 		resp, err := c.executeRequest(ctx, method, url, bodyBytes)
 		if err != nil {
-			lastErr = err
+			lastErr = markPriorAttempts(err, uncertain)
+			uncertain = uncertain || !provider.IsNotProcessedError(err)
 			// Verificar se deve tentar novamente
 			if !c.shouldRetry(err, attempt) {
-				return nil, err
+				return nil, lastErr
 			}
 			continue
 		}
@@ -130,10 +160,12 @@ func (c *ClicksignClient) doRequest(ctx context.Context, method, endpoint string
 		if resp.StatusCode >= 500 && attempt < c.retryAttempts {
 			resp.Body.Close() // Fechar o body antes de tentar novamente
 			lastErr = &ClicksignError{
-				Type:       ErrorTypeServer,
-				Message:    "server error - retrying",
-				StatusCode: resp.StatusCode,
+				Type:                             ErrorTypeServer,
+				Message:                          "server error - retrying",
+				StatusCode:                       resp.StatusCode,
+				priorAttemptMayHaveBeenProcessed: uncertain,
 			}
+			uncertain = uncertain || !provider.IsNotProcessedError(lastErr)
 			continue
 		}
 
@@ -141,9 +173,10 @@ func (c *ClicksignClient) doRequest(ctx context.Context, method, endpoint string
 		if resp.StatusCode >= 500 {
 			resp.Body.Close()
 			return nil, &ClicksignError{
-				Type:       ErrorTypeServer,
-				Message:    "server error - max retries exceeded",
-				StatusCode: resp.StatusCode,
+				Type:                             ErrorTypeServer,
+				Message:                          "server error - max retries exceeded",
+				StatusCode:                       resp.StatusCode,
+				priorAttemptMayHaveBeenProcessed: uncertain,
 			}
 		}
 
@@ -161,6 +194,15 @@ func (c *ClicksignClient) doRequest(ctx context.Context, method, endpoint string
 	}
 }
 
+// markPriorAttempts marca o erro quando uma tentativa anterior pode ter sido processada pela API
+func markPriorAttempts(err error, uncertain bool) error {
+	var clicksignErr *ClicksignError
+	if uncertain && errors.As(err, &clicksignErr) {
+		clicksignErr.priorAttemptMayHaveBeenProcessed = true
+	}
+	return err
+}
+
 // executeRequest executa uma única requisição HTTP
 func (c *ClicksignClient) executeRequest(ctx context.Context, method, url string, bodyBytes []byte) (*http.Response, error) {
 	var bodyReader io.Reader
@@ -113,7 +113,7 @@ func TestDocumentCreateRequestConformance(t *testing.T) {
 		// Arrange
 		mockClient := &MockClicksignClient{}
 		logger := logrus.New()
-		service := NewDocumentService(mockClient, logger)
+		service := NewDocumentService(mockClient, nil, nil, logger)
 
 		expectedResponse := dto.DocumentCreateResponseWrapper{
 			Data: dto.DocumentCreateResponseData{
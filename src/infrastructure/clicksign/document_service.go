@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"os"
 	"path/filepath"
 	"regexp"
 	"strconv"
@@ -14,6 +13,8 @@ import (
 
 	"app/entity"
 	"app/infrastructure/clicksign/dto"
+	"app/infrastructure/storage"
+	"app/pkg/fetcher"
 	"app/pkg/utils"
 
 	"github.com/sirupsen/logrus"
@@ -21,12 +22,17 @@ import (
 
 type DocumentService struct {
 	clicksignClient ClicksignClientInterface
+	storage         storage.Storage
+	fetcher         *fetcher.Fetcher
 	logger          *logrus.Logger
 }
 
-func NewDocumentService(clicksignClient ClicksignClientInterface, logger *logrus.Logger) *DocumentService {
+// NewDocumentService lê os documentos gravados em documentStorage e baixa as URLs por documentFetcher
+func NewDocumentService(clicksignClient ClicksignClientInterface, documentStorage storage.Storage, documentFetcher *fetcher.Fetcher, logger *logrus.Logger) *DocumentService {
 	return &DocumentService{
 		clicksignClient: clicksignClient,
+		storage:         documentStorage,
+		fetcher:         documentFetcher,
 		logger:          logger,
 	}
 }
@@ -37,15 +43,17 @@ func (s *DocumentService) UploadDocument(ctx context.Context, document *entity.E
 	var uploadRequest *dto.DocumentUploadRequestWrapper
 	var err error
 
-	if document.IsFromBase64 {
-		// Documento veio de base64, usar conteúdo base64 diretamente
-		uploadRequest, err = s.prepareBase64Upload(document)
+	_, isStored := storage.KeyFromURI(document.FilePath)
+
+	if document.IsFromBase64 || isStored {
+		// Documento veio de base64 ou de upload no storage, usar conteúdo base64 diretamente
+		uploadRequest, err = s.prepareBase64Upload(ctx, document)
 		if err != nil {
 			return "", fmt.Errorf("failed to prepare base64 upload: %w", err)
 		}
 	} else {
 		// Documento veio de file_path, ler arquivo e converter para base64
-		uploadRequest, err = s.prepareFilePathUpload(document)
+		uploadRequest, err = s.prepareFilePathUpload(ctx, document)
 		if err != nil {
 			return "", fmt.Errorf("failed to prepare file path upload: %w", err)
 		}
@@ -95,14 +103,26 @@ func (s *DocumentService) CreateDocument(ctx context.Context, envelopeID string,
 	// Verificar se FilePath é uma URL (começa com http:// ou https://)
 	isURL := strings.HasPrefix(document.FilePath, "http://") || strings.HasPrefix(document.FilePath, "https://")
 
-	if document.IsFromBase64 {
-		createRequest, err = s.prepareBase64CreateRequest(document, internalEnvelopeID)
+	_, isStored := storage.KeyFromURI(document.FilePath)
+
+	if document.IsFromBase64 || isStored {
+		createRequest, err = s.prepareBase64CreateRequest(ctx, document, internalEnvelopeID)
 		if err != nil {
 			return "", fmt.Errorf("failed to prepare base64 create request: %w", err)
 		}
+	} else if isURL && document.StorageKey != "" {
+		// Original da URL já está no storage: reenviar sem baixar novamente
+		tempDoc := *document
+		tempDoc.FilePath = storage.URI(document.StorageKey)
+		tempDoc.IsFromBase64 = true
+
+		createRequest, err = s.prepareBase64CreateRequest(ctx, &tempDoc, internalEnvelopeID)
+		if err != nil {
+			return "", fmt.Errorf("failed to prepare base64 create request from storage: %w", err)
+		}
 	} else if isURL {
 		// Se FilePath é uma URL, fazer download e converter para base64
-		fileInfo, err := utils.DownloadFileFromURL(document.FilePath)
+		fileInfo, err := utils.DownloadFileFromURL(s.fetcher, document.FilePath)
 		if err != nil {
 			return "", fmt.Errorf("failed to download file from URL: %w", err)
 		}
@@ -115,12 +135,12 @@ func (s *DocumentService) CreateDocument(ctx context.Context, envelopeID string,
 		tempDoc.MimeType = fileInfo.MimeType
 		tempDoc.IsFromBase64 = true
 
-		createRequest, err = s.prepareBase64CreateRequest(&tempDoc, internalEnvelopeID)
+		createRequest, err = s.prepareBase64CreateRequest(ctx, &tempDoc, internalEnvelopeID)
 		if err != nil {
 			return "", fmt.Errorf("failed to prepare base64 create request from URL: %w", err)
 		}
 	} else {
-		createRequest, err = s.prepareFilePathCreateRequest(document, internalEnvelopeID)
+		createRequest, err = s.prepareFilePathCreateRequest(ctx, document, internalEnvelopeID)
 		if err != nil {
 			return "", fmt.Errorf("failed to prepare file path create request: %w", err)
 		}
@@ -162,10 +182,32 @@ func (s *DocumentService) CreateDocument(ctx context.Context, envelopeID string,
 	return createResponse.Data.ID, nil
 }
 
+// DeleteDocument remove um documento de um envelope em rascunho
+func (s *DocumentService) DeleteDocument(ctx context.Context, envelopeID string, documentID string) error {
+	endpoint := fmt.Sprintf("/api/v3/envelopes/%s/documents/%s", envelopeID, documentID)
+	resp, err := s.clicksignClient.Delete(ctx, endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to delete document from Clicksign envelope: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+
+		var errorResp dto.ClicksignErrorResponse
+		if err := json.Unmarshal(body, &errorResp); err != nil || (errorResp.Error.Type == "" && errorResp.Error.Message == "") {
+			return fmt.Errorf("Clicksign API error (status %d): %s", resp.StatusCode, string(body))
+		}
+		return fmt.Errorf("Clicksign API error: %s - %s", errorResp.Error.Type, errorResp.Error.Message)
+	}
+
+	return nil
+}
+
 // prepareBase64CreateRequest prepara a requisição de criação de documento que veio de base64
-func (s *DocumentService) prepareBase64CreateRequest(document *entity.EntityDocument, internalEnvelopeID int) (*dto.DocumentCreateRequestWrapper, error) {
+func (s *DocumentService) prepareBase64CreateRequest(ctx context.Context, document *entity.EntityDocument, internalEnvelopeID int) (*dto.DocumentCreateRequestWrapper, error) {
 	// Ler arquivo temporário e converter para base64
-	fileData, err := os.ReadFile(document.FilePath)
+	fileData, err := storage.ReadFile(ctx, s.storage, document.FilePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read temporary file: %w", err)
 	}
@@ -218,9 +260,9 @@ func (s *DocumentService) prepareBase64CreateRequest(document *entity.EntityDocu
 }
 
 // prepareFilePathCreateRequest prepara a requisição de criação de documento que veio de file_path
-func (s *DocumentService) prepareFilePathCreateRequest(document *entity.EntityDocument, internalEnvelopeID int) (*dto.DocumentCreateRequestWrapper, error) {
+func (s *DocumentService) prepareFilePathCreateRequest(ctx context.Context, document *entity.EntityDocument, internalEnvelopeID int) (*dto.DocumentCreateRequestWrapper, error) {
 	// Ler arquivo do sistema e converter para base64
-	fileData, err := os.ReadFile(document.FilePath)
+	fileData, err := storage.ReadFile(ctx, s.storage, document.FilePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file from path: %w", err)
 	}
@@ -273,9 +315,9 @@ func (s *DocumentService) prepareFilePathCreateRequest(document *entity.EntityDo
 }
 
 // prepareBase64Upload prepara o upload de documento que veio de base64
-func (s *DocumentService) prepareBase64Upload(document *entity.EntityDocument) (*dto.DocumentUploadRequestWrapper, error) {
+func (s *DocumentService) prepareBase64Upload(ctx context.Context, document *entity.EntityDocument) (*dto.DocumentUploadRequestWrapper, error) {
 	// Ler arquivo temporário e converter para base64
-	fileData, err := os.ReadFile(document.FilePath)
+	fileData, err := storage.ReadFile(ctx, s.storage, document.FilePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read temporary file: %w", err)
 	}
@@ -297,9 +339,9 @@ func (s *DocumentService) prepareBase64Upload(document *entity.EntityDocument) (
 }
 
 // prepareFilePathUpload prepara o upload de documento que veio de file_path
-func (s *DocumentService) prepareFilePathUpload(document *entity.EntityDocument) (*dto.DocumentUploadRequestWrapper, error) {
+func (s *DocumentService) prepareFilePathUpload(ctx context.Context, document *entity.EntityDocument) (*dto.DocumentUploadRequestWrapper, error) {
 	// Ler arquivo do sistema e converter para base64
-	fileData, err := os.ReadFile(document.FilePath)
+	fileData, err := storage.ReadFile(ctx, s.storage, document.FilePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file from path: %w", err)
 	}
@@ -1,6 +1,10 @@
 package dto
 
-import "time"
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
 
 // EnvelopeCreateRequestWrapper representa a estrutura JSON API para criação de envelope na API do Clicksign
 type EnvelopeCreateRequestWrapper struct {
@@ -349,6 +353,7 @@ type AtomicResult struct {
 }
 
 // ClicksignErrorResponse representa a estrutura de erro da API do Clicksign
+// Aceita tanto o formato legado ("error") quanto a lista de erros JSON:API ("errors") da API v3
 type ClicksignErrorResponse struct {
 	Error struct {
 		Type       string         `json:"type"`
@@ -357,4 +362,37 @@ type ClicksignErrorResponse struct {
 		Code       string         `json:"code,omitempty"`
 		StatusCode int            `json:"status_code,omitempty"`
 	} `json:"error"`
+	Errors []ClicksignAPIError `json:"errors,omitempty"`
+}
+
+// ClicksignAPIError representa um item da lista de erros JSON:API da API v3 do Clicksign
+type ClicksignAPIError struct {
+	Status string `json:"status"`
+	Code   string `json:"code"`
+	Title  string `json:"title"`
+	Detail string `json:"detail,omitempty"`
+	Source *struct {
+		Pointer string `json:"pointer"`
+	} `json:"source,omitempty"`
+}
+
+// UnmarshalJSON preenche Error a partir do primeiro item de "errors" quando a resposta vem no formato JSON:API
+func (r *ClicksignErrorResponse) UnmarshalJSON(data []byte) error {
+	type plain ClicksignErrorResponse
+	if err := json.Unmarshal(data, (*plain)(r)); err != nil {
+		return err
+	}
+
+	if r.Error.Type == "" && r.Error.Message == "" && len(r.Errors) > 0 {
+		first := r.Errors[0]
+		r.Error.Type = first.Code
+		r.Error.Code = first.Code
+		r.Error.Message = first.Title
+		if first.Detail != "" {
+			r.Error.Message = first.Detail
+		}
+		r.Error.StatusCode, _ = strconv.Atoi(first.Status)
+	}
+
+	return nil
 }
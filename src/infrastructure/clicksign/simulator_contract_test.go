@@ -0,0 +1,334 @@
+package clicksign
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"app/entity"
+	"app/infrastructure/clicksign/dto"
+	"app/infrastructure/clicksign_simulator"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Regerar os payloads de referência: go test ./infrastructure/clicksign/ -run Contract -update
+var updateGolden = flag.Bool("update", false, "atualiza os arquivos golden em testdata/golden")
+
+// assertGolden compara as trocas registradas pelo simulador com testdata/golden/<name>.json
+func assertGolden(t *testing.T, name string, exchanges []clicksign_simulator.Exchange) {
+	t.Helper()
+
+	actual, err := json.MarshalIndent(exchanges, "", "  ")
+	require.NoError(t, err)
+	actual = append(actual, '\n')
+
+	path := filepath.Join("testdata", "golden", name+".json")
+	if *updateGolden {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, actual, 0o644))
+	}
+
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err, "golden file ausente; rode com -update para gerá-lo")
+
+	var expectedIndented bytes.Buffer
+	require.NoError(t, json.Indent(&expectedIndented, bytes.TrimSpace(expected), "", "  "))
+	assert.JSONEq(t, expectedIndented.String(), string(actual))
+}
+
+func newSimulatorServices(t *testing.T) (*clicksign_simulator.Server, ClicksignClientInterface) {
+	t.Helper()
+
+	server := clicksign_simulator.NewServer("")
+	t.Cleanup(server.Close)
+
+	return server, NewClicksignClient(server.EnvVars(), logrus.New())
+}
+
+func newContractDocument(t *testing.T) *entity.EntityDocument {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "contrato.pdf")
+	require.NoError(t, os.WriteFile(path, []byte("%PDF-1.4\n% contrato de teste\n%%EOF\n"), 0o644))
+
+	return &entity.EntityDocument{
+		ID:           7,
+		Name:         "Contrato de Prestação",
+		FilePath:     path,
+		MimeType:     "application/pdf",
+		IsFromBase64: true,
+	}
+}
+
+func TestContract_EnvelopeLifecycle(t *testing.T) {
+	server, client := newSimulatorServices(t)
+	logger := logrus.New()
+	ctx := context.Background()
+
+	envelopeService := NewEnvelopeService(client, logger)
+	documentService := NewDocumentService(client, nil, nil, logger)
+	signerService := NewSignerService(client, logger)
+	requirementService := NewRequirementService(client, logger)
+	autoSignatureService := NewAutoSignatureService(client, logger)
+
+	deadline := time.Date(2025, time.February, 1, 12, 0, 0, 0, time.UTC)
+	envelopeID, rawData, err := envelopeService.CreateEnvelope(ctx, &entity.EntityEnvelope{
+		ID:             42,
+		Name:           "Contrato de Prestação",
+		Message:        "Por favor, assine o contrato",
+		RemindInterval: 3,
+		AutoClose:      true,
+		DeadlineAt:     &deadline,
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, envelopeID)
+	assert.Contains(t, rawData, `"status":"draft"`)
+
+	documentID, err := documentService.CreateDocument(ctx, envelopeID, newContractDocument(t), 42)
+	require.NoError(t, err)
+
+	documentation := "123.456.789-09"
+	var signerIDs []string
+	for _, signer := range []SignerData{
+		{Name: "Maria Silva", Email: "maria@empresa.com", Birthday: "1990-05-10", Documentation: &documentation, HasDocumentation: true, Group: 1},
+		{Name: "João Souza", Email: "joao@empresa.com", Refusable: true, Group: 2, CommunicateEvents: &SignerCommunicateEventsData{
+			DocumentSigned:    "email",
+			SignatureRequest:  "email",
+			SignatureReminder: "email",
+		}},
+	} {
+		signerID, err := signerService.CreateSigner(ctx, envelopeID, signer)
+		require.NoError(t, err)
+		signerIDs = append(signerIDs, signerID)
+	}
+
+	qualificationID, err := requirementService.CreateRequirement(ctx, envelopeID, RequirementData{
+		Action: "agree", Role: "sign", DocumentID: documentID, SignerID: signerIDs[0],
+	})
+	require.NoError(t, err)
+	_, err = requirementService.CreateRequirement(ctx, envelopeID, RequirementData{
+		Action: "provide_evidence", Auth: "email", DocumentID: documentID, SignerID: signerIDs[0],
+	})
+	require.NoError(t, err)
+
+	bulkIDs, err := requirementService.CreateBulkRequirements(ctx, envelopeID, []BulkOperation{
+		{Operation: "remove", RequirementID: qualificationID},
+		{Operation: "add", RequirementData: &RequirementData{Action: "agree", Role: "sign", DocumentID: documentID, SignerID: signerIDs[0]}},
+		{Operation: "add", RequirementData: &RequirementData{Action: "agree", Role: "sign", DocumentID: documentID, SignerID: signerIDs[1]}},
+		{Operation: "add", RequirementData: &RequirementData{Action: "provide_evidence", Auth: "icp_brasil", DocumentID: documentID, SignerID: signerIDs[1]}},
+	})
+	require.NoError(t, err)
+	assert.Len(t, bulkIDs, 3)
+
+	require.NoError(t, envelopeService.ActivateEnvelope(ctx, envelopeID))
+	require.NoError(t, envelopeService.NotifyEnvelope(ctx, envelopeID, "Lembrete de assinatura"))
+
+	term, err := autoSignatureService.CreateAutoSignatureTerm(dto.AutoSignatureTermRequest{
+		Data: dto.AutoSignatureTermData{
+			Type: "auto_signature_terms",
+			Attributes: dto.AutoSignatureTermAttributes{
+				Signer: dto.SignerInfo{
+					Documentation: documentation,
+					Birthday:      "1990-05-10",
+					Email:         "maria@empresa.com",
+					Name:          "Maria Silva",
+				},
+				AdminEmail: "admin@empresa.com",
+				APIEmail:   "api@empresa.com",
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, term.Data.ID)
+	assert.Equal(t, "maria@empresa.com", term.Data.Attributes.Signer.Email)
+
+	status, documents, signers, requirements, notifications, ok := server.Envelope(envelopeID)
+	require.True(t, ok)
+	assert.Equal(t, "running", status)
+	assert.Equal(t, 1, documents)
+	assert.Equal(t, 2, signers)
+	assert.Equal(t, 4, requirements)
+	assert.Equal(t, 1, notifications)
+
+	assertGolden(t, "envelope_lifecycle", server.Exchanges())
+}
+
+func TestContract_ErrorResponses(t *testing.T) {
+	logger := logrus.New()
+	ctx := context.Background()
+
+	t.Run("should reject envelope without name as client error", func(t *testing.T) {
+		server, client := newSimulatorServices(t)
+
+		_, _, err := NewEnvelopeService(client, logger).CreateEnvelope(ctx, &entity.EntityEnvelope{})
+
+		var clicksignErr *ClicksignError
+		require.True(t, errors.As(err, &clicksignErr))
+		assert.Equal(t, ErrorTypeClient, clicksignErr.Type)
+		assert.Equal(t, http.StatusUnprocessableEntity, clicksignErr.StatusCode)
+		assert.False(t, clicksignErr.Temporary())
+		assertGolden(t, "error_envelope_without_name", server.Exchanges())
+	})
+
+	t.Run("should report invalid API key as authentication error", func(t *testing.T) {
+		server, _ := newSimulatorServices(t)
+		envVars := server.EnvVars()
+		envVars.CLICKSIGN_API_KEY = "invalid-key"
+
+		_, _, err := NewEnvelopeService(NewClicksignClient(envVars, logger), logger).CreateEnvelope(ctx, &entity.EntityEnvelope{Name: "Contrato"})
+
+		var clicksignErr *ClicksignError
+		require.True(t, errors.As(err, &clicksignErr))
+		assert.Equal(t, ErrorTypeAuthentication, clicksignErr.Type)
+		assert.Equal(t, http.StatusUnauthorized, clicksignErr.StatusCode)
+	})
+
+	t.Run("should retry server errors and succeed", func(t *testing.T) {
+		server, _ := newSimulatorServices(t)
+		server.QueueResponse(http.MethodPost, "/api/v3/envelopes", http.StatusServiceUnavailable, `{"errors":[{"status":"503","code":"service_unavailable","title":"Serviço indisponível"}]}`)
+		envVars := server.EnvVars()
+		envVars.CLICKSIGN_RETRY_ATTEMPTS = 1
+
+		envelopeID, _, err := NewEnvelopeService(NewClicksignClient(envVars, logger), logger).CreateEnvelope(ctx, &entity.EntityEnvelope{Name: "Contrato"})
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, envelopeID)
+		assert.Len(t, server.Exchanges(), 2)
+	})
+
+	t.Run("should report exhausted server errors as temporary", func(t *testing.T) {
+		server, client := newSimulatorServices(t)
+		server.QueueResponse(http.MethodPost, "/api/v3/envelopes", http.StatusInternalServerError, `{"errors":[{"status":"500","code":"internal_server_error","title":"Erro interno"}]}`)
+
+		_, _, err := NewEnvelopeService(client, logger).CreateEnvelope(ctx, &entity.EntityEnvelope{Name: "Contrato"})
+
+		var clicksignErr *ClicksignError
+		require.True(t, errors.As(err, &clicksignErr))
+		assert.Equal(t, ErrorTypeServer, clicksignErr.Type)
+		assert.True(t, clicksignErr.Temporary())
+		assert.False(t, clicksignErr.NotProcessed(), "a 500 may have created the envelope")
+	})
+
+	t.Run("should report service unavailable as not processed", func(t *testing.T) {
+		server, client := newSimulatorServices(t)
+		server.QueueResponse(http.MethodPost, "/api/v3/envelopes", http.StatusServiceUnavailable, `{"errors":[{"status":"503","code":"service_unavailable","title":"Serviço indisponível"}]}`)
+
+		_, _, err := NewEnvelopeService(client, logger).CreateEnvelope(ctx, &entity.EntityEnvelope{Name: "Contrato"})
+
+		var clicksignErr *ClicksignError
+		require.True(t, errors.As(err, &clicksignErr))
+		assert.True(t, clicksignErr.NotProcessed())
+	})
+
+	t.Run("should not report 503 as not processed after an attempt with unknown outcome", func(t *testing.T) {
+		server, _ := newSimulatorServices(t)
+		server.QueueResponse(http.MethodPost, "/api/v3/envelopes", http.StatusBadGateway, `{"errors":[{"status":"502","code":"bad_gateway","title":"Bad gateway"}]}`)
+		server.QueueResponse(http.MethodPost, "/api/v3/envelopes", http.StatusServiceUnavailable, `{"errors":[{"status":"503","code":"service_unavailable","title":"Serviço indisponível"}]}`)
+		envVars := server.EnvVars()
+		envVars.CLICKSIGN_RETRY_ATTEMPTS = 1
+
+		_, _, err := NewEnvelopeService(NewClicksignClient(envVars, logger), logger).CreateEnvelope(ctx, &entity.EntityEnvelope{Name: "Contrato"})
+
+		var clicksignErr *ClicksignError
+		require.True(t, errors.As(err, &clicksignErr))
+		assert.Equal(t, http.StatusServiceUnavailable, clicksignErr.StatusCode)
+		assert.False(t, clicksignErr.NotProcessed())
+	})
+
+	t.Run("should surface JSON:API error detail when activation is not allowed", func(t *testing.T) {
+		server, client := newSimulatorServices(t)
+		envelopeService := NewEnvelopeService(client, logger)
+		envelopeID, _, err := envelopeService.CreateEnvelope(ctx, &entity.EntityEnvelope{Name: "Contrato"})
+		require.NoError(t, err)
+
+		err = envelopeService.ActivateEnvelope(ctx, envelopeID)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unprocessable_entity")
+		assert.Contains(t, err.Error(), "pelo menos um documento")
+		assertGolden(t, "error_activate_empty_envelope", server.Exchanges())
+	})
+
+	t.Run("should reject notification for draft envelope", func(t *testing.T) {
+		_, client := newSimulatorServices(t)
+		envelopeService := NewEnvelopeService(client, logger)
+		envelopeID, _, err := envelopeService.CreateEnvelope(ctx, &entity.EntityEnvelope{Name: "Contrato"})
+		require.NoError(t, err)
+
+		err = envelopeService.NotifyEnvelope(ctx, envelopeID, "Lembrete")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "envelopes em andamento")
+	})
+
+	t.Run("should reject invalid signer email", func(t *testing.T) {
+		_, client := newSimulatorServices(t)
+		envelopeID, _, err := NewEnvelopeService(client, logger).CreateEnvelope(ctx, &entity.EntityEnvelope{Name: "Contrato"})
+		require.NoError(t, err)
+
+		_, err = NewSignerService(client, logger).CreateSigner(ctx, envelopeID, SignerData{Name: "Sem Email"})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "email")
+	})
+
+	t.Run("should report requirement for unknown signer as client error", func(t *testing.T) {
+		server, client := newSimulatorServices(t)
+		envelopeID, _, err := NewEnvelopeService(client, logger).CreateEnvelope(ctx, &entity.EntityEnvelope{Name: "Contrato"})
+		require.NoError(t, err)
+		documentID, err := NewDocumentService(client, nil, nil, logger).CreateDocument(ctx, envelopeID, newContractDocument(t), 1)
+		require.NoError(t, err)
+
+		_, err = NewRequirementService(client, logger).CreateRequirement(ctx, envelopeID, RequirementData{
+			Action: "agree", Role: "sign", DocumentID: documentID, SignerID: "unknown-signer",
+		})
+
+		var clicksignErr *ClicksignError
+		require.True(t, errors.As(err, &clicksignErr))
+		assert.Equal(t, http.StatusNotFound, clicksignErr.StatusCode)
+		assert.Equal(t, ErrorTypeClient, clicksignErr.Type)
+
+		exchanges := server.Exchanges()
+		assertGolden(t, "error_requirement_unknown_signer", exchanges[len(exchanges)-1:])
+	})
+
+	t.Run("should roll back bulk requirements when one operation fails", func(t *testing.T) {
+		server, client := newSimulatorServices(t)
+		envelopeID, _, err := NewEnvelopeService(client, logger).CreateEnvelope(ctx, &entity.EntityEnvelope{Name: "Contrato"})
+		require.NoError(t, err)
+		documentID, err := NewDocumentService(client, nil, nil, logger).CreateDocument(ctx, envelopeID, newContractDocument(t), 1)
+		require.NoError(t, err)
+		signerID, err := NewSignerService(client, logger).CreateSigner(ctx, envelopeID, SignerData{Name: "Maria", Email: "maria@empresa.com"})
+		require.NoError(t, err)
+
+		_, err = NewRequirementService(client, logger).CreateBulkRequirements(ctx, envelopeID, []BulkOperation{
+			{Operation: "add", RequirementData: &RequirementData{Action: "agree", Role: "sign", DocumentID: documentID, SignerID: signerID}},
+			{Operation: "add", RequirementData: &RequirementData{Action: "provide_evidence", Auth: "carrier_pigeon", DocumentID: documentID, SignerID: signerID}},
+		})
+
+		require.Error(t, err)
+		_, _, _, requirements, _, _ := server.Envelope(envelopeID)
+		assert.Equal(t, 0, requirements)
+	})
+}
+
+func TestClicksignErrorResponse_UnmarshalJSONAPIErrors(t *testing.T) {
+	var errorResp dto.ClicksignErrorResponse
+
+	err := json.Unmarshal([]byte(`{"errors":[{"status":"422","code":"unprocessable_entity","title":"não pode ficar em branco","detail":"name: não pode ficar em branco"}]}`), &errorResp)
+
+	require.NoError(t, err)
+	assert.Equal(t, "unprocessable_entity", errorResp.Error.Type)
+	assert.Equal(t, "name: não pode ficar em branco", errorResp.Error.Message)
+	assert.Equal(t, 422, errorResp.Error.StatusCode)
+}
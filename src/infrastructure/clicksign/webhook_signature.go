@@ -0,0 +1,50 @@
+package clicksign
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// WebhookSignatureHeader é o header em que a Clicksign envia a HMAC-SHA256 do corpo do webhook
+const WebhookSignatureHeader = "Content-Hmac"
+
+const webhookSignaturePrefix = "sha256="
+
+var (
+	ErrWebhookSecretNotConfigured = errors.New("webhook secret is not configured")
+	ErrInvalidWebhookSignature    = errors.New("invalid webhook signature")
+)
+
+// SignWebhook calcula o valor do header Content-Hmac ("sha256=<hex>") para o corpo informado
+func SignWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return webhookSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature confere, em tempo constante, a HMAC enviada no header Content-Hmac
+// Sem segredo configurado nenhum webhook é aceito
+func VerifyWebhookSignature(secret string, body []byte, signature string) error {
+	if secret == "" {
+		return ErrWebhookSecretNotConfigured
+	}
+
+	signature = strings.TrimSpace(signature)
+	if !strings.HasPrefix(strings.ToLower(signature), webhookSignaturePrefix) {
+		return ErrInvalidWebhookSignature
+	}
+	received, err := hex.DecodeString(signature[len(webhookSignaturePrefix):])
+	if err != nil {
+		return ErrInvalidWebhookSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(received, mac.Sum(nil)) {
+		return ErrInvalidWebhookSignature
+	}
+	return nil
+}
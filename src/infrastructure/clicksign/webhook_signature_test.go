@@ -0,0 +1,19 @@
+package clicksign
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	body := []byte(`{"event":{"name":"auto_close"},"document":{"key":"doc-1"}}`)
+	signature := SignWebhook("webhook-secret", body)
+
+	assert.NoError(t, VerifyWebhookSignature("webhook-secret", body, signature))
+	assert.ErrorIs(t, VerifyWebhookSignature("other-secret", body, signature), ErrInvalidWebhookSignature)
+	assert.ErrorIs(t, VerifyWebhookSignature("webhook-secret", append(body, ' '), signature), ErrInvalidWebhookSignature)
+	assert.ErrorIs(t, VerifyWebhookSignature("webhook-secret", body, ""), ErrInvalidWebhookSignature)
+	assert.ErrorIs(t, VerifyWebhookSignature("webhook-secret", body, "sha256=zz"), ErrInvalidWebhookSignature)
+	assert.ErrorIs(t, VerifyWebhookSignature("", body, signature), ErrWebhookSecretNotConfigured)
+}
@@ -3,13 +3,35 @@ package clicksign_provider
 import (
 	"context"
 
+	"app/config"
 	"app/entity"
 	"app/infrastructure/clicksign"
 	"app/infrastructure/provider"
+	"app/infrastructure/storage"
+	"app/pkg/fetcher"
+	"app/pkg/utils"
 
 	"github.com/sirupsen/logrus"
 )
 
+// Capabilities descreve as funcionalidades suportadas pelo provider Clicksign
+var Capabilities = provider.Capabilities{
+	Name:                      "clicksign",
+	AuthMethods:               []string{"email", "icp_brasil"},
+	Actions:                   []string{"agree", "sign", "provide_evidence"},
+	SupportsNotify:            true,
+	SupportsCancel:            false,
+	SupportsSequentialSigning: true,
+	SupportsDocumentReplace:   true,
+	NotifiesSignersOnCreate:   false,
+	MaxFileSize:               int64(utils.MaxFileSize),
+	MimeTypes:                 []string{"application/pdf", "image/jpeg", "image/jpg", "image/png", "image/gif"},
+}
+
+func init() {
+	provider.Register("clicksign", NewClicksignProviderFromConfig, Capabilities)
+}
+
 // ClicksignProvider implementa a interface EnvelopeProvider para o provider Clicksign
 type ClicksignProvider struct {
 	envelopeService    *clicksign.EnvelopeService
@@ -22,10 +44,12 @@ type ClicksignProvider struct {
 // NewClicksignProvider cria uma nova instância do ClicksignProvider
 func NewClicksignProvider(
 	clicksignClient clicksign.ClicksignClientInterface,
+	documentStorage storage.Storage,
+	documentFetcher *fetcher.Fetcher,
 	logger *logrus.Logger,
 ) provider.EnvelopeProvider {
 	envelopeService := clicksign.NewEnvelopeService(clicksignClient, logger)
-	documentService := clicksign.NewDocumentService(clicksignClient, logger)
+	documentService := clicksign.NewDocumentService(clicksignClient, documentStorage, documentFetcher, logger)
 	signerService := clicksign.NewSignerService(clicksignClient, logger)
 	requirementService := clicksign.NewRequirementService(clicksignClient, logger)
 
@@ -38,6 +62,21 @@ func NewClicksignProvider(
 	}
 }
 
+// NewClicksignProviderFromConfig cria o provider a partir das variáveis de ambiente
+// Credenciais informadas (ex.: por tenant) substituem API key e base URL globais
+func NewClicksignProviderFromConfig(envVars config.EnvironmentVars, credentials *provider.Credentials, documentStorage storage.Storage, documentFetcher *fetcher.Fetcher, logger *logrus.Logger) (provider.EnvelopeProvider, error) {
+	if credentials != nil {
+		if credentials.APIKey != "" {
+			envVars.CLICKSIGN_API_KEY = credentials.APIKey
+		}
+		if credentials.BaseURL != "" {
+			envVars.CLICKSIGN_BASE_URL = credentials.BaseURL
+		}
+	}
+
+	return NewClicksignProvider(clicksign.NewClicksignClient(envVars, logger), documentStorage, documentFetcher, logger), nil
+}
+
 // CreateEnvelope cria um envelope no Clicksign
 func (p *ClicksignProvider) CreateEnvelope(ctx context.Context, envelope *entity.EntityEnvelope) (string, string, error) {
 	return p.envelopeService.CreateEnvelope(ctx, envelope)
@@ -48,6 +87,11 @@ func (p *ClicksignProvider) CreateDocument(ctx context.Context, envelopeKey stri
 	return p.documentService.CreateDocument(ctx, envelopeKey, document, internalEnvelopeID)
 }
 
+// DeleteDocument remove um documento de um envelope em rascunho no Clicksign
+func (p *ClicksignProvider) DeleteDocument(ctx context.Context, envelopeKey string, documentKey string) error {
+	return p.documentService.DeleteDocument(ctx, envelopeKey, documentKey)
+}
+
 // CreateSigner cria um signatário no envelope do Clicksign
 func (p *ClicksignProvider) CreateSigner(ctx context.Context, envelopeKey string, signerData provider.SignerData) (string, error) {
 	// Converter SignerData genérico para SignerData do Clicksign
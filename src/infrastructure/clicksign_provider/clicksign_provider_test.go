@@ -20,7 +20,7 @@ func TestNewClicksignProvider(t *testing.T) {
 	mockClient := mocks.NewMockClicksignClientInterface(ctrl)
 	logger := logrus.New()
 
-	envelopeProvider := NewClicksignProvider(mockClient, logger)
+	envelopeProvider := NewClicksignProvider(mockClient, nil, nil, logger)
 
 	assert.NotNil(t, envelopeProvider)
 	assert.IsType(t, &ClicksignProvider{}, envelopeProvider)
@@ -33,7 +33,7 @@ func TestClicksignProvider_ImplementsInterface(t *testing.T) {
 	mockClient := mocks.NewMockClicksignClientInterface(ctrl)
 	logger := logrus.New()
 
-	envelopeProvider := NewClicksignProvider(mockClient, logger)
+	envelopeProvider := NewClicksignProvider(mockClient, nil, nil, logger)
 
 	// Verificar que implementa a interface
 	var _ provider.EnvelopeProvider = envelopeProvider
@@ -47,7 +47,7 @@ func TestClicksignProvider_CreateSigner(t *testing.T) {
 	mockClient := mocks.NewMockClicksignClientInterface(ctrl)
 	logger := logrus.New()
 
-	envelopeProvider := NewClicksignProvider(mockClient, logger)
+	envelopeProvider := NewClicksignProvider(mockClient, nil, nil, logger)
 	clicksignProvider := envelopeProvider.(*ClicksignProvider)
 
 	signerData := provider.SignerData{
@@ -73,7 +73,7 @@ func TestClicksignProvider_CreateRequirement(t *testing.T) {
 	mockClient := mocks.NewMockClicksignClientInterface(ctrl)
 	logger := logrus.New()
 
-	envelopeProvider := NewClicksignProvider(mockClient, logger)
+	envelopeProvider := NewClicksignProvider(mockClient, nil, nil, logger)
 
 	reqData := provider.RequirementData{
 		Action:     "sign",
@@ -96,7 +96,7 @@ func TestClicksignProvider_Context(t *testing.T) {
 	mockClient := mocks.NewMockClicksignClientInterface(ctrl)
 	logger := logrus.New()
 
-	envelopeProvider := NewClicksignProvider(mockClient, logger)
+	envelopeProvider := NewClicksignProvider(mockClient, nil, nil, logger)
 
 	ctx := context.Background()
 	envelope := &entity.EntityEnvelope{
@@ -0,0 +1,531 @@
+package clicksign_simulator
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// resource representa um recurso JSON:API genérico
+type resource struct {
+	Type          string                  `json:"type"`
+	ID            string                  `json:"id,omitempty"`
+	Attributes    map[string]interface{}  `json:"attributes,omitempty"`
+	Relationships map[string]relationship `json:"relationships,omitempty"`
+}
+
+type relationship struct {
+	Data *resourceIdentifier `json:"data"`
+}
+
+type resourceIdentifier struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+type resourceDocument struct {
+	Data *resource `json:"data"`
+}
+
+type atomicOperation struct {
+	Op   string              `json:"op"`
+	Ref  *resourceIdentifier `json:"ref,omitempty"`
+	Data *resource           `json:"data,omitempty"`
+}
+
+type atomicRequest struct {
+	Operations []atomicOperation `json:"atomic:operations"`
+}
+
+type atomicResult struct {
+	Data *resource `json:"data,omitempty"`
+}
+
+// apiError segue o formato de erros JSON:API devolvido pela API v3 do Clicksign
+type apiError struct {
+	Status string          `json:"status"`
+	Code   string          `json:"code"`
+	Title  string          `json:"title"`
+	Detail string          `json:"detail,omitempty"`
+	Source *apiErrorSource `json:"source,omitempty"`
+}
+
+type apiErrorSource struct {
+	Pointer string `json:"pointer"`
+}
+
+type envelope struct {
+	resource      resource
+	status        string
+	documents     []string
+	signers       map[string]resource
+	signerOrder   []string
+	requirements  map[string]resource
+	notifications int
+}
+
+type document struct {
+	resource   resource
+	envelopeID string
+}
+
+func errorResponse(statusCode int, code, title, pointer string) (int, []byte) {
+	err := apiError{
+		Status: strconv.Itoa(statusCode),
+		Code:   code,
+		Title:  title,
+	}
+	if pointer != "" {
+		err.Source = &apiErrorSource{Pointer: pointer}
+		err.Detail = fmt.Sprintf("%s: %s", strings.TrimPrefix(pointer, "/data/attributes/"), title)
+	}
+
+	body, _ := json.Marshal(map[string][]apiError{"errors": {err}})
+	return statusCode, body
+}
+
+func validationError(title, pointer string) (int, []byte) {
+	return errorResponse(http.StatusUnprocessableEntity, "unprocessable_entity", title, pointer)
+}
+
+func jsonResponse(statusCode int, payload interface{}) (int, []byte) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errorResponse(http.StatusInternalServerError, "internal_server_error", err.Error(), "")
+	}
+	return statusCode, body
+}
+
+// parseResource decodifica um documento JSON:API e valida o tipo do recurso
+func parseResource(body []byte, expectedType string) (*resource, int, []byte) {
+	var payload resourceDocument
+	if err := json.Unmarshal(body, &payload); err != nil {
+		status, response := errorResponse(http.StatusBadRequest, "bad_request", "JSON inválido", "")
+		return nil, status, response
+	}
+	if payload.Data == nil {
+		status, response := validationError("não pode ficar em branco", "/data")
+		return nil, status, response
+	}
+	if payload.Data.Type != expectedType {
+		status, response := errorResponse(http.StatusConflict, "conflict", fmt.Sprintf("tipo deve ser %s", expectedType), "/data/type")
+		return nil, status, response
+	}
+	if payload.Data.Attributes == nil {
+		payload.Data.Attributes = map[string]interface{}{}
+	}
+	return payload.Data, 0, nil
+}
+
+func stringAttribute(attributes map[string]interface{}, name string) string {
+	value, _ := attributes[name].(string)
+	return strings.TrimSpace(value)
+}
+
+func (s *Server) createEnvelope(body []byte) (int, []byte) {
+	data, status, response := parseResource(body, "envelopes")
+	if data == nil {
+		return status, response
+	}
+	if stringAttribute(data.Attributes, "name") == "" {
+		return validationError("não pode ficar em branco", "/data/attributes/name")
+	}
+
+	attributes := map[string]interface{}{
+		"name":                data.Attributes["name"],
+		"status":              "draft",
+		"locale":              valueOrDefault(data.Attributes["locale"], "pt-BR"),
+		"auto_close":          valueOrDefault(data.Attributes["auto_close"], false),
+		"remind_interval":     valueOrDefault(data.Attributes["remind_interval"], nil),
+		"block_after_refusal": valueOrDefault(data.Attributes["block_after_refusal"], false),
+		"deadline_at":         valueOrDefault(data.Attributes["deadline_at"], nil),
+		"default_subject":     valueOrDefault(data.Attributes["default_subject"], nil),
+		"created_at":          s.timestamp(),
+		"updated_at":          s.timestamp(),
+	}
+
+	env := &envelope{
+		resource:     resource{Type: "envelopes", ID: s.nextID(), Attributes: attributes},
+		status:       "draft",
+		signers:      make(map[string]resource),
+		requirements: make(map[string]resource),
+	}
+	s.envelopes[env.resource.ID] = env
+
+	return jsonResponse(http.StatusCreated, resourceDocument{Data: &env.resource})
+}
+
+func (s *Server) getEnvelope(id string) (int, []byte) {
+	env, ok := s.envelopes[id]
+	if !ok {
+		return errorResponse(http.StatusNotFound, "not_found", "Envelope não encontrado", "")
+	}
+	return jsonResponse(http.StatusOK, resourceDocument{Data: &env.resource})
+}
+
+func (s *Server) updateEnvelope(id string, body []byte) (int, []byte) {
+	env, ok := s.envelopes[id]
+	if !ok {
+		return errorResponse(http.StatusNotFound, "not_found", "Envelope não encontrado", "")
+	}
+
+	data, status, response := parseResource(body, "envelopes")
+	if data == nil {
+		return status, response
+	}
+	if data.ID != "" && data.ID != id {
+		return errorResponse(http.StatusConflict, "conflict", "id não corresponde ao envelope da URL", "/data/id")
+	}
+
+	if newStatus := stringAttribute(data.Attributes, "status"); newStatus != "" && newStatus != env.status {
+		if newStatus != "running" || env.status != "draft" {
+			return validationError(fmt.Sprintf("transição de %s para %s não permitida", env.status, newStatus), "/data/attributes/status")
+		}
+		if len(env.documents) == 0 {
+			return validationError("envelope precisa de pelo menos um documento", "/data/attributes/status")
+		}
+		if len(env.signers) == 0 {
+			return validationError("envelope precisa de pelo menos um signatário", "/data/attributes/status")
+		}
+		for _, signerID := range env.signerOrder {
+			if !env.hasQualification(signerID) {
+				return validationError(fmt.Sprintf("signatário %s não possui requisito de qualificação", signerID), "/data/attributes/status")
+			}
+		}
+		env.status = newStatus
+		env.resource.Attributes["status"] = newStatus
+	}
+	if deadline, ok := data.Attributes["deadline_at"]; ok {
+		env.resource.Attributes["deadline_at"] = deadline
+	}
+	env.resource.Attributes["updated_at"] = s.timestamp()
+
+	return jsonResponse(http.StatusOK, resourceDocument{Data: &env.resource})
+}
+
+func (s *Server) createDocument(env *envelope, body []byte) (int, []byte) {
+	if env.status != "draft" {
+		return validationError("documentos só podem ser adicionados a envelopes em rascunho", "/data")
+	}
+
+	data, status, response := parseResource(body, "documents")
+	if data == nil {
+		return status, response
+	}
+
+	doc, status, response := s.newDocument(data)
+	if doc == nil {
+		return status, response
+	}
+	doc.envelopeID = env.resource.ID
+	env.documents = append(env.documents, doc.resource.ID)
+
+	return jsonResponse(http.StatusCreated, resourceDocument{Data: &doc.resource})
+}
+
+func (s *Server) uploadDocument(body []byte) (int, []byte) {
+	data, status, response := parseResource(body, "documents")
+	if data == nil {
+		return status, response
+	}
+
+	doc, status, response := s.newDocument(data)
+	if doc == nil {
+		return status, response
+	}
+	doc.resource.Attributes["path"] = "/" + stringAttribute(data.Attributes, "filename")
+
+	return jsonResponse(http.StatusCreated, resourceDocument{Data: &doc.resource})
+}
+
+// newDocument valida filename e o data URI em content_base64 e registra o documento
+func (s *Server) newDocument(data *resource) (*document, int, []byte) {
+	filename := stringAttribute(data.Attributes, "filename")
+	if filename == "" {
+		status, response := validationError("não pode ficar em branco", "/data/attributes/filename")
+		return nil, status, response
+	}
+
+	content := stringAttribute(data.Attributes, "content_base64")
+	if content == "" {
+		status, response := validationError("não pode ficar em branco", "/data/attributes/content_base64")
+		return nil, status, response
+	}
+
+	contentType, payload, ok := parseDataURI(content)
+	if !ok {
+		status, response := validationError("deve ser um data URI no formato data:<mime>;base64,<conteúdo>", "/data/attributes/content_base64")
+		return nil, status, response
+	}
+	decoded, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		status, response := validationError("conteúdo base64 inválido", "/data/attributes/content_base64")
+		return nil, status, response
+	}
+
+	attributes := map[string]interface{}{
+		"filename":     filename,
+		"content_type": contentType,
+		"filesize":     len(decoded),
+		"created_at":   s.timestamp(),
+		"updated_at":   s.timestamp(),
+	}
+	if metadata, ok := data.Attributes["metadata"]; ok {
+		attributes["metadata"] = metadata
+	}
+
+	doc := &document{resource: resource{Type: "documents", ID: s.nextID(), Attributes: attributes}}
+	s.documents[doc.resource.ID] = doc
+
+	return doc, 0, nil
+}
+
+func (s *Server) createSigner(env *envelope, body []byte) (int, []byte) {
+	if env.status != "draft" {
+		return validationError("signatários só podem ser adicionados a envelopes em rascunho", "/data")
+	}
+
+	data, status, response := parseResource(body, "signers")
+	if data == nil {
+		return status, response
+	}
+	if stringAttribute(data.Attributes, "name") == "" {
+		return validationError("não pode ficar em branco", "/data/attributes/name")
+	}
+	email := stringAttribute(data.Attributes, "email")
+	if email == "" || !strings.Contains(email, "@") {
+		return validationError("não é um email válido", "/data/attributes/email")
+	}
+	for _, signerID := range env.signerOrder {
+		if strings.EqualFold(stringAttribute(env.signers[signerID].Attributes, "email"), email) {
+			return validationError("já está em uso neste envelope", "/data/attributes/email")
+		}
+	}
+
+	attributes := make(map[string]interface{}, len(data.Attributes)+2)
+	for key, value := range data.Attributes {
+		attributes[key] = value
+	}
+	attributes["created_at"] = s.timestamp()
+	attributes["updated_at"] = s.timestamp()
+
+	signer := resource{Type: "signers", ID: s.nextID(), Attributes: attributes}
+	env.signers[signer.ID] = signer
+	env.signerOrder = append(env.signerOrder, signer.ID)
+
+	return jsonResponse(http.StatusCreated, resourceDocument{Data: &signer})
+}
+
+func (s *Server) createRequirement(env *envelope, body []byte) (int, []byte) {
+	data, status, response := parseResource(body, "requirements")
+	if data == nil {
+		return status, response
+	}
+
+	requirement, status, response := s.newRequirement(env, data, "/data")
+	if requirement == nil {
+		return status, response
+	}
+
+	return jsonResponse(http.StatusCreated, resourceDocument{Data: requirement})
+}
+
+func (s *Server) bulkRequirements(env *envelope, body []byte) (int, []byte) {
+	var payload atomicRequest
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return errorResponse(http.StatusBadRequest, "bad_request", "JSON inválido", "")
+	}
+	if len(payload.Operations) == 0 {
+		return validationError("não pode ficar em branco", "/atomic:operations")
+	}
+
+	// Operações são atômicas: valida tudo antes de aplicar
+	results := make([]atomicResult, 0, len(payload.Operations))
+	var added []string
+	var removed []string
+	rollback := func() {
+		for _, id := range added {
+			delete(env.requirements, id)
+		}
+	}
+
+	for i, operation := range payload.Operations {
+		pointer := fmt.Sprintf("/atomic:operations/%d", i)
+		switch operation.Op {
+		case "add":
+			if operation.Data == nil || operation.Data.Type != "requirements" {
+				rollback()
+				return validationError("data deve ser um recurso requirements", pointer+"/data")
+			}
+			if operation.Data.Attributes == nil {
+				operation.Data.Attributes = map[string]interface{}{}
+			}
+			requirement, status, response := s.newRequirement(env, operation.Data, pointer+"/data")
+			if requirement == nil {
+				rollback()
+				return status, response
+			}
+			added = append(added, requirement.ID)
+			results = append(results, atomicResult{Data: requirement})
+		case "remove":
+			if operation.Ref == nil || operation.Ref.Type != "requirements" {
+				rollback()
+				return validationError("ref deve referenciar requirements", pointer+"/ref")
+			}
+			if _, ok := env.requirements[operation.Ref.ID]; !ok {
+				rollback()
+				return errorResponse(http.StatusNotFound, "not_found", fmt.Sprintf("Requisito %s não encontrado", operation.Ref.ID), pointer+"/ref/id")
+			}
+			removed = append(removed, operation.Ref.ID)
+			results = append(results, atomicResult{})
+		default:
+			rollback()
+			return validationError(fmt.Sprintf("operação '%s' não suportada", operation.Op), pointer+"/op")
+		}
+	}
+
+	for _, id := range removed {
+		delete(env.requirements, id)
+	}
+
+	return jsonResponse(http.StatusOK, map[string][]atomicResult{"atomic:results": results})
+}
+
+// newRequirement valida ação, autenticação e relacionamentos e registra o requisito no envelope
+func (s *Server) newRequirement(env *envelope, data *resource, pointer string) (*resource, int, []byte) {
+	action := stringAttribute(data.Attributes, "action")
+	switch action {
+	case "agree", "sign":
+		// Qualificação
+	case "provide_evidence":
+		if auth := stringAttribute(data.Attributes, "auth"); auth != "email" && auth != "icp_brasil" && auth != "sms" && auth != "whatsapp" {
+			status, response := validationError("autenticação não suportada", pointer+"/attributes/auth")
+			return nil, status, response
+		}
+	default:
+		status, response := validationError("não está incluído na lista", pointer+"/attributes/action")
+		return nil, status, response
+	}
+
+	documentRef := data.Relationships["document"].Data
+	if documentRef == nil || documentRef.Type != "documents" {
+		status, response := validationError("documento é obrigatório", pointer+"/relationships/document")
+		return nil, status, response
+	}
+	if doc, ok := s.documents[documentRef.ID]; !ok || doc.envelopeID != env.resource.ID {
+		status, response := errorResponse(http.StatusNotFound, "not_found", fmt.Sprintf("Documento %s não encontrado no envelope", documentRef.ID), pointer+"/relationships/document")
+		return nil, status, response
+	}
+
+	signerRef := data.Relationships["signer"].Data
+	if signerRef == nil || signerRef.Type != "signers" {
+		status, response := validationError("signatário é obrigatório", pointer+"/relationships/signer")
+		return nil, status, response
+	}
+	if _, ok := env.signers[signerRef.ID]; !ok {
+		status, response := errorResponse(http.StatusNotFound, "not_found", fmt.Sprintf("Signatário %s não encontrado no envelope", signerRef.ID), pointer+"/relationships/signer")
+		return nil, status, response
+	}
+
+	attributes := map[string]interface{}{
+		"action":     action,
+		"created_at": s.timestamp(),
+		"updated_at": s.timestamp(),
+	}
+	if role := stringAttribute(data.Attributes, "role"); role != "" {
+		attributes["role"] = role
+	}
+	if auth := stringAttribute(data.Attributes, "auth"); auth != "" {
+		attributes["auth"] = auth
+	}
+
+	requirement := resource{
+		Type:       "requirements",
+		ID:         s.nextID(),
+		Attributes: attributes,
+		Relationships: map[string]relationship{
+			"document": {Data: &resourceIdentifier{Type: "documents", ID: documentRef.ID}},
+			"signer":   {Data: &resourceIdentifier{Type: "signers", ID: signerRef.ID}},
+		},
+	}
+	env.requirements[requirement.ID] = requirement
+
+	return &requirement, 0, nil
+}
+
+func (s *Server) notify(env *envelope, body []byte) (int, []byte) {
+	if env.status != "running" {
+		return validationError("notificações só podem ser enviadas para envelopes em andamento", "/data")
+	}
+
+	data, status, response := parseResource(body, "notifications")
+	if data == nil {
+		return status, response
+	}
+	env.notifications++
+
+	notification := resource{
+		Type: "notifications",
+		ID:   s.nextID(),
+		Attributes: map[string]interface{}{
+			"message":    valueOrDefault(data.Attributes["message"], nil),
+			"created_at": s.timestamp(),
+		},
+	}
+	return jsonResponse(http.StatusCreated, resourceDocument{Data: &notification})
+}
+
+func (s *Server) createAutoSignatureTerm(body []byte) (int, []byte) {
+	data, status, response := parseResource(body, "auto_signature_terms")
+	if data == nil {
+		return status, response
+	}
+
+	signer, _ := data.Attributes["signer"].(map[string]interface{})
+	for _, field := range []string{"documentation", "birthday", "email", "name"} {
+		if stringAttribute(signer, field) == "" {
+			return validationError("não pode ficar em branco", "/data/attributes/signer/"+field)
+		}
+	}
+	for _, field := range []string{"admin_email", "api_email"} {
+		if stringAttribute(data.Attributes, field) == "" {
+			return validationError("não pode ficar em branco", "/data/attributes/"+field)
+		}
+	}
+
+	term := resource{Type: "auto_signature_terms", ID: s.nextID(), Attributes: data.Attributes}
+	s.terms[term.ID] = term
+
+	return jsonResponse(http.StatusCreated, resourceDocument{Data: &term})
+}
+
+func (e *envelope) hasQualification(signerID string) bool {
+	for _, requirement := range e.requirements {
+		action := stringAttribute(requirement.Attributes, "action")
+		if (action == "agree" || action == "sign") && requirement.Relationships["signer"].Data.ID == signerID {
+			return true
+		}
+	}
+	return false
+}
+
+func parseDataURI(value string) (contentType, payload string, ok bool) {
+	if !strings.HasPrefix(value, "data:") {
+		return "", "", false
+	}
+	header, payload, found := strings.Cut(strings.TrimPrefix(value, "data:"), ",")
+	if !found || !strings.HasSuffix(header, ";base64") {
+		return "", "", false
+	}
+	return strings.TrimSuffix(header, ";base64"), payload, true
+}
+
+func valueOrDefault(value, defaultValue interface{}) interface{} {
+	if value == nil {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,232 @@
+package clicksign_simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"time"
+
+	"app/config"
+)
+
+// JSONAPIContentType é o content type exigido e devolvido pela API v3 do Clicksign
+const JSONAPIContentType = "application/vnd.api+json"
+
+// DefaultAPIKey é a chave aceita pelo simulador quando nenhuma outra é informada
+const DefaultAPIKey = "simulator-api-key"
+
+// DefaultTime é o horário fixo usado nos timestamps das respostas, para que sejam reproduzíveis
+var DefaultTime = time.Date(2025, time.January, 2, 10, 0, 0, 0, time.UTC)
+
+// Exchange registra uma requisição recebida pelo simulador e a resposta devolvida
+type Exchange struct {
+	Method       string          `json:"method"`
+	Path         string          `json:"path"`
+	RequestBody  json.RawMessage `json:"request_body,omitempty"`
+	StatusCode   int             `json:"status_code"`
+	ResponseBody json.RawMessage `json:"response_body,omitempty"`
+}
+
+type queuedResponse struct {
+	method     string
+	path       string
+	statusCode int
+	body       string
+}
+
+// Server simula a API v3 do Clicksign sobre um httptest.Server
+// Mantém envelopes, documentos, signatários, requisitos e termos de assinatura automática em memória,
+// valida a estrutura JSON:API dos requests e devolve erros no formato da API real
+type Server struct {
+	*httptest.Server
+
+	apiKey string
+	now    func() time.Time
+
+	mu        sync.Mutex
+	sequence  int
+	envelopes map[string]*envelope
+	documents map[string]*document
+	terms     map[string]resource
+	exchanges []Exchange
+	queued    []queuedResponse
+}
+
+// NewServer inicia um simulador que aceita apenas a chave de API informada
+func NewServer(apiKey string) *Server {
+	if apiKey == "" {
+		apiKey = DefaultAPIKey
+	}
+
+	s := &Server{
+		apiKey:    apiKey,
+		now:       func() time.Time { return DefaultTime },
+		envelopes: make(map[string]*envelope),
+		documents: make(map[string]*document),
+		terms:     make(map[string]resource),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// EnvVars retorna a configuração do client Clicksign apontando para o simulador
+// Retries ficam desabilitados; ajuste CLICKSIGN_RETRY_ATTEMPTS para exercitar o backoff
+func (s *Server) EnvVars() config.EnvironmentVars {
+	return config.EnvironmentVars{
+		CLICKSIGN_API_KEY:        s.apiKey,
+		CLICKSIGN_BASE_URL:       s.URL,
+		CLICKSIGN_TIMEOUT:        5,
+		CLICKSIGN_RETRY_ATTEMPTS: 0,
+	}
+}
+
+// SetClock define a função usada para os timestamps das respostas
+func (s *Server) SetClock(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.now = now
+}
+
+// QueueResponse faz com que a próxima requisição para method e path devolva status e body informados
+// Respostas enfileiradas são consumidas em ordem e têm prioridade sobre a simulação
+func (s *Server) QueueResponse(method, path string, statusCode int, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.queued = append(s.queued, queuedResponse{
+		method:     method,
+		path:       path,
+		statusCode: statusCode,
+		body:       body,
+	})
+}
+
+// Exchanges retorna as requisições recebidas e as respostas devolvidas, em ordem
+func (s *Server) Exchanges() []Exchange {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Exchange(nil), s.exchanges...)
+}
+
+// Envelope retorna o status e as contagens de um envelope simulado
+func (s *Server) Envelope(id string) (status string, documents, signers, requirements, notifications int, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	env, ok := s.envelopes[id]
+	if !ok {
+		return "", 0, 0, 0, 0, false
+	}
+	return env.status, len(env.documents), len(env.signers), len(env.requirements), env.notifications, true
+}
+
+var (
+	envelopesPath        = regexp.MustCompile(`^/api/v3/envelopes$`)
+	envelopePath         = regexp.MustCompile(`^/api/v3/envelopes/([^/]+)$`)
+	envelopeResourcePath = regexp.MustCompile(`^/api/v3/envelopes/([^/]+)/(documents|signers|requirements|bulk_requirements|notifications)$`)
+	documentsPath        = regexp.MustCompile(`^/api/v3/documents$`)
+	termsPath            = regexp.MustCompile(`^/api/v3/auto_signature/terms$`)
+)
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statusCode, response := s.route(r, body)
+
+	exchange := Exchange{
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		StatusCode: statusCode,
+	}
+	if json.Valid(body) {
+		exchange.RequestBody = json.RawMessage(body)
+	}
+	if json.Valid(response) {
+		exchange.ResponseBody = json.RawMessage(response)
+	}
+	s.exchanges = append(s.exchanges, exchange)
+
+	w.Header().Set("Content-Type", JSONAPIContentType)
+	w.WriteHeader(statusCode)
+	if len(response) > 0 {
+		_, _ = w.Write(response)
+	}
+}
+
+func (s *Server) route(r *http.Request, body []byte) (int, []byte) {
+	if queued, ok := s.popQueued(r.Method, r.URL.Path); ok {
+		return queued.statusCode, []byte(queued.body)
+	}
+
+	if r.Header.Get("Authorization") != s.apiKey {
+		return errorResponse(http.StatusUnauthorized, "unauthorized", "Access token inválido ou ausente", "")
+	}
+
+	if r.Method != http.MethodGet && r.Header.Get("Content-Type") != JSONAPIContentType {
+		return errorResponse(http.StatusUnsupportedMediaType, "unsupported_media_type", fmt.Sprintf("Content-Type deve ser %s", JSONAPIContentType), "")
+	}
+
+	path := r.URL.Path
+	switch {
+	case envelopesPath.MatchString(path) && r.Method == http.MethodPost:
+		return s.createEnvelope(body)
+	case envelopePath.MatchString(path) && r.Method == http.MethodGet:
+		return s.getEnvelope(envelopePath.FindStringSubmatch(path)[1])
+	case envelopePath.MatchString(path) && r.Method == http.MethodPatch:
+		return s.updateEnvelope(envelopePath.FindStringSubmatch(path)[1], body)
+	case envelopeResourcePath.MatchString(path) && r.Method == http.MethodPost:
+		match := envelopeResourcePath.FindStringSubmatch(path)
+		env, ok := s.envelopes[match[1]]
+		if !ok {
+			return errorResponse(http.StatusNotFound, "not_found", "Envelope não encontrado", "")
+		}
+		switch match[2] {
+		case "documents":
+			return s.createDocument(env, body)
+		case "signers":
+			return s.createSigner(env, body)
+		case "requirements":
+			return s.createRequirement(env, body)
+		case "bulk_requirements":
+			return s.bulkRequirements(env, body)
+		default:
+			return s.notify(env, body)
+		}
+	case documentsPath.MatchString(path) && r.Method == http.MethodPost:
+		return s.uploadDocument(body)
+	case termsPath.MatchString(path) && r.Method == http.MethodPost:
+		return s.createAutoSignatureTerm(body)
+	}
+
+	return errorResponse(http.StatusNotFound, "not_found", fmt.Sprintf("Rota não encontrada: %s %s", r.Method, path), "")
+}
+
+func (s *Server) popQueued(method, path string) (queuedResponse, bool) {
+	for i, queued := range s.queued {
+		if queued.method == method && queued.path == path {
+			s.queued = append(s.queued[:i], s.queued[i+1:]...)
+			return queued, true
+		}
+	}
+	return queuedResponse{}, false
+}
+
+// nextID gera IDs determinísticos no formato UUID usado pelo Clicksign
+func (s *Server) nextID() string {
+	s.sequence++
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", s.sequence)
+}
+
+func (s *Server) timestamp() string {
+	return s.now().UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,60 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"app/config"
+)
+
+const (
+	// BackendLibreOffice converte com o LibreOffice em modo headless (soffice)
+	BackendLibreOffice = "libreoffice"
+	// BackendDisabled recusa documentos que precisariam de conversão
+	BackendDisabled = "disabled"
+)
+
+var (
+	// ErrDisabled é retornado quando a conversão está desabilitada em DOCUMENT_CONVERTER
+	ErrDisabled = errors.New("document conversion is disabled")
+	// ErrUnsupportedType é retornado para tipos que o conversor não sabe converter
+	ErrUnsupportedType = errors.New("unsupported source type for conversion")
+)
+
+// Converter converte documentos de escritório e HTML para PDF
+type Converter interface {
+	// ConvertToPDF converte data, do tipo mimeType, e retorna o PDF gerado
+	ConvertToPDF(ctx context.Context, data []byte, mimeType string) ([]byte, error)
+	// Name identifica o conversor no metadata do documento
+	Name() string
+}
+
+// NewConverterFromConfig cria o conversor configurado em DOCUMENT_CONVERTER
+func NewConverterFromConfig(envVars config.EnvironmentVars) (Converter, error) {
+	switch strings.ToLower(strings.TrimSpace(envVars.DOCUMENT_CONVERTER)) {
+	case "", BackendLibreOffice:
+		sandbox, err := NewSandbox(envVars.LIBREOFFICE_SANDBOX)
+		if err != nil {
+			return nil, err
+		}
+		return NewLibreOfficeConverter(envVars.LIBREOFFICE_PATH, time.Duration(envVars.DOCUMENT_CONVERSION_TIMEOUT)*time.Second, sandbox), nil
+	case BackendDisabled:
+		return DisabledConverter{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported document converter: %s", envVars.DOCUMENT_CONVERTER)
+	}
+}
+
+// DisabledConverter recusa toda conversão
+type DisabledConverter struct{}
+
+func (DisabledConverter) ConvertToPDF(ctx context.Context, data []byte, mimeType string) ([]byte, error) {
+	return nil, ErrDisabled
+}
+
+func (DisabledConverter) Name() string {
+	return BackendDisabled
+}
@@ -0,0 +1,205 @@
+package converter
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"app/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const docxMimeType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+
+// writeFakeSoffice cria um executável que imita o soffice: grava um PDF em --outdir com o nome do arquivo de entrada
+func writeFakeSoffice(t *testing.T, script string) string {
+	path := filepath.Join(t.TempDir(), "soffice")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o755))
+	return path
+}
+
+// zipDocument monta um pacote DOCX/ODT com as partes informadas
+func zipDocument(t *testing.T, parts map[string]string) []byte {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for name, content := range parts {
+		part, err := writer.Create(name)
+		require.NoError(t, err)
+		_, err = part.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+// readZipPart lê uma parte do pacote
+func readZipPart(t *testing.T, data []byte, name string) string {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	for _, file := range reader.File {
+		if file.Name == name {
+			content, err := readZipFile(file)
+			require.NoError(t, err)
+			return string(content)
+		}
+	}
+	t.Fatalf("part %s not found", name)
+	return ""
+}
+
+const fakeSofficeScript = `
+while [ $# -gt 0 ]; do
+	case "$1" in
+		--outdir) outdir="$2"; shift ;;
+		-*) ;;
+		*) input="$1" ;;
+	esac
+	shift
+done
+mkdir -p "$outdir"
+name=$(basename "$input")
+printf '%%PDF-1.4\n%s\n' "$name" > "$outdir/${name%.*}.pdf"
+`
+
+// echoSofficeScript grava como "PDF" o próprio arquivo recebido, para conferir o que chega ao soffice
+const echoSofficeScript = `
+while [ $# -gt 0 ]; do
+	case "$1" in
+		--outdir) outdir="$2"; shift ;;
+		-*) ;;
+		*) input="$1" ;;
+	esac
+	shift
+done
+mkdir -p "$outdir"
+name=$(basename "$input")
+cp "$input" "$outdir/${name%.*}.pdf"
+`
+
+func TestLibreOfficeConverter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should convert through soffice and return the PDF", func(t *testing.T) {
+		c := NewLibreOfficeConverter(writeFakeSoffice(t, fakeSofficeScript), time.Second, NoSandbox{})
+
+		pdf, err := c.ConvertToPDF(ctx, zipDocument(t, map[string]string{"word/document.xml": "<w:document/>"}), docxMimeType)
+
+		require.NoError(t, err)
+		assert.Equal(t, "%PDF-1.4\ndocument.docx\n", string(pdf))
+		assert.Equal(t, BackendLibreOffice, c.Name())
+	})
+
+	t.Run("should report soffice failures with its output", func(t *testing.T) {
+		c := NewLibreOfficeConverter(writeFakeSoffice(t, "echo 'Error: source file could not be loaded' >&2\nexit 1\n"), time.Second, NoSandbox{})
+
+		_, err := c.ConvertToPDF(ctx, zipDocument(t, map[string]string{"word/document.xml": "<w:document/>"}), docxMimeType)
+
+		assert.ErrorContains(t, err, "source file could not be loaded")
+	})
+
+	t.Run("should fail when no PDF is produced", func(t *testing.T) {
+		c := NewLibreOfficeConverter(writeFakeSoffice(t, "exit 0\n"), time.Second, NoSandbox{})
+
+		_, err := c.ConvertToPDF(ctx, []byte("<html></html>"), "text/html")
+
+		assert.ErrorContains(t, err, "produced no PDF")
+	})
+
+	t.Run("should stop slow conversions", func(t *testing.T) {
+		c := NewLibreOfficeConverter(writeFakeSoffice(t, "exec sleep 5\n"), 100*time.Millisecond, NoSandbox{})
+
+		_, err := c.ConvertToPDF(ctx, zipDocument(t, map[string]string{"content.xml": "<office:document-content/>"}), "application/vnd.oasis.opendocument.text")
+
+		assert.ErrorContains(t, err, "timed out")
+	})
+
+	t.Run("should kill the child processes of soffice on timeout", func(t *testing.T) {
+		pidFile := filepath.Join(t.TempDir(), "child.pid")
+		c := NewLibreOfficeConverter(writeFakeSoffice(t, fmt.Sprintf("sleep 30 &\necho $! > %s\nwait\n", pidFile)), 200*time.Millisecond, NoSandbox{})
+
+		started := time.Now()
+		_, err := c.ConvertToPDF(ctx, []byte("<html></html>"), "text/html")
+
+		assert.ErrorContains(t, err, "timed out")
+		// O filho herdaria a saída do soffice e seguraria ConvertToPDF até terminar
+		assert.Less(t, time.Since(started), 5*time.Second)
+
+		content, err := os.ReadFile(pidFile)
+		require.NoError(t, err)
+		pid, err := strconv.Atoi(strings.TrimSpace(string(content)))
+		require.NoError(t, err)
+		assert.Eventually(t, func() bool {
+			stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+			// Morto: já recolhido ou zumbi aguardando o init
+			return err != nil || strings.Contains(string(stat), ") Z ")
+		}, 2*time.Second, 20*time.Millisecond)
+	})
+
+	t.Run("should not pass local file references to soffice", func(t *testing.T) {
+		c := NewLibreOfficeConverter(writeFakeSoffice(t, echoSofficeScript), time.Second, NoSandbox{})
+
+		converted, err := c.ConvertToPDF(ctx, []byte(`<html><body><p>Contrato</p><img src="file:///etc/passwd"></body></html>`), "text/html")
+
+		require.NoError(t, err)
+		assert.NotContains(t, string(converted), "/etc/passwd")
+		assert.Contains(t, string(converted), "<p>Contrato</p>")
+		assert.Contains(t, string(converted), "<img>")
+	})
+
+	t.Run("should reject unsupported types", func(t *testing.T) {
+		c := NewLibreOfficeConverter("", 0, nil)
+
+		_, err := c.ConvertToPDF(ctx, []byte("nome,valor"), "text/csv")
+
+		assert.ErrorIs(t, err, ErrUnsupportedType)
+	})
+}
+
+func TestBubblewrapSandbox(t *testing.T) {
+	command := BubblewrapSandbox{}.Wrap("/tmp/docsigner_convert_1", []string{"/opt/libreoffice/program/soffice", "--headless"})
+	args := strings.Join(command, " ")
+
+	assert.Equal(t, "bwrap", command[0])
+	assert.Contains(t, args, "--unshare-all")
+	assert.Contains(t, args, "--die-with-parent")
+	assert.Contains(t, args, "--bind /tmp/docsigner_convert_1 /tmp/docsigner_convert_1")
+	assert.Contains(t, args, "--ro-bind-try /opt/libreoffice /opt/libreoffice")
+	assert.Contains(t, args, "--ro-bind-try /etc/fonts /etc/fonts")
+	// /etc inteiro e a raiz do host não entram no sandbox
+	assert.NotContains(t, args, "/etc /etc")
+	assert.NotContains(t, args, "--bind / /")
+	assert.Equal(t, []string{"--", "/opt/libreoffice/program/soffice", "--headless"}, command[len(command)-3:])
+}
+
+func TestNewConverterFromConfig(t *testing.T) {
+	c, err := NewConverterFromConfig(config.EnvironmentVars{DOCUMENT_CONVERTER: "libreoffice", LIBREOFFICE_PATH: "/opt/libreoffice/program/soffice", DOCUMENT_CONVERSION_TIMEOUT: 30})
+	require.NoError(t, err)
+	require.IsType(t, &LibreOfficeConverter{}, c)
+	assert.Equal(t, "/opt/libreoffice/program/soffice", c.(*LibreOfficeConverter).binary)
+	assert.Equal(t, 30*time.Second, c.(*LibreOfficeConverter).timeout)
+	assert.Equal(t, BubblewrapSandbox{}, c.(*LibreOfficeConverter).sandbox)
+
+	c, err = NewConverterFromConfig(config.EnvironmentVars{DOCUMENT_CONVERTER: "libreoffice", LIBREOFFICE_SANDBOX: "none"})
+	require.NoError(t, err)
+	assert.Equal(t, NoSandbox{}, c.(*LibreOfficeConverter).sandbox)
+
+	_, err = NewConverterFromConfig(config.EnvironmentVars{DOCUMENT_CONVERTER: "libreoffice", LIBREOFFICE_SANDBOX: "docker"})
+	assert.ErrorContains(t, err, "unsupported libreoffice sandbox")
+
+	c, err = NewConverterFromConfig(config.EnvironmentVars{DOCUMENT_CONVERTER: "disabled"})
+	require.NoError(t, err)
+	_, err = c.ConvertToPDF(context.Background(), []byte("PK"), docxMimeType)
+	assert.ErrorIs(t, err, ErrDisabled)
+
+	_, err = NewConverterFromConfig(config.EnvironmentVars{DOCUMENT_CONVERTER: "pandoc"})
+	assert.ErrorContains(t, err, "unsupported document converter")
+}
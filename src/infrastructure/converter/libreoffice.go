@@ -0,0 +1,125 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultLibreOfficeBinary  = "soffice"
+	defaultConversionTimeout  = 120 * time.Second
+	maxConversionOutputLength = 2048
+	// processKillGrace é quanto ConvertToPDF espera pela saída dos processos depois de matar o grupo
+	processKillGrace = 2 * time.Second
+)
+
+// sourceExtensions define a extensão do arquivo de entrada; o LibreOffice escolhe o filtro de importação por ela
+var sourceExtensions = map[string]string{
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document": ".docx",
+	"application/vnd.oasis.opendocument.text":                                 ".odt",
+	"text/html": ".html",
+}
+
+// LibreOfficeConverter converte documentos executando o LibreOffice headless em um diretório temporário
+// Cada conversão usa um perfil próprio, o que permite conversões concorrentes
+// As referências externas do documento são removidas antes da conversão e o soffice roda dentro do sandbox
+type LibreOfficeConverter struct {
+	binary  string
+	timeout time.Duration
+	sandbox Sandbox
+}
+
+// NewLibreOfficeConverter cria o conversor; binary vazio usa "soffice" do PATH e sandbox nil usa o bubblewrap
+func NewLibreOfficeConverter(binary string, timeout time.Duration, sandbox Sandbox) *LibreOfficeConverter {
+	if strings.TrimSpace(binary) == "" {
+		binary = defaultLibreOfficeBinary
+	}
+	if timeout <= 0 {
+		timeout = defaultConversionTimeout
+	}
+	if sandbox == nil {
+		sandbox = BubblewrapSandbox{}
+	}
+
+	return &LibreOfficeConverter{binary: binary, timeout: timeout, sandbox: sandbox}
+}
+
+func (c *LibreOfficeConverter) Name() string {
+	return BackendLibreOffice
+}
+
+func (c *LibreOfficeConverter) ConvertToPDF(ctx context.Context, data []byte, mimeType string) ([]byte, error) {
+	extension, ok := sourceExtensions[mimeType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, mimeType)
+	}
+
+	workDir, err := os.MkdirTemp("", "docsigner_convert_*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create conversion directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	data, err = stripExternalReferences(data, mimeType)
+	if err != nil {
+		return nil, err
+	}
+
+	inputPath := filepath.Join(workDir, "document"+extension)
+	if err := os.WriteFile(inputPath, data, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write conversion input: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	outputDir := filepath.Join(workDir, "out")
+	command := c.sandbox.Wrap(workDir, []string{c.binary,
+		"-env:UserInstallation=file://" + filepath.ToSlash(filepath.Join(workDir, "profile")),
+		"--headless",
+		"--norestore",
+		"--nolockcheck",
+		"--convert-to", "pdf",
+		"--outdir", outputDir,
+		inputPath,
+	})
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	// O soffice cria processos filhos (oosplash, soffice.bin): no timeout o grupo inteiro é morto
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = processKillGrace
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("conversion timed out after %s", c.timeout)
+		}
+		return nil, fmt.Errorf("libreoffice conversion failed: %w: %s", err, truncate(output.String()))
+	}
+
+	pdf, err := os.ReadFile(filepath.Join(outputDir, "document.pdf"))
+	if err != nil {
+		return nil, fmt.Errorf("libreoffice produced no PDF: %s", truncate(output.String()))
+	}
+
+	return pdf, nil
+}
+
+func truncate(output string) string {
+	output = strings.TrimSpace(output)
+	if len(output) > maxConversionOutputLength {
+		return output[:maxConversionOutputLength] + "..."
+	}
+	return output
+}
@@ -0,0 +1,85 @@
+package converter
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// SandboxBubblewrap executa o soffice com o bubblewrap (bwrap), sem rede e com um sistema de arquivos mínimo
+	SandboxBubblewrap = "bwrap"
+	// SandboxNone executa o soffice diretamente; use apenas quando o próprio container já isola a conversão
+	SandboxNone = "none"
+
+	defaultBubblewrapBinary = "bwrap"
+)
+
+// Sandbox isola o processo de conversão
+type Sandbox interface {
+	// Wrap recebe o comando do conversor e retorna o comando a executar; workDir é o único diretório gravável
+	Wrap(workDir string, command []string) []string
+}
+
+// NewSandbox cria o sandbox configurado em LIBREOFFICE_SANDBOX
+func NewSandbox(name string) (Sandbox, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", SandboxBubblewrap:
+		return BubblewrapSandbox{}, nil
+	case SandboxNone:
+		return NoSandbox{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported libreoffice sandbox: %s", name)
+	}
+}
+
+// NoSandbox executa o comando sem isolamento
+type NoSandbox struct{}
+
+func (NoSandbox) Wrap(workDir string, command []string) []string {
+	return command
+}
+
+// BubblewrapSandbox executa o comando em namespaces próprios (rede, PID, IPC, usuário), enxergando apenas as
+// bibliotecas do sistema, as fontes e o diretório da conversão; arquivos como /etc/passwd, segredos montados e o
+// código da aplicação ficam fora do sandbox
+type BubblewrapSandbox struct {
+	// Binary é o executável do bubblewrap; vazio usa "bwrap" do PATH
+	Binary string
+}
+
+// bubblewrapReadOnlyPaths são montados somente leitura quando existem no host
+var bubblewrapReadOnlyPaths = []string{"/usr", "/lib", "/lib64", "/bin", "/sbin", "/opt", "/etc/fonts", "/etc/alternatives", "/etc/ld.so.cache"}
+
+func (s BubblewrapSandbox) Wrap(workDir string, command []string) []string {
+	binary := s.Binary
+	if binary == "" {
+		binary = defaultBubblewrapBinary
+	}
+
+	args := []string{binary,
+		"--unshare-all",
+		"--die-with-parent",
+		"--new-session",
+		"--clearenv",
+		"--setenv", "PATH", "/usr/bin:/bin",
+		"--setenv", "HOME", workDir,
+	}
+	readOnly := append([]string{}, bubblewrapReadOnlyPaths...)
+	// Um soffice instalado fora dos caminhos do sistema precisa do seu diretório de instalação
+	if filepath.IsAbs(command[0]) {
+		readOnly = append(readOnly, filepath.Dir(filepath.Dir(command[0])))
+	}
+	for _, path := range readOnly {
+		args = append(args, "--ro-bind-try", path, path)
+	}
+	args = append(args,
+		"--proc", "/proc",
+		"--dev", "/dev",
+		"--tmpfs", "/tmp",
+		"--bind", workDir, workDir,
+		"--chdir", workDir,
+		"--",
+	)
+	return append(args, command...)
+}
@@ -0,0 +1,258 @@
+package converter
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// O LibreOffice segue referências externas dos documentos (imagens e seções vinculadas, objetos OLE, campos
+// INCLUDETEXT, folhas de estilo) durante a conversão, o que permitiria ler arquivos locais ou acessar a rede
+// interna sem passar pelo fetcher. stripExternalReferences remove essas referências antes de converter;
+// apenas conteúdo embutido no próprio documento (data: URIs e partes do pacote) é mantido.
+
+// htmlURLAttributes são os atributos HTML que fazem o conversor carregar um recurso
+var htmlURLAttributes = map[string]bool{
+	"src": true, "srcset": true, "href": true, "background": true, "poster": true, "data": true,
+	"action": true, "formaction": true, "cite": true, "longdesc": true, "lowsrc": true, "dynsrc": true,
+	"codebase": true, "archive": true, "classid": true, "usemap": true, "profile": true, "manifest": true,
+	"xlink:href": true,
+}
+
+// htmlDroppedElements são removidos com seus atributos; o conteúdo de script também é descartado
+var htmlDroppedElements = map[string]bool{
+	"base": true, "link": true, "iframe": true, "frame": true, "frameset": true, "object": true,
+	"embed": true, "applet": true, "script": true, "meta": true, "portal": true,
+}
+
+var (
+	cssURLPattern    = regexp.MustCompile(`(?i)url\(\s*(?:"[^"]*"|'[^']*'|[^)]*)\s*\)`)
+	cssImportPattern = regexp.MustCompile(`(?i)@import\s+[^;]*;?`)
+
+	// Campos do Word que incluem arquivos ou vínculos externos no documento
+	docxFieldPattern       = regexp.MustCompile(`(?i)\b(INCLUDETEXT|INCLUDEPICTURE|INCLUDE|IMPORT|LINK|DDE|DDEAUTO|HYPERLINK)\b|[a-z][a-z0-9+.-]*:[/\\]|\\\\`)
+	docxInstrTextPattern   = regexp.MustCompile(`(?s)(<w:instrText\b[^>]*>)(.*?)(</w:instrText>)`)
+	docxInstrAttrPattern   = regexp.MustCompile(`(\bw:instr=")([^"]*)(")`)
+	relationshipPattern    = regexp.MustCompile(`<Relationship\b[^>]*?/?>`)
+	relationshipTarget     = regexp.MustCompile(`\bTarget="[^"]*"`)
+	xmlStartTagPattern     = regexp.MustCompile(`<([A-Za-z_][\w.-]*:[\w.-]+)\b[^>]*>`)
+	odfReferenceAttributes = regexp.MustCompile(`\b(xlink:href|form:image-data|form:href)="([^"]*)"`)
+)
+
+// stripExternalReferences retorna uma cópia do documento sem referências a recursos externos
+func stripExternalReferences(data []byte, mimeType string) ([]byte, error) {
+	switch mimeType {
+	case "text/html":
+		return stripHTMLReferences(data)
+	case "application/vnd.openxmlformats-officedocument.wordprocessingml.document":
+		return rewriteZipParts(data, stripDOCXPart)
+	case "application/vnd.oasis.opendocument.text":
+		return rewriteZipParts(data, stripODFPart)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, mimeType)
+}
+
+// isExternalReference indica se o valor aponta para fora do documento; data: URIs e âncoras são internos
+func isExternalReference(value string) bool {
+	value = strings.TrimSpace(html.UnescapeString(value))
+	if value == "" || strings.HasPrefix(value, "#") || strings.HasPrefix(strings.ToLower(value), "data:") {
+		return false
+	}
+	if strings.HasPrefix(value, "/") || strings.HasPrefix(value, `\`) || strings.Contains(value, "..") {
+		return true
+	}
+	parsed, err := url.Parse(value)
+	return err != nil || parsed.Scheme != "" || parsed.Host != ""
+}
+
+func stripCSS(css string) string {
+	css = cssImportPattern.ReplaceAllString(css, "")
+	return cssURLPattern.ReplaceAllStringFunc(css, func(match string) string {
+		inner := strings.Trim(strings.TrimSpace(match[4:len(match)-1]), `"'`)
+		if isExternalReference(inner) {
+			return "none"
+		}
+		return match
+	})
+}
+
+func stripHTMLReferences(data []byte) ([]byte, error) {
+	var out bytes.Buffer
+	tokenizer := html.NewTokenizer(bytes.NewReader(data))
+	inScript, inStyle := false, false
+
+	for {
+		tokenType := tokenizer.Next()
+		switch tokenType {
+		case html.ErrorToken:
+			if tokenizer.Err() == io.EOF {
+				return out.Bytes(), nil
+			}
+			return nil, fmt.Errorf("failed to parse HTML: %w", tokenizer.Err())
+
+		case html.StartTagToken, html.SelfClosingTagToken, html.EndTagToken:
+			token := tokenizer.Token()
+			if token.Data == "script" {
+				inScript = tokenType == html.StartTagToken
+			}
+			if token.Data == "style" {
+				inStyle = tokenType == html.StartTagToken
+			}
+			if htmlDroppedElements[token.Data] {
+				continue
+			}
+			if tokenType == html.EndTagToken {
+				out.Write(tokenizer.Raw())
+				continue
+			}
+
+			attributes := token.Attr[:0]
+			for _, attribute := range token.Attr {
+				name := strings.ToLower(attribute.Key)
+				if attribute.Namespace != "" {
+					name = attribute.Namespace + ":" + name
+				}
+				switch {
+				case strings.HasPrefix(name, "on"):
+					continue
+				case name == "style":
+					attribute.Val = stripCSS(attribute.Val)
+				case name == "srcset":
+					continue
+				case htmlURLAttributes[name] && isExternalReference(attribute.Val):
+					// Hyperlinks não são seguidos pelo conversor e continuam clicáveis no PDF
+					if token.Data == "a" && name == "href" && isHyperlink(attribute.Val) {
+						break
+					}
+					continue
+				}
+				attributes = append(attributes, attribute)
+			}
+			token.Attr = attributes
+			out.WriteString(token.String())
+
+		case html.TextToken:
+			switch {
+			case inScript:
+			case inStyle:
+				out.WriteString(stripCSS(string(tokenizer.Raw())))
+			default:
+				out.Write(tokenizer.Raw())
+			}
+
+		default:
+			out.Write(tokenizer.Raw())
+		}
+	}
+}
+
+func isHyperlink(value string) bool {
+	parsed, err := url.Parse(strings.TrimSpace(value))
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "http", "https", "mailto":
+		return true
+	}
+	return false
+}
+
+// rewriteZipParts regrava o pacote (DOCX/ODT) aplicando rewrite às partes XML
+func rewriteZipParts(data []byte, rewrite func(name string, content []byte) []byte) ([]byte, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open document package: %w", err)
+	}
+
+	var out bytes.Buffer
+	writer := zip.NewWriter(&out)
+	for _, file := range reader.File {
+		content, err := readZipFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasSuffix(file.Name, ".xml") || strings.HasSuffix(file.Name, ".rels") {
+			content = rewrite(file.Name, content)
+		}
+
+		header := file.FileHeader
+		// O tamanho e o CRC mudam com a regravação; o mimetype do ODF precisa continuar sem compressão
+		partWriter, err := writer.CreateHeader(&zip.FileHeader{Name: header.Name, Method: header.Method, Modified: header.Modified})
+		if err != nil {
+			return nil, fmt.Errorf("failed to write document package: %w", err)
+		}
+		if _, err := partWriter.Write(content); err != nil {
+			return nil, fmt.Errorf("failed to write document package: %w", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write document package: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+func readZipFile(file *zip.File) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read document part %s: %w", file.Name, err)
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// stripDOCXPart esvazia os alvos externos das relações (exceto hyperlinks) e os campos que incluem arquivos
+func stripDOCXPart(name string, content []byte) []byte {
+	if strings.HasSuffix(name, ".rels") {
+		return relationshipPattern.ReplaceAllFunc(content, func(relationship []byte) []byte {
+			if !bytes.Contains(relationship, []byte(`TargetMode="External"`)) {
+				return relationship
+			}
+			if bytes.Contains(relationship, []byte(`/relationships/hyperlink"`)) {
+				if target := relationshipTarget.Find(relationship); target != nil && isHyperlink(html.UnescapeString(string(target[8:len(target)-1]))) {
+					return relationship
+				}
+			}
+			return relationshipTarget.ReplaceAll(relationship, []byte(`Target=""`))
+		})
+	}
+
+	content = docxInstrTextPattern.ReplaceAllFunc(content, func(match []byte) []byte {
+		parts := docxInstrTextPattern.FindSubmatch(match)
+		if !docxFieldPattern.Match(parts[2]) {
+			return match
+		}
+		return append(append([]byte{}, parts[1]...), parts[3]...)
+	})
+	return docxInstrAttrPattern.ReplaceAllFunc(content, func(match []byte) []byte {
+		parts := docxInstrAttrPattern.FindSubmatch(match)
+		if !docxFieldPattern.Match(parts[2]) {
+			return match
+		}
+		return append(append([]byte{}, parts[1]...), parts[3]...)
+	})
+}
+
+// stripODFPart esvazia as referências externas dos elementos ODF; links de texto (text:a, draw:a) são mantidos
+func stripODFPart(name string, content []byte) []byte {
+	return xmlStartTagPattern.ReplaceAllFunc(content, func(tag []byte) []byte {
+		element := string(xmlStartTagPattern.FindSubmatch(tag)[1])
+		return odfReferenceAttributes.ReplaceAllFunc(tag, func(attribute []byte) []byte {
+			parts := odfReferenceAttributes.FindSubmatch(attribute)
+			value := string(parts[2])
+			if !isExternalReference(value) {
+				return attribute
+			}
+			if (element == "text:a" || element == "draw:a") && isHyperlink(html.UnescapeString(value)) {
+				return attribute
+			}
+			return []byte(string(parts[1]) + `=""`)
+		})
+	})
+}
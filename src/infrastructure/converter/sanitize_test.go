@@ -0,0 +1,94 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStripExternalReferences(t *testing.T) {
+	t.Run("should strip external resources from HTML", func(t *testing.T) {
+		input := `<html><head>
+<link rel="stylesheet" href="http://10.0.0.1/style.css">
+<base href="file:///etc/">
+<style>@import url("http://169.254.169.254/");
+body { background: url('file:///etc/shadow'); } p > b { color: red }</style>
+<script>fetch("http://internal")</script>
+</head><body background="http://internal/bg.png">
+<img src="file:///etc/passwd" alt="x"><img src="data:image/png;base64,AAAA">
+<iframe src="http://169.254.169.254/latest/meta-data"></iframe>
+<object data="file:///etc/hosts"></object>
+<p style="background-image:url(//internal/x.png)">Contrato</p>
+<a href="https://example.com/termos">termos</a><a href="file:///etc/passwd">local</a>
+<svg><image xlink:href="file:///etc/passwd"/></svg>
+</body></html>`
+
+		output, err := stripExternalReferences([]byte(input), "text/html")
+
+		require.NoError(t, err)
+		html := string(output)
+		for _, forbidden := range []string{"file:", "10.0.0.1", "169.254.169.254", "//internal", "http://internal", "<link", "<base", "<iframe", "<object", "<script", "@import"} {
+			assert.NotContains(t, html, forbidden)
+		}
+		assert.Contains(t, html, `<img alt="x">`)
+		assert.Contains(t, html, `data:image/png;base64,AAAA`)
+		assert.Contains(t, html, `<a href="https://example.com/termos">`)
+		assert.Contains(t, html, `p > b { color: red }`)
+		assert.Contains(t, html, "Contrato")
+	})
+
+	t.Run("should strip external relationships and include fields from DOCX", func(t *testing.T) {
+		docx := zipDocument(t, map[string]string{
+			"word/_rels/document.xml.rels": `<Relationships>` +
+				`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/image" Target="media/image1.png"/>` +
+				`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/image" Target="file:///etc/passwd" TargetMode="External"/>` +
+				`<Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="https://example.com" TargetMode="External"/>` +
+				`<Relationship Id="rId4" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="file:///etc/passwd" TargetMode="External"/>` +
+				`</Relationships>`,
+			"word/document.xml": `<w:document><w:r><w:instrText xml:space="preserve"> INCLUDETEXT "/etc/passwd" </w:instrText></w:r>` +
+				`<w:r><w:instrText> PAGE </w:instrText></w:r><w:fldSimple w:instr="INCLUDEPICTURE http://10.0.0.1/x.png"/></w:document>`,
+		})
+
+		output, err := stripExternalReferences(docx, docxMimeType)
+
+		require.NoError(t, err)
+		rels := readZipPart(t, output, "word/_rels/document.xml.rels")
+		assert.Contains(t, rels, `Target="media/image1.png"`)
+		assert.Contains(t, rels, `Target="https://example.com"`)
+		assert.NotContains(t, rels, "/etc/passwd")
+		document := readZipPart(t, output, "word/document.xml")
+		assert.NotContains(t, document, "/etc/passwd")
+		assert.NotContains(t, document, "10.0.0.1")
+		assert.Contains(t, document, "PAGE")
+	})
+
+	t.Run("should strip external links from ODT but keep embedded objects and text links", func(t *testing.T) {
+		odt := zipDocument(t, map[string]string{
+			"mimetype": "application/vnd.oasis.opendocument.text",
+			"content.xml": `<office:document-content>` +
+				`<draw:image xlink:href="Pictures/logo.png"/>` +
+				`<draw:image xlink:href="file:///etc/passwd"/>` +
+				`<text:section-source xlink:href="../../etc/passwd"/>` +
+				`<draw:object xlink:href="./Object 1"/>` +
+				`<text:a xlink:href="https://example.com">site</text:a>` +
+				`</office:document-content>`,
+		})
+
+		output, err := stripExternalReferences(odt, "application/vnd.oasis.opendocument.text")
+
+		require.NoError(t, err)
+		content := readZipPart(t, output, "content.xml")
+		assert.NotContains(t, content, "etc/passwd")
+		assert.Contains(t, content, `xlink:href="Pictures/logo.png"`)
+		assert.Contains(t, content, `xlink:href="./Object 1"`)
+		assert.Contains(t, content, `xlink:href="https://example.com"`)
+		assert.Equal(t, "application/vnd.oasis.opendocument.text", readZipPart(t, output, "mimetype"))
+	})
+
+	t.Run("should reject packages that are not zip files", func(t *testing.T) {
+		_, err := stripExternalReferences([]byte("PK docx"), docxMimeType)
+
+		assert.ErrorContains(t, err, "failed to open document package")
+	})
+}
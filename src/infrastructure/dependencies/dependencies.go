@@ -0,0 +1,101 @@
+package dependencies
+
+import (
+	"fmt"
+
+	"app/config"
+	"app/infrastructure/converter"
+	"app/infrastructure/jwtkeys"
+	"app/infrastructure/normalizer"
+	"app/infrastructure/oidc"
+	"app/infrastructure/ratelimit"
+	"app/infrastructure/scanner"
+	"app/infrastructure/storage"
+	"app/pkg/fetcher"
+	custom_logger "app/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Dependencies reúne a infraestrutura criada uma vez na inicialização e repassada aos handlers, ao cron e ao kafka
+type Dependencies struct {
+	Logger   *logrus.Logger
+	Redactor *custom_logger.Redactor
+
+	// Rate limiting por IP e por cliente; LoginAttempts é nil quando o bloqueio de login está desligado
+	Limiter       *ratelimit.Limiter
+	LoginAttempts *ratelimit.LoginAttempts
+
+	// Chaves dos tokens de usuário e verificador OIDC, nil quando OIDC_ENABLED está desligado
+	TokenKeys *jwtkeys.KeySet
+	Identity  *oidc.Verifier
+
+	// Ingestão e download de documentos
+	Storage    storage.Storage
+	Converter  converter.Converter
+	Normalizer *normalizer.Normalizer
+	Scanner    scanner.Scanner
+	Fetcher    *fetcher.Fetcher
+}
+
+// NewDependenciesFromConfig cria a infraestrutura a partir das variáveis de ambiente
+// Qualquer configuração inválida retorna erro: a inicialização deve ser interrompida em vez de seguir com um fallback
+func NewDependenciesFromConfig(envVars config.EnvironmentVars, conn *gorm.DB, redactor *custom_logger.Redactor) (*Dependencies, error) {
+	deps := &Dependencies{
+		Logger:   custom_logger.NewLogrusLogger(envVars.LogLevel, redactor),
+		Redactor: redactor,
+	}
+
+	// Os contadores ficam na memória ou no Postgres
+	rateLimitStore, err := ratelimit.NewStoreFromConfig(envVars, conn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure rate limit store: %w", err)
+	}
+	deps.Limiter, err = ratelimit.NewLimiterFromConfig(envVars, rateLimitStore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure rate limits: %w", err)
+	}
+	deps.LoginAttempts = ratelimit.NewLoginAttemptsFromConfig(envVars, rateLimitStore)
+
+	deps.TokenKeys, err = jwtkeys.NewKeySetFromConfig(envVars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure jwt signing keys: %w", err)
+	}
+
+	// SSO: tokens do provedor OIDC aceitos junto com os tokens locais
+	if envVars.OIDC_ENABLED {
+		deps.Identity, err = oidc.NewVerifierFromConfig(envVars)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure OIDC: %w", err)
+		}
+	}
+
+	deps.Storage, err = storage.NewStorageFromConfig(envVars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure document storage: %w", err)
+	}
+
+	deps.Converter, err = converter.NewConverterFromConfig(envVars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure document converter: %w", err)
+	}
+
+	deps.Normalizer, err = normalizer.NewNormalizerFromConfig(envVars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure document normalizer: %w", err)
+	}
+
+	deps.Scanner, err = scanner.NewScannerFromConfig(envVars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure document scanner: %w", err)
+	}
+
+	// Downloads de URLs informadas pelos clientes, com bloqueio da rede interna
+	deps.Fetcher, err = fetcher.NewFetcherFromConfig(envVars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure URL fetcher: %w", err)
+	}
+
+	return deps, nil
+}
@@ -0,0 +1,244 @@
+package fake_provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"app/config"
+	"app/entity"
+	"app/infrastructure/provider"
+	"app/infrastructure/storage"
+	"app/pkg/fetcher"
+	"app/pkg/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ProviderName é o nome usado para selecionar o provider fake no ProviderFactory
+const ProviderName = "fake"
+
+// ErrDisabled indica que o provider fake não foi habilitado via FAKE_PROVIDER_ENABLED
+var ErrDisabled = errors.New("fake provider is disabled (set FAKE_PROVIDER_ENABLED=true)")
+
+// Capabilities descreve as funcionalidades suportadas pelo provider fake
+// Aceita tudo o que os providers reais aceitam, para permitir testar qualquer fluxo offline
+var Capabilities = provider.Capabilities{
+	Name:                      ProviderName,
+	AuthMethods:               []string{"email", "icp_brasil", "auto_signature"},
+	Actions:                   []string{"agree", "sign", "provide_evidence"},
+	SupportsNotify:            true,
+	SupportsCancel:            false,
+	SupportsSequentialSigning: true,
+	SupportsDocumentReplace:   true,
+	NotifiesSignersOnCreate:   false,
+	MaxFileSize:               int64(utils.MaxFileSize),
+	MimeTypes:                 []string{"application/pdf", "image/jpeg", "image/jpg", "image/png", "image/gif"},
+}
+
+var registerOnce sync.Once
+
+// Register adiciona o provider fake ao registro de providers; chamado na inicialização apenas com
+// FAKE_PROVIDER_ENABLED, para que em produção ele não apareça na listagem, na validação nem no roteamento "auto"
+func Register() {
+	registerOnce.Do(func() {
+		provider.Register(ProviderName, NewFakeProviderFromConfig, Capabilities)
+	})
+}
+
+// FakeProvider implementa a interface EnvelopeProvider armazenando os envelopes em memória
+// Destinado a desenvolvimento local e testes de integração, sem chamadas a APIs externas
+type FakeProvider struct {
+	store  *Store
+	logger *logrus.Logger
+}
+
+// NewFakeProvider cria uma nova instância do FakeProvider sobre o Store informado
+func NewFakeProvider(store *Store, logger *logrus.Logger) provider.EnvelopeProvider {
+	return &FakeProvider{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// NewFakeProviderFromConfig cria o provider fake sobre o Store global
+// Retorna ErrDisabled quando FAKE_PROVIDER_ENABLED não está habilitado; credenciais são ignoradas
+func NewFakeProviderFromConfig(envVars config.EnvironmentVars, credentials *provider.Credentials, documentStorage storage.Storage, documentFetcher *fetcher.Fetcher, logger *logrus.Logger) (provider.EnvelopeProvider, error) {
+	if !envVars.FAKE_PROVIDER_ENABLED {
+		return nil, ErrDisabled
+	}
+
+	return NewFakeProvider(DefaultStore(), logger), nil
+}
+
+// CreateEnvelope cria um envelope em rascunho no Store
+func (p *FakeProvider) CreateEnvelope(ctx context.Context, envelope *entity.EntityEnvelope) (string, string, error) {
+	created := p.store.createEnvelope(Envelope{
+		EnvelopeID: envelope.ID,
+		Name:       envelope.Name,
+		AutoClose:  envelope.AutoClose,
+		DeadlineAt: envelope.DeadlineAt,
+	})
+
+	rawData, err := json.Marshal(created)
+	if err != nil {
+		p.logger.Warnf("Failed to marshal fake envelope: %v", err)
+		rawData = []byte("{}")
+	}
+
+	p.logger.WithFields(logrus.Fields{
+		"envelope_id":  envelope.ID,
+		"envelope_key": created.Key,
+	}).Debug("Fake envelope created")
+
+	return created.Key, string(rawData), nil
+}
+
+// CreateDocument adiciona um documento ao envelope fake
+func (p *FakeProvider) CreateDocument(ctx context.Context, envelopeKey string, document *entity.EntityDocument, internalEnvelopeID int) (string, error) {
+	documentKey := newKey("fake-doc")
+	_, err := p.store.update(envelopeKey, func(envelope *Envelope) error {
+		if envelope.Status != StatusDraft {
+			return fmt.Errorf("cannot add document to fake envelope in status %s", envelope.Status)
+		}
+		envelope.Documents = append(envelope.Documents, Document{
+			Key:        documentKey,
+			DocumentID: document.ID,
+			Filename:   document.Name,
+		})
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return documentKey, nil
+}
+
+// DeleteDocument remove um documento do envelope fake junto com os requisitos que o referenciam, como o Clicksign
+func (p *FakeProvider) DeleteDocument(ctx context.Context, envelopeKey string, documentKey string) error {
+	_, err := p.store.update(envelopeKey, func(envelope *Envelope) error {
+		if envelope.Status != StatusDraft {
+			return fmt.Errorf("cannot delete document from fake envelope in status %s", envelope.Status)
+		}
+		if !envelope.hasDocument(documentKey) {
+			return fmt.Errorf("fake document not found in envelope: %s", documentKey)
+		}
+
+		documents := envelope.Documents[:0]
+		for _, document := range envelope.Documents {
+			if document.Key != documentKey {
+				documents = append(documents, document)
+			}
+		}
+		envelope.Documents = documents
+
+		requirements := envelope.Requirements[:0]
+		for _, requirement := range envelope.Requirements {
+			if requirement.DocumentKey != documentKey {
+				requirements = append(requirements, requirement)
+			}
+		}
+		envelope.Requirements = requirements
+		return nil
+	})
+	return err
+}
+
+// CreateSigner adiciona um signatário ao envelope fake
+func (p *FakeProvider) CreateSigner(ctx context.Context, envelopeKey string, signerData provider.SignerData) (string, error) {
+	signerKey := newKey("fake-signer")
+	_, err := p.store.update(envelopeKey, func(envelope *Envelope) error {
+		if envelope.Status != StatusDraft {
+			return fmt.Errorf("cannot add signer to fake envelope in status %s", envelope.Status)
+		}
+		envelope.Signers = append(envelope.Signers, Signer{
+			Key:   signerKey,
+			Name:  signerData.Name,
+			Email: signerData.Email,
+			Group: signerData.Group,
+			Auth:  signerData.AuthMethod,
+		})
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return signerKey, nil
+}
+
+// CreateRequirement adiciona um requisito ao envelope fake
+// O documento e o signatário referenciados precisam existir no envelope
+func (p *FakeProvider) CreateRequirement(ctx context.Context, envelopeKey string, reqData provider.RequirementData) (string, error) {
+	requirementKey := newKey("fake-req")
+	_, err := p.store.update(envelopeKey, func(envelope *Envelope) error {
+		if !envelope.hasDocument(reqData.DocumentID) {
+			return fmt.Errorf("fake document not found in envelope: %s", reqData.DocumentID)
+		}
+		if !envelope.hasSigner(reqData.SignerID) {
+			return fmt.Errorf("fake signer not found in envelope: %s", reqData.SignerID)
+		}
+		envelope.Requirements = append(envelope.Requirements, Requirement{
+			Key:         requirementKey,
+			Action:      reqData.Action,
+			Role:        reqData.Role,
+			Auth:        reqData.Auth,
+			DocumentKey: reqData.DocumentID,
+			SignerKey:   reqData.SignerID,
+		})
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return requirementKey, nil
+}
+
+// ActivateEnvelope coloca o envelope fake em andamento
+func (p *FakeProvider) ActivateEnvelope(ctx context.Context, envelopeKey string) error {
+	_, err := p.store.update(envelopeKey, func(envelope *Envelope) error {
+		if envelope.Status != StatusDraft {
+			return fmt.Errorf("cannot activate fake envelope in status %s", envelope.Status)
+		}
+		if len(envelope.Documents) == 0 || len(envelope.Signers) == 0 {
+			return fmt.Errorf("fake envelope requires at least one document and one signer to be activated")
+		}
+		envelope.Status = StatusRunning
+		return nil
+	})
+	return err
+}
+
+// NotifyEnvelope registra uma notificação aos signatários do envelope fake
+func (p *FakeProvider) NotifyEnvelope(ctx context.Context, envelopeKey string, message string) error {
+	_, err := p.store.update(envelopeKey, func(envelope *Envelope) error {
+		if envelope.Status != StatusRunning {
+			return fmt.Errorf("cannot notify fake envelope in status %s", envelope.Status)
+		}
+		envelope.Notifications++
+		return nil
+	})
+	return err
+}
+
+func (e *Envelope) hasDocument(documentKey string) bool {
+	for _, document := range e.Documents {
+		if document.Key == documentKey {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Envelope) hasSigner(signerKey string) bool {
+	for _, signer := range e.Signers {
+		if signer.Key == signerKey {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,216 @@
+package fake_provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"app/config"
+	"app/entity"
+	"app/infrastructure/provider"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type webhookRecorder struct {
+	mu       sync.Mutex
+	payloads []WebhookPayload
+}
+
+func (r *webhookRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var payload WebhookPayload
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	r.mu.Lock()
+	r.payloads = append(r.payloads, payload)
+	r.mu.Unlock()
+	w.WriteHeader(http.StatusOK)
+}
+
+func createRunningEnvelope(t *testing.T, envelopeProvider provider.EnvelopeProvider, signers ...string) (string, []string) {
+	ctx := context.Background()
+
+	envelopeKey, rawData, err := envelopeProvider.CreateEnvelope(ctx, &entity.EntityEnvelope{ID: 42, Name: "Contrato", AutoClose: true})
+	require.NoError(t, err)
+	assert.Contains(t, rawData, envelopeKey)
+
+	documentKey, err := envelopeProvider.CreateDocument(ctx, envelopeKey, &entity.EntityDocument{ID: 7, Name: "contrato.pdf"}, 42)
+	require.NoError(t, err)
+
+	var signerKeys []string
+	for _, email := range signers {
+		signerKey, err := envelopeProvider.CreateSigner(ctx, envelopeKey, provider.SignerData{Name: "Signer", Email: email, AuthMethod: "email"})
+		require.NoError(t, err)
+
+		_, err = envelopeProvider.CreateRequirement(ctx, envelopeKey, provider.RequirementData{Action: "sign", Role: "sign", DocumentID: documentKey, SignerID: signerKey})
+		require.NoError(t, err)
+
+		signerKeys = append(signerKeys, signerKey)
+	}
+
+	require.NoError(t, envelopeProvider.ActivateEnvelope(ctx, envelopeKey))
+	return envelopeKey, signerKeys
+}
+
+func TestNewFakeProviderFromConfig(t *testing.T) {
+	logger := logrus.New()
+
+	t.Run("should fail when disabled", func(t *testing.T) {
+		envelopeProvider, err := NewFakeProviderFromConfig(config.EnvironmentVars{}, nil, nil, nil, logger)
+
+		assert.ErrorIs(t, err, ErrDisabled)
+		assert.Nil(t, envelopeProvider)
+	})
+
+	t.Run("should use the shared store when enabled", func(t *testing.T) {
+		envelopeProvider, err := NewFakeProviderFromConfig(config.EnvironmentVars{FAKE_PROVIDER_ENABLED: true}, nil, nil, nil, logger)
+
+		require.NoError(t, err)
+		assert.Same(t, DefaultStore(), envelopeProvider.(*FakeProvider).store)
+	})
+
+	t.Run("should be registered only on demand", func(t *testing.T) {
+		_, ok := provider.Lookup(ProviderName)
+		assert.False(t, ok)
+
+		Register()
+		Register()
+		registration, ok := provider.Lookup(ProviderName)
+
+		require.True(t, ok)
+		assert.Equal(t, ProviderName, registration.Capabilities.Name)
+	})
+}
+
+func TestFakeProvider_Lifecycle(t *testing.T) {
+	logger := logrus.New()
+	store := NewStore()
+	envelopeProvider := NewFakeProvider(store, logger)
+	recorder := &webhookRecorder{}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+	simulator := NewSimulator(store, NewHTTPWebhookEmitter(server.URL, ""), logger)
+
+	envelopeKey, signerKeys := createRunningEnvelope(t, envelopeProvider, "a@empresa.com", "b@empresa.com")
+	require.NoError(t, envelopeProvider.NotifyEnvelope(context.Background(), envelopeKey, "Lembrete"))
+
+	payloads, err := simulator.Sign(context.Background(), envelopeKey, signerKeys[0])
+	require.NoError(t, err)
+	require.Len(t, payloads, 1)
+	assert.Equal(t, "sign", payloads[0].Event.Name)
+	assert.Equal(t, StatusRunning, payloads[0].Document.Status)
+
+	payloads, err = simulator.Sign(context.Background(), envelopeKey, signerKeys[1])
+	require.NoError(t, err)
+	require.Len(t, payloads, 2)
+	assert.Equal(t, "auto_close", payloads[1].Event.Name)
+
+	envelope, ok := store.Get(envelopeKey)
+	require.True(t, ok)
+	assert.Equal(t, StatusClosed, envelope.Status)
+	assert.Equal(t, 1, envelope.Notifications)
+	assert.NotNil(t, envelope.FinishedAt)
+
+	require.Len(t, recorder.payloads, 3)
+	closed := recorder.payloads[2]
+	assert.Equal(t, envelopeKey, closed.Document.Key)
+	assert.Equal(t, AccountKey, closed.Document.AccountKey)
+	assert.Equal(t, StatusClosed, closed.Document.Status)
+	assert.Equal(t, float64(42), closed.Document.Metadata["envelope_id"])
+	assert.Len(t, closed.Document.Signers, 2)
+
+	t.Run("should reject signing a closed envelope", func(t *testing.T) {
+		_, err := simulator.Sign(context.Background(), envelopeKey, signerKeys[0])
+		assert.Error(t, err)
+	})
+}
+
+func TestFakeProvider_Errors(t *testing.T) {
+	logger := logrus.New()
+	envelopeProvider := NewFakeProvider(NewStore(), logger)
+	ctx := context.Background()
+
+	t.Run("should fail for unknown envelope", func(t *testing.T) {
+		_, err := envelopeProvider.CreateDocument(ctx, "unknown", &entity.EntityDocument{Name: "doc.pdf"}, 1)
+		assert.Error(t, err)
+	})
+
+	t.Run("should not activate envelope without signers", func(t *testing.T) {
+		envelopeKey, _, err := envelopeProvider.CreateEnvelope(ctx, &entity.EntityEnvelope{ID: 1, Name: "Vazio"})
+		require.NoError(t, err)
+
+		assert.Error(t, envelopeProvider.ActivateEnvelope(ctx, envelopeKey))
+	})
+
+	t.Run("should reject requirement for unknown signer", func(t *testing.T) {
+		envelopeKey, _, err := envelopeProvider.CreateEnvelope(ctx, &entity.EntityEnvelope{ID: 1, Name: "Envelope"})
+		require.NoError(t, err)
+		documentKey, err := envelopeProvider.CreateDocument(ctx, envelopeKey, &entity.EntityDocument{Name: "doc.pdf"}, 1)
+		require.NoError(t, err)
+
+		_, err = envelopeProvider.CreateRequirement(ctx, envelopeKey, provider.RequirementData{Action: "sign", DocumentID: documentKey, SignerID: "unknown"})
+		assert.Error(t, err)
+	})
+
+	t.Run("should not notify draft envelope", func(t *testing.T) {
+		envelopeKey, _, err := envelopeProvider.CreateEnvelope(ctx, &entity.EntityEnvelope{ID: 1, Name: "Envelope"})
+		require.NoError(t, err)
+
+		assert.Error(t, envelopeProvider.NotifyEnvelope(ctx, envelopeKey, ""))
+	})
+}
+
+func TestFakeProvider_DeleteDocument(t *testing.T) {
+	logger := logrus.New()
+	store := NewStore()
+	envelopeProvider := NewFakeProvider(store, logger)
+	ctx := context.Background()
+
+	envelopeKey, _, err := envelopeProvider.CreateEnvelope(ctx, &entity.EntityEnvelope{ID: 1, Name: "Envelope"})
+	require.NoError(t, err)
+	documentKey, err := envelopeProvider.CreateDocument(ctx, envelopeKey, &entity.EntityDocument{ID: 7, Name: "doc.pdf"}, 1)
+	require.NoError(t, err)
+	signerKey, err := envelopeProvider.CreateSigner(ctx, envelopeKey, provider.SignerData{Name: "Signer", Email: "a@empresa.com", AuthMethod: "email"})
+	require.NoError(t, err)
+	_, err = envelopeProvider.CreateRequirement(ctx, envelopeKey, provider.RequirementData{Action: "sign", DocumentID: documentKey, SignerID: signerKey})
+	require.NoError(t, err)
+
+	require.NoError(t, envelopeProvider.DeleteDocument(ctx, envelopeKey, documentKey))
+
+	envelope, ok := store.Get(envelopeKey)
+	require.True(t, ok)
+	assert.Empty(t, envelope.Documents)
+	assert.Empty(t, envelope.Requirements)
+	assert.Len(t, envelope.Signers, 1)
+
+	t.Run("should fail for unknown document", func(t *testing.T) {
+		assert.Error(t, envelopeProvider.DeleteDocument(ctx, envelopeKey, documentKey))
+	})
+
+	t.Run("should not delete documents of running envelope", func(t *testing.T) {
+		runningKey, _ := createRunningEnvelope(t, envelopeProvider, "b@empresa.com")
+		running, _ := store.Get(runningKey)
+
+		assert.Error(t, envelopeProvider.DeleteDocument(ctx, runningKey, running.Documents[0].Key))
+	})
+}
+
+func TestHTTPWebhookEmitter_RejectsErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	err := NewHTTPWebhookEmitter(server.URL, "").Emit(context.Background(), WebhookPayload{Event: WebhookEvent{Name: "sign"}})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "500")
+}
@@ -0,0 +1,264 @@
+package fake_provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"app/infrastructure/clicksign"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AccountKey é a chave de conta enviada nos webhooks do provider fake
+const AccountKey = "fake-account"
+
+// WebhookPayload reproduz o formato dos webhooks do Clicksign aceito por /api/v1/webhooks
+type WebhookPayload struct {
+	Event    WebhookEvent    `json:"event"`
+	Document WebhookDocument `json:"document"`
+}
+
+// WebhookEvent representa o evento de um webhook fake
+type WebhookEvent struct {
+	Name       string                 `json:"name"`
+	Data       map[string]interface{} `json:"data"`
+	OccurredAt string                 `json:"occurred_at"`
+}
+
+// WebhookDocument representa o documento (envelope) de um webhook fake
+type WebhookDocument struct {
+	Key        string                 `json:"key"`
+	AccountKey string                 `json:"account_key"`
+	Filename   string                 `json:"filename"`
+	UploadedAt string                 `json:"uploaded_at"`
+	UpdatedAt  string                 `json:"updated_at"`
+	FinishedAt string                 `json:"finished_at,omitempty"`
+	DeadlineAt string                 `json:"deadline_at,omitempty"`
+	Status     string                 `json:"status"`
+	AutoClose  bool                   `json:"auto_close"`
+	Locale     string                 `json:"locale"`
+	Metadata   map[string]interface{} `json:"metadata"`
+	Signers    []WebhookSigner        `json:"signers"`
+}
+
+// WebhookSigner representa um signatário de um webhook fake
+type WebhookSigner struct {
+	Key       string   `json:"key"`
+	Email     string   `json:"email"`
+	Name      string   `json:"name"`
+	SignAs    string   `json:"sign_as"`
+	Auths     []string `json:"auths"`
+	CreatedAt string   `json:"created_at"`
+}
+
+// WebhookEmitter entrega webhooks gerados pelo simulador
+type WebhookEmitter interface {
+	Emit(ctx context.Context, payload WebhookPayload) error
+}
+
+// HTTPWebhookEmitter envia os webhooks via POST para a URL configurada, assinados como os da Clicksign
+type HTTPWebhookEmitter struct {
+	url        string
+	secret     string
+	httpClient *http.Client
+}
+
+// NewHTTPWebhookEmitter cria um emissor que envia webhooks para url com a HMAC calculada com secret
+func NewHTTPWebhookEmitter(url, secret string) *HTTPWebhookEmitter {
+	return &HTTPWebhookEmitter{
+		url:        url,
+		secret:     secret,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Emit envia o payload e retorna erro para respostas diferentes de 2xx
+func (e *HTTPWebhookEmitter) Emit(ctx context.Context, payload WebhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal fake webhook: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create fake webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.secret != "" {
+		req.Header.Set(clicksign.WebhookSignatureHeader, clicksign.SignWebhook(e.secret, body))
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send fake webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("fake webhook rejected with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
+
+// Simulator simula ações dos signatários nos envelopes fake e emite os webhooks correspondentes
+type Simulator struct {
+	store   *Store
+	emitter WebhookEmitter
+	logger  *logrus.Logger
+}
+
+// NewSimulator cria um novo Simulator
+func NewSimulator(store *Store, emitter WebhookEmitter, logger *logrus.Logger) *Simulator {
+	return &Simulator{
+		store:   store,
+		emitter: emitter,
+		logger:  logger,
+	}
+}
+
+// Store retorna o Store usado pelo simulador
+func (s *Simulator) Store() *Store {
+	return s.store
+}
+
+// Sign registra a assinatura do signatário e emite o webhook "sign"
+// Quando o último signatário assina, o envelope é fechado e o webhook "auto_close" também é emitido
+func (s *Simulator) Sign(ctx context.Context, envelopeKey, signerKey string) ([]WebhookPayload, error) {
+	var signer Signer
+	envelope, err := s.store.update(envelopeKey, func(envelope *Envelope) error {
+		if envelope.Status != StatusRunning {
+			return fmt.Errorf("cannot sign fake envelope in status %s", envelope.Status)
+		}
+
+		index := -1
+		for i := range envelope.Signers {
+			if envelope.Signers[i].Key == signerKey {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return fmt.Errorf("fake signer not found in envelope: %s", signerKey)
+		}
+		if envelope.Signers[index].HasSigned() {
+			return fmt.Errorf("fake signer already signed: %s", signerKey)
+		}
+
+		now := time.Now()
+		envelope.Signers[index].SignedAt = &now
+		signer = envelope.Signers[index]
+
+		if envelope.AllSigned() && envelope.AutoClose {
+			envelope.Status = StatusClosed
+			envelope.FinishedAt = &now
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	payloads := []WebhookPayload{
+		buildWebhookPayload(envelope, "sign", map[string]interface{}{
+			"signer": map[string]interface{}{
+				"key":   signer.Key,
+				"email": signer.Email,
+				"name":  signer.Name,
+			},
+		}),
+	}
+	if envelope.Status == StatusClosed {
+		payloads = append(payloads, buildWebhookPayload(envelope, "auto_close", map[string]interface{}{}))
+	}
+
+	return payloads, s.emit(ctx, payloads)
+}
+
+// EmitEvent emite um webhook arbitrário para o envelope, sem alterar o seu estado
+// Útil para exercitar eventos como "upload", "add_signer" e "signature_started"
+func (s *Simulator) EmitEvent(ctx context.Context, envelopeKey, eventName string, data map[string]interface{}) (WebhookPayload, error) {
+	envelope, ok := s.store.Get(envelopeKey)
+	if !ok {
+		return WebhookPayload{}, fmt.Errorf("fake envelope not found: %s", envelopeKey)
+	}
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+
+	payload := buildWebhookPayload(envelope, eventName, data)
+	return payload, s.emit(ctx, []WebhookPayload{payload})
+}
+
+func (s *Simulator) emit(ctx context.Context, payloads []WebhookPayload) error {
+	for _, payload := range payloads {
+		if err := s.emitter.Emit(ctx, payload); err != nil {
+			return err
+		}
+
+		s.logger.WithFields(logrus.Fields{
+			"event_name":   payload.Event.Name,
+			"document_key": payload.Document.Key,
+		}).Debug("Fake webhook emitted")
+	}
+	return nil
+}
+
+func buildWebhookPayload(envelope Envelope, eventName string, data map[string]interface{}) WebhookPayload {
+	document := WebhookDocument{
+		Key:        envelope.Key,
+		AccountKey: AccountKey,
+		UploadedAt: formatTime(envelope.CreatedAt),
+		UpdatedAt:  formatTime(envelope.UpdatedAt),
+		Status:     envelope.Status,
+		AutoClose:  envelope.AutoClose,
+		Locale:     "pt-BR",
+		Metadata: map[string]interface{}{
+			"envelope_id": envelope.EnvelopeID,
+		},
+		Signers: make([]WebhookSigner, 0, len(envelope.Signers)),
+	}
+	if len(envelope.Documents) > 0 {
+		document.Filename = envelope.Documents[0].Filename
+	}
+	if envelope.FinishedAt != nil {
+		document.FinishedAt = formatTime(*envelope.FinishedAt)
+	}
+	if envelope.DeadlineAt != nil {
+		document.DeadlineAt = formatTime(*envelope.DeadlineAt)
+	}
+
+	for _, signer := range envelope.Signers {
+		auth := signer.Auth
+		if auth == "" {
+			auth = "email"
+		}
+		document.Signers = append(document.Signers, WebhookSigner{
+			Key:       signer.Key,
+			Email:     signer.Email,
+			Name:      signer.Name,
+			SignAs:    "sign",
+			Auths:     []string{auth},
+			CreatedAt: formatTime(envelope.CreatedAt),
+		})
+	}
+
+	return WebhookPayload{
+		Event: WebhookEvent{
+			Name:       eventName,
+			Data:       data,
+			OccurredAt: formatTime(time.Now()),
+		},
+		Document: document,
+	}
+}
+
+func formatTime(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,178 @@
+package fake_provider
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status dos envelopes no provider fake, seguindo os nomes usados pelo Clicksign
+const (
+	StatusDraft    = "draft"
+	StatusRunning  = "running"
+	StatusClosed   = "closed"
+	StatusCanceled = "canceled"
+)
+
+// Envelope representa um envelope armazenado em memória pelo provider fake
+type Envelope struct {
+	Key           string        `json:"key"`
+	EnvelopeID    int           `json:"envelope_id"`
+	Name          string        `json:"name"`
+	Status        string        `json:"status"`
+	AutoClose     bool          `json:"auto_close"`
+	DeadlineAt    *time.Time    `json:"deadline_at,omitempty"`
+	Documents     []Document    `json:"documents"`
+	Signers       []Signer      `json:"signers"`
+	Requirements  []Requirement `json:"requirements"`
+	Notifications int           `json:"notifications"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+	FinishedAt    *time.Time    `json:"finished_at,omitempty"`
+}
+
+// Document representa um documento de um envelope fake
+type Document struct {
+	Key        string `json:"key"`
+	DocumentID int    `json:"document_id"`
+	Filename   string `json:"filename"`
+}
+
+// Signer representa um signatário de um envelope fake
+type Signer struct {
+	Key      string     `json:"key"`
+	Name     string     `json:"name"`
+	Email    string     `json:"email"`
+	Group    int        `json:"group"`
+	Auth     string     `json:"auth"`
+	SignedAt *time.Time `json:"signed_at,omitempty"`
+}
+
+// Requirement representa um requisito de assinatura de um envelope fake
+type Requirement struct {
+	Key         string `json:"key"`
+	Action      string `json:"action"`
+	Role        string `json:"role,omitempty"`
+	Auth        string `json:"auth,omitempty"`
+	DocumentKey string `json:"document_key"`
+	SignerKey   string `json:"signer_key"`
+}
+
+// HasSigned indica se o signatário já assinou
+func (s Signer) HasSigned() bool {
+	return s.SignedAt != nil
+}
+
+// AllSigned indica se todos os signatários do envelope já assinaram
+func (e *Envelope) AllSigned() bool {
+	if len(e.Signers) == 0 {
+		return false
+	}
+	for _, signer := range e.Signers {
+		if !signer.HasSigned() {
+			return false
+		}
+	}
+	return true
+}
+
+// Store armazena os envelopes do provider fake em memória
+// É compartilhado entre as instâncias do provider, pois o factory cria uma instância por request
+type Store struct {
+	mu        sync.RWMutex
+	envelopes map[string]*Envelope
+}
+
+// NewStore cria um Store vazio
+func NewStore() *Store {
+	return &Store{envelopes: make(map[string]*Envelope)}
+}
+
+var defaultStore = NewStore()
+
+// DefaultStore retorna o Store global usado pelas instâncias criadas via ProviderFactory
+func DefaultStore() *Store {
+	return defaultStore
+}
+
+func newKey(prefix string) string {
+	return fmt.Sprintf("%s-%s", prefix, uuid.New().String())
+}
+
+func (s *Store) createEnvelope(envelope Envelope) Envelope {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	envelope.Key = newKey("fake-env")
+	envelope.Status = StatusDraft
+	envelope.CreatedAt = now
+	envelope.UpdatedAt = now
+	s.envelopes[envelope.Key] = &envelope
+
+	return envelope
+}
+
+// update aplica fn ao envelope sob lock e retorna uma cópia do resultado
+func (s *Store) update(envelopeKey string, fn func(envelope *Envelope) error) (Envelope, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	envelope, ok := s.envelopes[envelopeKey]
+	if !ok {
+		return Envelope{}, fmt.Errorf("fake envelope not found: %s", envelopeKey)
+	}
+
+	if err := fn(envelope); err != nil {
+		return Envelope{}, err
+	}
+	envelope.UpdatedAt = time.Now()
+
+	return envelope.clone(), nil
+}
+
+// Get retorna uma cópia do envelope pela chave
+func (s *Store) Get(envelopeKey string) (Envelope, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	envelope, ok := s.envelopes[envelopeKey]
+	if !ok {
+		return Envelope{}, false
+	}
+	return envelope.clone(), true
+}
+
+// List retorna cópias de todos os envelopes, do mais antigo para o mais recente
+func (s *Store) List() []Envelope {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	envelopes := make([]Envelope, 0, len(s.envelopes))
+	for _, envelope := range s.envelopes {
+		envelopes = append(envelopes, envelope.clone())
+	}
+	sort.Slice(envelopes, func(i, j int) bool {
+		return envelopes[i].CreatedAt.Before(envelopes[j].CreatedAt)
+	})
+	return envelopes
+}
+
+// Reset remove todos os envelopes armazenados
+func (s *Store) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.envelopes = make(map[string]*Envelope)
+}
+
+func (e *Envelope) clone() Envelope {
+	clone := *e
+	clone.Documents = append([]Document(nil), e.Documents...)
+	clone.Signers = append([]Signer(nil), e.Signers...)
+	clone.Requirements = append([]Requirement(nil), e.Requirements...)
+	return clone
+}
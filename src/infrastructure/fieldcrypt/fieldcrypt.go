@@ -0,0 +1,285 @@
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"app/config"
+	"app/entity"
+)
+
+// Formato dos valores cifrados: enc:v2:<id da chave>:<chave de dados cifrada>:<nonce e texto cifrado>
+// No v2 o texto cifrado é amarrado ao id da chave e à tabela, coluna e linha do valor; os valores v1,
+// amarrados apenas ao id da chave, continuam legíveis até a recifragem
+const (
+	formatPrefix       = entity.EncryptedPIIPrefix + "v2:"
+	legacyFormatPrefix = entity.EncryptedPIIPrefix + "v1:"
+)
+
+const keySize = 32
+
+var (
+	ErrNotConfigured  = errors.New("PII_ENCRYPTION_KEYS is required; set PII_ENCRYPTION_DISABLED=true to store personal data in plaintext")
+	ErrUnknownKey     = errors.New("unknown encryption key")
+	ErrMalformedValue = errors.New("malformed encrypted value")
+	ErrBlindIndexKey  = errors.New("PII_BLIND_INDEX_KEY must be a base64 encoded 32 byte key")
+	ErrInvalidKeys    = errors.New(`PII_ENCRYPTION_KEYS must be "id:base64key,..." with 32 byte keys and ids made of letters, digits and "-"`)
+)
+
+var (
+	validKeyID  = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
+	base64NoPad = base64.RawStdEncoding
+)
+
+// KeyProvider guarda as chaves mestras, no modelo de um KMS: as chaves não saem do provedor,
+// que apenas cifra e decifra as chaves de dados geradas para cada valor
+type KeyProvider interface {
+	// ActiveKeyID é a chave mestra usada nas novas cifragens
+	ActiveKeyID() string
+	WrapKey(keyID string, dataKey []byte) ([]byte, error)
+	UnwrapKey(keyID string, wrapped []byte) ([]byte, error)
+}
+
+// StaticKeyProvider mantém as chaves mestras lidas da configuração
+// As chaves antigas continuam aceitas na leitura até que a recifragem termine
+type StaticKeyProvider struct {
+	active string
+	keys   map[string]cipher.AEAD
+}
+
+// NewStaticKeyProvider cria o provedor; active precisa estar entre as chaves
+func NewStaticKeyProvider(active string, keys map[string][]byte) (*StaticKeyProvider, error) {
+	provider := &StaticKeyProvider{active: active, keys: make(map[string]cipher.AEAD, len(keys))}
+	for keyID, key := range keys {
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid encryption key %s: %w", keyID, err)
+		}
+		provider.keys[keyID] = aead
+	}
+	if _, ok := provider.keys[active]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, active)
+	}
+	return provider, nil
+}
+
+func (p *StaticKeyProvider) ActiveKeyID() string {
+	return p.active
+}
+
+func (p *StaticKeyProvider) WrapKey(keyID string, dataKey []byte) ([]byte, error) {
+	aead, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	return seal(aead, dataKey, []byte(keyID))
+}
+
+func (p *StaticKeyProvider) UnwrapKey(keyID string, wrapped []byte) ([]byte, error) {
+	aead, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	return open(aead, wrapped, []byte(keyID))
+}
+
+// Cipher implementa entity.PIICipher com envelope encryption: cada valor é cifrado com AES-256-GCM
+// por uma chave de dados própria, guardada junto do valor cifrada pela chave mestra ativa
+type Cipher struct {
+	keys     KeyProvider
+	indexKey []byte
+}
+
+// NewCipher cria a cifragem; indexKey calcula os blind indexes e não pode ser trocada sem reindexar
+func NewCipher(keys KeyProvider, indexKey []byte) (*Cipher, error) {
+	if len(indexKey) != keySize {
+		return nil, ErrBlindIndexKey
+	}
+	return &Cipher{keys: keys, indexKey: indexKey}, nil
+}
+
+// NewCipherFromConfig lê as chaves de PII_ENCRYPTION_KEYS; sem elas a aplicação não inicia, a menos que
+// PII_ENCRYPTION_DISABLED=true desligue explicitamente a cifragem (retorna nil)
+func NewCipherFromConfig(envVars config.EnvironmentVars) (*Cipher, error) {
+	if strings.TrimSpace(envVars.PII_ENCRYPTION_KEYS) == "" {
+		if !envVars.PII_ENCRYPTION_DISABLED {
+			return nil, ErrNotConfigured
+		}
+		log.Println("PII_ENCRYPTION_DISABLED=true: signer personal data is stored in plaintext")
+		return nil, nil
+	}
+
+	keys, firstID, err := parseKeys(envVars.PII_ENCRYPTION_KEYS)
+	if err != nil {
+		return nil, err
+	}
+	active := strings.TrimSpace(envVars.PII_ENCRYPTION_ACTIVE_KEY)
+	if active == "" {
+		active = firstID
+	}
+
+	provider, err := NewStaticKeyProvider(active, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	indexKey, err := base64.StdEncoding.DecodeString(strings.TrimSpace(envVars.PII_BLIND_INDEX_KEY))
+	if err != nil {
+		return nil, ErrBlindIndexKey
+	}
+	return NewCipher(provider, indexKey)
+}
+
+// Encrypt cifra plaintext amarrado a binding (tabela, coluna e linha, ver entity.PIIBinding)
+func (c *Cipher) Encrypt(plaintext, binding string) (string, error) {
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	keyID := c.keys.ActiveKeyID()
+	wrapped, err := c.keys.WrapKey(keyID, dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(aead, []byte(plaintext), boundData(keyID, binding))
+	if err != nil {
+		return "", err
+	}
+
+	return formatPrefix + keyID + ":" + base64NoPad.EncodeToString(wrapped) + ":" + base64NoPad.EncodeToString(sealed), nil
+}
+
+// Decrypt decifra value, que precisa ter sido cifrado para o mesmo binding (exceto os valores v1)
+func (c *Cipher) Decrypt(value, binding string) (string, error) {
+	if !strings.HasPrefix(value, entity.EncryptedPIIPrefix) {
+		return value, nil
+	}
+
+	var parts []string
+	switch {
+	case strings.HasPrefix(value, formatPrefix):
+		parts = strings.Split(strings.TrimPrefix(value, formatPrefix), ":")
+	case strings.HasPrefix(value, legacyFormatPrefix):
+		parts = strings.Split(strings.TrimPrefix(value, legacyFormatPrefix), ":")
+	}
+	if len(parts) != 3 {
+		return "", ErrMalformedValue
+	}
+	keyID := parts[0]
+	additionalData := []byte(keyID)
+	if strings.HasPrefix(value, formatPrefix) {
+		additionalData = boundData(keyID, binding)
+	}
+	wrapped, err := base64NoPad.DecodeString(parts[1])
+	if err != nil {
+		return "", ErrMalformedValue
+	}
+	sealed, err := base64NoPad.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrMalformedValue
+	}
+
+	dataKey, err := c.keys.UnwrapKey(keyID, wrapped)
+	if err != nil {
+		return "", err
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(aead, sealed, additionalData)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func (c *Cipher) BlindIndex(value string) string {
+	mac := hmac.New(sha256.New, c.indexKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (c *Cipher) CurrentPrefix() string {
+	return formatPrefix + c.keys.ActiveKeyID() + ":"
+}
+
+// boundData é o additional data do v2: o id da chave e o binding, separados por um byte nulo
+func boundData(keyID, binding string) []byte {
+	return []byte(keyID + "\x00" + binding)
+}
+
+// parseKeys lê "id:base64,id2:base64"; a primeira chave é a ativa quando PII_ENCRYPTION_ACTIVE_KEY não é informada
+func parseKeys(value string) (map[string][]byte, string, error) {
+	keys := map[string][]byte{}
+	var firstID string
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		keyID, encoded, found := strings.Cut(entry, ":")
+		if !found || !validKeyID.MatchString(keyID) {
+			return nil, "", ErrInvalidKeys
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != keySize {
+			return nil, "", ErrInvalidKeys
+		}
+		if firstID == "" {
+			firstID = keyID
+		}
+		keys[keyID] = key
+	}
+	if firstID == "" {
+		return nil, "", ErrInvalidKeys
+	}
+	return keys, firstID, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != keySize {
+		return nil, fmt.Errorf("key must have %d bytes", keySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal retorna nonce seguido do texto cifrado; additionalData amarra o valor ao id da chave e, no v2, à linha
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(aead cipher.AEAD, sealed, additionalData []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrMalformedValue
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return plaintext, nil
+}
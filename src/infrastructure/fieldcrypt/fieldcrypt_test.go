@@ -0,0 +1,184 @@
+package fieldcrypt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"app/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, keySize)
+}
+
+func newTestCipher(t *testing.T, active string, keys map[string][]byte) *Cipher {
+	provider, err := NewStaticKeyProvider(active, keys)
+	require.NoError(t, err)
+	c, err := NewCipher(provider, testKey(9))
+	require.NoError(t, err)
+	return c
+}
+
+const testBinding = "signatories.documentation#7"
+
+// encryptV1 cifra no formato v1, amarrado apenas ao id da chave, como os valores gravados antes do v2
+func encryptV1(t *testing.T, c *Cipher, plaintext string) string {
+	dataKey := testKey(5)
+	keyID := c.keys.ActiveKeyID()
+	wrapped, err := c.keys.WrapKey(keyID, dataKey)
+	require.NoError(t, err)
+	aead, err := newAEAD(dataKey)
+	require.NoError(t, err)
+	sealed, err := seal(aead, []byte(plaintext), []byte(keyID))
+	require.NoError(t, err)
+	return legacyFormatPrefix + keyID + ":" + base64NoPad.EncodeToString(wrapped) + ":" + base64NoPad.EncodeToString(sealed)
+}
+
+func TestCipher_EncryptDecrypt(t *testing.T) {
+	c := newTestCipher(t, "k1", map[string][]byte{"k1": testKey(1)})
+
+	t.Run("should round trip with a fresh data key per value", func(t *testing.T) {
+		first, err := c.Encrypt("123.456.789-09", testBinding)
+		require.NoError(t, err)
+		second, err := c.Encrypt("123.456.789-09", testBinding)
+		require.NoError(t, err)
+
+		assert.True(t, strings.HasPrefix(first, c.CurrentPrefix()))
+		assert.NotContains(t, first, "123.456.789-09")
+		assert.NotEqual(t, first, second)
+
+		plaintext, err := c.Decrypt(first, testBinding)
+		require.NoError(t, err)
+		assert.Equal(t, "123.456.789-09", plaintext)
+	})
+
+	t.Run("should pass legacy plaintext through", func(t *testing.T) {
+		plaintext, err := c.Decrypt("12345678909", testBinding)
+		require.NoError(t, err)
+		assert.Equal(t, "12345678909", plaintext)
+	})
+
+	t.Run("should reject tampered and malformed values", func(t *testing.T) {
+		value, err := c.Encrypt("11999998888", testBinding)
+		require.NoError(t, err)
+		tampered := value[:len(value)-2] + "AA"
+		if tampered == value {
+			tampered = value[:len(value)-2] + "BB"
+		}
+
+		_, err = c.Decrypt(tampered, testBinding)
+		assert.Error(t, err)
+
+		_, err = c.Decrypt("enc:v1:k1:abc", testBinding)
+		assert.ErrorIs(t, err, ErrMalformedValue)
+
+		_, err = c.Decrypt("enc:v2:k1:a:b", testBinding)
+		assert.ErrorIs(t, err, ErrMalformedValue)
+
+		_, err = c.Decrypt("enc:v3:k1:a:b", testBinding)
+		assert.ErrorIs(t, err, ErrMalformedValue)
+	})
+
+	t.Run("should not accept a value moved to another row or column", func(t *testing.T) {
+		value, err := c.Encrypt("123.456.789-09", testBinding)
+		require.NoError(t, err)
+
+		_, err = c.Decrypt(value, "signatories.documentation#8")
+		assert.Error(t, err)
+		_, err = c.Decrypt(value, "signatories.phone_number#7")
+		assert.Error(t, err)
+	})
+
+	t.Run("should still decrypt v1 values regardless of the binding", func(t *testing.T) {
+		value := encryptV1(t, c, "1990-01-01")
+		assert.False(t, strings.HasPrefix(value, c.CurrentPrefix()))
+
+		plaintext, err := c.Decrypt(value, "signatories.birthday#42")
+		require.NoError(t, err)
+		assert.Equal(t, "1990-01-01", plaintext)
+	})
+
+	t.Run("should not accept a value moved to another key id", func(t *testing.T) {
+		rotated := newTestCipher(t, "k2", map[string][]byte{"k1": testKey(1), "k2": testKey(1)})
+		value, err := rotated.Encrypt("1990-01-01", testBinding)
+		require.NoError(t, err)
+
+		_, err = rotated.Decrypt(strings.Replace(value, ":k2:", ":k1:", 1), testBinding)
+		assert.Error(t, err)
+	})
+}
+
+func TestCipher_Rotation(t *testing.T) {
+	old := newTestCipher(t, "k1", map[string][]byte{"k1": testKey(1)})
+	value, err := old.Encrypt("12345678909", testBinding)
+	require.NoError(t, err)
+
+	rotated := newTestCipher(t, "k2", map[string][]byte{"k1": testKey(1), "k2": testKey(2)})
+
+	plaintext, err := rotated.Decrypt(value, testBinding)
+	require.NoError(t, err)
+	assert.Equal(t, "12345678909", plaintext)
+	assert.False(t, strings.HasPrefix(value, rotated.CurrentPrefix()))
+
+	reencrypted, err := rotated.Encrypt(plaintext, testBinding)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(reencrypted, "enc:v2:k2:"))
+
+	withoutOldKey := newTestCipher(t, "k2", map[string][]byte{"k2": testKey(2)})
+	_, err = withoutOldKey.Decrypt(value, testBinding)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+
+	assert.Equal(t, old.BlindIndex("12345678909"), rotated.BlindIndex("12345678909"))
+}
+
+func TestParseKeys(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString(testKey(1))
+
+	keys, firstID, err := parseKeys(" 2024-01:" + encoded + ", 2023-07:" + base64.StdEncoding.EncodeToString(testKey(2)) + ",")
+	require.NoError(t, err)
+	assert.Equal(t, "2024-01", firstID)
+	assert.Len(t, keys, 2)
+	assert.Equal(t, testKey(2), keys["2023-07"])
+
+	for _, invalid := range []string{"", "k1", "k:1:" + encoded, "k1:" + base64.StdEncoding.EncodeToString([]byte("short")), "k1:not-base64"} {
+		_, _, err := parseKeys(invalid)
+		assert.ErrorIs(t, err, ErrInvalidKeys, invalid)
+	}
+}
+
+func TestNewCipherFromConfig(t *testing.T) {
+	keys := "k1:" + base64.StdEncoding.EncodeToString(testKey(1)) + ",k2:" + base64.StdEncoding.EncodeToString(testKey(2))
+	indexKey := base64.StdEncoding.EncodeToString(testKey(9))
+
+	t.Run("should refuse to start without keys unless encryption is disabled", func(t *testing.T) {
+		_, err := NewCipherFromConfig(config.EnvironmentVars{})
+		assert.ErrorIs(t, err, ErrNotConfigured)
+
+		c, err := NewCipherFromConfig(config.EnvironmentVars{PII_ENCRYPTION_DISABLED: true})
+		require.NoError(t, err)
+		assert.Nil(t, c)
+	})
+
+	t.Run("should use the first key unless the active key is informed", func(t *testing.T) {
+		c, err := NewCipherFromConfig(config.EnvironmentVars{PII_ENCRYPTION_KEYS: keys, PII_BLIND_INDEX_KEY: indexKey})
+		require.NoError(t, err)
+		assert.Equal(t, "enc:v2:k1:", c.CurrentPrefix())
+
+		c, err = NewCipherFromConfig(config.EnvironmentVars{PII_ENCRYPTION_KEYS: keys, PII_ENCRYPTION_ACTIVE_KEY: "k2", PII_BLIND_INDEX_KEY: indexKey})
+		require.NoError(t, err)
+		assert.Equal(t, "enc:v2:k2:", c.CurrentPrefix())
+	})
+
+	t.Run("should reject unknown active key and missing blind index key", func(t *testing.T) {
+		_, err := NewCipherFromConfig(config.EnvironmentVars{PII_ENCRYPTION_KEYS: keys, PII_ENCRYPTION_ACTIVE_KEY: "k3", PII_BLIND_INDEX_KEY: indexKey})
+		assert.ErrorIs(t, err, ErrUnknownKey)
+
+		_, err = NewCipherFromConfig(config.EnvironmentVars{PII_ENCRYPTION_KEYS: keys})
+		assert.ErrorIs(t, err, ErrBlindIndexKey)
+	})
+}
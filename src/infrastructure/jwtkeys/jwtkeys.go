@@ -0,0 +1,247 @@
+package jwtkeys
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"app/config"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	// AlgorithmHS256 assina com um segredo compartilhado
+	AlgorithmHS256 = "HS256"
+	// AlgorithmRS256 assina com chave privada RSA; as chaves públicas ficam disponíveis no JWKS
+	AlgorithmRS256 = "RS256"
+
+	defaultKeyID = "default"
+)
+
+// ErrUnknownKey indica um token assinado com uma chave que não está mais configurada
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// Key é uma chave de assinatura ou apenas de verificação (rotação)
+type Key struct {
+	ID        string
+	Algorithm string
+	secret    []byte
+	private   *rsa.PrivateKey
+	public    *rsa.PublicKey
+}
+
+// NewHMACKey cria uma chave HS256
+func NewHMACKey(id, secret string) *Key {
+	return &Key{ID: id, Algorithm: AlgorithmHS256, secret: []byte(secret)}
+}
+
+// NewRSAKey cria uma chave RS256 capaz de assinar
+func NewRSAKey(id string, private *rsa.PrivateKey) *Key {
+	return &Key{ID: id, Algorithm: AlgorithmRS256, private: private, public: &private.PublicKey}
+}
+
+// NewRSAPublicKey cria uma chave RS256 apenas de verificação
+func NewRSAPublicKey(id string, public *rsa.PublicKey) *Key {
+	return &Key{ID: id, Algorithm: AlgorithmRS256, public: public}
+}
+
+// KeySet assina com a chave atual e valida tokens de qualquer chave configurada, escolhida pelo kid
+type KeySet struct {
+	current *Key
+	keys    map[string]*Key
+}
+
+// NewKeySet cria o conjunto; verification são chaves anteriores mantidas até os tokens emitidos com elas expirarem
+func NewKeySet(current *Key, verification ...*Key) *KeySet {
+	keys := map[string]*Key{current.ID: current}
+	for _, key := range verification {
+		if _, exists := keys[key.ID]; !exists {
+			keys[key.ID] = key
+		}
+	}
+	return &KeySet{current: current, keys: keys}
+}
+
+// NewKeySetFromConfig cria o conjunto a partir de JWT_ALGORITHM, JWT_SECRET/JWT_PRIVATE_KEY_PATH e JWT_VERIFICATION_KEYS
+func NewKeySetFromConfig(envVars config.EnvironmentVars) (*KeySet, error) {
+	keyID := strings.TrimSpace(envVars.JWT_KEY_ID)
+	if keyID == "" {
+		keyID = defaultKeyID
+	}
+
+	algorithm := strings.ToUpper(strings.TrimSpace(envVars.JWT_ALGORITHM))
+	var current *Key
+	switch algorithm {
+	case "", AlgorithmHS256:
+		algorithm = AlgorithmHS256
+		if envVars.JWT_SECRET == "" {
+			return nil, errors.New("JWT_SECRET is required for HS256")
+		}
+		current = NewHMACKey(keyID, envVars.JWT_SECRET)
+	case AlgorithmRS256:
+		private, err := readRSAPrivateKey(envVars.JWT_PRIVATE_KEY_PATH)
+		if err != nil {
+			return nil, err
+		}
+		current = NewRSAKey(keyID, private)
+	default:
+		return nil, fmt.Errorf("unsupported jwt algorithm: %s", envVars.JWT_ALGORITHM)
+	}
+
+	verification, err := parseVerificationKeys(algorithm, envVars.JWT_VERIFICATION_KEYS)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeySet(current, verification...), nil
+}
+
+// parseVerificationKeys lê "kid=valor,kid2=valor2"; valor é o segredo (HS256) ou o caminho da chave pública PEM (RS256)
+func parseVerificationKeys(algorithm, value string) ([]*Key, error) {
+	var keys []*Key
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, keyValue, found := strings.Cut(entry, "=")
+		id = strings.TrimSpace(id)
+		if !found || id == "" || keyValue == "" {
+			return nil, fmt.Errorf("invalid JWT_VERIFICATION_KEYS entry: expected kid=value")
+		}
+
+		if algorithm == AlgorithmHS256 {
+			keys = append(keys, NewHMACKey(id, keyValue))
+			continue
+		}
+		public, err := readRSAPublicKey(strings.TrimSpace(keyValue))
+		if err != nil {
+			return nil, fmt.Errorf("verification key %s: %w", id, err)
+		}
+		keys = append(keys, NewRSAPublicKey(id, public))
+	}
+	return keys, nil
+}
+
+func readRSAPrivateKey(path string) (*rsa.PrivateKey, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, errors.New("JWT_PRIVATE_KEY_PATH is required for RS256")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jwt private key: %w", err)
+	}
+	private, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse jwt private key: %w", err)
+	}
+	return private, nil
+}
+
+func readRSAPublicKey(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jwt public key: %w", err)
+	}
+	public, err := jwt.ParseRSAPublicKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse jwt public key: %w", err)
+	}
+	return public, nil
+}
+
+// Algorithm retorna o algoritmo da chave atual
+func (s *KeySet) Algorithm() string {
+	return s.current.Algorithm
+}
+
+// Sign assina as claims com a chave atual, identificada no header kid
+func (s *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(signingMethod(s.current.Algorithm), claims)
+	token.Header["kid"] = s.current.ID
+
+	if s.current.Algorithm == AlgorithmHS256 {
+		return token.SignedString(s.current.secret)
+	}
+	return token.SignedString(s.current.private)
+}
+
+// Parse valida a assinatura e as claims padrão (exp, iat, nbf)
+// Tokens sem kid, emitidos antes da rotação de chaves, são validados com a chave atual
+func (s *KeySet) Parse(signedToken string, claims jwt.Claims) error {
+	_, err := jwt.ParseWithClaims(signedToken, claims, func(token *jwt.Token) (interface{}, error) {
+		key := s.current
+		if kid, ok := token.Header["kid"].(string); ok && kid != "" {
+			if key, ok = s.keys[kid]; !ok {
+				return nil, ErrUnknownKey
+			}
+		}
+
+		// O algoritmo vem da chave, nunca do token, para impedir a troca de RS256 por HS256
+		if token.Method.Alg() != key.Algorithm {
+			return nil, fmt.Errorf("unexpected signing method: %s", token.Method.Alg())
+		}
+		if key.Algorithm == AlgorithmHS256 {
+			return key.secret, nil
+		}
+		return key.public, nil
+	})
+
+	// jwt v3 não expõe o erro da keyFunc via Unwrap
+	var validationErr *jwt.ValidationError
+	if errors.As(err, &validationErr) && errors.Is(validationErr.Inner, ErrUnknownKey) {
+		return ErrUnknownKey
+	}
+	return err
+}
+
+// JWK é uma chave pública no formato do RFC 7517
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+}
+
+// JWKS retorna as chaves públicas RSA (atual e de verificação); vazio com HS256
+func (s *KeySet) JWKS() []JWK {
+	var previous []string
+	for id := range s.keys {
+		if id != s.current.ID {
+			previous = append(previous, id)
+		}
+	}
+	sort.Strings(previous)
+	ids := append([]string{s.current.ID}, previous...)
+
+	jwks := []JWK{}
+	for _, id := range ids {
+		key := s.keys[id]
+		if key.Algorithm != AlgorithmRS256 {
+			continue
+		}
+		jwks = append(jwks, JWK{
+			KeyType:   "RSA",
+			Use:       "sig",
+			Algorithm: AlgorithmRS256,
+			KeyID:     key.ID,
+			Modulus:   base64.RawURLEncoding.EncodeToString(key.public.N.Bytes()),
+			Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.public.E)).Bytes()),
+		})
+	}
+	return jwks
+}
+
+func signingMethod(algorithm string) jwt.SigningMethod {
+	if algorithm == AlgorithmRS256 {
+		return jwt.SigningMethodRS256
+	}
+	return jwt.SigningMethodHS256
+}
@@ -0,0 +1,147 @@
+package jwtkeys
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"app/config"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testClaims() jwt.StandardClaims {
+	return jwt.StandardClaims{Subject: "1", ExpiresAt: time.Now().Add(time.Hour).Unix()}
+}
+
+func generateRSAKey(t *testing.T) *rsa.PrivateKey {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return private
+}
+
+func TestKeySet_HS256(t *testing.T) {
+	keys := NewKeySet(NewHMACKey("2026-06", "new-secret"), NewHMACKey("2026-01", "old-secret"))
+
+	token, err := keys.Sign(testClaims())
+	require.NoError(t, err)
+	assert.NoError(t, keys.Parse(token, &jwt.StandardClaims{}))
+
+	t.Run("should accept tokens signed with a previous key", func(t *testing.T) {
+		old, err := NewKeySet(NewHMACKey("2026-01", "old-secret")).Sign(testClaims())
+		require.NoError(t, err)
+
+		assert.NoError(t, keys.Parse(old, &jwt.StandardClaims{}))
+	})
+
+	t.Run("should reject tokens from keys no longer configured", func(t *testing.T) {
+		removed, err := NewKeySet(NewHMACKey("2025-06", "removed-secret")).Sign(testClaims())
+		require.NoError(t, err)
+
+		err = keys.Parse(removed, &jwt.StandardClaims{})
+		assert.ErrorIs(t, err, ErrUnknownKey)
+	})
+
+	t.Run("should validate tokens without kid with the current key", func(t *testing.T) {
+		legacy, err := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims()).SignedString([]byte("new-secret"))
+		require.NoError(t, err)
+
+		assert.NoError(t, keys.Parse(legacy, &jwt.StandardClaims{}))
+	})
+
+	t.Run("should reject expired tokens", func(t *testing.T) {
+		expired, err := keys.Sign(jwt.StandardClaims{ExpiresAt: time.Now().Add(-time.Minute).Unix()})
+		require.NoError(t, err)
+
+		assert.Error(t, keys.Parse(expired, &jwt.StandardClaims{}))
+	})
+}
+
+func TestKeySet_RS256(t *testing.T) {
+	current := generateRSAKey(t)
+	previous := generateRSAKey(t)
+	keys := NewKeySet(NewRSAKey("current", current), NewRSAPublicKey("previous", &previous.PublicKey))
+
+	token, err := keys.Sign(testClaims())
+	require.NoError(t, err)
+	assert.NoError(t, keys.Parse(token, &jwt.StandardClaims{}))
+
+	t.Run("should reject HS256 tokens forged with the public key", func(t *testing.T) {
+		publicDER, err := x509.MarshalPKIXPublicKey(&current.PublicKey)
+		require.NoError(t, err)
+		forged := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims())
+		forged.Header["kid"] = "current"
+		signed, err := forged.SignedString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}))
+		require.NoError(t, err)
+
+		assert.Error(t, keys.Parse(signed, &jwt.StandardClaims{}))
+	})
+
+	t.Run("should publish every public key with the current one first", func(t *testing.T) {
+		jwks := keys.JWKS()
+
+		require.Len(t, jwks, 2)
+		assert.Equal(t, "current", jwks[0].KeyID)
+		assert.Equal(t, "previous", jwks[1].KeyID)
+		assert.Equal(t, "RSA", jwks[0].KeyType)
+		assert.Equal(t, "AQAB", jwks[0].Exponent)
+	})
+}
+
+func TestNewKeySetFromConfig(t *testing.T) {
+	t.Run("should load the RSA private key and the previous public keys", func(t *testing.T) {
+		dir := t.TempDir()
+		private := generateRSAKey(t)
+		previous := generateRSAKey(t)
+
+		privatePath := filepath.Join(dir, "current.pem")
+		require.NoError(t, os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(private),
+		}), 0o600))
+		publicDER, err := x509.MarshalPKIXPublicKey(&previous.PublicKey)
+		require.NoError(t, err)
+		publicPath := filepath.Join(dir, "previous.pem")
+		require.NoError(t, os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0o600))
+
+		keys, err := NewKeySetFromConfig(config.EnvironmentVars{
+			JWT_ALGORITHM:         "rs256",
+			JWT_KEY_ID:            "2026-06",
+			JWT_PRIVATE_KEY_PATH:  privatePath,
+			JWT_VERIFICATION_KEYS: "2026-01=" + publicPath,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, AlgorithmRS256, keys.Algorithm())
+		assert.Len(t, keys.JWKS(), 2)
+	})
+
+	t.Run("should reject invalid configurations", func(t *testing.T) {
+		_, err := NewKeySetFromConfig(config.EnvironmentVars{})
+		assert.ErrorContains(t, err, "JWT_SECRET is required")
+
+		_, err = NewKeySetFromConfig(config.EnvironmentVars{JWT_ALGORITHM: "RS256"})
+		assert.Error(t, err)
+
+		_, err = NewKeySetFromConfig(config.EnvironmentVars{JWT_ALGORITHM: "ES256"})
+		assert.Error(t, err)
+
+		_, err = NewKeySetFromConfig(config.EnvironmentVars{JWT_SECRET: "secret", JWT_VERIFICATION_KEYS: "missing-value"})
+		assert.Error(t, err)
+	})
+
+	t.Run("should not publish shared secrets", func(t *testing.T) {
+		keys, err := NewKeySetFromConfig(config.EnvironmentVars{JWT_SECRET: "secret", JWT_VERIFICATION_KEYS: "old=previous-secret"})
+
+		require.NoError(t, err)
+		assert.Equal(t, AlgorithmHS256, keys.Algorithm())
+		assert.Empty(t, keys.JWKS())
+	})
+}
@@ -0,0 +1,120 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"app/config"
+)
+
+var ErrMailerNotConfigured = errors.New("email delivery is not configured (EMAIL_HOST)")
+
+// SMTPMailer envia emails em texto pelo servidor EMAIL_HOST
+type SMTPMailer struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+	// implicitTLS abre a conexão já com TLS (porta 465); sem ele o STARTTLS é usado quando o servidor oferece
+	implicitTLS bool
+}
+
+// NewMailerFromConfig cria o mailer a partir das variáveis EMAIL_*
+func NewMailerFromConfig(envVars config.EnvironmentVars) (*SMTPMailer, error) {
+	if strings.TrimSpace(envVars.EMAIL_HOST) == "" {
+		return nil, ErrMailerNotConfigured
+	}
+	if strings.TrimSpace(envVars.EMAIL_FROM) == "" {
+		return nil, fmt.Errorf("EMAIL_FROM is required to send emails")
+	}
+
+	port := envVars.EMAIL_PORT
+	if port == 0 {
+		port = 25
+	}
+
+	return &SMTPMailer{
+		host:        envVars.EMAIL_HOST,
+		port:        port,
+		username:    envVars.EMAIL_HOST_USER,
+		password:    envVars.EMAIL_HOST_PASSWORD,
+		from:        envVars.EMAIL_FROM,
+		implicitTLS: envVars.EMAIL_USE_TLS,
+	}, nil
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, to, subject, body string) error {
+	address := net.JoinHostPort(m.host, strconv.Itoa(m.port))
+	message := buildMessage(m.from, to, subject, body, time.Now())
+
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+
+	if !m.implicitTLS {
+		return smtp.SendMail(address, auth, m.from, []string{to}, message)
+	}
+
+	dialer := &tls.Dialer{Config: &tls.Config{ServerName: m.host}}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start smtp session: %w", err)
+	}
+	defer client.Close()
+
+	if auth != nil {
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("smtp authentication failed: %w", err)
+		}
+	}
+	if err := client.Mail(m.from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(message); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// buildMessage monta a mensagem em texto UTF-8; o assunto é codificado para aceitar acentos
+func buildMessage(from, to, subject, body string, now time.Time) []byte {
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", from)
+	fmt.Fprintf(&message, "To: %s\r\n", to)
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&message, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	message.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	message.WriteString("\r\n")
+	message.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return message.Bytes()
+}
@@ -0,0 +1,34 @@
+package mailer
+
+import (
+	"testing"
+	"time"
+
+	"app/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMailerFromConfig(t *testing.T) {
+	_, err := NewMailerFromConfig(config.EnvironmentVars{})
+	assert.ErrorIs(t, err, ErrMailerNotConfigured)
+
+	_, err = NewMailerFromConfig(config.EnvironmentVars{EMAIL_HOST: "mail"})
+	assert.Error(t, err)
+
+	smtpMailer, err := NewMailerFromConfig(config.EnvironmentVars{EMAIL_HOST: "mail", EMAIL_FROM: "noreply@empresa.com"})
+	require.NoError(t, err)
+	assert.Equal(t, 25, smtpMailer.port)
+}
+
+func TestBuildMessage(t *testing.T) {
+	now := time.Date(2026, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	message := string(buildMessage("noreply@empresa.com", "maria@empresa.com", "Redefinição de senha", "Olá\nlink", now))
+
+	assert.Contains(t, message, "To: maria@empresa.com\r\n")
+	assert.Contains(t, message, "Subject: =?utf-8?q?Redefini=C3=A7=C3=A3o_de_senha?=\r\n")
+	assert.Contains(t, message, "Content-Type: text/plain; charset=UTF-8\r\n")
+	assert.Contains(t, message, "\r\n\r\nOlá\r\nlink")
+}
@@ -0,0 +1,82 @@
+package normalizer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+)
+
+const (
+	// headerSearchSize é a região do início do arquivo onde o cabeçalho %PDF- é aceito
+	headerSearchSize = 1024
+	// trailerSearchSize é a região do fim do arquivo onde ficam startxref, trailer e %%EOF
+	trailerSearchSize = 64 * 1024
+	// eofSearchSize tolera lixo após o %%EOF final, comum em PDFs gerados por scanners
+	eofSearchSize = 2048
+)
+
+// encryptPattern reconhece a referência ao dicionário de criptografia no trailer (ou no xref stream)
+var encryptPattern = regexp.MustCompile(`/Encrypt\s*(?:\d+\s+\d+\s+R|<<)`)
+
+// BasicTool valida a estrutura do PDF lendo apenas o início e o fim do arquivo
+// Não junta nem reescreve PDFs: imagens são convertidas sem ferramentas externas, PDFs exigem o qpdf
+type BasicTool struct{}
+
+func (BasicTool) Name() string {
+	return BackendBasic
+}
+
+func (BasicTool) Check(ctx context.Context, path string) error {
+	return checkStructure(path)
+}
+
+func (BasicTool) Rewrite(ctx context.Context, input, output string, options Options) error {
+	return fmt.Errorf("%w: rewriting PDFs requires DOCUMENT_NORMALIZER=%s", ErrUnsupported, BackendQPDF)
+}
+
+func (BasicTool) Merge(ctx context.Context, inputs []string, output string) error {
+	return fmt.Errorf("%w: merging PDFs requires DOCUMENT_NORMALIZER=%s", ErrUnsupported, BackendQPDF)
+}
+
+// checkStructure confere cabeçalho, startxref e %%EOF e rejeita PDFs criptografados
+func checkStructure(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	size := info.Size()
+
+	head := make([]byte, min(size, headerSearchSize))
+	if _, err := io.ReadFull(file, head); err != nil {
+		return fmt.Errorf("%w: %v", ErrCorruptPDF, err)
+	}
+	if !bytes.Contains(head, []byte("%PDF-")) {
+		return fmt.Errorf("%w: missing %%PDF header", ErrCorruptPDF)
+	}
+
+	tail := make([]byte, min(size, trailerSearchSize))
+	if _, err := file.ReadAt(tail, size-int64(len(tail))); err != nil && err != io.EOF {
+		return fmt.Errorf("%w: %v", ErrCorruptPDF, err)
+	}
+
+	if !bytes.Contains(tail[max(0, len(tail)-eofSearchSize):], []byte("%%EOF")) {
+		return fmt.Errorf("%w: missing %%%%EOF marker, the file may be truncated", ErrCorruptPDF)
+	}
+	if !bytes.Contains(tail, []byte("startxref")) {
+		return fmt.Errorf("%w: missing cross-reference table", ErrCorruptPDF)
+	}
+	if encryptPattern.Match(tail) {
+		return ErrEncryptedPDF
+	}
+
+	return nil
+}
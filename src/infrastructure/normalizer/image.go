@@ -0,0 +1,171 @@
+package normalizer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+
+	"app/pkg/pdf"
+)
+
+const (
+	imageJPEGQuality = 90
+	// exifOrientationTag é a tag 0x0112 (Orientation) do IFD0
+	exifOrientationTag = 0x0112
+)
+
+// normalizeImage decodifica a imagem, aplica a orientação EXIF e recodifica em JPEG sem metadados
+// Transparência é achatada sobre fundo branco; de GIFs animados só o primeiro quadro é usado
+// As dimensões são lidas do cabeçalho antes da decodificação: um arquivo pequeno pode declarar uma imagem enorme
+func normalizeImage(data []byte, options Options) (pdf.ImagePage, bool, error) {
+	imageConfig, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return pdf.ImagePage{}, false, fmt.Errorf("%w: invalid image: %v", ErrUnsupportedType, err)
+	}
+	if imageConfig.Width > options.MaxImageWidth || imageConfig.Height > options.MaxImageHeight ||
+		int64(imageConfig.Width)*int64(imageConfig.Height) > int64(options.MaxImagePixels) {
+		return pdf.ImagePage{}, false, fmt.Errorf("%w: %dx%d (max %dx%d, %d pixels)", ErrImageTooLarge,
+			imageConfig.Width, imageConfig.Height, options.MaxImageWidth, options.MaxImageHeight, options.MaxImagePixels)
+	}
+
+	decoded, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return pdf.ImagePage{}, false, fmt.Errorf("%w: invalid image: %v", ErrUnsupportedType, err)
+	}
+
+	bounds := decoded.Bounds()
+	flattened := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(flattened, flattened.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(flattened, flattened.Bounds(), decoded, bounds.Min, draw.Over)
+
+	orientation := 1
+	if format == "jpeg" {
+		orientation = exifOrientation(data)
+	}
+	oriented := orient(flattened, orientation)
+
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, oriented, &jpeg.Options{Quality: imageJPEGQuality}); err != nil {
+		return pdf.ImagePage{}, false, fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	size := oriented.Bounds().Size()
+	return pdf.ImagePage{JPEG: encoded.Bytes(), Width: size.X, Height: size.Y}, orientation > 1, nil
+}
+
+// orient aplica uma das 8 orientações EXIF, devolvendo a imagem como deve ser exibida
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dstW, dstH := w, h
+	if orientation >= 5 {
+		dstW, dstH = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		for x := 0; x < dstW; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // espelhada na horizontal
+				sx, sy = w-1-x, y
+			case 3: // girada 180°
+				sx, sy = w-1-x, h-1-y
+			case 4: // espelhada na vertical
+				sx, sy = x, h-1-y
+			case 5: // transposta
+				sx, sy = y, x
+			case 6: // precisa girar 90° no sentido horário
+				sx, sy = y, h-1-x
+			case 7: // transversa
+				sx, sy = w-1-y, h-1-x
+			case 8: // precisa girar 90° no sentido anti-horário
+				sx, sy = w-1-y, x
+			}
+			dst.SetRGBA(x, y, src.RGBAAt(sx, sy))
+		}
+	}
+	return dst
+}
+
+// exifOrientation lê a tag Orientation do segmento APP1 (Exif) de um JPEG; 1 quando ausente ou inválida
+func exifOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		// SOS: os metadados vêm antes dos dados da imagem
+		if marker == 0xDA || marker == 0xD9 {
+			return 1
+		}
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) || marker == 0xFF {
+			i += 2
+			continue
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return 1
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	if order.Uint16(tiff[2:]) != 42 {
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+
+	entries := int(order.Uint16(tiff[ifd:]))
+	for n := 0; n < entries; n++ {
+		entry := ifd + 2 + n*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == exifOrientationTag {
+			orientation := int(order.Uint16(tiff[entry+8:]))
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+			return orientation
+		}
+	}
+	return 1
+}
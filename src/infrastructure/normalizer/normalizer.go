@@ -0,0 +1,305 @@
+package normalizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"app/config"
+	"app/pkg/pdf"
+)
+
+const (
+	// BackendBasic valida a estrutura do PDF sem ferramentas externas; não junta nem reescreve PDFs
+	BackendBasic = "basic"
+	// BackendQPDF usa o qpdf para validar, juntar, remover metadados e achatar formulários
+	BackendQPDF = "qpdf"
+)
+
+// Etapas registradas no metadata do documento normalizado
+const (
+	StepPDFValidated     = "pdf_validated"
+	StepImagesConverted  = "images_converted"
+	StepOrientationFixed = "orientation_fixed"
+	StepMerged           = "merged"
+	StepMetadataStripped = "metadata_stripped"
+	StepFormsFlattened   = "forms_flattened"
+)
+
+var (
+	// ErrEncryptedPDF é retornado para PDFs criptografados ou protegidos por senha
+	ErrEncryptedPDF = errors.New("PDF is encrypted or password protected")
+	// ErrCorruptPDF é retornado para PDFs com estrutura inválida ou truncados
+	ErrCorruptPDF = errors.New("PDF structure is invalid")
+	// ErrUnsupported é retornado quando a operação precisa de um backend que a ferramenta configurada não oferece
+	ErrUnsupported = errors.New("operation is not supported by the configured normalizer")
+	// ErrUnsupportedType é retornado para partes que não são PDF nem imagem
+	ErrUnsupportedType = errors.New("unsupported type for normalization")
+	// ErrImageTooLarge é retornado para imagens acima das dimensões configuradas
+	ErrImageTooLarge = errors.New("image dimensions exceed the configured limit")
+)
+
+// Limites padrão das imagens; a imagem decodificada ocupa 4 bytes por pixel na memória
+const (
+	defaultMaxImageWidth  = 10000
+	defaultMaxImageHeight = 10000
+	defaultMaxImagePixels = 25000000
+)
+
+// Options define as etapas opcionais da normalização
+type Options struct {
+	// StripMetadata remove o dicionário Info e o XMP do PDF (imagens sempre perdem EXIF ao virar PDF)
+	StripMetadata bool
+	// FlattenForms achata campos de formulário e anotações no conteúdo da página
+	FlattenForms bool
+	// ImagesToPDF converte imagens enviadas isoladamente em PDF de uma página
+	ImagesToPDF bool
+	// MaxImageWidth, MaxImageHeight e MaxImagePixels recusam imagens maiores antes de decodificá-las; 0 usa o padrão
+	MaxImageWidth  int
+	MaxImageHeight int
+	MaxImagePixels int
+}
+
+func (o Options) rewritesPDF() bool {
+	return o.StripMetadata || o.FlattenForms
+}
+
+// Tool executa as operações sobre arquivos PDF em disco
+type Tool interface {
+	Name() string
+	// Check rejeita PDFs corrompidos (ErrCorruptPDF) ou criptografados (ErrEncryptedPDF)
+	Check(ctx context.Context, path string) error
+	// Rewrite grava em output o PDF com as etapas de options aplicadas
+	Rewrite(ctx context.Context, input, output string, options Options) error
+	// Merge junta os PDFs de inputs, na ordem, em output
+	Merge(ctx context.Context, inputs []string, output string) error
+}
+
+// Part é um arquivo de entrada da normalização
+type Part struct {
+	Data     []byte
+	MimeType string
+}
+
+// Result é o PDF normalizado; Changed indica se o conteúdo difere do arquivo enviado
+type Result struct {
+	Data       []byte
+	Changed    bool
+	Steps      []string
+	Normalizer string
+}
+
+// FileResult é o resultado de NormalizeFile; Path é o próprio arquivo de entrada quando nada mudou
+type FileResult struct {
+	Path    string
+	Changed bool
+	Steps   []string
+}
+
+// Normalizer valida PDFs, converte imagens e junta partes em um único PDF
+type Normalizer struct {
+	tool    Tool
+	options Options
+}
+
+func NewNormalizer(tool Tool, options Options) *Normalizer {
+	if options.MaxImageWidth <= 0 {
+		options.MaxImageWidth = defaultMaxImageWidth
+	}
+	if options.MaxImageHeight <= 0 {
+		options.MaxImageHeight = defaultMaxImageHeight
+	}
+	if options.MaxImagePixels <= 0 {
+		options.MaxImagePixels = defaultMaxImagePixels
+	}
+	return &Normalizer{tool: tool, options: options}
+}
+
+// NewNormalizerFromConfig cria o normalizador configurado em DOCUMENT_NORMALIZER
+func NewNormalizerFromConfig(envVars config.EnvironmentVars) (*Normalizer, error) {
+	options := Options{
+		StripMetadata: envVars.DOCUMENT_STRIP_METADATA,
+		FlattenForms:  envVars.DOCUMENT_FLATTEN_FORMS,
+		ImagesToPDF:   envVars.DOCUMENT_IMAGES_TO_PDF,
+
+		MaxImageWidth:  envVars.DOCUMENT_IMAGE_MAX_WIDTH,
+		MaxImageHeight: envVars.DOCUMENT_IMAGE_MAX_HEIGHT,
+		MaxImagePixels: envVars.DOCUMENT_IMAGE_MAX_PIXELS,
+	}
+
+	switch strings.ToLower(strings.TrimSpace(envVars.DOCUMENT_NORMALIZER)) {
+	case "", BackendBasic:
+		if options.rewritesPDF() {
+			return nil, fmt.Errorf("DOCUMENT_STRIP_METADATA and DOCUMENT_FLATTEN_FORMS require DOCUMENT_NORMALIZER=%s", BackendQPDF)
+		}
+		return NewNormalizer(BasicTool{}, options), nil
+	case BackendQPDF:
+		timeout := time.Duration(envVars.DOCUMENT_NORMALIZATION_TIMEOUT) * time.Second
+		return NewNormalizer(NewQPDFTool(envVars.QPDF_PATH, timeout), options), nil
+	default:
+		return nil, fmt.Errorf("unsupported document normalizer: %s", envVars.DOCUMENT_NORMALIZER)
+	}
+}
+
+func (n *Normalizer) Name() string {
+	return n.tool.Name()
+}
+
+func (n *Normalizer) Options() Options {
+	return n.options
+}
+
+// Normalize valida os PDFs, converte as imagens e junta todas as partes, na ordem, em um único PDF
+// Um único PDF sem etapas de reescrita é apenas validado e devolvido sem alteração
+func (n *Normalizer) Normalize(ctx context.Context, parts []Part) (*Result, error) {
+	if len(parts) == 0 {
+		return nil, errors.New("no parts to normalize")
+	}
+
+	workDir, err := os.MkdirTemp("", "docsigner_normalize_*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create normalization directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	result := &Result{Normalizer: n.tool.Name()}
+	var segments []string
+	var images []pdf.ImagePage
+
+	writeSegment := func(data []byte) (string, error) {
+		path := filepath.Join(workDir, fmt.Sprintf("segment_%d.pdf", len(segments)+1))
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			return "", fmt.Errorf("failed to write normalization input: %w", err)
+		}
+		segments = append(segments, path)
+		return path, nil
+	}
+	flushImages := func() error {
+		if len(images) == 0 {
+			return nil
+		}
+		data, err := pdf.RenderImages(images)
+		if err != nil {
+			return err
+		}
+		images = nil
+		result.addStep(StepImagesConverted)
+		_, err = writeSegment(data)
+		return err
+	}
+
+	for i, part := range parts {
+		switch mimeType := strings.ToLower(strings.TrimSpace(part.MimeType)); {
+		case mimeType == "application/pdf":
+			if err := flushImages(); err != nil {
+				return nil, err
+			}
+			path, err := writeSegment(part.Data)
+			if err != nil {
+				return nil, err
+			}
+			if err := n.tool.Check(ctx, path); err != nil {
+				return nil, partError(parts, i, err)
+			}
+			result.addStep(StepPDFValidated)
+		case strings.HasPrefix(mimeType, "image/"):
+			page, rotated, err := normalizeImage(part.Data, n.options)
+			if err != nil {
+				return nil, partError(parts, i, err)
+			}
+			if rotated {
+				result.addStep(StepOrientationFixed)
+			}
+			images = append(images, page)
+		default:
+			return nil, partError(parts, i, fmt.Errorf("%w: %s", ErrUnsupportedType, part.MimeType))
+		}
+	}
+	if err := flushImages(); err != nil {
+		return nil, err
+	}
+
+	if len(parts) == 1 && len(result.Steps) == 1 && result.Steps[0] == StepPDFValidated && !n.options.rewritesPDF() {
+		result.Data = parts[0].Data
+		return result, nil
+	}
+
+	current := segments[0]
+	if len(segments) > 1 {
+		current = filepath.Join(workDir, "merged.pdf")
+		if err := n.tool.Merge(ctx, segments, current); err != nil {
+			return nil, fmt.Errorf("failed to merge documents: %w", err)
+		}
+		result.addStep(StepMerged)
+	}
+
+	if n.options.rewritesPDF() {
+		output := filepath.Join(workDir, "normalized.pdf")
+		if err := n.tool.Rewrite(ctx, current, output, n.options); err != nil {
+			return nil, fmt.Errorf("failed to normalize PDF: %w", err)
+		}
+		current = output
+		result.addRewriteSteps(n.options)
+	}
+
+	data, err := os.ReadFile(current)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read normalized PDF: %w", err)
+	}
+	result.Data = data
+	result.Changed = true
+	return result, nil
+}
+
+// NormalizeFile valida um PDF em disco e aplica as etapas de reescrita configuradas
+// Quando há reescrita, o resultado é gravado ao lado da entrada e cabe ao chamador removê-lo
+func (n *Normalizer) NormalizeFile(ctx context.Context, path string) (*FileResult, error) {
+	if err := n.tool.Check(ctx, path); err != nil {
+		return nil, err
+	}
+
+	result := &FileResult{Path: path, Steps: []string{StepPDFValidated}}
+	if !n.options.rewritesPDF() {
+		return result, nil
+	}
+
+	output := path + ".normalized.pdf"
+	if err := n.tool.Rewrite(ctx, path, output, n.options); err != nil {
+		os.Remove(output)
+		return nil, fmt.Errorf("failed to normalize PDF: %w", err)
+	}
+
+	rewritten := &Result{Steps: result.Steps}
+	rewritten.addRewriteSteps(n.options)
+	return &FileResult{Path: output, Changed: true, Steps: rewritten.Steps}, nil
+}
+
+func (r *Result) addStep(step string) {
+	for _, existing := range r.Steps {
+		if existing == step {
+			return
+		}
+	}
+	r.Steps = append(r.Steps, step)
+}
+
+func (r *Result) addRewriteSteps(options Options) {
+	if options.StripMetadata {
+		r.addStep(StepMetadataStripped)
+	}
+	if options.FlattenForms {
+		r.addStep(StepFormsFlattened)
+	}
+}
+
+// partError identifica a parte com problema quando há mais de uma
+func partError(parts []Part, index int, err error) error {
+	if len(parts) == 1 {
+		return err
+	}
+	return fmt.Errorf("part %d: %w", index+1, err)
+}
@@ -0,0 +1,311 @@
+package normalizer
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"app/config"
+	"app/pkg/pdf"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func samplePDF(t *testing.T, text string) []byte {
+	data, err := pdf.RenderLines([]string{text})
+	require.NoError(t, err)
+	return data
+}
+
+func samplePNG(t *testing.T, width, height int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, 0, color.NRGBA{R: 255, A: 255})
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+// sampleJPEGWithOrientation gera um JPEG com segmento Exif contendo apenas a tag Orientation
+func sampleJPEGWithOrientation(t *testing.T, width, height int, orientation uint16) []byte {
+	var encoded bytes.Buffer
+	require.NoError(t, jpeg.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, width, height)), nil))
+
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08\x00\x01")
+	entry := make([]byte, 12)
+	binary.BigEndian.PutUint16(entry[0:], exifOrientationTag)
+	binary.BigEndian.PutUint16(entry[2:], 3) // SHORT
+	binary.BigEndian.PutUint32(entry[4:], 1)
+	binary.BigEndian.PutUint16(entry[8:], orientation)
+	tiff = append(append(tiff, entry...), 0, 0, 0, 0)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(segment)+2))
+
+	jpegData := encoded.Bytes()
+	return append(append(append([]byte{}, jpegData[:2]...), append(app1, segment...)...), jpegData[2:]...)
+}
+
+func writeTemp(t *testing.T, data []byte) string {
+	path := filepath.Join(t.TempDir(), "input.pdf")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func TestBasicTool_Check(t *testing.T) {
+	valid := samplePDF(t, "Contrato")
+
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{name: "valid", data: valid},
+		{name: "trailing garbage after EOF", data: append(append([]byte{}, valid...), "\r\n\x00\x00"...)},
+		{name: "not a PDF", data: []byte("<html>contrato</html>"), err: ErrCorruptPDF},
+		{name: "truncated", data: valid[:len(valid)/2], err: ErrCorruptPDF},
+		{name: "encrypted", data: bytes.Replace(valid, []byte("/Root 1 0 R"), []byte("/Root 1 0 R /Encrypt 9 0 R"), 1), err: ErrEncryptedPDF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := BasicTool{}.Check(context.Background(), writeTemp(t, tt.data))
+			if tt.err == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestExifOrientation(t *testing.T) {
+	assert.Equal(t, 6, exifOrientation(sampleJPEGWithOrientation(t, 4, 2, 6)))
+	assert.Equal(t, 1, exifOrientation(sampleJPEGWithOrientation(t, 4, 2, 42)))
+	assert.Equal(t, 1, exifOrientation(samplePNG(t, 2, 2)))
+	assert.Equal(t, 1, exifOrientation([]byte{0xFF, 0xD8, 0xFF, 0xE1, 0xFF, 0xFF}))
+}
+
+func TestOrient(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	marker := color.RGBA{R: 255, A: 255}
+	src.SetRGBA(0, 0, marker)
+
+	rotated := orient(src, 6)
+	assert.Equal(t, image.Rect(0, 0, 2, 3), rotated.Bounds())
+	// Girando 90° no sentido horário, o canto superior esquerdo vai para o superior direito
+	assert.Equal(t, marker, rotated.RGBAAt(1, 0))
+
+	assert.Equal(t, marker, orient(src, 3).RGBAAt(2, 1))
+	assert.Same(t, src, orient(src, 1))
+}
+
+func TestNormalizer_Normalize(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should return a valid single PDF untouched", func(t *testing.T) {
+		data := samplePDF(t, "Contrato")
+
+		result, err := NewNormalizer(BasicTool{}, Options{}).Normalize(ctx, []Part{{Data: data, MimeType: "application/pdf"}})
+
+		require.NoError(t, err)
+		assert.False(t, result.Changed)
+		assert.Equal(t, data, result.Data)
+		assert.Equal(t, []string{StepPDFValidated}, result.Steps)
+	})
+
+	t.Run("should merge images into one PDF fixing orientation", func(t *testing.T) {
+		result, err := NewNormalizer(BasicTool{}, Options{}).Normalize(ctx, []Part{
+			{Data: sampleJPEGWithOrientation(t, 40, 20, 6), MimeType: "image/jpeg"},
+			{Data: samplePNG(t, 30, 30), MimeType: "image/png"},
+		})
+
+		require.NoError(t, err)
+		assert.True(t, result.Changed)
+		assert.Equal(t, []string{StepOrientationFixed, StepImagesConverted}, result.Steps)
+		assert.Contains(t, string(result.Data), "/Count 2 >>")
+		// A foto girada passa a ser retrato: 20x40
+		assert.Contains(t, string(result.Data), "/Width 20 /Height 40")
+		assert.NotContains(t, string(result.Data), "Exif")
+		assert.NoError(t, checkStructure(writeTemp(t, result.Data)))
+	})
+
+	t.Run("should require qpdf to merge PDFs", func(t *testing.T) {
+		_, err := NewNormalizer(BasicTool{}, Options{}).Normalize(ctx, []Part{
+			{Data: samplePDF(t, "Contrato"), MimeType: "application/pdf"},
+			{Data: samplePNG(t, 10, 10), MimeType: "image/png"},
+		})
+
+		assert.ErrorIs(t, err, ErrUnsupported)
+	})
+
+	t.Run("should identify the rejected part", func(t *testing.T) {
+		_, err := NewNormalizer(BasicTool{}, Options{}).Normalize(ctx, []Part{
+			{Data: samplePNG(t, 10, 10), MimeType: "image/png"},
+			{Data: []byte("%PDF-1.4\nsem fim"), MimeType: "application/pdf"},
+		})
+
+		assert.ErrorIs(t, err, ErrCorruptPDF)
+		assert.ErrorContains(t, err, "part 2")
+	})
+
+	t.Run("should reject images above the configured dimensions before decoding", func(t *testing.T) {
+		n := NewNormalizer(BasicTool{}, Options{MaxImageWidth: 100, MaxImageHeight: 100, MaxImagePixels: 2000})
+
+		_, err := n.Normalize(ctx, []Part{{Data: samplePNG(t, 101, 10), MimeType: "image/png"}})
+		assert.ErrorIs(t, err, ErrImageTooLarge)
+
+		_, err = n.Normalize(ctx, []Part{{Data: samplePNG(t, 50, 50), MimeType: "image/png"}})
+		assert.ErrorIs(t, err, ErrImageTooLarge)
+
+		_, err = n.Normalize(ctx, []Part{{Data: samplePNG(t, 40, 50), MimeType: "image/png"}})
+		assert.NoError(t, err)
+	})
+
+	t.Run("should reject a small file declaring huge dimensions", func(t *testing.T) {
+		// Cabeçalho de PNG com 100000x100000 pixels, sem os dados da imagem
+		header := samplePNG(t, 1, 1)[:33]
+		binary.BigEndian.PutUint32(header[16:], 100000)
+		binary.BigEndian.PutUint32(header[20:], 100000)
+		binary.BigEndian.PutUint32(header[29:], crc32.ChecksumIEEE(header[12:29]))
+
+		_, err := NewNormalizer(BasicTool{}, Options{}).Normalize(ctx, []Part{{Data: header, MimeType: "image/png"}})
+
+		assert.ErrorIs(t, err, ErrImageTooLarge)
+	})
+
+	t.Run("should reject unsupported parts", func(t *testing.T) {
+		_, err := NewNormalizer(BasicTool{}, Options{}).Normalize(ctx, []Part{{Data: []byte("a,b"), MimeType: "text/csv"}})
+
+		assert.ErrorIs(t, err, ErrUnsupportedType)
+	})
+}
+
+// fakeQPDFScript imita o qpdf: --requires-password e --check inspecionam marcadores no arquivo,
+// --pages concatena as entradas e a reescrita copia a entrada registrando as opções
+const fakeQPDFScript = `
+log="$(dirname "$0")/calls.log"
+echo "$@" >> "$log"
+case "$1" in
+	--requires-password)
+		grep -q ENCRYPTED "$2" && exit 0
+		exit 2 ;;
+	--check)
+		grep -q BROKEN "$2" && { echo "xref not found"; exit 2; }
+		exit 0 ;;
+	--empty)
+		shift 2
+		files=""
+		while [ "$1" != "--" ]; do files="$files $1"; shift; done
+		cat $files > "$2"
+		exit 0 ;;
+esac
+for last; do :; done
+input=""
+for arg; do case "$arg" in --*) ;; *) [ -z "$input" ] && input="$arg" ;; esac; done
+cp "$input" "$last"
+echo "% rewritten" >> "$last"
+exit 3
+`
+
+func writeFakeQPDF(t *testing.T) (string, string) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "qpdf")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+fakeQPDFScript), 0o755))
+	return path, filepath.Join(dir, "calls.log")
+}
+
+func TestQPDFTool(t *testing.T) {
+	ctx := context.Background()
+	binary, calls := writeFakeQPDF(t)
+	tool := NewQPDFTool(binary, time.Second)
+
+	t.Run("should reject encrypted and broken files", func(t *testing.T) {
+		valid := samplePDF(t, "Contrato")
+
+		assert.NoError(t, tool.Check(ctx, writeTemp(t, valid)))
+		assert.ErrorIs(t, tool.Check(ctx, writeTemp(t, append([]byte("%PDF-1.4 ENCRYPTED\n"), valid...))), ErrEncryptedPDF)
+
+		err := tool.Check(ctx, writeTemp(t, append([]byte("%PDF-1.4 BROKEN\n"), valid...)))
+		assert.ErrorIs(t, err, ErrCorruptPDF)
+		assert.ErrorContains(t, err, "xref not found")
+	})
+
+	t.Run("should merge PDFs and images and strip metadata", func(t *testing.T) {
+		n := NewNormalizer(tool, Options{StripMetadata: true, FlattenForms: true})
+
+		result, err := n.Normalize(ctx, []Part{
+			{Data: samplePDF(t, "Contrato"), MimeType: "application/pdf"},
+			{Data: samplePNG(t, 10, 10), MimeType: "image/png"},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{StepPDFValidated, StepImagesConverted, StepMerged, StepMetadataStripped, StepFormsFlattened}, result.Steps)
+		assert.True(t, strings.HasSuffix(string(result.Data), "% rewritten\n"))
+		assert.Equal(t, BackendQPDF, result.Normalizer)
+
+		log, err := os.ReadFile(calls)
+		require.NoError(t, err)
+		assert.Contains(t, string(log), "--empty --pages")
+		assert.Contains(t, string(log), "--remove-metadata --remove-info --generate-appearances --flatten-annotations=all")
+	})
+
+	t.Run("should rewrite files in place for streamed uploads", func(t *testing.T) {
+		input := writeTemp(t, samplePDF(t, "Contrato"))
+
+		result, err := NewNormalizer(tool, Options{StripMetadata: true}).NormalizeFile(ctx, input)
+
+		require.NoError(t, err)
+		assert.True(t, result.Changed)
+		assert.Equal(t, input+".normalized.pdf", result.Path)
+		assert.Equal(t, []string{StepPDFValidated, StepMetadataStripped}, result.Steps)
+	})
+
+	t.Run("should stop slow executions", func(t *testing.T) {
+		slow := filepath.Join(t.TempDir(), "qpdf")
+		require.NoError(t, os.WriteFile(slow, []byte("#!/bin/sh\nexec sleep 5\n"), 0o755))
+
+		err := NewQPDFTool(slow, 100*time.Millisecond).Check(ctx, writeTemp(t, samplePDF(t, "Contrato")))
+
+		assert.ErrorContains(t, err, "timed out")
+	})
+}
+
+func TestNewNormalizerFromConfig(t *testing.T) {
+	n, err := NewNormalizerFromConfig(config.EnvironmentVars{DOCUMENT_NORMALIZER: "basic", DOCUMENT_IMAGES_TO_PDF: true})
+	require.NoError(t, err)
+	assert.Equal(t, BackendBasic, n.Name())
+	assert.True(t, n.Options().ImagesToPDF)
+	assert.Equal(t, defaultMaxImagePixels, n.Options().MaxImagePixels)
+
+	n, err = NewNormalizerFromConfig(config.EnvironmentVars{DOCUMENT_IMAGE_MAX_WIDTH: 2000, DOCUMENT_IMAGE_MAX_HEIGHT: 3000, DOCUMENT_IMAGE_MAX_PIXELS: 6000000})
+	require.NoError(t, err)
+	assert.Equal(t, 2000, n.Options().MaxImageWidth)
+	assert.Equal(t, 3000, n.Options().MaxImageHeight)
+	assert.Equal(t, 6000000, n.Options().MaxImagePixels)
+
+	n, err = NewNormalizerFromConfig(config.EnvironmentVars{DOCUMENT_NORMALIZER: "qpdf", QPDF_PATH: "/usr/local/bin/qpdf", DOCUMENT_STRIP_METADATA: true})
+	require.NoError(t, err)
+	require.IsType(t, &QPDFTool{}, n.tool)
+	assert.Equal(t, "/usr/local/bin/qpdf", n.tool.(*QPDFTool).binary)
+	assert.Equal(t, defaultNormalizationTimeout, n.tool.(*QPDFTool).timeout)
+
+	_, err = NewNormalizerFromConfig(config.EnvironmentVars{DOCUMENT_NORMALIZER: "basic", DOCUMENT_FLATTEN_FORMS: true})
+	assert.ErrorContains(t, err, "require DOCUMENT_NORMALIZER=qpdf")
+
+	_, err = NewNormalizerFromConfig(config.EnvironmentVars{DOCUMENT_NORMALIZER: "ghostscript"})
+	assert.ErrorContains(t, err, "unsupported document normalizer")
+}
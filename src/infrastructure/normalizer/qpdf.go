@@ -0,0 +1,140 @@
+package normalizer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	defaultQPDFBinary            = "qpdf"
+	defaultNormalizationTimeout  = 60 * time.Second
+	maxNormalizationOutputLength = 2048
+)
+
+// Códigos de saída do qpdf
+const (
+	qpdfExitSuccess = 0
+	qpdfExitError   = 2
+	qpdfExitWarning = 3
+)
+
+// QPDFTool normaliza PDFs com o qpdf (versão 11 ou superior)
+type QPDFTool struct {
+	binary  string
+	timeout time.Duration
+}
+
+// NewQPDFTool cria a ferramenta; binary vazio usa "qpdf" do PATH
+func NewQPDFTool(binary string, timeout time.Duration) *QPDFTool {
+	if strings.TrimSpace(binary) == "" {
+		binary = defaultQPDFBinary
+	}
+	if timeout <= 0 {
+		timeout = defaultNormalizationTimeout
+	}
+
+	return &QPDFTool{binary: binary, timeout: timeout}
+}
+
+func (t *QPDFTool) Name() string {
+	return BackendQPDF
+}
+
+// Check aplica a validação básica e depois a do qpdf, que inspeciona todos os objetos do arquivo
+func (t *QPDFTool) Check(ctx context.Context, path string) error {
+	if err := checkStructure(path); err != nil {
+		return err
+	}
+
+	// --requires-password: 0 exige senha, 3 é criptografado sem senha de abertura, 2 não é criptografado
+	code, output, err := t.run(ctx, "--requires-password", path)
+	if err != nil {
+		return err
+	}
+	switch code {
+	case qpdfExitSuccess, qpdfExitWarning:
+		return ErrEncryptedPDF
+	case qpdfExitError:
+	default:
+		return fmt.Errorf("qpdf failed to inspect encryption (exit %d): %s", code, output)
+	}
+
+	code, output, err = t.run(ctx, "--check", path)
+	if err != nil {
+		return err
+	}
+	if code != qpdfExitSuccess && code != qpdfExitWarning {
+		return fmt.Errorf("%w: %s", ErrCorruptPDF, output)
+	}
+	return nil
+}
+
+func (t *QPDFTool) Rewrite(ctx context.Context, input, output string, options Options) error {
+	var args []string
+	if options.StripMetadata {
+		args = append(args, "--remove-metadata", "--remove-info")
+	}
+	if options.FlattenForms {
+		args = append(args, "--generate-appearances", "--flatten-annotations=all")
+	}
+	args = append(args, input, output)
+
+	return t.runChecked(ctx, args...)
+}
+
+func (t *QPDFTool) Merge(ctx context.Context, inputs []string, output string) error {
+	args := append([]string{"--empty", "--pages"}, inputs...)
+	args = append(args, "--", output)
+
+	return t.runChecked(ctx, args...)
+}
+
+// runChecked executa o qpdf aceitando avisos (exit 3), que não impedem a gravação do arquivo
+func (t *QPDFTool) runChecked(ctx context.Context, args ...string) error {
+	code, output, err := t.run(ctx, args...)
+	if err != nil {
+		return err
+	}
+	if code != qpdfExitSuccess && code != qpdfExitWarning {
+		return fmt.Errorf("qpdf failed (exit %d): %s", code, output)
+	}
+	return nil
+}
+
+// run executa o qpdf e retorna o código de saída; err só é preenchido quando o processo não pôde rodar até o fim
+func (t *QPDFTool) run(ctx context.Context, args ...string) (int, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, t.binary, args...)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return 0, "", fmt.Errorf("normalization timed out after %s", t.timeout)
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), truncate(output.String()), nil
+	}
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to run qpdf: %w", err)
+	}
+	return qpdfExitSuccess, truncate(output.String()), nil
+}
+
+func truncate(output string) string {
+	output = strings.TrimSpace(output)
+	if len(output) > maxNormalizationOutputLength {
+		return output[:maxNormalizationOutputLength] + "..."
+	}
+	return output
+}
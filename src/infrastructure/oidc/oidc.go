@@ -0,0 +1,361 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"app/config"
+	"app/entity"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	// leeway tolera diferenças de relógio entre a API e o provedor
+	leeway = time.Minute
+	// minRefreshInterval limita recargas do JWKS provocadas por kids desconhecidos
+	minRefreshInterval = time.Minute
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid identity token")
+	ErrUnknownKey   = errors.New("identity token signed with an unknown key")
+)
+
+// Config configura a validação dos tokens do provedor
+type Config struct {
+	Issuer      string
+	Audience    string
+	JWKSURL     string
+	CacheTTL    time.Duration
+	EmailClaim  string
+	NameClaim   string
+	GroupsClaim string
+	HTTPClient  *http.Client
+}
+
+// Verifier valida tokens do provedor OIDC com as chaves do JWKS, mantidas em cache
+type Verifier struct {
+	config Config
+	now    func() time.Time
+
+	mu          sync.Mutex
+	jwksURL     string
+	keys        map[string]*rsa.PublicKey
+	fetchedAt   time.Time
+	lastAttempt time.Time
+}
+
+// NewVerifier cria o verificador; o JWKS é baixado no primeiro token recebido
+func NewVerifier(cfg Config) (*Verifier, error) {
+	cfg.Issuer = strings.TrimSpace(cfg.Issuer)
+	if cfg.Issuer == "" {
+		return nil, errors.New("OIDC_ISSUER is required")
+	}
+	if strings.TrimSpace(cfg.Audience) == "" {
+		return nil, errors.New("OIDC_AUDIENCE is required")
+	}
+	if cfg.CacheTTL <= 0 {
+		cfg.CacheTTL = time.Hour
+	}
+	if cfg.EmailClaim == "" {
+		cfg.EmailClaim = "email"
+	}
+	if cfg.NameClaim == "" {
+		cfg.NameClaim = "name"
+	}
+	if cfg.GroupsClaim == "" {
+		cfg.GroupsClaim = "groups"
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &Verifier{config: cfg, now: time.Now, jwksURL: cfg.JWKSURL}, nil
+}
+
+// NewVerifierFromConfig cria o verificador a partir das variáveis OIDC_*
+func NewVerifierFromConfig(envVars config.EnvironmentVars) (*Verifier, error) {
+	return NewVerifier(Config{
+		Issuer:      envVars.OIDC_ISSUER,
+		Audience:    envVars.OIDC_AUDIENCE,
+		JWKSURL:     envVars.OIDC_JWKS_URL,
+		CacheTTL:    time.Duration(envVars.OIDC_JWKS_CACHE_MINUTES) * time.Minute,
+		EmailClaim:  envVars.OIDC_EMAIL_CLAIM,
+		NameClaim:   envVars.OIDC_NAME_CLAIM,
+		GroupsClaim: envVars.OIDC_GROUPS_CLAIM,
+	})
+}
+
+// Issuer retorna o issuer esperado nos tokens do provedor
+func (v *Verifier) Issuer() string {
+	return v.config.Issuer
+}
+
+// Verify valida assinatura, issuer, audience e validade do token e extrai a identidade
+func (v *Verifier) Verify(rawToken string) (*entity.ExternalIdentity, error) {
+	claims := jwt.MapClaims{}
+	parser := &jwt.Parser{SkipClaimsValidation: true}
+	_, err := parser.ParseWithClaims(rawToken, claims, func(token *jwt.Token) (interface{}, error) {
+		// Apenas RSA: impede tokens HS256 assinados com a chave pública
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %s", token.Method.Alg())
+		}
+		kid, _ := token.Header["kid"].(string)
+		return v.key(kid)
+	})
+	if err != nil {
+		var validationErr *jwt.ValidationError
+		if errors.As(err, &validationErr) && errors.Is(validationErr.Inner, ErrUnknownKey) {
+			return nil, ErrUnknownKey
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	if err := v.validateClaims(claims); err != nil {
+		return nil, err
+	}
+
+	identity := &entity.ExternalIdentity{
+		Issuer: v.config.Issuer,
+		Email:  stringClaim(claims, v.config.EmailClaim),
+		Name:   stringClaim(claims, v.config.NameClaim),
+		Groups: listClaim(claims, v.config.GroupsClaim),
+	}
+	identity.Subject, _ = claims["sub"].(string)
+	if identity.Subject == "" {
+		return nil, fmt.Errorf("%w: missing sub claim", ErrInvalidToken)
+	}
+	// Sem a claim o email não é considerado verificado: o provedor pode aceitar emails arbitrários no cadastro
+	identity.EmailVerified, _ = claims["email_verified"].(bool)
+
+	return identity, nil
+}
+
+func (v *Verifier) validateClaims(claims jwt.MapClaims) error {
+	now := v.now()
+
+	if issuer, _ := claims["iss"].(string); issuer != v.config.Issuer {
+		return fmt.Errorf("%w: unexpected issuer", ErrInvalidToken)
+	}
+	if !hasAudience(claims["aud"], v.config.Audience) {
+		return fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+	}
+
+	expiresAt, ok := numericClaim(claims, "exp")
+	if !ok {
+		return fmt.Errorf("%w: missing exp claim", ErrInvalidToken)
+	}
+	if now.Add(-leeway).After(expiresAt) {
+		return fmt.Errorf("%w: token is expired", ErrInvalidToken)
+	}
+	if notBefore, ok := numericClaim(claims, "nbf"); ok && now.Add(leeway).Before(notBefore) {
+		return fmt.Errorf("%w: token is not valid yet", ErrInvalidToken)
+	}
+	return nil
+}
+
+// key retorna a chave do kid, recarregando o JWKS quando o cache expirou ou o kid é novo (rotação no provedor)
+func (v *Verifier) key(kid string) (*rsa.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := v.now()
+	stale := v.keys == nil || now.Sub(v.fetchedAt) >= v.config.CacheTTL
+	if key, ok := v.lookup(kid); ok && !stale {
+		return key, nil
+	}
+
+	if now.Sub(v.lastAttempt) >= minRefreshInterval || v.keys == nil {
+		v.lastAttempt = now
+		if err := v.refresh(); err != nil {
+			// Mantém as chaves anteriores se o provedor estiver indisponível
+			log.Printf("Failed to refresh OIDC JWKS: %v", err)
+			if v.keys == nil {
+				return nil, err
+			}
+		} else {
+			v.fetchedAt = now
+		}
+	}
+
+	if key, ok := v.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, ErrUnknownKey
+}
+
+// lookup aceita token sem kid apenas quando o provedor publica uma única chave
+func (v *Verifier) lookup(kid string) (*rsa.PublicKey, bool) {
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, true
+		}
+	}
+	key, ok := v.keys[kid]
+	return key, ok
+}
+
+func (v *Verifier) refresh() error {
+	if v.jwksURL == "" {
+		jwksURL, err := v.discoverJWKSURL()
+		if err != nil {
+			return err
+		}
+		v.jwksURL = jwksURL
+	}
+
+	var document struct {
+		Keys []struct {
+			KeyType string `json:"kty"`
+			Use     string `json:"use"`
+			KeyID   string `json:"kid"`
+			N       string `json:"n"`
+			E       string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := v.getJSON(v.jwksURL, &document); err != nil {
+		return fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(document.Keys))
+	for _, jwk := range document.Keys {
+		if jwk.KeyType != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		key, err := rsaPublicKey(jwk.N, jwk.E)
+		if err != nil {
+			return fmt.Errorf("invalid jwk %s: %w", jwk.KeyID, err)
+		}
+		keys[jwk.KeyID] = key
+	}
+	if len(keys) == 0 {
+		return errors.New("jwks has no RSA signing keys")
+	}
+
+	v.keys = keys
+	return nil
+}
+
+func (v *Verifier) discoverJWKSURL() (string, error) {
+	var discovery struct {
+		Issuer  string `json:"issuer"`
+		JWKSURI string `json:"jwks_uri"`
+	}
+	url := strings.TrimSuffix(v.config.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := v.getJSON(url, &discovery); err != nil {
+		return "", fmt.Errorf("failed to fetch openid configuration: %w", err)
+	}
+	if discovery.JWKSURI == "" {
+		return "", errors.New("openid configuration has no jwks_uri")
+	}
+	return discovery.JWKSURI, nil
+}
+
+func (v *Verifier) getJSON(url string, target interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := v.config.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(target)
+}
+
+func rsaPublicKey(n, e string) (*rsa.PublicKey, error) {
+	modulus, err := base64.RawURLEncoding.DecodeString(n)
+	if err != nil {
+		return nil, err
+	}
+	exponent, err := base64.RawURLEncoding.DecodeString(e)
+	if err != nil {
+		return nil, err
+	}
+	if len(modulus) == 0 || len(exponent) == 0 {
+		return nil, errors.New("empty modulus or exponent")
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(modulus),
+		E: int(new(big.Int).SetBytes(exponent).Int64()),
+	}, nil
+}
+
+func hasAudience(aud interface{}, expected string) bool {
+	switch value := aud.(type) {
+	case string:
+		return value == expected
+	case []interface{}:
+		for _, item := range value {
+			if item == expected {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func numericClaim(claims jwt.MapClaims, name string) (time.Time, bool) {
+	switch value := claims[name].(type) {
+	case float64:
+		return time.Unix(int64(value), 0), true
+	case json.Number:
+		seconds, err := value.Int64()
+		return time.Unix(seconds, 0), err == nil
+	}
+	return time.Time{}, false
+}
+
+// claimValue resolve caminhos com ponto, como "realm_access.roles" do Keycloak
+func claimValue(claims jwt.MapClaims, path string) interface{} {
+	var current interface{} = map[string]interface{}(claims)
+	for _, part := range strings.Split(path, ".") {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = object[part]
+	}
+	return current
+}
+
+func stringClaim(claims jwt.MapClaims, path string) string {
+	value, _ := claimValue(claims, path).(string)
+	return strings.TrimSpace(value)
+}
+
+// listClaim aceita lista JSON ou string separada por espaços ou vírgulas
+func listClaim(claims jwt.MapClaims, path string) []string {
+	var values []string
+	switch value := claimValue(claims, path).(type) {
+	case []interface{}:
+		for _, item := range value {
+			if text, ok := item.(string); ok && text != "" {
+				values = append(values, text)
+			}
+		}
+	case string:
+		values = strings.FieldsFunc(value, func(r rune) bool { return r == ' ' || r == ',' })
+	}
+	return values
+}
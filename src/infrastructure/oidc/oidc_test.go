@@ -0,0 +1,193 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testProvider struct {
+	server     *httptest.Server
+	keys       map[string]*rsa.PrivateKey
+	jwksServed int32
+}
+
+func newTestProvider(t *testing.T) *testProvider {
+	provider := &testProvider{keys: map[string]*rsa.PrivateKey{}}
+	provider.addKey(t, "key-1")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   provider.server.URL,
+			"jwks_uri": provider.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&provider.jwksServed, 1)
+		keys := []map[string]string{}
+		for kid, key := range provider.keys {
+			keys = append(keys, map[string]string{
+				"kty": "RSA",
+				"use": "sig",
+				"kid": kid,
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+	})
+	provider.server = httptest.NewServer(mux)
+	t.Cleanup(provider.server.Close)
+
+	return provider
+}
+
+func (p *testProvider) addKey(t *testing.T, kid string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	p.keys[kid] = key
+}
+
+func (p *testProvider) sign(t *testing.T, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(p.keys[kid])
+	require.NoError(t, err)
+	return signed
+}
+
+func (p *testProvider) claims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":            p.server.URL,
+		"aud":            []string{"assinaturas-api", "account"},
+		"sub":            "f7c1d2",
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"email":          "maria@empresa.com",
+		"email_verified": true,
+		"name":           "Maria Souza",
+		"realm_access": map[string]interface{}{
+			"roles": []string{"assinaturas-admin", "offline_access"},
+		},
+	}
+}
+
+func newTestVerifier(t *testing.T, provider *testProvider) *Verifier {
+	verifier, err := NewVerifier(Config{
+		Issuer:      provider.server.URL,
+		Audience:    "assinaturas-api",
+		GroupsClaim: "realm_access.roles",
+	})
+	require.NoError(t, err)
+	return verifier
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	provider := newTestProvider(t)
+	verifier := newTestVerifier(t, provider)
+
+	t.Run("should extract the identity from a valid token", func(t *testing.T) {
+		identity, err := verifier.Verify(provider.sign(t, "key-1", provider.claims()))
+
+		require.NoError(t, err)
+		assert.Equal(t, provider.server.URL+"|f7c1d2", identity.ExternalID())
+		assert.Equal(t, "maria@empresa.com", identity.Email)
+		assert.Equal(t, "Maria Souza", identity.Name)
+		assert.True(t, identity.EmailVerified)
+		assert.Equal(t, []string{"assinaturas-admin", "offline_access"}, identity.Groups)
+	})
+
+	t.Run("should cache the jwks", func(t *testing.T) {
+		served := atomic.LoadInt32(&provider.jwksServed)
+
+		_, err := verifier.Verify(provider.sign(t, "key-1", provider.claims()))
+
+		require.NoError(t, err)
+		assert.Equal(t, served, atomic.LoadInt32(&provider.jwksServed))
+	})
+
+	t.Run("should reject tokens for other audiences, issuers or already expired", func(t *testing.T) {
+		claims := provider.claims()
+		claims["aud"] = "other-api"
+		_, err := verifier.Verify(provider.sign(t, "key-1", claims))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+
+		claims = provider.claims()
+		claims["iss"] = "https://other-issuer"
+		_, err = verifier.Verify(provider.sign(t, "key-1", claims))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+
+		claims = provider.claims()
+		claims["exp"] = time.Now().Add(-2 * time.Minute).Unix()
+		_, err = verifier.Verify(provider.sign(t, "key-1", claims))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("should reject HS256 tokens", func(t *testing.T) {
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, provider.claims()).SignedString([]byte("secret"))
+		require.NoError(t, err)
+
+		_, err = verifier.Verify(signed)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("should report an unverified email", func(t *testing.T) {
+		claims := provider.claims()
+		claims["email_verified"] = false
+
+		identity, err := verifier.Verify(provider.sign(t, "key-1", claims))
+
+		require.NoError(t, err)
+		assert.False(t, identity.EmailVerified)
+	})
+
+	t.Run("should not consider the email verified without the claim", func(t *testing.T) {
+		claims := provider.claims()
+		delete(claims, "email_verified")
+
+		identity, err := verifier.Verify(provider.sign(t, "key-1", claims))
+
+		require.NoError(t, err)
+		assert.False(t, identity.EmailVerified)
+	})
+}
+
+func TestVerifier_KeyRotation(t *testing.T) {
+	provider := newTestProvider(t)
+	verifier := newTestVerifier(t, provider)
+	now := time.Now()
+	verifier.now = func() time.Time { return now }
+
+	_, err := verifier.Verify(provider.sign(t, "key-1", provider.claims()))
+	require.NoError(t, err)
+
+	provider.addKey(t, "key-2")
+	rotated := provider.sign(t, "key-2", provider.claims())
+
+	// Recargas por kid desconhecido respeitam o intervalo mínimo
+	_, err = verifier.Verify(rotated)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+
+	now = now.Add(minRefreshInterval)
+	_, err = verifier.Verify(rotated)
+	assert.NoError(t, err)
+}
+
+func TestNewVerifier(t *testing.T) {
+	_, err := NewVerifier(Config{Audience: "assinaturas-api"})
+	assert.Error(t, err)
+
+	_, err = NewVerifier(Config{Issuer: "https://sso.empresa.com"})
+	assert.Error(t, err)
+}
@@ -3,6 +3,7 @@ package postgres
 import (
 	"app/config"
 	"app/entity"
+	"app/infrastructure/tenancy"
 	custom_logger "app/pkg/logger"
 	"fmt"
 
@@ -32,6 +33,17 @@ func Migrations() {
 	db.AutoMigrate(&entity.EntityRequirement{})
 	db.AutoMigrate(&entity.EntityWebhook{})
 	db.AutoMigrate(&entity.EntityAutoSignatureTerm{})
+	db.AutoMigrate(&entity.EntityProviderCredential{})
+	db.AutoMigrate(&entity.EntityDocumentTemplate{})
+	db.AutoMigrate(&entity.EntityDocumentVersion{})
+	db.AutoMigrate(&entity.EntityRetentionRun{})
+	db.AutoMigrate(&entity.EntityAPIClient{})
+	db.AutoMigrate(&entity.EntityAPIKey{})
+	db.AutoMigrate(&entity.EntityRefreshToken{})
+	db.AutoMigrate(&entity.EntityRevokedToken{})
+	db.AutoMigrate(&entity.EntityUserToken{})
+	db.AutoMigrate(&entity.EntityTenant{})
+	db.AutoMigrate(&entity.EntityRateLimitCounter{})
 }
 
 func conn() *gorm.DB {
@@ -53,6 +65,11 @@ func conn() *gorm.DB {
 		panic(err)
 	}
 
+	// Repositórios criados a partir de tenancy.Scope ficam restritos ao tenant da requisição
+	if err := tenancy.Register(conn); err != nil {
+		panic(err)
+	}
+
 	gormDB = conn
 
 	return gormDB
@@ -0,0 +1,43 @@
+package provider
+
+import "strings"
+
+// Capabilities descreve as funcionalidades suportadas por um provider de envelope
+// Permite que os chamadores descubram antecipadamente o que cada provider aceita,
+// evitando falhas tardias durante a comunicação com a API externa
+type Capabilities struct {
+	Name                      string   `json:"name"`
+	AuthMethods               []string `json:"auth_methods"`                // Métodos de autenticação aceitos para signatários/requirements
+	Actions                   []string `json:"actions"`                     // Ações de requirement suportadas ("agree", "sign", "provide_evidence")
+	SupportsNotify            bool     `json:"supports_notify"`             // Suporta reenvio de notificações aos signatários
+	SupportsCancel            bool     `json:"supports_cancel"`             // Suporta cancelamento de envelopes
+	SupportsSequentialSigning bool     `json:"supports_sequential_signing"` // Suporta assinatura sequencial por grupos
+	NotifiesSignersOnCreate   bool     `json:"notifies_signers_on_create"`  // A criação já envia o envelope aos signatários (não é seguro repetir em outro provider)
+	SupportsDocumentReplace   bool     `json:"supports_document_replace"`   // Permite remover e recriar documentos enquanto o envelope está em rascunho
+	MaxFileSize               int64    `json:"max_file_size"`               // Tamanho máximo de arquivo em bytes
+	MimeTypes                 []string `json:"mime_types"`                  // Tipos MIME aceitos para documentos
+}
+
+// SupportsAuthMethod verifica se o método de autenticação é suportado
+func (c Capabilities) SupportsAuthMethod(authMethod string) bool {
+	return containsFold(c.AuthMethods, authMethod)
+}
+
+// SupportsAction verifica se a ação de requirement é suportada
+func (c Capabilities) SupportsAction(action string) bool {
+	return containsFold(c.Actions, action)
+}
+
+// SupportsMimeType verifica se o tipo MIME é suportado
+func (c Capabilities) SupportsMimeType(mimeType string) bool {
+	return containsFold(c.MimeTypes, mimeType)
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
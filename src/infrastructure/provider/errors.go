@@ -0,0 +1,30 @@
+package provider
+
+import (
+	"errors"
+	"net"
+)
+
+// notProcessedError é implementado pelos erros dos clientes de provider que sabem dizer se a requisição
+// com certeza não foi processada
+type notProcessedError interface {
+	NotProcessed() bool
+}
+
+// IsNotProcessedError indica se o provider com certeza não processou a requisição (conexão recusada, host
+// inexistente, 503 ou 429), caso em que é seguro tentar outro provider sem deixar um envelope órfão
+// Timeouts e demais 5xx não entram: o provider pode ter criado o envelope antes de falhar
+func IsNotProcessedError(err error) bool {
+	var notProcessed notProcessedError
+	return errors.As(err, &notProcessed) && notProcessed.NotProcessed()
+}
+
+// IsConnectionError indica falha ao estabelecer a conexão, antes de qualquer byte da requisição ser enviado
+func IsConnectionError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
@@ -0,0 +1,22 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsConnectionError(t *testing.T) {
+	refused := &url.Error{Op: "Post", URL: "https://provider", Err: &net.OpError{Op: "dial", Net: "tcp", Err: fmt.Errorf("connection refused")}}
+	noHost := &url.Error{Op: "Post", URL: "https://provider", Err: &net.DNSError{Err: "no such host", Name: "provider"}}
+	reset := &url.Error{Op: "Post", URL: "https://provider", Err: &net.OpError{Op: "read", Net: "tcp", Err: fmt.Errorf("connection reset by peer")}}
+
+	assert.True(t, IsConnectionError(refused))
+	assert.True(t, IsConnectionError(noHost))
+	assert.False(t, IsConnectionError(reset))
+	assert.False(t, IsConnectionError(context.DeadlineExceeded))
+}
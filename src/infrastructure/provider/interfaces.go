@@ -15,6 +15,10 @@ type EnvelopeProvider interface {
 	// CreateDocument cria um documento dentro de um envelope no provider
 	CreateDocument(ctx context.Context, envelopeKey string, document *entity.EntityDocument, internalEnvelopeID int) (documentKey string, err error)
 
+	// DeleteDocument remove um documento de um envelope ainda não ativado
+	// Providers sem suporte (Capabilities.SupportsDocumentReplace falso) retornam erro
+	DeleteDocument(ctx context.Context, envelopeKey string, documentKey string) error
+
 	// CreateSigner cria um signatário no envelope do provider
 	// signerData contém os dados necessários para criar o signatário
 	CreateSigner(ctx context.Context, envelopeKey string, signerData SignerData) (signerKey string, err error)
@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"app/config"
+	"app/infrastructure/storage"
+	"app/pkg/fetcher"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Credentials representa as credenciais de acesso a um provider
+// Campos vazios mantêm o valor configurado nas variáveis de ambiente
+type Credentials struct {
+	APIKey   string
+	BaseURL  string
+	Login    string
+	Password string
+}
+
+// Constructor cria uma instância do provider a partir da configuração global
+// credentials é nil quando devem ser usadas apenas as variáveis de ambiente
+// documentStorage e documentFetcher dão acesso ao conteúdo dos documentos enviados ao provider
+type Constructor func(envVars config.EnvironmentVars, credentials *Credentials, documentStorage storage.Storage, documentFetcher *fetcher.Fetcher, logger *logrus.Logger) (EnvelopeProvider, error)
+
+// Registration agrupa o construtor e as capacidades de um provider registrado
+type Registration struct {
+	Name         string
+	Constructor  Constructor
+	Capabilities Capabilities
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Registration)
+)
+
+// Register registra um provider pelo nome
+// Deve ser chamado no init() do pacote do provider; nomes vazios ou duplicados causam panic
+func Register(name string, constructor Constructor, capabilities Capabilities) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if name == "" {
+		panic("provider: Register called with empty name")
+	}
+	if constructor == nil {
+		panic(fmt.Sprintf("provider: Register constructor is nil for provider '%s'", name))
+	}
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("provider: Register called twice for provider '%s'", name))
+	}
+
+	capabilities.Name = name
+	registry[name] = Registration{
+		Name:         name,
+		Constructor:  constructor,
+		Capabilities: capabilities,
+	}
+}
+
+// Lookup retorna o registro de um provider pelo nome
+func Lookup(name string) (Registration, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	registration, ok := registry[name]
+	return registration, ok
+}
+
+// RegisteredNames retorna os nomes dos providers registrados em ordem alfabética
+func RegisteredNames() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
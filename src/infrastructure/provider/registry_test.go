@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"testing"
+
+	"app/config"
+	"app/infrastructure/storage"
+	"app/pkg/fetcher"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegister(t *testing.T) {
+	constructor := func(envVars config.EnvironmentVars, credentials *Credentials, documentStorage storage.Storage, documentFetcher *fetcher.Fetcher, logger *logrus.Logger) (EnvelopeProvider, error) {
+		return nil, nil
+	}
+
+	t.Run("should register provider and expose capabilities", func(t *testing.T) {
+		Register("registry-test", constructor, Capabilities{Actions: []string{"sign"}})
+
+		registration, ok := Lookup("registry-test")
+
+		assert.True(t, ok)
+		assert.Equal(t, "registry-test", registration.Capabilities.Name)
+		assert.True(t, registration.Capabilities.SupportsAction("SIGN"))
+		assert.Contains(t, RegisteredNames(), "registry-test")
+	})
+
+	t.Run("should panic on duplicated provider", func(t *testing.T) {
+		assert.Panics(t, func() {
+			Register("registry-test", constructor, Capabilities{})
+		})
+	})
+
+	t.Run("should panic on empty name", func(t *testing.T) {
+		assert.Panics(t, func() {
+			Register("", constructor, Capabilities{})
+		})
+	})
+
+	t.Run("should not find unregistered provider", func(t *testing.T) {
+		_, ok := Lookup("unregistered")
+		assert.False(t, ok)
+	})
+}
@@ -1,61 +1,187 @@
 package provider_factory
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	"app/config"
-	"app/infrastructure/clicksign"
-	"app/infrastructure/clicksign_provider"
+	"app/entity"
 	"app/infrastructure/provider"
-	"app/infrastructure/vertc_assinaturas"
-	vertc_assinaturas_provider "app/infrastructure/vertc_assinaturas_provider"
+	"app/infrastructure/storage"
+	"app/pkg/fetcher"
+
+	// Providers se registram no init() dos seus pacotes; o fake só é registrado quando habilitado (ver main.go)
+	_ "app/infrastructure/clicksign_provider"
+	_ "app/infrastructure/vertc_assinaturas_provider"
 
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
-// ProviderFactory cria instâncias de EnvelopeProvider baseado no nome do provider
+// ErrCredentialsNotFound indica que não há credenciais ativas do provider para o tenant
+var ErrCredentialsNotFound = errors.New("provider credentials not found for tenant")
+
+// CredentialsRepository busca credenciais de provider armazenadas por tenant
+type CredentialsRepository interface {
+	GetByTenantAndProvider(tenantID, providerName string) (*entity.EntityProviderCredential, error)
+}
+
+// LookupCapabilities retorna as capacidades de um provider sem exigir uma instância do factory
+// Útil para validações de DTO que precisam rejeitar combinações não suportadas antes de qualquer chamada externa
+func LookupCapabilities(providerName string) (provider.Capabilities, bool) {
+	registration, ok := provider.Lookup(providerName)
+	return registration.Capabilities, ok
+}
+
+// SupportedProviderNames retorna os nomes dos providers registrados em ordem alfabética
+func SupportedProviderNames() []string {
+	return provider.RegisteredNames()
+}
+
+// DefaultProviderOrder retorna a ordem de preferência usada no roteamento "auto"
+// Lida de PROVIDER_FAILOVER_ORDER (separada por vírgula); nomes não registrados são ignorados
+// e, sem configuração, todos os providers registrados são usados em ordem alfabética
+func DefaultProviderOrder(envVars config.EnvironmentVars) []string {
+	if strings.TrimSpace(envVars.PROVIDER_FAILOVER_ORDER) == "" {
+		return SupportedProviderNames()
+	}
+
+	var order []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(envVars.PROVIDER_FAILOVER_ORDER, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		if _, ok := provider.Lookup(name); !ok {
+			continue
+		}
+		seen[name] = true
+		order = append(order, name)
+	}
+
+	return order
+}
+
+// ProviderFactory cria instâncias de EnvelopeProvider a partir do registro de providers
 type ProviderFactory struct {
-	clicksignClient clicksign.ClicksignClientInterface
-	envVars         config.EnvironmentVars
-	logger          *logrus.Logger
+	envVars               config.EnvironmentVars
+	credentialsRepository CredentialsRepository
+	storage               storage.Storage
+	fetcher               *fetcher.Fetcher
+	logger                *logrus.Logger
 }
 
-// NewProviderFactory cria uma nova instância do ProviderFactory
-func NewProviderFactory(envVars config.EnvironmentVars, logger *logrus.Logger) *ProviderFactory {
-	clicksignClient := clicksign.NewClicksignClient(envVars, logger)
+// NewProviderFactory cria uma nova instância do ProviderFactory usando apenas as credenciais das variáveis de ambiente
+func NewProviderFactory(envVars config.EnvironmentVars, documentStorage storage.Storage, documentFetcher *fetcher.Fetcher, logger *logrus.Logger) *ProviderFactory {
+	return &ProviderFactory{
+		envVars: envVars,
+		storage: documentStorage,
+		fetcher: documentFetcher,
+		logger:  logger,
+	}
+}
 
+// NewProviderFactoryWithCredentials cria um ProviderFactory capaz de usar credenciais por tenant armazenadas no banco
+func NewProviderFactoryWithCredentials(envVars config.EnvironmentVars, credentialsRepository CredentialsRepository, documentStorage storage.Storage, documentFetcher *fetcher.Fetcher, logger *logrus.Logger) *ProviderFactory {
 	return &ProviderFactory{
-		clicksignClient: clicksignClient,
-		envVars:         envVars,
-		logger:          logger,
+		envVars:               envVars,
+		credentialsRepository: credentialsRepository,
+		storage:               documentStorage,
+		fetcher:               documentFetcher,
+		logger:                logger,
 	}
 }
 
-// GetProvider retorna uma instância do provider baseado no nome
-// Retorna erro se o provider não for suportado ou não estiver implementado
+// GetProvider retorna uma instância do provider com as credenciais globais
+// Retorna erro se o provider não estiver registrado
 func (f *ProviderFactory) GetProvider(providerName string) (provider.EnvelopeProvider, error) {
-	switch providerName {
-	case "clicksign":
-		return clicksign_provider.NewClicksignProvider(f.clicksignClient, f.logger), nil
-	case "vert-sign":
-		// Criar cliente vertc-assinaturas
-		vertcClient := vertc_assinaturas.NewVertcAssinaturasClient(f.envVars, f.logger)
-		// Criar serviço quick-send
-		quickSendService := vertc_assinaturas.NewQuickSendService(vertcClient, f.logger)
-		directFlowService := vertc_assinaturas.NewDirectFlowService(vertcClient, f.logger)
-		// Criar provider
-		return vertc_assinaturas_provider.NewVertcAssinaturasProvider(quickSendService, directFlowService, f.logger), nil
-	default:
-		return nil, fmt.Errorf("unsupported provider: '%s'. Supported providers: clicksign, vert-sign", providerName)
-	}
-}
-
-// IsProviderSupported verifica se um provider é suportado (mesmo que não implementado)
+	registration, ok := provider.Lookup(providerName)
+	if !ok {
+		return nil, f.unsupportedProviderError(providerName)
+	}
+
+	return registration.Constructor(f.envVars, nil, f.storage, f.fetcher, f.logger)
+}
+
+// GetProviderForTenant retorna uma instância do provider com as credenciais do tenant
+// Sem tenant, utiliza as credenciais globais; com tenant, exige credenciais ativas cadastradas
+func (f *ProviderFactory) GetProviderForTenant(tenantID, providerName string) (provider.EnvelopeProvider, error) {
+	tenantID = strings.TrimSpace(tenantID)
+	if tenantID == "" {
+		return f.GetProvider(providerName)
+	}
+
+	registration, ok := provider.Lookup(providerName)
+	if !ok {
+		return nil, f.unsupportedProviderError(providerName)
+	}
+
+	if f.credentialsRepository == nil {
+		return nil, fmt.Errorf("tenant credentials are not available: credentials repository not configured")
+	}
+
+	credential, err := f.credentialsRepository.GetByTenantAndProvider(tenantID, providerName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: tenant '%s', provider '%s'", ErrCredentialsNotFound, tenantID, providerName)
+		}
+		return nil, fmt.Errorf("failed to load credentials for tenant '%s': %w", tenantID, err)
+	}
+
+	if !credential.Active {
+		return nil, fmt.Errorf("%w: tenant '%s', provider '%s' (inactive)", ErrCredentialsNotFound, tenantID, providerName)
+	}
+
+	f.logger.WithFields(logrus.Fields{
+		"tenant_id": tenantID,
+		"provider":  providerName,
+	}).Debug("Using tenant provider credentials")
+
+	return registration.Constructor(f.envVars, &provider.Credentials{
+		APIKey:   credential.APIKey,
+		BaseURL:  credential.BaseURL,
+		Login:    credential.Login,
+		Password: credential.Password,
+	}, f.storage, f.fetcher, f.logger)
+}
+
+// IsProviderSupported verifica se um provider está registrado
 func (f *ProviderFactory) IsProviderSupported(providerName string) bool {
-	return providerName == "clicksign" || providerName == "vert-sign"
+	_, ok := provider.Lookup(providerName)
+	return ok
 }
 
 // IsProviderImplemented verifica se um provider está implementado
+// Todo provider registrado possui construtor, portanto equivale a IsProviderSupported
 func (f *ProviderFactory) IsProviderImplemented(providerName string) bool {
-	return providerName == "clicksign" || providerName == "vert-sign"
+	return f.IsProviderSupported(providerName)
+}
+
+// GetCapabilities retorna as capacidades de um provider
+// Retorna erro se o provider não for suportado
+func (f *ProviderFactory) GetCapabilities(providerName string) (provider.Capabilities, error) {
+	capabilities, ok := LookupCapabilities(providerName)
+	if !ok {
+		return provider.Capabilities{}, f.unsupportedProviderError(providerName)
+	}
+
+	return capabilities, nil
+}
+
+// ListCapabilities retorna as capacidades de todos os providers registrados, ordenadas por nome
+func (f *ProviderFactory) ListCapabilities() []provider.Capabilities {
+	names := SupportedProviderNames()
+	capabilities := make([]provider.Capabilities, 0, len(names))
+	for _, name := range names {
+		providerCapabilities, _ := LookupCapabilities(name)
+		capabilities = append(capabilities, providerCapabilities)
+	}
+
+	return capabilities
+}
+
+func (f *ProviderFactory) unsupportedProviderError(providerName string) error {
+	return fmt.Errorf("unsupported provider: '%s'. Supported providers: %s", providerName, strings.Join(SupportedProviderNames(), ", "))
 }
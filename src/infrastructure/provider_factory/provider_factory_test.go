@@ -1,14 +1,30 @@
 package provider_factory
 
 import (
+	"errors"
 	"testing"
 
 	"app/config"
+	"app/entity"
+	"app/infrastructure/fake_provider"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
 )
 
+type stubCredentialsRepository struct {
+	credentials map[string]*entity.EntityProviderCredential
+}
+
+func (s *stubCredentialsRepository) GetByTenantAndProvider(tenantID, providerName string) (*entity.EntityProviderCredential, error) {
+	credential, ok := s.credentials[tenantID+"/"+providerName]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return credential, nil
+}
+
 func TestNewProviderFactory(t *testing.T) {
 	envVars := config.EnvironmentVars{
 		CLICKSIGN_API_KEY:        "test-api-key",
@@ -18,7 +34,7 @@ func TestNewProviderFactory(t *testing.T) {
 	}
 
 	logger := logrus.New()
-	factory := NewProviderFactory(envVars, logger)
+	factory := NewProviderFactory(envVars, nil, nil, logger)
 
 	assert.NotNil(t, factory)
 	assert.IsType(t, &ProviderFactory{}, factory)
@@ -33,7 +49,7 @@ func TestProviderFactory_GetProvider(t *testing.T) {
 	}
 
 	logger := logrus.New()
-	factory := NewProviderFactory(envVars, logger)
+	factory := NewProviderFactory(envVars, nil, nil, logger)
 
 	t.Run("should return ClicksignProvider for clicksign", func(t *testing.T) {
 		provider, err := factory.GetProvider("clicksign")
@@ -64,7 +80,7 @@ func TestProviderFactory_IsProviderSupported(t *testing.T) {
 	}
 
 	logger := logrus.New()
-	factory := NewProviderFactory(envVars, logger)
+	factory := NewProviderFactory(envVars, nil, nil, logger)
 
 	t.Run("should return true for clicksign", func(t *testing.T) {
 		assert.True(t, factory.IsProviderSupported("clicksign"))
@@ -88,7 +104,7 @@ func TestProviderFactory_IsProviderImplemented(t *testing.T) {
 	}
 
 	logger := logrus.New()
-	factory := NewProviderFactory(envVars, logger)
+	factory := NewProviderFactory(envVars, nil, nil, logger)
 
 	t.Run("should return true for clicksign", func(t *testing.T) {
 		assert.True(t, factory.IsProviderImplemented("clicksign"))
@@ -102,3 +118,113 @@ func TestProviderFactory_IsProviderImplemented(t *testing.T) {
 		assert.False(t, factory.IsProviderImplemented("invalid-provider"))
 	})
 }
+
+func TestProviderFactory_GetCapabilities(t *testing.T) {
+	factory := NewProviderFactory(config.EnvironmentVars{}, nil, nil, logrus.New())
+
+	t.Run("should return clicksign capabilities", func(t *testing.T) {
+		capabilities, err := factory.GetCapabilities("clicksign")
+		assert.NoError(t, err)
+		assert.Equal(t, "clicksign", capabilities.Name)
+		assert.True(t, capabilities.SupportsNotify)
+		assert.True(t, capabilities.SupportsAuthMethod("icp_brasil"))
+		assert.False(t, capabilities.SupportsAuthMethod("auto_signature"))
+	})
+
+	t.Run("should return vert-sign capabilities", func(t *testing.T) {
+		capabilities, err := factory.GetCapabilities("vert-sign")
+		assert.NoError(t, err)
+		assert.Equal(t, "vert-sign", capabilities.Name)
+		assert.False(t, capabilities.SupportsNotify)
+		assert.False(t, capabilities.SupportsSequentialSigning)
+		assert.True(t, capabilities.SupportsAuthMethod("auto_signature"))
+		assert.False(t, capabilities.SupportsAuthMethod("icp_brasil"))
+	})
+
+	t.Run("should return error for unsupported provider", func(t *testing.T) {
+		_, err := factory.GetCapabilities("invalid-provider")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported provider")
+	})
+}
+
+func TestProviderFactory_ListCapabilities(t *testing.T) {
+	factory := NewProviderFactory(config.EnvironmentVars{}, nil, nil, logrus.New())
+
+	capabilities := factory.ListCapabilities()
+
+	assert.Len(t, capabilities, 2)
+	assert.Equal(t, "clicksign", capabilities[0].Name)
+	assert.Equal(t, "vert-sign", capabilities[1].Name)
+}
+
+func TestProviderFactory_GetProvider_Fake(t *testing.T) {
+	t.Run("should not know the fake provider until it is registered", func(t *testing.T) {
+		_, ok := LookupCapabilities("fake")
+		assert.False(t, ok)
+		assert.NotContains(t, DefaultProviderOrder(config.EnvironmentVars{}), "fake")
+	})
+
+	fake_provider.Register()
+
+	t.Run("should fail while fake provider is disabled", func(t *testing.T) {
+		factory := NewProviderFactory(config.EnvironmentVars{}, nil, nil, logrus.New())
+
+		_, err := factory.GetProvider("fake")
+		assert.Error(t, err)
+	})
+
+	t.Run("should create fake provider when enabled", func(t *testing.T) {
+		factory := NewProviderFactory(config.EnvironmentVars{FAKE_PROVIDER_ENABLED: true}, nil, nil, logrus.New())
+
+		envelopeProvider, err := factory.GetProvider("fake")
+		assert.NoError(t, err)
+		assert.NotNil(t, envelopeProvider)
+	})
+}
+
+func TestProviderFactory_GetProviderForTenant(t *testing.T) {
+	repository := &stubCredentialsRepository{
+		credentials: map[string]*entity.EntityProviderCredential{
+			"unidade-a/clicksign": {TenantID: "unidade-a", Provider: "clicksign", APIKey: "tenant-key", Active: true},
+			"unidade-b/clicksign": {TenantID: "unidade-b", Provider: "clicksign", APIKey: "tenant-key", Active: false},
+		},
+	}
+	factory := NewProviderFactoryWithCredentials(config.EnvironmentVars{}, repository, nil, nil, logrus.New())
+
+	t.Run("should use global credentials without tenant", func(t *testing.T) {
+		provider, err := factory.GetProviderForTenant("", "clicksign")
+		assert.NoError(t, err)
+		assert.NotNil(t, provider)
+	})
+
+	t.Run("should build provider with tenant credentials", func(t *testing.T) {
+		provider, err := factory.GetProviderForTenant("unidade-a", "clicksign")
+		assert.NoError(t, err)
+		assert.NotNil(t, provider)
+	})
+
+	t.Run("should return error when tenant has no credentials", func(t *testing.T) {
+		provider, err := factory.GetProviderForTenant("unidade-a", "vert-sign")
+		assert.Nil(t, provider)
+		assert.True(t, errors.Is(err, ErrCredentialsNotFound))
+	})
+
+	t.Run("should return error when tenant credentials are inactive", func(t *testing.T) {
+		provider, err := factory.GetProviderForTenant("unidade-b", "clicksign")
+		assert.Nil(t, provider)
+		assert.True(t, errors.Is(err, ErrCredentialsNotFound))
+	})
+
+	t.Run("should return error for unsupported provider", func(t *testing.T) {
+		provider, err := factory.GetProviderForTenant("unidade-a", "invalid-provider")
+		assert.Nil(t, provider)
+		assert.Contains(t, err.Error(), "unsupported provider")
+	})
+
+	t.Run("should return error when repository is not configured", func(t *testing.T) {
+		provider, err := NewProviderFactory(config.EnvironmentVars{}, nil, nil, logrus.New()).GetProviderForTenant("unidade-a", "clicksign")
+		assert.Nil(t, provider)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,48 @@
+package ratelimit
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// LoginAttempts bloqueia o login de uma conta depois de maxAttempts falhas dentro da janela de lockout
+// A janela começa na primeira falha; o login com sucesso zera a contagem
+type LoginAttempts struct {
+	store       Store
+	maxAttempts int
+	lockout     time.Duration
+	now         func() time.Time
+}
+
+func NewLoginAttempts(store Store, maxAttempts int, lockout time.Duration) *LoginAttempts {
+	return &LoginAttempts{
+		store:       store,
+		maxAttempts: maxAttempts,
+		lockout:     lockout,
+		now:         time.Now,
+	}
+}
+
+// Locked informa se a conta atingiu o limite de falhas
+func (a *LoginAttempts) Locked(email string) (bool, error) {
+	count, _, err := a.store.Peek(context.Background(), loginKey(email), a.now())
+	if err != nil {
+		return false, err
+	}
+	return count >= a.maxAttempts, nil
+}
+
+func (a *LoginAttempts) Failed(email string) error {
+	_, _, err := a.store.Hit(context.Background(), loginKey(email), a.lockout, a.now())
+	return err
+}
+
+func (a *LoginAttempts) Succeeded(email string) error {
+	return a.store.Reset(context.Background(), loginKey(email))
+}
+
+// loginKey conta as falhas por conta, inclusive de emails inexistentes, para o bloqueio não revelar quais existem
+func loginKey(email string) string {
+	return "login_failures:" + strings.ToLower(strings.TrimSpace(email))
+}
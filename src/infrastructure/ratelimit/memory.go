@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memorySweepInterval limita a frequência da limpeza das janelas expiradas
+const memorySweepInterval = time.Minute
+
+type memoryCounter struct {
+	count     int
+	expiresAt time.Time
+}
+
+// MemoryStore guarda os contadores na memória; cada réplica conta apenas as próprias requisições
+type MemoryStore struct {
+	mu        sync.Mutex
+	counters  map[string]memoryCounter
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{counters: map[string]memoryCounter{}}
+}
+
+func (s *MemoryStore) Hit(_ context.Context, key string, window time.Duration, now time.Time) (int, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	counter, ok := s.counters[key]
+	if !ok || !now.Before(counter.expiresAt) {
+		counter = memoryCounter{expiresAt: now.Add(window)}
+	}
+	counter.count++
+	s.counters[key] = counter
+
+	return counter.count, counter.expiresAt, nil
+}
+
+func (s *MemoryStore) Peek(_ context.Context, key string, now time.Time) (int, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counter, ok := s.counters[key]
+	if !ok || !now.Before(counter.expiresAt) {
+		return 0, time.Time{}, nil
+	}
+	return counter.count, counter.expiresAt, nil
+}
+
+func (s *MemoryStore) Reset(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.counters, key)
+	return nil
+}
+
+// sweep remove as janelas expiradas para a memória não crescer com IPs de passagem
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memorySweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, counter := range s.counters {
+		if !now.Before(counter.expiresAt) {
+			delete(s.counters, key)
+		}
+	}
+}
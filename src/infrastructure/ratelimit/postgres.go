@@ -0,0 +1,58 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"app/entity"
+
+	"gorm.io/gorm"
+)
+
+// hitSQL incrementa o contador numa única instrução, para réplicas concorrentes não perderem requisições
+const hitSQL = `INSERT INTO rate_limit_counters (key, count, expires_at) VALUES (?, 1, ?)
+ON CONFLICT (key) DO UPDATE SET
+	count = CASE WHEN rate_limit_counters.expires_at <= ? THEN 1 ELSE rate_limit_counters.count + 1 END,
+	expires_at = CASE WHEN rate_limit_counters.expires_at <= ? THEN excluded.expires_at ELSE rate_limit_counters.expires_at END
+RETURNING count, expires_at`
+
+// PostgresStore guarda os contadores na tabela rate_limit_counters, compartilhada pelas réplicas
+type PostgresStore struct {
+	db *gorm.DB
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) Hit(ctx context.Context, key string, window time.Duration, now time.Time) (int, time.Time, error) {
+	var counter entity.EntityRateLimitCounter
+	err := s.db.WithContext(ctx).Raw(hitSQL, key, now.Add(window), now, now).Scan(&counter).Error
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return counter.Count, counter.ExpiresAt, nil
+}
+
+func (s *PostgresStore) Peek(ctx context.Context, key string, now time.Time) (int, time.Time, error) {
+	var counter entity.EntityRateLimitCounter
+	err := s.db.WithContext(ctx).Where("key = ? AND expires_at > ?", key, now).First(&counter).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, time.Time{}, nil
+	}
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return counter.Count, counter.ExpiresAt, nil
+}
+
+func (s *PostgresStore) Reset(ctx context.Context, key string) error {
+	return s.db.WithContext(ctx).Where("key = ?", key).Delete(&entity.EntityRateLimitCounter{}).Error
+}
+
+// DeleteExpired remove as janelas encerradas; executado pelo cron
+func (s *PostgresStore) DeleteExpired(before time.Time) (int64, error) {
+	result := s.db.Where("expires_at <= ?", before).Delete(&entity.EntityRateLimitCounter{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,197 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"app/config"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// StoreMemory mantém os contadores na memória da instância
+	StoreMemory = "memory"
+	// StorePostgres compartilha os contadores entre as réplicas pela tabela rate_limit_counters
+	StorePostgres = "postgres"
+)
+
+// Grupos de cota contados por IP; os demais grupos são os das rotas autenticadas, contados por cliente
+const (
+	GroupIP    = "ip"
+	GroupLogin = "login"
+)
+
+var ErrInvalidQuota = errors.New("invalid rate limit quota")
+
+// Quota é o número de requisições aceitas a cada janela
+type Quota struct {
+	Limit  int
+	Window time.Duration
+}
+
+// ParseQuota lê cotas no formato <requisições>/<s|m|h>, ex.: "600/m"
+func ParseQuota(value string) (Quota, error) {
+	limit, unit, found := strings.Cut(strings.TrimSpace(value), "/")
+	if !found {
+		return Quota{}, fmt.Errorf("%w: %q", ErrInvalidQuota, value)
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(limit))
+	if err != nil || n <= 0 {
+		return Quota{}, fmt.Errorf("%w: %q", ErrInvalidQuota, value)
+	}
+
+	var window time.Duration
+	switch strings.TrimSpace(unit) {
+	case "s":
+		window = time.Second
+	case "m":
+		window = time.Minute
+	case "h":
+		window = time.Hour
+	default:
+		return Quota{}, fmt.Errorf("%w: %q", ErrInvalidQuota, value)
+	}
+
+	return Quota{Limit: n, Window: window}, nil
+}
+
+// ParseQuotas lê as cotas por grupo no formato "login=10/m,documents=60/m"
+func ParseQuotas(value string) (map[string]Quota, error) {
+	quotas := map[string]Quota{}
+	for _, item := range strings.Split(value, ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+
+		group, quota, found := strings.Cut(item, "=")
+		group = strings.TrimSpace(group)
+		if !found || group == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidQuota, item)
+		}
+
+		parsed, err := ParseQuota(quota)
+		if err != nil {
+			return nil, err
+		}
+		quotas[group] = parsed
+	}
+	return quotas, nil
+}
+
+// Store guarda os contadores das janelas de cada chave
+type Store interface {
+	// Hit incrementa o contador de key; uma janela expirada recomeça em 1 e vale por window a partir de now
+	Hit(ctx context.Context, key string, window time.Duration, now time.Time) (count int, resetAt time.Time, err error)
+	// Peek retorna o contador da janela corrente sem alterá-lo; zero quando não há janela aberta
+	Peek(ctx context.Context, key string, now time.Time) (count int, resetAt time.Time, err error)
+	Reset(ctx context.Context, key string) error
+}
+
+// Result é o resultado de uma requisição contada, usado nos headers RateLimit-*
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	ResetAt   time.Time
+}
+
+// Limiter aplica as cotas por grupo de rotas
+type Limiter struct {
+	store       Store
+	ipQuota     Quota
+	clientQuota Quota
+	quotas      map[string]Quota
+	now         func() time.Time
+}
+
+// NewLimiter cria o limiter; quotas sobrepõe as cotas padrão por IP e por cliente nos grupos informados
+func NewLimiter(store Store, ipQuota, clientQuota Quota, quotas map[string]Quota) *Limiter {
+	return &Limiter{
+		store:       store,
+		ipQuota:     ipQuota,
+		clientQuota: clientQuota,
+		quotas:      quotas,
+		now:         time.Now,
+	}
+}
+
+// Quota retorna a cota do grupo; sem cota própria, GroupIP usa a cota por IP e os demais a cota por cliente
+func (l *Limiter) Quota(group string) Quota {
+	if quota, ok := l.quotas[group]; ok {
+		return quota
+	}
+	if group == GroupIP {
+		return l.ipQuota
+	}
+	return l.clientQuota
+}
+
+// Allow conta a requisição de key no grupo e informa se ela cabe na cota
+func (l *Limiter) Allow(ctx context.Context, group, key string) (Result, error) {
+	quota := l.Quota(group)
+
+	count, resetAt, err := l.store.Hit(ctx, group+":"+key, quota.Window, l.now())
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to count request: %w", err)
+	}
+
+	return Result{
+		Allowed:   count <= quota.Limit,
+		Limit:     quota.Limit,
+		Remaining: max(quota.Limit-count, 0),
+		ResetAt:   resetAt,
+	}, nil
+}
+
+// NewStoreFromConfig cria o store configurado em RATE_LIMIT_STORE
+func NewStoreFromConfig(envVars config.EnvironmentVars, db *gorm.DB) (Store, error) {
+	switch strings.ToLower(strings.TrimSpace(envVars.RATE_LIMIT_STORE)) {
+	case "", StoreMemory:
+		return NewMemoryStore(), nil
+	case StorePostgres:
+		return NewPostgresStore(db), nil
+	default:
+		return nil, fmt.Errorf("unsupported rate limit store: %s", envVars.RATE_LIMIT_STORE)
+	}
+}
+
+// NewLimiterFromConfig cria o limiter com as cotas RATE_LIMIT_*; nil quando RATE_LIMIT_ENABLED é false
+func NewLimiterFromConfig(envVars config.EnvironmentVars, store Store) (*Limiter, error) {
+	if !envVars.RATE_LIMIT_ENABLED {
+		return nil, nil
+	}
+
+	ipQuota, err := ParseQuota(envVars.RATE_LIMIT_IP)
+	if err != nil {
+		return nil, fmt.Errorf("RATE_LIMIT_IP: %w", err)
+	}
+	clientQuota, err := ParseQuota(envVars.RATE_LIMIT_CLIENT)
+	if err != nil {
+		return nil, fmt.Errorf("RATE_LIMIT_CLIENT: %w", err)
+	}
+	quotas, err := ParseQuotas(envVars.RATE_LIMIT_QUOTAS)
+	if err != nil {
+		return nil, fmt.Errorf("RATE_LIMIT_QUOTAS: %w", err)
+	}
+
+	return NewLimiter(store, ipQuota, clientQuota, quotas), nil
+}
+
+// NewLoginAttemptsFromConfig cria o bloqueio de login; nil quando LOGIN_LOCKOUT_MAX_ATTEMPTS é 0
+func NewLoginAttemptsFromConfig(envVars config.EnvironmentVars, store Store) *LoginAttempts {
+	if envVars.LOGIN_LOCKOUT_MAX_ATTEMPTS <= 0 {
+		return nil
+	}
+
+	lockout := time.Duration(envVars.LOGIN_LOCKOUT_MINUTES) * time.Minute
+	if lockout <= 0 {
+		lockout = 15 * time.Minute
+	}
+	return NewLoginAttempts(store, envVars.LOGIN_LOCKOUT_MAX_ATTEMPTS, lockout)
+}
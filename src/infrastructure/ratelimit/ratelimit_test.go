@@ -0,0 +1,132 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"app/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuota(t *testing.T) {
+	quota, err := ParseQuota(" 600/m ")
+	require.NoError(t, err)
+	assert.Equal(t, Quota{Limit: 600, Window: time.Minute}, quota)
+
+	quotas, err := ParseQuotas("login=10/m, documents=5/s,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Quota{
+		"login":     {Limit: 10, Window: time.Minute},
+		"documents": {Limit: 5, Window: time.Second},
+	}, quotas)
+
+	for _, invalid := range []string{"", "10", "0/m", "-1/m", "10/d", "dez/m"} {
+		_, err := ParseQuota(invalid)
+		assert.ErrorIs(t, err, ErrInvalidQuota, invalid)
+	}
+
+	_, err = ParseQuotas("=10/m")
+	assert.ErrorIs(t, err, ErrInvalidQuota)
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	limiter := NewLimiter(NewMemoryStore(), Quota{Limit: 3, Window: time.Minute}, Quota{Limit: 2, Window: time.Minute}, map[string]Quota{
+		GroupLogin: {Limit: 1, Window: time.Minute},
+	})
+	limiter.now = func() time.Time { return now }
+
+	t.Run("should refuse requests over the quota until the window ends", func(t *testing.T) {
+		for i := 1; i <= 2; i++ {
+			result, err := limiter.Allow(context.Background(), "envelopes", "user:1")
+			require.NoError(t, err)
+			assert.True(t, result.Allowed)
+			assert.Equal(t, 2-i, result.Remaining)
+		}
+
+		result, err := limiter.Allow(context.Background(), "envelopes", "user:1")
+		require.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, 0, result.Remaining)
+		assert.Equal(t, now.Add(time.Minute), result.ResetAt)
+
+		now = now.Add(time.Minute)
+		result, err = limiter.Allow(context.Background(), "envelopes", "user:1")
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+	})
+
+	t.Run("should count groups and clients separately", func(t *testing.T) {
+		result, _ := limiter.Allow(context.Background(), "documents", "user:1")
+		assert.True(t, result.Allowed)
+
+		result, _ = limiter.Allow(context.Background(), "envelopes", "user:2")
+		assert.True(t, result.Allowed)
+	})
+
+	t.Run("should use the quota of each group", func(t *testing.T) {
+		assert.Equal(t, 3, limiter.Quota(GroupIP).Limit)
+		assert.Equal(t, 2, limiter.Quota("webhooks").Limit)
+
+		result, _ := limiter.Allow(context.Background(), GroupLogin, "10.0.0.1")
+		assert.True(t, result.Allowed)
+		result, _ = limiter.Allow(context.Background(), GroupLogin, "10.0.0.1")
+		assert.False(t, result.Allowed)
+	})
+}
+
+func TestLoginAttempts(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	attempts := NewLoginAttempts(NewMemoryStore(), 3, 15*time.Minute)
+	attempts.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, attempts.Failed("Maria@Empresa.com"))
+	}
+	locked, err := attempts.Locked("maria@empresa.com")
+	require.NoError(t, err)
+	assert.False(t, locked)
+
+	require.NoError(t, attempts.Failed("maria@empresa.com "))
+	locked, _ = attempts.Locked("maria@empresa.com")
+	assert.True(t, locked)
+
+	now = now.Add(15 * time.Minute)
+	locked, _ = attempts.Locked("maria@empresa.com")
+	assert.False(t, locked)
+
+	require.NoError(t, attempts.Failed("maria@empresa.com"))
+	require.NoError(t, attempts.Succeeded("maria@empresa.com"))
+	count, _, _ := attempts.store.Peek(context.Background(), loginKey("maria@empresa.com"), now)
+	assert.Zero(t, count)
+}
+
+func TestNewLimiterFromConfig(t *testing.T) {
+	store, err := NewStoreFromConfig(config.EnvironmentVars{RATE_LIMIT_STORE: "memory"}, nil)
+	require.NoError(t, err)
+
+	limiter, err := NewLimiterFromConfig(config.EnvironmentVars{RATE_LIMIT_ENABLED: false}, store)
+	require.NoError(t, err)
+	assert.Nil(t, limiter)
+
+	limiter, err = NewLimiterFromConfig(config.EnvironmentVars{
+		RATE_LIMIT_ENABLED: true,
+		RATE_LIMIT_IP:      "600/m",
+		RATE_LIMIT_CLIENT:  "300/m",
+		RATE_LIMIT_QUOTAS:  "login=10/m",
+	}, store)
+	require.NoError(t, err)
+	assert.Equal(t, 10, limiter.Quota(GroupLogin).Limit)
+
+	_, err = NewLimiterFromConfig(config.EnvironmentVars{RATE_LIMIT_ENABLED: true, RATE_LIMIT_IP: "muito"}, store)
+	assert.ErrorIs(t, err, ErrInvalidQuota)
+
+	_, err = NewStoreFromConfig(config.EnvironmentVars{RATE_LIMIT_STORE: "redis"}, nil)
+	assert.Error(t, err)
+
+	assert.Nil(t, NewLoginAttemptsFromConfig(config.EnvironmentVars{LOGIN_LOCKOUT_MAX_ATTEMPTS: 0}, store))
+	assert.NotNil(t, NewLoginAttemptsFromConfig(config.EnvironmentVars{LOGIN_LOCKOUT_MAX_ATTEMPTS: 5}, store))
+}
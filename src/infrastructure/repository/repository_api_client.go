@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"app/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type RepositoryAPIClient struct {
+	db *gorm.DB
+}
+
+func NewRepositoryAPIClient(db *gorm.DB) *RepositoryAPIClient {
+	return &RepositoryAPIClient{
+		db: db,
+	}
+}
+
+// CreateClient grava o cliente e as chaves em client.Keys na mesma transação
+func (r *RepositoryAPIClient) CreateClient(client *entity.EntityAPIClient) error {
+	return r.db.Create(client).Error
+}
+
+func (r *RepositoryAPIClient) UpdateClient(client *entity.EntityAPIClient) error {
+	return r.db.Omit("Keys").Save(client).Error
+}
+
+func (r *RepositoryAPIClient) GetClientByID(id int) (*entity.EntityAPIClient, error) {
+	var client entity.EntityAPIClient
+	err := r.db.Preload("Keys", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).First(&client, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+func (r *RepositoryAPIClient) GetClients() ([]entity.EntityAPIClient, error) {
+	var clients []entity.EntityAPIClient
+	err := r.db.Preload("Keys", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Order("name").Find(&clients).Error
+	if err != nil {
+		return nil, err
+	}
+	return clients, nil
+}
+
+func (r *RepositoryAPIClient) CreateKey(key *entity.EntityAPIKey) error {
+	return r.db.Create(key).Error
+}
+
+func (r *RepositoryAPIClient) UpdateKey(key *entity.EntityAPIKey) error {
+	return r.db.Save(key).Error
+}
+
+func (r *RepositoryAPIClient) GetKeyByPrefix(prefix string) (*entity.EntityAPIKey, error) {
+	var key entity.EntityAPIKey
+	err := r.db.Where("prefix = ?", prefix).First(&key).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *RepositoryAPIClient) GetKeysByClientID(clientID int) ([]entity.EntityAPIKey, error) {
+	var keys []entity.EntityAPIKey
+	err := r.db.Where("client_id = ?", clientID).Order("id").Find(&keys).Error
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (r *RepositoryAPIClient) TouchKey(id int, at time.Time, ip string) error {
+	return r.db.Model(&entity.EntityAPIKey{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{"last_used_at": at, "last_used_ip": ip}).Error
+}
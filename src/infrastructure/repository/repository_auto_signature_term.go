@@ -38,6 +38,15 @@ func (r *RepositoryAutoSignatureTerm) GetByClicksignKey(key string) (*entity.Ent
 	return &term, nil
 }
 
+func (r *RepositoryAutoSignatureTerm) GetBySignerDocumentation(documentation string) ([]entity.EntityAutoSignatureTerm, error) {
+	var terms []entity.EntityAutoSignatureTerm
+	err := r.db.Where("signer_documentation_index = ?", entity.DocumentationIndex(documentation)).Find(&terms).Error
+	if err != nil {
+		return nil, err
+	}
+	return terms, nil
+}
+
 func (r *RepositoryAutoSignatureTerm) Update(term *entity.EntityAutoSignatureTerm) error {
 	return r.db.Save(term).Error
 }
@@ -93,3 +93,37 @@ func (r *RepositoryDocument) GetByClicksignKey(key string) (*entity.EntityDocume
 
 	return &doc, nil
 }
+
+// GetByContentHash retorna os documentos cujo original ou cópia assinada tem o SHA-256 informado
+func (r *RepositoryDocument) GetByContentHash(sum string) ([]entity.EntityDocument, error) {
+	var documents []entity.EntityDocument
+
+	err := r.db.Where("sha256 = ? OR signed_sha256 = ?", sum, sum).Order("created_at DESC").Find(&documents).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return documents, nil
+}
+
+// ReplaceContent grava a versão substituída e o documento com o novo conteúdo na mesma transação
+func (r *RepositoryDocument) ReplaceContent(document *entity.EntityDocument, previous *entity.EntityDocumentVersion) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(previous).Error; err != nil {
+			return err
+		}
+		return tx.Save(document).Error
+	})
+}
+
+// GetVersions retorna as versões substituídas do documento, da mais antiga para a mais recente
+func (r *RepositoryDocument) GetVersions(documentID int) ([]entity.EntityDocumentVersion, error) {
+	var versions []entity.EntityDocumentVersion
+
+	err := r.db.Where("document_id = ?", documentID).Order("version ASC").Find(&versions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
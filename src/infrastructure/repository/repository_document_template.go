@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"app/entity"
+
+	"gorm.io/gorm"
+)
+
+type RepositoryDocumentTemplate struct {
+	db *gorm.DB
+}
+
+func NewRepositoryDocumentTemplate(db *gorm.DB) *RepositoryDocumentTemplate {
+	return &RepositoryDocumentTemplate{
+		db: db,
+	}
+}
+
+func (r *RepositoryDocumentTemplate) Create(template *entity.EntityDocumentTemplate) error {
+	return r.db.Create(template).Error
+}
+
+func (r *RepositoryDocumentTemplate) GetByID(id int) (*entity.EntityDocumentTemplate, error) {
+	var template entity.EntityDocumentTemplate
+	err := r.db.First(&template, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *RepositoryDocumentTemplate) GetAll() ([]entity.EntityDocumentTemplate, error) {
+	var templates []entity.EntityDocumentTemplate
+	err := r.db.Order("name").Find(&templates).Error
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (r *RepositoryDocumentTemplate) Update(template *entity.EntityDocumentTemplate) error {
+	return r.db.Save(template).Error
+}
+
+func (r *RepositoryDocumentTemplate) Delete(template *entity.EntityDocumentTemplate) error {
+	return r.db.Delete(template).Error
+}
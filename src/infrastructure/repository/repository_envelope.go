@@ -75,6 +75,10 @@ func (r *RepositoryEnvelope) GetEnvelopes(filters entity.EntityEnvelopeFilters)
 		query = query.Where("clicksign_key = ?", filters.ClicksignKey)
 	}
 
+	if filters.CreatedByID != 0 {
+		query = query.Where("created_by_id = ?", filters.CreatedByID)
+	}
+
 	err := query.Order("created_at DESC").Find(&envelopes).Error
 	if err != nil {
 		return nil, err
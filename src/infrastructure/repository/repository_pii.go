@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"app/entity"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// RepositoryPII recifra os dados pessoais após a troca da chave ativa ou a ativação da cifragem
+type RepositoryPII struct {
+	db *gorm.DB
+}
+
+func NewRepositoryPII(db *gorm.DB) *RepositoryPII {
+	return &RepositoryPII{db: db}
+}
+
+var signatoryPIIColumns = []string{"documentation", "birthday", "phone_number", "documentation_index"}
+
+var autoSignatureTermPIIColumns = []string{"signer_documentation", "signer_documentation_index"}
+
+var providerCredentialSecretColumns = []string{"api_key", "password", "webhook_secret"}
+
+// ReencryptSignatories regrava, em lotes, os signatários com dados cifrados por outra chave, em texto puro ou sem blind index
+// currentPrefix vazio (cifragem desativada) apenas completa os blind indexes
+func (r *RepositoryPII) ReencryptSignatories(currentPrefix string, batchSize int) (int64, error) {
+	var total int64
+	lastID := 0
+	for {
+		query := r.db.Where("documentation IS NOT NULL AND documentation <> '' AND documentation_index IS NULL")
+		for _, column := range []string{"documentation", "birthday", "phone_number"} {
+			query = orStaleColumn(query, column, currentPrefix)
+		}
+
+		var signatories []entity.EntitySignatory
+		if err := r.db.Where("id > ?", lastID).Where(query).Order("id").Limit(batchSize).Find(&signatories).Error; err != nil {
+			return total, err
+		}
+		if len(signatories) == 0 {
+			return total, nil
+		}
+
+		for i := range signatories {
+			signatories[i].IndexPII()
+			// UpdateColumns não altera updated_at: a recifragem não é uma alteração do signatário
+			if err := r.db.Model(&signatories[i]).Select(signatoryPIIColumns).UpdateColumns(&signatories[i]).Error; err != nil {
+				return total, err
+			}
+			lastID = signatories[i].ID
+			total++
+		}
+	}
+}
+
+// ReencryptAutoSignatureTerms faz o mesmo que ReencryptSignatories para os termos de assinatura automática
+func (r *RepositoryPII) ReencryptAutoSignatureTerms(currentPrefix string, batchSize int) (int64, error) {
+	var total int64
+	lastID := 0
+	for {
+		query := orStaleColumn(r.db.Where("signer_documentation <> '' AND (signer_documentation_index IS NULL OR signer_documentation_index = '')"), "signer_documentation", currentPrefix)
+
+		var terms []entity.EntityAutoSignatureTerm
+		if err := r.db.Where("id > ?", lastID).Where(query).Order("id").Limit(batchSize).Find(&terms).Error; err != nil {
+			return total, err
+		}
+		if len(terms) == 0 {
+			return total, nil
+		}
+
+		for i := range terms {
+			terms[i].IndexPII()
+			if err := r.db.Model(&terms[i]).Select(autoSignatureTermPIIColumns).UpdateColumns(&terms[i]).Error; err != nil {
+				return total, err
+			}
+			lastID = terms[i].ID
+			total++
+		}
+	}
+}
+
+// ReencryptProviderCredentials recifra a chave de API, a senha e o segredo dos webhooks dos providers; sem cifragem não há o que fazer,
+// pois esses segredos nunca são gravados em texto puro
+func (r *RepositoryPII) ReencryptProviderCredentials(currentPrefix string, batchSize int) (int64, error) {
+	if currentPrefix == "" {
+		return 0, nil
+	}
+
+	var total int64
+	lastID := 0
+	for {
+		query := r.db.Where("1 = 0")
+		for _, column := range providerCredentialSecretColumns {
+			query = orStaleColumn(query, column, currentPrefix)
+		}
+
+		var credentials []entity.EntityProviderCredential
+		if err := r.db.Where("id > ?", lastID).Where(query).Order("id").Limit(batchSize).Find(&credentials).Error; err != nil {
+			return total, err
+		}
+		if len(credentials) == 0 {
+			return total, nil
+		}
+
+		for i := range credentials {
+			if err := r.db.Model(&credentials[i]).Select(providerCredentialSecretColumns).UpdateColumns(&credentials[i]).Error; err != nil {
+				return total, err
+			}
+			lastID = credentials[i].ID
+			total++
+		}
+	}
+}
+
+// orStaleColumn inclui os valores que não estão cifrados com a chave ativa
+func orStaleColumn(query *gorm.DB, column, currentPrefix string) *gorm.DB {
+	if currentPrefix == "" {
+		return query
+	}
+	pattern := escapeLike(currentPrefix) + "%"
+	return query.Or(column+" IS NOT NULL AND "+column+" <> '' AND "+column+" NOT LIKE ?", pattern)
+}
+
+// escapeLike escapa os curingas do LIKE (\ é o escape padrão do Postgres)
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
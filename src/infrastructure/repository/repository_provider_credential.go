@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"app/entity"
+
+	"gorm.io/gorm"
+)
+
+type RepositoryProviderCredential struct {
+	db *gorm.DB
+}
+
+func NewRepositoryProviderCredential(db *gorm.DB) *RepositoryProviderCredential {
+	return &RepositoryProviderCredential{
+		db: db,
+	}
+}
+
+func (r *RepositoryProviderCredential) Create(credential *entity.EntityProviderCredential) error {
+	return r.db.Create(credential).Error
+}
+
+func (r *RepositoryProviderCredential) GetByID(id int) (*entity.EntityProviderCredential, error) {
+	var credential entity.EntityProviderCredential
+	err := r.db.First(&credential, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &credential, nil
+}
+
+func (r *RepositoryProviderCredential) GetByTenantAndProvider(tenantID, providerName string) (*entity.EntityProviderCredential, error) {
+	var credential entity.EntityProviderCredential
+	err := r.db.Where("tenant_id = ? AND provider = ?", tenantID, providerName).First(&credential).Error
+	if err != nil {
+		return nil, err
+	}
+	return &credential, nil
+}
+
+func (r *RepositoryProviderCredential) GetByAccountKey(accountKey string) (*entity.EntityProviderCredential, error) {
+	var credential entity.EntityProviderCredential
+	err := r.db.Where("account_key = ?", accountKey).First(&credential).Error
+	if err != nil {
+		return nil, err
+	}
+	return &credential, nil
+}
+
+func (r *RepositoryProviderCredential) GetAll() ([]entity.EntityProviderCredential, error) {
+	var credentials []entity.EntityProviderCredential
+	err := r.db.Order("tenant_id, provider").Find(&credentials).Error
+	if err != nil {
+		return nil, err
+	}
+	return credentials, nil
+}
+
+func (r *RepositoryProviderCredential) Update(credential *entity.EntityProviderCredential) error {
+	return r.db.Save(credential).Error
+}
+
+func (r *RepositoryProviderCredential) Delete(credential *entity.EntityProviderCredential) error {
+	return r.db.Delete(credential).Error
+}
@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"app/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// retentionBatchSize limita a quantidade de IDs por comando do expurgo
+const retentionBatchSize = 500
+
+// finishedEnvelopeStatuses são os status a partir dos quais o envelope não muda mais
+var finishedEnvelopeStatuses = []string{"completed", "cancelled"}
+
+type RepositoryRetention struct {
+	db *gorm.DB
+}
+
+func NewRepositoryRetention(db *gorm.DB) *RepositoryRetention {
+	return &RepositoryRetention{
+		db: db,
+	}
+}
+
+func (r *RepositoryRetention) FindEnvelopesWithRawData(before time.Time) ([]int, error) {
+	var ids []int
+	err := r.db.Model(&entity.EntityEnvelope{}).
+		Where("clicksign_raw_data IS NOT NULL AND created_at < ?", before).
+		Order("id").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// PurgeEnvelopeRawData usa UpdateColumn para não alterar updated_at nem disparar hooks do envelope
+func (r *RepositoryRetention) PurgeEnvelopeRawData(ids []int) error {
+	return inBatches(ids, func(batch []int) error {
+		return r.db.Model(&entity.EntityEnvelope{}).
+			Where("id IN ?", batch).
+			UpdateColumn("clicksign_raw_data", gorm.Expr("NULL")).Error
+	})
+}
+
+// FindWebhooksWithPayload ignora webhooks pendentes, que ainda precisam do payload para serem processados
+func (r *RepositoryRetention) FindWebhooksWithPayload(before time.Time) ([]int, error) {
+	var ids []int
+	err := r.db.Model(&entity.EntityWebhook{}).
+		Where("status <> ? AND created_at < ?", "pending", before).
+		Where("raw_payload <> '' OR (event_data IS NOT NULL AND event_data <> '')").
+		Order("id").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *RepositoryRetention) PurgeWebhookPayloads(ids []int) error {
+	return inBatches(ids, func(batch []int) error {
+		return r.db.Model(&entity.EntityWebhook{}).
+			Where("id IN ?", batch).
+			UpdateColumns(map[string]interface{}{"raw_payload": "", "event_data": ""}).Error
+	})
+}
+
+// FindFinishedEnvelopes retorna os envelopes concluídos ou cancelados antes de before
+// Envelopes finalizados antes da coluna finished_at existir usam updated_at
+func (r *RepositoryRetention) FindFinishedEnvelopes(before time.Time) ([]entity.EntityEnvelope, error) {
+	var envelopes []entity.EntityEnvelope
+	err := r.db.Select("id", "documents_ids").
+		Where("status IN ? AND COALESCE(finished_at, updated_at) < ?", finishedEnvelopeStatuses, before).
+		Order("id").
+		Find(&envelopes).Error
+	return envelopes, err
+}
+
+func (r *RepositoryRetention) FindSignatoriesWithPII(envelopeIDs []int) ([]int, error) {
+	var ids []int
+	err := inBatches(envelopeIDs, func(batch []int) error {
+		var found []int
+		err := r.db.Model(&entity.EntitySignatory{}).
+			Where("envelope_id IN ? AND anonymized_at IS NULL", batch).
+			Where("documentation IS NOT NULL OR birthday IS NOT NULL OR phone_number IS NOT NULL").
+			Order("id").
+			Pluck("id", &found).Error
+		ids = append(ids, found...)
+		return err
+	})
+	return ids, err
+}
+
+func (r *RepositoryRetention) AnonymizeSignatories(ids []int, at time.Time) error {
+	return inBatches(ids, func(batch []int) error {
+		return r.db.Model(&entity.EntitySignatory{}).
+			Where("id IN ?", batch).
+			UpdateColumns(map[string]interface{}{
+				"documentation":       gorm.Expr("NULL"),
+				"documentation_index": gorm.Expr("NULL"),
+				"birthday":            gorm.Expr("NULL"),
+				"phone_number":        gorm.Expr("NULL"),
+				"anonymized_at":       at,
+			}).Error
+	})
+}
+
+// FindAutoSignatureTermsWithPII retorna os termos sem alteração desde before cujo signatário não tem mais
+// nenhum signatário com CPF em claro, ou seja, que não podem mais ser usados por um envelope em andamento
+func (r *RepositoryRetention) FindAutoSignatureTermsWithPII(before time.Time) ([]int, error) {
+	inUse := r.db.Model(&entity.EntitySignatory{}).
+		Select("documentation_index").
+		Where("anonymized_at IS NULL AND documentation_index IS NOT NULL")
+
+	var ids []int
+	err := r.db.Model(&entity.EntityAutoSignatureTerm{}).
+		Where("anonymized_at IS NULL AND updated_at < ?", before).
+		Where("signer_documentation_index IS NULL OR signer_documentation_index NOT IN (?)", inUse).
+		Order("id").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// AnonymizeAutoSignatureTerms limpa as colunas NOT NULL com string vazia e descarta o payload do provider, que repete o CPF
+func (r *RepositoryRetention) AnonymizeAutoSignatureTerms(ids []int, at time.Time) error {
+	return inBatches(ids, func(batch []int) error {
+		return r.db.Model(&entity.EntityAutoSignatureTerm{}).
+			Where("id IN ?", batch).
+			UpdateColumns(map[string]interface{}{
+				"signer_documentation":       gorm.Expr("''"),
+				"signer_documentation_index": gorm.Expr("NULL"),
+				"signer_birthday":            gorm.Expr("''"),
+				"clicksign_raw_data":         gorm.Expr("NULL"),
+				"anonymized_at":              at,
+			}).Error
+	})
+}
+
+func (r *RepositoryRetention) FindDocumentsWithFiles(ids []int) ([]entity.EntityDocument, error) {
+	var documents []entity.EntityDocument
+	err := inBatches(ids, func(batch []int) error {
+		var found []entity.EntityDocument
+		err := r.db.Where("id IN ? AND files_purged_at IS NULL", batch).Order("id").Find(&found).Error
+		documents = append(documents, found...)
+		return err
+	})
+	return documents, err
+}
+
+func (r *RepositoryRetention) FindDocumentVersions(documentIDs []int) ([]entity.EntityDocumentVersion, error) {
+	var versions []entity.EntityDocumentVersion
+	err := inBatches(documentIDs, func(batch []int) error {
+		var found []entity.EntityDocumentVersion
+		err := r.db.Where("document_id IN ?", batch).Order("document_id, version").Find(&found).Error
+		versions = append(versions, found...)
+		return err
+	})
+	return versions, err
+}
+
+// IsStorageKeyReferenced verifica se algum documento fora de excludeDocumentIDs, ou versão dele, ainda usa a chave
+func (r *RepositoryRetention) IsStorageKeyReferenced(key string, excludeDocumentIDs []int) (bool, error) {
+	pattern := "%" + escapeLike(key) + "%"
+
+	var count int64
+	documents := r.db.Model(&entity.EntityDocument{}).
+		Where("files_purged_at IS NULL").
+		Where("storage_key = ? OR signed_storage_key = ? OR metadata::text LIKE ?", key, key, pattern)
+	if len(excludeDocumentIDs) > 0 {
+		documents = documents.Where("id NOT IN ?", excludeDocumentIDs)
+	}
+	if err := documents.Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+
+	versions := r.db.Model(&entity.EntityDocumentVersion{}).
+		Joins("JOIN documents ON documents.id = document_versions.document_id").
+		Where("documents.files_purged_at IS NULL").
+		Where("document_versions.storage_key = ? OR document_versions.metadata::text LIKE ?", key, pattern)
+	if len(excludeDocumentIDs) > 0 {
+		versions = versions.Where("document_versions.document_id NOT IN ?", excludeDocumentIDs)
+	}
+	if err := versions.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// MarkDocumentFilesPurged limpa as chaves do storage; hashes e metadados continuam para verificação
+func (r *RepositoryRetention) MarkDocumentFilesPurged(ids []int, at time.Time) error {
+	return inBatches(ids, func(batch []int) error {
+		return r.db.Model(&entity.EntityDocument{}).
+			Where("id IN ?", batch).
+			UpdateColumns(map[string]interface{}{
+				"storage_key":        "",
+				"signed_storage_key": "",
+				"files_purged_at":    at,
+			}).Error
+	})
+}
+
+func (r *RepositoryRetention) CreateRun(run *entity.EntityRetentionRun) error {
+	return r.db.Create(run).Error
+}
+
+func (r *RepositoryRetention) UpdateRun(run *entity.EntityRetentionRun) error {
+	return r.db.Save(run).Error
+}
+
+func (r *RepositoryRetention) GetRuns(limit int) ([]entity.EntityRetentionRun, error) {
+	var runs []entity.EntityRetentionRun
+	err := r.db.Order("id DESC").Limit(limit).Find(&runs).Error
+	if err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+func (r *RepositoryRetention) GetRunByID(id int) (*entity.EntityRetentionRun, error) {
+	var run entity.EntityRetentionRun
+	err := r.db.First(&run, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func inBatches(ids []int, fn func(batch []int) error) error {
+	for start := 0; start < len(ids); start += retentionBatchSize {
+		end := start + retentionBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		if err := fn(ids[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
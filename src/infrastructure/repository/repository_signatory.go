@@ -75,6 +75,10 @@ func (r *RepositorySignatory) GetSignatories(filters entity.EntitySignatoryFilte
 		query = query.Where("name ILIKE ?", "%"+filters.Name+"%")
 	}
 
+	if filters.Documentation != "" {
+		query = query.Where("documentation_index = ?", entity.DocumentationIndex(filters.Documentation))
+	}
+
 	err := query.Order("created_at DESC").Find(&signatories).Error
 	if err != nil {
 		return nil, err
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"app/entity"
+
+	"gorm.io/gorm"
+)
+
+type RepositoryTenant struct {
+	db *gorm.DB
+}
+
+func NewRepositoryTenant(db *gorm.DB) *RepositoryTenant {
+	return &RepositoryTenant{
+		db: db,
+	}
+}
+
+func (r *RepositoryTenant) Create(tenant *entity.EntityTenant) error {
+	return r.db.Create(tenant).Error
+}
+
+func (r *RepositoryTenant) Update(tenant *entity.EntityTenant) error {
+	return r.db.Save(tenant).Error
+}
+
+func (r *RepositoryTenant) GetByID(id string) (*entity.EntityTenant, error) {
+	var tenant entity.EntityTenant
+	if err := r.db.Where("id = ?", id).First(&tenant).Error; err != nil {
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+func (r *RepositoryTenant) GetAll() ([]entity.EntityTenant, error) {
+	var tenants []entity.EntityTenant
+	if err := r.db.Order("id").Find(&tenants).Error; err != nil {
+		return nil, err
+	}
+	return tenants, nil
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"app/entity"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RepositoryToken struct {
+	db *gorm.DB
+}
+
+func NewRepositoryToken(db *gorm.DB) *RepositoryToken {
+	return &RepositoryToken{
+		db: db,
+	}
+}
+
+func (r *RepositoryToken) CreateRefreshToken(token *entity.EntityRefreshToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *RepositoryToken) GetRefreshTokenByHash(hash string) (*entity.EntityRefreshToken, error) {
+	var token entity.EntityRefreshToken
+	if err := r.db.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkRefreshTokenRotated só altera tokens ainda ativos; nenhuma linha afetada significa que outra requisição já usou o token
+func (r *RepositoryToken) MarkRefreshTokenRotated(id int, at time.Time) (bool, error) {
+	result := r.db.Model(&entity.EntityRefreshToken{}).
+		Where("id = ? AND rotated_at IS NULL AND revoked_at IS NULL", id).
+		UpdateColumn("rotated_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *RepositoryToken) RevokeRefreshTokenFamily(familyID string, at time.Time) error {
+	return r.db.Model(&entity.EntityRefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		UpdateColumn("revoked_at", at).Error
+}
+
+func (r *RepositoryToken) RevokeUserRefreshTokens(userID int, at time.Time) error {
+	return r.db.Model(&entity.EntityRefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		UpdateColumn("revoked_at", at).Error
+}
+
+// RevokeToken é idempotente: revogar de novo o mesmo jti não gera erro
+func (r *RepositoryToken) RevokeToken(token *entity.EntityRevokedToken) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(token).Error
+}
+
+func (r *RepositoryToken) IsTokenRevoked(jti string) (bool, error) {
+	var count int64
+	err := r.db.Model(&entity.EntityRevokedToken{}).Where("jti = ?", jti).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *RepositoryToken) SetTokensRevokedAt(userID int, at time.Time) error {
+	return r.db.Model(&entity.EntityUser{}).Where("id = ?", userID).UpdateColumn("tokens_revoked_at", at).Error
+}
+
+// DeleteExpiredTokens remove o que não pode mais ser aceito nem reutilizado
+func (r *RepositoryToken) DeleteExpiredTokens(before time.Time) (int64, error) {
+	var deleted int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("expires_at < ?", before).Delete(&entity.EntityRefreshToken{})
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted += result.RowsAffected
+
+		result = tx.Where("expires_at < ?", before).Delete(&entity.EntityRevokedToken{})
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted += result.RowsAffected
+
+		result = tx.Where("expires_at < ?", before).Delete(&entity.EntityUserToken{})
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted += result.RowsAffected
+		return nil
+	})
+	return deleted, err
+}
+
+func (r *RepositoryToken) CreateUserToken(token *entity.EntityUserToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *RepositoryToken) GetUserTokenByHash(hash string) (*entity.EntityUserToken, error) {
+	var token entity.EntityUserToken
+	if err := r.db.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkUserTokenUsed só altera tokens ainda não usados; nenhuma linha afetada significa que outra requisição já usou o token
+func (r *RepositoryToken) MarkUserTokenUsed(id int, at time.Time) (bool, error) {
+	result := r.db.Model(&entity.EntityUserToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		UpdateColumn("used_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// InvalidateUserTokens inutiliza os tokens pendentes do usuário; purpose vazio inclui todas as finalidades
+func (r *RepositoryToken) InvalidateUserTokens(userID int, purpose string, at time.Time) error {
+	query := r.db.Model(&entity.EntityUserToken{}).Where("user_id = ? AND used_at IS NULL", userID)
+	if purpose != "" {
+		query = query.Where("purpose = ?", purpose)
+	}
+	return query.UpdateColumn("used_at", at).Error
+}
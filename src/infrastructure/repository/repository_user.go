@@ -26,6 +26,12 @@ func (u *RepositoryUser) GetByMail(email string) (user *entity.EntityUser, err e
 	return user, err
 }
 
+func (u *RepositoryUser) GetByExternalID(externalID string) (user *entity.EntityUser, err error) {
+	err = u.DB.Where("external_id = ?", externalID).First(&user).Error
+
+	return user, err
+}
+
 func (u *RepositoryUser) CreateUser(user *entity.EntityUser) error {
 
 	return u.DB.Create(&user).Error
@@ -39,7 +45,28 @@ func (u *RepositoryUser) UpdateUser(user *entity.EntityUser) error {
 		return err
 	}
 
-	return u.DB.Save(&user).Error
+	// Senha, situação e revogação de sessões mudam apenas pelos fluxos próprios (SetPassword, SetActive, logout)
+	return u.DB.Omit("password", "active", "tokens_revoked_at", "created_at").Save(&user).Error
+}
+
+func (u *RepositoryUser) SetPassword(id int, passwordHash string) error {
+	return u.updateColumn(id, "password", passwordHash)
+}
+
+func (u *RepositoryUser) SetActive(id int, active bool) error {
+	return u.updateColumn(id, "active", active)
+}
+
+// updateColumn retorna gorm.ErrRecordNotFound quando o usuário não existe (ou é de outro tenant)
+func (u *RepositoryUser) updateColumn(id int, column string, value interface{}) error {
+	result := u.DB.Model(&entity.EntityUser{}).Where("id = ?", id).UpdateColumn(column, value)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
 
 func (u *RepositoryUser) DeleteUser(user *entity.EntityUser) error {
@@ -0,0 +1,131 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	defaultClamAVAddress = "localhost:3310"
+	defaultScanTimeout   = 60 * time.Second
+	// clamAVChunkSize fica bem abaixo do StreamMaxLength padrão do clamd (25MB)
+	clamAVChunkSize = 64 * 1024
+	// maxClamAVReplyLength limita a leitura da resposta, que é uma única linha
+	maxClamAVReplyLength = 4096
+)
+
+// ClamAVScanner verifica o conteúdo com o clamd pelo comando INSTREAM (TCPSocket)
+type ClamAVScanner struct {
+	address string
+	timeout time.Duration
+}
+
+// NewClamAVScanner cria o scanner; address vazio usa localhost:3310
+func NewClamAVScanner(address string, timeout time.Duration) *ClamAVScanner {
+	if strings.TrimSpace(address) == "" {
+		address = defaultClamAVAddress
+	}
+	if timeout <= 0 {
+		timeout = defaultScanTimeout
+	}
+
+	return &ClamAVScanner{address: address, timeout: timeout}
+}
+
+func (s *ClamAVScanner) Name() string {
+	return BackendClamAV
+}
+
+// Scan envia r em blocos prefixados pelo tamanho (big-endian) e interpreta a resposta do clamd:
+// "stream: OK", "stream: <assinatura> FOUND" ou "<mensagem> ERROR"
+func (s *ClamAVScanner) Scan(ctx context.Context, r io.Reader) (*Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to clamd: %w", err)
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	if err := s.stream(conn, r); err != nil {
+		// O clamd encerra a conexão ao recusar o stream (ex.: StreamMaxLength); a resposta explica o motivo
+		if reply, replyErr := readReply(conn); replyErr == nil && reply != "" {
+			return parseReply(reply)
+		}
+		return nil, err
+	}
+
+	reply, err := readReply(conn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read clamd reply: %w", err)
+	}
+	return parseReply(reply)
+}
+
+func (s *ClamAVScanner) stream(conn net.Conn, r io.Reader) error {
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return fmt.Errorf("failed to send command to clamd: %w", err)
+	}
+
+	chunk := make([]byte, 4+clamAVChunkSize)
+	for {
+		n, readErr := io.ReadFull(r, chunk[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(chunk[:4], uint32(n))
+			if _, err := conn.Write(chunk[:4+n]); err != nil {
+				return fmt.Errorf("failed to stream content to clamd: %w", err)
+			}
+		}
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+		if readErr != nil {
+			return fmt.Errorf("failed to read content to scan: %w", readErr)
+		}
+	}
+
+	// Bloco de tamanho zero encerra o stream
+	if _, err := conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return fmt.Errorf("failed to stream content to clamd: %w", err)
+	}
+	return nil
+}
+
+// readReply lê a resposta terminada em \0 (comandos com prefixo "z")
+func readReply(conn net.Conn) (string, error) {
+	reply, err := bufio.NewReader(io.LimitReader(conn, maxClamAVReplyLength)).ReadString(0)
+	if err != nil && !(errors.Is(err, io.EOF) && reply != "") {
+		return "", err
+	}
+	return strings.TrimSpace(strings.TrimRight(reply, "\x00")), nil
+}
+
+func parseReply(reply string) (*Result, error) {
+	// Respostas do INSTREAM vêm prefixadas por "stream: "
+	message := strings.TrimSpace(strings.TrimPrefix(reply, "stream:"))
+
+	switch {
+	case message == "OK":
+		return &Result{}, nil
+	case strings.HasSuffix(message, " FOUND"):
+		return &Result{Infected: true, Threat: strings.TrimSpace(strings.TrimSuffix(message, " FOUND"))}, nil
+	case strings.HasSuffix(message, " ERROR"):
+		return nil, fmt.Errorf("clamd error: %s", strings.TrimSpace(strings.TrimSuffix(message, " ERROR")))
+	default:
+		return nil, fmt.Errorf("unexpected clamd reply: %q", reply)
+	}
+}
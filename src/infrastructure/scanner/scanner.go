@@ -0,0 +1,61 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"app/config"
+)
+
+const (
+	// BackendDisabled não verifica os documentos
+	BackendDisabled = "disabled"
+	// BackendClamAV envia o conteúdo ao clamd pelo protocolo INSTREAM
+	BackendClamAV = "clamav"
+)
+
+// Scanner verifica se o conteúdo de um documento contém malware
+type Scanner interface {
+	// Scan lê r até o fim e retorna o resultado da verificação
+	Scan(ctx context.Context, r io.Reader) (*Result, error)
+	// Name identifica o scanner no metadata do documento
+	Name() string
+}
+
+// Result é o resultado de uma verificação; Threat traz o nome da assinatura encontrada
+type Result struct {
+	Infected bool
+	Threat   string
+}
+
+// NewScannerFromConfig cria o scanner configurado em DOCUMENT_SCANNER
+func NewScannerFromConfig(envVars config.EnvironmentVars) (Scanner, error) {
+	switch strings.ToLower(strings.TrimSpace(envVars.DOCUMENT_SCANNER)) {
+	case "", BackendDisabled:
+		return DisabledScanner{}, nil
+	case BackendClamAV:
+		return NewClamAVScanner(envVars.CLAMAV_ADDRESS, time.Duration(envVars.DOCUMENT_SCAN_TIMEOUT)*time.Second), nil
+	default:
+		return nil, fmt.Errorf("unsupported document scanner: %s", envVars.DOCUMENT_SCANNER)
+	}
+}
+
+// IsDisabled indica se s foi configurado para não fazer verificação alguma
+// Um scanner nil não conta como desabilitado: quem o recebe deve recusar o conteúdo
+func IsDisabled(s Scanner) bool {
+	return s != nil && s.Name() == BackendDisabled
+}
+
+// DisabledScanner considera todo conteúdo limpo sem lê-lo
+type DisabledScanner struct{}
+
+func (DisabledScanner) Scan(ctx context.Context, r io.Reader) (*Result, error) {
+	return &Result{}, nil
+}
+
+func (DisabledScanner) Name() string {
+	return BackendDisabled
+}
@@ -0,0 +1,181 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"app/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// eicar é o arquivo de teste padrão reconhecido por todos os antivírus
+const eicar = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// startFakeClamd imita o clamd: lê um INSTREAM e responde com reply(conteúdo recebido)
+// Com maxLength > 0, recusa o stream como o clamd ao exceder StreamMaxLength
+func startFakeClamd(t *testing.T, maxLength int, reply func(content []byte) string) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveFakeClamd(conn, maxLength, reply)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func serveFakeClamd(conn net.Conn, maxLength int, reply func(content []byte) string) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	command, err := reader.ReadString(0)
+	if err != nil || command != "zINSTREAM\x00" {
+		conn.Write([]byte("UNKNOWN COMMAND\x00"))
+		return
+	}
+
+	var content bytes.Buffer
+	size := make([]byte, 4)
+	for {
+		if _, err := io.ReadFull(reader, size); err != nil {
+			return
+		}
+		length := binary.BigEndian.Uint32(size)
+		if length == 0 {
+			break
+		}
+		if _, err := io.CopyN(&content, reader, int64(length)); err != nil {
+			return
+		}
+		if maxLength > 0 && content.Len() > maxLength {
+			conn.Write([]byte("INSTREAM size limit exceeded. ERROR\x00"))
+			return
+		}
+	}
+	conn.Write([]byte(reply(content.Bytes()) + "\x00"))
+}
+
+func eicarReply(content []byte) string {
+	if bytes.Contains(content, []byte("EICAR-STANDARD-ANTIVIRUS-TEST-FILE")) {
+		return "stream: Eicar-Test-Signature FOUND"
+	}
+	return "stream: OK"
+}
+
+func TestClamAVScanner(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should report clean content", func(t *testing.T) {
+		s := NewClamAVScanner(startFakeClamd(t, 0, eicarReply), time.Second)
+
+		result, err := s.Scan(ctx, strings.NewReader("%PDF-1.4\ncontrato\n"))
+
+		require.NoError(t, err)
+		assert.False(t, result.Infected)
+		assert.Equal(t, BackendClamAV, s.Name())
+	})
+
+	t.Run("should report the threat found", func(t *testing.T) {
+		s := NewClamAVScanner(startFakeClamd(t, 0, eicarReply), time.Second)
+
+		result, err := s.Scan(ctx, strings.NewReader(eicar))
+
+		require.NoError(t, err)
+		assert.True(t, result.Infected)
+		assert.Equal(t, "Eicar-Test-Signature", result.Threat)
+	})
+
+	t.Run("should stream content larger than one chunk", func(t *testing.T) {
+		var received int
+		s := NewClamAVScanner(startFakeClamd(t, 0, func(content []byte) string {
+			received = len(content)
+			return "stream: OK"
+		}), time.Second)
+
+		_, err := s.Scan(ctx, bytes.NewReader(bytes.Repeat([]byte("a"), 3*clamAVChunkSize+10)))
+
+		require.NoError(t, err)
+		assert.Equal(t, 3*clamAVChunkSize+10, received)
+	})
+
+	t.Run("should return clamd errors", func(t *testing.T) {
+		s := NewClamAVScanner(startFakeClamd(t, 1024, eicarReply), time.Second)
+
+		_, err := s.Scan(ctx, bytes.NewReader(bytes.Repeat([]byte("a"), 4096)))
+
+		assert.ErrorContains(t, err, "INSTREAM size limit exceeded")
+	})
+
+	t.Run("should fail when clamd is unreachable", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		address := listener.Addr().String()
+		listener.Close()
+
+		_, err = NewClamAVScanner(address, time.Second).Scan(ctx, strings.NewReader("conteúdo"))
+
+		assert.ErrorContains(t, err, "failed to connect to clamd")
+	})
+}
+
+// TestClamAVScanner_LocalClamd roda contra um clamd real quando CLAMAV_TEST_ADDRESS estiver definido
+func TestClamAVScanner_LocalClamd(t *testing.T) {
+	address := os.Getenv("CLAMAV_TEST_ADDRESS")
+	if address == "" {
+		t.Skip("CLAMAV_TEST_ADDRESS not set")
+	}
+	s := NewClamAVScanner(address, 30*time.Second)
+
+	result, err := s.Scan(context.Background(), strings.NewReader(eicar))
+	require.NoError(t, err)
+	assert.True(t, result.Infected)
+
+	result, err = s.Scan(context.Background(), strings.NewReader("%PDF-1.4\ncontrato\n"))
+	require.NoError(t, err)
+	assert.False(t, result.Infected)
+}
+
+func TestParseReply(t *testing.T) {
+	result, err := parseReply("stream: OK")
+	require.NoError(t, err)
+	assert.False(t, result.Infected)
+
+	result, err = parseReply("stream: Win.Test.EICAR_HDB-1 FOUND")
+	require.NoError(t, err)
+	assert.Equal(t, &Result{Infected: true, Threat: "Win.Test.EICAR_HDB-1"}, result)
+
+	_, err = parseReply("PONG")
+	assert.ErrorContains(t, err, "unexpected clamd reply")
+}
+
+func TestNewScannerFromConfig(t *testing.T) {
+	s, err := NewScannerFromConfig(config.EnvironmentVars{})
+	require.NoError(t, err)
+	assert.True(t, IsDisabled(s))
+
+	s, err = NewScannerFromConfig(config.EnvironmentVars{DOCUMENT_SCANNER: "ClamAV", CLAMAV_ADDRESS: "clamd:3310"})
+	require.NoError(t, err)
+	assert.Equal(t, BackendClamAV, s.Name())
+	assert.False(t, IsDisabled(s))
+
+	_, err = NewScannerFromConfig(config.EnvironmentVars{DOCUMENT_SCANNER: "virustotal"})
+	assert.ErrorContains(t, err, "unsupported document scanner")
+	// Sem scanner não há verificação configurada: quem o recebe deve recusar o conteúdo
+	assert.False(t, IsDisabled(nil))
+}
@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// LocalStorage grava os objetos como arquivos abaixo de um diretório raiz
+type LocalStorage struct {
+	root string
+}
+
+// NewLocalStorage cria o diretório raiz, se necessário, e retorna o storage
+func NewLocalStorage(root string) (*LocalStorage, error) {
+	if root == "" {
+		return nil, errors.New("local storage root path is required")
+	}
+
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create local storage root %s: %w", root, err)
+	}
+
+	return &LocalStorage{root: root}, nil
+}
+
+func (s *LocalStorage) path(key string) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+// Put grava em um arquivo temporário no mesmo diretório e renomeia ao final,
+// para que leitores nunca vejam um objeto parcial
+func (s *LocalStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", key, err)
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file for %s: %w", key, err)
+	}
+	defer os.Remove(tempFile.Name())
+
+	if _, err := io.Copy(tempFile, r); err != nil {
+		tempFile.Close()
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+
+	if err := tempFile.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", key, err)
+	}
+
+	if err := os.Rename(tempFile.Name(), target); err != nil {
+		return fmt.Errorf("failed to move %s into place: %w", key, err)
+	}
+
+	return nil
+}
+
+func (s *LocalStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	target, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(target)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", key, err)
+	}
+
+	return file, nil
+}
+
+func (s *LocalStorage) Exists(ctx context.Context, key string) (bool, error) {
+	target, err := s.path(key)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = os.Stat(target)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to stat %s: %w", key, err)
+	}
+
+	return true, nil
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+
+	return nil
+}
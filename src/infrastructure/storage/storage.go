@@ -0,0 +1,229 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"app/config"
+)
+
+const (
+	// BackendLocal grava os documentos em disco, abaixo de STORAGE_LOCAL_PATH
+	BackendLocal = "local"
+	// BackendS3 grava os documentos em um bucket compatível com S3 (AWS, MinIO)
+	BackendS3 = "s3"
+
+	// URIScheme prefixa o FilePath de documentos que estão no storage
+	URIScheme = "storage://"
+)
+
+// Kind identifica o tipo de conteúdo armazenado e compõe o prefixo da chave
+type Kind string
+
+const (
+	KindOriginal Kind = "originals"
+	KindSigned   Kind = "signed"
+)
+
+var (
+	// ErrNotFound é retornado quando a chave não existe no storage
+	ErrNotFound = errors.New("storage object not found")
+	// ErrInvalidKey é retornado para chaves vazias, absolutas ou com "..", que escapariam do prefixo
+	ErrInvalidKey = errors.New("invalid storage key")
+	// ErrTooLarge é retornado por StoreStream quando o conteúdo excede o tamanho máximo
+	ErrTooLarge = errors.New("content exceeds maximum size")
+)
+
+// Object descreve um conteúdo gravado por StoreStream
+type Object struct {
+	Key    string
+	SHA256 string
+	Size   int64
+}
+
+// Storage armazena o conteúdo dos documentos por chave
+type Storage interface {
+	// Put grava o conteúdo lido de r; size pode ser -1 quando desconhecido
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Open abre o conteúdo da chave para leitura; retorna ErrNotFound se não existir
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Exists(ctx context.Context, key string) (bool, error)
+	// Delete remove a chave; remover uma chave inexistente não é erro
+	Delete(ctx context.Context, key string) error
+}
+
+// NewStorageFromConfig cria o backend configurado em STORAGE_BACKEND
+func NewStorageFromConfig(envVars config.EnvironmentVars) (Storage, error) {
+	switch strings.ToLower(strings.TrimSpace(envVars.STORAGE_BACKEND)) {
+	case "", BackendLocal:
+		return NewLocalStorage(envVars.STORAGE_LOCAL_PATH)
+	case BackendS3:
+		return NewS3Storage(S3Config{
+			Endpoint:        envVars.STORAGE_S3_ENDPOINT,
+			Region:          envVars.STORAGE_S3_REGION,
+			Bucket:          envVars.STORAGE_S3_BUCKET,
+			AccessKeyID:     envVars.STORAGE_S3_ACCESS_KEY_ID,
+			SecretAccessKey: envVars.STORAGE_S3_SECRET_ACCESS_KEY,
+			UsePathStyle:    envVars.STORAGE_S3_USE_PATH_STYLE,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported storage backend: %s", envVars.STORAGE_BACKEND)
+	}
+}
+
+// ContentKey monta a chave endereçada por conteúdo a partir do SHA-256 em hexadecimal
+func ContentKey(kind Kind, sum string) string {
+	return path.Join(string(kind), "sha256", sum)
+}
+
+// StoreContent grava data sob a chave derivada do seu SHA-256 e retorna a chave e o hash
+// Conteúdo idêntico já armazenado não é regravado
+func StoreContent(ctx context.Context, s Storage, kind Kind, data []byte, contentType string) (string, string, error) {
+	hash := sha256.Sum256(data)
+	sum := hex.EncodeToString(hash[:])
+	key := ContentKey(kind, sum)
+
+	exists, err := s.Exists(ctx, key)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to check storage key %s: %w", key, err)
+	}
+	if exists {
+		return key, sum, nil
+	}
+
+	if err := s.Put(ctx, key, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+		return "", "", fmt.Errorf("failed to store content under %s: %w", key, err)
+	}
+	return key, sum, nil
+}
+
+// StoreStream grava o conteúdo de r sob a chave derivada do seu SHA-256, calculado durante a leitura
+// O conteúdo passa por um arquivo temporário, pois a chave só é conhecida ao final; maxSize <= 0 não limita
+func StoreStream(ctx context.Context, s Storage, kind Kind, r io.Reader, contentType string, maxSize int64) (*Object, error) {
+	tempFile, err := os.CreateTemp("", "docsigner_upload_*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tempFile.Name())
+	defer tempFile.Close()
+
+	if maxSize > 0 {
+		r = io.LimitReader(r, maxSize+1)
+	}
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tempFile, hash), r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content: %w", err)
+	}
+	if maxSize > 0 && size > maxSize {
+		return nil, fmt.Errorf("%w: limit is %d bytes", ErrTooLarge, maxSize)
+	}
+
+	sum := hex.EncodeToString(hash.Sum(nil))
+	object := &Object{
+		Key:    ContentKey(kind, sum),
+		SHA256: sum,
+		Size:   size,
+	}
+
+	exists, err := s.Exists(ctx, object.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check storage key %s: %w", object.Key, err)
+	}
+	if exists {
+		return object, nil
+	}
+
+	if _, err := tempFile.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to rewind temporary file: %w", err)
+	}
+	if err := s.Put(ctx, object.Key, tempFile, size, contentType); err != nil {
+		return nil, fmt.Errorf("failed to store content under %s: %w", object.Key, err)
+	}
+	return object, nil
+}
+
+// Hash calcula o SHA-256 e o tamanho do conteúdo de r sem armazená-lo; maxSize <= 0 não limita
+func Hash(r io.Reader, maxSize int64) (*Object, error) {
+	if maxSize > 0 {
+		r = io.LimitReader(r, maxSize+1)
+	}
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content: %w", err)
+	}
+	if maxSize > 0 && size > maxSize {
+		return nil, fmt.Errorf("%w: limit is %d bytes", ErrTooLarge, maxSize)
+	}
+
+	return &Object{SHA256: hex.EncodeToString(hash.Sum(nil)), Size: size}, nil
+}
+
+// SumFromKey extrai o SHA-256 de uma chave endereçada por conteúdo (<kind>/sha256/<hash>)
+func SumFromKey(key string) (string, bool) {
+	dir, sum := path.Split(key)
+	if !strings.HasSuffix(dir, "/sha256/") || len(sum) != sha256.Size*2 {
+		return "", false
+	}
+	if _, err := hex.DecodeString(sum); err != nil {
+		return "", false
+	}
+	return sum, true
+}
+
+// ReadAll lê todo o conteúdo de uma chave
+func ReadAll(ctx context.Context, s Storage, key string) ([]byte, error) {
+	reader, err := s.Open(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+// URI monta o FilePath que referencia uma chave do storage
+func URI(key string) string {
+	return URIScheme + key
+}
+
+// KeyFromURI extrai a chave de um FilePath no formato storage://<chave>
+func KeyFromURI(filePath string) (string, bool) {
+	if !strings.HasPrefix(filePath, URIScheme) {
+		return "", false
+	}
+	return strings.TrimPrefix(filePath, URIScheme), true
+}
+
+// ReadFile lê o conteúdo de um FilePath, resolvendo storage:// em s
+// e caminhos locais diretamente no disco
+func ReadFile(ctx context.Context, s Storage, filePath string) ([]byte, error) {
+	if key, ok := KeyFromURI(filePath); ok {
+		return ReadAll(ctx, s, key)
+	}
+	return os.ReadFile(filePath)
+}
+
+// cleanKey valida a chave e a normaliza para o formato a/b/c
+func cleanKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+		}
+	}
+	return key, nil
+}
@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"app/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorage(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	t.Run("should put, read and delete", func(t *testing.T) {
+		require.NoError(t, s.Put(ctx, "originals/sha256/abc", strings.NewReader("conteudo"), -1, "application/pdf"))
+
+		exists, err := s.Exists(ctx, "originals/sha256/abc")
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		data, err := ReadAll(ctx, s, "originals/sha256/abc")
+		require.NoError(t, err)
+		assert.Equal(t, "conteudo", string(data))
+
+		require.NoError(t, s.Delete(ctx, "originals/sha256/abc"))
+		require.NoError(t, s.Delete(ctx, "originals/sha256/abc"))
+
+		_, err = s.Open(ctx, "originals/sha256/abc")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("should reject keys escaping the root", func(t *testing.T) {
+		for _, key := range []string{"", "/etc/passwd", "../fora", "a/../../b", "a//b"} {
+			err := s.Put(ctx, key, strings.NewReader("x"), 1, "")
+			assert.ErrorIs(t, err, ErrInvalidKey, key)
+		}
+	})
+}
+
+func TestStoreContent(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	key, sum, err := StoreContent(ctx, s, KindOriginal, []byte("%PDF-1.4"), "application/pdf")
+	require.NoError(t, err)
+	assert.Equal(t, "originals/sha256/"+sum, key)
+	assert.Len(t, sum, 64)
+
+	t.Run("same content should map to the same key", func(t *testing.T) {
+		again, _, err := StoreContent(ctx, s, KindOriginal, []byte("%PDF-1.4"), "application/pdf")
+		require.NoError(t, err)
+		assert.Equal(t, key, again)
+	})
+
+	t.Run("signed copies should use their own prefix", func(t *testing.T) {
+		signed, signedSum, err := StoreContent(ctx, s, KindSigned, []byte("%PDF-1.4"), "application/pdf")
+		require.NoError(t, err)
+		assert.Equal(t, sum, signedSum)
+		assert.Equal(t, "signed/sha256/"+sum, signed)
+	})
+}
+
+func TestReadFile(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	key, _, err := StoreContent(ctx, s, KindOriginal, []byte("armazenado"), "application/pdf")
+	require.NoError(t, err)
+
+	data, err := ReadFile(ctx, s, URI(key))
+	require.NoError(t, err)
+	assert.Equal(t, "armazenado", string(data))
+
+	localPath := filepath.Join(t.TempDir(), "local.pdf")
+	require.NoError(t, os.WriteFile(localPath, []byte("local"), 0o600))
+	data, err = ReadFile(ctx, s, localPath)
+	require.NoError(t, err)
+	assert.Equal(t, "local", string(data))
+
+	_, err = ReadFile(ctx, s, URI("originals/sha256/inexistente"))
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestNewStorageFromConfig(t *testing.T) {
+	t.Run("should default to local", func(t *testing.T) {
+		s, err := NewStorageFromConfig(config.EnvironmentVars{STORAGE_LOCAL_PATH: t.TempDir()})
+		require.NoError(t, err)
+		assert.IsType(t, &LocalStorage{}, s)
+	})
+
+	t.Run("should require bucket for s3", func(t *testing.T) {
+		_, err := NewStorageFromConfig(config.EnvironmentVars{STORAGE_BACKEND: "s3", STORAGE_S3_ENDPOINT: "http://localhost:9000"})
+		assert.Error(t, err)
+	})
+
+	t.Run("should reject unknown backend", func(t *testing.T) {
+		_, err := NewStorageFromConfig(config.EnvironmentVars{STORAGE_BACKEND: "ftp"})
+		assert.Error(t, err)
+	})
+
+	t.Run("should build s3", func(t *testing.T) {
+		s, err := NewStorageFromConfig(config.EnvironmentVars{
+			STORAGE_BACKEND:              "s3",
+			STORAGE_S3_ENDPOINT:          "http://localhost:9000",
+			STORAGE_S3_BUCKET:            "documentos",
+			STORAGE_S3_ACCESS_KEY_ID:     "minio",
+			STORAGE_S3_SECRET_ACCESS_KEY: "minio123",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, defaultS3Region, s.(*S3Storage).cfg.Region)
+	})
+}
+
+func TestKeyFromURI(t *testing.T) {
+	key, ok := KeyFromURI("storage://originals/sha256/abc")
+	assert.True(t, ok)
+	assert.Equal(t, "originals/sha256/abc", key)
+
+	_, ok = KeyFromURI("/tmp/docsigner_base64_123")
+	assert.False(t, ok)
+
+	assert.Equal(t, "storage://signed/sha256/abc", URI(ContentKey(KindSigned, "abc")))
+}
+
+func TestStoreStream(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	t.Run("should hash while streaming", func(t *testing.T) {
+		content := strings.Repeat("%PDF-1.4 ", 1024)
+
+		object, err := StoreStream(ctx, s, KindOriginal, strings.NewReader(content), "application/pdf", 0)
+		require.NoError(t, err)
+
+		key, sum, err := StoreContent(ctx, s, KindOriginal, []byte(content), "application/pdf")
+		require.NoError(t, err)
+		assert.Equal(t, key, object.Key)
+		assert.Equal(t, sum, object.SHA256)
+		assert.Equal(t, int64(len(content)), object.Size)
+
+		data, err := ReadAll(ctx, s, object.Key)
+		require.NoError(t, err)
+		assert.Equal(t, content, string(data))
+	})
+
+	t.Run("should reject content above the limit", func(t *testing.T) {
+		_, err := StoreStream(ctx, s, KindOriginal, strings.NewReader("0123456789"), "", 9)
+		assert.ErrorIs(t, err, ErrTooLarge)
+
+		object, err := StoreStream(ctx, s, KindOriginal, strings.NewReader("0123456789"), "", 10)
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), object.Size)
+	})
+}
+
+func TestHash(t *testing.T) {
+	object, err := Hash(strings.NewReader("test"), 0)
+	require.NoError(t, err)
+	assert.Equal(t, "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", object.SHA256)
+	assert.Equal(t, int64(4), object.Size)
+	assert.Empty(t, object.Key)
+
+	_, err = Hash(strings.NewReader("conteudo grande"), 4)
+	assert.ErrorIs(t, err, ErrTooLarge)
+}
+
+func TestSumFromKey(t *testing.T) {
+	sum := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+
+	got, ok := SumFromKey(ContentKey(KindOriginal, sum))
+	assert.True(t, ok)
+	assert.Equal(t, sum, got)
+
+	_, ok = SumFromKey("originals/sha256/abc")
+	assert.False(t, ok)
+
+	_, ok = SumFromKey("originals/md5/" + sum)
+	assert.False(t, ok)
+}
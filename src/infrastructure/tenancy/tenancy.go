@@ -0,0 +1,134 @@
+package tenancy
+
+import (
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	settingKey = "tenancy:tenant_id"
+	fieldName  = "TenantID"
+)
+
+// Scope retorna uma conexão restrita ao tenant: consultas, updates e deletes de modelos com TenantID
+// ganham o filtro por tenant_id e os registros criados recebem o tenant
+// Conexões sem Scope (cron, consumers, jobs de retenção) continuam enxergando todos os tenants
+func Scope(db *gorm.DB, tenantID string) *gorm.DB {
+	return db.Set(settingKey, tenantID).Session(&gorm.Session{})
+}
+
+// TenantOf retorna o tenant da conexão e se ela está restrita
+func TenantOf(db *gorm.DB) (string, bool) {
+	value, ok := db.Get(settingKey)
+	if !ok {
+		return "", false
+	}
+	tenantID, ok := value.(string)
+	return tenantID, ok
+}
+
+// Register instala os callbacks que aplicam o escopo de Scope
+func Register(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Create().Before("gorm:create").Register("tenancy:create", assignTenant); err != nil {
+		return err
+	}
+	if err := callbacks.Query().Before("gorm:query").Register("tenancy:query", restrictToTenant); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("tenancy:update", restrictUpdate); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("tenancy:delete", restrictToTenant); err != nil {
+		return err
+	}
+	return callbacks.Row().Before("gorm:row").Register("tenancy:row", restrictToTenant)
+}
+
+func tenantField(db *gorm.DB) (*schema.Field, string, bool) {
+	tenantID, scoped := TenantOf(db)
+	if !scoped || db.Statement.Schema == nil {
+		return nil, "", false
+	}
+	field := db.Statement.Schema.LookUpField(fieldName)
+	if field == nil {
+		return nil, "", false
+	}
+	return field, tenantID, true
+}
+
+// tenantCondition trata NULL como o tenant padrão ("") para linhas criadas antes da coluna existir
+func tenantCondition(table string, field *schema.Field, tenantID string) clause.Expression {
+	column := clause.Column{Table: table, Name: field.DBName}
+	if tenantID == "" {
+		return clause.Or(clause.Eq{Column: column, Value: ""}, clause.Eq{Column: column, Value: nil})
+	}
+	return clause.Eq{Column: column, Value: tenantID}
+}
+
+func assignTenant(db *gorm.DB) {
+	field, tenantID, ok := tenantField(db)
+	if !ok || db.Error != nil {
+		return
+	}
+	setTenant(db, field, tenantID)
+
+	// Save sobre um id de outro tenant cai em INSERT ... ON CONFLICT DO UPDATE; o WHERE impede sobrescrever a linha
+	if c, exists := db.Statement.Clauses["ON CONFLICT"]; exists {
+		if onConflict, isOnConflict := c.Expression.(clause.OnConflict); isOnConflict && !onConflict.DoNothing {
+			onConflict.Where.Exprs = append(onConflict.Where.Exprs, tenantCondition(db.Statement.Table, field, tenantID))
+			c.Expression = onConflict
+			db.Statement.Clauses["ON CONFLICT"] = c
+		}
+	}
+}
+
+func restrictUpdate(db *gorm.DB) {
+	field, tenantID, ok := tenantField(db)
+	if !ok || db.Error != nil {
+		return
+	}
+	// Save grava todas as colunas; o tenant do registro não pode ser trocado pelo valor do struct
+	setTenant(db, field, tenantID)
+	addCondition(db.Statement, tenantCondition(clause.CurrentTable, field, tenantID))
+}
+
+func restrictToTenant(db *gorm.DB) {
+	field, tenantID, ok := tenantField(db)
+	if !ok || db.Error != nil {
+		return
+	}
+	addCondition(db.Statement, tenantCondition(clause.CurrentTable, field, tenantID))
+}
+
+// addCondition combina o filtro do tenant com as condições existentes entre parênteses,
+// para que um Or() da consulta não escape do escopo
+func addCondition(stmt *gorm.Statement, condition clause.Expression) {
+	c, exists := stmt.Clauses["WHERE"]
+	if where, isWhere := c.Expression.(clause.Where); exists && isWhere && len(where.Exprs) > 0 {
+		c.Expression = clause.Where{Exprs: []clause.Expression{condition, clause.And(where.Exprs...)}}
+		stmt.Clauses["WHERE"] = c
+		return
+	}
+	stmt.AddClause(clause.Where{Exprs: []clause.Expression{condition}})
+}
+
+func setTenant(db *gorm.DB, field *schema.Field, tenantID string) {
+	value := db.Statement.ReflectValue
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			element := reflect.Indirect(value.Index(i))
+			if element.Kind() == reflect.Struct && element.CanAddr() {
+				db.AddError(field.Set(db.Statement.Context, element, tenantID))
+			}
+		}
+	case reflect.Struct:
+		if value.CanAddr() {
+			db.AddError(field.Set(db.Statement.Context, value, tenantID))
+		}
+	}
+}
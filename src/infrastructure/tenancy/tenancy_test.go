@@ -0,0 +1,117 @@
+package tenancy
+
+import (
+	"testing"
+
+	"app/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/utils/tests"
+)
+
+func newDryRunDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	require.NoError(t, err)
+	require.NoError(t, Register(db))
+	return db
+}
+
+func TestScope_Query(t *testing.T) {
+	db := newDryRunDB(t)
+
+	t.Run("should filter models with a tenant", func(t *testing.T) {
+		stmt := Scope(db, "acme").Where("status = ?", "draft").Find(&[]entity.EntityEnvelope{}).Statement
+
+		assert.Equal(t, "SELECT * FROM `envelopes` WHERE `envelopes`.`tenant_id` = ? AND status = ?", stmt.SQL.String())
+		assert.Equal(t, []interface{}{"acme", "draft"}, stmt.Vars)
+	})
+
+	t.Run("should keep OR conditions inside the tenant", func(t *testing.T) {
+		stmt := Scope(db, "acme").Where("name LIKE ? or email LIKE ?", "%a%", "%a%").Find(&[]entity.EntityUser{}).Statement
+
+		assert.Equal(t, "SELECT * FROM `entity_users` WHERE `entity_users`.`tenant_id` = ? AND (name LIKE ? or email LIKE ?)", stmt.SQL.String())
+
+		stmt = Scope(db, "acme").Where("id = ?", 1).Or("id = ?", 2).Find(&[]entity.EntityDocument{}).Statement
+
+		assert.Equal(t, "SELECT * FROM `documents` WHERE `documents`.`tenant_id` = ? AND (id = ? OR id = ?)", stmt.SQL.String())
+	})
+
+	t.Run("should match legacy rows in the default tenant", func(t *testing.T) {
+		stmt := Scope(db, "").First(&entity.EntityEnvelope{}, 7).Statement
+
+		assert.Contains(t, stmt.SQL.String(), "WHERE (`envelopes`.`tenant_id` = ? OR `envelopes`.`tenant_id` IS NULL) AND `envelopes`.`id` = ?")
+	})
+
+	t.Run("should survive new sessions and contexts", func(t *testing.T) {
+		scoped := Scope(db, "acme")
+		scoped.Where("id = ?", 1).Find(&[]entity.EntitySignatory{})
+
+		stmt := scoped.WithContext(t.Context()).Find(&[]entity.EntityRequirement{}).Statement
+
+		assert.Equal(t, "SELECT * FROM `requirements` WHERE `requirements`.`tenant_id` = ?", stmt.SQL.String())
+	})
+
+	t.Run("should not filter unscoped connections or models without a tenant", func(t *testing.T) {
+		stmt := db.Find(&[]entity.EntityEnvelope{}).Statement
+		assert.Equal(t, "SELECT * FROM `envelopes`", stmt.SQL.String())
+
+		stmt = Scope(db, "acme").Find(&[]entity.EntityRetentionRun{}).Statement
+		assert.NotContains(t, stmt.SQL.String(), "tenant_id")
+	})
+
+	t.Run("should filter document templates", func(t *testing.T) {
+		stmt := Scope(db, "acme").First(&entity.EntityDocumentTemplate{}, 4).Statement
+
+		assert.Contains(t, stmt.SQL.String(), "WHERE `document_templates`.`tenant_id` = ? AND `document_templates`.`id` = ?")
+	})
+}
+
+func TestScope_Write(t *testing.T) {
+	db := newDryRunDB(t)
+
+	t.Run("should assign the tenant on create", func(t *testing.T) {
+		documents := []entity.EntityDocument{{Name: "a", TenantID: "other"}, {Name: "b"}}
+
+		require.NoError(t, Scope(db, "acme").Create(&documents).Error)
+
+		assert.Equal(t, "acme", documents[0].TenantID)
+		assert.Equal(t, "acme", documents[1].TenantID)
+	})
+
+	t.Run("should not overwrite rows of other tenants on upsert", func(t *testing.T) {
+		stmt := Scope(db, "acme").Clauses(clause.OnConflict{UpdateAll: true}).Create(&entity.EntityDocument{ID: 9, Name: "a"}).Statement
+
+		assert.Contains(t, stmt.SQL.String(), "ON CONFLICT")
+		assert.Contains(t, stmt.SQL.String(), "WHERE `documents`.`tenant_id` = ?")
+	})
+
+	t.Run("should restrict updates and keep the tenant on save", func(t *testing.T) {
+		envelope := &entity.EntityEnvelope{ID: 3, Name: "Contrato", TenantID: "other"}
+
+		stmt := Scope(db, "acme").Save(envelope).Statement
+
+		assert.Contains(t, stmt.SQL.String(), "`tenant_id`=?,")
+		assert.Contains(t, stmt.SQL.String(), "WHERE `envelopes`.`tenant_id` = ? AND `id` = ?")
+		assert.Equal(t, "acme", envelope.TenantID)
+	})
+
+	t.Run("should restrict deletes", func(t *testing.T) {
+		stmt := Scope(db, "acme").Delete(&entity.EntityRequirement{ID: 5}).Statement
+
+		assert.Equal(t, "DELETE FROM `requirements` WHERE `requirements`.`tenant_id` = ? AND `requirements`.`id` = ?", stmt.SQL.String())
+	})
+}
+
+func TestTenantOf(t *testing.T) {
+	db := newDryRunDB(t)
+
+	_, scoped := TenantOf(db)
+	assert.False(t, scoped)
+
+	tenantID, scoped := TenantOf(Scope(db, "acme"))
+	assert.True(t, scoped)
+	assert.Equal(t, "acme", tenantID)
+}
@@ -1,7 +1,6 @@
 package vertc_assinaturas
 
 import (
-	"bytes"
 	"context"
 	"crypto/rand"
 	"encoding/base64"
@@ -13,6 +12,8 @@ import (
 	"strings"
 	"time"
 
+	"app/pkg/pdf"
+
 	"github.com/sirupsen/logrus"
 )
 
@@ -455,69 +456,7 @@ func generateAutomaticSignatureTermPDF(signerEmail, signerName, envelopeID strin
 		"Este termo integra o registro de evidencias da plataforma.",
 	)
 
-	return buildSimplePDF(lines)
-}
-
-func buildSimplePDF(lines []string) ([]byte, error) {
-	streamContent := buildPDFTextStream(lines)
-
-	obj1 := "<< /Type /Catalog /Pages 2 0 R >>"
-	obj2 := "<< /Type /Pages /Kids [3 0 R] /Count 1 >>"
-	obj3 := "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] /Resources << /Font << /F1 5 0 R >> >> /Contents 4 0 R >>"
-	obj4 := fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len([]byte(streamContent)), streamContent)
-	obj5 := "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>"
-
-	objects := []string{obj1, obj2, obj3, obj4, obj5}
-
-	var buf bytes.Buffer
-	buf.WriteString("%PDF-1.4\n")
-
-	offsets := make([]int, len(objects)+1)
-
-	for i, objectContent := range objects {
-		objectID := i + 1
-		offsets[objectID] = buf.Len()
-		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", objectID, objectContent)
-	}
-
-	xrefOffset := buf.Len()
-	fmt.Fprintf(&buf, "xref\n0 %d\n", len(objects)+1)
-	buf.WriteString("0000000000 65535 f \n")
-
-	for i := 1; i <= len(objects); i++ {
-		fmt.Fprintf(&buf, "%010d 00000 n \n", offsets[i])
-	}
-
-	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF", len(objects)+1, xrefOffset)
-
-	return buf.Bytes(), nil
-}
-
-func buildPDFTextStream(lines []string) string {
-	var streamBuilder strings.Builder
-	streamBuilder.WriteString("BT\n")
-	streamBuilder.WriteString("/F1 12 Tf\n")
-	streamBuilder.WriteString("50 790 Td\n")
-	streamBuilder.WriteString("16 TL\n")
-
-	for index, line := range lines {
-		if index > 0 {
-			streamBuilder.WriteString("T*\n")
-		}
-		streamBuilder.WriteString("(")
-		streamBuilder.WriteString(escapePDFText(line))
-		streamBuilder.WriteString(") Tj\n")
-	}
-
-	streamBuilder.WriteString("ET\n")
-	return streamBuilder.String()
-}
-
-func escapePDFText(value string) string {
-	escaped := strings.ReplaceAll(value, "\\", "\\\\")
-	escaped = strings.ReplaceAll(escaped, "(", "\\(")
-	escaped = strings.ReplaceAll(escaped, ")", "\\)")
-	return escaped
+	return pdf.RenderLines(lines)
 }
 
 func parseAutomaticSignaturePermissions(body []byte) ([]automaticSignaturePermissionResponse, error) {
@@ -5,12 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"os"
 	"strings"
 	"time"
 
 	"app/entity"
 	"app/infrastructure/provider"
+	"app/infrastructure/storage"
+	"app/pkg/fetcher"
 	"app/pkg/utils"
 
 	"github.com/sirupsen/logrus"
@@ -52,15 +53,20 @@ type DirectFlowExecutionResult struct {
 
 // DirectFlowService executa o fluxo "create envelope -> upload docs -> create signers -> send".
 type DirectFlowService struct {
-	client *VertcAssinaturasClient
-	logger *logrus.Logger
+	client  *VertcAssinaturasClient
+	storage storage.Storage
+	fetcher *fetcher.Fetcher
+	logger  *logrus.Logger
 }
 
 // NewDirectFlowService cria uma nova instância do serviço de fluxo direto.
-func NewDirectFlowService(client *VertcAssinaturasClient, logger *logrus.Logger) *DirectFlowService {
+// Os documentos são lidos de documentStorage ou baixados por documentFetcher.
+func NewDirectFlowService(client *VertcAssinaturasClient, documentStorage storage.Storage, documentFetcher *fetcher.Fetcher, logger *logrus.Logger) *DirectFlowService {
 	return &DirectFlowService{
-		client: client,
-		logger: logger,
+		client:  client,
+		storage: documentStorage,
+		fetcher: documentFetcher,
+		logger:  logger,
 	}
 }
 
@@ -144,7 +150,7 @@ func (s *DirectFlowService) createEnvelope(ctx context.Context, envelope *entity
 }
 
 func (s *DirectFlowService) uploadDocument(ctx context.Context, envelopeID string, document *entity.EntityDocument) error {
-	fileContent, err := s.readDocumentContent(document)
+	fileContent, err := s.readDocumentContent(ctx, document)
 	if err != nil {
 		return fmt.Errorf("failed to read document '%s' for direct flow upload: %w", document.Name, err)
 	}
@@ -217,9 +223,14 @@ func (s *DirectFlowService) sendEnvelope(ctx context.Context, envelopeID string,
 	return nil
 }
 
-func (s *DirectFlowService) readDocumentContent(document *entity.EntityDocument) ([]byte, error) {
+func (s *DirectFlowService) readDocumentContent(ctx context.Context, document *entity.EntityDocument) ([]byte, error) {
+	// Original já gravado no storage: evita baixar a URL novamente
+	if document.StorageKey != "" {
+		return storage.ReadAll(ctx, s.storage, document.StorageKey)
+	}
+
 	if strings.HasPrefix(document.FilePath, "http://") || strings.HasPrefix(document.FilePath, "https://") {
-		fileInfo, err := utils.DownloadFileFromURL(document.FilePath)
+		fileInfo, err := utils.DownloadFileFromURL(s.fetcher, document.FilePath)
 		if err != nil {
 			return nil, err
 		}
@@ -233,7 +244,7 @@ func (s *DirectFlowService) readDocumentContent(document *entity.EntityDocument)
 		return fileInfo.DecodedData, nil
 	}
 
-	fileContent, err := os.ReadFile(document.FilePath)
+	fileContent, err := storage.ReadFile(ctx, s.storage, document.FilePath)
 	if err != nil {
 		return nil, err
 	}
@@ -74,7 +74,7 @@ func TestDirectFlowService_CreateEnvelopeWithDocumentsAndSigners(t *testing.T) {
 			logger:     logrus.New(),
 		}
 
-		service := NewDirectFlowService(client, logrus.New())
+		service := NewDirectFlowService(client, nil, nil, logrus.New())
 		deadline := time.Now().Add(24 * time.Hour)
 
 		result, err := service.CreateEnvelopeWithDocumentsAndSigners(context.Background(), QuickSendData{
@@ -135,7 +135,7 @@ func TestDirectFlowService_CreateEnvelopeWithDocumentsAndSigners(t *testing.T) {
 			logger:     logrus.New(),
 		}
 
-		service := NewDirectFlowService(client, logrus.New())
+		service := NewDirectFlowService(client, nil, nil, logrus.New())
 
 		_, err := service.createSigners(context.Background(), "env-123", []provider.SignerData{
 			{
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"app/config"
+	"app/infrastructure/provider"
 
 	"github.com/sirupsen/logrus"
 )
@@ -30,6 +31,24 @@ func (e *VertcAssinaturasError) Error() string {
 	return fmt.Sprintf("vertc-assinaturas %s error: %s", e.Type, e.Message)
 }
 
+// Temporary indica se o erro representa indisponibilidade temporária da API
+func (e *VertcAssinaturasError) Temporary() bool {
+	switch e.Type {
+	case ErrorTypeNetwork, ErrorTypeTimeout, ErrorTypeServer:
+		return true
+	}
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+// NotProcessed indica se a API com certeza não processou a requisição: a conexão nem foi estabelecida
+// ou a API a recusou com 503/429. Timeouts e demais 5xx têm resultado desconhecido
+func (e *VertcAssinaturasError) NotProcessed() bool {
+	if e.StatusCode == http.StatusServiceUnavailable || e.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return e.StatusCode == 0 && e.Original != nil && provider.IsConnectionError(e.Original)
+}
+
 // Error types constants
 const (
 	ErrorTypeNetwork        = "network"
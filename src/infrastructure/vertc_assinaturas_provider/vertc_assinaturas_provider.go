@@ -5,13 +5,36 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"app/config"
 	"app/entity"
 	"app/infrastructure/provider"
+	"app/infrastructure/storage"
 	"app/infrastructure/vertc_assinaturas"
+	"app/pkg/fetcher"
+	"app/pkg/utils"
 
 	"github.com/sirupsen/logrus"
 )
 
+// Capabilities descreve as funcionalidades suportadas pelo provider vert-sign
+// Requirements são configurados via requiredMethods no quick-send, portanto apenas "sign" é aceito
+var Capabilities = provider.Capabilities{
+	Name:                      "vert-sign",
+	AuthMethods:               []string{"email", "auto_signature"},
+	Actions:                   []string{"sign"},
+	SupportsNotify:            false,
+	SupportsCancel:            false,
+	SupportsSequentialSigning: false,
+	SupportsDocumentReplace:   false,
+	NotifiesSignersOnCreate:   true,
+	MaxFileSize:               int64(utils.MaxFileSize),
+	MimeTypes:                 []string{"application/pdf", "image/jpeg", "image/jpg", "image/png", "image/gif"},
+}
+
+func init() {
+	provider.Register("vert-sign", NewVertcAssinaturasProviderFromConfig, Capabilities)
+}
+
 // VertcAssinaturasProvider implementa a interface EnvelopeProvider para o provider vertc-assinaturas
 type VertcAssinaturasProvider struct {
 	quickSendService  *vertc_assinaturas.QuickSendService
@@ -32,6 +55,28 @@ func NewVertcAssinaturasProvider(
 	}
 }
 
+// NewVertcAssinaturasProviderFromConfig cria o provider a partir das variáveis de ambiente
+// Credenciais informadas (ex.: por tenant) substituem login, senha e base URL globais
+func NewVertcAssinaturasProviderFromConfig(envVars config.EnvironmentVars, credentials *provider.Credentials, documentStorage storage.Storage, documentFetcher *fetcher.Fetcher, logger *logrus.Logger) (provider.EnvelopeProvider, error) {
+	if credentials != nil {
+		if credentials.Login != "" {
+			envVars.VERTC_ASSINATURAS_EMAIL = credentials.Login
+		}
+		if credentials.Password != "" {
+			envVars.VERTC_ASSINATURAS_PASSWORD = credentials.Password
+		}
+		if credentials.BaseURL != "" {
+			envVars.VERTC_ASSINATURAS_BASE_URL = credentials.BaseURL
+		}
+	}
+
+	vertcClient := vertc_assinaturas.NewVertcAssinaturasClient(envVars, logger)
+	quickSendService := vertc_assinaturas.NewQuickSendService(vertcClient, logger)
+	directFlowService := vertc_assinaturas.NewDirectFlowService(vertcClient, documentStorage, documentFetcher, logger)
+
+	return NewVertcAssinaturasProvider(quickSendService, directFlowService, logger), nil
+}
+
 // CreateEnvelope cria um envelope no provider vertc-assinaturas.
 // O provider decide entre quick-send e fluxo direto com base nos signatários recebidos.
 // Os documentos e signatários devem estar no contexto via QuickSendData.
@@ -95,6 +140,11 @@ func (p *VertcAssinaturasProvider) CreateDocument(ctx context.Context, envelopeK
 	return "", fmt.Errorf("CreateDocument is not supported for vertc-assinaturas provider. Use quick-send to create envelope with documents")
 }
 
+// DeleteDocument não é suportado: o quick-send cria o envelope já ativo
+func (p *VertcAssinaturasProvider) DeleteDocument(ctx context.Context, envelopeKey string, documentKey string) error {
+	return fmt.Errorf("DeleteDocument is not supported for vertc-assinaturas provider")
+}
+
 // CreateSigner não é necessário para vertc-assinaturas (quick-send já cria)
 func (p *VertcAssinaturasProvider) CreateSigner(ctx context.Context, envelopeKey string, signerData provider.SignerData) (string, error) {
 	return "", fmt.Errorf("CreateSigner is not supported for vertc-assinaturas provider. Use quick-send to create envelope with signers")
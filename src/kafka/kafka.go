@@ -1,6 +1,7 @@
 package kafka
 
 import (
+	"app/infrastructure/jwtkeys"
 	"app/infrastructure/postgres"
 	"app/infrastructure/repository"
 	kafka_handlers "app/kafka/handlers"
@@ -9,12 +10,12 @@ import (
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
 )
 
-func StartKafka() {
+func StartKafka(tokenKeys *jwtkeys.KeySet) {
 
 	db := postgres.Connect()
 
 	repositoryUser := repository.NewUserPostgres(db)
-	usecaseUser := usecase_user.NewService(repositoryUser)
+	usecaseUser := usecase_user.NewService(repositoryUser, tokenKeys)
 
 	var topicParams []KafkaReadTopicsParams
 
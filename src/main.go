@@ -4,11 +4,17 @@ import (
 	"app/api"
 	"app/config"
 	"app/cron"
+	"app/entity"
+	"app/infrastructure/dependencies"
+	"app/infrastructure/fake_provider"
+	"app/infrastructure/fieldcrypt"
 	"app/infrastructure/postgres"
 	"app/infrastructure/repository"
 	"app/kafka"
+	custom_logger "app/pkg/logger"
 	usecase_user "app/usecase/user"
 	"log"
+	"os"
 
 	_ "time/tzdata" // Required for tzdata to work
 )
@@ -17,22 +23,50 @@ func main() {
 
 	config.ReadEnvironmentVars()
 
-	cron.StartCronJobs()
+	// Redação de dados pessoais em todos os logs, inclusive os do pacote log
+	redactor, err := custom_logger.NewRedactorFromConfig(config.EnvironmentVariables)
+	if err != nil {
+		log.Fatalf("Failed to configure log redaction: %v", err)
+	}
+	log.SetOutput(redactor.Writer(os.Stderr))
+
+	// O provider fake só é registrado quando habilitado, para não ser listado nem escolhido pelo roteamento "auto"
+	if config.EnvironmentVariables.FAKE_PROVIDER_ENABLED {
+		fake_provider.Register()
+	}
+
+	// Cifragem dos dados pessoais dos signatários; precisa estar ativa antes de qualquer acesso ao banco
+	piiCipher, err := fieldcrypt.NewCipherFromConfig(config.EnvironmentVariables)
+	if err != nil {
+		log.Fatalf("Failed to configure PII encryption: %v", err)
+	}
+	if piiCipher != nil {
+		entity.SetPIICipher(piiCipher)
+	}
 
 	conn := postgres.Connect()
 	postgres.Migrations()
 
+	// Infraestrutura compartilhada pela API, pelo cron e pelo kafka; configuração inválida interrompe a inicialização
+	deps, err := dependencies.NewDependenciesFromConfig(config.EnvironmentVariables, conn, redactor)
+	if err != nil {
+		log.Fatalf("Failed to configure dependencies: %v", err)
+	}
+
+	cron.StartCronJobs(deps)
+
 	usecase := usecase_user.NewService(
 		repository.NewUserPostgres(conn),
+		deps.TokenKeys,
 	)
 
-	err := usecase.CreateAdminUser()
+	err = usecase.CreateAdminUser()
 	if err != nil {
 		log.Println("---------->     Error creating admin user     <----------")
 		log.Println(err)
 	}
 
-	go kafka.StartKafka()
+	go kafka.StartKafka(deps.TokenKeys)
 
-	api.StartWebServer()
+	api.StartWebServer(conn, deps)
 }
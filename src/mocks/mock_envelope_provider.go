@@ -67,6 +67,20 @@ func (mr *MockEnvelopeProviderMockRecorder) CreateDocument(ctx, envelopeKey, doc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDocument", reflect.TypeOf((*MockEnvelopeProvider)(nil).CreateDocument), ctx, envelopeKey, document, internalEnvelopeID)
 }
 
+// DeleteDocument mocks base method.
+func (m *MockEnvelopeProvider) DeleteDocument(ctx context.Context, envelopeKey, documentKey string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDocument", ctx, envelopeKey, documentKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDocument indicates an expected call of DeleteDocument.
+func (mr *MockEnvelopeProviderMockRecorder) DeleteDocument(ctx, envelopeKey, documentKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDocument", reflect.TypeOf((*MockEnvelopeProvider)(nil).DeleteDocument), ctx, envelopeKey, documentKey)
+}
+
 // CreateSigner mocks base method.
 func (m *MockEnvelopeProvider) CreateSigner(ctx context.Context, envelopeKey string, signerData provider.SignerData) (string, error) {
 	m.ctrl.T.Helper()
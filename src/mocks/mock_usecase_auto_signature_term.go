@@ -109,6 +109,21 @@ func (mr *MockIUsecaseAutoSignatureTermMockRecorder) GetAutoSignatureTermByClick
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAutoSignatureTermByClicksignKey", reflect.TypeOf((*MockIUsecaseAutoSignatureTerm)(nil).GetAutoSignatureTermByClicksignKey), arg0)
 }
 
+// GetAutoSignatureTermsBySignerDocumentation mocks base method.
+func (m *MockIUsecaseAutoSignatureTerm) GetAutoSignatureTermsBySignerDocumentation(arg0 string) ([]entity.EntityAutoSignatureTerm, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAutoSignatureTermsBySignerDocumentation", arg0)
+	ret0, _ := ret[0].([]entity.EntityAutoSignatureTerm)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAutoSignatureTermsBySignerDocumentation indicates an expected call of GetAutoSignatureTermsBySignerDocumentation.
+func (mr *MockIUsecaseAutoSignatureTermMockRecorder) GetAutoSignatureTermsBySignerDocumentation(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAutoSignatureTermsBySignerDocumentation", reflect.TypeOf((*MockIUsecaseAutoSignatureTerm)(nil).GetAutoSignatureTermsBySignerDocumentation), arg0)
+}
+
 // UpdateAutoSignatureTerm mocks base method.
 func (m *MockIUsecaseAutoSignatureTerm) UpdateAutoSignatureTerm(arg0 *entity.EntityAutoSignatureTerm) error {
 	m.ctrl.T.Helper()
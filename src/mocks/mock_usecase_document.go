@@ -62,6 +62,21 @@ func (mr *MockIUsecaseDocumentMockRecorder) Delete(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIUsecaseDocument)(nil).Delete), arg0)
 }
 
+// FindByContentHash mocks base method.
+func (m *MockIUsecaseDocument) FindByContentHash(arg0 string) ([]entity.EntityDocument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByContentHash", arg0)
+	ret0, _ := ret[0].([]entity.EntityDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByContentHash indicates an expected call of FindByContentHash.
+func (mr *MockIUsecaseDocumentMockRecorder) FindByContentHash(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByContentHash", reflect.TypeOf((*MockIUsecaseDocument)(nil).FindByContentHash), arg0)
+}
+
 // GetDocument mocks base method.
 func (m *MockIUsecaseDocument) GetDocument(arg0 int) (*entity.EntityDocument, error) {
 	m.ctrl.T.Helper()
@@ -107,6 +122,21 @@ func (mr *MockIUsecaseDocumentMockRecorder) GetDocuments(arg0 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDocuments", reflect.TypeOf((*MockIUsecaseDocument)(nil).GetDocuments), arg0)
 }
 
+// GetVersions mocks base method.
+func (m *MockIUsecaseDocument) GetVersions(arg0 int) ([]entity.EntityDocumentVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVersions", arg0)
+	ret0, _ := ret[0].([]entity.EntityDocumentVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVersions indicates an expected call of GetVersions.
+func (mr *MockIUsecaseDocumentMockRecorder) GetVersions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersions", reflect.TypeOf((*MockIUsecaseDocument)(nil).GetVersions), arg0)
+}
+
 // PrepareForSigning mocks base method.
 func (m *MockIUsecaseDocument) PrepareForSigning(arg0 int) (*entity.EntityDocument, error) {
 	m.ctrl.T.Helper()
@@ -122,6 +152,21 @@ func (mr *MockIUsecaseDocumentMockRecorder) PrepareForSigning(arg0 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrepareForSigning", reflect.TypeOf((*MockIUsecaseDocument)(nil).PrepareForSigning), arg0)
 }
 
+// ReplaceContent mocks base method.
+func (m *MockIUsecaseDocument) ReplaceContent(arg0, arg1 *entity.EntityDocument) (*entity.EntityDocumentVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceContent", arg0, arg1)
+	ret0, _ := ret[0].(*entity.EntityDocumentVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplaceContent indicates an expected call of ReplaceContent.
+func (mr *MockIUsecaseDocumentMockRecorder) ReplaceContent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceContent", reflect.TypeOf((*MockIUsecaseDocument)(nil).ReplaceContent), arg0, arg1)
+}
+
 // Update mocks base method.
 func (m *MockIUsecaseDocument) Update(arg0 *entity.EntityDocument) error {
 	m.ctrl.T.Helper()
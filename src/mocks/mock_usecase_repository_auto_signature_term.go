@@ -107,6 +107,21 @@ func (mr *MockIRepositoryAutoSignatureTermMockRecorder) GetByID(arg0 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockIRepositoryAutoSignatureTerm)(nil).GetByID), arg0)
 }
 
+// GetBySignerDocumentation mocks base method.
+func (m *MockIRepositoryAutoSignatureTerm) GetBySignerDocumentation(arg0 string) ([]entity.EntityAutoSignatureTerm, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBySignerDocumentation", arg0)
+	ret0, _ := ret[0].([]entity.EntityAutoSignatureTerm)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBySignerDocumentation indicates an expected call of GetBySignerDocumentation.
+func (mr *MockIRepositoryAutoSignatureTermMockRecorder) GetBySignerDocumentation(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySignerDocumentation", reflect.TypeOf((*MockIRepositoryAutoSignatureTerm)(nil).GetBySignerDocumentation), arg0)
+}
+
 // Update mocks base method.
 func (m *MockIRepositoryAutoSignatureTerm) Update(arg0 *entity.EntityAutoSignatureTerm) error {
 	m.ctrl.T.Helper()
@@ -77,6 +77,21 @@ func (mr *MockIRepositoryDocumentMockRecorder) GetByClicksignKey(arg0 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByClicksignKey", reflect.TypeOf((*MockIRepositoryDocument)(nil).GetByClicksignKey), arg0)
 }
 
+// GetByContentHash mocks base method.
+func (m *MockIRepositoryDocument) GetByContentHash(arg0 string) ([]entity.EntityDocument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByContentHash", arg0)
+	ret0, _ := ret[0].([]entity.EntityDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByContentHash indicates an expected call of GetByContentHash.
+func (mr *MockIRepositoryDocumentMockRecorder) GetByContentHash(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByContentHash", reflect.TypeOf((*MockIRepositoryDocument)(nil).GetByContentHash), arg0)
+}
+
 // GetByID mocks base method.
 func (m *MockIRepositoryDocument) GetByID(arg0 int) (*entity.EntityDocument, error) {
 	m.ctrl.T.Helper()
@@ -107,6 +122,35 @@ func (mr *MockIRepositoryDocumentMockRecorder) GetDocuments(arg0 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDocuments", reflect.TypeOf((*MockIRepositoryDocument)(nil).GetDocuments), arg0)
 }
 
+// GetVersions mocks base method.
+func (m *MockIRepositoryDocument) GetVersions(arg0 int) ([]entity.EntityDocumentVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVersions", arg0)
+	ret0, _ := ret[0].([]entity.EntityDocumentVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVersions indicates an expected call of GetVersions.
+func (mr *MockIRepositoryDocumentMockRecorder) GetVersions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersions", reflect.TypeOf((*MockIRepositoryDocument)(nil).GetVersions), arg0)
+}
+
+// ReplaceContent mocks base method.
+func (m *MockIRepositoryDocument) ReplaceContent(arg0 *entity.EntityDocument, arg1 *entity.EntityDocumentVersion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceContent", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceContent indicates an expected call of ReplaceContent.
+func (mr *MockIRepositoryDocumentMockRecorder) ReplaceContent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceContent", reflect.TypeOf((*MockIRepositoryDocument)(nil).ReplaceContent), arg0, arg1)
+}
+
 // Update mocks base method.
 func (m *MockIRepositoryDocument) Update(arg0 *entity.EntityDocument) error {
 	m.ctrl.T.Helper()
@@ -62,6 +62,21 @@ func (mr *MockIRepositoryUserMockRecorder) DeleteUser(arg0 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockIRepositoryUser)(nil).DeleteUser), arg0)
 }
 
+// GetByExternalID mocks base method.
+func (m *MockIRepositoryUser) GetByExternalID(arg0 string) (*entity.EntityUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByExternalID", arg0)
+	ret0, _ := ret[0].(*entity.EntityUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByExternalID indicates an expected call of GetByExternalID.
+func (mr *MockIRepositoryUserMockRecorder) GetByExternalID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByExternalID", reflect.TypeOf((*MockIRepositoryUser)(nil).GetByExternalID), arg0)
+}
+
 // GetByID mocks base method.
 func (m *MockIRepositoryUser) GetByID(arg0 int) (*entity.EntityUser, error) {
 	m.ctrl.T.Helper()
@@ -137,6 +152,34 @@ func (mr *MockIRepositoryUserMockRecorder) GetUsersFromIDs(arg0 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersFromIDs", reflect.TypeOf((*MockIRepositoryUser)(nil).GetUsersFromIDs), arg0)
 }
 
+// SetActive mocks base method.
+func (m *MockIRepositoryUser) SetActive(arg0 int, arg1 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetActive", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetActive indicates an expected call of SetActive.
+func (mr *MockIRepositoryUserMockRecorder) SetActive(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetActive", reflect.TypeOf((*MockIRepositoryUser)(nil).SetActive), arg0, arg1)
+}
+
+// SetPassword mocks base method.
+func (m *MockIRepositoryUser) SetPassword(arg0 int, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPassword", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPassword indicates an expected call of SetPassword.
+func (mr *MockIRepositoryUserMockRecorder) SetPassword(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPassword", reflect.TypeOf((*MockIRepositoryUser)(nil).SetPassword), arg0, arg1)
+}
+
 // UpdateUser mocks base method.
 func (m *MockIRepositoryUser) UpdateUser(arg0 *entity.EntityUser) error {
 	m.ctrl.T.Helper()
@@ -6,6 +6,7 @@ package mocks
 
 import (
 	entity "app/entity"
+	usecase_user "app/usecase/user"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -62,6 +63,21 @@ func (mr *MockIUsecaseUserMockRecorder) Delete(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIUsecaseUser)(nil).Delete), arg0)
 }
 
+// DeleteExpiredTokens mocks base method.
+func (m *MockIUsecaseUser) DeleteExpiredTokens() (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredTokens")
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredTokens indicates an expected call of DeleteExpiredTokens.
+func (mr *MockIUsecaseUserMockRecorder) DeleteExpiredTokens() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredTokens", reflect.TypeOf((*MockIUsecaseUser)(nil).DeleteExpiredTokens))
+}
+
 // GetUser mocks base method.
 func (m *MockIUsecaseUser) GetUser(arg0 int) (*entity.EntityUser, error) {
 	m.ctrl.T.Helper()
@@ -122,6 +138,21 @@ func (mr *MockIUsecaseUserMockRecorder) GetUsersFromIDs(arg0 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersFromIDs", reflect.TypeOf((*MockIUsecaseUser)(nil).GetUsersFromIDs), arg0)
 }
 
+// IssueTokens mocks base method.
+func (m *MockIUsecaseUser) IssueTokens(arg0 *entity.EntityUser) (*usecase_user.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IssueTokens", arg0)
+	ret0, _ := ret[0].(*usecase_user.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssueTokens indicates an expected call of IssueTokens.
+func (mr *MockIUsecaseUserMockRecorder) IssueTokens(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueTokens", reflect.TypeOf((*MockIUsecaseUser)(nil).IssueTokens), arg0)
+}
+
 // LoginUser mocks base method.
 func (m *MockIUsecaseUser) LoginUser(arg0, arg1 string) (*entity.EntityUser, error) {
 	m.ctrl.T.Helper()
@@ -137,6 +168,35 @@ func (mr *MockIUsecaseUserMockRecorder) LoginUser(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginUser", reflect.TypeOf((*MockIUsecaseUser)(nil).LoginUser), arg0, arg1)
 }
 
+// Logout mocks base method.
+func (m *MockIUsecaseUser) Logout(arg0, arg1 string, arg2 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockIUsecaseUserMockRecorder) Logout(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockIUsecaseUser)(nil).Logout), arg0, arg1, arg2)
+}
+
+// RefreshTokens mocks base method.
+func (m *MockIUsecaseUser) RefreshTokens(arg0 string) (*usecase_user.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshTokens", arg0)
+	ret0, _ := ret[0].(*usecase_user.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshTokens indicates an expected call of RefreshTokens.
+func (mr *MockIUsecaseUserMockRecorder) RefreshTokens(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshTokens", reflect.TypeOf((*MockIUsecaseUser)(nil).RefreshTokens), arg0)
+}
+
 // Update mocks base method.
 func (m *MockIUsecaseUser) Update(arg0 *entity.EntityUser) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,43 @@
+package fetcher
+
+import "fmt"
+
+// Category classifica o motivo da falha ao buscar um documento remoto
+type Category string
+
+const (
+	CategoryInvalidURL       Category = "invalid_url"
+	CategorySchemeNotAllowed Category = "scheme_not_allowed"
+	CategoryHostNotAllowed   Category = "host_not_allowed"
+	CategoryBlockedAddress   Category = "blocked_address"
+	CategoryDNSFailure       Category = "dns_failure"
+	CategoryTooManyRedirects Category = "too_many_redirects"
+	CategoryTooLarge         Category = "too_large"
+	CategoryContentType      Category = "unsupported_content_type"
+	CategoryHTTPStatus       Category = "http_status"
+	CategoryNetwork          Category = "network_error"
+)
+
+// Error descreve uma falha do fetcher com a categoria e a URL envolvida
+// Em redirects, URL é o destino que foi recusado
+type Error struct {
+	Category Category
+	URL      string
+	Message  string
+	Err      error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Category, e.Message, e.Err)
+	}
+	return fmt.Sprintf("%s: %s", e.Category, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func newError(category Category, rawURL, message string, err error) *Error {
+	return &Error{Category: category, URL: rawURL, Message: message, Err: err}
+}
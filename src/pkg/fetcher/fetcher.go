@@ -0,0 +1,416 @@
+package fetcher
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"app/config"
+)
+
+const (
+	defaultTimeout      = 30 * time.Second
+	defaultMaxRedirects = 5
+	defaultMaxSize      = 50 * 1024 * 1024
+	sniffLength         = 512
+)
+
+// Resolver resolve nomes para endereços IP; net.DefaultResolver satisfaz a interface
+type Resolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+}
+
+// Config define as regras aplicadas a toda URL buscada, inclusive destinos de redirect
+type Config struct {
+	// AllowedSchemes aceitos; vazio equivale a http e https
+	AllowedSchemes []string
+	// AllowedHosts restringe os hosts aceitos; "*.exemplo.com" aceita subdomínios. Vazio aceita qualquer host público
+	AllowedHosts []string
+	// AllowedNetworks são exceções ao bloqueio de endereços privados, loopback e link-local
+	AllowedNetworks []netip.Prefix
+	// AllowedMimeTypes restringe o tipo detectado pelo conteúdo; vazio aceita qualquer tipo
+	AllowedMimeTypes []string
+	MaxSize          int64
+	MaxRedirects     int
+	Timeout          time.Duration
+	Resolver         Resolver
+}
+
+// Fetcher busca documentos em URLs informadas por clientes sem permitir acesso à rede interna
+// Os endereços são resolvidos e verificados no momento da conexão, e a conexão é feita no IP verificado,
+// de modo que redirects e DNS rebinding não escapam do bloqueio
+type Fetcher struct {
+	cfg    Config
+	client *http.Client
+}
+
+// Response é o resultado de Open; Body aplica o limite de tamanho durante a leitura
+type Response struct {
+	Body          io.ReadCloser
+	MimeType      string
+	ContentLength int64
+	FinalURL      string
+}
+
+// Result é o resultado de Fetch, com o conteúdo já lido
+type Result struct {
+	Data     []byte
+	MimeType string
+	Size     int64
+	FinalURL string
+}
+
+// NewFetcher aplica os padrões da configuração e monta o client HTTP
+func NewFetcher(cfg Config) *Fetcher {
+	if len(cfg.AllowedSchemes) == 0 {
+		cfg.AllowedSchemes = []string{"http", "https"}
+	}
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = defaultMaxSize
+	}
+	if cfg.MaxRedirects <= 0 {
+		cfg.MaxRedirects = defaultMaxRedirects
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	if cfg.Resolver == nil {
+		cfg.Resolver = net.DefaultResolver
+	}
+
+	f := &Fetcher{cfg: cfg}
+
+	dialer := &net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second}
+	transport := &http.Transport{
+		// Proxy desabilitado: a verificação de IP precisa valer para a conexão final
+		Proxy: nil,
+		DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+			return f.dial(ctx, dialer, network, address)
+		},
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: cfg.Timeout,
+	}
+
+	f.client = &http.Client{
+		Transport: transport,
+		Timeout:   cfg.Timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > f.cfg.MaxRedirects {
+				return newError(CategoryTooManyRedirects, req.URL.String(), fmt.Sprintf("more than %d redirects", f.cfg.MaxRedirects), nil)
+			}
+			return f.checkURL(req.URL)
+		},
+	}
+
+	return f
+}
+
+// NewFetcherFromConfig monta o fetcher a partir das variáveis FETCH_*
+func NewFetcherFromConfig(envVars config.EnvironmentVars) (*Fetcher, error) {
+	cfg := Config{
+		AllowedSchemes: splitList(envVars.FETCH_ALLOWED_SCHEMES),
+		AllowedHosts:   splitList(envVars.FETCH_ALLOWED_HOSTS),
+		MaxSize:        int64(envVars.FETCH_MAX_SIZE_MB) * 1024 * 1024,
+		MaxRedirects:   envVars.FETCH_MAX_REDIRECTS,
+		Timeout:        time.Duration(envVars.FETCH_TIMEOUT) * time.Second,
+	}
+
+	for _, network := range splitList(envVars.FETCH_ALLOWED_NETWORKS) {
+		prefix, err := netip.ParsePrefix(network)
+		if err != nil {
+			return nil, fmt.Errorf("invalid FETCH_ALLOWED_NETWORKS entry %q: %w", network, err)
+		}
+		cfg.AllowedNetworks = append(cfg.AllowedNetworks, prefix)
+	}
+
+	return NewFetcher(cfg), nil
+}
+
+// WithMaxSize retorna uma cópia do fetcher com outro limite de tamanho
+func (f *Fetcher) WithMaxSize(maxSize int64) *Fetcher {
+	cfg := f.cfg
+	cfg.MaxSize = maxSize
+	return NewFetcher(cfg)
+}
+
+// WithAllowedHosts retorna uma cópia do fetcher que aceita apenas os hosts informados
+func (f *Fetcher) WithAllowedHosts(hosts ...string) *Fetcher {
+	cfg := f.cfg
+	cfg.AllowedHosts = hosts
+	return NewFetcher(cfg)
+}
+
+// WithAllowedMimeTypes retorna uma cópia do fetcher que aceita apenas os tipos informados
+func (f *Fetcher) WithAllowedMimeTypes(mimeTypes ...string) *Fetcher {
+	cfg := f.cfg
+	cfg.AllowedMimeTypes = mimeTypes
+	return NewFetcher(cfg)
+}
+
+// Open valida a URL, faz o GET e detecta o tipo do conteúdo pelos primeiros bytes
+// O chamador deve fechar Body
+func (f *Fetcher) Open(ctx context.Context, rawURL string) (*Response, error) {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || parsed.Host == "" {
+		return nil, newError(CategoryInvalidURL, rawURL, "URL must be absolute", err)
+	}
+	if err := f.checkURL(parsed); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, parsed.String(), nil)
+	if err != nil {
+		return nil, newError(CategoryInvalidURL, rawURL, "failed to build request", err)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		var fetchErr *Error
+		if errors.As(err, &fetchErr) {
+			return nil, fetchErr
+		}
+		return nil, newError(CategoryNetwork, rawURL, "request failed", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, newError(CategoryHTTPStatus, rawURL, fmt.Sprintf("unexpected status code %d", resp.StatusCode), nil)
+	}
+
+	if resp.ContentLength > f.cfg.MaxSize {
+		resp.Body.Close()
+		return nil, newError(CategoryTooLarge, rawURL, fmt.Sprintf("content length %d exceeds limit of %d bytes", resp.ContentLength, f.cfg.MaxSize), nil)
+	}
+
+	body := &limitedBody{
+		reader:    bufio.NewReaderSize(resp.Body, sniffLength),
+		closer:    resp.Body,
+		remaining: f.cfg.MaxSize,
+		url:       rawURL,
+	}
+
+	head, err := body.reader.Peek(sniffLength)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		body.Close()
+		return nil, newError(CategoryNetwork, rawURL, "failed to read response", err)
+	}
+
+	mimeType := http.DetectContentType(head)
+	if !f.mimeTypeAllowed(mimeType) {
+		body.Close()
+		return nil, newError(CategoryContentType, rawURL, fmt.Sprintf("content type %s is not allowed", mimeType), nil)
+	}
+
+	return &Response{
+		Body:          body,
+		MimeType:      mimeType,
+		ContentLength: resp.ContentLength,
+		FinalURL:      resp.Request.URL.String(),
+	}, nil
+}
+
+// Fetch busca a URL e lê todo o conteúdo, respeitando o limite de tamanho
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (*Result, error) {
+	resp, err := f.Open(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		var fetchErr *Error
+		if errors.As(err, &fetchErr) {
+			return nil, fetchErr
+		}
+		return nil, newError(CategoryNetwork, rawURL, "failed to read response", err)
+	}
+
+	return &Result{
+		Data:     data,
+		MimeType: resp.MimeType,
+		Size:     int64(len(data)),
+		FinalURL: resp.FinalURL,
+	}, nil
+}
+
+// checkURL aplica as allowlists de esquema e host; o endereço é verificado na conexão
+func (f *Fetcher) checkURL(u *url.URL) error {
+	scheme := strings.ToLower(u.Scheme)
+	if !containsFold(f.cfg.AllowedSchemes, scheme) {
+		return newError(CategorySchemeNotAllowed, u.String(), fmt.Sprintf("scheme %q is not allowed", u.Scheme), nil)
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return newError(CategoryInvalidURL, u.String(), "URL has no host", nil)
+	}
+
+	if len(f.cfg.AllowedHosts) > 0 && !hostAllowed(f.cfg.AllowedHosts, host) {
+		return newError(CategoryHostNotAllowed, u.String(), fmt.Sprintf("host %q is not in the allowlist", host), nil)
+	}
+
+	return nil
+}
+
+// dial resolve o host, recusa endereços internos e conecta no IP verificado
+func (f *Fetcher) dial(ctx context.Context, dialer *net.Dialer, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, newError(CategoryInvalidURL, address, "invalid address", err)
+	}
+
+	var addrs []netip.Addr
+	if addr, err := netip.ParseAddr(host); err == nil {
+		addrs = []netip.Addr{addr}
+	} else {
+		addrs, err = f.cfg.Resolver.LookupNetIP(ctx, "ip", host)
+		if err != nil {
+			return nil, newError(CategoryDNSFailure, host, "failed to resolve host", err)
+		}
+		if len(addrs) == 0 {
+			return nil, newError(CategoryDNSFailure, host, "host has no addresses", nil)
+		}
+	}
+
+	var lastErr error
+	for _, addr := range addrs {
+		addr = addr.Unmap()
+		if f.blocked(addr) {
+			lastErr = newError(CategoryBlockedAddress, host, fmt.Sprintf("address %s is not publicly routable", addr), nil)
+			continue
+		}
+
+		conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(addr.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = newError(CategoryNetwork, host, "connection failed", err)
+	}
+
+	return nil, lastErr
+}
+
+// blockedNetworks complementa os testes de netip.Addr com faixas reservadas que não são públicas
+var blockedNetworks = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	// Prefixos de tradução e túnel (NAT64, 6to4 e Teredo) embutem endereços IPv4, inclusive internos
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+	netip.MustParsePrefix("2002::/16"),
+	netip.MustParsePrefix("2001::/32"),
+	netip.MustParsePrefix("100::/64"),
+	netip.MustParsePrefix("2001:db8::/32"),
+}
+
+func (f *Fetcher) blocked(addr netip.Addr) bool {
+	for _, allowed := range f.cfg.AllowedNetworks {
+		if allowed.Contains(addr) {
+			return false
+		}
+	}
+
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return true
+	}
+
+	for _, network := range blockedNetworks {
+		if network.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f *Fetcher) mimeTypeAllowed(mimeType string) bool {
+	if len(f.cfg.AllowedMimeTypes) == 0 {
+		return true
+	}
+	base := strings.TrimSpace(strings.SplitN(mimeType, ";", 2)[0])
+	return containsFold(f.cfg.AllowedMimeTypes, base)
+}
+
+type limitedBody struct {
+	reader    *bufio.Reader
+	closer    io.Closer
+	remaining int64
+	url       string
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, newError(CategoryTooLarge, b.url, "content exceeds size limit", nil)
+	}
+
+	// Lê um byte além do limite para distinguir "exatamente no limite" de "excedeu"
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+
+	n, err := b.reader.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n + int(b.remaining), newError(CategoryTooLarge, b.url, "content exceeds size limit", nil)
+	}
+	return n, err
+}
+
+func (b *limitedBody) Close() error {
+	return b.closer.Close()
+}
+
+func hostAllowed(allowedHosts []string, host string) bool {
+	for _, allowed := range allowedHosts {
+		allowed = strings.ToLower(allowed)
+		if strings.HasPrefix(allowed, "*.") {
+			if strings.HasSuffix(host, allowed[1:]) {
+				return true
+			}
+			continue
+		}
+		if host == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
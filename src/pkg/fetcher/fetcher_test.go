@@ -0,0 +1,235 @@
+package fetcher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"testing"
+
+	"app/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticResolver resolve qualquer host para os endereços configurados
+type staticResolver map[string][]netip.Addr
+
+func (r staticResolver) LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error) {
+	addrs, ok := r[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	return addrs, nil
+}
+
+var pdfContent = append([]byte("%PDF-1.4\n"), bytes.Repeat([]byte("0"), 2048)...)
+
+func newTestServer(t *testing.T, handler http.HandlerFunc) (*httptest.Server, string) {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	return server, serverURL.Port()
+}
+
+// newLoopbackFetcher libera apenas 127.0.0.1, simulando um host público servido pelo httptest
+func newLoopbackFetcher(cfg Config) *Fetcher {
+	cfg.AllowedNetworks = []netip.Prefix{netip.MustParsePrefix("127.0.0.1/32")}
+	cfg.Resolver = staticResolver{
+		"docs.example.com":     {netip.MustParseAddr("127.0.0.1")},
+		"metadata.example.com": {netip.MustParseAddr("169.254.169.254")},
+		"intranet.example.com": {netip.MustParseAddr("10.0.0.8")},
+	}
+	return NewFetcher(cfg)
+}
+
+func assertCategory(t *testing.T, err error, category Category) {
+	t.Helper()
+	var fetchErr *Error
+	require.True(t, errors.As(err, &fetchErr), "expected *fetcher.Error, got %v", err)
+	assert.Equal(t, category, fetchErr.Category)
+}
+
+func TestFetcher_BlocksInternalAddressesByDefault(t *testing.T) {
+	server, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(pdfContent)
+	})
+
+	f := NewFetcher(Config{})
+
+	_, err := f.Fetch(context.Background(), server.URL)
+	assertCategory(t, err, CategoryBlockedAddress)
+
+	for _, rawURL := range []string{
+		"http://169.254.169.254/latest/meta-data/",
+		"http://[::1]/",
+		"http://0.0.0.0/",
+		"http://100.64.0.1/",
+		"http://[::ffff:10.0.0.1]/",
+	} {
+		_, err := f.Fetch(context.Background(), rawURL)
+		assertCategory(t, err, CategoryBlockedAddress)
+	}
+}
+
+func TestFetcher_Fetch(t *testing.T) {
+	_, port := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/contrato.pdf":
+			w.Write(pdfContent)
+		case "/planilha.csv":
+			w.Write([]byte("nome,valor\nteste,1\n"))
+		case "/metadata":
+			http.Redirect(w, r, "http://metadata.example.com/latest/meta-data/", http.StatusFound)
+		case "/intranet":
+			http.Redirect(w, r, "http://intranet.example.com/admin", http.StatusFound)
+		case "/file-scheme":
+			http.Redirect(w, r, "file:///etc/passwd", http.StatusFound)
+		case "/other-host":
+			http.Redirect(w, r, "http://evil.example.net/x.pdf", http.StatusFound)
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		case "/missing":
+			http.NotFound(w, r)
+		}
+	})
+	base := "http://docs.example.com:" + port
+
+	t.Run("should download from allowed host", func(t *testing.T) {
+		f := newLoopbackFetcher(Config{AllowedMimeTypes: []string{"application/pdf"}})
+
+		result, err := f.Fetch(context.Background(), base+"/contrato.pdf")
+
+		require.NoError(t, err)
+		assert.Equal(t, pdfContent, result.Data)
+		assert.Equal(t, "application/pdf", result.MimeType)
+		assert.Equal(t, int64(len(pdfContent)), result.Size)
+	})
+
+	t.Run("should reject redirect to link-local metadata address", func(t *testing.T) {
+		_, err := newLoopbackFetcher(Config{}).Fetch(context.Background(), base+"/metadata")
+		assertCategory(t, err, CategoryBlockedAddress)
+	})
+
+	t.Run("should reject redirect to private address", func(t *testing.T) {
+		_, err := newLoopbackFetcher(Config{}).Fetch(context.Background(), base+"/intranet")
+		assertCategory(t, err, CategoryBlockedAddress)
+	})
+
+	t.Run("should reject redirect to disallowed scheme", func(t *testing.T) {
+		_, err := newLoopbackFetcher(Config{}).Fetch(context.Background(), base+"/file-scheme")
+		assertCategory(t, err, CategorySchemeNotAllowed)
+	})
+
+	t.Run("should re-check host allowlist on redirect", func(t *testing.T) {
+		f := newLoopbackFetcher(Config{AllowedHosts: []string{"*.example.com"}})
+
+		_, err := f.Fetch(context.Background(), base+"/other-host")
+		assertCategory(t, err, CategoryHostNotAllowed)
+	})
+
+	t.Run("should reject host outside allowlist", func(t *testing.T) {
+		f := newLoopbackFetcher(Config{AllowedHosts: []string{"files.example.com"}})
+
+		_, err := f.Fetch(context.Background(), base+"/contrato.pdf")
+		assertCategory(t, err, CategoryHostNotAllowed)
+	})
+
+	t.Run("should limit redirects", func(t *testing.T) {
+		_, err := newLoopbackFetcher(Config{MaxRedirects: 3}).Fetch(context.Background(), base+"/loop")
+		assertCategory(t, err, CategoryTooManyRedirects)
+	})
+
+	t.Run("should reject content above the size limit", func(t *testing.T) {
+		_, err := newLoopbackFetcher(Config{MaxSize: 1024}).Fetch(context.Background(), base+"/contrato.pdf")
+		assertCategory(t, err, CategoryTooLarge)
+	})
+
+	t.Run("should reject unsupported content type", func(t *testing.T) {
+		f := newLoopbackFetcher(Config{AllowedMimeTypes: []string{"application/pdf"}})
+
+		_, err := f.Fetch(context.Background(), base+"/planilha.csv")
+		assertCategory(t, err, CategoryContentType)
+	})
+
+	t.Run("should report non-200 status", func(t *testing.T) {
+		_, err := newLoopbackFetcher(Config{}).Fetch(context.Background(), base+"/missing")
+		assertCategory(t, err, CategoryHTTPStatus)
+	})
+
+	t.Run("should report DNS failures", func(t *testing.T) {
+		_, err := newLoopbackFetcher(Config{}).Fetch(context.Background(), "http://unknown.example.com/")
+		assertCategory(t, err, CategoryDNSFailure)
+	})
+
+	t.Run("should reject relative URLs", func(t *testing.T) {
+		_, err := newLoopbackFetcher(Config{}).Fetch(context.Background(), "/contrato.pdf")
+		assertCategory(t, err, CategoryInvalidURL)
+	})
+}
+
+func TestFetcher_OpenStreamsWithinLimit(t *testing.T) {
+	_, port := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		// Sem Content-Length: o limite precisa valer durante a leitura
+		w.Header().Set("Transfer-Encoding", "chunked")
+		w.Write(pdfContent)
+		w.(http.Flusher).Flush()
+		w.Write(pdfContent)
+	})
+
+	f := newLoopbackFetcher(Config{MaxSize: int64(len(pdfContent)) + 10})
+
+	resp, err := f.Open(context.Background(), "http://docs.example.com:"+port+"/")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "application/pdf", resp.MimeType)
+
+	_, err = io.ReadAll(resp.Body)
+	assertCategory(t, err, CategoryTooLarge)
+}
+
+func TestNewFetcherFromConfig(t *testing.T) {
+	f, err := NewFetcherFromConfig(config.EnvironmentVars{
+		FETCH_ALLOWED_SCHEMES:  "https",
+		FETCH_ALLOWED_HOSTS:    "docs.example.com, *.cdn.example.com",
+		FETCH_ALLOWED_NETWORKS: "10.20.0.0/16",
+		FETCH_MAX_SIZE_MB:      2,
+		FETCH_TIMEOUT:          5,
+		FETCH_MAX_REDIRECTS:    2,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"https"}, f.cfg.AllowedSchemes)
+	assert.Equal(t, []string{"docs.example.com", "*.cdn.example.com"}, f.cfg.AllowedHosts)
+	assert.Equal(t, int64(2*1024*1024), f.cfg.MaxSize)
+	assert.False(t, f.blocked(netip.MustParseAddr("10.20.1.1")))
+	assert.True(t, f.blocked(netip.MustParseAddr("10.21.1.1")))
+
+	_, err = f.Fetch(context.Background(), "http://docs.example.com/contrato.pdf")
+	assertCategory(t, err, CategorySchemeNotAllowed)
+
+	_, err = NewFetcherFromConfig(config.EnvironmentVars{FETCH_ALLOWED_NETWORKS: "not-a-cidr"})
+	assert.ErrorContains(t, err, "FETCH_ALLOWED_NETWORKS")
+}
+
+func TestFetcher_BlocksIPv4TranslationPrefixes(t *testing.T) {
+	f := NewFetcher(Config{})
+
+	for _, addr := range []string{
+		"64:ff9b::a9fe:a9fe",  // NAT64 de 169.254.169.254
+		"64:ff9b:1::a00:1",    // NAT64 de uso local
+		"2002:a00:1::1",       // 6to4 de 10.0.0.1
+		"2002:7f00:1::1",      // 6to4 de 127.0.0.1
+		"2001:0:4136:e378::1", // Teredo
+	} {
+		assert.True(t, f.blocked(netip.MustParseAddr(addr)), addr)
+	}
+	assert.False(t, f.blocked(netip.MustParseAddr("2606:4700::1111")))
+}
@@ -6,7 +6,8 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func NewLogrusLogger(level string) *logrus.Logger {
+// NewLogrusLogger cria o logger em JSON que redige os dados pessoais com redactor
+func NewLogrusLogger(level string, redactor *Redactor) *logrus.Logger {
 	logger := logrus.New()
 
 	logger.SetFormatter(&logrus.JSONFormatter{
@@ -14,6 +15,7 @@ func NewLogrusLogger(level string) *logrus.Logger {
 	})
 
 	logger.SetOutput(os.Stdout)
+	logger.AddHook(NewRedactionHook(redactor))
 
 	switch LogLevel(level) {
 	case DEBUG:
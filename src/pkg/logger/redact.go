@@ -0,0 +1,242 @@
+package logger
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"app/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Modos de redação dos dados pessoais nos logs
+const (
+	RedactionMask = "mask" // substitui o valor por RedactedValue
+	RedactionHash = "hash" // substitui por um hash curto, que ainda permite correlacionar os logs
+	RedactionOff  = "off"
+)
+
+const RedactedValue = "[REDACTED]"
+
+// DefaultRedactionFields são os campos redigidos por padrão; também valem com prefixo, como signer_email
+var DefaultRedactionFields = []string{"email", "documentation", "birthday", "phone", "phone_number", "signer_name", "payload", "raw_payload", "raw_data"}
+
+// DefaultRedactionPatterns são os padrões procurados nas mensagens e nos demais valores
+var DefaultRedactionPatterns = []string{"cpf", "cnpj", "email"}
+
+var redactionPatterns = map[string]*regexp.Regexp{
+	"cpf":  regexp.MustCompile(`\b\d{3}\.\d{3}\.\d{3}-\d{2}\b|\b\d{11}\b`),
+	"cnpj": regexp.MustCompile(`\b[0-9A-Z]{2}\.[0-9A-Z]{3}\.[0-9A-Z]{3}/[0-9A-Z]{4}-\d{2}\b|\b\d{14}\b`),
+	// Inclui o @ codificado das query strings
+	"email": regexp.MustCompile(`[A-Za-z0-9._+-]+(?:@|%40)[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`),
+}
+
+type RedactionConfig struct {
+	Mode     string
+	Fields   []string
+	Patterns []string
+	// HashKey torna os hashes uma HMAC; sem ela, CPFs e telefones podem ser recuperados por força bruta
+	HashKey string
+}
+
+// Redactor remove os dados pessoais dos campos e das mensagens de log
+type Redactor struct {
+	mode     string
+	fields   []string
+	patterns []*regexp.Regexp
+	hashKey  []byte
+}
+
+func NewRedactor(cfg RedactionConfig) (*Redactor, error) {
+	mode := strings.ToLower(strings.TrimSpace(cfg.Mode))
+	if mode == "" {
+		mode = RedactionMask
+	}
+	if mode != RedactionMask && mode != RedactionHash && mode != RedactionOff {
+		return nil, fmt.Errorf("unknown log redaction mode %q: use %s, %s or %s", cfg.Mode, RedactionMask, RedactionHash, RedactionOff)
+	}
+
+	redactor := &Redactor{mode: mode, hashKey: []byte(cfg.HashKey)}
+	for _, field := range cfg.Fields {
+		if field = strings.ToLower(strings.TrimSpace(field)); field != "" {
+			redactor.fields = append(redactor.fields, field)
+		}
+	}
+	for _, name := range cfg.Patterns {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		pattern, ok := redactionPatterns[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown log redaction pattern %q", name)
+		}
+		redactor.patterns = append(redactor.patterns, pattern)
+	}
+	return redactor, nil
+}
+
+// NewRedactorFromConfig lê LOG_PII_*; listas vazias usam os campos e padrões padrão
+func NewRedactorFromConfig(envVars config.EnvironmentVars) (*Redactor, error) {
+	cfg := RedactionConfig{
+		Mode:     envVars.LOG_PII_REDACTION,
+		Fields:   DefaultRedactionFields,
+		Patterns: DefaultRedactionPatterns,
+		HashKey:  envVars.LOG_PII_HASH_KEY,
+	}
+	if strings.TrimSpace(envVars.LOG_PII_FIELDS) != "" {
+		cfg.Fields = strings.Split(envVars.LOG_PII_FIELDS, ",")
+	}
+	if strings.TrimSpace(envVars.LOG_PII_PATTERNS) != "" {
+		cfg.Patterns = strings.Split(envVars.LOG_PII_PATTERNS, ",")
+	}
+	return NewRedactor(cfg)
+}
+
+// defaultRedactor redige os campos e padrões padrão nos loggers criados sem redactor
+var defaultRedactor = mustRedactor(RedactionConfig{Fields: DefaultRedactionFields, Patterns: DefaultRedactionPatterns})
+
+func mustRedactor(cfg RedactionConfig) *Redactor {
+	redactor, err := NewRedactor(cfg)
+	if err != nil {
+		panic(err)
+	}
+	return redactor
+}
+
+func (r *Redactor) Enabled() bool {
+	return r.mode != RedactionOff
+}
+
+// RedactString substitui os padrões configurados (CPF, CNPJ, email) encontrados no texto
+func (r *Redactor) RedactString(value string) string {
+	if !r.Enabled() {
+		return value
+	}
+	for _, pattern := range r.patterns {
+		value = pattern.ReplaceAllStringFunc(value, r.replace)
+	}
+	return value
+}
+
+// RedactFields devolve uma cópia dos campos com os dados pessoais removidos
+func (r *Redactor) RedactFields(fields map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		redacted[key] = r.redactValue(key, value)
+	}
+	return redacted
+}
+
+func (r *Redactor) redactValue(key string, value interface{}) interface{} {
+	if r.isPIIField(key) {
+		switch v := value.(type) {
+		case nil, bool, int, int32, int64, uint, uint32, uint64, float32, float64:
+			return v
+		case string:
+			if v == "" {
+				return v
+			}
+			return r.replace(v)
+		case *string:
+			if v == nil || *v == "" {
+				return v
+			}
+			return r.replace(*v)
+		case []byte:
+			return r.replace(string(v))
+		}
+		return r.replace(fmt.Sprint(value))
+	}
+
+	switch v := value.(type) {
+	case string:
+		return r.RedactString(v)
+	case error:
+		return r.RedactString(v.Error())
+	case map[string]interface{}:
+		return r.RedactFields(v)
+	case logrus.Fields:
+		return r.RedactFields(v)
+	}
+	return value
+}
+
+// isPIIField compara o nome sem diferenciar caixa; "email" também cobre signer_email e admin_email
+func (r *Redactor) isPIIField(key string) bool {
+	key = strings.ToLower(key)
+	for _, field := range r.fields {
+		if key == field || strings.HasSuffix(key, "_"+field) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Redactor) replace(value string) string {
+	if r.mode != RedactionHash {
+		return RedactedValue
+	}
+	var sum []byte
+	if len(r.hashKey) > 0 {
+		mac := hmac.New(sha256.New, r.hashKey)
+		mac.Write([]byte(value))
+		sum = mac.Sum(nil)
+	} else {
+		digest := sha256.Sum256([]byte(value))
+		sum = digest[:]
+	}
+	return "hash:" + hex.EncodeToString(sum[:8])
+}
+
+// Writer redige o texto gravado em w; usado no log de acesso do gin e no pacote log
+func (r *Redactor) Writer(w io.Writer) io.Writer {
+	return &redactingWriter{redactor: r, out: w}
+}
+
+type redactingWriter struct {
+	redactor *Redactor
+	out      io.Writer
+}
+
+func (w *redactingWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(w.out, w.redactor.RedactString(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// RedactionHook redige mensagem e campos de todas as entradas antes da formatação
+// Sem redactor, redige os campos e padrões padrão. Campos precisam ir em WithField/WithFields:
+// passados como argumento (logger.Info("msg", map...)) viram texto da mensagem e só os padrões são redigidos
+type RedactionHook struct {
+	redactor *Redactor
+}
+
+func NewRedactionHook(redactor *Redactor) *RedactionHook {
+	if redactor == nil {
+		redactor = defaultRedactor
+	}
+	return &RedactionHook{redactor: redactor}
+}
+
+func (h *RedactionHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *RedactionHook) Fire(entry *logrus.Entry) error {
+	redactor := h.redactor
+	if !redactor.Enabled() {
+		return nil
+	}
+
+	entry.Message = redactor.RedactString(entry.Message)
+	// entry.Data é uma cópia própria de cada entrada, então pode ser substituída
+	entry.Data = redactor.RedactFields(entry.Data)
+	return nil
+}
@@ -0,0 +1,155 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"app/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLogger(t *testing.T, redactor *Redactor) (*logrus.Logger, *bytes.Buffer) {
+	var out bytes.Buffer
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.JSONFormatter{})
+	logger.SetOutput(&out)
+	logger.AddHook(NewRedactionHook(redactor))
+	return logger, &out
+}
+
+func decodeEntry(t *testing.T, out *bytes.Buffer) map[string]interface{} {
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &entry))
+	return entry
+}
+
+func TestRedactor_RedactString(t *testing.T) {
+	redactor, err := NewRedactor(RedactionConfig{Patterns: DefaultRedactionPatterns})
+	require.NoError(t, err)
+
+	cases := map[string]string{
+		"Signer maria.silva+vs@example.com.br has no term":   "Signer [REDACTED] has no term",
+		"GET /api/v1/terms?email=maria%40example.com":        "GET /api/v1/terms?email=[REDACTED]",
+		"cpf 123.456.789-09 e 12345678909":                   "cpf [REDACTED] e [REDACTED]",
+		"cnpj 12.ABC.345/01DE-35 e 12345678000195":           "cnpj [REDACTED] e [REDACTED]",
+		"envelope 42 criado em 1700000000000 com 3 arquivos": "envelope 42 criado em 1700000000000 com 3 arquivos",
+	}
+	for input, expected := range cases {
+		assert.Equal(t, expected, redactor.RedactString(input), input)
+	}
+}
+
+func TestRedactionHook(t *testing.T) {
+	t.Run("should mask pii fields, nested fields and patterns in the message", func(t *testing.T) {
+		redactor, err := NewRedactor(RedactionConfig{Fields: DefaultRedactionFields, Patterns: DefaultRedactionPatterns})
+		require.NoError(t, err)
+		logger, out := newTestLogger(t, redactor)
+
+		logger.WithFields(logrus.Fields{
+			"signer_email":      "maria@example.com",
+			"documentation":     "123.456.789-09",
+			"has_documentation": true,
+			"raw_payload":       map[string]interface{}{"name": "Maria"},
+			"correlation_id":    "abc-123",
+			"details":           map[string]interface{}{"email": "joao@example.com", "provider": "vert-sign"},
+		}).WithError(errors.New("API error (status 422): cpf 98765432100 invalid")).
+			Warn("Blocked envelope for maria@example.com")
+
+		entry := decodeEntry(t, out)
+		assert.Equal(t, "Blocked envelope for [REDACTED]", entry["msg"])
+		assert.Equal(t, RedactedValue, entry["signer_email"])
+		assert.Equal(t, RedactedValue, entry["documentation"])
+		assert.Equal(t, true, entry["has_documentation"])
+		assert.Equal(t, RedactedValue, entry["raw_payload"])
+		assert.Equal(t, "abc-123", entry["correlation_id"])
+		assert.Equal(t, map[string]interface{}{"email": RedactedValue, "provider": "vert-sign"}, entry["details"])
+		assert.Equal(t, "API error (status 422): cpf [REDACTED] invalid", entry["error"])
+	})
+
+	t.Run("should redact patterns when fields are passed as a map argument", func(t *testing.T) {
+		redactor, err := NewRedactor(RedactionConfig{Fields: DefaultRedactionFields, Patterns: DefaultRedactionPatterns})
+		require.NoError(t, err)
+		logger, out := newTestLogger(t, redactor)
+
+		// Sem WithFields o mapa vira texto da mensagem: só os padrões (email, CPF/CNPJ) são reconhecidos
+		logger.Info("Processing webhook", map[string]interface{}{
+			"signer_email":  "maria@example.com",
+			"documentation": "123.456.789-09",
+		})
+
+		entry := decodeEntry(t, out)
+		assert.NotContains(t, entry["msg"], "maria@example.com")
+		assert.NotContains(t, entry["msg"], "123.456.789-09")
+		assert.Contains(t, entry["msg"], RedactedValue)
+		assert.NotContains(t, entry, "signer_email")
+	})
+
+	t.Run("should hash values so entries can still be correlated", func(t *testing.T) {
+		redactor, err := NewRedactor(RedactionConfig{Mode: RedactionHash, Fields: []string{"email"}, HashKey: "secret"})
+		require.NoError(t, err)
+		logger, out := newTestLogger(t, redactor)
+
+		logger.WithField("email", "maria@example.com").Info("first")
+		first := decodeEntry(t, out)
+		out.Reset()
+		logger.WithField("admin_email", "maria@example.com").Info("second")
+		second := decodeEntry(t, out)
+
+		assert.True(t, strings.HasPrefix(first["email"].(string), "hash:"))
+		assert.Equal(t, first["email"], second["admin_email"])
+		assert.NotContains(t, first["email"], "maria")
+	})
+
+	t.Run("should keep entries untouched when disabled", func(t *testing.T) {
+		redactor, err := NewRedactor(RedactionConfig{Mode: RedactionOff, Fields: DefaultRedactionFields, Patterns: DefaultRedactionPatterns})
+		require.NoError(t, err)
+		logger, out := newTestLogger(t, redactor)
+
+		logger.WithField("email", "maria@example.com").Info("cpf 123.456.789-09")
+
+		entry := decodeEntry(t, out)
+		assert.Equal(t, "maria@example.com", entry["email"])
+		assert.Equal(t, "cpf 123.456.789-09", entry["msg"])
+	})
+
+	t.Run("should not change the fields of the caller", func(t *testing.T) {
+		logger, out := newTestLogger(t, nil)
+		fields := logrus.Fields{"email": "maria@example.com"}
+
+		logger.WithFields(fields).Info("message")
+
+		assert.Equal(t, RedactedValue, decodeEntry(t, out)["email"])
+		assert.Equal(t, "maria@example.com", fields["email"])
+	})
+}
+
+func TestRedactor_Writer(t *testing.T) {
+	var out bytes.Buffer
+	writer := defaultRedactor.Writer(&out)
+
+	n, err := writer.Write([]byte("[GIN] GET /api/v1/auto-signature/terms?documentation=123.456.789-09\n"))
+
+	require.NoError(t, err)
+	assert.Equal(t, 68, n)
+	assert.Equal(t, "[GIN] GET /api/v1/auto-signature/terms?documentation=[REDACTED]\n", out.String())
+}
+
+func TestNewRedactorFromConfig(t *testing.T) {
+	redactor, err := NewRedactorFromConfig(config.EnvironmentVars{LOG_PII_FIELDS: "ssn, token", LOG_PII_PATTERNS: "cpf"})
+	require.NoError(t, err)
+	assert.True(t, redactor.isPIIField("api_token"))
+	assert.False(t, redactor.isPIIField("email"))
+	assert.Equal(t, "x@example.com [REDACTED]", redactor.RedactString("x@example.com 12345678909"))
+
+	_, err = NewRedactorFromConfig(config.EnvironmentVars{LOG_PII_REDACTION: "drop"})
+	assert.Error(t, err)
+
+	_, err = NewRedactorFromConfig(config.EnvironmentVars{LOG_PII_PATTERNS: "rg"})
+	assert.Error(t, err)
+}
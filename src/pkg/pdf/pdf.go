@@ -0,0 +1,245 @@
+// Package pdf gera PDFs simples (texto em Helvetica ou imagens JPEG, A4) sem dependências externas
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Style define a fonte e o espaçamento de um bloco de texto
+type Style int
+
+const (
+	StyleBody Style = iota
+	StyleHeading
+	StyleSubheading
+)
+
+const (
+	pageWidth    = 595.0
+	pageHeight   = 842.0
+	marginLeft   = 50.0
+	marginTop    = 52.0
+	marginBottom = 50.0
+	textWidth    = pageWidth - 2*marginLeft
+	imageMargin  = 36.0
+)
+
+type styleMetrics struct {
+	font    string
+	size    float64
+	leading float64
+	// charWidth é a largura média de um caractere em unidades do tamanho da fonte
+	charWidth float64
+}
+
+var metrics = map[Style]styleMetrics{
+	StyleBody:       {font: "F1", size: 11, leading: 15, charWidth: 0.5},
+	StyleHeading:    {font: "F2", size: 16, leading: 24, charWidth: 0.56},
+	StyleSubheading: {font: "F2", size: 13, leading: 19, charWidth: 0.56},
+}
+
+// Block é um parágrafo; textos longos são quebrados na largura da página e "" gera uma linha em branco
+type Block struct {
+	Text  string
+	Style Style
+}
+
+// RenderLines gera um PDF com uma linha de corpo de texto por item
+func RenderLines(lines []string) ([]byte, error) {
+	blocks := make([]Block, len(lines))
+	for i, line := range lines {
+		blocks[i] = Block{Text: line}
+	}
+	return Render(blocks)
+}
+
+// Render gera o PDF, quebrando linhas e páginas automaticamente
+// O texto é codificado em WinAnsi (Windows-1252), que cobre a acentuação do português
+func Render(blocks []Block) ([]byte, error) {
+	var pages []string
+	var page strings.Builder
+	y := pageHeight - marginTop
+
+	for _, block := range blocks {
+		m, ok := metrics[block.Style]
+		if !ok {
+			return nil, fmt.Errorf("unknown pdf style: %d", block.Style)
+		}
+
+		for _, line := range wrap(block.Text, int(textWidth/(m.size*m.charWidth))) {
+			if y-m.leading < marginBottom {
+				pages = append(pages, page.String())
+				page.Reset()
+				y = pageHeight - marginTop
+			}
+			y -= m.leading
+
+			fmt.Fprintf(&page, "BT /%s %g Tf %g %g Td (%s) Tj ET\n", m.font, m.size, marginLeft, y, encode(line))
+		}
+	}
+	pages = append(pages, page.String())
+
+	textPages := make([]pageObject, len(pages))
+	for i, content := range pages {
+		textPages[i] = pageObject{width: pageWidth, height: pageHeight, content: content}
+	}
+	return assemble(textPages), nil
+}
+
+// ImagePage é uma imagem JPEG (RGB) ocupando uma página
+type ImagePage struct {
+	JPEG   []byte
+	Width  int
+	Height int
+}
+
+// RenderImages gera um PDF com uma imagem por página, centralizada e reduzida para caber nas margens
+// Imagens mais largas que altas usam a página A4 em paisagem
+func RenderImages(images []ImagePage) ([]byte, error) {
+	if len(images) == 0 {
+		return nil, fmt.Errorf("no images to render")
+	}
+
+	pages := make([]pageObject, len(images))
+	for i, img := range images {
+		if img.Width <= 0 || img.Height <= 0 || len(img.JPEG) == 0 {
+			return nil, fmt.Errorf("invalid image %d", i+1)
+		}
+
+		width, height := pageWidth, pageHeight
+		if img.Width > img.Height {
+			width, height = pageHeight, pageWidth
+		}
+
+		scale := min((width-2*imageMargin)/float64(img.Width), (height-2*imageMargin)/float64(img.Height), 1)
+		drawWidth := float64(img.Width) * scale
+		drawHeight := float64(img.Height) * scale
+
+		pages[i] = pageObject{
+			width:   width,
+			height:  height,
+			content: fmt.Sprintf("q %.2f 0 0 %.2f %.2f %.2f cm /Im1 Do Q\n", drawWidth, drawHeight, (width-drawWidth)/2, (height-drawHeight)/2),
+			image:   &images[i],
+		}
+	}
+
+	return assemble(pages), nil
+}
+
+type pageObject struct {
+	width   float64
+	height  float64
+	content string
+	image   *ImagePage
+}
+
+// assemble monta catálogo, fontes, páginas (com conteúdo e imagem) e a tabela xref
+func assemble(pages []pageObject) []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"", // árvore de páginas, preenchida depois de numerar as páginas
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+	}
+
+	kids := make([]string, len(pages))
+	for i, page := range pages {
+		pageNumber := len(objects) + 1
+		contentNumber := pageNumber + 1
+		kids[i] = fmt.Sprintf("%d 0 R", pageNumber)
+
+		resources := "/Font << /F1 3 0 R /F2 4 0 R >>"
+		if page.image != nil {
+			resources = fmt.Sprintf("/XObject << /Im1 %d 0 R >>", contentNumber+1)
+		}
+
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %g %g] /Resources << %s >> /Contents %d 0 R >>", page.width, page.height, resources, contentNumber),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(page.content), page.content),
+		)
+		if page.image != nil {
+			objects = append(objects, fmt.Sprintf(
+				"<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode /Length %d >>\nstream\n%s\nendstream",
+				page.image.Width, page.image.Height, len(page.image.JPEG), page.image.JPEG,
+			))
+		}
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, len(objects)+1)
+	for i, object := range objects {
+		offsets[i+1] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xrefOffset := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n", len(objects)+1)
+	buf.WriteString("0000000000 65535 f \n")
+	for i := 1; i <= len(objects); i++ {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offsets[i])
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF", len(objects)+1, xrefOffset)
+
+	return buf.Bytes()
+}
+
+// wrap quebra o texto em linhas de até width caracteres, preservando palavras quando possível
+func wrap(text string, width int) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	var lines []string
+	var current []rune
+	for _, word := range words {
+		runes := []rune(word)
+		for len(runes) > width {
+			if len(current) > 0 {
+				lines = append(lines, string(current))
+				current = nil
+			}
+			lines = append(lines, string(runes[:width]))
+			runes = runes[width:]
+		}
+
+		switch {
+		case len(current) == 0:
+			current = runes
+		case len(current)+1+len(runes) <= width:
+			current = append(append(current, ' '), runes...)
+		default:
+			lines = append(lines, string(current))
+			current = runes
+		}
+	}
+	if len(current) > 0 {
+		lines = append(lines, string(current))
+	}
+	return lines
+}
+
+// encode converte para Windows-1252 e escapa os delimitadores de string do PDF
+// Caracteres fora do Windows-1252 viram "?"
+func encode(text string) string {
+	var encoded bytes.Buffer
+	for _, r := range text {
+		b, ok := charmap.Windows1252.EncodeRune(r)
+		if !ok {
+			b = '?'
+		}
+		switch b {
+		case '\\', '(', ')':
+			encoded.WriteByte('\\')
+		}
+		encoded.WriteByte(b)
+	}
+	return encoded.String()
+}
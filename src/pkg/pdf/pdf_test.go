@@ -0,0 +1,101 @@
+package pdf
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	t.Run("should produce a valid single page PDF", func(t *testing.T) {
+		data, err := Render([]Block{
+			{Text: "Contrato de Prestação", Style: StyleHeading},
+			{Text: "Cláusula (1): o \\ contratante"},
+		})
+		require.NoError(t, err)
+
+		assert.True(t, bytes.HasPrefix(data, []byte("%PDF-1.4\n")))
+		assert.True(t, bytes.HasSuffix(data, []byte("\n%%EOF")))
+		assert.Contains(t, string(data), "/Count 1 >>")
+		assert.Contains(t, string(data), "/F2 16 Tf")
+		assert.Contains(t, string(data), "Presta\xe7\xe3o")
+		assert.Contains(t, string(data), `Cl`+"\xe1"+`usula \(1\): o \\ contratante`)
+		assertXref(t, data)
+	})
+
+	t.Run("should wrap long paragraphs and paginate", func(t *testing.T) {
+		paragraph := strings.Repeat("palavra ", 200)
+		blocks := make([]Block, 20)
+		for i := range blocks {
+			blocks[i] = Block{Text: paragraph}
+		}
+
+		data, err := Render(blocks)
+		require.NoError(t, err)
+
+		pages := regexp.MustCompile(`/Count (\d+) >>`).FindSubmatch(data)
+		require.NotNil(t, pages)
+		count, _ := strconv.Atoi(string(pages[1]))
+		assert.Greater(t, count, 1)
+		assertXref(t, data)
+	})
+
+	t.Run("should reject unknown styles", func(t *testing.T) {
+		_, err := Render([]Block{{Text: "x", Style: Style(99)}})
+		assert.ErrorContains(t, err, "unknown pdf style")
+	})
+}
+
+func TestRenderImages(t *testing.T) {
+	t.Run("should place each image on its own page", func(t *testing.T) {
+		data, err := RenderImages([]ImagePage{
+			{JPEG: []byte("\xff\xd8retrato\xff\xd9"), Width: 1000, Height: 2000},
+			{JPEG: []byte("\xff\xd8paisagem\xff\xd9"), Width: 400, Height: 300},
+		})
+		require.NoError(t, err)
+
+		assert.Contains(t, string(data), "/Count 2 >>")
+		assert.Contains(t, string(data), "/MediaBox [0 0 595 842]")
+		assert.Contains(t, string(data), "/MediaBox [0 0 842 595]")
+		assert.Contains(t, string(data), "/Width 1000 /Height 2000 /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode")
+		// Imagens menores que a área útil não são ampliadas
+		assert.Contains(t, string(data), "q 400.00 0 0 300.00 221.00 147.50 cm /Im1 Do Q")
+		assertXref(t, data)
+	})
+
+	t.Run("should reject empty input", func(t *testing.T) {
+		_, err := RenderImages(nil)
+		assert.Error(t, err)
+
+		_, err = RenderImages([]ImagePage{{JPEG: []byte("x")}})
+		assert.ErrorContains(t, err, "invalid image 1")
+	})
+}
+
+func TestWrap(t *testing.T) {
+	assert.Equal(t, []string{""}, wrap("   ", 10))
+	assert.Equal(t, []string{"um dois", "tres"}, wrap("um dois tres", 8))
+	assert.Equal(t, []string{"abcde", "fghij", "k"}, wrap("abcdefghijk", 5))
+}
+
+// assertXref confere se cada entrada da tabela xref aponta para o início do objeto correspondente
+func assertXref(t *testing.T, data []byte) {
+	t.Helper()
+
+	start := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(data)
+	require.NotNil(t, start)
+	xrefOffset, _ := strconv.Atoi(string(start[1]))
+	require.True(t, bytes.HasPrefix(data[xrefOffset:], []byte("xref\n")))
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(data[xrefOffset:], -1)
+	require.NotEmpty(t, entries)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		assert.True(t, bytes.HasPrefix(data[offset:], []byte(strconv.Itoa(i+1)+" 0 obj\n")), "object %d", i+1)
+	}
+}
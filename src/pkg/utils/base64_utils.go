@@ -4,7 +4,6 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
-	"net/http"
 	"os"
 )
 
@@ -16,6 +15,15 @@ const (
 	MaxBase64Size = 10 * 1024 * 1024
 )
 
+// SupportedMimeTypes lista os tipos de arquivo aceitos em base64, upload e download por URL
+var SupportedMimeTypes = []string{
+	"application/pdf",
+	"image/jpeg",
+	"image/jpg",
+	"image/png",
+	"image/gif",
+}
+
 // Base64FileInfo contém informações do arquivo decodificado
 type Base64FileInfo struct {
 	DecodedData []byte
@@ -61,12 +69,8 @@ func DecodeBase64File(base64Data string) (*Base64FileInfo, error) {
 		return nil, fmt.Errorf("tamanho do arquivo após decodificação excede o limite de %.1f MB", MaxFileSize/(1024*1024))
 	}
 
-	// Detectar MIME type usando amostra adequada
-	sampleSize := len(decodedData)
-	if sampleSize > 512 {
-		sampleSize = 512
-	}
-	mimeType := http.DetectContentType(decodedData[:sampleSize])
+	// Detectar MIME type pelo conteúdo
+	mimeType := DetectMimeType(decodedData)
 
 	// Criar arquivo temporário
 	tempFile, err := os.CreateTemp("", "docsigner_base64_*")
@@ -108,20 +112,18 @@ func CleanupTempFile(tempPath string) error {
 }
 
 // ValidateMimeType verifica se o MIME type é suportado
+// Tipos conversíveis (DOCX, ODT, HTML) são aceitos e convertidos para PDF na ingestão
 func ValidateMimeType(mimeType string) error {
-	supportedTypes := map[string]bool{
-		"application/pdf": true,
-		"image/jpeg":      true,
-		"image/jpg":       true,
-		"image/png":       true,
-		"image/gif":       true,
+	for _, supported := range SupportedMimeTypes {
+		if mimeType == supported {
+			return nil
+		}
 	}
-
-	if !supportedTypes[mimeType] {
-		return fmt.Errorf("tipo de arquivo não suportado: %s", mimeType)
+	if IsConvertibleMimeType(mimeType) {
+		return nil
 	}
 
-	return nil
+	return fmt.Errorf("tipo de arquivo não suportado: %s", mimeType)
 }
 
 // GetFileExtensionFromMimeType retorna a extensão baseada no MIME type
@@ -1,29 +1,32 @@
 package utils
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
+	neturl "net/url"
 	"os"
 	"path/filepath"
+
+	"app/pkg/fetcher"
 )
 
-func DownloadFile(url string) (localPath string, err error) {
+// DownloadFile baixa a URL para um arquivo temporário por documentFetcher, com as mesmas restrições de rede e tamanho
+func DownloadFile(documentFetcher *fetcher.Fetcher, url string) (localPath string, err error) {
 	// Faz o download
-	resp, err := http.Get(url)
+	resp, err := documentFetcher.Open(context.Background(), url)
 	if err != nil {
 		return "", err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("download failed: status %s", resp.Status)
-	}
-
 	// Extrai o nome do arquivo da URL (fallback para temp)
-	filename := filepath.Base(resp.Request.URL.Path)
-	if filename == "." || filename == "/" {
-		filename = "downloaded_file"
+	filename := "downloaded_file"
+	if finalURL, parseErr := neturl.Parse(resp.FinalURL); parseErr == nil {
+		if base := filepath.Base(finalURL.Path); base != "." && base != "/" {
+			filename = base
+		}
 	}
 
 	// Cria arquivo temporário
@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	MimeTypeDOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	MimeTypeODT  = "application/vnd.oasis.opendocument.text"
+	MimeTypeHTML = "text/html"
+)
+
+// ConvertibleMimeTypes lista os tipos aceitos na entrada que são convertidos para PDF antes do envio ao provider
+var ConvertibleMimeTypes = []string{
+	MimeTypeDOCX,
+	MimeTypeODT,
+	MimeTypeHTML,
+}
+
+// DetectMimeType detecta o MIME type pelo conteúdo
+// Além de http.DetectContentType, distingue DOCX e ODT de um ZIP qualquer e remove o charset de HTML
+func DetectMimeType(data []byte) string {
+	sample := data
+	if len(sample) > 512 {
+		sample = sample[:512]
+	}
+
+	mimeType := http.DetectContentType(sample)
+	switch {
+	case mimeType == "application/zip":
+		return detectOpenDocument(data, mimeType)
+	case strings.HasPrefix(mimeType, MimeTypeHTML):
+		return MimeTypeHTML
+	}
+	return mimeType
+}
+
+// IsConvertibleMimeType indica se o tipo é convertido para PDF na ingestão
+func IsConvertibleMimeType(mimeType string) bool {
+	for _, convertible := range ConvertibleMimeTypes {
+		if mimeType == convertible {
+			return true
+		}
+	}
+	return false
+}
+
+// MayBeConvertible indica se um tipo detectado em uma amostra parcial pode ser um documento conversível
+// DOCX e ODT só são identificados com o ZIP completo; numa amostra aparecem como application/zip
+func MayBeConvertible(mimeType string) bool {
+	return mimeType == "application/zip" || strings.HasPrefix(mimeType, MimeTypeHTML) || IsConvertibleMimeType(mimeType)
+}
+
+// detectOpenDocument identifica DOCX pelo word/document.xml e ODT pelo arquivo mimetype do pacote
+func detectOpenDocument(data []byte, fallback string) string {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fallback
+	}
+
+	for _, file := range reader.File {
+		switch file.Name {
+		case "word/document.xml":
+			return MimeTypeDOCX
+		case "mimetype":
+			content, err := file.Open()
+			if err != nil {
+				continue
+			}
+			declared, _ := io.ReadAll(io.LimitReader(content, 128))
+			content.Close()
+			if strings.TrimSpace(string(declared)) == MimeTypeODT {
+				return MimeTypeODT
+			}
+		}
+	}
+
+	return fallback
+}
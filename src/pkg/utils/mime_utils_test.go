@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildZip(t *testing.T, files map[string]string, order ...string) []byte {
+	buffer := &bytes.Buffer{}
+	writer := zip.NewWriter(buffer)
+	for _, name := range order {
+		file, err := writer.Create(name)
+		require.NoError(t, err)
+		_, err = file.Write([]byte(files[name]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return buffer.Bytes()
+}
+
+func TestDetectMimeType(t *testing.T) {
+	docx := buildZip(t, map[string]string{
+		"[Content_Types].xml": "<Types/>",
+		"word/document.xml":   "<w:document/>",
+	}, "[Content_Types].xml", "word/document.xml")
+
+	odt := buildZip(t, map[string]string{
+		"mimetype":    MimeTypeODT,
+		"content.xml": "<office:document-content/>",
+	}, "mimetype", "content.xml")
+
+	plainZip := buildZip(t, map[string]string{"dados.csv": "a,b"}, "dados.csv")
+
+	tests := []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{name: "PDF", data: []byte("%PDF-1.4\n"), expected: "application/pdf"},
+		{name: "DOCX", data: docx, expected: MimeTypeDOCX},
+		{name: "ODT", data: odt, expected: MimeTypeODT},
+		{name: "HTML without charset", data: []byte("<!DOCTYPE html><html><body>Contrato</body></html>"), expected: MimeTypeHTML},
+		{name: "Plain ZIP", data: plainZip, expected: "application/zip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DetectMimeType(tt.data))
+		})
+	}
+}
+
+func TestValidateMimeType_Convertible(t *testing.T) {
+	assert.NoError(t, ValidateMimeType(MimeTypeDOCX))
+	assert.NoError(t, ValidateMimeType(MimeTypeODT))
+	assert.NoError(t, ValidateMimeType(MimeTypeHTML))
+	assert.Error(t, ValidateMimeType("application/zip"))
+
+	assert.True(t, MayBeConvertible("application/zip"))
+	assert.True(t, MayBeConvertible("text/html; charset=utf-8"))
+	assert.False(t, MayBeConvertible("application/pdf"))
+}
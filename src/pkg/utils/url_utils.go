@@ -1,12 +1,13 @@
 package utils
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"time"
+
+	"app/pkg/fetcher"
 )
 
 const (
@@ -16,47 +17,26 @@ const (
 
 // DownloadFileFromURL baixa um arquivo de uma URL e retorna informações do arquivo
 // Retorna o mesmo formato de Base64FileInfo para compatibilidade
-func DownloadFileFromURL(url string) (*Base64FileInfo, error) {
+// O download passa por documentFetcher, que bloqueia endereços internos; falhas de política retornam *fetcher.Error
+func DownloadFileFromURL(documentFetcher *fetcher.Fetcher, url string) (*Base64FileInfo, error) {
 	if url == "" {
 		return nil, errors.New("URL não pode estar vazia")
 	}
 
-	// Criar cliente HTTP com timeout
-	client := &http.Client{
-		Timeout: MaxURLDownloadTimeout,
-	}
-
-	// Fazer requisição GET
-	resp, err := client.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao fazer download da URL: %v", err)
-	}
-	defer resp.Body.Close()
-
-	// Verificar status code
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("erro ao baixar arquivo: status code %d", resp.StatusCode)
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), MaxURLDownloadTimeout)
+	defer cancel()
 
-	// Ler conteúdo do arquivo
-	fileData, err := io.ReadAll(resp.Body)
+	// DOCX e ODT aparecem como application/zip na amostra; o tipo final é confirmado com o conteúdo completo
+	allowedMimeTypes := append([]string{"application/zip", MimeTypeHTML}, SupportedMimeTypes...)
+	result, err := documentFetcher.
+		WithMaxSize(MaxFileSize).
+		WithAllowedMimeTypes(allowedMimeTypes...).
+		Fetch(ctx, url)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao ler conteúdo do arquivo: %v", err)
+		return nil, fmt.Errorf("erro ao fazer download da URL: %w", err)
 	}
 
-	// Verificar tamanho após download
-	if len(fileData) > MaxFileSize {
-		return nil, fmt.Errorf("tamanho do arquivo após download excede o limite de %.1f MB", MaxFileSize/(1024*1024))
-	}
-
-	// Detectar MIME type usando amostra adequada
-	sampleSize := len(fileData)
-	if sampleSize > 512 {
-		sampleSize = 512
-	}
-	mimeType := http.DetectContentType(fileData[:sampleSize])
-
-	// Validar MIME type
+	mimeType := DetectMimeType(result.Data)
 	if err := ValidateMimeType(mimeType); err != nil {
 		return nil, fmt.Errorf("tipo de arquivo não suportado: %v", err)
 	}
@@ -68,7 +48,7 @@ func DownloadFileFromURL(url string) (*Base64FileInfo, error) {
 	}
 
 	// Escrever dados no arquivo temporário
-	if _, err := tempFile.Write(fileData); err != nil {
+	if _, err := tempFile.Write(result.Data); err != nil {
 		tempFile.Close()
 		os.Remove(tempFile.Name())
 		return nil, fmt.Errorf("erro ao escrever arquivo temporário: %v", err)
@@ -80,12 +60,9 @@ func DownloadFileFromURL(url string) (*Base64FileInfo, error) {
 	}
 
 	return &Base64FileInfo{
-		DecodedData: fileData,
+		DecodedData: result.Data,
 		MimeType:    mimeType,
-		Size:        int64(len(fileData)),
+		Size:        result.Size,
 		TempPath:    tempFile.Name(),
 	}, nil
 }
-
-
-
@@ -0,0 +1,30 @@
+package usecase_api_client
+
+import (
+	"app/entity"
+	"time"
+)
+
+//go:generate mockgen -destination=../../mocks/mock_usecase_repository_api_client.go -package=mocks app/usecase/api_client IRepositoryAPIClient
+type IRepositoryAPIClient interface {
+	CreateClient(client *entity.EntityAPIClient) error
+	UpdateClient(client *entity.EntityAPIClient) error
+	GetClientByID(id int) (*entity.EntityAPIClient, error)
+	GetClients() ([]entity.EntityAPIClient, error)
+	CreateKey(key *entity.EntityAPIKey) error
+	UpdateKey(key *entity.EntityAPIKey) error
+	GetKeyByPrefix(prefix string) (*entity.EntityAPIKey, error)
+	GetKeysByClientID(clientID int) ([]entity.EntityAPIKey, error)
+	TouchKey(id int, at time.Time, ip string) error
+}
+
+//go:generate mockgen -destination=../../mocks/mock_usecase_api_client.go -package=mocks app/usecase/api_client IUsecaseAPIClient
+type IUsecaseAPIClient interface {
+	CreateClient(client *entity.EntityAPIClient) (*entity.EntityAPIClient, string, error)
+	GetClient(id int) (*entity.EntityAPIClient, error)
+	GetClients() ([]entity.EntityAPIClient, error)
+	UpdateClient(client *entity.EntityAPIClient) error
+	RotateKey(clientID int, overlap time.Duration) (*entity.EntityAPIKey, string, error)
+	RevokeKey(clientID, keyID int) error
+	Authenticate(rawKey, ip string) (*entity.EntityAPIClient, error)
+}
@@ -0,0 +1,180 @@
+package usecase_api_client
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"app/entity"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// lastUsedResolution evita uma escrita no banco a cada request: o último uso é gravado no máximo uma vez por minuto
+const lastUsedResolution = time.Minute
+
+type UsecaseAPIClientService struct {
+	repository IRepositoryAPIClient
+	logger     *logrus.Logger
+	now        func() time.Time
+}
+
+func NewUsecaseAPIClientService(repository IRepositoryAPIClient, logger *logrus.Logger) *UsecaseAPIClientService {
+	return &UsecaseAPIClientService{
+		repository: repository,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// CreateClient cria o cliente com a primeira chave; o valor da chave só é retornado aqui
+func (u *UsecaseAPIClientService) CreateClient(client *entity.EntityAPIClient) (*entity.EntityAPIClient, string, error) {
+	key, rawKey, err := entity.NewAPIKey(0)
+	if err != nil {
+		return nil, "", err
+	}
+	client.Keys = []entity.EntityAPIKey{*key}
+
+	if err := u.repository.CreateClient(client); err != nil {
+		return nil, "", fmt.Errorf("failed to create api client: %w", err)
+	}
+
+	u.logger.WithFields(logrus.Fields{
+		"api_client_id": client.ID,
+		"scopes":        client.Scopes,
+	}).Info("API client created")
+
+	return client, rawKey, nil
+}
+
+func (u *UsecaseAPIClientService) GetClient(id int) (*entity.EntityAPIClient, error) {
+	return u.repository.GetClientByID(id)
+}
+
+func (u *UsecaseAPIClientService) GetClients() ([]entity.EntityAPIClient, error) {
+	return u.repository.GetClients()
+}
+
+func (u *UsecaseAPIClientService) UpdateClient(client *entity.EntityAPIClient) error {
+	if err := client.Validate(); err != nil {
+		return err
+	}
+
+	client.UpdatedAt = u.now()
+	if err := u.repository.UpdateClient(client); err != nil {
+		return fmt.Errorf("failed to update api client: %w", err)
+	}
+	return nil
+}
+
+// RotateKey emite uma nova chave e faz as chaves atuais expirarem após overlap,
+// dando tempo para os sistemas trocarem a chave sem indisponibilidade
+func (u *UsecaseAPIClientService) RotateKey(clientID int, overlap time.Duration) (*entity.EntityAPIKey, string, error) {
+	if _, err := u.repository.GetClientByID(clientID); err != nil {
+		return nil, "", err
+	}
+
+	keys, err := u.repository.GetKeysByClientID(clientID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get api keys: %w", err)
+	}
+
+	key, rawKey, err := entity.NewAPIKey(clientID)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := u.repository.CreateKey(key); err != nil {
+		return nil, "", fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	now := u.now()
+	for i := range keys {
+		if keys[i].CheckUsable(now) != nil {
+			continue
+		}
+		keys[i].ExpireAt(now.Add(overlap))
+		if err := u.repository.UpdateKey(&keys[i]); err != nil {
+			return nil, "", fmt.Errorf("failed to expire api key %d: %w", keys[i].ID, err)
+		}
+	}
+
+	u.logger.WithFields(logrus.Fields{
+		"api_client_id": clientID,
+		"api_key_id":    key.ID,
+		"overlap":       overlap.String(),
+	}).Info("API key rotated")
+
+	return key, rawKey, nil
+}
+
+// RevokeKey carrega o cliente antes das chaves: as chaves não têm tenant e só a busca do cliente
+// garante que ele pertence ao tenant da requisição
+func (u *UsecaseAPIClientService) RevokeKey(clientID, keyID int) error {
+	if _, err := u.repository.GetClientByID(clientID); err != nil {
+		return err
+	}
+
+	keys, err := u.repository.GetKeysByClientID(clientID)
+	if err != nil {
+		return fmt.Errorf("failed to get api keys: %w", err)
+	}
+
+	for i := range keys {
+		if keys[i].ID != keyID {
+			continue
+		}
+		keys[i].Revoke()
+		if err := u.repository.UpdateKey(&keys[i]); err != nil {
+			return fmt.Errorf("failed to revoke api key: %w", err)
+		}
+
+		u.logger.WithFields(logrus.Fields{
+			"api_client_id": clientID,
+			"api_key_id":    keyID,
+		}).Info("API key revoked")
+		return nil
+	}
+
+	return gorm.ErrRecordNotFound
+}
+
+// Authenticate valida a chave e retorna o cliente dono dela, registrando o último uso
+func (u *UsecaseAPIClientService) Authenticate(rawKey, ip string) (*entity.EntityAPIClient, error) {
+	prefix, err := entity.ParseAPIKeyPrefix(rawKey)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := u.repository.GetKeyByPrefix(prefix)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrInvalidAPIKey
+		}
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+	if !key.Matches(rawKey) {
+		return nil, entity.ErrInvalidAPIKey
+	}
+
+	now := u.now()
+	if err := key.CheckUsable(now); err != nil {
+		return nil, err
+	}
+
+	client, err := u.repository.GetClientByID(key.ClientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api client: %w", err)
+	}
+	if !client.Active {
+		return nil, entity.ErrAPIClientInactive
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		if err := u.repository.TouchKey(key.ID, now, ip); err != nil {
+			u.logger.WithError(err).WithField("api_key_id", key.ID).Warn("Failed to record API key usage")
+		}
+	}
+
+	return client, nil
+}
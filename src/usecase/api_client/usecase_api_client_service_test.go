@@ -0,0 +1,164 @@
+package usecase_api_client
+
+import (
+	"testing"
+	"time"
+
+	"app/entity"
+	"app/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var apiClientNow = time.Date(2026, 6, 30, 12, 0, 0, 0, time.UTC)
+
+func newTestAPIClientService(repo IRepositoryAPIClient) *UsecaseAPIClientService {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	service := NewUsecaseAPIClientService(repo, logger)
+	service.now = func() time.Time { return apiClientNow }
+	return service
+}
+
+func issueTestKey(t *testing.T, clientID int) (*entity.EntityAPIKey, string) {
+	key, rawKey, err := entity.NewAPIKey(clientID)
+	require.NoError(t, err)
+	key.ID = 10
+	return key, rawKey
+}
+
+func TestUsecaseAPIClientService_CreateClient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockIRepositoryAPIClient(ctrl)
+	service := newTestAPIClientService(repo)
+
+	client, err := entity.NewAPIClient(entity.EntityAPIClient{Name: "erp-financeiro", Scopes: []string{entity.ScopeEnvelopesRead}})
+	require.NoError(t, err)
+	repo.EXPECT().CreateClient(client).Return(nil)
+
+	created, rawKey, err := service.CreateClient(client)
+
+	require.NoError(t, err)
+	require.Len(t, created.Keys, 1)
+	assert.True(t, created.Keys[0].Matches(rawKey))
+}
+
+func TestUsecaseAPIClientService_Authenticate(t *testing.T) {
+	t.Run("should return the client and record the usage", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockIRepositoryAPIClient(ctrl)
+		service := newTestAPIClientService(repo)
+		key, rawKey := issueTestKey(t, 3)
+
+		repo.EXPECT().GetKeyByPrefix(key.Prefix).Return(key, nil)
+		repo.EXPECT().GetClientByID(3).Return(&entity.EntityAPIClient{ID: 3, Active: true}, nil)
+		repo.EXPECT().TouchKey(10, apiClientNow, "10.0.0.1").Return(nil)
+
+		client, err := service.Authenticate(rawKey, "10.0.0.1")
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, client.ID)
+	})
+
+	t.Run("should not record the usage again within a minute", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockIRepositoryAPIClient(ctrl)
+		service := newTestAPIClientService(repo)
+		key, rawKey := issueTestKey(t, 3)
+		lastUsed := apiClientNow.Add(-30 * time.Second)
+		key.LastUsedAt = &lastUsed
+
+		repo.EXPECT().GetKeyByPrefix(key.Prefix).Return(key, nil)
+		repo.EXPECT().GetClientByID(3).Return(&entity.EntityAPIClient{ID: 3, Active: true}, nil)
+
+		_, err := service.Authenticate(rawKey, "10.0.0.1")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("should reject unknown, wrong, expired and inactive keys", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockIRepositoryAPIClient(ctrl)
+		service := newTestAPIClientService(repo)
+		key, rawKey := issueTestKey(t, 3)
+
+		repo.EXPECT().GetKeyByPrefix("000000000000").Return(nil, gorm.ErrRecordNotFound)
+		_, err := service.Authenticate("dsk_000000000000_secret", "")
+		assert.ErrorIs(t, err, entity.ErrInvalidAPIKey)
+
+		repo.EXPECT().GetKeyByPrefix(key.Prefix).Return(key, nil)
+		_, err = service.Authenticate("dsk_"+key.Prefix+"_wrong", "")
+		assert.ErrorIs(t, err, entity.ErrInvalidAPIKey)
+
+		expired := *key
+		expired.ExpireAt(apiClientNow)
+		repo.EXPECT().GetKeyByPrefix(key.Prefix).Return(&expired, nil)
+		_, err = service.Authenticate(rawKey, "")
+		assert.ErrorIs(t, err, entity.ErrAPIKeyExpired)
+
+		repo.EXPECT().GetKeyByPrefix(key.Prefix).Return(key, nil)
+		repo.EXPECT().GetClientByID(3).Return(&entity.EntityAPIClient{ID: 3, Active: false}, nil)
+		_, err = service.Authenticate(rawKey, "")
+		assert.ErrorIs(t, err, entity.ErrAPIClientInactive)
+	})
+}
+
+func TestUsecaseAPIClientService_RotateKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockIRepositoryAPIClient(ctrl)
+	service := newTestAPIClientService(repo)
+
+	revokedAt := apiClientNow.Add(-time.Hour)
+	current := entity.EntityAPIKey{ID: 1, ClientID: 3}
+	revoked := entity.EntityAPIKey{ID: 2, ClientID: 3, RevokedAt: &revokedAt}
+
+	repo.EXPECT().GetClientByID(3).Return(&entity.EntityAPIClient{ID: 3, Active: true}, nil)
+	repo.EXPECT().GetKeysByClientID(3).Return([]entity.EntityAPIKey{current, revoked}, nil)
+	repo.EXPECT().CreateKey(gomock.Any()).Return(nil)
+	repo.EXPECT().UpdateKey(gomock.Any()).DoAndReturn(func(key *entity.EntityAPIKey) error {
+		assert.Equal(t, 1, key.ID)
+		assert.Equal(t, apiClientNow.Add(24*time.Hour), *key.ExpiresAt)
+		return nil
+	})
+
+	key, rawKey, err := service.RotateKey(3, 24*time.Hour)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, key.ClientID)
+	assert.True(t, key.Matches(rawKey))
+}
+
+func TestUsecaseAPIClientService_RevokeKey(t *testing.T) {
+	t.Run("should revoke the client key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockIRepositoryAPIClient(ctrl)
+		service := newTestAPIClientService(repo)
+
+		repo.EXPECT().GetClientByID(3).Return(&entity.EntityAPIClient{ID: 3, Active: true}, nil).Times(2)
+		repo.EXPECT().GetKeysByClientID(3).Return([]entity.EntityAPIKey{{ID: 1, ClientID: 3}}, nil).Times(2)
+		repo.EXPECT().UpdateKey(gomock.Any()).DoAndReturn(func(key *entity.EntityAPIKey) error {
+			assert.NotNil(t, key.RevokedAt)
+			return nil
+		})
+
+		assert.NoError(t, service.RevokeKey(3, 1))
+		assert.ErrorIs(t, service.RevokeKey(3, 99), gorm.ErrRecordNotFound)
+	})
+
+	t.Run("should not revoke keys of a client outside the tenant", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockIRepositoryAPIClient(ctrl)
+		service := newTestAPIClientService(repo)
+
+		repo.EXPECT().GetClientByID(3).Return(nil, gorm.ErrRecordNotFound)
+		repo.EXPECT().GetKeysByClientID(gomock.Any()).Times(0)
+		repo.EXPECT().UpdateKey(gomock.Any()).Times(0)
+
+		assert.ErrorIs(t, service.RevokeKey(3, 1), gorm.ErrRecordNotFound)
+	})
+}
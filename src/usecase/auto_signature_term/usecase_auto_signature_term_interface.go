@@ -13,6 +13,7 @@ type IRepositoryAutoSignatureTerm interface {
 	Update(term *entity.EntityAutoSignatureTerm) error
 	Delete(term *entity.EntityAutoSignatureTerm) error
 	GetAll() ([]entity.EntityAutoSignatureTerm, error)
+	GetBySignerDocumentation(documentation string) ([]entity.EntityAutoSignatureTerm, error)
 }
 
 //go:generate mockgen -destination=../../mocks/mock_usecase_auto_signature_term.go -package=mocks app/usecase/auto_signature_term IUsecaseAutoSignatureTerm
@@ -21,6 +22,7 @@ type IUsecaseAutoSignatureTerm interface {
 	GetAutoSignatureTerm(id int) (*entity.EntityAutoSignatureTerm, error)
 	GetAutoSignatureTermByClicksignKey(key string) (*entity.EntityAutoSignatureTerm, error)
 	GetAllAutoSignatureTerms() ([]entity.EntityAutoSignatureTerm, error)
+	GetAutoSignatureTermsBySignerDocumentation(documentation string) ([]entity.EntityAutoSignatureTerm, error)
 	UpdateAutoSignatureTerm(term *entity.EntityAutoSignatureTerm) error
 	DeleteAutoSignatureTerm(id int) error
 }
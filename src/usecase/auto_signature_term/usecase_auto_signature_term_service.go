@@ -101,6 +101,16 @@ func (u *UsecaseAutoSignatureTermService) GetAllAutoSignatureTerms() ([]entity.E
 	return terms, nil
 }
 
+// GetAutoSignatureTermsBySignerDocumentation busca os termos pelo documento do signatário via índice cego
+func (u *UsecaseAutoSignatureTermService) GetAutoSignatureTermsBySignerDocumentation(documentation string) ([]entity.EntityAutoSignatureTerm, error) {
+	terms, err := u.repository.GetBySignerDocumentation(documentation)
+	if err != nil {
+		u.logger.WithError(err).Error("Failed to get auto signature terms by signer documentation")
+		return nil, fmt.Errorf("failed to get auto signature terms by signer documentation: %w", err)
+	}
+	return terms, nil
+}
+
 func (u *UsecaseAutoSignatureTermService) UpdateAutoSignatureTerm(term *entity.EntityAutoSignatureTerm) error {
 	err := u.repository.Update(term)
 	if err != nil {
@@ -0,0 +1,500 @@
+package document
+
+import (
+	"app/entity"
+	"app/infrastructure/converter"
+	"app/infrastructure/normalizer"
+	"app/infrastructure/scanner"
+	"app/infrastructure/storage"
+	"app/pkg/utils"
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnsupportedUpload = errors.New("unsupported file type")
+	ErrUploadStorage     = errors.New("failed to store uploaded file")
+	ErrConversion        = errors.New("failed to convert document to PDF")
+	ErrNormalization     = errors.New("document rejected")
+	ErrInfected          = errors.New("document is infected")
+	ErrScanFailed        = errors.New("malware scan failed")
+	ErrNotConfigured     = errors.New("document ingestion is not configured")
+)
+
+// MergedParts é o PDF montado a partir das partes de um documento, com as partes de origem e a verificação
+// combinada delas
+type MergedParts struct {
+	Sources []normalizer.Part
+	Result  *normalizer.Result
+	Scan    *entity.DocumentScan
+}
+
+// UsecaseDocumentIngestionService verifica, converte, normaliza e grava no storage o conteúdo dos documentos recebidos
+type UsecaseDocumentIngestionService struct {
+	storage    storage.Storage
+	converter  converter.Converter
+	normalizer *normalizer.Normalizer
+	scanner    scanner.Scanner
+}
+
+func NewUsecaseDocumentIngestionService(
+	documentStorage storage.Storage,
+	documentConverter converter.Converter,
+	documentNormalizer *normalizer.Normalizer,
+	documentScanner scanner.Scanner,
+) *UsecaseDocumentIngestionService {
+	return &UsecaseDocumentIngestionService{
+		storage:    documentStorage,
+		converter:  documentConverter,
+		normalizer: documentNormalizer,
+		scanner:    documentScanner,
+	}
+}
+
+// StoreOriginal grava o original no storage de documentos e descarta o arquivo temporário
+// Documentos de base64 passam a referenciar o storage no FilePath; os de URL mantêm a URL,
+// usada diretamente pelo vert-sign, e guardam apenas a chave
+func (u *UsecaseDocumentIngestionService) StoreOriginal(ctx context.Context, document *entity.EntityDocument, fileInfo *utils.Base64FileInfo) error {
+	defer utils.CleanupTempFile(fileInfo.TempPath)
+
+	if err := u.checkConfigured(); err != nil {
+		return err
+	}
+
+	scan, err := u.scanContent(ctx, bytes.NewReader(fileInfo.DecodedData))
+	if err != nil {
+		return err
+	}
+	if err := recordScan(document, scan); err != nil {
+		return err
+	}
+
+	if utils.IsConvertibleMimeType(fileInfo.MimeType) {
+		return u.storeConvertedDocument(ctx, document, fileInfo.DecodedData, fileInfo.MimeType)
+	}
+
+	sources := []normalizer.Part{{Data: fileInfo.DecodedData, MimeType: fileInfo.MimeType}}
+	if u.needsNormalization(fileInfo.MimeType) {
+		result, err := u.normalizer.Normalize(ctx, sources)
+		if err != nil {
+			return normalizationError(err)
+		}
+		if result.Changed {
+			return u.storeNormalizedDocument(ctx, document, sources, result)
+		}
+	}
+
+	key, sum, err := storage.StoreContent(ctx, u.storage, storage.KindOriginal, fileInfo.DecodedData, fileInfo.MimeType)
+	if err != nil {
+		return err
+	}
+	document.SetContentHash(sum, int64(len(fileInfo.DecodedData)))
+
+	if document.IsFromBase64 {
+		document.SetStorageKey(key)
+	} else {
+		document.StorageKey = key
+	}
+	return nil
+}
+
+// MergeParts verifica cada parte contra malware e as junta, na ordem, em um único PDF
+func (u *UsecaseDocumentIngestionService) MergeParts(ctx context.Context, parts []normalizer.Part) (*MergedParts, error) {
+	if err := u.checkConfigured(); err != nil {
+		return nil, err
+	}
+
+	var scan *entity.DocumentScan
+	for i, part := range parts {
+		partScan, err := u.scanContent(ctx, bytes.NewReader(part.Data))
+		if err != nil {
+			return nil, fmt.Errorf("part %d: %w", i+1, err)
+		}
+		scan = combineScan(scan, i+1, partScan)
+	}
+
+	result, err := u.normalizer.Normalize(ctx, parts)
+	if err != nil {
+		return nil, normalizationError(err)
+	}
+	return &MergedParts{Sources: parts, Result: result, Scan: scan}, nil
+}
+
+// StoreMergedParts grava as partes e o PDF montado por MergeParts; o documento passa a referenciar o PDF
+func (u *UsecaseDocumentIngestionService) StoreMergedParts(ctx context.Context, document *entity.EntityDocument, merged *MergedParts) error {
+	if err := u.checkConfigured(); err != nil {
+		return err
+	}
+	if err := recordScan(document, merged.Scan); err != nil {
+		return err
+	}
+	return u.storeNormalizedDocument(ctx, document, merged.Sources, merged.Result)
+}
+
+// StoreUpload grava o arquivo enviado e o verifica durante a gravação, lendo o mesmo fluxo por um pipe
+// Um upload infectado fica no storage e o documento em quarentena
+func (u *UsecaseDocumentIngestionService) StoreUpload(ctx context.Context, document *entity.EntityDocument, file io.Reader, maxSize int64) error {
+	if err := u.checkConfigured(); err != nil {
+		return err
+	}
+	if scanner.IsDisabled(u.scanner) {
+		return u.storeUploadedContent(ctx, document, file, maxSize)
+	}
+
+	reader, writer := io.Pipe()
+	type scanOutcome struct {
+		scan *entity.DocumentScan
+		err  error
+	}
+	scanned := make(chan scanOutcome, 1)
+	go func() {
+		scan, err := u.scanContent(ctx, reader)
+		// Sem leitor, a gravação falha em vez de ficar bloqueada no pipe
+		reader.CloseWithError(io.ErrClosedPipe)
+		scanned <- scanOutcome{scan: scan, err: err}
+	}()
+
+	err := u.storeUploadedContent(ctx, document, io.TeeReader(file, writer), maxSize)
+	writer.CloseWithError(err)
+	outcome := <-scanned
+
+	if errors.Is(outcome.err, ErrInfected) {
+		if recordErr := recordScan(document, outcome.scan); recordErr != nil {
+			return recordErr
+		}
+		return outcome.err
+	}
+	if err != nil {
+		return err
+	}
+	if outcome.err != nil {
+		return outcome.err
+	}
+	return recordScan(document, outcome.scan)
+}
+
+// checkConfigured recusa a ingestão quando falta alguma dependência, em vez de gravar conteúdo sem verificação
+// Sem scanner a resposta é ErrScanFailed; a verificação só é pulada com o DisabledScanner configurado explicitamente
+func (u *UsecaseDocumentIngestionService) checkConfigured() error {
+	switch {
+	case u.scanner == nil:
+		return fmt.Errorf("%w: scanner not configured", ErrScanFailed)
+	case u.storage == nil:
+		return fmt.Errorf("%w: storage not configured", ErrNotConfigured)
+	case u.converter == nil:
+		return fmt.Errorf("%w: converter not configured", ErrNotConfigured)
+	case u.normalizer == nil:
+		return fmt.Errorf("%w: normalizer not configured", ErrNotConfigured)
+	}
+	return nil
+}
+
+// storeConvertedDocument guarda o arquivo enviado e o PDF convertido; o documento passa a referenciar o PDF,
+// que é o conteúdo enviado ao provider, e o arquivo de origem fica registrado no metadata
+func (u *UsecaseDocumentIngestionService) storeConvertedDocument(ctx context.Context, document *entity.EntityDocument, data []byte, sourceMimeType string) error {
+	sourceKey, sourceSum, err := storage.StoreContent(ctx, u.storage, storage.KindOriginal, data, sourceMimeType)
+	if err != nil {
+		return err
+	}
+
+	pdf, err := u.converter.ConvertToPDF(ctx, data, sourceMimeType)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrConversion, err)
+	}
+	if mimeType := http.DetectContentType(pdf); mimeType != "application/pdf" {
+		return fmt.Errorf("%w: converter returned %s", ErrConversion, mimeType)
+	}
+
+	key, sum, err := storage.StoreContent(ctx, u.storage, storage.KindOriginal, pdf, "application/pdf")
+	if err != nil {
+		return err
+	}
+
+	document.MimeType = "application/pdf"
+	document.SetStorageKey(key)
+	document.SetContentHash(sum, int64(len(pdf)))
+	return document.SetConversion(entity.DocumentConversion{
+		SourceMimeType:   sourceMimeType,
+		SourceStorageKey: sourceKey,
+		SourceSHA256:     sourceSum,
+		SourceSize:       int64(len(data)),
+		Converter:        u.converter.Name(),
+		ConvertedAt:      time.Now(),
+	})
+}
+
+// scanContent verifica r com o scanner configurado; nil quando a verificação foi desabilitada
+// Conteúdo infectado é retornado como ErrInfected, com o nome da ameaça
+func (u *UsecaseDocumentIngestionService) scanContent(ctx context.Context, r io.Reader) (*entity.DocumentScan, error) {
+	if scanner.IsDisabled(u.scanner) {
+		return nil, nil
+	}
+
+	result, err := u.scanner.Scan(ctx, r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrScanFailed, err)
+	}
+
+	scan := &entity.DocumentScan{
+		Scanner:   u.scanner.Name(),
+		Infected:  result.Infected,
+		Threat:    result.Threat,
+		ScannedAt: time.Now(),
+	}
+	if scan.Infected {
+		return scan, fmt.Errorf("%w: %s", ErrInfected, scan.Threat)
+	}
+	return scan, nil
+}
+
+// combineScan acrescenta o resultado da parte index à verificação do documento
+// O documento está infectado se alguma parte estiver; sem verificação (scanner desabilitado) retorna scan
+func combineScan(scan *entity.DocumentScan, index int, partScan *entity.DocumentScan) *entity.DocumentScan {
+	if partScan == nil {
+		return scan
+	}
+	if scan == nil {
+		scan = &entity.DocumentScan{Scanner: partScan.Scanner}
+	}
+	scan.Parts = append(scan.Parts, entity.DocumentScanPart{
+		Index:    index,
+		Infected: partScan.Infected,
+		Threat:   partScan.Threat,
+	})
+	if partScan.Infected && !scan.Infected {
+		scan.Infected = true
+		scan.Threat = partScan.Threat
+	}
+	scan.ScannedAt = partScan.ScannedAt
+	return scan
+}
+
+func recordScan(document *entity.EntityDocument, scan *entity.DocumentScan) error {
+	if scan == nil {
+		return nil
+	}
+	return document.SetScan(*scan)
+}
+
+// needsNormalization indica se o tipo passa pelo normalizador na ingestão
+// PDFs são sempre validados; imagens isoladas só viram PDF com DOCUMENT_IMAGES_TO_PDF
+func (u *UsecaseDocumentIngestionService) needsNormalization(mimeType string) bool {
+	if mimeType == "application/pdf" {
+		return true
+	}
+	return strings.HasPrefix(mimeType, "image/") && u.normalizer.Options().ImagesToPDF
+}
+
+// normalizationError marca as recusas do normalizador, que são erros do arquivo enviado
+func normalizationError(err error) error {
+	for _, target := range []error{normalizer.ErrEncryptedPDF, normalizer.ErrCorruptPDF, normalizer.ErrUnsupported, normalizer.ErrUnsupportedType, normalizer.ErrImageTooLarge} {
+		if errors.Is(err, target) {
+			return fmt.Errorf("%w: %v", ErrNormalization, err)
+		}
+	}
+	return err
+}
+
+// storeNormalizedDocument guarda os arquivos de origem e o PDF normalizado; o documento passa a referenciar o PDF
+// e as origens ficam registradas no metadata
+func (u *UsecaseDocumentIngestionService) storeNormalizedDocument(ctx context.Context, document *entity.EntityDocument, sources []normalizer.Part, result *normalizer.Result) error {
+	normalization := entity.DocumentNormalization{
+		Steps:        result.Steps,
+		Normalizer:   result.Normalizer,
+		NormalizedAt: time.Now(),
+	}
+	for _, source := range sources {
+		key, sum, err := storage.StoreContent(ctx, u.storage, storage.KindOriginal, source.Data, source.MimeType)
+		if err != nil {
+			return err
+		}
+		normalization.Sources = append(normalization.Sources, entity.DocumentSource{
+			MimeType:   source.MimeType,
+			StorageKey: key,
+			SHA256:     sum,
+			Size:       int64(len(source.Data)),
+		})
+	}
+
+	key, sum, err := storage.StoreContent(ctx, u.storage, storage.KindOriginal, result.Data, "application/pdf")
+	if err != nil {
+		return err
+	}
+
+	document.MimeType = "application/pdf"
+	document.SetStorageKey(key)
+	document.SetContentHash(sum, int64(len(result.Data)))
+	return document.SetNormalization(normalization)
+}
+
+// storeUploadedContent detecta o MIME type pelos primeiros bytes e grava o arquivo no storage em streaming
+// Formatos conversíveis são lidos por inteiro, pois a conversão precisa do arquivo completo
+func (u *UsecaseDocumentIngestionService) storeUploadedContent(ctx context.Context, document *entity.EntityDocument, file io.Reader, maxSize int64) error {
+	buffered := bufio.NewReaderSize(file, 512)
+	head, err := buffered.Peek(512)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return err
+	}
+
+	mimeType := http.DetectContentType(head)
+	if utils.MayBeConvertible(mimeType) {
+		return u.storeUploadedForConversion(ctx, document, buffered, maxSize)
+	}
+	if err := utils.ValidateMimeType(mimeType); err != nil {
+		return fmt.Errorf("%w: %s", ErrUnsupportedUpload, mimeType)
+	}
+	if mimeType == "application/pdf" {
+		return u.storeUploadedPDF(ctx, document, buffered, maxSize)
+	}
+	if u.needsNormalization(mimeType) {
+		return u.storeUploadedForNormalization(ctx, document, buffered, mimeType, maxSize)
+	}
+
+	object, err := u.storeUploadStream(ctx, buffered, mimeType, maxSize)
+	if err != nil {
+		return err
+	}
+
+	document.MimeType = mimeType
+	document.SetStorageKey(object.Key)
+	document.SetContentHash(object.SHA256, object.Size)
+	return nil
+}
+
+// storeUploadStream grava o arquivo no storage, separando limite de tamanho de falhas do storage
+func (u *UsecaseDocumentIngestionService) storeUploadStream(ctx context.Context, file io.Reader, mimeType string, maxSize int64) (*storage.Object, error) {
+	object, err := storage.StoreStream(ctx, u.storage, storage.KindOriginal, file, mimeType, maxSize)
+	if err != nil {
+		if errors.Is(err, storage.ErrTooLarge) {
+			return nil, err
+		}
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", ErrUploadStorage, err)
+	}
+	return object, nil
+}
+
+// storeUploadedPDF grava o PDF em um arquivo temporário para validá-lo antes do storage, sem carregá-lo em memória
+func (u *UsecaseDocumentIngestionService) storeUploadedPDF(ctx context.Context, document *entity.EntityDocument, file io.Reader, maxSize int64) error {
+	temp, err := os.CreateTemp("", "docsigner_upload_*.pdf")
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUploadStorage, err)
+	}
+	defer os.Remove(temp.Name())
+	defer temp.Close()
+
+	written, err := io.Copy(temp, io.LimitReader(file, maxSize+1))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return err
+		}
+		return fmt.Errorf("%w: %v", ErrUploadStorage, err)
+	}
+	if written > maxSize {
+		return fmt.Errorf("%w: limit is %d bytes", storage.ErrTooLarge, maxSize)
+	}
+
+	result, err := u.normalizer.NormalizeFile(ctx, temp.Name())
+	if err != nil {
+		return normalizationError(err)
+	}
+
+	source, err := u.storeUploadFile(ctx, temp.Name(), maxSize)
+	if err != nil {
+		return err
+	}
+	if !result.Changed {
+		document.MimeType = "application/pdf"
+		document.SetStorageKey(source.Key)
+		document.SetContentHash(source.SHA256, source.Size)
+		return nil
+	}
+	defer os.Remove(result.Path)
+
+	normalized, err := u.storeUploadFile(ctx, result.Path, maxSize)
+	if err != nil {
+		return err
+	}
+
+	document.MimeType = "application/pdf"
+	document.SetStorageKey(normalized.Key)
+	document.SetContentHash(normalized.SHA256, normalized.Size)
+	return document.SetNormalization(entity.DocumentNormalization{
+		Sources: []entity.DocumentSource{{
+			MimeType:   "application/pdf",
+			StorageKey: source.Key,
+			SHA256:     source.SHA256,
+			Size:       source.Size,
+		}},
+		Steps:        result.Steps,
+		Normalizer:   u.normalizer.Name(),
+		NormalizedAt: time.Now(),
+	})
+}
+
+func (u *UsecaseDocumentIngestionService) storeUploadFile(ctx context.Context, path string, maxSize int64) (*storage.Object, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUploadStorage, err)
+	}
+	defer file.Close()
+
+	return u.storeUploadStream(ctx, file, "application/pdf", maxSize)
+}
+
+// storeUploadedForNormalization lê a imagem por inteiro para convertê-la em PDF
+func (u *UsecaseDocumentIngestionService) storeUploadedForNormalization(ctx context.Context, document *entity.EntityDocument, file io.Reader, mimeType string, maxSize int64) error {
+	data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) > maxSize {
+		return fmt.Errorf("%w: limit is %d bytes", storage.ErrTooLarge, maxSize)
+	}
+
+	sources := []normalizer.Part{{Data: data, MimeType: mimeType}}
+	result, err := u.normalizer.Normalize(ctx, sources)
+	if err != nil {
+		return normalizationError(err)
+	}
+
+	if err := u.storeNormalizedDocument(ctx, document, sources, result); err != nil {
+		return fmt.Errorf("%w: %v", ErrUploadStorage, err)
+	}
+	return nil
+}
+
+func (u *UsecaseDocumentIngestionService) storeUploadedForConversion(ctx context.Context, document *entity.EntityDocument, file io.Reader, maxSize int64) error {
+	data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) > maxSize {
+		return fmt.Errorf("%w: limit is %d bytes", storage.ErrTooLarge, maxSize)
+	}
+
+	mimeType := utils.DetectMimeType(data)
+	if !utils.IsConvertibleMimeType(mimeType) {
+		return fmt.Errorf("%w: %s", ErrUnsupportedUpload, mimeType)
+	}
+
+	if err := u.storeConvertedDocument(ctx, document, data, mimeType); err != nil {
+		if errors.Is(err, ErrConversion) {
+			return err
+		}
+		return fmt.Errorf("%w: %v", ErrUploadStorage, err)
+	}
+	return nil
+}
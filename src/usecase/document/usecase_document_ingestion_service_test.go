@@ -0,0 +1,382 @@
+package document
+
+import (
+	"app/entity"
+	"app/infrastructure/converter"
+	"app/infrastructure/normalizer"
+	"app/infrastructure/scanner"
+	"app/infrastructure/storage"
+	"app/pkg/utils"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubConverter devolve um PDF fixo ou o erro configurado
+type stubConverter struct {
+	pdf []byte
+	err error
+}
+
+func (s stubConverter) ConvertToPDF(ctx context.Context, data []byte, mimeType string) ([]byte, error) {
+	return s.pdf, s.err
+}
+
+func (s stubConverter) Name() string {
+	return "stub"
+}
+
+// stubScanner acusa como infectado o conteúdo que contém marker
+type stubScanner struct {
+	marker string
+	err    error
+}
+
+func (s stubScanner) Scan(ctx context.Context, r io.Reader) (*scanner.Result, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	if bytes.Contains(data, []byte(s.marker)) {
+		return &scanner.Result{Infected: true, Threat: "Eicar-Test-Signature"}, nil
+	}
+	return &scanner.Result{}, nil
+}
+
+func (s stubScanner) Name() string {
+	return "stub"
+}
+
+func newTestIngestionService(t *testing.T, documentConverter converter.Converter, documentScanner scanner.Scanner) (*UsecaseDocumentIngestionService, storage.Storage) {
+	documentStorage, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	if documentConverter == nil {
+		documentConverter = converter.DisabledConverter{}
+	}
+	if documentScanner == nil {
+		documentScanner = scanner.DisabledScanner{}
+	}
+	service := NewUsecaseDocumentIngestionService(
+		documentStorage,
+		documentConverter,
+		normalizer.NewNormalizer(normalizer.BasicTool{}, normalizer.Options{}),
+		documentScanner,
+	)
+	return service, documentStorage
+}
+
+// samplePDF monta um PDF com a estrutura mínima aceita pela validação (cabeçalho, startxref e %%EOF)
+func samplePDF(padding int, trailer string) []byte {
+	return []byte("%PDF-1.4\n%" + strings.Repeat("0", padding) + "\ntrailer\n<< /Size 1" + trailer + " >>\nstartxref\n0\n%%EOF\n")
+}
+
+func samplePNG(t *testing.T, width, height int) []byte {
+	var buffer bytes.Buffer
+	require.NoError(t, png.Encode(&buffer, image.NewRGBA(image.Rect(0, 0, width, height))))
+	return buffer.Bytes()
+}
+
+func decodeMetadata(t *testing.T, document *entity.EntityDocument, target interface{}) {
+	require.NoError(t, json.Unmarshal(document.Metadata, target))
+}
+
+func TestUsecaseDocumentIngestionService_StoreOriginal(t *testing.T) {
+	ctx := context.Background()
+	pdf := samplePDF(64, "")
+
+	t.Run("should store base64 documents and reference the storage", func(t *testing.T) {
+		service, documentStorage := newTestIngestionService(t, nil, nil)
+		document := &entity.EntityDocument{IsFromBase64: true}
+
+		err := service.StoreOriginal(ctx, document, &utils.Base64FileInfo{DecodedData: pdf, MimeType: "application/pdf"})
+
+		require.NoError(t, err)
+		assert.Equal(t, storage.URI(document.StorageKey), document.FilePath)
+		assert.Equal(t, int64(len(pdf)), document.FileSize)
+		stored, err := storage.ReadAll(ctx, documentStorage, document.StorageKey)
+		require.NoError(t, err)
+		assert.Equal(t, pdf, stored)
+	})
+
+	t.Run("should keep the URL of downloaded documents", func(t *testing.T) {
+		service, _ := newTestIngestionService(t, nil, nil)
+		document := &entity.EntityDocument{FilePath: "https://exemplo.com/contrato.pdf"}
+
+		err := service.StoreOriginal(ctx, document, &utils.Base64FileInfo{DecodedData: pdf, MimeType: "application/pdf"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "https://exemplo.com/contrato.pdf", document.FilePath)
+		assert.NotEmpty(t, document.StorageKey)
+	})
+
+	t.Run("should convert documents and keep the source", func(t *testing.T) {
+		converted := []byte("%PDF-1.4\nconvertido\n")
+		service, documentStorage := newTestIngestionService(t, stubConverter{pdf: converted}, nil)
+		document := &entity.EntityDocument{IsFromBase64: true}
+		docx := []byte("conteudo docx")
+
+		err := service.StoreOriginal(ctx, document, &utils.Base64FileInfo{DecodedData: docx, MimeType: utils.MimeTypeDOCX})
+
+		require.NoError(t, err)
+		assert.Equal(t, "application/pdf", document.MimeType)
+		var metadata struct {
+			Conversion entity.DocumentConversion `json:"conversion"`
+		}
+		decodeMetadata(t, document, &metadata)
+		assert.Equal(t, "stub", metadata.Conversion.Converter)
+		source, err := storage.ReadAll(ctx, documentStorage, metadata.Conversion.SourceStorageKey)
+		require.NoError(t, err)
+		assert.Equal(t, docx, source)
+	})
+
+	t.Run("should mark conversion failures", func(t *testing.T) {
+		service, _ := newTestIngestionService(t, stubConverter{err: errors.New("soffice crashed")}, nil)
+
+		err := service.StoreOriginal(ctx, &entity.EntityDocument{}, &utils.Base64FileInfo{DecodedData: []byte("docx"), MimeType: utils.MimeTypeDOCX})
+
+		assert.ErrorIs(t, err, ErrConversion)
+	})
+
+	t.Run("should mark PDFs rejected by the normalizer", func(t *testing.T) {
+		service, _ := newTestIngestionService(t, nil, nil)
+
+		err := service.StoreOriginal(ctx, &entity.EntityDocument{}, &utils.Base64FileInfo{DecodedData: samplePDF(64, " /Encrypt 5 0 R"), MimeType: "application/pdf"})
+
+		assert.ErrorIs(t, err, ErrNormalization)
+	})
+
+	t.Run("should record the scan of clean documents", func(t *testing.T) {
+		service, _ := newTestIngestionService(t, nil, stubScanner{marker: "EICAR"})
+		document := &entity.EntityDocument{Status: "draft"}
+
+		require.NoError(t, service.StoreOriginal(ctx, document, &utils.Base64FileInfo{DecodedData: pdf, MimeType: "application/pdf"}))
+
+		var metadata struct {
+			Scan entity.DocumentScan `json:"scan"`
+		}
+		decodeMetadata(t, document, &metadata)
+		assert.Equal(t, "stub", metadata.Scan.Scanner)
+		assert.False(t, metadata.Scan.Infected)
+		assert.Equal(t, "draft", document.Status)
+	})
+
+	t.Run("should reject infected documents without storing them", func(t *testing.T) {
+		service, _ := newTestIngestionService(t, nil, stubScanner{marker: "EICAR"})
+		document := &entity.EntityDocument{}
+
+		err := service.StoreOriginal(ctx, document, &utils.Base64FileInfo{DecodedData: append(pdf, []byte("EICAR")...), MimeType: "application/pdf"})
+
+		assert.ErrorIs(t, err, ErrInfected)
+		assert.Empty(t, document.StorageKey)
+	})
+
+	t.Run("should mark scanner failures", func(t *testing.T) {
+		service, _ := newTestIngestionService(t, nil, stubScanner{err: errors.New("failed to connect to clamd")})
+
+		err := service.StoreOriginal(ctx, &entity.EntityDocument{}, &utils.Base64FileInfo{DecodedData: pdf, MimeType: "application/pdf"})
+
+		assert.ErrorIs(t, err, ErrScanFailed)
+	})
+}
+
+func TestUsecaseDocumentIngestionService_StoreUpload(t *testing.T) {
+	ctx := context.Background()
+	pdf := samplePDF(4096, "")
+
+	t.Run("should stream the upload to the storage", func(t *testing.T) {
+		service, documentStorage := newTestIngestionService(t, nil, stubScanner{marker: "EICAR"})
+		document := &entity.EntityDocument{Status: "draft"}
+
+		require.NoError(t, service.StoreUpload(ctx, document, bytes.NewReader(pdf), 1024*1024))
+
+		assert.Equal(t, "application/pdf", document.MimeType)
+		assert.Equal(t, storage.ContentKey(storage.KindOriginal, document.SHA256), document.StorageKey)
+		stored, err := storage.ReadAll(ctx, documentStorage, document.StorageKey)
+		require.NoError(t, err)
+		assert.Equal(t, pdf, stored)
+		assert.Equal(t, "draft", document.Status)
+	})
+
+	t.Run("should reject unsupported file types", func(t *testing.T) {
+		service, _ := newTestIngestionService(t, nil, nil)
+
+		err := service.StoreUpload(ctx, &entity.EntityDocument{}, strings.NewReader("nome,valor\nteste,1\n"), 1024*1024)
+
+		assert.ErrorIs(t, err, ErrUnsupportedUpload)
+	})
+
+	t.Run("should reject uploads above the limit", func(t *testing.T) {
+		service, _ := newTestIngestionService(t, nil, nil)
+
+		err := service.StoreUpload(ctx, &entity.EntityDocument{}, bytes.NewReader(pdf), 1024)
+
+		assert.ErrorIs(t, err, storage.ErrTooLarge)
+	})
+
+	t.Run("should store infected uploads and quarantine the document", func(t *testing.T) {
+		service, _ := newTestIngestionService(t, nil, stubScanner{marker: "EICAR"})
+		document := &entity.EntityDocument{Status: "draft"}
+
+		err := service.StoreUpload(ctx, document, bytes.NewReader(append(samplePDF(128, ""), []byte("EICAR")...)), 1024*1024)
+
+		assert.ErrorIs(t, err, ErrInfected)
+		assert.Equal(t, entity.DocumentStatusQuarantined, document.Status)
+		assert.NotEmpty(t, document.StorageKey)
+	})
+}
+
+func TestUsecaseDocumentIngestionService_MergeParts(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should merge the parts and store them with the sources", func(t *testing.T) {
+		service, documentStorage := newTestIngestionService(t, nil, nil)
+		parts := []normalizer.Part{
+			{Data: samplePNG(t, 40, 60), MimeType: "image/png"},
+			{Data: samplePNG(t, 60, 40), MimeType: "image/png"},
+		}
+
+		merged, err := service.MergeParts(ctx, parts)
+		require.NoError(t, err)
+
+		document := &entity.EntityDocument{IsFromBase64: true}
+		require.NoError(t, service.StoreMergedParts(ctx, document, merged))
+
+		assert.Equal(t, "application/pdf", document.MimeType)
+		stored, err := storage.ReadAll(ctx, documentStorage, document.StorageKey)
+		require.NoError(t, err)
+		assert.Equal(t, 2, bytes.Count(stored, []byte("/Type /Page ")))
+
+		var metadata struct {
+			Normalization entity.DocumentNormalization `json:"normalization"`
+		}
+		decodeMetadata(t, document, &metadata)
+		require.Len(t, metadata.Normalization.Sources, 2)
+		assert.Equal(t, "image/png", metadata.Normalization.Sources[1].MimeType)
+	})
+
+	t.Run("should record the scan of every part", func(t *testing.T) {
+		service, _ := newTestIngestionService(t, nil, stubScanner{marker: "EICAR"})
+
+		merged, err := service.MergeParts(ctx, []normalizer.Part{
+			{Data: samplePNG(t, 40, 60), MimeType: "image/png"},
+			{Data: samplePNG(t, 60, 40), MimeType: "image/png"},
+		})
+		require.NoError(t, err)
+
+		document := &entity.EntityDocument{Status: "draft"}
+		require.NoError(t, service.StoreMergedParts(ctx, document, merged))
+
+		var metadata struct {
+			Scan entity.DocumentScan `json:"scan"`
+		}
+		decodeMetadata(t, document, &metadata)
+		assert.Equal(t, "stub", metadata.Scan.Scanner)
+		assert.False(t, metadata.Scan.Infected)
+		assert.Equal(t, []entity.DocumentScanPart{{Index: 1}, {Index: 2}}, metadata.Scan.Parts)
+		assert.Equal(t, "draft", document.Status)
+	})
+
+	t.Run("should reject infected parts", func(t *testing.T) {
+		service, _ := newTestIngestionService(t, nil, stubScanner{marker: "EICAR"})
+
+		_, err := service.MergeParts(ctx, []normalizer.Part{
+			{Data: samplePDF(16, ""), MimeType: "application/pdf"},
+			{Data: append(samplePDF(16, ""), []byte("EICAR")...), MimeType: "application/pdf"},
+		})
+
+		assert.ErrorIs(t, err, ErrInfected)
+		assert.ErrorContains(t, err, "part 2")
+	})
+
+	t.Run("should mark parts rejected by the normalizer", func(t *testing.T) {
+		service, _ := newTestIngestionService(t, nil, nil)
+
+		_, err := service.MergeParts(ctx, []normalizer.Part{
+			{Data: samplePNG(t, 10, 10), MimeType: "image/png"},
+			{Data: []byte("%PDF-1.4\nsem fim\n"), MimeType: "application/pdf"},
+		})
+
+		assert.ErrorIs(t, err, ErrNormalization)
+	})
+}
+
+func TestUsecaseDocumentIngestionService_FailClosed(t *testing.T) {
+	ctx := context.Background()
+	pdf := samplePDF(64, "")
+	basic := normalizer.NewNormalizer(normalizer.BasicTool{}, normalizer.Options{})
+
+	t.Run("should reject documents without a scanner", func(t *testing.T) {
+		documentStorage, err := storage.NewLocalStorage(t.TempDir())
+		require.NoError(t, err)
+		service := NewUsecaseDocumentIngestionService(documentStorage, converter.DisabledConverter{}, basic, nil)
+		document := &entity.EntityDocument{}
+
+		err = service.StoreOriginal(ctx, document, &utils.Base64FileInfo{DecodedData: pdf, MimeType: "application/pdf"})
+
+		assert.ErrorIs(t, err, ErrScanFailed)
+		assert.Empty(t, document.StorageKey)
+	})
+
+	t.Run("should not store uploads without a scanner", func(t *testing.T) {
+		documentStorage, err := storage.NewLocalStorage(t.TempDir())
+		require.NoError(t, err)
+		service := NewUsecaseDocumentIngestionService(documentStorage, converter.DisabledConverter{}, basic, nil)
+		document := &entity.EntityDocument{}
+
+		err = service.StoreUpload(ctx, document, bytes.NewReader(pdf), 1024*1024)
+
+		assert.ErrorIs(t, err, ErrScanFailed)
+		assert.Empty(t, document.StorageKey)
+	})
+
+	t.Run("should reject parts without a scanner", func(t *testing.T) {
+		documentStorage, err := storage.NewLocalStorage(t.TempDir())
+		require.NoError(t, err)
+		service := NewUsecaseDocumentIngestionService(documentStorage, converter.DisabledConverter{}, basic, nil)
+
+		_, err = service.MergeParts(ctx, []normalizer.Part{{Data: pdf, MimeType: "application/pdf"}})
+
+		assert.ErrorIs(t, err, ErrScanFailed)
+	})
+
+	t.Run("should reject documents without a normalizer", func(t *testing.T) {
+		documentStorage, err := storage.NewLocalStorage(t.TempDir())
+		require.NoError(t, err)
+		service := NewUsecaseDocumentIngestionService(documentStorage, converter.DisabledConverter{}, nil, scanner.DisabledScanner{})
+
+		err = service.StoreOriginal(ctx, &entity.EntityDocument{}, &utils.Base64FileInfo{DecodedData: pdf, MimeType: "application/pdf"})
+
+		assert.ErrorIs(t, err, ErrNotConfigured)
+	})
+}
+
+func TestCombineScan(t *testing.T) {
+	clean := &entity.DocumentScan{Scanner: "clamav"}
+	infected := &entity.DocumentScan{Scanner: "clamav", Infected: true, Threat: "Eicar-Test-Signature"}
+
+	scan := combineScan(nil, 1, clean)
+	scan = combineScan(scan, 2, infected)
+	scan = combineScan(scan, 3, clean)
+
+	assert.Equal(t, "clamav", scan.Scanner)
+	assert.True(t, scan.Infected)
+	assert.Equal(t, "Eicar-Test-Signature", scan.Threat)
+	assert.Equal(t, []entity.DocumentScanPart{
+		{Index: 1},
+		{Index: 2, Infected: true, Threat: "Eicar-Test-Signature"},
+		{Index: 3},
+	}, scan.Parts)
+	assert.Nil(t, combineScan(nil, 1, nil))
+}
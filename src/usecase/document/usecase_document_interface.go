@@ -10,6 +10,9 @@ type IRepositoryDocument interface {
 	Delete(document *entity.EntityDocument) error
 	GetDocuments(filters entity.EntityDocumentFilters) ([]entity.EntityDocument, error)
 	GetByClicksignKey(key string) (*entity.EntityDocument, error)
+	GetByContentHash(sum string) ([]entity.EntityDocument, error)
+	ReplaceContent(document *entity.EntityDocument, previous *entity.EntityDocumentVersion) error
+	GetVersions(documentID int) ([]entity.EntityDocumentVersion, error)
 }
 
 //go:generate mockgen -destination=../../mocks/mock_usecase_document.go -package=mocks app/usecase/document IUsecaseDocument
@@ -20,6 +23,9 @@ type IUsecaseDocument interface {
 	GetDocument(id int) (*entity.EntityDocument, error)
 	GetDocumentByClicksignKey(key string) (*entity.EntityDocument, error)
 	GetDocuments(filters entity.EntityDocumentFilters) ([]entity.EntityDocument, error)
+	FindByContentHash(sum string) ([]entity.EntityDocument, error)
 	PrepareForSigning(id int) (*entity.EntityDocument, error)
 	UploadToClicksign(document *entity.EntityDocument) (string, error)
+	ReplaceContent(document *entity.EntityDocument, replacement *entity.EntityDocument) (*entity.EntityDocumentVersion, error)
+	GetVersions(documentID int) ([]entity.EntityDocumentVersion, error)
 }
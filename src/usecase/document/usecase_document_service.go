@@ -3,8 +3,12 @@ package document
 import (
 	"app/entity"
 	"app/infrastructure/clicksign"
+	"app/infrastructure/storage"
+	"app/pkg/fetcher"
 	"context"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 )
@@ -25,9 +29,11 @@ func NewUsecaseDocumentService(repositoryDocument IRepositoryDocument) *UsecaseD
 func NewUsecaseDocumentServiceWithClicksign(
 	repositoryDocument IRepositoryDocument,
 	clicksignClient clicksign.ClicksignClientInterface,
+	documentStorage storage.Storage,
+	documentFetcher *fetcher.Fetcher,
 	logger *logrus.Logger,
 ) IUsecaseDocument {
-	documentService := clicksign.NewDocumentService(clicksignClient, logger)
+	documentService := clicksign.NewDocumentService(clicksignClient, documentStorage, documentFetcher, logger)
 
 	return &UsecaseDocumentService{
 		repositoryDocument: repositoryDocument,
@@ -43,6 +49,10 @@ func (u *UsecaseDocumentService) Create(document *entity.EntityDocument) error {
 		return fmt.Errorf("document validation failed: %w", err)
 	}
 
+	if err := fingerprint(document); err != nil {
+		return fmt.Errorf("failed to fingerprint document: %w", err)
+	}
+
 	err = u.repositoryDocument.Create(document)
 	if err != nil {
 		return fmt.Errorf("failed to create document: %w", err)
@@ -119,6 +129,16 @@ func (u *UsecaseDocumentService) GetDocuments(filters entity.EntityDocumentFilte
 	return documents, nil
 }
 
+// FindByContentHash retorna os documentos cujo original ou cópia assinada tem o SHA-256 informado
+func (u *UsecaseDocumentService) FindByContentHash(sum string) ([]entity.EntityDocument, error) {
+	documents, err := u.repositoryDocument.GetByContentHash(strings.ToLower(sum))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find documents by hash: %w", err)
+	}
+
+	return documents, nil
+}
+
 func (u *UsecaseDocumentService) PrepareForSigning(id int) (*entity.EntityDocument, error) {
 	document, err := u.repositoryDocument.GetByID(id)
 	if err != nil {
@@ -143,6 +163,9 @@ func (u *UsecaseDocumentService) UploadToClicksign(document *entity.EntityDocume
 	if u.documentService == nil {
 		return "", fmt.Errorf("clicksign service not configured")
 	}
+	if document.IsQuarantined() {
+		return "", entity.ErrDocumentQuarantined
+	}
 
 	ctx := context.Background()
 	correlationID := fmt.Sprintf("doc_%d", document.CreatedAt.Unix())
@@ -161,3 +184,69 @@ func (u *UsecaseDocumentService) UploadToClicksign(document *entity.EntityDocume
 
 	return clicksignDocID, nil
 }
+
+// ReplaceContent troca o conteúdo do documento pelo de replacement e guarda a versão anterior no histórico
+func (u *UsecaseDocumentService) ReplaceContent(document *entity.EntityDocument, replacement *entity.EntityDocument) (*entity.EntityDocumentVersion, error) {
+	if err := fingerprint(replacement); err != nil {
+		return nil, fmt.Errorf("failed to fingerprint replacement: %w", err)
+	}
+
+	previous, err := document.ReplaceContent(replacement)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := document.Validate(); err != nil {
+		return nil, fmt.Errorf("document validation failed: %w", err)
+	}
+
+	if err := u.repositoryDocument.ReplaceContent(document, previous); err != nil {
+		return nil, fmt.Errorf("failed to replace document content: %w", err)
+	}
+
+	return previous, nil
+}
+
+// GetVersions retorna as versões substituídas do documento, da mais antiga para a mais recente
+func (u *UsecaseDocumentService) GetVersions(documentID int) ([]entity.EntityDocumentVersion, error) {
+	versions, err := u.repositoryDocument.GetVersions(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document versions: %w", err)
+	}
+
+	return versions, nil
+}
+
+// fingerprint garante o SHA-256 do original quando o chamador não o calculou na ingestão
+// Documentos no storage usam o hash da própria chave; arquivos locais são lidos; URLs remotas ficam sem hash
+func fingerprint(document *entity.EntityDocument) error {
+	if document.SHA256 != "" {
+		return nil
+	}
+
+	if sum, ok := storage.SumFromKey(document.StorageKey); ok {
+		document.SHA256 = sum
+		return nil
+	}
+
+	if document.FilePath == "" || strings.HasPrefix(document.FilePath, "http://") || strings.HasPrefix(document.FilePath, "https://") {
+		return nil
+	}
+	if _, ok := storage.KeyFromURI(document.FilePath); ok {
+		return nil
+	}
+
+	file, err := os.Open(document.FilePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	object, err := storage.Hash(file, 0)
+	if err != nil {
+		return err
+	}
+
+	document.SetContentHash(object.SHA256, object.Size)
+	return nil
+}
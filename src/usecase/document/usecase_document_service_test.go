@@ -5,6 +5,7 @@ import (
 	"app/mocks"
 	"errors"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -464,3 +465,97 @@ func TestUsecaseDocumentService_PrepareForSigning(t *testing.T) {
 		})
 	}
 }
+
+func TestUsecaseDocumentService_CreateFingerprint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIRepositoryDocument(ctrl)
+	service := NewUsecaseDocumentService(mockRepo)
+
+	t.Run("should hash local file when ingestion did not", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "contrato.pdf")
+		require.NoError(t, os.WriteFile(path, []byte("test"), 0o600))
+
+		document := &entity.EntityDocument{Name: "Contrato", FilePath: path, FileSize: 4, MimeType: "application/pdf", Status: "draft"}
+		mockRepo.EXPECT().Create(document).Return(nil)
+
+		require.NoError(t, service.Create(document))
+		assert.Equal(t, "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", document.SHA256)
+	})
+
+	t.Run("should take hash from content-addressed storage key", func(t *testing.T) {
+		sum := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+		document := &entity.EntityDocument{Name: "Contrato", FileSize: 1, MimeType: "application/pdf", Status: "draft"}
+		document.SetStorageKey("originals/sha256/" + sum)
+		mockRepo.EXPECT().Create(document).Return(nil)
+
+		require.NoError(t, service.Create(document))
+		assert.Equal(t, sum, document.SHA256)
+	})
+}
+
+func TestUsecaseDocumentService_FindByContentHash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIRepositoryDocument(ctrl)
+	service := NewUsecaseDocumentService(mockRepo)
+
+	mockRepo.EXPECT().GetByContentHash("abc").Return([]entity.EntityDocument{{ID: 7}}, nil)
+
+	documents, err := service.FindByContentHash("ABC")
+
+	require.NoError(t, err)
+	require.Len(t, documents, 1)
+	assert.Equal(t, 7, documents[0].ID)
+}
+
+func TestUsecaseDocumentService_ReplaceContent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIRepositoryDocument(ctrl)
+	service := NewUsecaseDocumentService(mockRepo)
+
+	oldSum := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	newSum := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	newDocument := func() *entity.EntityDocument {
+		document := &entity.EntityDocument{ID: 3, Name: "Contrato", FileSize: 4, MimeType: "application/pdf", Status: "draft", SHA256: oldSum}
+		document.SetStorageKey("originals/sha256/" + oldSum)
+		return document
+	}
+
+	t.Run("should store the previous version with the new content", func(t *testing.T) {
+		document := newDocument()
+		replacement := &entity.EntityDocument{FileSize: 8, MimeType: "application/pdf", IsFromBase64: true}
+		replacement.SetStorageKey("originals/sha256/" + newSum)
+
+		mockRepo.EXPECT().ReplaceContent(document, gomock.Any()).DoAndReturn(
+			func(d *entity.EntityDocument, previous *entity.EntityDocumentVersion) error {
+				assert.Equal(t, 1, previous.Version)
+				assert.Equal(t, oldSum, previous.SHA256)
+				return nil
+			})
+
+		previous, err := service.ReplaceContent(document, replacement)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, previous.Version)
+		assert.Equal(t, 2, document.Version)
+		assert.Equal(t, newSum, document.SHA256)
+	})
+
+	t.Run("should return repository errors", func(t *testing.T) {
+		document := newDocument()
+		replacement := &entity.EntityDocument{FileSize: 8, MimeType: "application/pdf", IsFromBase64: true}
+		replacement.SetStorageKey("originals/sha256/" + newSum)
+
+		mockRepo.EXPECT().ReplaceContent(document, gomock.Any()).Return(errors.New("database error"))
+
+		_, err := service.ReplaceContent(document, replacement)
+
+		assert.ErrorContains(t, err, "failed to replace document content")
+	})
+}
@@ -0,0 +1,25 @@
+package usecase_document_template
+
+import (
+	"app/entity"
+	"context"
+)
+
+//go:generate mockgen -destination=../../mocks/mock_usecase_repository_document_template.go -package=mocks app/usecase/document_template IRepositoryDocumentTemplate
+type IRepositoryDocumentTemplate interface {
+	Create(template *entity.EntityDocumentTemplate) error
+	GetByID(id int) (*entity.EntityDocumentTemplate, error)
+	GetAll() ([]entity.EntityDocumentTemplate, error)
+	Update(template *entity.EntityDocumentTemplate) error
+	Delete(template *entity.EntityDocumentTemplate) error
+}
+
+//go:generate mockgen -destination=../../mocks/mock_usecase_document_template.go -package=mocks app/usecase/document_template IUsecaseDocumentTemplate
+type IUsecaseDocumentTemplate interface {
+	CreateTemplate(template *entity.EntityDocumentTemplate) (*entity.EntityDocumentTemplate, error)
+	GetTemplate(id int) (*entity.EntityDocumentTemplate, error)
+	GetTemplates() ([]entity.EntityDocumentTemplate, error)
+	UpdateTemplate(template *entity.EntityDocumentTemplate) error
+	DeleteTemplate(id int) error
+	GeneratePDF(ctx context.Context, id int, variables map[string]string) ([]byte, *entity.EntityDocumentTemplate, error)
+}
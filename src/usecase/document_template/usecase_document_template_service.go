@@ -0,0 +1,138 @@
+package usecase_document_template
+
+import (
+	"app/entity"
+	"app/infrastructure/converter"
+	"app/pkg/pdf"
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+type UsecaseDocumentTemplateService struct {
+	repository IRepositoryDocumentTemplate
+	converter  converter.Converter
+	logger     *logrus.Logger
+}
+
+// NewUsecaseDocumentTemplateService cria o serviço; templates HTML são convertidos para PDF por documentConverter
+func NewUsecaseDocumentTemplateService(repository IRepositoryDocumentTemplate, documentConverter converter.Converter, logger *logrus.Logger) *UsecaseDocumentTemplateService {
+	return &UsecaseDocumentTemplateService{
+		repository: repository,
+		converter:  documentConverter,
+		logger:     logger,
+	}
+}
+
+func (u *UsecaseDocumentTemplateService) CreateTemplate(template *entity.EntityDocumentTemplate) (*entity.EntityDocumentTemplate, error) {
+	if err := u.repository.Create(template); err != nil {
+		return nil, fmt.Errorf("failed to create document template: %w", err)
+	}
+
+	u.logger.WithFields(logrus.Fields{
+		"template_id":  template.ID,
+		"format":       template.Format,
+		"placeholders": template.Placeholders(),
+	}).Info("Document template created")
+
+	return template, nil
+}
+
+func (u *UsecaseDocumentTemplateService) GetTemplate(id int) (*entity.EntityDocumentTemplate, error) {
+	return u.repository.GetByID(id)
+}
+
+func (u *UsecaseDocumentTemplateService) GetTemplates() ([]entity.EntityDocumentTemplate, error) {
+	return u.repository.GetAll()
+}
+
+func (u *UsecaseDocumentTemplateService) UpdateTemplate(template *entity.EntityDocumentTemplate) error {
+	if err := template.Validate(); err != nil {
+		return err
+	}
+	return u.repository.Update(template)
+}
+
+func (u *UsecaseDocumentTemplateService) DeleteTemplate(id int) error {
+	template, err := u.repository.GetByID(id)
+	if err != nil {
+		return err
+	}
+	return u.repository.Delete(template)
+}
+
+// GeneratePDF renderiza o template com as variáveis e gera o PDF
+// Markdown é desenhado direto pelo pacote pdf; HTML passa pelo conversor configurado
+func (u *UsecaseDocumentTemplateService) GeneratePDF(ctx context.Context, id int, variables map[string]string) ([]byte, *entity.EntityDocumentTemplate, error) {
+	template, err := u.repository.GetByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := template.Render(variables)
+	if err != nil {
+		return nil, template, err
+	}
+
+	var data []byte
+	switch template.Format {
+	case entity.TemplateFormatMarkdown:
+		data, err = pdf.Render(markdownToBlocks(content))
+	case entity.TemplateFormatHTML:
+		data, err = u.converter.ConvertToPDF(ctx, []byte(content), "text/html")
+	default:
+		err = fmt.Errorf("unsupported template format: %s", template.Format)
+	}
+	if err != nil {
+		return nil, template, fmt.Errorf("failed to generate PDF from template %d: %w", template.ID, err)
+	}
+
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		return nil, template, fmt.Errorf("failed to generate PDF from template %d: output is not a PDF", template.ID)
+	}
+
+	return data, template, nil
+}
+
+// markdownToBlocks cobre o subconjunto de Markdown usado em contratos: títulos, listas e parágrafos
+// Linhas consecutivas formam um parágrafo e ênfases (** e __) são removidas
+func markdownToBlocks(content string) []pdf.Block {
+	var blocks []pdf.Block
+	var paragraph []string
+
+	flush := func() {
+		if len(paragraph) > 0 {
+			blocks = append(blocks, pdf.Block{Text: strings.Join(paragraph, " ")})
+			paragraph = nil
+		}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		line = strings.NewReplacer("**", "", "__", "").Replace(strings.TrimSpace(line))
+
+		switch {
+		case line == "":
+			flush()
+			if len(blocks) > 0 && blocks[len(blocks)-1].Text != "" {
+				blocks = append(blocks, pdf.Block{})
+			}
+		case strings.HasPrefix(line, "# "):
+			flush()
+			blocks = append(blocks, pdf.Block{Text: strings.TrimSpace(line[2:]), Style: pdf.StyleHeading})
+		case strings.HasPrefix(line, "#"):
+			flush()
+			blocks = append(blocks, pdf.Block{Text: strings.TrimSpace(strings.TrimLeft(line, "#")), Style: pdf.StyleSubheading})
+		case strings.HasPrefix(line, "- "), strings.HasPrefix(line, "* "):
+			flush()
+			blocks = append(blocks, pdf.Block{Text: "• " + strings.TrimSpace(line[2:])})
+		default:
+			paragraph = append(paragraph, line)
+		}
+	}
+	flush()
+
+	return blocks
+}
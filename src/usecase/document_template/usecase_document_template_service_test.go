@@ -0,0 +1,137 @@
+package usecase_document_template
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"app/entity"
+	"app/infrastructure/converter"
+	"app/mocks"
+	"app/pkg/pdf"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingConverter struct {
+	input  string
+	output []byte
+	err    error
+}
+
+func (c *recordingConverter) ConvertToPDF(ctx context.Context, data []byte, mimeType string) ([]byte, error) {
+	c.input = string(data)
+	return c.output, c.err
+}
+
+func (c *recordingConverter) Name() string {
+	return "recording"
+}
+
+func TestUsecaseDocumentTemplateService_GeneratePDF(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIRepositoryDocumentTemplate(ctrl)
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	ctx := context.Background()
+
+	t.Run("should render markdown templates directly", func(t *testing.T) {
+		service := NewUsecaseDocumentTemplateService(mockRepo, converter.DisabledConverter{}, logger)
+		mockRepo.EXPECT().GetByID(1).Return(&entity.EntityDocumentTemplate{
+			ID:      1,
+			Format:  entity.TemplateFormatMarkdown,
+			Content: "# Contrato\n\nContratante: {{ nome }}",
+		}, nil)
+
+		data, template, err := service.GeneratePDF(ctx, 1, map[string]string{"nome": "Ana Souza"})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, template.ID)
+		assert.Contains(t, string(data), "%PDF-1.4")
+		assert.Contains(t, string(data), "Contratante: Ana Souza")
+	})
+
+	t.Run("should convert html templates with the configured converter", func(t *testing.T) {
+		fake := &recordingConverter{output: []byte("%PDF-1.7\n")}
+		service := NewUsecaseDocumentTemplateService(mockRepo, fake, logger)
+		mockRepo.EXPECT().GetByID(2).Return(&entity.EntityDocumentTemplate{
+			ID:      2,
+			Format:  entity.TemplateFormatHTML,
+			Content: "<p>{{ nome }}</p>",
+		}, nil)
+
+		data, _, err := service.GeneratePDF(ctx, 2, map[string]string{"nome": "Ana & Cia"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "%PDF-1.7\n", string(data))
+		assert.Equal(t, "<p>Ana &amp; Cia</p>", fake.input)
+	})
+
+	t.Run("should fail when variables are missing", func(t *testing.T) {
+		service := NewUsecaseDocumentTemplateService(mockRepo, converter.DisabledConverter{}, logger)
+		mockRepo.EXPECT().GetByID(3).Return(&entity.EntityDocumentTemplate{
+			ID:      3,
+			Format:  entity.TemplateFormatMarkdown,
+			Content: "{{ nome }} {{ cpf }}",
+		}, nil)
+
+		_, _, err := service.GeneratePDF(ctx, 3, map[string]string{"nome": "Ana"})
+
+		assert.ErrorIs(t, err, entity.ErrMissingTemplateVariables)
+	})
+
+	t.Run("should report converter failures", func(t *testing.T) {
+		service := NewUsecaseDocumentTemplateService(mockRepo, converter.DisabledConverter{}, logger)
+		mockRepo.EXPECT().GetByID(4).Return(&entity.EntityDocumentTemplate{
+			ID:      4,
+			Format:  entity.TemplateFormatHTML,
+			Content: "<p>Contrato</p>",
+		}, nil)
+
+		_, _, err := service.GeneratePDF(ctx, 4, nil)
+
+		assert.ErrorIs(t, err, converter.ErrDisabled)
+	})
+
+	t.Run("should reject converter output that is not a PDF", func(t *testing.T) {
+		service := NewUsecaseDocumentTemplateService(mockRepo, &recordingConverter{output: []byte("<html>")}, logger)
+		mockRepo.EXPECT().GetByID(5).Return(&entity.EntityDocumentTemplate{
+			ID:      5,
+			Format:  entity.TemplateFormatHTML,
+			Content: "<p>Contrato</p>",
+		}, nil)
+
+		_, _, err := service.GeneratePDF(ctx, 5, nil)
+
+		assert.ErrorContains(t, err, "output is not a PDF")
+	})
+
+	t.Run("should propagate repository errors", func(t *testing.T) {
+		service := NewUsecaseDocumentTemplateService(mockRepo, converter.DisabledConverter{}, logger)
+		mockRepo.EXPECT().GetByID(6).Return(nil, errors.New("record not found"))
+
+		_, template, err := service.GeneratePDF(ctx, 6, nil)
+
+		assert.Error(t, err)
+		assert.Nil(t, template)
+	})
+}
+
+func TestMarkdownToBlocks(t *testing.T) {
+	blocks := markdownToBlocks("# Contrato\r\n\r\n## Cláusula 1\nO **contratante** se obriga\na pagar.\n\n\n- item um\n* item dois")
+
+	assert.Equal(t, []pdf.Block{
+		{Text: "Contrato", Style: pdf.StyleHeading},
+		{},
+		{Text: "Cláusula 1", Style: pdf.StyleSubheading},
+		{Text: "O contratante se obriga a pagar."},
+		{},
+		{Text: "• item um"},
+		{Text: "• item dois"},
+	}, blocks)
+}
@@ -0,0 +1,58 @@
+package usecase_envelope
+
+import (
+	"fmt"
+	"time"
+
+	"app/entity"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ApproveEnvelope registra a aprovação e ativa o envelope no provider
+// Se a ativação falhar, a aprovação continua registrada e o envelope fica em approved, sem aceitar edições,
+// para nova tentativa em /activate
+func (u *UsecaseEnvelopeProviderService) ApproveEnvelope(id int, approver entity.EntityUser, comment string) (*entity.EntityEnvelope, error) {
+	envelope, err := u.repositoryEnvelope.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("envelope not found: %w", err)
+	}
+
+	if err := envelope.Approve(approver, comment, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := u.repositoryEnvelope.Update(envelope); err != nil {
+		return nil, fmt.Errorf("failed to record approval: %w", err)
+	}
+
+	u.logger.WithFields(logrus.Fields{
+		"envelope_id": envelope.ID,
+		"approver_id": approver.ID,
+	}).Info("Envelope approved")
+
+	return u.ActivateEnvelope(id)
+}
+
+// RejectEnvelope registra a rejeição; o envelope continua em rascunho no provider e nunca é enviado aos signatários
+func (u *UsecaseEnvelopeProviderService) RejectEnvelope(id int, approver entity.EntityUser, comment string) (*entity.EntityEnvelope, error) {
+	envelope, err := u.repositoryEnvelope.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("envelope not found: %w", err)
+	}
+
+	if err := envelope.Reject(approver, comment, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := u.repositoryEnvelope.Update(envelope); err != nil {
+		return nil, fmt.Errorf("failed to record rejection: %w", err)
+	}
+
+	u.logger.WithFields(logrus.Fields{
+		"envelope_id": envelope.ID,
+		"approver_id": approver.ID,
+	}).Info("Envelope rejected")
+
+	return envelope, nil
+}
@@ -0,0 +1,94 @@
+package usecase_envelope_test
+
+import (
+	"errors"
+	"testing"
+
+	"app/entity"
+	"app/mocks"
+	usecase_envelope "app/usecase/envelope"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsecaseEnvelopeProviderService_ApproveEnvelope(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIRepositoryEnvelope(ctrl)
+	mockProvider := mocks.NewMockEnvelopeProvider(ctrl)
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	service := usecase_envelope.NewUsecaseEnvelopeProviderService(mockRepo, mockProvider, nil, nil, logger)
+	approver := entity.EntityUser{ID: 2, Role: entity.RoleApprover}
+
+	t.Run("should record approval and activate envelope in provider", func(t *testing.T) {
+		envelope := &entity.EntityEnvelope{ID: 1, Status: entity.EnvelopeStatusPendingApproval, ClicksignKey: "key-1", CreatedByID: 1}
+
+		mockRepo.EXPECT().GetByID(1).Return(envelope, nil).Times(2)
+		mockRepo.EXPECT().Update(envelope).Return(nil).Times(2)
+		mockProvider.EXPECT().ActivateEnvelope(gomock.Any(), "key-1").Return(nil)
+
+		result, err := service.ApproveEnvelope(1, approver, "Conferido")
+
+		require.NoError(t, err)
+		assert.Equal(t, "sent", result.Status)
+		assert.True(t, result.IsApproved())
+	})
+
+	t.Run("should not activate envelope approved by its creator", func(t *testing.T) {
+		envelope := &entity.EntityEnvelope{ID: 3, Status: entity.EnvelopeStatusPendingApproval, ClicksignKey: "key-3", CreatedByID: approver.ID}
+
+		mockRepo.EXPECT().GetByID(3).Return(envelope, nil)
+
+		_, err := service.ApproveEnvelope(3, approver, "")
+
+		assert.ErrorIs(t, err, entity.ErrSelfApproval)
+	})
+
+	t.Run("should return not found error", func(t *testing.T) {
+		mockRepo.EXPECT().GetByID(4).Return(nil, errors.New("record not found"))
+
+		_, err := service.ApproveEnvelope(4, approver, "")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestUsecaseEnvelopeProviderService_RejectEnvelope(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIRepositoryEnvelope(ctrl)
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	// A rejeição não chega ao provider
+	service := usecase_envelope.NewUsecaseEnvelopeProviderService(mockRepo, nil, nil, nil, logger)
+	approver := entity.EntityUser{ID: 2, Role: entity.RoleApprover}
+
+	t.Run("should record rejection without activating", func(t *testing.T) {
+		envelope := &entity.EntityEnvelope{ID: 1, Status: entity.EnvelopeStatusPendingApproval, CreatedByID: 1}
+
+		mockRepo.EXPECT().GetByID(1).Return(envelope, nil)
+		mockRepo.EXPECT().Update(envelope).Return(nil)
+
+		result, err := service.RejectEnvelope(1, approver, "Valor divergente")
+
+		require.NoError(t, err)
+		assert.Equal(t, entity.EnvelopeStatusRejected, result.Status)
+		assert.Equal(t, "Valor divergente", result.ApprovalTrail[0].Comment)
+	})
+
+	t.Run("should refuse envelopes not pending approval", func(t *testing.T) {
+		mockRepo.EXPECT().GetByID(2).Return(&entity.EntityEnvelope{ID: 2, Status: "sent"}, nil)
+
+		_, err := service.RejectEnvelope(2, approver, "Tarde demais")
+
+		assert.ErrorIs(t, err, entity.ErrEnvelopeNotPendingApproval)
+	})
+}
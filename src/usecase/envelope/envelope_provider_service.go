@@ -2,6 +2,7 @@ package usecase_envelope
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"app/entity"
@@ -12,6 +13,13 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+var (
+	// ErrEnvelopeNotDraft é retornado ao alterar documentos de um envelope que já saiu do rascunho
+	ErrEnvelopeNotDraft = entity.ErrEnvelopeNotDraft
+	// ErrDocumentNotInEnvelope é retornado quando o documento não pertence ao envelope
+	ErrDocumentNotInEnvelope = errors.New("document does not belong to envelope")
+)
+
 // UsecaseEnvelopeProviderService é um serviço que usa EnvelopeProvider para operações de envelope
 // Esta implementação é agnóstica ao provider específico, permitindo alternar entre Clicksign, vertc-assinaturas, etc.
 type UsecaseEnvelopeProviderService struct {
@@ -84,9 +92,157 @@ func (u *UsecaseEnvelopeProviderService) CreateEnvelope(ctx context.Context, env
 
 // CreateDocument cria um documento dentro de um envelope usando o provider
 func (u *UsecaseEnvelopeProviderService) CreateDocument(ctx context.Context, envelopeKey string, document *entity.EntityDocument, internalEnvelopeID int) (string, error) {
+	if document.IsQuarantined() {
+		return "", entity.ErrDocumentQuarantined
+	}
 	return u.envelopeProvider.CreateDocument(ctx, envelopeKey, document, internalEnvelopeID)
 }
 
+// ReplaceDocument substitui o conteúdo de um documento de um envelope em rascunho
+// No provider o documento anterior é removido e o novo é criado, recriando os requisitos que o referenciavam;
+// localmente a versão anterior vai para o histórico do documento
+func (u *UsecaseEnvelopeProviderService) ReplaceDocument(ctx context.Context, envelopeID int, documentID int, replacement *entity.EntityDocument) (*entity.EntityDocument, error) {
+	envelope, err := u.repositoryEnvelope.GetByID(envelopeID)
+	if err != nil {
+		return nil, fmt.Errorf("envelope not found: %w", err)
+	}
+
+	if err := envelope.CheckDraft(); err != nil {
+		return nil, err
+	}
+
+	if !containsID(envelope.DocumentsIDs, documentID) {
+		return nil, ErrDocumentNotInEnvelope
+	}
+
+	if replacement.IsQuarantined() {
+		return nil, entity.ErrDocumentQuarantined
+	}
+
+	document, err := u.usecaseDocument.GetDocument(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("document not found: %w", err)
+	}
+
+	previousKey := document.ClicksignKey
+	var requirements []entity.EntityRequirement
+	if envelope.ClicksignKey != "" && previousKey != "" {
+		requirements, err = u.documentRequirements(ctx, envelope.ID, previousKey)
+		if err != nil {
+			return nil, err
+		}
+
+		err = u.envelopeProvider.DeleteDocument(ctx, envelope.ClicksignKey, previousKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete document in provider: %w", err)
+		}
+
+		upload := *replacement
+		upload.ID = document.ID
+		upload.Name = document.Name
+		upload.Description = document.Description
+		documentKey, err := u.envelopeProvider.CreateDocument(ctx, envelope.ClicksignKey, &upload, envelope.ID)
+		if err != nil {
+			u.restoreDocument(ctx, envelope, document, requirements)
+			return nil, fmt.Errorf("failed to create document in provider: %w", err)
+		}
+		replacement.ClicksignKey = documentKey
+	} else {
+		replacement.ClicksignKey = previousKey
+	}
+
+	_, err = u.usecaseDocument.ReplaceContent(document, replacement)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.recreateRequirements(ctx, envelope.ClicksignKey, requirements, document.ClicksignKey); err != nil {
+		return document, err
+	}
+
+	return document, nil
+}
+
+// documentRequirements retorna os requisitos do envelope que referenciam o documento no provider
+func (u *UsecaseEnvelopeProviderService) documentRequirements(ctx context.Context, envelopeID int, documentKey string) ([]entity.EntityRequirement, error) {
+	requirements, err := u.usecaseRequirement.GetRequirementsByEnvelopeID(ctx, envelopeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get envelope requirements: %w", err)
+	}
+
+	var documentRequirements []entity.EntityRequirement
+	for _, requirement := range requirements {
+		if requirement.DocumentID != nil && *requirement.DocumentID == documentKey {
+			documentRequirements = append(documentRequirements, requirement)
+		}
+	}
+	return documentRequirements, nil
+}
+
+// recreateRequirements cria no provider os requisitos removidos junto com o documento, agora apontando para documentKey
+func (u *UsecaseEnvelopeProviderService) recreateRequirements(ctx context.Context, envelopeKey string, requirements []entity.EntityRequirement, documentKey string) error {
+	for i := range requirements {
+		requirement := &requirements[i]
+
+		reqData := provider.RequirementData{
+			Action:     requirement.Action,
+			Role:       requirement.Role,
+			DocumentID: documentKey,
+		}
+		if requirement.Auth != nil {
+			reqData.Auth = *requirement.Auth
+		}
+		if requirement.SignerID != nil {
+			reqData.SignerID = *requirement.SignerID
+		}
+
+		requirementKey, err := u.envelopeProvider.CreateRequirement(ctx, envelopeKey, reqData)
+		if err != nil {
+			return fmt.Errorf("failed to recreate requirement %d in provider: %w", requirement.ID, err)
+		}
+
+		key := documentKey
+		requirement.DocumentID = &key
+		requirement.SetClicksignKey(requirementKey)
+		if _, err := u.usecaseRequirement.UpdateRequirement(ctx, requirement); err != nil {
+			return fmt.Errorf("failed to update requirement %d: %w", requirement.ID, err)
+		}
+	}
+	return nil
+}
+
+// restoreDocument recoloca no provider a versão atual quando a nova não pôde ser criada (best effort)
+func (u *UsecaseEnvelopeProviderService) restoreDocument(ctx context.Context, envelope *entity.EntityEnvelope, document *entity.EntityDocument, requirements []entity.EntityRequirement) {
+	logger := u.logger.WithFields(logrus.Fields{
+		"envelope_id": envelope.ID,
+		"document_id": document.ID,
+	})
+
+	documentKey, err := u.envelopeProvider.CreateDocument(ctx, envelope.ClicksignKey, document, envelope.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to restore document in provider after replacement failure")
+		return
+	}
+
+	document.SetClicksignKey(documentKey)
+	if err := u.usecaseDocument.Update(document); err != nil {
+		logger.WithError(err).Error("Failed to update restored document with provider key")
+	}
+
+	if err := u.recreateRequirements(ctx, envelope.ClicksignKey, requirements, documentKey); err != nil {
+		logger.WithError(err).Error("Failed to restore document requirements in provider")
+	}
+}
+
+func containsID(ids []int, id int) bool {
+	for _, value := range ids {
+		if value == id {
+			return true
+		}
+	}
+	return false
+}
+
 // UpdateEnvelope atualiza um envelope
 func (u *UsecaseEnvelopeProviderService) UpdateEnvelope(envelope *entity.EntityEnvelope) error {
 	err := envelope.Validate()
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"app/entity"
+	"app/infrastructure/provider"
 	"app/mocks"
 	usecase_envelope "app/usecase/envelope"
 
@@ -327,3 +328,140 @@ func TestUsecaseEnvelopeProviderService_CreateDocument(t *testing.T) {
 		assert.Equal(t, "doc-key-123", docKey)
 	})
 }
+
+func TestUsecaseEnvelopeProviderService_ReplaceDocument(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIRepositoryEnvelope(ctrl)
+	mockProvider := mocks.NewMockEnvelopeProvider(ctrl)
+	mockDocumentUsecase := mocks.NewMockIUsecaseDocument(ctrl)
+	mockRequirementUsecase := mocks.NewMockIUsecaseRequirement(ctrl)
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	service := usecase_envelope.NewUsecaseEnvelopeProviderService(
+		mockRepo,
+		mockProvider,
+		mockDocumentUsecase,
+		mockRequirementUsecase,
+		logger,
+	)
+
+	draftEnvelope := func() *entity.EntityEnvelope {
+		return &entity.EntityEnvelope{ID: 1, Status: "draft", ClicksignKey: "envelope-key", DocumentsIDs: []int{5}}
+	}
+	sentDocument := func() *entity.EntityDocument {
+		return &entity.EntityDocument{ID: 5, Name: "Contrato", Status: "draft", ClicksignKey: "doc-old"}
+	}
+
+	t.Run("should recreate the document and its requirements at the provider", func(t *testing.T) {
+		// Arrange
+		document := sentDocument()
+		replacement := &entity.EntityDocument{FilePath: "/tmp/new.pdf", MimeType: "application/pdf"}
+		oldKey := "doc-old"
+		otherKey := "doc-other"
+		auth := "email"
+		signerKey := "signer-key"
+
+		mockRepo.EXPECT().GetByID(1).Return(draftEnvelope(), nil)
+		mockDocumentUsecase.EXPECT().GetDocument(5).Return(document, nil)
+		mockRequirementUsecase.EXPECT().GetRequirementsByEnvelopeID(gomock.Any(), 1).Return([]entity.EntityRequirement{
+			{ID: 10, Action: "sign", Role: "sign", Auth: &auth, DocumentID: &oldKey, SignerID: &signerKey},
+			{ID: 11, Action: "sign", Role: "sign", DocumentID: &otherKey, SignerID: &signerKey},
+		}, nil)
+		mockProvider.EXPECT().DeleteDocument(gomock.Any(), "envelope-key", "doc-old").Return(nil)
+		mockProvider.EXPECT().
+			CreateDocument(gomock.Any(), "envelope-key", gomock.Any(), 1).
+			DoAndReturn(func(ctx context.Context, envelopeKey string, upload *entity.EntityDocument, envelopeID int) (string, error) {
+				assert.Equal(t, 5, upload.ID)
+				assert.Equal(t, "Contrato", upload.Name)
+				assert.Equal(t, "/tmp/new.pdf", upload.FilePath)
+				return "doc-new", nil
+			})
+		mockDocumentUsecase.EXPECT().
+			ReplaceContent(document, replacement).
+			DoAndReturn(func(document, replacement *entity.EntityDocument) (*entity.EntityDocumentVersion, error) {
+				return document.ReplaceContent(replacement)
+			})
+		mockProvider.EXPECT().
+			CreateRequirement(gomock.Any(), "envelope-key", provider.RequirementData{Action: "sign", Role: "sign", Auth: "email", DocumentID: "doc-new", SignerID: "signer-key"}).
+			Return("req-new", nil)
+		mockRequirementUsecase.EXPECT().
+			UpdateRequirement(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, requirement *entity.EntityRequirement) (*entity.EntityRequirement, error) {
+				assert.Equal(t, 10, requirement.ID)
+				assert.Equal(t, "doc-new", *requirement.DocumentID)
+				assert.Equal(t, "req-new", requirement.ClicksignKey)
+				return requirement, nil
+			})
+
+		// Act
+		replaced, err := service.ReplaceDocument(context.Background(), 1, 5, replacement)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "doc-new", replaced.ClicksignKey)
+		assert.Equal(t, 2, replaced.CurrentVersion())
+	})
+
+	t.Run("should restore the previous document when the provider rejects the new one", func(t *testing.T) {
+		// Arrange
+		document := sentDocument()
+
+		mockRepo.EXPECT().GetByID(1).Return(draftEnvelope(), nil)
+		mockDocumentUsecase.EXPECT().GetDocument(5).Return(document, nil)
+		mockRequirementUsecase.EXPECT().GetRequirementsByEnvelopeID(gomock.Any(), 1).Return(nil, nil)
+		mockProvider.EXPECT().DeleteDocument(gomock.Any(), "envelope-key", "doc-old").Return(nil)
+		gomock.InOrder(
+			mockProvider.EXPECT().CreateDocument(gomock.Any(), "envelope-key", gomock.Not(document), 1).Return("", errors.New("provider error")),
+			mockProvider.EXPECT().CreateDocument(gomock.Any(), "envelope-key", document, 1).Return("doc-restored", nil),
+		)
+		mockDocumentUsecase.EXPECT().Update(document).Return(nil)
+
+		// Act
+		_, err := service.ReplaceDocument(context.Background(), 1, 5, &entity.EntityDocument{MimeType: "application/pdf"})
+
+		// Assert
+		assert.ErrorContains(t, err, "failed to create document in provider")
+		assert.Equal(t, "doc-restored", document.ClicksignKey)
+		assert.Equal(t, 1, document.CurrentVersion())
+	})
+
+	t.Run("should reject envelopes that left the draft", func(t *testing.T) {
+		// Arrange
+		envelope := draftEnvelope()
+		envelope.Status = "sent"
+		mockRepo.EXPECT().GetByID(1).Return(envelope, nil)
+
+		// Act
+		_, err := service.ReplaceDocument(context.Background(), 1, 5, &entity.EntityDocument{})
+
+		// Assert
+		assert.ErrorIs(t, err, usecase_envelope.ErrEnvelopeNotDraft)
+	})
+
+	t.Run("should reject approved envelopes waiting for activation", func(t *testing.T) {
+		// Arrange
+		envelope := draftEnvelope()
+		envelope.Status = entity.EnvelopeStatusApproved
+		mockRepo.EXPECT().GetByID(1).Return(envelope, nil)
+
+		// Act
+		_, err := service.ReplaceDocument(context.Background(), 1, 5, &entity.EntityDocument{})
+
+		// Assert
+		assert.ErrorIs(t, err, usecase_envelope.ErrEnvelopeNotDraft)
+	})
+
+	t.Run("should reject documents of other envelopes", func(t *testing.T) {
+		// Arrange
+		mockRepo.EXPECT().GetByID(1).Return(draftEnvelope(), nil)
+
+		// Act
+		_, err := service.ReplaceDocument(context.Background(), 1, 6, &entity.EntityDocument{})
+
+		// Assert
+		assert.ErrorIs(t, err, usecase_envelope.ErrDocumentNotInEnvelope)
+	})
+}
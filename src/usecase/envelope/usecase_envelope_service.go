@@ -6,6 +6,8 @@ import (
 
 	"app/entity"
 	"app/infrastructure/clicksign"
+	"app/infrastructure/storage"
+	"app/pkg/fetcher"
 	usecase_document "app/usecase/document"
 	usecase_requirement "app/usecase/requirement"
 
@@ -27,10 +29,12 @@ func NewUsecaseEnvelopeService(
 	clicksignClient clicksign.ClicksignClientInterface,
 	usecaseDocument usecase_document.IUsecaseDocument,
 	usecaseRequirement usecase_requirement.IUsecaseRequirement,
+	documentStorage storage.Storage,
+	documentFetcher *fetcher.Fetcher,
 	logger *logrus.Logger,
 ) *UsecaseEnvelopeService {
 	envelopeService := clicksign.NewEnvelopeService(clicksignClient, logger)
-	documentService := clicksign.NewDocumentService(clicksignClient, logger)
+	documentService := clicksign.NewDocumentService(clicksignClient, documentStorage, documentFetcher, logger)
 
 	return &UsecaseEnvelopeService{
 		repositoryEnvelope: repositoryEnvelope,
@@ -92,6 +96,9 @@ func (u *UsecaseEnvelopeService) CreateEnvelopeWithDocuments(envelope *entity.En
 		if err := doc.Validate(); err != nil {
 			return nil, fmt.Errorf("document %d validation failed: %w", i, err)
 		}
+		if doc.IsQuarantined() {
+			return nil, fmt.Errorf("document %d: %w", i, entity.ErrDocumentQuarantined)
+		}
 	}
 
 	// Criar documentos localmente primeiro
@@ -161,6 +168,9 @@ func (u *UsecaseEnvelopeService) CreateEnvelopeWithDocuments(envelope *entity.En
 }
 
 func (u *UsecaseEnvelopeService) CreateDocument(ctx context.Context, envelopeID string, document *entity.EntityDocument, internalEnvelopeID int) (string, error) {
+	if document.IsQuarantined() {
+		return "", entity.ErrDocumentQuarantined
+	}
 	return u.documentService.CreateDocument(ctx, envelopeID, document, internalEnvelopeID)
 }
 
@@ -28,7 +28,7 @@ func TestUsecaseEnvelopeService_CreateEnvelope(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel) // Reduce log noise in tests
 
-	service := usecase_envelope.NewUsecaseEnvelopeService(mockRepo, mockClicksignClient, mockDocumentUsecase, mockRequirementUsecase, logger)
+	service := usecase_envelope.NewUsecaseEnvelopeService(mockRepo, mockClicksignClient, mockDocumentUsecase, mockRequirementUsecase, nil, nil, logger)
 
 	t.Run("should create envelope successfully", func(t *testing.T) {
 		// Arrange
@@ -188,7 +188,7 @@ func TestUsecaseEnvelopeService_GetEnvelope(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
 
-	service := usecase_envelope.NewUsecaseEnvelopeService(mockRepo, mockClicksignClient, nil, nil, logger)
+	service := usecase_envelope.NewUsecaseEnvelopeService(mockRepo, mockClicksignClient, nil, nil, nil, nil, logger)
 
 	t.Run("should get envelope successfully", func(t *testing.T) {
 		// Arrange
@@ -236,7 +236,7 @@ func TestUsecaseEnvelopeService_GetEnvelopes(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
 
-	service := usecase_envelope.NewUsecaseEnvelopeService(mockRepo, mockClicksignClient, nil, nil, logger)
+	service := usecase_envelope.NewUsecaseEnvelopeService(mockRepo, mockClicksignClient, nil, nil, nil, nil, logger)
 
 	t.Run("should get envelopes successfully", func(t *testing.T) {
 		// Arrange
@@ -291,7 +291,7 @@ func TestUsecaseEnvelopeService_UpdateEnvelope(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
 
-	service := usecase_envelope.NewUsecaseEnvelopeService(mockRepo, mockClicksignClient, nil, nil, logger)
+	service := usecase_envelope.NewUsecaseEnvelopeService(mockRepo, mockClicksignClient, nil, nil, nil, nil, logger)
 
 	t.Run("should update envelope successfully", func(t *testing.T) {
 		// Arrange
@@ -381,7 +381,7 @@ func TestUsecaseEnvelopeService_DeleteEnvelope(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
 
-	service := usecase_envelope.NewUsecaseEnvelopeService(mockRepo, mockClicksignClient, nil, nil, logger)
+	service := usecase_envelope.NewUsecaseEnvelopeService(mockRepo, mockClicksignClient, nil, nil, nil, nil, logger)
 
 	t.Run("should delete envelope successfully", func(t *testing.T) {
 		// Arrange
@@ -449,7 +449,7 @@ func TestUsecaseEnvelopeService_ActivateEnvelope(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
 
-	service := usecase_envelope.NewUsecaseEnvelopeService(mockRepo, mockClicksignClient, nil, nil, logger)
+	service := usecase_envelope.NewUsecaseEnvelopeService(mockRepo, mockClicksignClient, nil, nil, nil, nil, logger)
 
 	t.Run("should activate envelope successfully", func(t *testing.T) {
 		// Arrange
@@ -529,7 +529,7 @@ func TestUsecaseEnvelopeService_ValidateBusinessRules(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
 
-	service := usecase_envelope.NewUsecaseEnvelopeService(mockRepo, mockClicksignClient, mockDocument, mockRequirements, logger)
+	service := usecase_envelope.NewUsecaseEnvelopeService(mockRepo, mockClicksignClient, mockDocument, mockRequirements, nil, nil, logger)
 
 	t.Run("should pass validation with valid envelope", func(t *testing.T) {
 		// Arrange
@@ -607,7 +607,7 @@ func TestUsecaseEnvelopeService_CreateEnvelope_WithClicksignRawData(t *testing.T
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
 
-	service := usecase_envelope.NewUsecaseEnvelopeService(mockRepo, mockClicksignClient, nil, nil, logger)
+	service := usecase_envelope.NewUsecaseEnvelopeService(mockRepo, mockClicksignClient, nil, nil, nil, nil, logger)
 
 	t.Run("should create envelope with raw data successfully", func(t *testing.T) {
 		// Arrange
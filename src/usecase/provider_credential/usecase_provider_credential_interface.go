@@ -0,0 +1,23 @@
+package usecase_provider_credential
+
+import "app/entity"
+
+//go:generate mockgen -destination=../../mocks/mock_usecase_repository_provider_credential.go -package=mocks app/usecase/provider_credential IRepositoryProviderCredential
+type IRepositoryProviderCredential interface {
+	Create(credential *entity.EntityProviderCredential) error
+	GetByID(id int) (*entity.EntityProviderCredential, error)
+	GetByTenantAndProvider(tenantID, providerName string) (*entity.EntityProviderCredential, error)
+	GetByAccountKey(accountKey string) (*entity.EntityProviderCredential, error)
+	GetAll() ([]entity.EntityProviderCredential, error)
+	Update(credential *entity.EntityProviderCredential) error
+	Delete(credential *entity.EntityProviderCredential) error
+}
+
+//go:generate mockgen -destination=../../mocks/mock_usecase_provider_credential.go -package=mocks app/usecase/provider_credential IUsecaseProviderCredential
+type IUsecaseProviderCredential interface {
+	CreateProviderCredential(credential *entity.EntityProviderCredential) (*entity.EntityProviderCredential, error)
+	GetProviderCredential(id int) (*entity.EntityProviderCredential, error)
+	GetAllProviderCredentials() ([]entity.EntityProviderCredential, error)
+	UpdateProviderCredential(credential *entity.EntityProviderCredential) error
+	DeleteProviderCredential(id int) error
+}
@@ -0,0 +1,133 @@
+package usecase_provider_credential
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"app/entity"
+	"app/infrastructure/provider_factory"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrCredentialAlreadyExists indica que o tenant já possui credenciais para o provider
+	ErrCredentialAlreadyExists = errors.New("provider credential already exists for tenant")
+	// ErrAccountKeyInUse indica que a conta do provider já pertence a outra credencial; os webhooks não saberiam o tenant
+	ErrAccountKeyInUse = errors.New("provider account key already in use")
+)
+
+type UsecaseProviderCredentialService struct {
+	repository IRepositoryProviderCredential
+	logger     *logrus.Logger
+}
+
+func NewUsecaseProviderCredentialService(
+	repository IRepositoryProviderCredential,
+	logger *logrus.Logger,
+) *UsecaseProviderCredentialService {
+	return &UsecaseProviderCredentialService{
+		repository: repository,
+		logger:     logger,
+	}
+}
+
+func (u *UsecaseProviderCredentialService) CreateProviderCredential(credential *entity.EntityProviderCredential) (*entity.EntityProviderCredential, error) {
+	if err := validateProviderName(credential.Provider); err != nil {
+		return nil, err
+	}
+
+	existing, err := u.repository.GetByTenantAndProvider(credential.TenantID, credential.Provider)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check existing credential: %w", err)
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("%w: tenant '%s', provider '%s'", ErrCredentialAlreadyExists, credential.TenantID, credential.Provider)
+	}
+	if err := u.checkAccountKey(credential); err != nil {
+		return nil, err
+	}
+
+	if err := u.repository.Create(credential); err != nil {
+		u.logger.WithError(err).Error("Failed to save provider credential to database")
+		return nil, fmt.Errorf("failed to save provider credential: %w", err)
+	}
+
+	u.logger.WithFields(logrus.Fields{
+		"credential_id": credential.ID,
+		"tenant_id":     credential.TenantID,
+		"provider":      credential.Provider,
+	}).Info("Provider credential created successfully")
+
+	return credential, nil
+}
+
+func (u *UsecaseProviderCredentialService) GetProviderCredential(id int) (*entity.EntityProviderCredential, error) {
+	return u.repository.GetByID(id)
+}
+
+func (u *UsecaseProviderCredentialService) GetAllProviderCredentials() ([]entity.EntityProviderCredential, error) {
+	return u.repository.GetAll()
+}
+
+func (u *UsecaseProviderCredentialService) UpdateProviderCredential(credential *entity.EntityProviderCredential) error {
+	if err := validateProviderName(credential.Provider); err != nil {
+		return err
+	}
+
+	if err := credential.Validate(); err != nil {
+		return err
+	}
+	if err := u.checkAccountKey(credential); err != nil {
+		return err
+	}
+
+	if err := u.repository.Update(credential); err != nil {
+		u.logger.WithError(err).WithField("credential_id", credential.ID).Error("Failed to update provider credential")
+		return fmt.Errorf("failed to update provider credential: %w", err)
+	}
+
+	return nil
+}
+
+func (u *UsecaseProviderCredentialService) DeleteProviderCredential(id int) error {
+	credential, err := u.repository.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := u.repository.Delete(credential); err != nil {
+		u.logger.WithError(err).WithField("credential_id", id).Error("Failed to delete provider credential")
+		return fmt.Errorf("failed to delete provider credential: %w", err)
+	}
+
+	return nil
+}
+
+// checkAccountKey garante que cada conta do provider pertença a uma única credencial
+func (u *UsecaseProviderCredentialService) checkAccountKey(credential *entity.EntityProviderCredential) error {
+	if credential.AccountKey == "" {
+		return nil
+	}
+
+	existing, err := u.repository.GetByAccountKey(credential.AccountKey)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to check account key: %w", err)
+	}
+	if existing.ID != credential.ID {
+		return fmt.Errorf("%w: tenant '%s'", ErrAccountKeyInUse, existing.TenantID)
+	}
+	return nil
+}
+
+func validateProviderName(providerName string) error {
+	if _, ok := provider_factory.LookupCapabilities(providerName); !ok {
+		return fmt.Errorf("unsupported provider: '%s'. Supported providers: %s", providerName, strings.Join(provider_factory.SupportedProviderNames(), ", "))
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package usecase_provider_credential
+
+import (
+	"errors"
+	"testing"
+
+	"app/entity"
+	"app/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestUsecaseProviderCredentialService_CreateProviderCredential(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIRepositoryProviderCredential(ctrl)
+	service := NewUsecaseProviderCredentialService(mockRepo, logrus.New())
+
+	t.Run("should create credential for registered provider", func(t *testing.T) {
+		credential := &entity.EntityProviderCredential{TenantID: "unidade-a", Provider: "clicksign", APIKey: "key", Active: true}
+
+		mockRepo.EXPECT().GetByTenantAndProvider("unidade-a", "clicksign").Return(nil, gorm.ErrRecordNotFound)
+		mockRepo.EXPECT().Create(credential).Return(nil)
+
+		created, err := service.CreateProviderCredential(credential)
+
+		require.NoError(t, err)
+		assert.Equal(t, credential, created)
+	})
+
+	t.Run("should reject unsupported provider", func(t *testing.T) {
+		credential := &entity.EntityProviderCredential{TenantID: "unidade-a", Provider: "docusign"}
+
+		_, err := service.CreateProviderCredential(credential)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported provider")
+	})
+
+	t.Run("should reject duplicated tenant and provider", func(t *testing.T) {
+		credential := &entity.EntityProviderCredential{TenantID: "unidade-a", Provider: "vert-sign"}
+
+		mockRepo.EXPECT().GetByTenantAndProvider("unidade-a", "vert-sign").Return(&entity.EntityProviderCredential{ID: 1}, nil)
+
+		_, err := service.CreateProviderCredential(credential)
+
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrCredentialAlreadyExists))
+	})
+
+	t.Run("should reject account key owned by another tenant", func(t *testing.T) {
+		credential := &entity.EntityProviderCredential{TenantID: "unidade-b", Provider: "clicksign", AccountKey: "acc-123"}
+
+		mockRepo.EXPECT().GetByTenantAndProvider("unidade-b", "clicksign").Return(nil, gorm.ErrRecordNotFound)
+		mockRepo.EXPECT().GetByAccountKey("acc-123").Return(&entity.EntityProviderCredential{ID: 1, TenantID: "unidade-a"}, nil)
+
+		_, err := service.CreateProviderCredential(credential)
+
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrAccountKeyInUse))
+	})
+}
+
+func TestUsecaseProviderCredentialService_DeleteProviderCredential(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIRepositoryProviderCredential(ctrl)
+	service := NewUsecaseProviderCredentialService(mockRepo, logrus.New())
+
+	t.Run("should delete existing credential", func(t *testing.T) {
+		credential := &entity.EntityProviderCredential{ID: 3}
+
+		mockRepo.EXPECT().GetByID(3).Return(credential, nil)
+		mockRepo.EXPECT().Delete(credential).Return(nil)
+
+		require.NoError(t, service.DeleteProviderCredential(3))
+	})
+
+	t.Run("should return not found error", func(t *testing.T) {
+		mockRepo.EXPECT().GetByID(4).Return(nil, gorm.ErrRecordNotFound)
+
+		err := service.DeleteProviderCredential(4)
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
@@ -46,6 +46,10 @@ func (u *UsecaseRequirementService) CreateRequirement(ctx context.Context, requi
 		return nil, fmt.Errorf("envelope not found: %w", err)
 	}
 
+	if err := envelope.CheckDraft(); err != nil {
+		return nil, err
+	}
+
 	// Verificar se o envelope tem ClicksignKey
 	if envelope.ClicksignKey == "" {
 		return nil, fmt.Errorf("envelope must be created in Clicksign before adding requirements")
@@ -122,6 +126,10 @@ func (u *UsecaseRequirementService) GetRequirement(ctx context.Context, id int)
 }
 
 func (u *UsecaseRequirementService) UpdateRequirement(ctx context.Context, requirement *entity.EntityRequirement) (*entity.EntityRequirement, error) {
+	if err := u.checkEnvelopeDraft(requirement.EnvelopeID); err != nil {
+		return nil, err
+	}
+
 	updatedRequirement, err := u.repositoryRequirement.Update(ctx, requirement)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update requirement: %w", err)
@@ -137,6 +145,10 @@ func (u *UsecaseRequirementService) DeleteRequirement(ctx context.Context, id in
 		return fmt.Errorf("failed to fetch requirement for deletion: %w", err)
 	}
 
+	if err := u.checkEnvelopeDraft(requirement.EnvelopeID); err != nil {
+		return err
+	}
+
 	err = u.repositoryRequirement.Delete(ctx, requirement)
 	if err != nil {
 		return fmt.Errorf("failed to delete requirement: %w", err)
@@ -144,3 +156,12 @@ func (u *UsecaseRequirementService) DeleteRequirement(ctx context.Context, id in
 
 	return nil
 }
+
+// checkEnvelopeDraft impede alterar requisitos depois que o envelope entrou em aprovação ou foi enviado
+func (u *UsecaseRequirementService) checkEnvelopeDraft(envelopeID int) error {
+	envelope, err := u.repositoryEnvelope.GetByID(envelopeID)
+	if err != nil {
+		return fmt.Errorf("envelope not found: %w", err)
+	}
+	return envelope.CheckDraft()
+}
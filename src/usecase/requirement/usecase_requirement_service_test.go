@@ -59,6 +59,7 @@ func TestUsecaseRequirementService_CreateRequirement(t *testing.T) {
 		envelope := &entity.EntityEnvelope{
 			ID:           1,
 			Name:         "Test Envelope",
+			Status:       "draft",
 			ClicksignKey: "envelope123",
 		}
 
@@ -150,6 +151,7 @@ func TestUsecaseRequirementService_CreateRequirement(t *testing.T) {
 		envelope := &entity.EntityEnvelope{
 			ID:           1,
 			Name:         "Test Envelope",
+			Status:       "draft",
 			ClicksignKey: "", // No Clicksign key
 		}
 
@@ -166,6 +168,21 @@ func TestUsecaseRequirementService_CreateRequirement(t *testing.T) {
 		assert.Contains(t, err.Error(), "envelope must be created in Clicksign")
 	})
 
+	t.Run("should reject envelopes pending approval or approved", func(t *testing.T) {
+		for _, status := range []string{entity.EnvelopeStatusPendingApproval, entity.EnvelopeStatusApproved} {
+			requirement := &entity.EntityRequirement{EnvelopeID: 1, Action: "sign", Role: "sign", Status: "pending"}
+
+			mockRepoEnvelope.EXPECT().
+				GetByID(1).
+				Return(&entity.EntityEnvelope{ID: 1, Status: status, ClicksignKey: "envelope123"}, nil)
+
+			result, err := service.CreateRequirement(ctx, requirement)
+
+			assert.ErrorIs(t, err, entity.ErrEnvelopeNotDraft, status)
+			assert.Nil(t, result)
+		}
+	})
+
 	t.Run("should rollback when Clicksign creation fails", func(t *testing.T) {
 		// Arrange
 		requirement := &entity.EntityRequirement{
@@ -180,6 +197,7 @@ func TestUsecaseRequirementService_CreateRequirement(t *testing.T) {
 		envelope := &entity.EntityEnvelope{
 			ID:           1,
 			Name:         "Test Envelope",
+			Status:       "draft",
 			ClicksignKey: "envelope123",
 		}
 
@@ -366,6 +384,10 @@ func TestUsecaseRequirementService_UpdateRequirement(t *testing.T) {
 			Status:     "completed",
 		}
 
+		mockRepoEnvelope.EXPECT().
+			GetByID(1).
+			Return(&entity.EntityEnvelope{ID: 1, Status: "draft"}, nil)
+
 		mockRepoRequirement.EXPECT().
 			Update(gomock.Any(), requirement).
 			Return(requirement, nil)
@@ -378,6 +400,22 @@ func TestUsecaseRequirementService_UpdateRequirement(t *testing.T) {
 		assert.NotNil(t, result)
 		assert.Equal(t, requirement, result)
 	})
+
+	t.Run("should reject envelopes pending approval or approved", func(t *testing.T) {
+		for _, status := range []string{entity.EnvelopeStatusPendingApproval, entity.EnvelopeStatusApproved} {
+			requirement := &entity.EntityRequirement{ID: 1, EnvelopeID: 1, Action: "sign", Status: "completed"}
+
+			mockRepoEnvelope.EXPECT().
+				GetByID(1).
+				Return(&entity.EntityEnvelope{ID: 1, Status: status}, nil)
+			mockRepoRequirement.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+
+			result, err := service.UpdateRequirement(ctx, requirement)
+
+			assert.ErrorIs(t, err, entity.ErrEnvelopeNotDraft, status)
+			assert.Nil(t, result)
+		}
+	})
 }
 
 func TestUsecaseRequirementService_DeleteRequirement(t *testing.T) {
@@ -407,6 +445,10 @@ func TestUsecaseRequirementService_DeleteRequirement(t *testing.T) {
 			GetByID(gomock.Any(), requirementID).
 			Return(requirement, nil)
 
+		mockRepoEnvelope.EXPECT().
+			GetByID(1).
+			Return(&entity.EntityEnvelope{ID: 1, Status: "draft"}, nil)
+
 		mockRepoRequirement.EXPECT().
 			Delete(gomock.Any(), requirement).
 			Return(nil)
@@ -418,6 +460,24 @@ func TestUsecaseRequirementService_DeleteRequirement(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("should reject envelopes pending approval or approved", func(t *testing.T) {
+		for _, status := range []string{entity.EnvelopeStatusPendingApproval, entity.EnvelopeStatusApproved} {
+			requirement := &entity.EntityRequirement{ID: 2, EnvelopeID: 1, Action: "sign", Status: "pending"}
+
+			mockRepoRequirement.EXPECT().
+				GetByID(gomock.Any(), 2).
+				Return(requirement, nil)
+			mockRepoEnvelope.EXPECT().
+				GetByID(1).
+				Return(&entity.EntityEnvelope{ID: 1, Status: status}, nil)
+			mockRepoRequirement.EXPECT().Delete(gomock.Any(), gomock.Any()).Times(0)
+
+			err := service.DeleteRequirement(ctx, 2)
+
+			assert.ErrorIs(t, err, entity.ErrEnvelopeNotDraft, status)
+		}
+	})
+
 	t.Run("should fail when requirement not found", func(t *testing.T) {
 		// Arrange
 		requirementID := 999
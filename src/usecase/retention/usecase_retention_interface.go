@@ -0,0 +1,36 @@
+package usecase_retention
+
+import (
+	"app/entity"
+	"context"
+	"time"
+)
+
+//go:generate mockgen -destination=../../mocks/mock_usecase_repository_retention.go -package=mocks app/usecase/retention IRepositoryRetention
+type IRepositoryRetention interface {
+	FindEnvelopesWithRawData(before time.Time) ([]int, error)
+	PurgeEnvelopeRawData(ids []int) error
+	FindWebhooksWithPayload(before time.Time) ([]int, error)
+	PurgeWebhookPayloads(ids []int) error
+	FindFinishedEnvelopes(before time.Time) ([]entity.EntityEnvelope, error)
+	FindSignatoriesWithPII(envelopeIDs []int) ([]int, error)
+	AnonymizeSignatories(ids []int, at time.Time) error
+	FindAutoSignatureTermsWithPII(before time.Time) ([]int, error)
+	AnonymizeAutoSignatureTerms(ids []int, at time.Time) error
+	FindDocumentsWithFiles(ids []int) ([]entity.EntityDocument, error)
+	FindDocumentVersions(documentIDs []int) ([]entity.EntityDocumentVersion, error)
+	IsStorageKeyReferenced(key string, excludeDocumentIDs []int) (bool, error)
+	MarkDocumentFilesPurged(ids []int, at time.Time) error
+	CreateRun(run *entity.EntityRetentionRun) error
+	UpdateRun(run *entity.EntityRetentionRun) error
+	GetRuns(limit int) ([]entity.EntityRetentionRun, error)
+	GetRunByID(id int) (*entity.EntityRetentionRun, error)
+}
+
+//go:generate mockgen -destination=../../mocks/mock_usecase_retention.go -package=mocks app/usecase/retention IUsecaseRetention
+type IUsecaseRetention interface {
+	Run(ctx context.Context, trigger string, dryRun bool) (*entity.EntityRetentionRun, error)
+	Policy() entity.RetentionPolicy
+	GetRuns(limit int) ([]entity.EntityRetentionRun, error)
+	GetRun(id int) (*entity.EntityRetentionRun, error)
+}
@@ -0,0 +1,289 @@
+package usecase_retention
+
+import (
+	"app/config"
+	"app/entity"
+	"app/infrastructure/storage"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrRetentionRunning indica que já existe uma execução do expurgo em andamento
+var ErrRetentionRunning = errors.New("retention run already in progress")
+
+// runMu impede que o cron e um disparo manual expurguem ao mesmo tempo
+var runMu sync.Mutex
+
+// retentionRules é a ordem em que as regras são aplicadas
+var retentionRules = []string{
+	entity.RetentionRuleEnvelopeRawData,
+	entity.RetentionRuleWebhookPayload,
+	entity.RetentionRuleSignerPII,
+	entity.RetentionRuleAutoSignatureTermPII,
+	entity.RetentionRuleDocumentFiles,
+}
+
+type UsecaseRetentionService struct {
+	repository IRepositoryRetention
+	storage    storage.Storage
+	policy     entity.RetentionPolicy
+	logger     *logrus.Logger
+	now        func() time.Time
+}
+
+func NewUsecaseRetentionService(
+	repository IRepositoryRetention,
+	documentStorage storage.Storage,
+	policy entity.RetentionPolicy,
+	logger *logrus.Logger,
+) IUsecaseRetention {
+	return &UsecaseRetentionService{
+		repository: repository,
+		storage:    documentStorage,
+		policy:     policy,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// PolicyFromConfig monta a política a partir das variáveis RETENTION_*
+func PolicyFromConfig(envVars config.EnvironmentVars) entity.RetentionPolicy {
+	return entity.RetentionPolicy{
+		EnvelopeRawDataDays: envVars.RETENTION_ENVELOPE_RAW_DATA_DAYS,
+		WebhookPayloadDays:  envVars.RETENTION_WEBHOOK_PAYLOAD_DAYS,
+		SignerPIIDays:       envVars.RETENTION_SIGNER_PII_DAYS,
+		DocumentFileDays:    envVars.RETENTION_DOCUMENT_FILE_DAYS,
+	}
+}
+
+func (u *UsecaseRetentionService) Policy() entity.RetentionPolicy {
+	return u.policy
+}
+
+// Run aplica as regras habilitadas e registra o que foi expurgado
+// Em dry-run apenas levanta os registros que seriam expurgados; a falha de uma regra não interrompe as demais
+func (u *UsecaseRetentionService) Run(ctx context.Context, trigger string, dryRun bool) (*entity.EntityRetentionRun, error) {
+	if !runMu.TryLock() {
+		return nil, ErrRetentionRunning
+	}
+	defer runMu.Unlock()
+
+	run := entity.NewRetentionRun(trigger, dryRun, u.policy)
+	if err := u.repository.CreateRun(run); err != nil {
+		return nil, fmt.Errorf("failed to create retention run: %w", err)
+	}
+
+	now := u.now()
+	for _, rule := range retentionRules {
+		cutoff, enabled := u.policy.Cutoff(rule, now)
+		if !enabled {
+			continue
+		}
+
+		item := entity.RetentionRunItem{Rule: rule, Cutoff: cutoff}
+		var err error
+		switch rule {
+		case entity.RetentionRuleEnvelopeRawData:
+			err = u.purgeEnvelopeRawData(&item, dryRun)
+		case entity.RetentionRuleWebhookPayload:
+			err = u.purgeWebhookPayloads(&item, dryRun)
+		case entity.RetentionRuleSignerPII:
+			err = u.anonymizeSignatories(&item, dryRun, now)
+		case entity.RetentionRuleAutoSignatureTermPII:
+			err = u.anonymizeAutoSignatureTerms(&item, dryRun, now)
+		case entity.RetentionRuleDocumentFiles:
+			err = u.purgeDocumentFiles(ctx, &item, dryRun, now)
+		}
+		if err != nil {
+			item.Error = err.Error()
+		}
+		run.AddItem(item)
+	}
+	run.Finish()
+
+	u.logger.WithFields(logrus.Fields{
+		"retention_run_id": run.ID,
+		"trigger":          run.Trigger,
+		"dry_run":          run.DryRun,
+		"status":           run.Status,
+		"total":            run.Total(),
+	}).Info("Retention run finished")
+
+	if err := u.repository.UpdateRun(run); err != nil {
+		return run, fmt.Errorf("failed to update retention run: %w", err)
+	}
+	return run, nil
+}
+
+func (u *UsecaseRetentionService) purgeEnvelopeRawData(item *entity.RetentionRunItem, dryRun bool) error {
+	ids, err := u.repository.FindEnvelopesWithRawData(item.Cutoff)
+	if err != nil {
+		return fmt.Errorf("failed to find envelopes: %w", err)
+	}
+	item.IDs = ids
+	if dryRun || len(ids) == 0 {
+		return nil
+	}
+
+	if err := u.repository.PurgeEnvelopeRawData(ids); err != nil {
+		return fmt.Errorf("failed to purge envelope raw data: %w", err)
+	}
+	return nil
+}
+
+func (u *UsecaseRetentionService) purgeWebhookPayloads(item *entity.RetentionRunItem, dryRun bool) error {
+	ids, err := u.repository.FindWebhooksWithPayload(item.Cutoff)
+	if err != nil {
+		return fmt.Errorf("failed to find webhooks: %w", err)
+	}
+	item.IDs = ids
+	if dryRun || len(ids) == 0 {
+		return nil
+	}
+
+	if err := u.repository.PurgeWebhookPayloads(ids); err != nil {
+		return fmt.Errorf("failed to purge webhook payloads: %w", err)
+	}
+	return nil
+}
+
+func (u *UsecaseRetentionService) anonymizeSignatories(item *entity.RetentionRunItem, dryRun bool, now time.Time) error {
+	envelopes, err := u.repository.FindFinishedEnvelopes(item.Cutoff)
+	if err != nil {
+		return fmt.Errorf("failed to find finished envelopes: %w", err)
+	}
+	if len(envelopes) == 0 {
+		return nil
+	}
+
+	envelopeIDs := make([]int, 0, len(envelopes))
+	for _, envelope := range envelopes {
+		envelopeIDs = append(envelopeIDs, envelope.ID)
+	}
+
+	ids, err := u.repository.FindSignatoriesWithPII(envelopeIDs)
+	if err != nil {
+		return fmt.Errorf("failed to find signatories: %w", err)
+	}
+	item.IDs = ids
+	if dryRun || len(ids) == 0 {
+		return nil
+	}
+
+	if err := u.repository.AnonymizeSignatories(ids, now); err != nil {
+		return fmt.Errorf("failed to anonymize signatories: %w", err)
+	}
+	return nil
+}
+
+// anonymizeAutoSignatureTerms roda depois de anonymizeSignatories para que o termo de um signatário
+// recém-anonimizado já deixe de ser considerado em uso
+func (u *UsecaseRetentionService) anonymizeAutoSignatureTerms(item *entity.RetentionRunItem, dryRun bool, now time.Time) error {
+	ids, err := u.repository.FindAutoSignatureTermsWithPII(item.Cutoff)
+	if err != nil {
+		return fmt.Errorf("failed to find auto signature terms: %w", err)
+	}
+	item.IDs = ids
+	if dryRun || len(ids) == 0 {
+		return nil
+	}
+
+	if err := u.repository.AnonymizeAutoSignatureTerms(ids, now); err != nil {
+		return fmt.Errorf("failed to anonymize auto signature terms: %w", err)
+	}
+	return nil
+}
+
+// purgeDocumentFiles apaga do storage o conteúdo dos documentos (e das versões anteriores) de envelopes finalizados
+// Chaves ainda usadas por outro documento não expurgado são mantidas, já que o storage deduplica por hash
+func (u *UsecaseRetentionService) purgeDocumentFiles(ctx context.Context, item *entity.RetentionRunItem, dryRun bool, now time.Time) error {
+	envelopes, err := u.repository.FindFinishedEnvelopes(item.Cutoff)
+	if err != nil {
+		return fmt.Errorf("failed to find finished envelopes: %w", err)
+	}
+
+	var documentIDs []int
+	for _, envelope := range envelopes {
+		documentIDs = append(documentIDs, envelope.DocumentsIDs...)
+	}
+	if len(documentIDs) == 0 {
+		return nil
+	}
+
+	documents, err := u.repository.FindDocumentsWithFiles(documentIDs)
+	if err != nil {
+		return fmt.Errorf("failed to find documents: %w", err)
+	}
+	if len(documents) == 0 {
+		return nil
+	}
+
+	ids := make([]int, 0, len(documents))
+	var keys []string
+	for i := range documents {
+		ids = append(ids, documents[i].ID)
+		keys = append(keys, documents[i].StorageKeys()...)
+	}
+
+	versions, err := u.repository.FindDocumentVersions(ids)
+	if err != nil {
+		return fmt.Errorf("failed to find document versions: %w", err)
+	}
+	for i := range versions {
+		keys = append(keys, versions[i].StorageKeys()...)
+	}
+
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		referenced, err := u.repository.IsStorageKeyReferenced(key, ids)
+		if err != nil {
+			return fmt.Errorf("failed to check storage key references: %w", err)
+		}
+		if referenced {
+			continue
+		}
+
+		if !dryRun {
+			if err := u.storage.Delete(ctx, key); err != nil {
+				return fmt.Errorf("failed to delete %s from storage: %w", key, err)
+			}
+		}
+		item.Files++
+	}
+
+	item.IDs = ids
+	if dryRun {
+		return nil
+	}
+
+	if err := u.repository.MarkDocumentFilesPurged(ids, now); err != nil {
+		return fmt.Errorf("failed to mark document files as purged: %w", err)
+	}
+	return nil
+}
+
+func (u *UsecaseRetentionService) GetRuns(limit int) ([]entity.EntityRetentionRun, error) {
+	runs, err := u.repository.GetRuns(limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get retention runs: %w", err)
+	}
+	return runs, nil
+}
+
+func (u *UsecaseRetentionService) GetRun(id int) (*entity.EntityRetentionRun, error) {
+	run, err := u.repository.GetRunByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get retention run: %w", err)
+	}
+	return run, nil
+}
@@ -0,0 +1,202 @@
+package usecase_retention
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"app/config"
+	"app/entity"
+	"app/infrastructure/storage"
+	"app/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var retentionNow = time.Date(2026, 6, 30, 12, 0, 0, 0, time.UTC)
+
+func newTestRetentionService(t *testing.T, repo IRepositoryRetention, policy entity.RetentionPolicy) (*UsecaseRetentionService, storage.Storage) {
+	s, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	service := NewUsecaseRetentionService(repo, s, policy, logger).(*UsecaseRetentionService)
+	service.now = func() time.Time { return retentionNow }
+	return service, s
+}
+
+func putTestObject(t *testing.T, s storage.Storage, key string) {
+	require.NoError(t, s.Put(context.Background(), key, strings.NewReader("%PDF-1.4\n"), -1, "application/pdf"))
+}
+
+func expectRunPersisted(repo *mocks.MockIRepositoryRetention) {
+	repo.EXPECT().CreateRun(gomock.Any()).DoAndReturn(func(run *entity.EntityRetentionRun) error {
+		run.ID = 1
+		return nil
+	})
+	repo.EXPECT().UpdateRun(gomock.Any()).Return(nil)
+}
+
+func TestUsecaseRetentionService_Run(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should purge raw data and payloads and anonymize signers and auto signature terms", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockIRepositoryRetention(ctrl)
+		service, _ := newTestRetentionService(t, repo, entity.RetentionPolicy{
+			EnvelopeRawDataDays: 90,
+			WebhookPayloadDays:  30,
+			SignerPIIDays:       365,
+		})
+
+		expectRunPersisted(repo)
+		repo.EXPECT().FindEnvelopesWithRawData(retentionNow.AddDate(0, 0, -90)).Return([]int{1, 2}, nil)
+		repo.EXPECT().PurgeEnvelopeRawData([]int{1, 2}).Return(nil)
+		repo.EXPECT().FindWebhooksWithPayload(retentionNow.AddDate(0, 0, -30)).Return([]int{}, nil)
+		repo.EXPECT().FindFinishedEnvelopes(retentionNow.AddDate(0, 0, -365)).Return([]entity.EntityEnvelope{{ID: 1}, {ID: 3}}, nil)
+		repo.EXPECT().FindSignatoriesWithPII([]int{1, 3}).Return([]int{10, 11}, nil)
+		signers := repo.EXPECT().AnonymizeSignatories([]int{10, 11}, retentionNow).Return(nil)
+		repo.EXPECT().FindAutoSignatureTermsWithPII(retentionNow.AddDate(0, 0, -365)).Return([]int{7}, nil).After(signers)
+		repo.EXPECT().AnonymizeAutoSignatureTerms([]int{7}, retentionNow).Return(nil)
+
+		run, err := service.Run(ctx, entity.RetentionTriggerCron, false)
+
+		require.NoError(t, err)
+		assert.Equal(t, entity.RetentionRunCompleted, run.Status)
+		require.Len(t, run.Items, 4)
+		assert.Equal(t, 2, run.Items[0].Count)
+		assert.Equal(t, 0, run.Items[1].Count)
+		assert.Equal(t, []int{10, 11}, run.Items[2].IDs)
+		assert.Equal(t, entity.RetentionRuleAutoSignatureTermPII, run.Items[3].Rule)
+		assert.Equal(t, []int{7}, run.Items[3].IDs)
+		assert.Equal(t, 5, run.Total())
+	})
+
+	t.Run("should not anonymize auto signature terms in dry run", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockIRepositoryRetention(ctrl)
+		service, _ := newTestRetentionService(t, repo, entity.RetentionPolicy{SignerPIIDays: 365})
+
+		expectRunPersisted(repo)
+		repo.EXPECT().FindFinishedEnvelopes(gomock.Any()).Return(nil, nil)
+		repo.EXPECT().FindAutoSignatureTermsWithPII(retentionNow.AddDate(0, 0, -365)).Return([]int{7, 8}, nil)
+
+		run, err := service.Run(ctx, entity.RetentionTriggerManual, true)
+
+		require.NoError(t, err)
+		require.Len(t, run.Items, 2)
+		assert.Equal(t, []int{7, 8}, run.Items[1].IDs)
+	})
+
+	t.Run("should only report in dry run", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockIRepositoryRetention(ctrl)
+		service, s := newTestRetentionService(t, repo, entity.RetentionPolicy{EnvelopeRawDataDays: 90, DocumentFileDays: 30})
+		putTestObject(t, s, "originals/sha256/aa")
+
+		expectRunPersisted(repo)
+		repo.EXPECT().FindEnvelopesWithRawData(gomock.Any()).Return([]int{1}, nil)
+		repo.EXPECT().FindFinishedEnvelopes(gomock.Any()).Return([]entity.EntityEnvelope{{ID: 1, DocumentsIDs: []int{5}}}, nil)
+		repo.EXPECT().FindDocumentsWithFiles([]int{5}).Return([]entity.EntityDocument{{ID: 5, StorageKey: "originals/sha256/aa"}}, nil)
+		repo.EXPECT().FindDocumentVersions([]int{5}).Return(nil, nil)
+		repo.EXPECT().IsStorageKeyReferenced("originals/sha256/aa", []int{5}).Return(false, nil)
+
+		run, err := service.Run(ctx, entity.RetentionTriggerManual, true)
+
+		require.NoError(t, err)
+		assert.True(t, run.DryRun)
+		assert.Equal(t, []int{1}, run.Items[0].IDs)
+		assert.Equal(t, []int{5}, run.Items[1].IDs)
+		assert.Equal(t, 1, run.Items[1].Files)
+
+		exists, err := s.Exists(ctx, "originals/sha256/aa")
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("should delete unreferenced document files", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockIRepositoryRetention(ctrl)
+		service, s := newTestRetentionService(t, repo, entity.RetentionPolicy{DocumentFileDays: 30})
+		for _, key := range []string{"originals/sha256/aa", "signed/sha256/bb", "originals/sha256/cc", "originals/sha256/shared"} {
+			putTestObject(t, s, key)
+		}
+
+		expectRunPersisted(repo)
+		repo.EXPECT().FindFinishedEnvelopes(gomock.Any()).Return([]entity.EntityEnvelope{{ID: 1, DocumentsIDs: []int{5, 6}}}, nil)
+		repo.EXPECT().FindDocumentsWithFiles([]int{5, 6}).Return([]entity.EntityDocument{
+			{ID: 5, StorageKey: "originals/sha256/aa", SignedStorageKey: "signed/sha256/bb"},
+			{ID: 6, StorageKey: "originals/sha256/shared"},
+		}, nil)
+		repo.EXPECT().FindDocumentVersions([]int{5, 6}).Return([]entity.EntityDocumentVersion{
+			{DocumentID: 5, Version: 1, StorageKey: "originals/sha256/cc"},
+		}, nil)
+		repo.EXPECT().IsStorageKeyReferenced(gomock.Any(), []int{5, 6}).DoAndReturn(func(key string, _ []int) (bool, error) {
+			return key == "originals/sha256/shared", nil
+		}).Times(4)
+		repo.EXPECT().MarkDocumentFilesPurged([]int{5, 6}, retentionNow).Return(nil)
+
+		run, err := service.Run(ctx, entity.RetentionTriggerCron, false)
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, run.Items[0].Files)
+		for key, want := range map[string]bool{
+			"originals/sha256/aa":     false,
+			"signed/sha256/bb":        false,
+			"originals/sha256/cc":     false,
+			"originals/sha256/shared": true,
+		} {
+			exists, err := s.Exists(ctx, key)
+			require.NoError(t, err)
+			assert.Equal(t, want, exists, key)
+		}
+	})
+
+	t.Run("should record a failing rule and keep going", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockIRepositoryRetention(ctrl)
+		service, _ := newTestRetentionService(t, repo, entity.RetentionPolicy{EnvelopeRawDataDays: 90, WebhookPayloadDays: 30})
+
+		expectRunPersisted(repo)
+		repo.EXPECT().FindEnvelopesWithRawData(gomock.Any()).Return(nil, errors.New("connection refused"))
+		repo.EXPECT().FindWebhooksWithPayload(gomock.Any()).Return([]int{4}, nil)
+		repo.EXPECT().PurgeWebhookPayloads([]int{4}).Return(nil)
+
+		run, err := service.Run(ctx, entity.RetentionTriggerCron, false)
+
+		require.NoError(t, err)
+		assert.Equal(t, entity.RetentionRunFailed, run.Status)
+		assert.Contains(t, run.Error, "envelope_raw_data: failed to find envelopes: connection refused")
+		assert.Equal(t, 1, run.Items[1].Count)
+	})
+
+	t.Run("should refuse overlapping runs", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockIRepositoryRetention(ctrl)
+		service, _ := newTestRetentionService(t, repo, entity.RetentionPolicy{})
+
+		runMu.Lock()
+		defer runMu.Unlock()
+
+		_, err := service.Run(ctx, entity.RetentionTriggerManual, true)
+
+		assert.ErrorIs(t, err, ErrRetentionRunning)
+	})
+}
+
+func TestPolicyFromConfig(t *testing.T) {
+	policy := PolicyFromConfig(config.EnvironmentVars{
+		RETENTION_ENVELOPE_RAW_DATA_DAYS: 90,
+		RETENTION_WEBHOOK_PAYLOAD_DAYS:   30,
+		RETENTION_SIGNER_PII_DAYS:        1825,
+	})
+
+	assert.Equal(t, entity.RetentionPolicy{EnvelopeRawDataDays: 90, WebhookPayloadDays: 30, SignerPIIDays: 1825}, policy)
+}
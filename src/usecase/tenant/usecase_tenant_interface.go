@@ -0,0 +1,23 @@
+package usecase_tenant
+
+import (
+	"app/entity"
+)
+
+//go:generate mockgen -destination=../../mocks/mock_usecase_repository_tenant.go -package=mocks app/usecase/tenant IRepositoryTenant
+type IRepositoryTenant interface {
+	Create(tenant *entity.EntityTenant) error
+	Update(tenant *entity.EntityTenant) error
+	GetByID(id string) (*entity.EntityTenant, error)
+	GetAll() ([]entity.EntityTenant, error)
+}
+
+//go:generate mockgen -destination=../../mocks/mock_usecase_tenant.go -package=mocks app/usecase/tenant IUsecaseTenant
+type IUsecaseTenant interface {
+	CreateTenant(tenant *entity.EntityTenant) error
+	UpdateTenant(tenant *entity.EntityTenant) error
+	GetTenant(id string) (*entity.EntityTenant, error)
+	GetTenants() ([]entity.EntityTenant, error)
+	// RequireActive confirma que o tenant existe e está ativo antes de vincular usuários e clientes a ele
+	RequireActive(id string) error
+}
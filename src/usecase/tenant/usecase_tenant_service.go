@@ -0,0 +1,76 @@
+package usecase_tenant
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"app/entity"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type UsecaseTenantService struct {
+	repository IRepositoryTenant
+	logger     *logrus.Logger
+	now        func() time.Time
+}
+
+func NewUsecaseTenantService(repository IRepositoryTenant, logger *logrus.Logger) *UsecaseTenantService {
+	return &UsecaseTenantService{
+		repository: repository,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+func (u *UsecaseTenantService) CreateTenant(tenant *entity.EntityTenant) error {
+	if _, err := u.repository.GetByID(tenant.ID); err == nil {
+		return entity.ErrTenantExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to get tenant: %w", err)
+	}
+
+	if err := u.repository.Create(tenant); err != nil {
+		return fmt.Errorf("failed to create tenant: %w", err)
+	}
+
+	u.logger.WithField("tenant_id", tenant.ID).Info("Tenant created")
+	return nil
+}
+
+func (u *UsecaseTenantService) UpdateTenant(tenant *entity.EntityTenant) error {
+	if err := tenant.Validate(); err != nil {
+		return err
+	}
+
+	tenant.UpdatedAt = u.now()
+	if err := u.repository.Update(tenant); err != nil {
+		return fmt.Errorf("failed to update tenant: %w", err)
+	}
+	return nil
+}
+
+func (u *UsecaseTenantService) GetTenant(id string) (*entity.EntityTenant, error) {
+	tenant, err := u.repository.GetByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entity.ErrTenantNotFound
+	}
+	return tenant, err
+}
+
+func (u *UsecaseTenantService) GetTenants() ([]entity.EntityTenant, error) {
+	return u.repository.GetAll()
+}
+
+func (u *UsecaseTenantService) RequireActive(id string) error {
+	tenant, err := u.GetTenant(id)
+	if err != nil {
+		return err
+	}
+	if !tenant.Active {
+		return entity.ErrTenantInactive
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package usecase_tenant
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"app/entity"
+	"app/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func newTestTenantService(repo IRepositoryTenant) *UsecaseTenantService {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	return NewUsecaseTenantService(repo, logger)
+}
+
+func TestUsecaseTenantService_CreateTenant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockIRepositoryTenant(ctrl)
+	service := newTestTenantService(repo)
+
+	tenant := &entity.EntityTenant{ID: "acme", Name: "Acme", Active: true}
+
+	t.Run("should create a new tenant", func(t *testing.T) {
+		repo.EXPECT().GetByID("acme").Return(nil, gorm.ErrRecordNotFound)
+		repo.EXPECT().Create(tenant).Return(nil)
+
+		assert.NoError(t, service.CreateTenant(tenant))
+	})
+
+	t.Run("should refuse an existing id", func(t *testing.T) {
+		repo.EXPECT().GetByID("acme").Return(tenant, nil)
+
+		assert.ErrorIs(t, service.CreateTenant(tenant), entity.ErrTenantExists)
+	})
+
+	t.Run("should propagate repository errors", func(t *testing.T) {
+		repo.EXPECT().GetByID("acme").Return(nil, errors.New("connection refused"))
+
+		err := service.CreateTenant(tenant)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, entity.ErrTenantExists)
+	})
+}
+
+func TestUsecaseTenantService_UpdateTenant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockIRepositoryTenant(ctrl)
+	service := newTestTenantService(repo)
+	now := time.Date(2026, 7, 1, 9, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	tenant := &entity.EntityTenant{ID: "acme", Name: "Acme Brasil", Active: false}
+	repo.EXPECT().Update(tenant).Return(nil)
+
+	assert.NoError(t, service.UpdateTenant(tenant))
+	assert.Equal(t, now, tenant.UpdatedAt)
+}
+
+func TestUsecaseTenantService_RequireActive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockIRepositoryTenant(ctrl)
+	service := newTestTenantService(repo)
+
+	t.Run("should accept active tenants", func(t *testing.T) {
+		repo.EXPECT().GetByID("acme").Return(&entity.EntityTenant{ID: "acme", Active: true}, nil)
+
+		assert.NoError(t, service.RequireActive("acme"))
+	})
+
+	t.Run("should refuse inactive tenants", func(t *testing.T) {
+		repo.EXPECT().GetByID("acme").Return(&entity.EntityTenant{ID: "acme"}, nil)
+
+		assert.ErrorIs(t, service.RequireActive("acme"), entity.ErrTenantInactive)
+	})
+
+	t.Run("should refuse unknown tenants", func(t *testing.T) {
+		repo.EXPECT().GetByID("globex").Return(nil, gorm.ErrRecordNotFound)
+
+		assert.ErrorIs(t, service.RequireActive("globex"), entity.ErrTenantNotFound)
+	})
+}
@@ -0,0 +1,310 @@
+package usecase_user
+
+import (
+	"app/config"
+	"app/entity"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultInvitationTTL = 72 * time.Hour
+	defaultResetTTL      = time.Hour
+
+	// passwordResetSendTimeout limita o envio em segundo plano do link de redefinição
+	passwordResetSendTimeout = time.Minute
+)
+
+var (
+	ErrMailerNotConfigured = errors.New("email delivery is not configured")
+	ErrUserAlreadyExists   = errors.New("user already exists")
+)
+
+// AccountSettings define a validade dos links enviados por email e a política das senhas escolhidas por eles
+type AccountSettings struct {
+	Passwords     entity.PasswordPolicy
+	InvitationTTL time.Duration
+	ResetTTL      time.Duration
+	// AppURL é o endereço do frontend que recebe os links de convite e de redefinição de senha
+	AppURL string
+}
+
+// AccountSettingsFromConfig monta as configurações a partir das variáveis APP_URL, USER_INVITATION_TTL_HOURS, PASSWORD_RESET_TTL_MINUTES e PASSWORD_*
+func AccountSettingsFromConfig(envVars config.EnvironmentVars) AccountSettings {
+	settings := AccountSettings{
+		Passwords:     PasswordPolicyFromConfig(envVars),
+		InvitationTTL: time.Duration(envVars.USER_INVITATION_TTL_HOURS) * time.Hour,
+		ResetTTL:      time.Duration(envVars.PASSWORD_RESET_TTL_MINUTES) * time.Minute,
+		AppURL:        envVars.APP_URL,
+	}
+	if settings.InvitationTTL <= 0 {
+		settings.InvitationTTL = defaultInvitationTTL
+	}
+	if settings.ResetTTL <= 0 {
+		settings.ResetTTL = defaultResetTTL
+	}
+	return settings
+}
+
+// UseCaseUserAccount cuida do ciclo de vida da conta: convite, redefinição de senha, desativação e reativação
+type UseCaseUserAccount struct {
+	repo      IRepositoryUser
+	tokenRepo IRepositoryToken
+	mailer    IMailer
+	settings  AccountSettings
+	logger    *logrus.Logger
+	now       func() time.Time
+}
+
+// NewUserAccountService cria o serviço; mailer nil desativa o convite e a redefinição de senha
+func NewUserAccountService(repository IRepositoryUser, tokenRepo IRepositoryToken, mailer IMailer, settings AccountSettings, logger *logrus.Logger) *UseCaseUserAccount {
+	return &UseCaseUserAccount{
+		repo:      repository,
+		tokenRepo: tokenRepo,
+		mailer:    mailer,
+		settings:  settings,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// Invite cadastra o usuário com uma senha aleatória e envia o link para que ele escolha a própria senha
+func (u *UseCaseUserAccount) Invite(ctx context.Context, user *entity.EntityUser) error {
+	if u.mailer == nil {
+		return ErrMailerNotConfigured
+	}
+
+	_, err := u.repo.GetByMail(user.Email)
+	if err == nil {
+		return ErrUserAlreadyExists
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if user.Role == "" && !user.IsAdmin {
+		user.Role = entity.DefaultRole
+	}
+	user.NormalizeRole()
+
+	// Ninguém conhece a senha aleatória: o acesso só começa quando o convite é aceito
+	user.Password = rand.Text()
+	if err := user.GetValidated(); err != nil {
+		return err
+	}
+
+	if err := u.repo.CreateUser(user); err != nil {
+		return err
+	}
+
+	return u.sendInvitation(ctx, user)
+}
+
+// ResendInvitation envia um novo convite; os links enviados antes deixam de valer
+func (u *UseCaseUserAccount) ResendInvitation(ctx context.Context, id int) error {
+	if u.mailer == nil {
+		return ErrMailerNotConfigured
+	}
+
+	user, err := u.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if !user.Active {
+		return entity.ErrUserInactive
+	}
+
+	return u.sendInvitation(ctx, user)
+}
+
+// AcceptInvitation define a senha escolhida pelo convidado
+func (u *UseCaseUserAccount) AcceptInvitation(rawToken, password string) error {
+	user, err := u.consumeToken(rawToken, entity.UserTokenInvitation, password)
+	if err != nil {
+		return err
+	}
+
+	return u.tokenRepo.InvalidateUserTokens(user.ID, entity.UserTokenInvitation, u.now())
+}
+
+// RequestPasswordReset envia o link de redefinição de senha
+// Emails desconhecidos e usuários desativados não recebem nada e não geram erro, para não revelar quais contas existem.
+// Pelo mesmo motivo a emissão do link e o envio acontecem em segundo plano: o tempo de resposta não depende da conta
+func (u *UseCaseUserAccount) RequestPasswordReset(ctx context.Context, email string) error {
+	if u.mailer == nil {
+		return ErrMailerNotConfigured
+	}
+
+	user, err := u.repo.GetByMail(email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !user.Active {
+		return nil
+	}
+
+	// O envio não pode ser cancelado pelo fim da requisição que o originou
+	sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), passwordResetSendTimeout)
+	go func() {
+		defer cancel()
+		if err := u.sendPasswordReset(sendCtx, user); err != nil {
+			u.logger.WithError(err).WithField("user_id", user.ID).Error("Failed to send password reset link")
+		}
+	}()
+	return nil
+}
+
+func (u *UseCaseUserAccount) sendPasswordReset(ctx context.Context, user *entity.EntityUser) error {
+	rawToken, err := u.issueToken(user.ID, entity.UserTokenPasswordReset, u.settings.ResetTTL)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Olá, %s.\n\nRecebemos um pedido para redefinir a sua senha. Para escolher uma nova senha, acesse o link abaixo em até %s:\n\n%s\n\nSe você não fez o pedido, ignore este email; a senha atual continua valendo.\n",
+		user.Name, formatTTL(u.settings.ResetTTL), u.link("/password/reset", rawToken))
+
+	return u.mailer.Send(ctx, user.Email, "Redefinição de senha", body)
+}
+
+// ResetPassword define a nova senha e encerra todas as sessões do usuário
+func (u *UseCaseUserAccount) ResetPassword(rawToken, password string) error {
+	user, err := u.consumeToken(rawToken, entity.UserTokenPasswordReset, password)
+	if err != nil {
+		return err
+	}
+
+	now := u.now()
+	if err := u.tokenRepo.InvalidateUserTokens(user.ID, entity.UserTokenPasswordReset, now); err != nil {
+		return err
+	}
+	return u.revokeSessions(user.ID, now)
+}
+
+// Deactivate bloqueia o acesso do usuário: as sessões abertas e os links ainda não usados deixam de valer
+func (u *UseCaseUserAccount) Deactivate(id int) error {
+	if err := u.repo.SetActive(id, false); err != nil {
+		return err
+	}
+
+	now := u.now()
+	if err := u.revokeSessions(id, now); err != nil {
+		return err
+	}
+	return u.tokenRepo.InvalidateUserTokens(id, "", now)
+}
+
+// Activate devolve o acesso ao usuário desativado; as sessões encerradas na desativação não voltam
+func (u *UseCaseUserAccount) Activate(id int) error {
+	return u.repo.SetActive(id, true)
+}
+
+func (u *UseCaseUserAccount) sendInvitation(ctx context.Context, user *entity.EntityUser) error {
+	rawToken, err := u.issueToken(user.ID, entity.UserTokenInvitation, u.settings.InvitationTTL)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Olá, %s.\n\nVocê foi convidado para acessar a plataforma de assinaturas. Para ativar o seu acesso e escolher a sua senha, acesse o link abaixo em até %s:\n\n%s\n",
+		user.Name, formatTTL(u.settings.InvitationTTL), u.link("/invitation", rawToken))
+
+	return u.mailer.Send(ctx, user.Email, "Convite de acesso", body)
+}
+
+// issueToken invalida os links anteriores com a mesma finalidade e emite um novo
+func (u *UseCaseUserAccount) issueToken(userID int, purpose string, ttl time.Duration) (string, error) {
+	now := u.now()
+	if err := u.tokenRepo.InvalidateUserTokens(userID, purpose, now); err != nil {
+		return "", err
+	}
+
+	token, rawToken, err := entity.NewUserToken(userID, purpose, ttl, now)
+	if err != nil {
+		return "", err
+	}
+	if err := u.tokenRepo.CreateUserToken(token); err != nil {
+		return "", err
+	}
+	return rawToken, nil
+}
+
+// consumeToken valida o token e grava a nova senha
+// A senha é validada antes de marcar o token, para que uma senha fraca não inutilize o link
+func (u *UseCaseUserAccount) consumeToken(rawToken, purpose, password string) (*entity.EntityUser, error) {
+	if rawToken == "" {
+		return nil, entity.ErrInvalidUserToken
+	}
+
+	token, err := u.tokenRepo.GetUserTokenByHash(entity.HashUserToken(rawToken))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entity.ErrInvalidUserToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := u.now()
+	if err := token.CheckUsable(purpose, now); err != nil {
+		return nil, err
+	}
+
+	user, err := u.repo.GetByID(token.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entity.ErrInvalidUserToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !user.Active {
+		return nil, entity.ErrUserInactive
+	}
+
+	if err := u.settings.Passwords.Check(password, user.Email); err != nil {
+		return nil, err
+	}
+
+	// A marcação é condicional: entre duas requisições concorrentes com o mesmo token apenas uma vence
+	used, err := u.tokenRepo.MarkUserTokenUsed(token.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, entity.ErrUserTokenUsed
+	}
+
+	if err := user.UpdatePassword(password); err != nil {
+		return nil, err
+	}
+	if err := u.repo.SetPassword(user.ID, user.Password); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (u *UseCaseUserAccount) revokeSessions(userID int, now time.Time) error {
+	if err := u.tokenRepo.RevokeUserRefreshTokens(userID, now); err != nil {
+		return err
+	}
+	return u.tokenRepo.SetTokensRevokedAt(userID, now)
+}
+
+func (u *UseCaseUserAccount) link(path, rawToken string) string {
+	return u.settings.AppURL + path + "?token=" + url.QueryEscape(rawToken)
+}
+
+func formatTTL(ttl time.Duration) string {
+	if ttl >= time.Hour && ttl%time.Hour == 0 {
+		return fmt.Sprintf("%d horas", int(ttl.Hours()))
+	}
+	return fmt.Sprintf("%d minutos", int(ttl.Minutes()))
+}
@@ -0,0 +1,238 @@
+package usecase_user_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"app/entity"
+	"app/mocks"
+	usecase_user "app/usecase/user"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newAccountService(ctrl *gomock.Controller, withMailer bool) (*usecase_user.UseCaseUserAccount, *mocks.MockIRepositoryUser, *mocks.MockIRepositoryToken, *mocks.MockIMailer) {
+	userRepo := mocks.NewMockIRepositoryUser(ctrl)
+	tokenRepo := mocks.NewMockIRepositoryToken(ctrl)
+	mailer := mocks.NewMockIMailer(ctrl)
+	settings := usecase_user.AccountSettings{
+		Passwords:     entity.PasswordPolicy{MinLength: 10, RequireMixed: true},
+		InvitationTTL: 72 * time.Hour,
+		ResetTTL:      time.Hour,
+		AppURL:        "https://app.empresa.com",
+	}
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+
+	if !withMailer {
+		return usecase_user.NewUserAccountService(userRepo, tokenRepo, nil, settings, logger), userRepo, tokenRepo, mailer
+	}
+	return usecase_user.NewUserAccountService(userRepo, tokenRepo, mailer, settings, logger), userRepo, tokenRepo, mailer
+}
+
+// tokenFromLink extrai o token do link enviado por email
+func tokenFromLink(t *testing.T, body string) string {
+	_, after, found := strings.Cut(body, "?token=")
+	require.True(t, found)
+	return strings.Fields(after)[0]
+}
+
+func TestUseCaseUserAccount_Invite(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should create the user and email a one-time link", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service, userRepo, tokenRepo, mailer := newAccountService(ctrl, true)
+		user := &entity.EntityUser{Name: "Maria", Email: "maria@empresa.com"}
+
+		userRepo.EXPECT().GetByMail("maria@empresa.com").Return(nil, gorm.ErrRecordNotFound)
+		userRepo.EXPECT().CreateUser(user).DoAndReturn(func(user *entity.EntityUser) error {
+			user.ID = 7
+			return nil
+		})
+		tokenRepo.EXPECT().InvalidateUserTokens(7, entity.UserTokenInvitation, gomock.Any()).Return(nil)
+
+		var stored *entity.EntityUserToken
+		tokenRepo.EXPECT().CreateUserToken(gomock.Any()).DoAndReturn(func(token *entity.EntityUserToken) error {
+			stored = token
+			return nil
+		})
+		mailer.EXPECT().Send(ctx, "maria@empresa.com", gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _, _, body string) error {
+			assert.Contains(t, body, "https://app.empresa.com/invitation?token=")
+			assert.Equal(t, stored.TokenHash, entity.HashUserToken(tokenFromLink(t, body)))
+			return nil
+		})
+
+		require.NoError(t, service.Invite(ctx, user))
+		assert.Equal(t, entity.DefaultRole, user.Role)
+		assert.Equal(t, entity.UserTokenInvitation, stored.Purpose)
+		assert.NotEmpty(t, user.Password)
+	})
+
+	t.Run("should reject registered emails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service, userRepo, _, _ := newAccountService(ctrl, true)
+
+		userRepo.EXPECT().GetByMail("maria@empresa.com").Return(&entity.EntityUser{ID: 7}, nil)
+
+		err := service.Invite(ctx, &entity.EntityUser{Name: "Maria", Email: "maria@empresa.com"})
+
+		assert.ErrorIs(t, err, usecase_user.ErrUserAlreadyExists)
+	})
+
+	t.Run("should require the mailer", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service, _, _, _ := newAccountService(ctrl, false)
+
+		err := service.Invite(ctx, &entity.EntityUser{Name: "Maria", Email: "maria@empresa.com"})
+
+		assert.ErrorIs(t, err, usecase_user.ErrMailerNotConfigured)
+	})
+}
+
+func TestUseCaseUserAccount_AcceptInvitation(t *testing.T) {
+	user := &entity.EntityUser{ID: 7, Email: "maria@empresa.com", Active: true}
+
+	t.Run("should set the password and consume the token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service, userRepo, tokenRepo, _ := newAccountService(ctrl, true)
+		token, rawToken, err := entity.NewUserToken(7, entity.UserTokenInvitation, time.Hour, time.Now())
+		require.NoError(t, err)
+		token.ID = 3
+
+		tokenRepo.EXPECT().GetUserTokenByHash(token.TokenHash).Return(token, nil)
+		userRepo.EXPECT().GetByID(7).Return(user, nil)
+		tokenRepo.EXPECT().MarkUserTokenUsed(3, gomock.Any()).Return(true, nil)
+		userRepo.EXPECT().SetPassword(7, gomock.Any()).DoAndReturn(func(_ int, hash string) error {
+			assert.NoError(t, (&entity.EntityUser{Password: hash}).ValidatePassword("Segura2026!"))
+			return nil
+		})
+		tokenRepo.EXPECT().InvalidateUserTokens(7, entity.UserTokenInvitation, gomock.Any()).Return(nil)
+
+		require.NoError(t, service.AcceptInvitation(rawToken, "Segura2026!"))
+	})
+
+	t.Run("should keep the token when the password is weak", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service, userRepo, tokenRepo, _ := newAccountService(ctrl, true)
+		token, rawToken, err := entity.NewUserToken(7, entity.UserTokenInvitation, time.Hour, time.Now())
+		require.NoError(t, err)
+
+		tokenRepo.EXPECT().GetUserTokenByHash(token.TokenHash).Return(token, nil)
+		userRepo.EXPECT().GetByID(7).Return(user, nil)
+
+		err = service.AcceptInvitation(rawToken, "fraca")
+
+		assert.ErrorIs(t, err, entity.ErrWeakPassword)
+	})
+
+	t.Run("should reject unknown, reset and concurrently used tokens", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service, userRepo, tokenRepo, _ := newAccountService(ctrl, true)
+
+		tokenRepo.EXPECT().GetUserTokenByHash(entity.HashUserToken("unknown")).Return(nil, gorm.ErrRecordNotFound)
+		assert.ErrorIs(t, service.AcceptInvitation("unknown", "Segura2026!"), entity.ErrInvalidUserToken)
+
+		reset, rawReset, err := entity.NewUserToken(7, entity.UserTokenPasswordReset, time.Hour, time.Now())
+		require.NoError(t, err)
+		tokenRepo.EXPECT().GetUserTokenByHash(reset.TokenHash).Return(reset, nil)
+		assert.ErrorIs(t, service.AcceptInvitation(rawReset, "Segura2026!"), entity.ErrInvalidUserToken)
+
+		token, rawToken, err := entity.NewUserToken(7, entity.UserTokenInvitation, time.Hour, time.Now())
+		require.NoError(t, err)
+		tokenRepo.EXPECT().GetUserTokenByHash(token.TokenHash).Return(token, nil)
+		userRepo.EXPECT().GetByID(7).Return(user, nil)
+		tokenRepo.EXPECT().MarkUserTokenUsed(token.ID, gomock.Any()).Return(false, nil)
+		assert.ErrorIs(t, service.AcceptInvitation(rawToken, "Segura2026!"), entity.ErrUserTokenUsed)
+	})
+}
+
+func TestUseCaseUserAccount_PasswordReset(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should not reveal unknown or inactive accounts", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service, userRepo, _, _ := newAccountService(ctrl, true)
+
+		userRepo.EXPECT().GetByMail("ninguem@empresa.com").Return(nil, gorm.ErrRecordNotFound)
+		assert.NoError(t, service.RequestPasswordReset(ctx, "ninguem@empresa.com"))
+
+		userRepo.EXPECT().GetByMail("inativo@empresa.com").Return(&entity.EntityUser{ID: 8}, nil)
+		assert.NoError(t, service.RequestPasswordReset(ctx, "inativo@empresa.com"))
+	})
+
+	t.Run("should email the link and end every session on reset", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service, userRepo, tokenRepo, mailer := newAccountService(ctrl, true)
+		user := &entity.EntityUser{ID: 7, Name: "Maria", Email: "maria@empresa.com", Active: true}
+
+		var stored *entity.EntityUserToken
+		var rawToken string
+		userRepo.EXPECT().GetByMail("maria@empresa.com").Return(user, nil)
+		tokenRepo.EXPECT().InvalidateUserTokens(7, entity.UserTokenPasswordReset, gomock.Any()).Return(nil)
+		tokenRepo.EXPECT().CreateUserToken(gomock.Any()).DoAndReturn(func(token *entity.EntityUserToken) error {
+			stored = token
+			return nil
+		})
+		sent := make(chan struct{})
+		mailer.EXPECT().Send(gomock.Any(), "maria@empresa.com", gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _, _, body string) error {
+			defer close(sent)
+			assert.Contains(t, body, "https://app.empresa.com/password/reset?token=")
+			rawToken = tokenFromLink(t, body)
+			return nil
+		})
+
+		// O pedido responde sem esperar o envio, mesmo com a requisição já encerrada
+		requestCtx, cancel := context.WithCancel(ctx)
+		require.NoError(t, service.RequestPasswordReset(requestCtx, "maria@empresa.com"))
+		cancel()
+		select {
+		case <-sent:
+		case <-time.After(5 * time.Second):
+			t.Fatal("password reset link was not sent")
+		}
+		assert.Equal(t, entity.UserTokenPasswordReset, stored.Purpose)
+
+		tokenRepo.EXPECT().GetUserTokenByHash(entity.HashUserToken(rawToken)).Return(stored, nil)
+		userRepo.EXPECT().GetByID(7).Return(user, nil)
+		tokenRepo.EXPECT().MarkUserTokenUsed(stored.ID, gomock.Any()).Return(true, nil)
+		userRepo.EXPECT().SetPassword(7, gomock.Any()).Return(nil)
+		tokenRepo.EXPECT().InvalidateUserTokens(7, entity.UserTokenPasswordReset, gomock.Any()).Return(nil)
+		tokenRepo.EXPECT().RevokeUserRefreshTokens(7, gomock.Any()).Return(nil)
+		tokenRepo.EXPECT().SetTokensRevokedAt(7, gomock.Any()).Return(nil)
+
+		require.NoError(t, service.ResetPassword(rawToken, "Segura2026!"))
+	})
+}
+
+func TestUseCaseUserAccount_Deactivate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	service, userRepo, tokenRepo, _ := newAccountService(ctrl, false)
+
+	t.Run("should end sessions and pending links", func(t *testing.T) {
+		userRepo.EXPECT().SetActive(7, false).Return(nil)
+		tokenRepo.EXPECT().RevokeUserRefreshTokens(7, gomock.Any()).Return(nil)
+		tokenRepo.EXPECT().SetTokensRevokedAt(7, gomock.Any()).Return(nil)
+		tokenRepo.EXPECT().InvalidateUserTokens(7, "", gomock.Any()).Return(nil)
+
+		require.NoError(t, service.Deactivate(7))
+	})
+
+	t.Run("should not touch tokens of unknown users", func(t *testing.T) {
+		userRepo.EXPECT().SetActive(9, false).Return(gorm.ErrRecordNotFound)
+
+		assert.ErrorIs(t, service.Deactivate(9), gorm.ErrRecordNotFound)
+	})
+
+	t.Run("should reactivate", func(t *testing.T) {
+		userRepo.EXPECT().SetActive(7, true).Return(nil)
+
+		require.NoError(t, service.Activate(7))
+	})
+}
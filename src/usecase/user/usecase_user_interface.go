@@ -1,6 +1,10 @@
 package usecase_user
 
-import "app/entity"
+import (
+	"app/entity"
+	"context"
+	"time"
+)
 
 //go:generate mockgen -destination=../../mocks/mock_usecase_repository_user.go -package=mocks app/usecase/user IRepositoryUser
 type IRepositoryUser interface {
@@ -12,6 +16,49 @@ type IRepositoryUser interface {
 	GetUsers(filters entity.EntityUserFilters) (users []entity.EntityUser, err error)
 	GetUsersFromIDs(ids []int) (users []entity.EntityUser, err error)
 	GetUser(id int) (user *entity.EntityUser, err error)
+	GetByExternalID(externalID string) (user *entity.EntityUser, err error)
+	// SetPassword grava o hash da senha; UpdateUser não altera a senha
+	SetPassword(id int, passwordHash string) error
+	// SetActive ativa ou desativa o usuário; UpdateUser não altera a situação
+	SetActive(id int, active bool) error
+}
+
+//go:generate mockgen -destination=../../mocks/mock_usecase_repository_token.go -package=mocks app/usecase/user IRepositoryToken
+type IRepositoryToken interface {
+	CreateRefreshToken(token *entity.EntityRefreshToken) error
+	GetRefreshTokenByHash(hash string) (*entity.EntityRefreshToken, error)
+	// MarkRefreshTokenRotated retorna false se o token já tinha sido rotacionado ou revogado por outra requisição
+	MarkRefreshTokenRotated(id int, at time.Time) (bool, error)
+	RevokeRefreshTokenFamily(familyID string, at time.Time) error
+	RevokeUserRefreshTokens(userID int, at time.Time) error
+	RevokeToken(token *entity.EntityRevokedToken) error
+	IsTokenRevoked(jti string) (bool, error)
+	SetTokensRevokedAt(userID int, at time.Time) error
+	DeleteExpiredTokens(before time.Time) (int64, error)
+	CreateUserToken(token *entity.EntityUserToken) error
+	GetUserTokenByHash(hash string) (*entity.EntityUserToken, error)
+	// MarkUserTokenUsed retorna false se o token já tinha sido usado por outra requisição
+	MarkUserTokenUsed(id int, at time.Time) (bool, error)
+	InvalidateUserTokens(userID int, purpose string, at time.Time) error
+}
+
+//go:generate mockgen -destination=../../mocks/mock_usecase_mailer.go -package=mocks app/usecase/user IMailer
+type IMailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+//go:generate mockgen -destination=../../mocks/mock_usecase_identity_verifier.go -package=mocks app/usecase/user IIdentityVerifier
+type IIdentityVerifier interface {
+	Issuer() string
+	Verify(token string) (*entity.ExternalIdentity, error)
+}
+
+//go:generate mockgen -destination=../../mocks/mock_usecase_login_attempts.go -package=mocks app/usecase/user ILoginAttempts
+type ILoginAttempts interface {
+	// Locked informa se a conta está bloqueada por falhas seguidas de login
+	Locked(email string) (bool, error)
+	Failed(email string) error
+	Succeeded(email string) error
 }
 
 //go:generate mockgen -destination=../../mocks/mock_usecase_user.go -package=mocks app/usecase/user IUsecaseUser
@@ -25,4 +72,19 @@ type IUsecaseUser interface {
 	GetUsers(filters entity.EntityUserFilters) (users []entity.EntityUser, err error)
 	GetUsersFromIDs(ids []int) (users []entity.EntityUser, err error)
 	GetUser(id int) (user *entity.EntityUser, err error)
+	IssueTokens(user *entity.EntityUser) (*TokenPair, error)
+	RefreshTokens(refreshToken string) (*TokenPair, error)
+	Logout(accessToken, refreshToken string, allSessions bool) error
+	DeleteExpiredTokens() (int64, error)
+}
+
+//go:generate mockgen -destination=../../mocks/mock_usecase_user_account.go -package=mocks app/usecase/user IUsecaseUserAccount
+type IUsecaseUserAccount interface {
+	Invite(ctx context.Context, user *entity.EntityUser) error
+	ResendInvitation(ctx context.Context, id int) error
+	AcceptInvitation(rawToken, password string) error
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(rawToken, password string) error
+	Deactivate(id int) error
+	Activate(id int) error
 }
@@ -3,13 +3,28 @@ package usecase_user
 import (
 	"app/config"
 	"app/entity"
+	"app/infrastructure/jwtkeys"
 	"errors"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
-const SECRET_KEY = "9an0afx$thw)k9#y*_d9-ch^r&a6ndi#x#dwu^52zbqw=hso(9"
+const (
+	defaultAccessTokenTTL  = 24 * time.Hour
+	defaultRefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// ErrTokenRevocationDisabled indica um serviço criado sem o repositório de tokens
+var ErrTokenRevocationDisabled = errors.New("token revocation is not configured")
+
+// provisionMu evita criar o mesmo usuário duas vezes em acessos simultâneos de uma identidade nova
+var provisionMu sync.Mutex
 
 type SignedDetails struct {
 	ID    int
@@ -18,31 +33,158 @@ type SignedDetails struct {
 	jwt.StandardClaims
 }
 
+// TokenPair é o par entregue no login e no refresh
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	// ExpiresIn é a validade do access token em segundos
+	ExpiresIn int64
+}
+
+// IdentityPolicy define como as identidades do provedor OIDC viram usuários locais
+type IdentityPolicy struct {
+	// AdminGroups vazio mantém o perfil de administrador definido localmente
+	AdminGroups []string
+	// AllowedGroups vazio libera qualquer identidade do provedor
+	AllowedGroups   []string
+	AutoProvision   bool
+	AllowLocalLogin bool
+	// LinkByEmail vincula a identidade ao usuário local já existente com o mesmo email (verificado pelo provedor)
+	LinkByEmail bool
+	// BootstrapAdmin é o administrador criado por CreateAdminUser: mantém o login local, nunca perde o perfil
+	// e nunca é vinculado a uma identidade do provedor
+	BootstrapAdmin string
+}
+
+// IdentityPolicyFromConfig monta a política a partir das variáveis OIDC_*
+func IdentityPolicyFromConfig(envVars config.EnvironmentVars) IdentityPolicy {
+	return IdentityPolicy{
+		AdminGroups:     splitList(envVars.OIDC_ADMIN_GROUPS),
+		AllowedGroups:   splitList(envVars.OIDC_ALLOWED_GROUPS),
+		AutoProvision:   envVars.OIDC_AUTO_PROVISION,
+		AllowLocalLogin: envVars.OIDC_ALLOW_LOCAL_LOGIN,
+		LinkByEmail:     envVars.OIDC_LINK_BY_EMAIL,
+		BootstrapAdmin:  envVars.DEFAULT_ADMIN_MAIL,
+	}
+}
+
+// PasswordPolicyFromConfig monta a política de senhas a partir das variáveis PASSWORD_*
+func PasswordPolicyFromConfig(envVars config.EnvironmentVars) entity.PasswordPolicy {
+	return entity.PasswordPolicy{
+		MinLength:    envVars.PASSWORD_MIN_LENGTH,
+		RequireMixed: envVars.PASSWORD_REQUIRE_MIXED,
+	}
+}
+
 type UseCaseUser struct {
-	repo IRepositoryUser
+	repo       IRepositoryUser
+	tokenRepo  IRepositoryToken
+	keys       *jwtkeys.KeySet
+	identity   IIdentityVerifier
+	attempts   ILoginAttempts
+	policy     IdentityPolicy
+	passwords  entity.PasswordPolicy
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	issuer     string
+	now        func() time.Time
+}
+
+// NewService cria o serviço sem refresh tokens nem revogação, para quem só precisa dos dados de usuário
+func NewService(repository IRepositoryUser, keys *jwtkeys.KeySet) *UseCaseUser {
+	return NewServiceWithTokens(repository, nil, keys, nil)
 }
 
-func NewService(repository IRepositoryUser) *UseCaseUser {
-	return &UseCaseUser{repo: repository}
+// NewServiceWithTokens cria o serviço que emite, renova e revoga tokens
+// identity nil desativa os tokens do provedor OIDC
+func NewServiceWithTokens(repository IRepositoryUser, tokenRepo IRepositoryToken, keys *jwtkeys.KeySet, identity IIdentityVerifier) *UseCaseUser {
+	accessTTL := time.Duration(config.EnvironmentVariables.JWT_ACCESS_TOKEN_TTL_MINUTES) * time.Minute
+	if accessTTL <= 0 {
+		accessTTL = defaultAccessTokenTTL
+	}
+	refreshTTL := time.Duration(config.EnvironmentVariables.JWT_REFRESH_TOKEN_TTL_HOURS) * time.Hour
+	if refreshTTL <= 0 {
+		refreshTTL = defaultRefreshTokenTTL
+	}
+
+	return &UseCaseUser{
+		repo:       repository,
+		tokenRepo:  tokenRepo,
+		keys:       keys,
+		identity:   identity,
+		policy:     IdentityPolicyFromConfig(config.EnvironmentVariables),
+		passwords:  PasswordPolicyFromConfig(config.EnvironmentVariables),
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+		issuer:     config.EnvironmentVariables.JWT_ISSUER,
+		now:        time.Now,
+	}
 }
 
 func (u *UseCaseUser) LoginUser(email string, password string) (*entity.EntityUser, error) {
+	if u.identity != nil && !u.policy.AllowLocalLogin && !strings.EqualFold(email, u.policy.BootstrapAdmin) {
+		return nil, entity.ErrLocalLoginDisabled
+	}
+
+	if u.attempts != nil {
+		locked, err := u.attempts.Locked(email)
+		if err != nil {
+			return nil, err
+		}
+		if locked {
+			return nil, entity.ErrAccountLocked
+		}
+	}
+
 	user, err := u.repo.GetByMail(email)
 
 	if err != nil {
+		u.loginFailed(email)
 		return nil, err
 	}
 
 	err = user.ValidatePassword(password)
 
 	if err != nil {
+		u.loginFailed(email)
 		return nil, err
 	}
 
+	// A senha certa de um usuário desativado não conta como falha, mas também não entra
+	if !user.Active {
+		return nil, entity.ErrUserInactive
+	}
+
+	if u.attempts != nil {
+		if err := u.attempts.Succeeded(email); err != nil {
+			return nil, err
+		}
+	}
+
 	return user, nil
 }
 
+// SetLoginAttempts ativa o bloqueio do login depois de falhas seguidas
+func (u *UseCaseUser) SetLoginAttempts(attempts ILoginAttempts) {
+	u.attempts = attempts
+}
+
+// loginFailed conta a falha; um erro do store não muda a resposta do login, que já é de credenciais inválidas
+func (u *UseCaseUser) loginFailed(email string) {
+	if u.attempts != nil {
+		_ = u.attempts.Failed(email)
+	}
+}
+
 func (u *UseCaseUser) Create(user *entity.EntityUser) error {
+	if user.Role == "" && !user.IsAdmin {
+		user.Role = entity.DefaultRole
+	}
+	user.NormalizeRole()
+
+	if err := u.passwords.Check(user.Password, user.Email); err != nil {
+		return err
+	}
 
 	err := user.GetValidated()
 
@@ -54,6 +196,21 @@ func (u *UseCaseUser) Create(user *entity.EntityUser) error {
 }
 
 func (u *UseCaseUser) Update(user *entity.EntityUser) error {
+	// Atualizações sem papel mantêm o papel e as permissões atuais
+	if user.Role == "" && !user.IsAdmin {
+		if current, err := u.repo.GetByMail(user.Email); err == nil {
+			user.Role = current.Role
+			if user.Permissions == nil {
+				user.Permissions = current.Permissions
+			}
+		}
+	}
+	user.NormalizeRole()
+
+	if err := user.ValidateRole(); err != nil {
+		return err
+	}
+
 	return u.repo.UpdateUser(user)
 }
 
@@ -62,18 +219,41 @@ func (u *UseCaseUser) Delete(user *entity.EntityUser) error {
 }
 
 func (u *UseCaseUser) GetUserByToken(token string) (*entity.EntityUser, error) {
-	claims, err := ValidateToken(token)
+	if u.isExternalToken(token) {
+		return u.authenticateExternal(token)
+	}
+
+	claims, err := u.parseAccessToken(token)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if u.tokenRepo != nil && claims.Id != "" {
+		revoked, err := u.tokenRepo.IsTokenRevoked(claims.Id)
+		if err != nil {
+			return nil, err
+		}
+		if revoked {
+			return nil, entity.ErrTokenRevoked
+		}
+	}
+
 	user, err := u.repo.GetByID(claims.ID)
 
 	if err != nil {
 		return nil, err
 	}
 
+	// Logout de todas as sessões: tokens emitidos antes do instante da revogação deixam de valer
+	if user.TokensRevokedAt != nil && claims.IssuedAt < user.TokensRevokedAt.Unix() {
+		return nil, entity.ErrTokenRevoked
+	}
+
+	if !user.Active {
+		return nil, entity.ErrUserInactive
+	}
+
 	return user, nil
 }
 
@@ -95,17 +275,15 @@ func (u *UseCaseUser) UpdatePassword(id int, oldPassword, newPassword, confirmPa
 		return errors.New("passwords do not match")
 	}
 
-	user.UpdatePassword(newPassword)
-
-	err = user.GetValidated()
-
-	if err != nil {
+	if err := u.passwords.Check(newPassword, user.Email); err != nil {
 		return err
 	}
 
-	err = u.repo.UpdateUser(user)
+	if err := user.UpdatePassword(newPassword); err != nil {
+		return err
+	}
 
-	return err
+	return u.repo.SetPassword(user.ID, user.Password)
 }
 
 func (u *UseCaseUser) GetUsersFromIDs(ids []int) (users []entity.EntityUser, err error) {
@@ -119,64 +297,281 @@ func (u *UseCaseUser) GetUser(id int) (user *entity.EntityUser, err error) {
 	return u.repo.GetUser(id)
 }
 
-func JWTTokenGenerator(u entity.EntityUser) (signedToken string, signedRefreshToken string, err error) {
+// IssueTokens emite o access token e inicia uma nova família de refresh tokens
+func (u *UseCaseUser) IssueTokens(user *entity.EntityUser) (*TokenPair, error) {
+	return u.issueTokens(user, uuid.NewString())
+}
 
-	claims := SignedDetails{
-		ID:    int(u.ID),
-		Name:  u.Name,
-		Email: u.Email,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(time.Hour * 24).Unix(),
-		},
+// RefreshTokens troca o refresh token por um novo par; o token apresentado não pode ser usado de novo
+// Reapresentar um token já trocado indica vazamento e revoga toda a família
+func (u *UseCaseUser) RefreshTokens(refreshToken string) (*TokenPair, error) {
+	if u.tokenRepo == nil {
+		return nil, ErrTokenRevocationDisabled
+	}
+	if refreshToken == "" {
+		return nil, entity.ErrInvalidRefreshToken
 	}
 
-	refreshClaims := SignedDetails{
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(time.Hour * 24 * 7 * 365).Unix(),
-		},
+	stored, err := u.tokenRepo.GetRefreshTokenByHash(entity.HashRefreshToken(refreshToken))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entity.ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := u.now()
+	if err := stored.CheckUsable(now); err != nil {
+		if errors.Is(err, entity.ErrRefreshTokenReused) {
+			return nil, u.revokeFamily(stored.FamilyID, now, err)
+		}
+		return nil, err
 	}
 
-	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(SECRET_KEY))
+	// A marcação é condicional: entre duas requisições concorrentes com o mesmo token apenas uma vence
+	rotated, err := u.tokenRepo.MarkRefreshTokenRotated(stored.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		return nil, u.revokeFamily(stored.FamilyID, now, entity.ErrRefreshTokenReused)
+	}
 
+	user, err := u.repo.GetByID(stored.UserID)
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
+	if !user.Active {
+		return nil, entity.ErrUserInactive
+	}
+
+	return u.issueTokens(user, stored.FamilyID)
+}
 
-	refreshToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims).SignedString([]byte(SECRET_KEY))
+// Logout revoga o access token e a família do refresh token informado
+// Com allSessions, revoga também os demais refresh tokens e access tokens já emitidos para o usuário
+func (u *UseCaseUser) Logout(accessToken, refreshToken string, allSessions bool) error {
+	if u.tokenRepo == nil {
+		return ErrTokenRevocationDisabled
+	}
+	if u.isExternalToken(accessToken) {
+		return entity.ErrExternalIdentity
+	}
 
+	claims, err := u.parseAccessToken(accessToken)
 	if err != nil {
-		return "", "", err
+		return err
+	}
+
+	now := u.now()
+	if claims.Id != "" {
+		err = u.tokenRepo.RevokeToken(&entity.EntityRevokedToken{
+			JTI:       claims.Id,
+			UserID:    claims.ID,
+			ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+			RevokedAt: now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if refreshToken != "" {
+		stored, err := u.tokenRepo.GetRefreshTokenByHash(entity.HashRefreshToken(refreshToken))
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err == nil && stored.UserID == claims.ID {
+			if err := u.tokenRepo.RevokeRefreshTokenFamily(stored.FamilyID, now); err != nil {
+				return err
+			}
+		}
 	}
 
-	return token, refreshToken, nil
+	if !allSessions {
+		return nil
+	}
+	if err := u.tokenRepo.RevokeUserRefreshTokens(claims.ID, now); err != nil {
+		return err
+	}
+	return u.tokenRepo.SetTokensRevokedAt(claims.ID, now)
 }
 
-func ValidateToken(signedToken string) (claims *SignedDetails, err error) {
-	token, err := jwt.ParseWithClaims(
-		signedToken,
-		&SignedDetails{},
-		func(token *jwt.Token) (interface{}, error) {
-			return []byte(SECRET_KEY), nil
+// DeleteExpiredTokens remove refresh tokens e revogações que já expiraram
+func (u *UseCaseUser) DeleteExpiredTokens() (int64, error) {
+	if u.tokenRepo == nil {
+		return 0, ErrTokenRevocationDisabled
+	}
+	return u.tokenRepo.DeleteExpiredTokens(u.now())
+}
+
+func (u *UseCaseUser) issueTokens(user *entity.EntityUser, familyID string) (*TokenPair, error) {
+	now := u.now()
+
+	accessToken, err := u.keys.Sign(SignedDetails{
+		ID:    user.ID,
+		Name:  user.Name,
+		Email: user.Email,
+		StandardClaims: jwt.StandardClaims{
+			Id:        uuid.NewString(),
+			Subject:   strconv.Itoa(user.ID),
+			Issuer:    u.issuer,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(u.accessTTL).Unix(),
 		},
-	)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pair := &TokenPair{AccessToken: accessToken, ExpiresIn: int64(u.accessTTL.Seconds())}
+	if u.tokenRepo == nil {
+		return pair, nil
+	}
+
+	refresh, rawRefresh, err := entity.NewRefreshToken(user.ID, familyID, u.refreshTTL, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.tokenRepo.CreateRefreshToken(refresh); err != nil {
+		return nil, err
+	}
+	pair.RefreshToken = rawRefresh
+
+	return pair, nil
+}
+
+func (u *UseCaseUser) parseAccessToken(token string) (*SignedDetails, error) {
+	claims := &SignedDetails{}
+	if err := u.keys.Parse(token, claims); err != nil {
+		return nil, err
+	}
+
+	if u.issuer != "" && !claims.VerifyIssuer(u.issuer, false) {
+		return nil, errors.New("invalid token issuer")
+	}
+
+	return claims, nil
+}
+
+// isExternalToken identifica pelo iss, ainda sem validar a assinatura, os tokens emitidos pelo provedor OIDC
+func (u *UseCaseUser) isExternalToken(token string) bool {
+	if u.identity == nil {
+		return false
+	}
+
+	claims := jwt.MapClaims{}
+	if _, _, err := new(jwt.Parser).ParseUnverified(token, claims); err != nil {
+		return false
+	}
+	issuer, _ := claims["iss"].(string)
+	return issuer != "" && issuer == u.identity.Issuer()
+}
 
+// authenticateExternal valida o token no provedor e retorna o usuário local da identidade, criado no primeiro acesso
+func (u *UseCaseUser) authenticateExternal(token string) (*entity.EntityUser, error) {
+	identity, err := u.identity.Verify(token)
 	if err != nil {
+		return nil, err
+	}
+	if len(u.policy.AllowedGroups) > 0 && !identity.InAnyGroup(u.policy.AllowedGroups) {
+		return nil, entity.ErrIdentityNotAllowed
+	}
 
+	user, err := u.repo.GetByExternalID(identity.ExternalID())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		user, err = u.linkOrProvision(identity)
+	}
+	if err != nil {
 		return nil, err
 	}
 
-	claims, ok := token.Claims.(*SignedDetails)
-	if !ok {
+	if !user.Active {
+		return nil, entity.ErrUserInactive
+	}
 
+	if len(u.policy.AdminGroups) > 0 {
+		isAdmin := identity.InAnyGroup(u.policy.AdminGroups) || u.isBootstrapAdmin(user)
+		if user.IsAdmin != isAdmin {
+			user.SetAdmin(isAdmin)
+			if err := u.repo.UpdateUser(user); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return user, nil
+}
+
+// linkOrProvision vincula a identidade ao usuário local com o mesmo email, se permitido, ou cria um novo
+func (u *UseCaseUser) linkOrProvision(identity *entity.ExternalIdentity) (*entity.EntityUser, error) {
+	if identity.Email == "" {
+		return nil, entity.ErrUserNotProvisioned
+	}
+	if !identity.EmailVerified {
+		return nil, entity.ErrUnverifiedEmail
+	}
+
+	provisionMu.Lock()
+	defer provisionMu.Unlock()
+
+	user, err := u.repo.GetByMail(identity.Email)
+	if err == nil {
+		if user.ExternalID != "" && user.ExternalID != identity.ExternalID() {
+			return nil, entity.ErrIdentityNotAllowed
+		}
+		// O email é a única ligação entre as contas: vincular o administrador padrão entregaria a plataforma a
+		// quem registrar esse email no provedor
+		if u.isBootstrapAdmin(user) {
+			return nil, entity.ErrIdentityNotAllowed
+		}
+		if !u.policy.LinkByEmail {
+			return nil, entity.ErrIdentityNotLinked
+		}
+		user.ExternalID = identity.ExternalID()
+		if err := u.repo.UpdateUser(user); err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, err
 	}
 
-	if claims.ExpiresAt < time.Now().Local().Unix() {
+	if !u.policy.AutoProvision {
+		return nil, entity.ErrUserNotProvisioned
+	}
 
+	user, err = entity.NewUserFromIdentity(identity, identity.InAnyGroup(u.policy.AdminGroups))
+	if err != nil {
+		return nil, err
+	}
+	if err := u.repo.CreateUser(user); err != nil {
 		return nil, err
 	}
+	return user, nil
+}
+
+func (u *UseCaseUser) isBootstrapAdmin(user *entity.EntityUser) bool {
+	return u.policy.BootstrapAdmin != "" && strings.EqualFold(user.Email, u.policy.BootstrapAdmin)
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
 
-	return claims, nil
+// revokeFamily revoga a família após reuso e devolve cause para o chamador
+func (u *UseCaseUser) revokeFamily(familyID string, now time.Time, cause error) error {
+	if err := u.tokenRepo.RevokeRefreshTokenFamily(familyID, now); err != nil {
+		return err
+	}
+	return cause
 }
 
 func (u *UseCaseUser) CreateAdminUser() error {
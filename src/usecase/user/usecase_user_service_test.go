@@ -1,18 +1,23 @@
 package usecase_user_test
 
 import (
+	"app/config"
 	"app/entity"
+	"app/infrastructure/jwtkeys"
 	"app/mocks"
 	usecase_user "app/usecase/user"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt"
 	"github.com/golang/mock/gomock"
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 )
 
 func TestUsecaseUser_LoginUser(t *testing.T) {
-
 	ctrl := gomock.NewController(t)
 
 	defer ctrl.Finish()
@@ -23,9 +28,10 @@ func TestUsecaseUser_LoginUser(t *testing.T) {
 	mockUserRepo.EXPECT().GetByMail(gomock.Any()).Return(&entity.EntityUser{
 		Email:    "mailer@mailer.com",
 		Password: password,
+		Active:   true,
 	}, nil)
 
-	_, err := usecase_user.NewService(mockUserRepo).LoginUser("mailer@mailer.com", "password33")
+	_, err := newService(mockUserRepo).LoginUser("mailer@mailer.com", "password33")
 
 	assert.Nil(t, err)
 }
@@ -41,14 +47,14 @@ func TestUsecaseUser_CreateUser(t *testing.T) {
 
 	Convey("User can't be created", t, func() {
 
-		err := usecase_user.NewService(mockUserRepo).Create(&entity.EntityUser{})
+		err := newService(mockUserRepo).Create(&entity.EntityUser{})
 
 		So(err, ShouldNotBeNil)
 	})
 
 	Convey("User can be created", t, func() {
 
-		err := usecase_user.NewService(mockUserRepo).Create(&entity.EntityUser{
+		err := newService(mockUserRepo).Create(&entity.EntityUser{
 			Email:    "mailer@mailer.com",
 			Name:     "Name",
 			Password: "password33",
@@ -57,3 +63,470 @@ func TestUsecaseUser_CreateUser(t *testing.T) {
 		So(err, ShouldBeNil)
 	})
 }
+
+func TestUsecaseUser_Roles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockIRepositoryUser(ctrl)
+	service := newService(mockUserRepo)
+
+	t.Run("should create users as operators by default", func(t *testing.T) {
+		user := &entity.EntityUser{Email: "mailer@mailer.com", Name: "Name", Password: "password33"}
+		mockUserRepo.EXPECT().CreateUser(user).Return(nil)
+
+		require.NoError(t, service.Create(user))
+		assert.Equal(t, entity.RoleOperator, user.Role)
+		assert.False(t, user.IsAdmin)
+	})
+
+	t.Run("should keep is_admin and the admin role in sync", func(t *testing.T) {
+		user := &entity.EntityUser{Email: "admin@mailer.com", Name: "Admin", Password: "password33", IsAdmin: true}
+		mockUserRepo.EXPECT().CreateUser(user).Return(nil)
+
+		require.NoError(t, service.Create(user))
+		assert.Equal(t, entity.RoleAdmin, user.Role)
+	})
+
+	t.Run("should reject unknown roles and permissions", func(t *testing.T) {
+		err := service.Create(&entity.EntityUser{Email: "mailer@mailer.com", Name: "Name", Password: "password33", Role: "owner"})
+		assert.ErrorIs(t, err, entity.ErrUnknownRole)
+
+		err = service.Update(&entity.EntityUser{ID: 1, Email: "mailer@mailer.com", Role: entity.RoleViewer, Permissions: []string{"envelopes:delete_all"}})
+		assert.ErrorIs(t, err, entity.ErrUnknownPermission)
+	})
+
+	t.Run("should keep the current role when the update omits it", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetByMail("mailer@mailer.com").Return(&entity.EntityUser{ID: 1, Role: entity.RoleApprover, Permissions: []string{entity.ScopeWebhooksAdmin}}, nil)
+		user := &entity.EntityUser{ID: 1, Email: "mailer@mailer.com", Name: "Renamed"}
+		mockUserRepo.EXPECT().UpdateUser(user).Return(nil)
+
+		require.NoError(t, service.Update(user))
+		assert.Equal(t, entity.RoleApprover, user.Role)
+		assert.Equal(t, []string{entity.ScopeWebhooksAdmin}, user.Permissions)
+	})
+
+	t.Run("should demote admins given another role", func(t *testing.T) {
+		user := &entity.EntityUser{ID: 1, Email: "admin@mailer.com", IsAdmin: true, Role: entity.RoleViewer}
+		mockUserRepo.EXPECT().UpdateUser(user).Return(nil)
+
+		require.NoError(t, service.Update(user))
+		assert.False(t, user.IsAdmin)
+	})
+}
+
+// newService cria o serviço com chaves de teste; NewService exige as chaves configuradas no ambiente
+func newService(userRepo usecase_user.IRepositoryUser) *usecase_user.UseCaseUser {
+	return usecase_user.NewServiceWithTokens(userRepo, nil, jwtkeys.NewKeySet(jwtkeys.NewHMACKey("test", "test-secret")), nil)
+}
+
+func newTokenService(ctrl *gomock.Controller) (*usecase_user.UseCaseUser, *mocks.MockIRepositoryUser, *mocks.MockIRepositoryToken) {
+	userRepo := mocks.NewMockIRepositoryUser(ctrl)
+	tokenRepo := mocks.NewMockIRepositoryToken(ctrl)
+	keys := jwtkeys.NewKeySet(jwtkeys.NewHMACKey("test", "test-secret"))
+
+	return usecase_user.NewServiceWithTokens(userRepo, tokenRepo, keys, nil), userRepo, tokenRepo
+}
+
+func TestUsecaseUser_IssueTokens(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	service, userRepo, tokenRepo := newTokenService(ctrl)
+	user := &entity.EntityUser{ID: 1, Name: "Name", Email: "mailer@mailer.com", Active: true}
+
+	var stored *entity.EntityRefreshToken
+	tokenRepo.EXPECT().CreateRefreshToken(gomock.Any()).DoAndReturn(func(token *entity.EntityRefreshToken) error {
+		stored = token
+		return nil
+	})
+
+	tokens, err := service.IssueTokens(user)
+
+	require.NoError(t, err)
+	assert.True(t, stored.Matches(tokens.RefreshToken))
+	assert.Equal(t, 1, stored.UserID)
+	assert.NotEmpty(t, stored.FamilyID)
+	assert.Positive(t, tokens.ExpiresIn)
+
+	tokenRepo.EXPECT().IsTokenRevoked(gomock.Any()).Return(false, nil)
+	userRepo.EXPECT().GetByID(1).Return(user, nil)
+
+	authenticated, err := service.GetUserByToken(tokens.AccessToken)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, authenticated.ID)
+}
+
+func TestUsecaseUser_RefreshTokens(t *testing.T) {
+	user := &entity.EntityUser{ID: 1, Name: "Name", Email: "mailer@mailer.com", Active: true}
+
+	t.Run("should rotate the refresh token within the same family", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service, userRepo, tokenRepo := newTokenService(ctrl)
+		current, rawToken, err := entity.NewRefreshToken(1, "family", time.Hour, time.Now())
+		require.NoError(t, err)
+		current.ID = 10
+
+		tokenRepo.EXPECT().GetRefreshTokenByHash(current.TokenHash).Return(current, nil)
+		tokenRepo.EXPECT().MarkRefreshTokenRotated(10, gomock.Any()).Return(true, nil)
+		userRepo.EXPECT().GetByID(1).Return(user, nil)
+		tokenRepo.EXPECT().CreateRefreshToken(gomock.Any()).DoAndReturn(func(token *entity.EntityRefreshToken) error {
+			assert.Equal(t, "family", token.FamilyID)
+			assert.NotEqual(t, current.TokenHash, token.TokenHash)
+			return nil
+		})
+
+		tokens, err := service.RefreshTokens(rawToken)
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, tokens.AccessToken)
+		assert.NotEqual(t, rawToken, tokens.RefreshToken)
+	})
+
+	t.Run("should revoke the family when a rotated token is reused", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service, _, tokenRepo := newTokenService(ctrl)
+		current, rawToken, err := entity.NewRefreshToken(1, "family", time.Hour, time.Now())
+		require.NoError(t, err)
+		rotatedAt := time.Now().Add(-time.Minute)
+		current.RotatedAt = &rotatedAt
+
+		tokenRepo.EXPECT().GetRefreshTokenByHash(current.TokenHash).Return(current, nil)
+		tokenRepo.EXPECT().RevokeRefreshTokenFamily("family", gomock.Any()).Return(nil)
+
+		_, err = service.RefreshTokens(rawToken)
+
+		assert.ErrorIs(t, err, entity.ErrRefreshTokenReused)
+	})
+
+	t.Run("should revoke the family when a concurrent request already rotated the token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service, _, tokenRepo := newTokenService(ctrl)
+		current, rawToken, err := entity.NewRefreshToken(1, "family", time.Hour, time.Now())
+		require.NoError(t, err)
+		current.ID = 10
+
+		tokenRepo.EXPECT().GetRefreshTokenByHash(current.TokenHash).Return(current, nil)
+		tokenRepo.EXPECT().MarkRefreshTokenRotated(10, gomock.Any()).Return(false, nil)
+		tokenRepo.EXPECT().RevokeRefreshTokenFamily("family", gomock.Any()).Return(nil)
+
+		_, err = service.RefreshTokens(rawToken)
+
+		assert.ErrorIs(t, err, entity.ErrRefreshTokenReused)
+	})
+
+	t.Run("should reject unknown and expired tokens", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service, _, tokenRepo := newTokenService(ctrl)
+		expired, rawToken, err := entity.NewRefreshToken(1, "family", time.Hour, time.Now().Add(-2*time.Hour))
+		require.NoError(t, err)
+
+		tokenRepo.EXPECT().GetRefreshTokenByHash(entity.HashRefreshToken("unknown")).Return(nil, gorm.ErrRecordNotFound)
+		_, err = service.RefreshTokens("unknown")
+		assert.ErrorIs(t, err, entity.ErrInvalidRefreshToken)
+
+		tokenRepo.EXPECT().GetRefreshTokenByHash(expired.TokenHash).Return(expired, nil)
+		_, err = service.RefreshTokens(rawToken)
+		assert.ErrorIs(t, err, entity.ErrRefreshTokenExpired)
+	})
+}
+
+func TestUsecaseUser_Logout(t *testing.T) {
+	user := &entity.EntityUser{ID: 1, Name: "Name", Email: "mailer@mailer.com", Active: true}
+
+	t.Run("should revoke the access token and the refresh token family", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service, _, tokenRepo := newTokenService(ctrl)
+		tokenRepo.EXPECT().CreateRefreshToken(gomock.Any()).Return(nil)
+		tokens, err := service.IssueTokens(user)
+		require.NoError(t, err)
+		refresh := &entity.EntityRefreshToken{ID: 10, UserID: 1, FamilyID: "family"}
+
+		var revoked *entity.EntityRevokedToken
+		tokenRepo.EXPECT().RevokeToken(gomock.Any()).DoAndReturn(func(token *entity.EntityRevokedToken) error {
+			revoked = token
+			return nil
+		})
+		tokenRepo.EXPECT().GetRefreshTokenByHash(entity.HashRefreshToken(tokens.RefreshToken)).Return(refresh, nil)
+		tokenRepo.EXPECT().RevokeRefreshTokenFamily("family", gomock.Any()).Return(nil)
+
+		require.NoError(t, service.Logout(tokens.AccessToken, tokens.RefreshToken, false))
+		assert.Equal(t, 1, revoked.UserID)
+
+		tokenRepo.EXPECT().IsTokenRevoked(revoked.JTI).Return(true, nil)
+		_, err = service.GetUserByToken(tokens.AccessToken)
+		assert.ErrorIs(t, err, entity.ErrTokenRevoked)
+	})
+
+	t.Run("should end every session of the user", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service, userRepo, tokenRepo := newTokenService(ctrl)
+		tokenRepo.EXPECT().CreateRefreshToken(gomock.Any()).Return(nil)
+		tokens, err := service.IssueTokens(user)
+		require.NoError(t, err)
+
+		tokenRepo.EXPECT().RevokeToken(gomock.Any()).Return(nil)
+		tokenRepo.EXPECT().RevokeUserRefreshTokens(1, gomock.Any()).Return(nil)
+		tokenRepo.EXPECT().SetTokensRevokedAt(1, gomock.Any()).Return(nil)
+
+		require.NoError(t, service.Logout(tokens.AccessToken, "", true))
+
+		// Um token emitido antes da revogação, em outra sessão, também deixa de valer
+		revokedAt := time.Now().Add(time.Hour)
+		tokenRepo.EXPECT().IsTokenRevoked(gomock.Any()).Return(false, nil)
+		userRepo.EXPECT().GetByID(1).Return(&entity.EntityUser{ID: 1, TokensRevokedAt: &revokedAt}, nil)
+
+		_, err = service.GetUserByToken(tokens.AccessToken)
+		assert.ErrorIs(t, err, entity.ErrTokenRevoked)
+	})
+}
+
+func newIdentityService(t *testing.T, ctrl *gomock.Controller, envVars config.EnvironmentVars) (*usecase_user.UseCaseUser, *mocks.MockIRepositoryUser, *mocks.MockIIdentityVerifier) {
+	previous := config.EnvironmentVariables
+	config.EnvironmentVariables = envVars
+	t.Cleanup(func() { config.EnvironmentVariables = previous })
+
+	userRepo := mocks.NewMockIRepositoryUser(ctrl)
+	verifier := mocks.NewMockIIdentityVerifier(ctrl)
+	verifier.EXPECT().Issuer().Return("https://sso.empresa.com").AnyTimes()
+	keys := jwtkeys.NewKeySet(jwtkeys.NewHMACKey("test", "test-secret"))
+
+	return usecase_user.NewServiceWithTokens(userRepo, mocks.NewMockIRepositoryToken(ctrl), keys, verifier), userRepo, verifier
+}
+
+func externalToken(t *testing.T) string {
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"iss": "https://sso.empresa.com", "sub": "f7c1d2"}).SignedString([]byte("irrelevant"))
+	require.NoError(t, err)
+	return signed
+}
+
+func TestUsecaseUser_GetUserByToken_ExternalIdentity(t *testing.T) {
+	identity := &entity.ExternalIdentity{
+		Issuer:        "https://sso.empresa.com",
+		Subject:       "f7c1d2",
+		Email:         "maria@empresa.com",
+		EmailVerified: true,
+		Name:          "Maria Souza",
+		Groups:        []string{"assinaturas", "assinaturas-admin"},
+	}
+	envVars := config.EnvironmentVars{OIDC_AUTO_PROVISION: true, OIDC_ADMIN_GROUPS: "assinaturas-admin", OIDC_ALLOWED_GROUPS: "assinaturas"}
+
+	t.Run("should provision the user on the first access", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service, userRepo, verifier := newIdentityService(t, ctrl, envVars)
+		token := externalToken(t)
+
+		verifier.EXPECT().Verify(token).Return(identity, nil)
+		userRepo.EXPECT().GetByExternalID(identity.ExternalID()).Return(nil, gorm.ErrRecordNotFound)
+		userRepo.EXPECT().GetByMail("maria@empresa.com").Return(nil, gorm.ErrRecordNotFound)
+		userRepo.EXPECT().CreateUser(gomock.Any()).Return(nil)
+
+		user, err := service.GetUserByToken(token)
+
+		require.NoError(t, err)
+		assert.Equal(t, identity.ExternalID(), user.ExternalID)
+		assert.True(t, user.IsAdmin)
+		assert.True(t, user.Active)
+	})
+
+	t.Run("should link an existing local user with the same email when enabled", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		linking := envVars
+		linking.OIDC_LINK_BY_EMAIL = true
+		service, userRepo, verifier := newIdentityService(t, ctrl, linking)
+		token := externalToken(t)
+
+		verifier.EXPECT().Verify(token).Return(identity, nil)
+		userRepo.EXPECT().GetByExternalID(identity.ExternalID()).Return(nil, gorm.ErrRecordNotFound)
+		userRepo.EXPECT().GetByMail("maria@empresa.com").Return(&entity.EntityUser{ID: 7, Email: "maria@empresa.com", Active: true, IsAdmin: true}, nil)
+		userRepo.EXPECT().UpdateUser(gomock.Any()).DoAndReturn(func(user *entity.EntityUser) error {
+			assert.Equal(t, identity.ExternalID(), user.ExternalID)
+			return nil
+		})
+
+		user, err := service.GetUserByToken(token)
+
+		require.NoError(t, err)
+		assert.Equal(t, 7, user.ID)
+	})
+
+	t.Run("should not link an existing local user by default", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service, userRepo, verifier := newIdentityService(t, ctrl, envVars)
+		token := externalToken(t)
+
+		verifier.EXPECT().Verify(token).Return(identity, nil)
+		userRepo.EXPECT().GetByExternalID(identity.ExternalID()).Return(nil, gorm.ErrRecordNotFound)
+		userRepo.EXPECT().GetByMail("maria@empresa.com").Return(&entity.EntityUser{ID: 7, Email: "maria@empresa.com", Active: true, IsAdmin: true}, nil)
+
+		_, err := service.GetUserByToken(token)
+
+		assert.ErrorIs(t, err, entity.ErrIdentityNotLinked)
+	})
+
+	t.Run("should never link the bootstrap admin", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		linking := envVars
+		linking.OIDC_LINK_BY_EMAIL = true
+		linking.DEFAULT_ADMIN_MAIL = "Maria@empresa.com"
+		service, userRepo, verifier := newIdentityService(t, ctrl, linking)
+		token := externalToken(t)
+
+		verifier.EXPECT().Verify(token).Return(identity, nil)
+		userRepo.EXPECT().GetByExternalID(identity.ExternalID()).Return(nil, gorm.ErrRecordNotFound)
+		userRepo.EXPECT().GetByMail("maria@empresa.com").Return(&entity.EntityUser{ID: 1, Email: "maria@empresa.com", Active: true, IsAdmin: true}, nil)
+
+		_, err := service.GetUserByToken(token)
+
+		assert.ErrorIs(t, err, entity.ErrIdentityNotAllowed)
+	})
+
+	t.Run("should not link or provision unverified emails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service, userRepo, verifier := newIdentityService(t, ctrl, envVars)
+		token := externalToken(t)
+		unverified := *identity
+		unverified.EmailVerified = false
+
+		verifier.EXPECT().Verify(token).Return(&unverified, nil)
+		userRepo.EXPECT().GetByExternalID(identity.ExternalID()).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := service.GetUserByToken(token)
+
+		assert.ErrorIs(t, err, entity.ErrUnverifiedEmail)
+	})
+
+	t.Run("should sync the admin flag from the groups", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service, userRepo, verifier := newIdentityService(t, ctrl, envVars)
+		token := externalToken(t)
+		member := *identity
+		member.Groups = []string{"assinaturas"}
+
+		verifier.EXPECT().Verify(token).Return(&member, nil)
+		userRepo.EXPECT().GetByExternalID(identity.ExternalID()).Return(&entity.EntityUser{ID: 7, Email: "maria@empresa.com", Active: true, IsAdmin: true}, nil)
+		userRepo.EXPECT().UpdateUser(gomock.Any()).Return(nil)
+
+		user, err := service.GetUserByToken(token)
+
+		require.NoError(t, err)
+		assert.False(t, user.IsAdmin)
+	})
+
+	t.Run("should reject identities outside the allowed groups", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service, _, verifier := newIdentityService(t, ctrl, envVars)
+		token := externalToken(t)
+		outsider := *identity
+		outsider.Groups = []string{"financeiro"}
+
+		verifier.EXPECT().Verify(token).Return(&outsider, nil)
+
+		_, err := service.GetUserByToken(token)
+
+		assert.ErrorIs(t, err, entity.ErrIdentityNotAllowed)
+	})
+
+	t.Run("should not provision when disabled", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service, userRepo, verifier := newIdentityService(t, ctrl, config.EnvironmentVars{})
+		token := externalToken(t)
+
+		verifier.EXPECT().Verify(token).Return(identity, nil)
+		userRepo.EXPECT().GetByExternalID(identity.ExternalID()).Return(nil, gorm.ErrRecordNotFound)
+		userRepo.EXPECT().GetByMail("maria@empresa.com").Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := service.GetUserByToken(token)
+
+		assert.ErrorIs(t, err, entity.ErrUserNotProvisioned)
+	})
+}
+
+func TestUsecaseUser_LoginUser_LocalLoginDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	service, userRepo, _ := newIdentityService(t, ctrl, config.EnvironmentVars{DEFAULT_ADMIN_MAIL: "admin@empresa.com"})
+
+	_, err := service.LoginUser("maria@empresa.com", "password33")
+	assert.ErrorIs(t, err, entity.ErrLocalLoginDisabled)
+
+	password, _ := entity.GeneratePassword("password33")
+	userRepo.EXPECT().GetByMail("admin@empresa.com").Return(&entity.EntityUser{Email: "admin@empresa.com", Password: password, Active: true}, nil)
+
+	_, err = service.LoginUser("admin@empresa.com", "password33")
+	assert.NoError(t, err)
+}
+
+func TestUsecaseUser_LoginUser_Lockout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	password, _ := entity.GeneratePassword("password33")
+	userRepo := mocks.NewMockIRepositoryUser(ctrl)
+	attempts := mocks.NewMockILoginAttempts(ctrl)
+	service := newService(userRepo)
+	service.SetLoginAttempts(attempts)
+
+	t.Run("should count wrong passwords and unknown accounts", func(t *testing.T) {
+		attempts.EXPECT().Locked("maria@empresa.com").Return(false, nil)
+		userRepo.EXPECT().GetByMail("maria@empresa.com").Return(&entity.EntityUser{Email: "maria@empresa.com", Password: password}, nil)
+		attempts.EXPECT().Failed("maria@empresa.com").Return(nil)
+
+		_, err := service.LoginUser("maria@empresa.com", "errada")
+		assert.Error(t, err)
+
+		attempts.EXPECT().Locked("ninguem@empresa.com").Return(false, nil)
+		userRepo.EXPECT().GetByMail("ninguem@empresa.com").Return(nil, gorm.ErrRecordNotFound)
+		attempts.EXPECT().Failed("ninguem@empresa.com").Return(nil)
+
+		_, err = service.LoginUser("ninguem@empresa.com", "password33")
+		assert.Error(t, err)
+	})
+
+	t.Run("should refuse locked accounts even with the right password", func(t *testing.T) {
+		attempts.EXPECT().Locked("maria@empresa.com").Return(true, nil)
+
+		_, err := service.LoginUser("maria@empresa.com", "password33")
+
+		assert.ErrorIs(t, err, entity.ErrAccountLocked)
+	})
+
+	t.Run("should reset the failures after a successful login", func(t *testing.T) {
+		attempts.EXPECT().Locked("maria@empresa.com").Return(false, nil)
+		userRepo.EXPECT().GetByMail("maria@empresa.com").Return(&entity.EntityUser{Email: "maria@empresa.com", Password: password, Active: true}, nil)
+		attempts.EXPECT().Succeeded("maria@empresa.com").Return(nil)
+
+		_, err := service.LoginUser("maria@empresa.com", "password33")
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestUsecaseUser_InactiveUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	service, userRepo, tokenRepo := newTokenService(ctrl)
+	password, _ := entity.GeneratePassword("password33")
+	user := &entity.EntityUser{ID: 1, Name: "Name", Email: "mailer@mailer.com", Password: password, Active: true}
+
+	tokenRepo.EXPECT().CreateRefreshToken(gomock.Any()).Return(nil)
+	tokens, err := service.IssueTokens(user)
+	require.NoError(t, err)
+
+	inactive := *user
+	inactive.Active = false
+
+	t.Run("should refuse the login even with the right password", func(t *testing.T) {
+		userRepo.EXPECT().GetByMail("mailer@mailer.com").Return(&inactive, nil)
+
+		_, err := service.LoginUser("mailer@mailer.com", "password33")
+
+		assert.ErrorIs(t, err, entity.ErrUserInactive)
+	})
+
+	t.Run("should refuse access tokens issued before the deactivation", func(t *testing.T) {
+		tokenRepo.EXPECT().IsTokenRevoked(gomock.Any()).Return(false, nil)
+		userRepo.EXPECT().GetByID(1).Return(&inactive, nil)
+
+		_, err := service.GetUserByToken(tokens.AccessToken)
+
+		assert.ErrorIs(t, err, entity.ErrUserInactive)
+	})
+}
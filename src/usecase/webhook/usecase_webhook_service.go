@@ -2,20 +2,31 @@ package webhook
 
 import (
 	"app/api/handlers/dtos"
+	"app/config"
 	"app/entity"
+	"app/infrastructure/storage"
+	"app/pkg/fetcher"
 	"app/usecase/document"
 	usecase_envelope "app/usecase/envelope"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
 )
 
+// ErrSignedFileHostsNotConfigured impede o download da cópia assinada sem hosts do provider configurados
+var ErrSignedFileHostsNotConfigured = errors.New("CLICKSIGN_SIGNED_FILE_HOSTS is not configured")
+
 type UsecaseWebhookService struct {
 	webhookRepository IRepositoryWebhook
 	envelopeUsecase   usecase_envelope.IUsecaseEnvelope
 	documentUsecase   document.IUsecaseDocument
+	storage           storage.Storage
+	signedFiles       *fetcher.Fetcher
 	logger            *logrus.Logger
 }
 
@@ -23,23 +34,27 @@ func NewUsecaseWebhookService(
 	webhookRepository IRepositoryWebhook,
 	envelopeUsecase usecase_envelope.IUsecaseEnvelope,
 	documentUsecase document.IUsecaseDocument,
+	documentStorage storage.Storage,
+	documentFetcher *fetcher.Fetcher,
 	logger *logrus.Logger,
 ) *UsecaseWebhookService {
 	return &UsecaseWebhookService{
 		webhookRepository: webhookRepository,
 		envelopeUsecase:   envelopeUsecase,
 		documentUsecase:   documentUsecase,
+		storage:           documentStorage,
+		signedFiles:       newSignedFileFetcher(documentFetcher, config.EnvironmentVariables.CLICKSIGN_SIGNED_FILE_HOSTS),
 		logger:            logger,
 	}
 }
 
 // ProcessWebhook processa um webhook recebido
 func (u *UsecaseWebhookService) ProcessWebhook(webhookDTO *dtos.WebhookRequestDTO, rawPayload string) (*entity.EntityWebhook, error) {
-	u.logger.Info("Processing webhook", map[string]interface{}{
+	u.logger.WithFields(logrus.Fields{
 		"event_name":   webhookDTO.Event.Name,
 		"document_key": webhookDTO.Document.Key,
 		"account_key":  webhookDTO.Document.AccountKey,
-	})
+	}).Info("Processing webhook")
 
 	// Criar entidade webhook
 	webhook, err := entity.NewWebhook(
@@ -49,28 +64,21 @@ func (u *UsecaseWebhookService) ProcessWebhook(webhookDTO *dtos.WebhookRequestDT
 		rawPayload,
 	)
 	if err != nil {
-		u.logger.Error("Failed to create webhook entity", map[string]interface{}{
-			"error": err.Error(),
-		})
+		u.logger.WithError(err).Error("Failed to create webhook entity")
 		return nil, fmt.Errorf("failed to create webhook entity: %w", err)
 	}
 
 	// Salvar webhook no banco
 	err = u.webhookRepository.Create(webhook)
 	if err != nil {
-		u.logger.Error("Failed to save webhook", map[string]interface{}{
-			"error": err.Error(),
-		})
+		u.logger.WithError(err).Error("Failed to save webhook")
 		return nil, fmt.Errorf("failed to save webhook: %w", err)
 	}
 
 	// Processar evento específico
 	err = u.processSpecificEvent(webhookDTO, webhook)
 	if err != nil {
-		u.logger.Error("Failed to process specific event", map[string]interface{}{
-			"error":      err.Error(),
-			"webhook_id": webhook.ID,
-		})
+		u.logger.WithError(err).WithField("webhook_id", webhook.ID).Error("Failed to process specific event")
 
 		// Marcar webhook como falhou
 		webhook.MarkAsFailed(err.Error())
@@ -83,17 +91,14 @@ func (u *UsecaseWebhookService) ProcessWebhook(webhookDTO *dtos.WebhookRequestDT
 	webhook.MarkAsProcessed()
 	err = u.webhookRepository.Update(webhook)
 	if err != nil {
-		u.logger.Error("Failed to mark webhook as processed", map[string]interface{}{
-			"error":      err.Error(),
-			"webhook_id": webhook.ID,
-		})
+		u.logger.WithError(err).WithField("webhook_id", webhook.ID).Error("Failed to mark webhook as processed")
 		return webhook, fmt.Errorf("failed to mark webhook as processed: %w", err)
 	}
 
-	u.logger.Info("Webhook processed successfully", map[string]interface{}{
+	u.logger.WithFields(logrus.Fields{
 		"webhook_id": webhook.ID,
 		"event_name": webhookDTO.Event.Name,
-	})
+	}).Info("Webhook processed successfully")
 
 	return webhook, nil
 }
@@ -112,19 +117,17 @@ func (u *UsecaseWebhookService) processSpecificEvent(webhookDTO *dtos.WebhookReq
 	case "upload":
 		return u.ProcessUploadEvent(webhookDTO, webhook)
 	default:
-		u.logger.Warn("Unknown event type", map[string]interface{}{
-			"event_name": webhookDTO.Event.Name,
-		})
+		u.logger.WithField("event_name", webhookDTO.Event.Name).Warn("Unknown event type")
 		return nil // Evento desconhecido não é erro, apenas ignorado
 	}
 }
 
 // ProcessAutoCloseEvent processa especificamente eventos de fechamento automático
 func (u *UsecaseWebhookService) ProcessAutoCloseEvent(webhookDTO *dtos.WebhookRequestDTO, webhook *entity.EntityWebhook) error {
-	u.logger.Info("Processing auto close event", map[string]interface{}{
+	u.logger.WithFields(logrus.Fields{
 		"document_key": webhookDTO.Document.Key,
 		"status":       webhookDTO.Document.Status,
-	})
+	}).Info("Processing auto close event")
 
 	// Buscar envelope pelo ID que está no metadata do documento
 	var envelopeID int
@@ -141,11 +144,10 @@ func (u *UsecaseWebhookService) ProcessAutoCloseEvent(webhookDTO *dtos.WebhookRe
 		// Tentar buscar pelo ID primeiro
 		envelope, err = u.envelopeUsecase.GetEnvelope(envelopeID)
 		if err != nil {
-			u.logger.Warn("Failed to find envelope by ID, trying by document key", map[string]interface{}{
+			u.logger.WithError(err).WithFields(logrus.Fields{
 				"envelope_id":  envelopeID,
 				"document_key": webhookDTO.Document.Key,
-				"error":        err.Error(),
-			})
+			}).Warn("Failed to find envelope by ID, trying by document key")
 		}
 	}
 
@@ -158,29 +160,50 @@ func (u *UsecaseWebhookService) ProcessAutoCloseEvent(webhookDTO *dtos.WebhookRe
 	}
 
 	if envelope == nil {
-		u.logger.Warn("No envelope found for document key", map[string]interface{}{
-			"document_key": webhookDTO.Document.Key,
-		})
+		u.logger.WithField("document_key", webhookDTO.Document.Key).Warn("No envelope found for document key")
 		return nil // Não é erro se não encontrar envelope
 	}
 
 	// Verificar se o envelope já está finalizado
 	if envelope.Status == "completed" {
-		u.logger.Warn("Envelope already completed, ignoring auto close event", map[string]interface{}{
+		u.logger.WithFields(logrus.Fields{
 			"envelope_id":  envelope.ID,
 			"document_key": webhookDTO.Document.Key,
 			"status":       envelope.Status,
-		})
+		}).Warn("Envelope already completed, ignoring auto close event")
 		return fmt.Errorf("envelope is already completed and cannot be processed again. Envelope ID: %d, Document Key: %s", envelope.ID, webhookDTO.Document.Key)
 	}
 
+	// Conferir se o provider finalizou o mesmo conteúdo armazenado na ingestão antes de concluir o envelope
+	document, err := u.documentUsecase.GetDocumentByClicksignKey(webhookDTO.Document.Key)
+	if err != nil {
+		u.logger.WithError(err).WithField("document_key", webhookDTO.Document.Key).Warn("Failed to find document by clicksign key")
+		// Não é erro crítico se não encontrar o documento
+		document = nil
+	}
+	if document != nil {
+		if err := document.VerifyProviderHash(webhookDTO.Document.SHA256); err != nil {
+			// A divergência fica registrada no documento; nada do que o provider finalizou é aceito
+			if updateErr := u.documentUsecase.Update(document); updateErr != nil {
+				u.logger.WithError(updateErr).WithField("document_id", document.ID).Warn("Failed to record document integrity mismatch")
+			}
+			u.logger.WithFields(logrus.Fields{
+				"document_id":     document.ID,
+				"document_key":    webhookDTO.Document.Key,
+				"sha256":          document.SHA256,
+				"provider_sha256": document.ProviderSHA256,
+			}).Error("Document integrity check failed")
+			return fmt.Errorf("document integrity check failed: %w", err)
+		}
+	}
+
 	// Log do status atual do envelope antes da atualização
-	u.logger.Info("Envelope found, updating status to completed", map[string]interface{}{
+	u.logger.WithFields(logrus.Fields{
 		"envelope_id":    envelope.ID,
 		"document_key":   webhookDTO.Document.Key,
 		"current_status": envelope.Status,
 		"new_status":     "completed",
-	})
+	}).Info("Envelope found, updating status to completed")
 
 	// Atualizar status do envelope para completed
 	err = envelope.SetStatus("completed")
@@ -198,43 +221,39 @@ func (u *UsecaseWebhookService) ProcessAutoCloseEvent(webhookDTO *dtos.WebhookRe
 		return fmt.Errorf("failed to update envelope: %w", err)
 	}
 
-	u.logger.Info("Envelope updated successfully for auto close event", map[string]interface{}{
+	u.logger.WithFields(logrus.Fields{
 		"envelope_id":  envelope.ID,
 		"document_key": webhookDTO.Document.Key,
 		"new_status":   envelope.Status,
-	})
+	}).Info("Envelope updated successfully for auto close event")
 
 	// Atualizar status do documento para "sent" (finalizado)
-	// Buscar documento pelo clicksign_key
-	document, err := u.documentUsecase.GetDocumentByClicksignKey(webhookDTO.Document.Key)
-	if err != nil {
-		u.logger.Warn("Failed to find document by clicksign key", map[string]interface{}{
-			"document_key": webhookDTO.Document.Key,
-			"error":        err.Error(),
-		})
-		// Não é erro crítico se não encontrar o documento
-	} else {
+	if document != nil {
+		// A versão atual é a que o provider finalizou; substituições só ocorrem em rascunho
+		document.MarkSignedVersion()
+
+		// Guardar a cópia assinada no storage, quando o provider informa o download
+		if signedFileURL := webhookDTO.Document.Downloads.SignedFileURL; signedFileURL != "" {
+			if err := u.storeSignedCopy(document, signedFileURL); err != nil {
+				u.logger.WithError(err).WithField("document_id", document.ID).Warn("Failed to store signed copy")
+			}
+		}
+
 		// Atualizar status do documento para "sent"
 		err = document.SetStatus("sent")
 		if err != nil {
-			u.logger.Warn("Failed to set document status to sent", map[string]interface{}{
-				"document_id": document.ID,
-				"error":       err.Error(),
-			})
+			u.logger.WithError(err).WithField("document_id", document.ID).Warn("Failed to set document status to sent")
 		} else {
 			// Atualizar documento no banco
 			err = u.documentUsecase.Update(document)
 			if err != nil {
-				u.logger.Warn("Failed to update document", map[string]interface{}{
-					"document_id": document.ID,
-					"error":       err.Error(),
-				})
+				u.logger.WithError(err).WithField("document_id", document.ID).Warn("Failed to update document")
 			} else {
-				u.logger.Info("Document updated successfully for auto close event", map[string]interface{}{
+				u.logger.WithFields(logrus.Fields{
 					"document_id":  document.ID,
 					"document_key": webhookDTO.Document.Key,
 					"new_status":   document.Status,
-				})
+				}).Info("Document updated successfully for auto close event")
 			}
 		}
 	}
@@ -248,11 +267,44 @@ func (u *UsecaseWebhookService) ProcessAutoCloseEvent(webhookDTO *dtos.WebhookRe
 	return nil
 }
 
+// storeSignedCopy baixa o arquivo assinado e o grava no storage sob a chave do seu conteúdo
+// O download só é feito dos hosts do provider (CLICKSIGN_SIGNED_FILE_HOSTS), nunca de um host qualquer do payload
+func (u *UsecaseWebhookService) storeSignedCopy(document *entity.EntityDocument, signedFileURL string) error {
+	if u.signedFiles == nil {
+		return ErrSignedFileHostsNotConfigured
+	}
+
+	result, err := u.signedFiles.Fetch(context.Background(), signedFileURL)
+	if err != nil {
+		return fmt.Errorf("failed to download signed file: %w", err)
+	}
+
+	key, sum, err := storage.StoreContent(context.Background(), u.storage, storage.KindSigned, result.Data, result.MimeType)
+	if err != nil {
+		return err
+	}
+
+	document.SetSignedContent(key, sum, result.Size)
+	return nil
+}
+
+// newSignedFileFetcher restringe documentFetcher aos hosts de download do provider; sem hosts não há download
+func newSignedFileFetcher(documentFetcher *fetcher.Fetcher, hosts string) *fetcher.Fetcher {
+	var allowed []string
+	for _, host := range strings.Split(hosts, ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			allowed = append(allowed, host)
+		}
+	}
+	if len(allowed) == 0 || documentFetcher == nil {
+		return nil
+	}
+	return documentFetcher.WithAllowedHosts(allowed...).WithAllowedMimeTypes("application/pdf")
+}
+
 // ProcessSignEvent processa eventos de assinatura
 func (u *UsecaseWebhookService) ProcessSignEvent(webhookDTO *dtos.WebhookRequestDTO, webhook *entity.EntityWebhook) error {
-	u.logger.Info("Processing sign event", map[string]interface{}{
-		"document_key": webhookDTO.Document.Key,
-	})
+	u.logger.WithField("document_key", webhookDTO.Document.Key).Info("Processing sign event")
 
 	// Salvar dados do evento no webhook
 	err := webhook.SetEventData(webhookDTO)
@@ -268,9 +320,7 @@ func (u *UsecaseWebhookService) ProcessSignEvent(webhookDTO *dtos.WebhookRequest
 
 // ProcessSignatureStartedEvent processa eventos de início de assinatura
 func (u *UsecaseWebhookService) ProcessSignatureStartedEvent(webhookDTO *dtos.WebhookRequestDTO, webhook *entity.EntityWebhook) error {
-	u.logger.Info("Processing signature started event", map[string]interface{}{
-		"document_key": webhookDTO.Document.Key,
-	})
+	u.logger.WithField("document_key", webhookDTO.Document.Key).Info("Processing signature started event")
 
 	// Salvar dados do evento no webhook
 	err := webhook.SetEventData(webhookDTO)
@@ -286,9 +336,7 @@ func (u *UsecaseWebhookService) ProcessSignatureStartedEvent(webhookDTO *dtos.We
 
 // ProcessAddSignerEvent processa eventos de adição de signatário
 func (u *UsecaseWebhookService) ProcessAddSignerEvent(webhookDTO *dtos.WebhookRequestDTO, webhook *entity.EntityWebhook) error {
-	u.logger.Info("Processing add signer event", map[string]interface{}{
-		"document_key": webhookDTO.Document.Key,
-	})
+	u.logger.WithField("document_key", webhookDTO.Document.Key).Info("Processing add signer event")
 
 	// Salvar dados do evento no webhook
 	err := webhook.SetEventData(webhookDTO)
@@ -304,9 +352,7 @@ func (u *UsecaseWebhookService) ProcessAddSignerEvent(webhookDTO *dtos.WebhookRe
 
 // ProcessUploadEvent processa eventos de upload
 func (u *UsecaseWebhookService) ProcessUploadEvent(webhookDTO *dtos.WebhookRequestDTO, webhook *entity.EntityWebhook) error {
-	u.logger.Info("Processing upload event", map[string]interface{}{
-		"document_key": webhookDTO.Document.Key,
-	})
+	u.logger.WithField("document_key", webhookDTO.Document.Key).Info("Processing upload event")
 
 	// Salvar dados do evento no webhook
 	err := webhook.SetEventData(webhookDTO)
@@ -400,9 +446,7 @@ func (u *UsecaseWebhookService) RetryWebhook(id int) error {
 		return fmt.Errorf("failed to update webhook status: %w", err)
 	}
 
-	u.logger.Info("Webhook marked for retry", map[string]interface{}{
-		"webhook_id": webhook.ID,
-	})
+	u.logger.WithField("webhook_id", webhook.ID).Info("Webhook marked for retry")
 
 	return nil
 }
@@ -0,0 +1,85 @@
+package webhook
+
+import (
+	"app/api/handlers/dtos"
+	"app/entity"
+	"app/mocks"
+	"app/pkg/fetcher"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAutoCloseWebhook(sha256, signedFileURL string) *dtos.WebhookRequestDTO {
+	webhookDTO := &dtos.WebhookRequestDTO{}
+	webhookDTO.Event.Name = "auto_close"
+	webhookDTO.Document.Key = "doc-key"
+	webhookDTO.Document.SHA256 = sha256
+	webhookDTO.Document.Downloads.SignedFileURL = signedFileURL
+	return webhookDTO
+}
+
+func TestUsecaseWebhookService_ProcessAutoCloseEvent(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+	storedSum := strings.Repeat("a", 64)
+
+	t.Run("should stop before completing the envelope when the provider hash differs", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		envelopeUsecase := mocks.NewMockIUsecaseEnvelope(ctrl)
+		documentUsecase := mocks.NewMockIUsecaseDocument(ctrl)
+		service := NewUsecaseWebhookService(nil, envelopeUsecase, documentUsecase, nil, nil, logger)
+
+		envelopeUsecase.EXPECT().GetEnvelopeByClicksignKey("doc-key").Return(&entity.EntityEnvelope{ID: 1, Status: "running"}, nil)
+		documentUsecase.EXPECT().GetDocumentByClicksignKey("doc-key").Return(&entity.EntityDocument{ID: 2, Status: "draft", SHA256: storedSum}, nil)
+		documentUsecase.EXPECT().Update(gomock.Any()).DoAndReturn(func(document *entity.EntityDocument) error {
+			assert.Equal(t, entity.IntegrityMismatch, document.IntegrityStatus)
+			assert.Empty(t, document.SignedStorageKey)
+			assert.Zero(t, document.SignedVersion)
+			assert.Equal(t, "draft", document.Status)
+			return nil
+		})
+		envelopeUsecase.EXPECT().UpdateEnvelopeForWebhook(gomock.Any()).Times(0)
+
+		err := service.ProcessAutoCloseEvent(newAutoCloseWebhook(strings.Repeat("b", 64), "https://files.clicksign.com/signed.pdf"), &entity.EntityWebhook{})
+
+		assert.ErrorContains(t, err, "integrity check failed")
+	})
+
+	t.Run("should not download signed copies from hosts outside the provider allowlist", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		envelopeUsecase := mocks.NewMockIUsecaseEnvelope(ctrl)
+		documentUsecase := mocks.NewMockIUsecaseDocument(ctrl)
+		service := NewUsecaseWebhookService(nil, envelopeUsecase, documentUsecase, nil, nil, logger)
+		service.signedFiles = newSignedFileFetcher(fetcher.NewFetcher(fetcher.Config{}), "*.clicksign.com")
+
+		envelopeUsecase.EXPECT().GetEnvelopeByClicksignKey("doc-key").Return(&entity.EntityEnvelope{ID: 1, Status: "running"}, nil)
+		envelopeUsecase.EXPECT().UpdateEnvelopeForWebhook(gomock.Any()).Return(nil)
+		documentUsecase.EXPECT().GetDocumentByClicksignKey("doc-key").Return(&entity.EntityDocument{ID: 2, Status: "draft", SHA256: storedSum}, nil)
+		documentUsecase.EXPECT().Update(gomock.Any()).DoAndReturn(func(document *entity.EntityDocument) error {
+			assert.Equal(t, entity.IntegrityVerified, document.IntegrityStatus)
+			assert.Empty(t, document.SignedStorageKey)
+			return nil
+		})
+
+		err := service.ProcessAutoCloseEvent(newAutoCloseWebhook(storedSum, "https://attacker.example/signed.pdf"), &entity.EntityWebhook{})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should refuse downloads without configured hosts", func(t *testing.T) {
+		service := &UsecaseWebhookService{logger: logger}
+
+		err := service.storeSignedCopy(&entity.EntityDocument{}, "https://files.clicksign.com/signed.pdf")
+
+		assert.ErrorIs(t, err, ErrSignedFileHostsNotConfigured)
+	})
+}